@@ -1,8 +1,8 @@
 module chatbot-poc-go
 
-go 1.21
+go 1.24.0
 
-toolchain go1.22.4
+toolchain go1.24.4
 
 require (
 	//chatbot-poc-go/pkg/protos/services v0.0.0-00010101000000-000000000000
@@ -21,7 +21,6 @@ require (
 	filippo.io/edwards25519 v1.0.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
@@ -33,6 +32,7 @@ require (
 	github.com/golang/protobuf v1.5.4
 	github.com/loov/hrtime v1.0.3
 	github.com/s3131212/go-mls v0.0.0-20240819080345-3879b4025fcb
+	golang.org/x/crypto v0.23.0
 )
 
 require (
@@ -14,6 +14,7 @@ import (
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 	"io"
 	"log"
 	"math/rand"
@@ -56,23 +57,26 @@ func ensureUsersAndChatbots(t *testing.T, userSize, chatbotSize int) {
 }
 
 func deactivateUserById(t *testing.T, id int) {
-	users[id].Deactivate()
+	ctx := context.Background()
+	users[id].Deactivate(ctx)
 	msg, success := timeOutReadFromUserMessageChannel(users[id].GetMessageChan())
 	assert.True(t, success, "User %v should receive a deactivate message", id)
 	assert.Equal(t, msg.Message, []byte("Deactivate"), "User %v should receive a deactivate message", id)
 }
 
 func deactivateChatbotById(t *testing.T, id int) {
-	chatbots[id].Deactivate()
+	ctx := context.Background()
+	chatbots[id].Deactivate(ctx)
 	// Don't wait for the deactivate message from the chatbot because we don't want to mess up the benchmark.
 }
 
 func benchmarkSendIndividualMessage(t *testing.T, userSize int) {
+	ctx := context.Background()
 	ensureUsersAndChatbots(t, userSize, 0)
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User i % userSize sends a message to User (i+1) % userSize
-		err := users[i%userSize].SendIndividualMessage(protocol.NewSignalAddress(users[(i+1)%userSize].GetUserID(), 1), []byte(fmt.Sprintf("Hello User%v! I'm User%v.", (i+1)%userSize, i%userSize)), pb.MessageType_TEXT_MESSAGE)
+		err := users[i%userSize].SendIndividualMessage(ctx, protocol.NewSignalAddress(users[(i+1)%userSize].GetUserID(), 1), []byte(fmt.Sprintf("Hello User%v! I'm User%v.", (i+1)%userSize, i%userSize)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "User%v should be able to send a Message to User%v", i%userSize, (i+1)%userSize)
 		msg, success := timeOutReadFromUserMessageChannel(users[(i+1)%userSize].GetMessageChan())
 		assert.True(t, success, "User%v should receive a Message from User%v", (i+1)%userSize, i%userSize)
@@ -84,15 +88,20 @@ func benchmarkSendIndividualMessage(t *testing.T, userSize int) {
 }
 
 func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool) string {
+	return createServerSideGroupOfSizeWithHybridKEM(t, memberSize, chatbotSize, isIGA, isPseudo, false)
+}
+
+func createServerSideGroupOfSizeWithHybridKEM(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, hybridKEM bool) string {
+	ctx := context.Background()
 	ensureUsersAndChatbots(t, memberSize, chatbotSize)
 
 	// User 0 is the initiator of the group
-	groupId, err := users[0].CreateGroup(pb.GroupType_SERVER_SIDE)
+	groupId, err := users[0].CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
 	assert.Nil(t, err, "User 0 should be able to create a group")
 
 	for i := 1; i < memberSize; i++ {
 		// Invite user i to the group
-		users[0].RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, users[i].GetUserID())
+		users[0].RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, users[i].GetUserID())
 
 		// User i should receive a group invitation
 		msg, success := timeOutReadFromUserMessageChannel(users[i].GetMessageChan())
@@ -107,7 +116,7 @@ func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int,
 		}
 
 		// User i distributes its sender key.
-		err = users[i].DistributeSelfSenderKeyToAll(groupId)
+		err = users[i].DistributeSelfSenderKeyToAll(ctx, groupId)
 		assert.Nil(t, err, "User %v should be able to distribute its sender key", i)
 
 		// User 0 ~ i-1 should receive sender key distribution messages from User i.
@@ -141,7 +150,7 @@ func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int,
 
 	for i := 0; i < chatbotSize; i++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroupWithHybridKEM(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo, nil, hybridKEM)
 
 		// Chatbot i should receive a group invitation
 		msgc, success := timeOutReadFromChatbotMessageChannel(chatbots[i].GetMessageChan())
@@ -157,7 +166,7 @@ func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int,
 
 		if !isIGA {
 			// Chatbot i distributes its sender key.
-			err = chatbots[i].DistributeSelfSenderKeyToAll(groupId)
+			err = chatbots[i].DistributeSelfSenderKeyToAll(ctx, groupId)
 			assert.Nil(t, err, "Chatbot %v should be able to distribute its sender key", i)
 
 			// Users should receive sender key distribution messages from the new chatbot.
@@ -192,7 +201,7 @@ func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int,
 		for i := 0; i < chatbotSize; i++ {
 			for j := 0; j < memberSize; j++ {
 				// User j issues a pseudonym to chatbot i
-				err = users[j].CreateAndRegisterServerSidePseudonym(groupId, chatbots[i].GetChatbotID())
+				err = users[j].CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 				assert.Nil(t, err, "User %v should be able to create and register a pseudonym", j)
 
 				// Chatbot i should receive a pseudonym registration message
@@ -217,15 +226,16 @@ func createServerSideGroupOfSize(t *testing.T, memberSize int, chatbotSize int,
 }
 
 func createMlsGroupOfSize(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool) string {
+	ctx := context.Background()
 	ensureUsersAndChatbots(t, memberSize, chatbotSize)
 
 	// User 0 is the initiator of the group
-	groupId, err := users[0].CreateGroup(pb.GroupType_MLS)
+	groupId, err := users[0].CreateGroup(ctx, pb.GroupType_MLS)
 	assert.Nil(t, err, "User 0 should be able to create a group")
 
 	for i := 1; i < memberSize; i++ {
 		// Invite user i to the group
-		users[0].RequestInviteUserToGroup(groupId, pb.GroupType_MLS, users[i].GetUserID())
+		users[0].RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, users[i].GetUserID())
 
 		// User i should receive a group invitation
 		msg, success := timeOutReadFromUserMessageChannel(users[i].GetMessageChan())
@@ -253,7 +263,7 @@ func createMlsGroupOfSize(t *testing.T, memberSize int, chatbotSize int, isIGA b
 
 	for i := 0; i < chatbotSize; i++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbots[i].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbots[i].GetChatbotID(), isIGA, isPseudo)
 
 		// Chatbot i should receive a group invitation
 		msgc, success := timeOutReadFromChatbotMessageChannel(chatbots[i].GetMessageChan())
@@ -297,7 +307,7 @@ func createMlsGroupOfSize(t *testing.T, memberSize int, chatbotSize int, isIGA b
 		for i := 0; i < chatbotSize; i++ {
 			for j := 0; j < memberSize; j++ {
 				// User j issues a pseudonym to chatbot i
-				err = users[j].CreateAndRegisterMlsPseudonym(groupId, chatbots[i].GetChatbotID())
+				err = users[j].CreateAndRegisterMlsPseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 				assert.Nil(t, err, "User %v should be able to create and register a pseudonym", j)
 
 				// Chatbot i should receive a pseudonym registration message
@@ -324,6 +334,7 @@ func createMlsGroupOfSize(t *testing.T, memberSize int, chatbotSize int, isIGA b
 }
 
 func benchmarkChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	numberOfExperiments := 100
 	createUsersAndChatbots(t, memberSize, numberOfExperiments)
 	groupId := createServerSideGroupOfSize(t, memberSize, 0, isIGA, isPseudo)
@@ -331,7 +342,7 @@ func benchmarkChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo)
 
 		// Chatbot i should receive a group invitation
 		msgc, success := timeOutReadFromChatbotMessageChannel(chatbots[i].GetMessageChan())
@@ -347,7 +358,7 @@ func benchmarkChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo
 
 		if !isIGA {
 			// Chatbot i distributes its sender key.
-			err := chatbots[i].DistributeSelfSenderKeyToAll(groupId)
+			err := chatbots[i].DistributeSelfSenderKeyToAll(ctx, groupId)
 			assert.Nil(t, err, "Chatbot %v should be able to distribute its sender key", i)
 
 			// Users should receive sender key distribution messages from the new chatbot.
@@ -380,7 +391,7 @@ func benchmarkChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo
 			// Issue pseudonyms to each chatbot
 			for j := 0; j < memberSize; j++ {
 				// User j issues a pseudonym to chatbot i
-				err := users[j].CreateAndRegisterServerSidePseudonym(groupId, chatbots[i].GetChatbotID())
+				err := users[j].CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 				assert.Nil(t, err, "User %v should be able to create and register a pseudonym", j)
 
 				// Chatbot i should receive a pseudonym registration message
@@ -406,6 +417,7 @@ func benchmarkChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo
 }
 
 func benchmarkChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	numberOfExperiments := 100
 	createUsersAndChatbots(t, memberSize, numberOfExperiments)
 	groupId := createServerSideGroupOfSize(t, memberSize, 0, isIGA, isPseudo)
@@ -418,7 +430,7 @@ func benchmarkChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbots[i].GetChatbotID(), isIGA, isPseudo)
 
 		// Chatbot i should receive a group invitation
 		msgc, success := timeOutReadFromChatbotMessageChannel(chatbots[i].GetMessageChan())
@@ -434,7 +446,7 @@ func benchmarkChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool
 
 		if !isIGA {
 			// Chatbot i distributes its sender key.
-			err := chatbots[i].DistributeSelfSenderKeyToAll(groupId)
+			err := chatbots[i].DistributeSelfSenderKeyToAll(ctx, groupId)
 			assert.Nil(t, err, "Chatbot %v should be able to distribute its sender key", i)
 
 			// User 0 should receive sender key distribution messages from the new chatbot.
@@ -445,7 +457,7 @@ func benchmarkChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool
 
 		if isPseudo {
 			// User 0 issues a pseudonym to chatbot i
-			err := users[0].CreateAndRegisterServerSidePseudonym(groupId, chatbots[i].GetChatbotID())
+			err := users[0].CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 			assert.Nil(t, err, "User 0 should be able to create and register a pseudonym")
 		}
 	}
@@ -455,6 +467,7 @@ func benchmarkChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool
 }
 
 func benchmarkMlsChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	numberOfExperiments := 100
 	createUsersAndChatbots(t, memberSize, numberOfExperiments)
 	groupId := createMlsGroupOfSize(t, memberSize, 0, isIGA, isPseudo)
@@ -462,7 +475,7 @@ func benchmarkMlsChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPse
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for chatbotId := 0; bench.Next(); chatbotId++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbots[chatbotId].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbots[chatbotId].GetChatbotID(), isIGA, isPseudo)
 
 		// Chatbot i should receive a group invitation
 		msgc, success := timeOutReadFromChatbotMessageChannel(chatbots[chatbotId].GetMessageChan())
@@ -481,7 +494,7 @@ func benchmarkMlsChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPse
 			for i := 0; i <= chatbotId; i++ {
 				for j := 0; j < memberSize; j++ {
 					// User j issues a pseudonym to chatbot chatbotId
-					err := users[j].CreateAndRegisterMlsPseudonym(groupId, chatbots[i].GetChatbotID())
+					err := users[j].CreateAndRegisterMlsPseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 					assert.Nil(t, err, "User %v should be able to create and register a pseudonym", j)
 
 					// Chatbot chatbotId should receive a pseudonym registration message
@@ -508,6 +521,7 @@ func benchmarkMlsChatbotAddition(t *testing.T, memberSize int, isIGA bool, isPse
 }
 
 func benchmarkMlsChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	numberOfExperiments := 100
 	createUsersAndChatbots(t, memberSize, numberOfExperiments)
 	groupId := createMlsGroupOfSize(t, memberSize, 0, isIGA, isPseudo)
@@ -525,7 +539,7 @@ func benchmarkMlsChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA b
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for chatbotId := 0; bench.Next(); chatbotId++ {
 		// Invite chatbot i to the group
-		users[0].RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbots[chatbotId].GetChatbotID(), isIGA, isPseudo)
+		users[0].RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbots[chatbotId].GetChatbotID(), isIGA, isPseudo)
 
 		// User 0 should receive a GROUP_CHATBOT_ADDITION event.
 		msg, success := timeOutReadFromUserMessageChannel(users[0].GetMessageChan())
@@ -536,7 +550,7 @@ func benchmarkMlsChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA b
 			// Issue pseudonyms to each chatbot
 			for i := 0; i <= chatbotId; i++ {
 				// User 0 issues a pseudonym to chatbot chatbotId
-				err := users[0].CreateAndRegisterMlsPseudonym(groupId, chatbots[i].GetChatbotID())
+				err := users[0].CreateAndRegisterMlsPseudonym(ctx, groupId, chatbots[i].GetChatbotID())
 				assert.Nil(t, err, "User 0 should be able to create and register a pseudonym")
 			}
 		}
@@ -547,6 +561,7 @@ func benchmarkMlsChatbotAdditionSingleUser(t *testing.T, memberSize int, isIGA b
 }
 
 func benchmarkUserSendServerSideGroupMessage(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	createUsersAndChatbots(t, memberSize, chatbotSize)
 	groupId := createServerSideGroupOfSize(t, memberSize, chatbotSize, isIGA, isPseudo)
 
@@ -558,7 +573,7 @@ func benchmarkUserSendServerSideGroupMessage(t *testing.T, memberSize int, chatb
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User i % memberSize sends a message to the group
-		err := users[i%memberSize].SendServerSideGroupMessage(groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, false)
+		err := users[i%memberSize].SendServerSideGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, false)
 		assert.Nil(t, err, "User %v should be able to send a message to the group", i%memberSize)
 
 		// All members should receive the message
@@ -600,6 +615,7 @@ func benchmarkUserSendServerSideGroupMessage(t *testing.T, memberSize int, chatb
 }
 
 func benchmarkUserSendServerSideGroupHideTriggerMessage(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	createUsersAndChatbots(t, memberSize, chatbotSize)
 	groupId := createServerSideGroupOfSize(t, memberSize, chatbotSize, isIGA, isPseudo)
 
@@ -611,7 +627,7 @@ func benchmarkUserSendServerSideGroupHideTriggerMessage(t *testing.T, memberSize
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User i % memberSize sends a message to the group
-		err := users[i%memberSize].SendServerSideGroupMessage(groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, []string{}, true)
+		err := users[i%memberSize].SendServerSideGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, []string{}, true)
 		assert.Nil(t, err, "User %v should be able to send a message to the group", i%memberSize)
 
 		// All members should receive the message
@@ -650,6 +666,7 @@ func benchmarkUserSendServerSideGroupHideTriggerMessage(t *testing.T, memberSize
 }
 
 func benchmarkUserGenerateServerSideGroupMessage(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, hideTrigger bool, headerMessage string) {
+	ctx := context.Background()
 	createUsersAndChatbots(t, memberSize, chatbotSize)
 	groupId := createServerSideGroupOfSize(t, memberSize, chatbotSize, isIGA, isPseudo)
 
@@ -661,7 +678,7 @@ func benchmarkUserGenerateServerSideGroupMessage(t *testing.T, memberSize int, c
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User 0 sends a message to the group
-		_, err := users[0].GenerateServerSideGroupMessageCipherText(groupId, []byte(fmt.Sprintf("Hello everyone! This is message %v.", i)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, hideTrigger)
+		_, err := users[0].GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! This is message %v.", i)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, hideTrigger)
 		assert.Nil(t, err, "User 0 should be able to send message %v to the group", i)
 	}
 
@@ -669,7 +686,32 @@ func benchmarkUserGenerateServerSideGroupMessage(t *testing.T, memberSize int, c
 	fmt.Println(bench.Histogram(10))
 }
 
+func benchmarkUserGenerateServerSideGroupMessageOverhead(t *testing.T, memberSize int, chatbotSize int, isPseudo bool, hybridKEM bool, hideTrigger bool, headerMessage string) {
+	ctx := context.Background()
+	createUsersAndChatbots(t, memberSize, chatbotSize)
+	groupId := createServerSideGroupOfSizeWithHybridKEM(t, memberSize, chatbotSize, true, isPseudo, hybridKEM)
+
+	// Get chatbots list from user 0's session driver
+	sessionDriver, err := users[0].Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "User 0 should have the group session")
+	chatbotIdList := sessionDriver.GetGroupChatbots()
+
+	messageSize := 0
+	bench := hrtime.NewBenchmark(numberOfExperiments)
+	for i := 0; bench.Next(); i++ {
+		// User 0 generates a message to the group, every chatbot is rekeyed with the KEM it uses
+		messageWrapper, err := users[0].GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! This is message %v.", i)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, hideTrigger)
+		assert.Nil(t, err, "User 0 should be able to send message %v to the group", i)
+		messageSize += proto.Size(messageWrapper)
+	}
+
+	fmt.Println("====================  ", headerMessage, "  ====================")
+	fmt.Println("Average message size: ", messageSize/numberOfExperiments, " bytes")
+	fmt.Println(bench.Histogram(10))
+}
+
 func benchmarkUserSendMlsGroupMessage(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	createUsersAndChatbots(t, memberSize, chatbotSize)
 	groupId := createMlsGroupOfSize(t, memberSize, chatbotSize, isIGA, isPseudo)
 
@@ -681,7 +723,7 @@ func benchmarkUserSendMlsGroupMessage(t *testing.T, memberSize int, chatbotSize
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User i % memberSize sends a message to the group
-		err := users[i%memberSize].SendMlsGroupMessage(groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, false)
+		err := users[i%memberSize].SendMlsGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, chatbotIdList, false)
 		assert.Nil(t, err, "User %v should be able to send a message to the group", i%memberSize)
 
 		// All members should receive the message
@@ -723,13 +765,14 @@ func benchmarkUserSendMlsGroupMessage(t *testing.T, memberSize int, chatbotSize
 }
 
 func benchmarkUserSendMlsGroupHideTriggerMessage(t *testing.T, memberSize int, chatbotSize int, isIGA bool, isPseudo bool, headerMessage string) {
+	ctx := context.Background()
 	createUsersAndChatbots(t, memberSize, chatbotSize)
 	groupId := createMlsGroupOfSize(t, memberSize, chatbotSize, isIGA, isPseudo)
 
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 	for i := 0; bench.Next(); i++ {
 		// User i % memberSize sends a message to the group
-		err := users[i%memberSize].SendMlsGroupMessage(groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, []string{}, true)
+		err := users[i%memberSize].SendMlsGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! I'm User %v.", i%memberSize)), pb.MessageType_TEXT_MESSAGE, []string{}, true)
 		assert.Nil(t, err, "User %v should be able to send a message to the group", i%memberSize)
 
 		// All members should receive the message
@@ -788,12 +831,13 @@ func benchmarkUserGenerateMlsGroupMessage(t *testing.T, memberSize int, chatbotS
 }
 
 func benchmarkChatbotSendServerSideGroupMessage(t *testing.T, memberSize int, chatbotSize int) {
+	ctx := context.Background()
 	groupId := createServerSideGroupOfSize(t, memberSize, chatbotSize, false, false)
 	bench := hrtime.NewBenchmark(numberOfExperiments)
 
 	for i := 0; bench.Next(); i++ {
 		// Chatbot i % chatbotSize sends a message to the group
-		err := chatbots[i%chatbotSize].SendServerSideGroupMessage(groupId, []byte(fmt.Sprintf("Hello everyone! I'm Chatbot %v.", i%chatbotSize)), pb.MessageType_TEXT_MESSAGE)
+		err := chatbots[i%chatbotSize].SendServerSideGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Hello everyone! I'm Chatbot %v.", i%chatbotSize)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "Chatbot %v should be able to send a message to the group", i%chatbotSize)
 
 		// All members should receive the message
@@ -926,6 +970,27 @@ func TestBenchmarkUserGenerateServerSidePseudoGroupMessageWithHideTrigger(t *tes
 	}
 }
 
+func TestBenchmarkUserGenerateServerSideIGAGroupMessageOverhead(t *testing.T) {
+	for _, chatbotSize := range []int{1, 5, 10, 20, 50} {
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, false, false, false, fmt.Sprintf("Generate Server Side IGA Group Message, Classical KEM: 50 members, %v chatbots", chatbotSize))
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, false, true, false, fmt.Sprintf("Generate Server Side IGA Group Message, Hybrid KEM: 50 members, %v chatbots", chatbotSize))
+	}
+}
+
+func TestBenchmarkUserGenerateServerSideIGAGroupMessageOverheadWithHideTrigger(t *testing.T) {
+	for _, chatbotSize := range []int{1, 5, 10, 20, 50} {
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, false, false, true, fmt.Sprintf("Generate Server Side IGA Group Message with Hide Trigger, Classical KEM: 50 members, %v chatbots", chatbotSize))
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, false, true, true, fmt.Sprintf("Generate Server Side IGA Group Message with Hide Trigger, Hybrid KEM: 50 members, %v chatbots", chatbotSize))
+	}
+}
+
+func TestBenchmarkUserGenerateServerSidePseudoGroupMessageOverhead(t *testing.T) {
+	for _, chatbotSize := range []int{1, 5, 10, 20, 50} {
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, true, false, false, fmt.Sprintf("Generate Server Side Pseudo Group Message, Classical KEM: 50 members, %v chatbots", chatbotSize))
+		benchmarkUserGenerateServerSideGroupMessageOverhead(t, 50, chatbotSize, true, true, false, fmt.Sprintf("Generate Server Side Pseudo Group Message, Hybrid KEM: 50 members, %v chatbots", chatbotSize))
+	}
+}
+
 func TestBenchmarkUserSendMlsGroupMessageWithoutHideTrigger(t *testing.T) {
 	benchmarkUserSendMlsGroupMessage(t, 50, 1, false, false, "Send MLS Group Message: 50 members, 1 chatbot")
 
@@ -1023,16 +1088,18 @@ func dialer() func(context.Context, string) (net.Conn, error) {
 
 // createClientSideUserWithRandomUserID create a client side user with a random user ID.
 func createClientSideUserWithRandomUserID(prefix string) *user.ClientSideUser {
+	ctx := context.Background()
 	userID := prefix + "-" + randomString(8)
 	//user, _ := user.NewClientSideUser(userID, "localhost:50051", true)
-	return user.NewClientSideUserBufconn(userID, dialer(), true)
+	return user.NewClientSideUserBufconn(ctx, userID, dialer(), true)
 }
 
 // createClientSideChatbotWithRandomUserID create a client side chatbot with a random user ID.
 func createClientSideChatbotWithRandomUserID(prefix string) *chatbot.ClientSideChatbot {
+	ctx := context.Background()
 	chatbotID := prefix + "-" + randomString(8)
 	//chatbot, _ := NewClientSideChatbot(chatbotID, "localhost:50051", true)
-	return chatbot.NewClientSideChatbotBufconn(chatbotID, dialer(), true)
+	return chatbot.NewClientSideChatbotBufconn(ctx, chatbotID, dialer(), true)
 }
 
 // randomString create a random string with the given length.
@@ -3,98 +3,139 @@ package chatbot
 import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
 	"context"
 	"go.mau.fi/libsignal/logger"
 	"google.golang.org/grpc"
 	"net"
+	"sync"
 )
 
 type ClientSideChatbot struct {
 	Client         *client.Client
 	chatbotID      string
 	messageChan    chan OutputMessage
-	deactivateChan chan bool
+	errorChan      chan error
+	deactivateChan chan context.Context
+	// listenerDone is closed once the chatbot stopped listening to the streams.
+	listenerDone chan struct{}
+	// lifecycle is cancelled once the chatbot is deactivated, which stops the listener and the work started in the
+	// background while handling a message or event. background tracks that work.
+	lifecycle       context.Context
+	cancelLifecycle context.CancelFunc
+	background      sync.WaitGroup
+
+	// mutex keeps the group messages the chatbot sends from interleaving with the handling of incoming messages, as
+	// both change the group state.
+	mutex sync.Mutex
 
 	groupPseudonyms map[string]map[string]*PseudoUser
+	chatbotRouting  *pb.ChatbotRouting
+	groupScopes     map[string]*pb.ChatbotScopes
+
+	// Buffered key updates that can be handled now that the updates before them arrived, and the member the chatbot
+	// asked to recover the root of each group.
+	readyKeyUpdates []*pb.MessageWrapper
+	rootRecoveries  map[string]string
+
+	// stateStore is where the state of the chatbot is persisted to, if set.
+	stateStore stores.StateStore
 
-	chatServiceClient    pb.ChatServiceClient
-	chatServiceClientCtx context.Context
+	chatServiceClient pb.ChatServiceClient
+	chatServiceConn   *grpc.ClientConn
+	closeConnOnce     sync.Once
 }
 
-func NewClientSideChatbot(userID string, chatServiceAddress string, setup bool) (*ClientSideChatbot, func() error) {
+/*
+NewClientSideChatbot creates a chatbot connected to the server at chatServiceAddress, and uploads its prekeys if setup
+is set. ctx bounds the registration and the uploads, but not the streams. The close() of the connection is returned as
+well, though Deactivate closes it too.
+*/
+func NewClientSideChatbot(ctx context.Context, userID string, chatServiceAddress string, setup bool) (*ClientSideChatbot, func() error) {
 	clientObj := client.NewClient(userID)
 	csc := &ClientSideChatbot{
 		Client:          clientObj,
 		chatbotID:       userID,
 		messageChan:     make(chan OutputMessage, 100),
-		deactivateChan:  make(chan bool),
+		errorChan:       make(chan error, 100),
+		deactivateChan:  make(chan context.Context, 1),
 		groupPseudonyms: make(map[string]map[string]*PseudoUser),
+		groupScopes:     make(map[string]*pb.ChatbotScopes),
+		rootRecoveries:  make(map[string]string),
 	}
 
 	closeChatServiceClient := csc.SetupChatServiceClient(chatServiceAddress)
-	clientObj.SetChatServiceClient(&csc.chatServiceClient, &csc.chatServiceClientCtx)
-	if !csc.RegisterChatbotToServer() {
+	clientObj.SetChatServiceClient(&csc.chatServiceClient)
+	if !csc.RegisterChatbotToServer(ctx) {
 		logger.Error("Chatbot registration failed")
+		closeChatServiceClient()
 		return nil, nil
 	}
 
-	go csc.ListenToStreams()
+	csc.startListening()
 
 	if setup {
 		for i := 1; i <= 20; i = i + 1 {
 			preKeyID := clientObj.GeneratePreKey(i)
-			clientObj.UploadPreKeyByID(preKeyID)
+			clientObj.UploadPreKeyByID(ctx, preKeyID)
 		}
 		signedPreKeyID := clientObj.GenerateSignedPreKey()
-		clientObj.UploadSignedPreKeyByID(signedPreKeyID)
+		clientObj.UploadSignedPreKeyByID(ctx, signedPreKeyID)
 	}
 
 	return csc, closeChatServiceClient
 }
 
-func NewClientSideChatbotBufconn(userID string, dialer func(context.Context, string) (net.Conn, error), setup bool) *ClientSideChatbot {
+/*
+NewClientSideChatbotBufconn creates a chatbot connected to the server through the given dialer, like
+NewClientSideChatbot.
+*/
+func NewClientSideChatbotBufconn(ctx context.Context, userID string, dialer func(context.Context, string) (net.Conn, error), setup bool) *ClientSideChatbot {
 	clientObj := client.NewClient(userID)
 	csc := &ClientSideChatbot{
 		Client:          clientObj,
 		chatbotID:       userID,
 		messageChan:     make(chan OutputMessage, 100),
-		deactivateChan:  make(chan bool),
+		errorChan:       make(chan error, 100),
+		deactivateChan:  make(chan context.Context, 1),
 		groupPseudonyms: make(map[string]map[string]*PseudoUser),
+		groupScopes:     make(map[string]*pb.ChatbotScopes),
+		rootRecoveries:  make(map[string]string),
 	}
 
-	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "", grpc.WithInsecure(), grpc.WithContextDialer(dialer))
 	if err != nil {
 		panic(err)
 	}
 
 	serviceClient := pb.NewChatServiceClient(conn)
-	clientObj.SetChatServiceClient(&serviceClient, &ctx)
+	clientObj.SetChatServiceClient(&serviceClient)
 	csc.chatServiceClient = serviceClient
-	csc.chatServiceClientCtx = ctx
+	csc.chatServiceConn = conn
 
-	if !csc.RegisterChatbotToServer() {
+	if !csc.RegisterChatbotToServer(ctx) {
 		logger.Error("Chatbot registration failed")
+		conn.Close()
 		return nil
 	}
 
-	go csc.ListenToStreams()
+	csc.startListening()
 
 	if setup {
 		// Generate and upload prekeys
 		for i := 1; i <= 300; i = i + 1 {
 			preKeyID := clientObj.GeneratePreKey(i)
-			clientObj.UploadPreKeyByID(preKeyID)
+			clientObj.UploadPreKeyByID(ctx, preKeyID)
 		}
 
 		// Generate and upload signed prekey
 		signedPreKeyID := clientObj.GenerateSignedPreKey()
-		clientObj.UploadSignedPreKeyByID(signedPreKeyID)
+		clientObj.UploadSignedPreKeyByID(ctx, signedPreKeyID)
 
 		// Generate and upload MLS key packages
 		for i := 1; i <= 200; i = i + 1 {
 			clientObj.GenerateMLSKeyPackage(uint32(i))
-			clientObj.UploadMLSKeyPackage(uint32(i))
+			clientObj.UploadMLSKeyPackage(ctx, uint32(i))
 		}
 		logger.Info("Finish setup.")
 	}
@@ -102,14 +143,15 @@ func NewClientSideChatbotBufconn(userID string, dialer func(context.Context, str
 	return csc
 }
 
-func (csc *ClientSideChatbot) RegisterChatbotToServer() bool {
+func (csc *ClientSideChatbot) RegisterChatbotToServer(ctx context.Context) bool {
 	logger.Info("Registering User: ", csc.chatbotID)
 
 	// Register user
-	res, err := csc.chatServiceClient.SetChatbot(csc.chatServiceClientCtx, &pb.SetChatbotRequest{
+	res, err := csc.chatServiceClient.SetChatbot(ctx, &pb.SetChatbotRequest{
 		ChatbotID:         csc.chatbotID,
 		IdentityKeyPublic: csc.Client.GetIdentityKey().PublicKey().Serialize(),
 		RegistrationID:    csc.Client.GetRegistrationID(),
+		ChatbotRouting:    csc.chatbotRouting,
 	})
 
 	if err != nil {
@@ -125,6 +167,19 @@ func (csc *ClientSideChatbot) RegisterChatbotToServer() bool {
 	return true
 }
 
+/*
+RegisterRouting registers the mention names (without the leading "@") and command prefixes (e.g. "/weather") of the chatbot to the server.
+The server attaches them to the group when the chatbot is invited, so that group members can route messages to the chatbot automatically.
+Groups the chatbot has already joined are not affected.
+*/
+func (csc *ClientSideChatbot) RegisterRouting(ctx context.Context, mentionNames []string, commandPrefixes []string) bool {
+	csc.chatbotRouting = &pb.ChatbotRouting{
+		MentionNames:    mentionNames,
+		CommandPrefixes: commandPrefixes,
+	}
+	return csc.RegisterChatbotToServer(ctx)
+}
+
 /*
 GetChatbotID returns the chatbot ID.
 */
@@ -140,19 +195,73 @@ func (csc *ClientSideChatbot) GetMessageChan() <-chan OutputMessage {
 }
 
 /*
-Deactivate deactivates the chatbot.
+GetErrorChan returns the error channel, which receives a *HandlingError for every incoming message or server event the
+chatbot failed to handle.
 */
-func (csc *ClientSideChatbot) Deactivate() {
-	csc.deactivateChan <- true
+func (csc *ClientSideChatbot) GetErrorChan() <-chan error {
+	return csc.errorChan
 }
 
 /*
-OutputMessage is used in the message channel to denote the message receive by the user.
+Deactivate deactivates the chatbot. The server ends the streams once it sent what it already took off the queues, and
+the chatbot handles it before the connection to the server is closed. The streams are cancelled if ctx is done first.
 */
-type OutputMessage struct {
-	Message     []byte
-	MessageType pb.MessageType
-	EventType   pb.ServerEventType
+func (csc *ClientSideChatbot) Deactivate(ctx context.Context) error {
+	if csc.listenerDone == nil {
+		return nil
+	}
+
+	select {
+	case csc.deactivateChan <- ctx:
+	default:
+		// The chatbot is already being deactivated
+	}
+
+	select {
+	case <-csc.listenerDone:
+	case <-ctx.Done():
+		csc.stopBackground()
+		csc.closeConnection()
+		return ctx.Err()
+	}
+	csc.stopBackground()
+	return csc.closeConnection()
+}
+
+/*
+stopBackground cancels the work started in the background while handling messages and events, and waits for it to stop.
+*/
+func (csc *ClientSideChatbot) stopBackground() {
+	csc.cancelLifecycle()
+	csc.background.Wait()
+}
+
+/*
+closeConnection closes the connection to the server once, so that the chatbot can be deactivated again.
+*/
+func (csc *ClientSideChatbot) closeConnection() error {
+	var err error
+	csc.closeConnOnce.Do(func() {
+		err = csc.chatServiceConn.Close()
+	})
+	return err
+}
+
+/*
+OutputMessage is used in the message channel to denote the message or the server event received by the chatbot.
+*/
+type OutputMessage = client.OutputMessage
+
+/*
+newMessageOutput returns the output of a message received in the group, or nil if there is nothing to output.
+*/
+func newMessageOutput(messageWrapper *pb.MessageWrapper, groupID string, message []byte, messageType pb.MessageType) *OutputMessage {
+	output := client.NewMessageOutput(messageWrapper, groupID, message, messageType)
+	if output != nil && output.Channel == pb.Channel_IGA_CHANNEL {
+		// The chatbot must not learn who sent an IGA message.
+		output.SenderID = ""
+	}
+	return output
 }
 
 type PseudoUser struct {
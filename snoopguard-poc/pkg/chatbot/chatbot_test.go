@@ -5,6 +5,7 @@ import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/server"
+	"chatbot-poc-go/pkg/stores"
 	"chatbot-poc-go/pkg/treekem"
 	"chatbot-poc-go/pkg/user"
 	"context"
@@ -13,9 +14,15 @@ import (
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"log"
 	"math/rand"
 	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -40,29 +47,30 @@ func setup() {
 }
 
 func TestUserPreKeyAndSignedPreKey(t *testing.T) {
+	ctx := context.Background()
 	setup()
 
 	// Generate prekey and signed prekey
 	// Note that this is only for test purpose, when setup=true, the prekeys are generated and uploaded by the time the ClientSideUser is initialized.
 	chatbot1PreKeyID := chatbot1.Client.GeneratePreKey(0)
 	chatbot1SignedPreKeyID := chatbot1.Client.GenerateSignedPreKey()
-	assert.True(t, chatbot1.Client.UploadPreKeyByID(chatbot1PreKeyID), "Chatbot1 upload prekey should be successful")
-	assert.True(t, chatbot1.Client.UploadSignedPreKeyByID(chatbot1SignedPreKeyID), "Chatbot1 upload signed prekey should be successful")
+	assert.True(t, chatbot1.Client.UploadPreKeyByID(ctx, chatbot1PreKeyID), "Chatbot1 upload prekey should be successful")
+	assert.True(t, chatbot1.Client.UploadSignedPreKeyByID(ctx, chatbot1SignedPreKeyID), "Chatbot1 upload signed prekey should be successful")
 
 	chatbot2PreKeyID := chatbot2.Client.GeneratePreKey(0)
 	chatbot2SignedPreKeyID := chatbot2.Client.GenerateSignedPreKey()
-	assert.True(t, chatbot2.Client.UploadPreKeyByID(chatbot2PreKeyID), "Chatbot2 upload prekey should be successful")
-	assert.True(t, chatbot2.Client.UploadSignedPreKeyByID(chatbot2SignedPreKeyID), "Chatbot2 upload signed prekey should be successful")
+	assert.True(t, chatbot2.Client.UploadPreKeyByID(ctx, chatbot2PreKeyID), "Chatbot2 upload prekey should be successful")
+	assert.True(t, chatbot2.Client.UploadSignedPreKeyByID(ctx, chatbot2SignedPreKeyID), "Chatbot2 upload signed prekey should be successful")
 
 	chatbot3PreKeyID := chatbot3.Client.GeneratePreKey(0)
 	chatbot3SignedPreKeyID := chatbot3.Client.GenerateSignedPreKey()
-	assert.True(t, chatbot3.Client.UploadPreKeyByID(chatbot3PreKeyID), "Chatbot3 upload prekey should be successful")
-	assert.True(t, chatbot3.Client.UploadSignedPreKeyByID(chatbot3SignedPreKeyID), "Chatbot3 upload signed prekey should be successful")
+	assert.True(t, chatbot3.Client.UploadPreKeyByID(ctx, chatbot3PreKeyID), "Chatbot3 upload prekey should be successful")
+	assert.True(t, chatbot3.Client.UploadSignedPreKeyByID(ctx, chatbot3SignedPreKeyID), "Chatbot3 upload signed prekey should be successful")
 
 	// Test PreKeyBundle
-	aliceGetChatbot1PreKeyBundle, err := alice.Client.GetOthersPreKeyBundle(chatbot1.GetChatbotID())
+	aliceGetChatbot1PreKeyBundle, err := alice.Client.GetOthersPreKeyBundle(ctx, chatbot1.GetChatbotID())
 	assert.Nil(t, err, "Alice should be able to get chatbot1's prekey bundle")
-	chatbot1GetAlicesPreKeyBundle, err := chatbot1.Client.GetOthersPreKeyBundle(alice.GetUserID())
+	chatbot1GetAlicesPreKeyBundle, err := chatbot1.Client.GetOthersPreKeyBundle(ctx, alice.GetUserID())
 	assert.Nil(t, err, "Chatbot1 should be able to get Alice's prekey bundle")
 
 	// Get real PreKeyBundle
@@ -95,13 +103,14 @@ func TestUserPreKeyAndSignedPreKey(t *testing.T) {
 }
 
 func TestSendMessage(t *testing.T) {
+	ctx := context.Background()
 	setup()
 
 	// Alice to Chatbot1
-	_, err := alice.CreateIndividualSession(protocol.NewSignalAddress(chatbot1.GetChatbotID(), 1))
+	_, err := alice.CreateIndividualSession(ctx, protocol.NewSignalAddress(chatbot1.GetChatbotID(), 1))
 	assert.Nil(t, err, "Alice should be able to create a session with chatbot1")
 	for i := 0; i < 10; i++ {
-		err := alice.SendIndividualMessage(protocol.NewSignalAddress(chatbot1.GetChatbotID(), 1), []byte(fmt.Sprintf("Hello Chatbot1! %v", i)), pb.MessageType_TEXT_MESSAGE)
+		err := alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(chatbot1.GetChatbotID(), 1), []byte(fmt.Sprintf("Hello Chatbot1! %v", i)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "Alice should be able to send message to chatbot1")
 		msg, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
 		assert.True(t, success, "Chatbot1 should receive a message from Alice")
@@ -110,10 +119,10 @@ func TestSendMessage(t *testing.T) {
 	}
 
 	// Chatbot1 to Alice
-	_, err = chatbot1.CreateIndividualSession(protocol.NewSignalAddress(alice.GetUserID(), 1))
+	_, err = chatbot1.CreateIndividualSession(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1))
 	assert.Nil(t, err, "Chatbot1 should be able to create a session with Alice")
 	for i := 0; i < 10; i++ {
-		err := chatbot1.SendIndividualMessage(protocol.NewSignalAddress(alice.GetUserID(), 1), []byte(fmt.Sprintf("Hello Alice! %v", i)), pb.MessageType_TEXT_MESSAGE)
+		err := chatbot1.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte(fmt.Sprintf("Hello Alice! %v", i)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "Chatbot1 should be able to send message to Alice")
 		msg, success := timeOutReadFromUserMessageChannel(alice.GetMessageChan())
 		assert.True(t, success, "Alice should receive a message from chatbot1")
@@ -122,12 +131,59 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+func TestDeactivateLeaksNoGoroutines(t *testing.T) {
+	setup()
+	dial := dialer()
+	before := goroutines()
+
+	// Chatbot5 is set up, talks to Alice and is torn down within a deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	chatbot5 := NewClientSideChatbotBufconn(ctx, "chatbot5-"+randomString(8), dial, true)
+	assert.NotNil(t, chatbot5, "Chatbot5 should be created")
+	_, err := alice.CreateIndividualSession(ctx, protocol.NewSignalAddress(chatbot5.GetChatbotID(), 1))
+	assert.Nil(t, err, "Alice should be able to create a session with chatbot5")
+	err = alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(chatbot5.GetChatbotID(), 1), []byte("Hello Chatbot5!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Alice should be able to send message to chatbot5")
+	msg, success := timeOutReadFromChatbotMessageChannel(chatbot5.GetMessageChan())
+	assert.True(t, success, "Chatbot5 should receive a message from Alice")
+	assert.Equal(t, "Hello Chatbot5!", string(msg.Message), "Chatbot5 should receive the same message from Alice")
+	err = chatbot5.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte("Hello Alice!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Chatbot5 should be able to send message to Alice")
+	userMsg, success := timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a message from chatbot5")
+	assert.Equal(t, "Hello Alice!", string(userMsg.Message), "Alice should receive the same message from chatbot5")
+
+	// Work chatbot5 left running in the background is cancelled and waited for on deactivation.
+	backgroundStopped := make(chan struct{})
+	chatbot5.goBackground(func(ctx context.Context) {
+		defer close(backgroundStopped)
+		<-ctx.Done()
+	})
+	assert.Nil(t, chatbot5.Deactivate(ctx), "Chatbot5 should be deactivated")
+	select {
+	case <-backgroundStopped:
+	default:
+		t.Error("Chatbot5 should stop the work in the background before the deactivation returns")
+	}
+	assert.Nil(t, chatbot5.Deactivate(ctx), "Deactivating chatbot5 again should do nothing")
+
+	// Chatbot6 is torn down even if the deactivation is cancelled, and nothing is left running.
+	cancelledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	chatbot6 := NewClientSideChatbotBufconn(ctx, "chatbot6-"+randomString(8), dial, false)
+	assert.NotNil(t, chatbot6, "Chatbot6 should be created")
+	assert.ErrorIs(t, chatbot6.Deactivate(cancelledCtx), context.Canceled, "Deactivating chatbot6 should be cancelled")
+	assertNoLeakedGoroutines(t, before)
+}
+
 // TestClientSideGroupMessage test the client side group Message.
 func _TestClientSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	setup()
 
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_CLIENT_SIDE)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_CLIENT_SIDE)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
 	// Alice should have the group session
@@ -135,7 +191,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Alice should have the group session")
 
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, bob.GetUserID())
 	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
@@ -149,7 +205,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
 
 	// Invite Carol to the group
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, carol.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, carol.GetUserID())
 	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
 	assert.True(t, success, "Carol should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Carol should receive a group invitation from Alice")
@@ -165,7 +221,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Carol should have the group session")
 
 	// Alice send a message to the group
-	err = alice.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = alice.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob should receive the Message
@@ -189,7 +245,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Alice invite chatbot1 to the group
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_CLIENT_SIDE, chatbot1.GetChatbotID(), false, false)
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, chatbot1.GetChatbotID(), false, false)
 
 	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -214,7 +270,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Carol send a message
-	err = carol.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()})
+	err = carol.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()})
 	assert.Nil(t, err, "Carol should be able to send a Message to the group")
 
 	// Alice and Bob should receive the Message
@@ -237,7 +293,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Bob send a message
-	err = bob.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Bob. Chatbot should not receive this."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = bob.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob. Chatbot should not receive this."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Bob should be able to send a Message to the group")
 
 	// Alice and Bob should receive the Message
@@ -253,7 +309,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	assert.False(t, success, "Chatbot1 should not receive a Message from Alice")
 
 	// Chatbot1 should still be able to send a message
-	err = chatbot1.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot1. I can speak."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot1.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot1. I can speak."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot1 should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the message.
@@ -278,7 +334,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Alice invite David to the group
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, david.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, david.GetUserID())
 
 	// Alice, Bob, and Carol should receive GROUP_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -301,7 +357,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "David should have the group session")
 
 	// Alice send a message
-	err = alice.SendClientSideGroupMessage(groupId, []byte("Here's David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()})
+	err = alice.SendClientSideGroupMessage(ctx, groupId, []byte("Here's David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()})
 	assert.Nil(t, err, "Alice should be able to send a Message to the group")
 
 	// Bob and Carol should receive the Message even after David joins.
@@ -330,7 +386,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// David should be able to send a Message to the group
-	err = david.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = david.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "David should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the Message
@@ -351,7 +407,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot1 send a Message
-	err = chatbot1.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot1."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot1.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot1."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot1 should be able to send a Message to the group")
 
 	// Alice, Bob, Carol, and David should receive the message.
@@ -368,7 +424,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Remove Carol from the group
-	bob.RequestRemoveUserFromGroup(groupId, carol.GetUserID())
+	bob.RequestRemoveUserFromGroup(ctx, groupId, carol.GetUserID())
 
 	// All members, including Carol, should receive the GROUP_REMOVAL event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan(), david.GetMessageChan()} {
@@ -393,7 +449,7 @@ func _TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Alice send a message.
-	err = alice.SendClientSideGroupMessage(groupId, []byte("Bye Carol."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = alice.SendClientSideGroupMessage(ctx, groupId, []byte("Bye Carol."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Alice should be able to send a Message to the group")
 
 	// Carol should not receive the message
@@ -410,10 +466,11 @@ func _TestClientSideGroupMessage(t *testing.T) {
 }
 
 func TestServerSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	setup()
 
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_SERVER_SIDE)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
 	// Alice should have the group session
@@ -421,7 +478,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Alice should have the group session")
 
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
 	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
@@ -436,7 +493,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
 
 	// Bob distribute his sender key to all
-	err = bob.DistributeSelfSenderKeyToAll(groupId)
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
 
 	// Alice and Bob should receive sender key distribution messages from others
@@ -454,7 +511,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, treekemGroupEqual(aliceSessionDriver.GetTreeKEMState(), bobSessionDriver.GetTreeKEMState()), "Alice and Bob should have the same TreeKEM state")
 
 	// Alice send a message to the group
-	err = alice.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob should receive the message
@@ -468,7 +525,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Invite Carol to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
 
 	// Carol should receive a group invitation from Alice
 	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
@@ -487,7 +544,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Carol distribute her sender key to all
-	err = carol.DistributeSelfSenderKeyToAll(groupId)
+	err = carol.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "Carol should be able to distribute her sender key to all")
 
 	// Alice, Bob should receive sender key distribution messages from Carol
@@ -513,19 +570,24 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, carolSessionDriver.HasUserReceivingSession(bob.GetUserID()), "Carol should have Bob's receiving session")
 
 	// Alice invite chatbot1 to the group
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false)
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false)
 
 	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
 		msg, success = timeOutReadFromUserMessageChannel(c)
 		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
 		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_CHATBOT_ADDITION, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, chatbot1.GetChatbotID(), msg.ChatbotID, "Should learn that chatbot1 is added")
+		assert.Contains(t, msg.ChatbotIDs, chatbot1.GetChatbotID(), "Chatbot1 should be listed as a chatbot of the group")
 	}
 
 	// Chatbot1 should receive GROUP_CHATBOT_INVIATION event
 	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
 	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
 	assert.Equal(t, msgc.EventType, pb.ServerEventType_GROUP_CHATBOT_INVITATION, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, groupId, msgc.GroupID, "The invitation should be for the group")
+	assert.Equal(t, pb.GroupType_SERVER_SIDE, msgc.GroupType, "The invitation should be for a server-side group")
+	assert.ElementsMatch(t, []string{alice.GetUserID(), bob.GetUserID(), carol.GetUserID()}, msgc.ParticipantIDs, "The invitation should list the members")
 
 	// Chatbot1 should be in the group
 	chatbot1SessionDriver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
@@ -533,7 +595,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Contains(t, aliceSessionDriver.GetGroupChatbots(), chatbot1.GetChatbotID(), "Chatbot1 should be in the chatbot list")
 
 	// Chatbot1 distribute its sender key to all
-	err = chatbot1.DistributeSelfSenderKeyToAll(groupId)
+	err = chatbot1.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "Chatbot1 should be able to distribute its sender key to all")
 
 	// Alice, Bob, and Carol should receive sender key distribution messages from chatbot1
@@ -566,7 +628,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Carol send a message
-	err = carol.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	err = carol.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
 	assert.Nil(t, err, "Carol should be able to send a Message to the group")
 
 	// Alice and Bob should receive the Message
@@ -589,7 +651,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Bob send a message
-	err = bob.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Bob. Chatbot should not receive this."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = bob.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob. Chatbot should not receive this."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Bob should be able to send a Message to the group")
 
 	// Alice and Bob should receive the Message
@@ -610,7 +672,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot1 should still be able to send a message
-	err = chatbot1.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot1. I can speak."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot1.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot1. I can speak."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot1 should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the message.
@@ -635,7 +697,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Invite chatbot2 to the group with IGA
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbot2.GetChatbotID(), true, false)
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot2.GetChatbotID(), true, false)
 
 	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -654,7 +716,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Chatbot2 should have the group session")
 
 	// Bob tries to send a message to both chatbot
-	err = bob.SendServerSideGroupMessage(groupId, []byte("This is an IGA message. Chatbot 2 should receive it without learning my identity"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	err = bob.SendServerSideGroupMessage(ctx, groupId, []byte("This is an IGA message. Chatbot 2 should receive it without learning my identity"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
 	assert.Nil(t, err, "Bob should be able to send an IGA message to chatbots")
 
 	// Alice and Carol should receive the message.
@@ -703,7 +765,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot2 tries to send a message to the group
-	err = chatbot2.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot2.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
 
 	// Alice, Bob, and Carol should receive the message.
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -714,7 +776,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Bob invites Chatbot3 with pseudonymity
-	bob.RequestInviteChatbotToGroup(groupId, pb.GroupType_SERVER_SIDE, chatbot3.GetChatbotID(), true, true)
+	bob.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot3.GetChatbotID(), true, true)
 
 	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -733,7 +795,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Chatbot3 should have the group session")
 
 	// Alice issue a pseudonym
-	err = alice.CreateAndRegisterServerSidePseudonym(groupId, chatbot3.GetChatbotID())
+	err = alice.CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbot3.GetChatbotID())
 	assert.Nil(t, err, "Alice should be able to issue a pseudonym")
 
 	// Chatbot3 should receive a pseudonym registration message from Alice
@@ -751,7 +813,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	time.Sleep(500 * time.Millisecond) // TODO: fix race condition
 
 	// Alice send a message to chatbot 3
-	err = alice.SendServerSideGroupMessage(groupId, []byte("Hello Chatbot 3! I'm Alice."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello Chatbot 3! I'm Alice."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
 	assert.Nil(t, err, "Alice should be able to send a Message to the group")
 
 	// Chatbot3 should receive a message from Alice
@@ -778,7 +840,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Equal(t, 1, len(chatbot3SessionDriver.GetGroupParticipants()), "Chatbot3 should have Alice as the only participant.")
 
 	// Bob issues a pseudonym
-	err = bob.CreateAndRegisterServerSidePseudonym(groupId, chatbot3.GetChatbotID())
+	err = bob.CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbot3.GetChatbotID())
 	assert.Nil(t, err, "Bob should be able to issue a pseudonym")
 
 	// Chatbot3 should receive a pseudonym registration message from Bob
@@ -796,7 +858,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	time.Sleep(500 * time.Millisecond) // TODO: fix race condition
 
 	// Bob send a message to all chatbots.
-	err = bob.SendServerSideGroupMessage(groupId, []byte("Hello Chatbots! I'm Bob."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID()}, false)
+	err = bob.SendServerSideGroupMessage(ctx, groupId, []byte("Hello Chatbots! I'm Bob."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID()}, false)
 
 	// Alice and Carol should receive the message
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), carol.GetMessageChan()} {
@@ -806,14 +868,37 @@ func TestServerSideGroupMessage(t *testing.T) {
 		assert.Equal(t, "Hello Chatbots! I'm Bob.", string(msg.Message), "Should receive a group text Message from Bob")
 	}
 
-	// All chatbots should receive the message.
-	for _, c := range []<-chan OutputMessage{chatbot1.GetMessageChan(), chatbot2.GetMessageChan(), chatbot3.GetMessageChan()} {
-		msgc, success = timeOutReadFromChatbotMessageChannel(c)
+	// All chatbots should receive the message, over the channel they were invited with. Chatbot2 should not learn the
+	// sender, and chatbot3 should only learn the pseudonym of Bob.
+	bobPseudonym := bob.GetPseudoUser(groupId, chatbot3.GetChatbotID()).PseudoUserID
+	for _, expected := range []struct {
+		chatbot  *ClientSideChatbot
+		channel  pb.Channel
+		senderID string
+	}{
+		{chatbot1, pb.Channel_GROUP_CHANNEL, bob.GetUserID()},
+		{chatbot2, pb.Channel_IGA_CHANNEL, ""},
+		{chatbot3, pb.Channel_PSEUDO_CHANNEL, bobPseudonym},
+	} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(expected.chatbot.GetMessageChan())
 		assert.True(t, success, "Chatbots should receive a Message from Bob")
 		assert.Equal(t, msgc.MessageType, pb.MessageType_TEXT_MESSAGE, "Chatbots should receive a group text Message from Bob")
 		assert.Equal(t, "Hello Chatbots! I'm Bob.", string(msgc.Message), "Chatbots should receive a group text Message from Bob")
+		assert.Equal(t, groupId, msgc.GroupID, "Chatbots should learn the group of the message")
+		assert.Equal(t, expected.channel, msgc.Channel, "Chatbots should learn the channel of the message")
+		assert.Equal(t, expected.senderID, msgc.SenderID, "Chatbots should only learn the sender they may know")
+		assert.NotEmpty(t, msgc.MessageID, "The server should assign a message ID")
+		assert.False(t, msgc.Timestamp.IsZero(), "The server should timestamp the message")
 	}
 
+	// Chatbot3 rejects a message under a pseudonym that was never registered
+	_, _, err = chatbot3.HandleServerSideGroupMessage(ctx, &pb.MessageWrapper{
+		SenderID:    "unregistered-pseudonym",
+		RecipientID: groupId,
+		IsPseudo:    true,
+	})
+	assert.ErrorIs(t, err, ErrUnknownPseudonym, "Chatbot3 should reject a message under an unregistered pseudonym")
+
 	// Alice, Bob, and Carol should receive the validation message from both chatbot2 and chatbot3.
 	//for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
 	//	for i := 0; i < 2; i++ {
@@ -840,7 +925,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot 3 sends a message to the group
-	err = chatbot3.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot3."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot3.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot3."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot3 should be able to send a message to the group")
 
 	// Alice, Bob, and Carol should receive the message.
@@ -857,7 +942,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Alice invite David to the group
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, david.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, david.GetUserID())
 
 	// Alice, Bob, and Carol should receive GROUP_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -881,7 +966,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Equal(t, msgc.EventType, pb.ServerEventType_GROUP_ADDITION, "Chatbot1 should receive a GROUP_ADDITION event")
 
 	// David distribute his sender key to all
-	err = david.DistributeSelfSenderKeyToAll(groupId)
+	err = david.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "David should be able to distribute his sender key to all")
 
 	// Alice, Bob, and Carol should receive sender key distribution messages from David
@@ -920,7 +1005,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.False(t, chatbot2SessionDriver.HasUserReceivingSession(david.GetUserID()), "Chatbot2 should not have David's sender key")
 
 	// Alice send a message
-	err = alice.SendServerSideGroupMessage(groupId, []byte("Here's David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Here's David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
 	assert.Nil(t, err, "Alice should be able to send a Message to the group")
 
 	// Bob and Carol should receive the message even after David joins.
@@ -946,7 +1031,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	// Note: at this moment, David's multi-treekem would not match chatbots' multi-treekem because no one has issued the update.
 
 	// David should be able to send a message to the group
-	err = david.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = david.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "David should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the Message
@@ -962,7 +1047,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.False(t, success, "Chatbot1 should not receive a Message from David")
 
 	// David send a message which the chatbot should receive
-	err = david.SendServerSideGroupMessage(groupId, []byte("This message is also for the chatbot."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	err = david.SendServerSideGroupMessage(ctx, groupId, []byte("This message is also for the chatbot."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
 	assert.Nil(t, err, "David should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the Message
@@ -1008,7 +1093,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// David issues a pseudonym for chatbot 3
-	err = david.CreateAndRegisterServerSidePseudonym(groupId, chatbot3.GetChatbotID())
+	err = david.CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbot3.GetChatbotID())
 	assert.Nil(t, err, "David should be able to issue a pseudonym")
 
 	// Chatbot3 should receive a pseudonym registration message from David
@@ -1026,7 +1111,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	time.Sleep(500 * time.Millisecond) // TODO: fix race condition
 
 	// David send a message to all chatbots.
-	err = david.SendServerSideGroupMessage(groupId, []byte("Hello Chatbots! I'm David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID()}, false)
+	err = david.SendServerSideGroupMessage(ctx, groupId, []byte("Hello Chatbots! I'm David."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID()}, false)
 
 	// Alice, Bob, and Carol should receive the message
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -1061,7 +1146,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot1 send a message
-	err = chatbot1.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot1."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot1.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot1."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot1 should be able to send a Message to the group")
 
 	// Alice, Bob, Carol, and David should receive the message.
@@ -1073,7 +1158,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot2 send a message.
-	err = chatbot2.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot2.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot2 should be able to send a Message to the group")
 
 	// Alice, Bob, Carol, and David should receive the message.
@@ -1085,19 +1170,70 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Remove Carol from the group
-	bob.RequestRemoveUserFromGroup(groupId, carol.GetUserID())
+	bob.RequestRemoveUserFromGroup(ctx, groupId, carol.GetUserID())
 
-	// Alice, Bob, Carol, and David should receive GROUP_REMOVAL event
-	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan(), david.GetMessageChan()} {
-		msg, success = timeOutReadFromUserMessageChannel(c)
-		assert.True(t, success, "Should receive a GROUP_REMOVAL event")
-		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_REMOVAL, "Should receive a GROUP_REMOVAL event")
+	// Carol should receive GROUP_REMOVAL event
+	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
+	assert.True(t, success, "Carol should receive a GROUP_REMOVAL event")
+	assert.Equal(t, pb.ServerEventType_GROUP_REMOVAL, msg.EventType, "Carol should receive a GROUP_REMOVAL event")
+
+	// Alice, Bob, and David should receive GROUP_REMOVAL event, the rotated sender keys of the other remaining members
+	// and of Chatbot1, and (except Bob) the rekey from Bob, in any order
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), david.GetMessageChan()} {
+		expectedRekeys := 1
+		if c == bob.GetMessageChan() {
+			expectedRekeys = 0
+		}
+		removals, senderKeys, rekeys := 0, 0, 0
+		for i := 0; i < 4+expectedRekeys; i++ {
+			msg, success = timeOutReadFromUserMessageChannel(c)
+			assert.True(t, success, "Should receive the removal, the sender keys, and the rekey")
+			switch {
+			case msg.EventType == pb.ServerEventType_GROUP_REMOVAL:
+				removals++
+			case msg.MessageType == pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE:
+				senderKeys++
+			case msg.MessageType == pb.MessageType_SKIP:
+				rekeys++
+			}
+		}
+		assert.Equal(t, 1, removals, "Should receive a GROUP_REMOVAL event")
+		assert.Equal(t, 3, senderKeys, "Should receive the rotated sender keys of the remaining members and Chatbot1")
+		assert.Equal(t, expectedRekeys, rekeys, "Should receive the rekey from Bob")
+	}
+
+	// Chatbot 1 should receive GROUP_REMOVAL event, the rotated sender keys of Alice, Bob, and David, and the rekey from Bob
+	removals, senderKeys, rekeys := 0, 0, 0
+	for i := 0; i < 5; i++ {
+		msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+		assert.True(t, success, "Chatbot1 should receive the removal, the sender keys, and the rekey")
+		switch {
+		case msgc.EventType == pb.ServerEventType_GROUP_REMOVAL:
+			removals++
+		case msgc.MessageType == pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE:
+			senderKeys++
+		case msgc.MessageType == pb.MessageType_SKIP:
+			rekeys++
+		}
 	}
+	assert.Equal(t, 1, removals, "Chatbot1 should receive a GROUP_REMOVAL event")
+	assert.Equal(t, 3, senderKeys, "Chatbot1 should receive the rotated sender keys of Alice, Bob, and David")
+	assert.Equal(t, 1, rekeys, "Chatbot1 should receive the rekey from Bob")
 
-	// Chatbot 1 should receive GROUP_REMOVAL event
-	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
-	assert.True(t, success, "Chatbot1 should receive a GROUP_REMOVAL event")
-	assert.Equal(t, msgc.EventType, pb.ServerEventType_GROUP_REMOVAL, "Chatbot1 should receive a GROUP_REMOVAL event")
+	// IGA chatbots should receive the rekey from Bob
+	for _, c := range []<-chan OutputMessage{chatbot2.GetMessageChan(), chatbot3.GetMessageChan()} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(c)
+		assert.True(t, success, "Chatbots should receive the rekey from Bob")
+		assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbots should receive the rekey from Bob")
+	}
+
+	// Carol keeps her old state, as a compromised member would, but it does not follow the group any more
+	assert.False(t, treekemGroupEqual(carolSessionDriver.GetTreeKEMState(), aliceSessionDriver.GetTreeKEMState()), "Carol should not follow the TreeKEM")
+	assert.True(t, treekemGroupEqual(aliceSessionDriver.GetTreeKEMState(), davidSessionDriver.GetTreeKEMState()), "Alice and David should have the same TreeKEM state")
+	for _, cid := range []string{chatbot2.GetChatbotID(), chatbot3.GetChatbotID()} {
+		assert.Equal(t, aliceSessionDriver.GetMultiTreeKEM().GetRootSecret(cid), davidSessionDriver.GetMultiTreeKEM().GetRootSecret(cid), "Alice and David should share the chatbot root")
+		assert.NotEqual(t, aliceSessionDriver.GetMultiTreeKEM().GetRootSecret(cid), carolSessionDriver.GetMultiTreeKEM().GetRootSecret(cid), "Carol should not know the new chatbot root")
+	}
 
 	// Carol should not have the group session
 	carolSessionDriver, err = carol.Client.GetServerSideGroupSessionDriver(groupId)
@@ -1110,7 +1246,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.NotContains(t, davidSessionDriver.GetGroupParticipants(), carol.GetUserID(), "Carol should not be in the participant list")
 
 	// Alice send a hide trigger message
-	err = alice.SendServerSideGroupMessage(groupId, []byte("Bye Carol."), pb.MessageType_TEXT_MESSAGE, nil, true)
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Bye Carol."), pb.MessageType_TEXT_MESSAGE, nil, true)
 	assert.Nil(t, err, "Alice should be able to send a Message to the group")
 
 	// Carol should not receive the message
@@ -1143,7 +1279,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	//}
 
 	// Bob send a hide trigger message to chatbot 2
-	err = bob.SendServerSideGroupMessage(groupId, []byte("Only for Chatbot2."), pb.MessageType_TEXT_MESSAGE, []string{chatbot2.GetChatbotID()}, true)
+	err = bob.SendServerSideGroupMessage(ctx, groupId, []byte("Only for Chatbot2."), pb.MessageType_TEXT_MESSAGE, []string{chatbot2.GetChatbotID()}, true)
 	assert.Nil(t, err, "Bob should be able to send a Message to the group")
 
 	// Alice and David should receive the Message
@@ -1185,7 +1321,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// David send a hide trigger message to chatbot 3
-	err = david.SendServerSideGroupMessage(groupId, []byte("Only for Chatbot3."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, true)
+	err = david.SendServerSideGroupMessage(ctx, groupId, []byte("Only for Chatbot3."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, true)
 	assert.Nil(t, err, "David should be able to send a Message to the group")
 
 	// Alice and Bob should receive the Message
@@ -1209,181 +1345,1244 @@ func TestServerSideGroupMessage(t *testing.T) {
 		assert.Equal(t, msgc.MessageType, pb.MessageType_SKIP, "Chatbots should receive a SKIP Message from David")
 		assert.NotEqual(t, "Only for Chatbot3.", string(msgc.Message), "Chatbots should receive a SKIP Message from David")
 	}
+
+	// Alice send a hide trigger message to chatbot 1, which is neither IGA nor pseudonymous
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Only for Chatbot1."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, true)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+
+	// Bob and David should receive the Message
+	for _, c := range []<-chan user.OutputMessage{bob.GetMessageChan(), david.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msg.MessageType, "Should receive a group text Message from Alice")
+		assert.Equal(t, "Only for Chatbot1.", string(msg.Message), "Should receive a group text Message from Alice")
+	}
+
+	// Chatbot 1 should receive the original message.
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a Message from Alice")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot1 should receive a group text Message from Alice")
+	assert.Equal(t, "Only for Chatbot1.", string(msgc.Message), "Chatbot1 should receive a group text Message from Alice")
+
+	// Chatbot 2 and Chatbot 3 should receive the message with type SKIP.
+	for _, c := range []<-chan OutputMessage{chatbot2.GetMessageChan(), chatbot3.GetMessageChan()} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(c)
+		assert.True(t, success, "Chatbots should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbots should receive a SKIP Message from Alice")
+		assert.NotEqual(t, "Only for Chatbot1.", string(msgc.Message), "Chatbots should receive a SKIP Message from Alice")
+	}
+
+	// The message for chatbot 1 should look the same to the server whether chatbot 1 is triggered or not.
+	// These messages are only generated and never sent, so this check must stay at the end of the test.
+	triggeredWrapper, err := alice.GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte("Same length message."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, true)
+	assert.Nil(t, err, "Alice should be able to generate a hide trigger message")
+	notTriggeredWrapper, err := alice.GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte("Same length message."), pb.MessageType_TEXT_MESSAGE, []string{chatbot2.GetChatbotID()}, true)
+	assert.Nil(t, err, "Alice should be able to generate a hide trigger message")
+	assert.ElementsMatch(t, triggeredWrapper.GetChatbotIds(), notTriggeredWrapper.GetChatbotIds(), "All chatbots should be listed in both messages")
+
+	var triggeredChatbotWrapper, notTriggeredChatbotWrapper *pb.MessageWrapper
+	for _, chatbotMessage := range triggeredWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetChatbotID() == chatbot1.GetChatbotID() {
+			triggeredChatbotWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	for _, chatbotMessage := range notTriggeredWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetChatbotID() == chatbot1.GetChatbotID() {
+			notTriggeredChatbotWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	assert.NotNil(t, triggeredChatbotWrapper, "Chatbot1 should receive a message when triggered")
+	assert.NotNil(t, notTriggeredChatbotWrapper, "Chatbot1 should receive a message when not triggered")
+	assert.True(t, triggeredChatbotWrapper.GetHiddenTrigger() && notTriggeredChatbotWrapper.GetHiddenTrigger(), "Both messages should be hidden trigger messages")
+	assert.Equal(t, triggeredChatbotWrapper.GetChatbotIds(), notTriggeredChatbotWrapper.GetChatbotIds(), "Both messages should list the same chatbots")
+	assert.Equal(t, 3, len(triggeredChatbotWrapper.GetTreeKEMKeyUpdatePack().GetChatbotEpochs()), "Both messages should carry an epoch for every chatbot")
+	assertSameStructure(t, triggeredChatbotWrapper.ProtoReflect(), notTriggeredChatbotWrapper.ProtoReflect(), "MessageWrapper")
+
+	// The same holds for chatbot2, which has IGA and is only triggered by the second message.
+	var notTriggeredIGAWrapper, triggeredIGAWrapper *pb.MessageWrapper
+	for _, chatbotMessage := range triggeredWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetChatbotID() == chatbot2.GetChatbotID() {
+			notTriggeredIGAWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	for _, chatbotMessage := range notTriggeredWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetChatbotID() == chatbot2.GetChatbotID() {
+			triggeredIGAWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	assert.NotNil(t, triggeredIGAWrapper, "Chatbot2 should receive a message when triggered")
+	assert.NotNil(t, notTriggeredIGAWrapper, "Chatbot2 should receive a message when not triggered")
+	assertSameStructure(t, triggeredIGAWrapper.ProtoReflect(), notTriggeredIGAWrapper.ProtoReflect(), "MessageWrapper")
 }
 
-// TestMlsGroupMessage test the MLS group Message.
-func TestMlsGroupMessage(t *testing.T) {
+// TestRoutedServerSideGroupMessage test the mention-based and command-based routing of server-side group messages.
+func TestRoutedServerSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	setup()
 
+	// Chatbots register their mention names and command prefixes
+	assert.True(t, chatbot1.RegisterRouting(ctx, []string{"Weather"}, []string{"/forecast"}), "Chatbot1 should be able to register its routing")
+	assert.True(t, chatbot2.RegisterRouting(ctx, []string{"translator"}, []string{"/translate"}), "Chatbot2 should be able to register its routing")
+
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_MLS)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
-	// Alice should have the group session
-	aliceSessionDriver, err := alice.Client.GetMlsGroupSessionDriver(groupId)
-	assert.Nil(t, err, "Alice should have the group session")
-
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
-
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_MLS, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
 	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
-	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
-
-	// Bob should have the group session
-	bobSessionDriver, err := bob.Client.GetMlsGroupSessionDriver(groupId)
-	assert.Nil(t, err, "Bob should have the group session")
-
-	// Alice should receive a GROUP_ADDITION event.
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
 	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
 	assert.True(t, success, "Alice should receive a group addition event")
-	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
 
-	// Alice and Bob should have the same state
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+	// Bob distribute his sender key to all
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
 
-	// Alice send a message to the group
-	err = alice.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
-	assert.Nil(t, err, "Alice should be able to send a message to the group")
+	// Alice invites chatbot1 without IGA and chatbot2 with IGA
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
 
-	// Bob should receive the message
+	err = chatbot1.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Chatbot1 should be able to distribute its sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution Message from Chatbot1")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution Message from Chatbot1")
+	}
+	for i := 0; i < 2; i++ {
+		msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+		assert.True(t, success, "Should receive a sender key distribution Message from Alice and Bob")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msgc.MessageType, "Should receive a sender key distribution Message from Alice and Bob")
+	}
+
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot2.GetChatbotID(), true, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot2 should receive a GROUP_CHATBOT_INVIATION event")
+
+	// Members should learn the routing metadata of the chatbots
+	assert.Equal(t, []string{"Weather"}, bob.GetChatbotRouting(groupId, chatbot1.GetChatbotID()).GetMentionNames(), "Bob should learn the mention names of chatbot1")
+	assert.Equal(t, []string{"/translate"}, alice.GetChatbotRouting(groupId, chatbot2.GetChatbotID()).GetCommandPrefixes(), "Alice should learn the command prefixes of chatbot2")
+
+	// Messages are routed by mentions and commands
+	receivingChatbotIDs, hideTrigger, err := alice.RouteGroupMessage(groupId, pb.GroupType_SERVER_SIDE, []byte("@weather will it rain?"))
+	assert.Nil(t, err, "Alice should be able to route a message")
+	assert.Equal(t, []string{chatbot1.GetChatbotID()}, receivingChatbotIDs, "Mentioning chatbot1 should route to chatbot1")
+	assert.False(t, hideTrigger, "The trigger should not be hidden by default")
+	receivingChatbotIDs, _, _ = alice.RouteGroupMessage(groupId, pb.GroupType_SERVER_SIDE, []byte("/translate bonjour"))
+	assert.Equal(t, []string{chatbot2.GetChatbotID()}, receivingChatbotIDs, "The command of chatbot2 should route to chatbot2")
+	receivingChatbotIDs, _, _ = alice.RouteGroupMessage(groupId, pb.GroupType_SERVER_SIDE, []byte("@"+chatbot2.GetChatbotID()+" and @Weather, hello"))
+	assert.Equal(t, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, receivingChatbotIDs, "Mentioning both chatbots should route to both")
+	receivingChatbotIDs, _, _ = alice.RouteGroupMessage(groupId, pb.GroupType_SERVER_SIDE, []byte("Just chatting."))
+	assert.Empty(t, receivingChatbotIDs, "A message without mentions or commands should not route to any chatbot")
+
+	// Alice sends a command to chatbot1
+	err = alice.SendRoutedGroupMessage(ctx, groupId, pb.GroupType_SERVER_SIDE, []byte("/forecast tomorrow"))
+	assert.Nil(t, err, "Alice should be able to send a routed message")
 	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a Message from Alice")
-	assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Bob should receive a group text message from Alice")
-	assert.Equal(t, "Hello everyone! I'm Alice.", string(msg.Message), "Bob should receive a group text message from Alice")
+	assert.Equal(t, "/forecast tomorrow", string(msg.Message), "Bob should receive a group text Message from Alice")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive the command")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot1 should receive the command")
+	assert.Equal(t, "/forecast tomorrow", string(msgc.Message), "Chatbot1 should receive the command")
+	_, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.False(t, success, "Chatbot2 should not receive the command of chatbot1")
+
+	// With hide trigger enabled for the group, routed messages hide which chatbot is triggered
+	alice.SetGroupHideTrigger(groupId, true)
+	_, hideTrigger, _ = alice.RouteGroupMessage(groupId, pb.GroupType_SERVER_SIDE, []byte("@translator hi"))
+	assert.True(t, hideTrigger, "The trigger should be hidden once enabled for the group")
+	err = alice.SendRoutedGroupMessage(ctx, groupId, pb.GroupType_SERVER_SIDE, []byte("@translator hi"))
+	assert.Nil(t, err, "Alice should be able to send a routed message")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "@translator hi", string(msg.Message), "Bob should receive a group text Message from Alice")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive the mention")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot2 should receive the mention")
+	assert.Equal(t, "@translator hi", string(msgc.Message), "Chatbot2 should receive the mention")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a hidden trigger message")
+	assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbot1 should receive a SKIP message")
+	assert.NotEqual(t, "@translator hi", string(msgc.Message), "Chatbot1 should not learn the message")
 
-	// Alice and Bob should have the same state
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+	// A member joining later should learn the routing metadata from the invitation
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
+	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
+	assert.True(t, success, "Carol should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Carol should receive a group invitation from Alice")
+	assert.Equal(t, []string{"/forecast"}, carol.GetChatbotRouting(groupId, chatbot1.GetChatbotID()).GetCommandPrefixes(), "Carol should learn the command prefixes of chatbot1")
+	assert.Equal(t, []string{"translator"}, carol.GetChatbotRouting(groupId, chatbot2.GetChatbotID()).GetMentionNames(), "Carol should learn the mention names of chatbot2")
+}
 
-	// Bob send a message to the group
-	err = bob.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
-	assert.Nil(t, err, "Bob should be able to send a message to the group")
+// TestForgedChatbotRouting test that the members only take the routing of a chatbot signed by the member who invited it.
+func TestForgedChatbotRouting(t *testing.T) {
+	ctx := context.Background()
+	setup()
 
-	// Alice should receive the message
-	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
-	assert.True(t, success, "Alice should receive a message from Bob")
-	assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Alice should receive a group text message from Bob")
-	assert.Equal(t, "Hello everyone! I'm Bob.", string(msg.Message), "Alice should receive a group text message from Bob")
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+	routing := &pb.ChatbotRouting{MentionNames: []string{"Weather"}, CommandPrefixes: []string{"/forecast"}}
+	signedRouting, err := alice.Client.SignChatbotRouting(groupId, chatbot1.GetChatbotID(), routing)
+	assert.Nil(t, err, "Alice should sign the routing of chatbot1")
+
+	// The routing signed by the inviter is taken
+	err = bob.SetSignedChatbotRouting(ctx, groupId, chatbot1.GetChatbotID(), signedRouting, []string{alice.GetUserID()})
+	assert.Nil(t, err, "Bob should take the routing signed by Alice")
+	assert.Equal(t, []string{"/forecast"}, bob.GetChatbotRouting(groupId, chatbot1.GetChatbotID()).GetCommandPrefixes(), "Bob should take the routing signed by Alice")
+	assert.Equal(t, alice.GetUserID(), bob.GetChatbotInviter(groupId, chatbot1.GetChatbotID()), "Bob should record Alice as the inviter")
+
+	// A routing changed by the server, signed for another chatbot or by someone else than the inviter is not taken
+	tampered := proto.Clone(signedRouting).(*pb.SignedChatbotRouting)
+	tampered.Routing.CommandPrefixes = []string{"/"}
+	err = bob.SetSignedChatbotRouting(ctx, groupId, chatbot1.GetChatbotID(), tampered, []string{alice.GetUserID()})
+	assert.ErrorIs(t, err, ErrBadSignature, "Bob should reject a routing changed by the server")
+	assert.Empty(t, bob.GetChatbotRouting(groupId, chatbot1.GetChatbotID()).GetCommandPrefixes(), "Bob should not take a routing changed by the server")
+	err = bob.SetSignedChatbotRouting(ctx, groupId, chatbot2.GetChatbotID(), signedRouting, []string{alice.GetUserID()})
+	assert.ErrorIs(t, err, ErrBadSignature, "Bob should reject a routing signed for another chatbot")
+	err = bob.SetSignedChatbotRouting(ctx, groupId, chatbot1.GetChatbotID(), signedRouting, []string{carol.GetUserID()})
+	assert.ErrorIs(t, err, ErrBadSignature, "Bob should reject a routing not signed by the inviter")
+	err = bob.SetSignedChatbotRouting(ctx, groupId, chatbot1.GetChatbotID(), &pb.SignedChatbotRouting{SignerID: alice.GetUserID(), Routing: routing}, []string{alice.GetUserID()})
+	assert.ErrorIs(t, err, ErrBadSignature, "Bob should reject an unsigned routing")
+	assert.Empty(t, bob.GetChatbotRouting(groupId, chatbot1.GetChatbotID()).GetCommandPrefixes(), "Bob should not take an unsigned routing")
+}
 
-	// Alice and Bob should have the same state
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+// TestChatbotScopes test that the access scopes of chatbots are enforced by the clients.
+func TestChatbotScopes(t *testing.T) {
+	ctx := context.Background()
+	setup()
 
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
+	assert.True(t, chatbot1.RegisterRouting(ctx, nil, []string{"/forecast"}), "Chatbot1 should be able to register its routing")
 
-	// Invite Carol to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_MLS, carol.GetUserID())
-	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
-	assert.True(t, success, "Carol should receive a group invitation from Alice")
-	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Carol should receive a group invitation from Alice")
+	// Alice is the initiator of the group
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
 
-	// Carol should have the group session
-	carolSessionDriver, err := carol.Client.GetMlsGroupSessionDriver(groupId)
-	assert.Nil(t, err, "Carol should have the group session")
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
 
-	// Alice and Bob should receive a GROUP_ADDITION event.
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
 		msg, success = timeOutReadFromUserMessageChannel(c)
-		assert.True(t, success, "Alice and Bob should receive a group addition event")
-		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice and Bob should receive a group addition event")
-	}
-
-	// Alice, Bob, and Carol should have the same state
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Alice and Carol should have the same group state")
-	assert.True(t, bobSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Bob and Carol should have the same group state")
-
-	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
-
-	// Alice, Bob, and Carol should have the same MLS tree root
-	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
-		assert.Equal(t, aliceSessionDriver.GetGroupState().Tree.RootHash(), sessionDriver.GetGroupState().Tree.RootHash(), "Alice, Bob, and Carol should have the same MLS tree root")
-
-		// Compare current root
-		aliceTreeRoot, err := aliceSessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
-		assert.Nil(t, err, "Alice should have the tree root")
-		otherTreeRoot, err := sessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
-		assert.Nil(t, err, "Other should have the tree root")
-		assert.Equal(t, aliceTreeRoot.Secret, otherTreeRoot.Secret, "Alice, Bob, and Carol should have the same MLS tree root")
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
 	}
 
-	// Alice invite chatbot1 to the group
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbot1.GetChatbotID(), false, false)
-
-	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
-	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
+	// Alice invites chatbot1 that may only read its commands, and chatbot2 with IGA and full access
+	alice.RequestInviteChatbotToGroupWithScopes(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false, &pb.ChatbotScopes{Commands: true})
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
 		msg, success = timeOutReadFromUserMessageChannel(c)
 		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
-		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_CHATBOT_ADDITION, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
 	}
-
-	// Chatbot1 should receive GROUP_CHATBOT_INVIATION event
 	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
 	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
-	assert.Equal(t, msgc.EventType, pb.ServerEventType_GROUP_CHATBOT_INVITATION, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.True(t, chatbot1.GetGroupScopes(groupId).GetCommands(), "Chatbot1 should learn its scopes")
+	assert.False(t, chatbot1.GetGroupScopes(groupId).GetMayPost(), "Chatbot1 should learn its scopes")
 
-	// Chatbot1 should be in the group
-	chatbot1SessionDriver, err := chatbot1.Client.GetMlsGroupSessionDriver(groupId)
-	assert.Nil(t, err, "Chatbot1 should have the group session")
-	assert.Contains(t, aliceSessionDriver.GetGroupChatbots(), chatbot1.GetChatbotID(), "Chatbot1 should be in the chatbot list")
+	err = chatbot1.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Chatbot1 should be able to distribute its sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution Message from Chatbot1")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution Message from Chatbot1")
+	}
+	for i := 0; i < 2; i++ {
+		msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+		assert.True(t, success, "Should receive a sender key distribution Message from Alice and Bob")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msgc.MessageType, "Should receive a sender key distribution Message from Alice and Bob")
+	}
 
-	// Chatbot1's state should match Alice, Bob, and Carol's state
-	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
-		assert.True(t, chatbot1SessionDriver.GetGroupState().Equals(*sessionDriver.GetGroupState()), "Chatbot1's state should match Alice, Bob, and Carol's state")
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot2.GetChatbotID(), true, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
 	}
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot2 should receive a GROUP_CHATBOT_INVIATION event")
 
-	// Carol send a message
-	err = carol.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
-	assert.Nil(t, err, "Carol should be able to send a Message to the group")
+	// A plain message addressed to both chatbots only reaches chatbot2
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello chatbots."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbots.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbots.", string(msgc.Message), "Chatbot2 should receive a group text Message from Alice")
+	_, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.False(t, success, "Chatbot1 should not receive a message that is not its command")
 
-	// Alice and Bob should receive the Message
+	// A command of chatbot1 reaches chatbot1
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("/forecast today"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive its command")
+	assert.Equal(t, "/forecast today", string(msgc.Message), "Chatbot1 should receive its command")
+
+	// Chatbot1 may not direct message members
+	err = chatbot1.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte("Hello Alice!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Chatbot1 should be able to send message to Alice")
+	_, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.False(t, success, "Alice should drop the direct message from chatbot1")
+
+	// Alice grants chatbot1 direct messages
+	err = alice.RequestUpdateChatbotScopes(ctx, groupId, chatbot1.GetChatbotID(), &pb.ChatbotScopes{Commands: true, MayDirectMessage: true})
+	assert.Nil(t, err, "Alice should be able to update the scopes of chatbot1")
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
 		msg, success = timeOutReadFromUserMessageChannel(c)
-		assert.True(t, success, "Should receive a Message from Carol")
-		assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Should receive a group text Message from Carol")
-		assert.Equal(t, "Hello everyone! I'm Carol.", string(msg.Message), "Should receive a group text Message from Carol")
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, msg.EventType, "Should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
 	}
-
-	// Chatbot1 should receive the Message
 	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
-	assert.True(t, success, "Chatbot1 should receive a Message from Alice")
-	assert.Equal(t, msgc.MessageType, pb.MessageType_TEXT_MESSAGE, "Chatbot1 should receive a group text Message from Alice")
-	assert.Equal(t, "Hello everyone! I'm Carol.", string(msgc.Message), "Chatbot1 should receive a group text Message from Alice")
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
+	assert.True(t, chatbot1.GetGroupScopes(groupId).GetMayDirectMessage(), "Chatbot1 should learn its new scopes")
 
-	// Chatbot1's state should match Alice, Bob, and Carol's state
-	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
-		assert.True(t, chatbot1SessionDriver.GetGroupState().Equals(*sessionDriver.GetGroupState()), "Chatbot1's state should match Alice, Bob, and Carol's state")
+	err = chatbot1.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte("Hello again Alice!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Chatbot1 should be able to send message to Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive the direct message from chatbot1")
+	assert.Equal(t, "Hello again Alice!", string(msg.Message), "Alice should receive the direct message from chatbot1")
+
+	// Only Alice, who invited chatbot2, may change its scopes
+	err = bob.RequestUpdateChatbotScopes(ctx, groupId, chatbot2.GetChatbotID(), &pb.ChatbotScopes{})
+	assert.ErrorIs(t, err, user.ErrRequestRejected, "Bob should not be able to update the scopes of chatbot2")
+
+	// Scope updates the server forges or replays are ignored
+	forgeScopeUpdate := func(chatbotID string, signedScopes *pb.SignedChatbotScopes) error {
+		_, err := bob.ParseServerEvent(ctx, &pb.ServerEvent{
+			EventType: pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE,
+			EventData: &pb.ServerEvent_GroupChatbotScopeUpdate{GroupChatbotScopeUpdate: &pb.GroupChatbotScopeUpdate{
+				SenderID:     signedScopes.GetSignerID(),
+				GroupID:      groupId,
+				ChatbotID:    chatbotID,
+				GroupType:    pb.GroupType_SERVER_SIDE,
+				Scopes:       signedScopes.GetScopes(),
+				SignedScopes: signedScopes,
+			}},
+		})
+		return err
+	}
+	signedScopes, err := bob.Client.SignChatbotScopes(groupId, chatbot2.GetChatbotID(), &pb.ChatbotScopes{}, 100)
+	assert.Nil(t, err, "Bob should sign the scopes")
+	err = forgeScopeUpdate(chatbot2.GetChatbotID(), signedScopes)
+	assert.ErrorIs(t, err, ErrBadSignature, "Bob should reject scopes not signed by the inviter")
+	signedScopes, err = alice.Client.SignChatbotScopes(groupId, chatbot1.GetChatbotID(), &pb.ChatbotScopes{Commands: true}, 1)
+	assert.Nil(t, err, "Alice should sign the scopes")
+	err = forgeScopeUpdate(chatbot1.GetChatbotID(), signedScopes)
+	assert.ErrorIs(t, err, ErrReplayedMessage, "Bob should reject the replayed scopes")
+	assert.True(t, bob.GetChatbotScopes(groupId, chatbot2.GetChatbotID()).GetMayPost(), "Bob should keep the scopes of chatbot2")
+	assert.True(t, bob.GetChatbotScopes(groupId, chatbot1.GetChatbotID()).GetMayDirectMessage(), "Bob should keep the scopes of chatbot1")
+
+	// Alice revokes the right of chatbot2 to post
+	err = alice.RequestUpdateChatbotScopes(ctx, groupId, chatbot2.GetChatbotID(), &pb.ChatbotScopes{AllMessages: true})
+	assert.Nil(t, err, "Alice should be able to update the scopes of chatbot2")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, msg.EventType, "Should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
 	}
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, msgc.EventType, "Chatbot2 should receive a GROUP_CHATBOT_SCOPE_UPDATE event")
 
-	// Alice, Bob, and Carol should have the same MLS tree root
-	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
-		assert.Equal(t, aliceSessionDriver.GetGroupState().Tree.RootHash(), sessionDriver.GetGroupState().Tree.RootHash(), "Alice, Bob, and Carol should have the same MLS tree root")
+	err = chatbot2.SendServerSideGroupMessage(ctx, groupId, []byte("I should not post."), pb.MessageType_TEXT_MESSAGE)
+	assert.NotNil(t, err, "Chatbot2 should refuse to post without the scope")
 
-		// Compare current root
-		aliceTreeRoot, err := aliceSessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
-		assert.Nil(t, err, "Alice should have the tree root")
-		otherTreeRoot, err := sessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
-		assert.Nil(t, err, "Other should have the tree root")
-		assert.Equal(t, aliceTreeRoot.Secret, otherTreeRoot.Secret, "Alice, Bob, and Carol should have the same MLS tree root")
+	// Members drop the post of a chatbot ignoring its scopes
+	chatbot2.SetGroupScopes(groupId, &pb.ChatbotScopes{AllMessages: true, MayPost: true})
+	err = chatbot2.SendServerSideGroupMessage(ctx, groupId, []byte("I post anyway."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "A misbehaving chatbot2 should be able to send the message")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		_, success = timeOutReadFromUserMessageChannel(c)
+		assert.False(t, success, "Members should drop the post of chatbot2")
 	}
+}
 
-	// Add chatbot 4 to the group.
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbot4.GetChatbotID(), false, false)
+// TestRemoveIGAChatbot tests that a removed IGA chatbot cannot decrypt the IGA messages sent after its removal.
+func TestSenderKeyRotationWithoutMembershipEvents(t *testing.T) {
+	ctx := context.Background()
+	setup()
 
-	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
-	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
-		msg, success = timeOutReadFromUserMessageChannel(c)
+	// Alice is the initiator of the group, and invites Bob and Carol
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+	members := []*user.ClientSideUser{alice}
+	for _, invited := range []*user.ClientSideUser{bob, carol} {
+		alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, invited.GetUserID())
+		msg, success := timeOutReadFromUserMessageChannel(invited.GetMessageChan())
+		assert.True(t, success, "Invited user should receive a group invitation from Alice")
+		assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Invited user should receive a group invitation from Alice")
+		for _, member := range members {
+			msg, success = timeOutReadFromUserMessageChannel(member.GetMessageChan())
+			assert.True(t, success, "Members should receive a group addition event")
+			assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Members should receive a group addition event")
+		}
+		members = append(members, invited)
+	}
+
+	// Alice invites chatbot1 whose scopes hide membership changes
+	alice.RequestInviteChatbotToGroupWithScopes(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false, &pb.ChatbotScopes{Commands: true})
+	for _, member := range members {
+		msg, success := timeOutReadFromUserMessageChannel(member.GetMessageChan())
 		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
-		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_CHATBOT_ADDITION, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.False(t, chatbot1.GetGroupScopes(groupId).GetMembershipEvents(), "Chatbot1 should not learn membership changes")
+
+	// Alice removes Carol
+	err = alice.RequestRemoveUserFromGroup(ctx, groupId, carol.GetUserID())
+	assert.Nil(t, err, "Alice should be able to remove Carol")
+	msg, success := timeOutReadFromUserMessageChannel(carol.GetMessageChan())
+	assert.True(t, success, "Carol should receive a GROUP_REMOVAL event")
+	assert.Equal(t, pb.ServerEventType_GROUP_REMOVAL, msg.EventType, "Carol should receive a GROUP_REMOVAL event")
+
+	// Chatbot1 rotates its sender key for Alice and Bob, without learning the removal
+	for _, member := range []*user.ClientSideUser{alice, bob} {
+		rotated := false
+		for i := 0; i < 6 && !rotated; i++ {
+			msg, success = timeOutReadFromUserMessageChannel(member.GetMessageChan())
+			assert.True(t, success, "Members should receive the rotated sender key of Chatbot1")
+			rotated = msg.MessageType == pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE && msg.SenderID == chatbot1.GetChatbotID()
+		}
+		assert.True(t, rotated, "Members should receive the rotated sender key of Chatbot1")
+	}
+	for i := 0; i < 3; i++ {
+		msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+		assert.True(t, success, "Chatbot1 should receive the rotated sender keys of Alice and Bob and the rekey from Alice")
+		assert.NotEqual(t, pb.ServerEventType_GROUP_REMOVAL, msgc.EventType, "Chatbot1 should not output the removal")
 	}
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+	assert.ElementsMatch(t, []string{alice.GetUserID(), bob.GetUserID()}, chatbot1Driver.GetGroupParticipants(), "Chatbot1 should follow the remaining participants")
 
-	// Chatbot4 should receive GROUP_CHATBOT_INVIATION event
-	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot4.GetMessageChan())
-	assert.True(t, success, "Chatbot4 should receive a GROUP_CHATBOT_INVIATION event")
+	// Carol does not receive the rotated sender key
+	for {
+		msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
+		if !success {
+			break
+		}
+		assert.NotEqual(t, chatbot1.GetChatbotID(), msg.SenderID, "Carol should not receive the rotated sender key of Chatbot1")
+	}
+}
 
-	// Chatbot4 should be in the group
-	chatbot4SessionDriver, err := chatbot4.Client.GetMlsGroupSessionDriver(groupId)
-	assert.Nil(t, err, "Chatbot4 should have the group session")
+func TestRemoveIGAChatbot(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	// Alice is the initiator of the group
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	// Alice invites chatbot1 and chatbot2, both with IGA
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, cb.GetChatbotID(), true, false)
+		for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+			msg, success = timeOutReadFromUserMessageChannel(c)
+			assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+			assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+		}
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+	}
+
+	// A message to both chatbots gives them the same root
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello chatbots."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbots.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a Message from Alice")
+		assert.Equal(t, "Hello chatbots.", string(msgc.Message), "Chatbot should receive a group text Message from Alice")
+	}
+
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+	// Chatbot2 keeps its state after the removal, as a compromised chatbot would
+	chatbot2Driver, err := chatbot2.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot2 should have a session driver")
+	assert.Equal(t, chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), chatbot2Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 and Chatbot2 should share the root")
+
+	// Alice removes chatbot2
+	alice.RequestRemoveChatbotFromGroup(ctx, groupId, chatbot2.GetChatbotID())
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a GROUP_CHATBOT_REMOVAL event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_REMOVAL, msg.EventType, "Alice should receive a GROUP_CHATBOT_REMOVAL event")
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a GROUP_CHATBOT_REMOVAL event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_REMOVAL, msgc.EventType, "Chatbot2 should receive a GROUP_CHATBOT_REMOVAL event")
+
+	// Bob receives the removal and the rekey, in either order
+	receivedRemoval, receivedRekey := false, false
+	for i := 0; i < 2; i++ {
+		msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+		assert.True(t, success, "Bob should receive the removal and the rekey")
+		receivedRemoval = receivedRemoval || msg.EventType == pb.ServerEventType_GROUP_CHATBOT_REMOVAL
+		receivedRekey = receivedRekey || msg.MessageType == pb.MessageType_SKIP
+	}
+	assert.True(t, receivedRemoval, "Bob should receive a GROUP_CHATBOT_REMOVAL event")
+	assert.True(t, receivedRekey, "Bob should receive the rekey from Alice")
+
+	// Chatbot1 receives the rekey, chatbot2 does not
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive the rekey from Alice")
+	assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbot1 should receive the rekey from Alice")
+	_, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.False(t, success, "Chatbot2 should not receive the rekey from Alice")
+
+	assert.NotContains(t, aliceDriver.GetMultiTreeKEM().GetExternalNodeIDs(), chatbot2.GetChatbotID(), "Alice should remove the external node of chatbot2")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should follow the root of Alice")
+	assert.NotEqual(t, chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), chatbot2Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot2 should not know the new root")
+
+	// Later IGA messages reach chatbot1 only
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello chatbot1."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbot1.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbot1.", string(msgc.Message), "Chatbot1 should receive a group text Message from Alice")
+
+	// The removed chatbot cannot decrypt an IGA message even if it obtains the ciphertext
+	igaCipherText, err := aliceDriver.EncryptMessageByMultiTreeKEMRoot([]byte("Not for chatbot2."), pb.MessageType_TEXT_MESSAGE, "iga", nil, chatbot1.GetChatbotID(), nil)
+	assert.Nil(t, err, "Alice should encrypt the IGA message")
+	cipherText := igaCipherText.Serialize()
+	message, messageType, err := chatbot1Driver.ParseEncryptedIGAMessage(cipherText, nil, "iga")
+	assert.Nil(t, err, "Chatbot1 should decrypt the IGA message")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, messageType, "Chatbot1 should decrypt the IGA message")
+	assert.Equal(t, "Not for chatbot2.", string(message), "Chatbot1 should decrypt the IGA message")
+	message, messageType, err = chatbot2Driver.ParseEncryptedIGAMessage(cipherText, nil, "iga")
+	assert.ErrorIs(t, err, ErrUndecryptable, "Chatbot2 should not decrypt the IGA message")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot2 should not decrypt the IGA message")
+	assert.Nil(t, message, "Chatbot2 should not decrypt the IGA message")
+}
+
+// TestUnauthenticatedKeyUpdate tests that members and chatbots reject key updates the server tampered with.
+func TestUnauthenticatedKeyUpdate(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	// Alice is the initiator of the group
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	// Alice invites chatbot1 with IGA
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), true, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+
+	// Alice generates a message to chatbot1 without sending it, so that it can be tampered with on the way
+	messageWrapper, err := alice.GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte("Hello chatbot1."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to generate a Message to the group")
+	var chatbotMessageWrapper *pb.MessageWrapper
+	for _, chatbotMessage := range messageWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetChatbotID() == chatbot1.GetChatbotID() {
+			chatbotMessageWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	assert.NotNil(t, chatbotMessageWrapper, "Alice should generate a Message to chatbot1")
+	assert.Empty(t, chatbotMessageWrapper.GetTreeKEMKeyUpdatePack().GetSignature(), "The pack for a chatbot with IGA should not reveal the sender")
+	assert.NotEmpty(t, messageWrapper.GetTreeKEMKeyUpdatePack().GetSignature(), "The pack for the members should be signed")
+
+	rootBefore := chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret()
+
+	// A root substituted by the server is rejected by chatbot1
+	forged := proto.Clone(chatbotMessageWrapper).(*pb.MessageWrapper)
+	forged.TreeKEMKeyUpdatePack.NewRootPubKey = aliceDriver.GetMultiTreeKEM().GetRootPublic(chatbot1.GetChatbotID())
+	_, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, forged)
+	assert.ErrorIs(t, err, treekem.ErrUnauthenticatedKeyUpdate, "Chatbot1 should reject a substituted root")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should reject a substituted root")
+	assert.Equal(t, rootBefore, chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should not apply a rejected update")
+
+	// So is a pack without MAC
+	stripped := proto.Clone(chatbotMessageWrapper).(*pb.MessageWrapper)
+	stripped.TreeKEMKeyUpdatePack.ChatbotMACs = nil
+	_, messageType, err = chatbot1.HandleServerSideGroupMessage(ctx, stripped)
+	assert.ErrorIs(t, err, treekem.ErrUnauthenticatedKeyUpdate, "Chatbot1 should reject a pack without MAC")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should reject a pack without MAC")
+	assert.Equal(t, rootBefore, chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should not apply a rejected update")
+
+	// The genuine pack is accepted
+	message, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, chatbotMessageWrapper)
+	assert.Nil(t, err, "Chatbot1 should accept the genuine pack")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, messageType, "Chatbot1 should accept the genuine pack")
+	assert.Equal(t, "Hello chatbot1.", string(message), "Chatbot1 should receive the Message from Alice")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should follow the root of Alice")
+
+	// Bob rejects the pack for the members if its signature does not verify
+	forgedUser := proto.Clone(messageWrapper).(*pb.MessageWrapper)
+	forgedUser.ChatbotMessages = nil
+	forgedUser.TreeKEMKeyUpdatePack.Signature[0] ^= 0xff
+	_, messageType, err = bob.HandleServerSideGroupMessage(forgedUser)
+	assert.ErrorIs(t, err, user.ErrBadSignature, "Bob should reject a pack with a bad signature")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Bob should reject a pack with a bad signature")
+}
+
+func TestForgedHiddenTriggerMessage(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Alice invites chatbot1 without IGA
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), false, false)
+	msg, success := timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a GROUP_CHATBOT_ADDITION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Alice should receive a GROUP_CHATBOT_ADDITION event")
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+
+	// Anyone who knows the external node key of chatbot1 can seal a message to it, but not sign it as Alice
+	forgedCipherText, err := aliceDriver.EncryptMessageForHiddenTrigger([]byte("Forged trigger"), pb.MessageType_TEXT_MESSAGE, "forged", chatbot1.GetChatbotID(), true, bob.Client.GetIdentityKey())
+	assert.Nil(t, err, "A hidden trigger message should be sealed")
+	forged := &pb.MessageWrapper{SenderID: alice.GetUserID(), RecipientID: groupId, EncryptedMessage: forgedCipherText, HiddenTrigger: true, MessageID: "forged"}
+	_, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, forged)
+	assert.ErrorIs(t, err, ErrBadSignature, "Chatbot1 should reject a hidden trigger message not signed by its sender")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should reject a hidden trigger message not signed by its sender")
+
+	// The message signed by Alice is accepted
+	cipherText, err := aliceDriver.EncryptMessageForHiddenTrigger([]byte("Genuine trigger"), pb.MessageType_TEXT_MESSAGE, "genuine", chatbot1.GetChatbotID(), true, alice.Client.GetIdentityKey())
+	assert.Nil(t, err, "A hidden trigger message should be sealed")
+	genuine := &pb.MessageWrapper{SenderID: alice.GetUserID(), RecipientID: groupId, EncryptedMessage: cipherText, HiddenTrigger: true, MessageID: "genuine"}
+	message, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, genuine)
+	assert.Nil(t, err, "Chatbot1 should accept a hidden trigger message signed by its sender")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, messageType, "Chatbot1 should accept a hidden trigger message signed by its sender")
+	assert.Equal(t, "Genuine trigger", string(message), "Chatbot1 should receive the message from Alice")
+}
+
+func TestOutOfOrderKeyUpdate(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	// Alice is the initiator of the group
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	// Alice invites chatbot1 with IGA
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, chatbot1.GetChatbotID(), true, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+
+	// Alice generates messages to chatbot1 without sending them, so that they can be delivered out of order
+	generateChatbotMessage := func(message string) *pb.MessageWrapper {
+		messageWrapper, err := alice.GenerateServerSideGroupMessageCipherText(ctx, groupId, []byte(message), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+		assert.Nil(t, err, "Alice should be able to generate a Message to the group")
+		for _, chatbotMessage := range messageWrapper.GetChatbotMessages() {
+			if chatbotMessage.GetChatbotID() == chatbot1.GetChatbotID() {
+				return chatbotMessage.GetMessageWrapper()
+			}
+		}
+		t.Fatal("Alice should generate a Message to chatbot1")
+		return nil
+	}
+	first := generateChatbotMessage("First.")
+	second := generateChatbotMessage("Second.")
+	assert.Equal(t, uint64(1), first.GetTreeKEMKeyUpdatePack().GetChatbotEpochs()[chatbot1.GetChatbotID()], "The first update should move the root to epoch 1")
+	assert.Equal(t, uint64(2), second.GetTreeKEMKeyUpdatePack().GetChatbotEpochs()[chatbot1.GetChatbotID()], "The second update should move the root to epoch 2")
+
+	// A forged update far ahead of the root is not buffered
+	rootBefore := chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret()
+	farAhead := proto.Clone(second).(*pb.MessageWrapper)
+	farAhead.GetTreeKEMKeyUpdatePack().GetChatbotEpochs()[chatbot1.GetChatbotID()] = client.MaxPendingKeyUpdates + 1
+	_, _, err = chatbot1.HandleServerSideGroupMessage(ctx, farAhead)
+	assert.ErrorIs(t, err, treekem.ErrDesync, "Chatbot1 should reject an update too far ahead of its root")
+	assert.Equal(t, 0, chatbot1.Client.GetPendingKeyUpdates().Len(groupId, chatbot1.GetChatbotID()), "Chatbot1 should not buffer an update too far ahead of its root")
+
+	// A forged copy of the second message arrives first and is buffered, but does not take the place of the genuine one
+	forged := proto.Clone(second).(*pb.MessageWrapper)
+	forged.GetTreeKEMKeyUpdatePack().NewRootPubKey = bytes.Clone(forged.GetTreeKEMKeyUpdatePack().GetNewRootPubKey())
+	forged.GetTreeKEMKeyUpdatePack().NewRootPubKey[0] ^= 0xff
+	_, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, forged)
+	assert.Nil(t, err, "Chatbot1 should buffer a message ahead of its root without error")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should not deliver a message ahead of its root")
+
+	// The second message arrives before the first and is buffered
+	_, messageType, err = chatbot1.HandleServerSideGroupMessage(ctx, second)
+	assert.Nil(t, err, "Chatbot1 should buffer a message ahead of its root without error")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should not deliver a message ahead of its root")
+	assert.Equal(t, rootBefore, chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should not apply an update ahead of its root")
+	assert.Equal(t, 2, chatbot1.Client.GetPendingKeyUpdates().Len(groupId, chatbot1.GetChatbotID()), "Chatbot1 should buffer both candidates for the epoch")
+
+	// Once the first message arrives, the buffered one is handled right after it
+	message, messageType, err := chatbot1.HandleServerSideGroupMessage(ctx, first)
+	assert.Nil(t, err, "Chatbot1 should accept the next update")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, messageType, "Chatbot1 should accept the next update")
+	assert.Equal(t, "First.", string(message), "Chatbot1 should receive the first Message from Alice")
+	for len(chatbot1.GetErrorChan()) > 0 {
+		<-chatbot1.GetErrorChan()
+	}
+	outputs := chatbot1.handleReadyKeyUpdates(ctx)
+	select {
+	case err = <-chatbot1.GetErrorChan():
+		assert.ErrorIs(t, err, treekem.ErrUnauthenticatedKeyUpdate, "Chatbot1 should discard the forged candidate")
+	case <-time.After(time.Second):
+		t.Error("Chatbot1 should report the forged candidate")
+	}
+	assert.Equal(t, 1, len(outputs), "Chatbot1 should handle the buffered Message")
+	if len(outputs) == 1 {
+		assert.Equal(t, "Second.", string(outputs[0].Message), "Chatbot1 should receive the second Message from Alice")
+	}
+	assert.Equal(t, 0, chatbot1.Client.GetPendingKeyUpdates().Len(groupId, chatbot1.GetChatbotID()), "Chatbot1 should have no buffered update left")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should follow the root of Alice")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetTranscriptHash(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetTranscriptHash(), "Chatbot1 should have the transcript of Alice")
+
+	// A replayed update is rejected
+	_, messageType, err = chatbot1.HandleServerSideGroupMessage(ctx, first)
+	assert.NotNil(t, err, "Chatbot1 should reject a replayed update")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should reject a replayed update")
+
+	// An update is lost on the way, so chatbot1 asks Alice for the current root
+	_ = generateChatbotMessage("Lost.")
+	fourth := generateChatbotMessage("Fourth.")
+	_, messageType, err = chatbot1.HandleServerSideGroupMessage(ctx, fourth)
+	assert.Nil(t, err, "Chatbot1 should buffer a message ahead of its root without error")
+	assert.Equal(t, pb.MessageType(-1), messageType, "Chatbot1 should not deliver a message ahead of its root")
+	err = chatbot1.RequestRootRecovery(ctx, groupId, pb.GroupType_SERVER_SIDE, alice.GetUserID())
+	assert.Nil(t, err, "Chatbot1 should be able to request a root recovery")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive the root from Alice")
+	assert.Equal(t, pb.MessageType_ROOT_RECOVERY, msgc.MessageType, "Chatbot1 should receive the root from Alice")
+	assert.Equal(t, groupId, string(msgc.Message), "Chatbot1 should recover the root of the group")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetEpoch(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetEpoch(), "Chatbot1 should resume at the epoch of Alice")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should resume at the root of Alice")
+	assert.Equal(t, 0, chatbot1.Client.GetPendingKeyUpdates().Len(groupId, chatbot1.GetChatbotID()), "Chatbot1 should drop the updates the recovered root has passed")
+
+	// Chatbot1 follows the updates after the recovery again
+	message, messageType, err = chatbot1.HandleServerSideGroupMessage(ctx, generateChatbotMessage("After recovery."))
+	assert.Nil(t, err, "Chatbot1 should accept the update after the recovery")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, messageType, "Chatbot1 should accept the update after the recovery")
+	assert.Equal(t, "After recovery.", string(message), "Chatbot1 should receive the Message after the recovery")
+}
+
+func TestConcurrentServerSideGroupKeyUpdates(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	groupId := setupConcurrentGroup(t, pb.GroupType_SERVER_SIDE)
+	sendConcurrentGroupMessages(t, groupId, func(member *user.ClientSideUser, message string, chatbotIDs []string) error {
+		return member.SendServerSideGroupMessage(ctx, groupId, []byte(message), pb.MessageType_TEXT_MESSAGE, chatbotIDs, false)
+	}, func(chatbot *ClientSideChatbot, message string) error {
+		return chatbot.SendServerSideGroupMessage(ctx, groupId, []byte(message), pb.MessageType_TEXT_MESSAGE)
+	})
+
+	// Every member and chatbot ends up with the same roots
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	for _, member := range []*user.ClientSideUser{bob, carol, david} {
+		memberDriver, err := member.Client.GetServerSideGroupSessionDriver(groupId)
+		assert.Nil(t, err, "Member should have a session driver")
+		assert.True(t, treekemGroupEqual(aliceDriver.GetTreeKEMState(), memberDriver.GetTreeKEMState()), "Members should have the same TreeKEM state")
+		assert.True(t, multiTreeKemEqual(aliceDriver.GetMultiTreeKEM(), memberDriver.GetMultiTreeKEM()), "Members should have the same MultiTreeKEM state")
+	}
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2, chatbot3} {
+		chatbotDriver, err := cb.Client.GetServerSideGroupSessionDriver(groupId)
+		assert.Nil(t, err, "Chatbot should have a session driver")
+		assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(cb.GetChatbotID()), chatbotDriver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot should follow the root of the members")
+		assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetTranscriptHash(cb.GetChatbotID()), chatbotDriver.GetMultiTreeKEMExternal().GetTranscriptHash(), "Chatbot should have the transcript of the members")
+	}
+}
+
+func TestConcurrentMlsGroupKeyUpdates(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	groupId := setupConcurrentGroup(t, pb.GroupType_MLS)
+	sendConcurrentGroupMessages(t, groupId, func(member *user.ClientSideUser, message string, chatbotIDs []string) error {
+		return member.SendMlsGroupMessage(ctx, groupId, []byte(message), pb.MessageType_TEXT_MESSAGE, chatbotIDs, false)
+	}, func(chatbot *ClientSideChatbot, message string) error {
+		return chatbot.SendMlsGroupMessage(ctx, groupId, []byte(message), pb.MessageType_TEXT_MESSAGE)
+	})
+
+	// Every member and chatbot ends up with the same roots
+	aliceDriver, err := alice.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	for _, member := range []*user.ClientSideUser{bob, carol, david} {
+		memberDriver, err := member.Client.GetMlsGroupSessionDriver(groupId)
+		assert.Nil(t, err, "Member should have a session driver")
+		assert.True(t, aliceDriver.GetGroupState().Equals(*memberDriver.GetGroupState()), "Members should have the same MLS state")
+		for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2, chatbot3} {
+			assert.Equal(t, aliceDriver.GetMlsMultiTree().GetRootSecret(cb.GetChatbotID()), memberDriver.GetMlsMultiTree().GetRootSecret(cb.GetChatbotID()), "Members should have the same MlsMultiTree state")
+		}
+	}
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2, chatbot3} {
+		chatbotDriver, err := cb.Client.GetMlsGroupSessionDriver(groupId)
+		assert.Nil(t, err, "Chatbot should have a session driver")
+		assert.Equal(t, aliceDriver.GetMlsMultiTree().GetRootSecret(cb.GetChatbotID()), chatbotDriver.GetMlsMultiTreeExternal().GetRootSecret(), "Chatbot should follow the root of the members")
+		assert.Equal(t, aliceDriver.GetMlsMultiTree().GetTranscriptHash(cb.GetChatbotID()), chatbotDriver.GetMlsMultiTreeExternal().GetTranscriptHash(), "Chatbot should have the transcript of the members")
+	}
+}
+
+func TestCipherSuiteServerSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	// Alice creates a group running with X25519, Ed25519 and ChaCha20-Poly1305
+	groupId, err := alice.CreateGroupWithCipherSuite(ctx, pb.GroupType_SERVER_SIDE, treekem.X25519_CHACHA20POLY1305_SHA256_Ed25519)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	// Alice invites chatbot1 with IGA and chatbot2 with pseudonymity
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, cb.GetChatbotID(), true, cb == chatbot2)
+		for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+			msg, success = timeOutReadFromUserMessageChannel(c)
+			assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+			assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+		}
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+	}
+
+	// Everyone runs with the suite negotiated at group creation
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	bobDriver, err := bob.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Bob should have a session driver")
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+	chatbot2Driver, err := chatbot2.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot2 should have a session driver")
+	for _, suite := range []treekem.CipherSuite{aliceDriver.GetCipherSuite(), bobDriver.GetCipherSuite(), chatbot1Driver.GetCipherSuite(), chatbot2Driver.GetCipherSuite(), aliceDriver.GetTreeKEMState().Suite(), bobDriver.GetTreeKEMState().Suite()} {
+		assert.Equal(t, treekem.X25519_CHACHA20POLY1305_SHA256_Ed25519, suite.ID(), "Members and chatbots should run with the negotiated cipher suite")
+	}
+
+	// Alice issues a pseudonym, signed with Ed25519
+	err = alice.CreateAndRegisterServerSidePseudonym(ctx, groupId, chatbot2.GetChatbotID())
+	assert.Nil(t, err, "Alice should be able to issue a pseudonym")
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a pseudonym registration message from Alice")
+	assert.Equal(t, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, msgc.MessageType, "Chatbot2 should receive a pseudonym registration message from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a pseudonym registration message from Alice")
+	assert.Equal(t, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, msg.MessageType, "Bob should receive a pseudonym registration message from Alice")
+
+	time.Sleep(500 * time.Millisecond) // TODO: fix race condition
+
+	// A message to both chatbots is sealed and signed with the suite
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello chatbots."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbots.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot should receive a group text Message from Alice")
+		assert.Equal(t, "Hello chatbots.", string(msgc.Message), "Chatbot should receive a group text Message from Alice")
+	}
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should follow the root of Alice")
+	assert.True(t, multiTreeKemEqual(aliceDriver.GetMultiTreeKEM(), bobDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
+
+	// Chatbot1 replies under its external root
+	err = chatbot1.SendServerSideGroupMessage(ctx, groupId, []byte("Hello from chatbot1."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Chatbot1 should be able to send a Message to the group")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from Chatbot1")
+		assert.Equal(t, "Hello from chatbot1.", string(msg.Message), "Should receive a group text Message from Chatbot1")
+	}
+}
+
+// TestMlsGroupMessage test the MLS group Message.
+func TestHybridKEMServerSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	// Alice invites chatbot1 with IGA and the hybrid KEM, and chatbot2 with IGA and the classical KEM
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		alice.RequestInviteChatbotToGroupWithHybridKEM(ctx, groupId, pb.GroupType_SERVER_SIDE, cb.GetChatbotID(), true, false, nil, cb == chatbot1)
+		for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+			msg, success = timeOutReadFromUserMessageChannel(c)
+			assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+			assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+		}
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+	}
+
+	// Both members and the chatbots agree on which chatbot uses the hybrid KEM
+	aliceDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have a session driver")
+	bobDriver, err := bob.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Bob should have a session driver")
+	chatbot1Driver, err := chatbot1.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have a session driver")
+	chatbot2Driver, err := chatbot2.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot2 should have a session driver")
+	for _, driver := range []*client.ServerSideGroupSessionDriver{aliceDriver, bobDriver} {
+		assert.True(t, driver.GetMultiTreeKEM().IsExternalNodeHybridKEM(chatbot1.GetChatbotID()), "Chatbot1 should use the hybrid KEM")
+		assert.False(t, driver.GetMultiTreeKEM().IsExternalNodeHybridKEM(chatbot2.GetChatbotID()), "Chatbot2 should use the classical KEM")
+	}
+	assert.True(t, chatbot1Driver.GetMultiTreeKEMExternal().IsHybridKEM(), "Chatbot1 should use the hybrid KEM")
+	assert.False(t, chatbot2Driver.GetMultiTreeKEMExternal().IsHybridKEM(), "Chatbot2 should use the classical KEM")
+
+	// A message to both chatbots rekeys both roots, each with its own KEM
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello chatbots."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello chatbots.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot should receive a group text Message from Alice")
+		assert.Equal(t, "Hello chatbots.", string(msgc.Message), "Chatbot should receive a group text Message from Alice")
+	}
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot1.GetChatbotID()), chatbot1Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot1 should follow the root of Alice")
+	assert.Equal(t, aliceDriver.GetMultiTreeKEM().GetRootSecret(chatbot2.GetChatbotID()), chatbot2Driver.GetMultiTreeKEMExternal().GetRootSecret(), "Chatbot2 should follow the root of Alice")
+	assert.True(t, multiTreeKemEqual(aliceDriver.GetMultiTreeKEM(), bobDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
+
+	// The chatbots reply, each updating its external node with its own KEM
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2} {
+		err = cb.SendServerSideGroupMessage(ctx, groupId, []byte("Hello from "+cb.GetChatbotID()+"."), pb.MessageType_TEXT_MESSAGE)
+		assert.Nil(t, err, "Chatbot should be able to send a Message to the group")
+		for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+			msg, success = timeOutReadFromUserMessageChannel(c)
+			assert.True(t, success, "Should receive a Message from the chatbot")
+			assert.Equal(t, "Hello from "+cb.GetChatbotID()+".", string(msg.Message), "Should receive a group text Message from the chatbot")
+		}
+	}
+	assert.True(t, multiTreeKemEqual(aliceDriver.GetMultiTreeKEM(), bobDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
+
+	// The hybrid KEM cannot be used in MLS groups
+	mlsGroupId, err := alice.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Alice should be able to create a MLS group")
+	err = alice.SetChatbotHybridKEM(mlsGroupId, pb.GroupType_MLS, chatbot1.GetChatbotID(), true)
+	assert.NotNil(t, err, "Alice should not be able to use the hybrid KEM in a MLS group")
+}
+
+func TestMlsGroupMessage(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	// Alice is the initiator of the group
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	// Alice should have the group session
+	aliceSessionDriver, err := alice.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have the group session")
+
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
+
+	// Invite Bob to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
+
+	// Bob should have the group session
+	bobSessionDriver, err := bob.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Bob should have the group session")
+
+	// Alice should receive a GROUP_ADDITION event.
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
+
+	// Alice and Bob should have the same state
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+
+	// Alice send a message to the group
+	err = alice.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Alice should be able to send a message to the group")
+
+	// Bob should receive the message
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Bob should receive a group text message from Alice")
+	assert.Equal(t, "Hello everyone! I'm Alice.", string(msg.Message), "Bob should receive a group text message from Alice")
+
+	// Alice and Bob should have the same state
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+
+	// Bob send a message to the group
+	err = bob.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Bob should be able to send a message to the group")
+
+	// Alice should receive the message
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a message from Bob")
+	assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Alice should receive a group text message from Bob")
+	assert.Equal(t, "Hello everyone! I'm Bob.", string(msg.Message), "Alice should receive a group text message from Bob")
+
+	// Alice and Bob should have the same state
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
+
+	// Invite Carol to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, carol.GetUserID())
+	msg, success = timeOutReadFromUserMessageChannel(carol.GetMessageChan())
+	assert.True(t, success, "Carol should receive a group invitation from Alice")
+	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Carol should receive a group invitation from Alice")
+
+	// Carol should have the group session
+	carolSessionDriver, err := carol.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Carol should have the group session")
+
+	// Alice and Bob should receive a GROUP_ADDITION event.
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Alice and Bob should receive a group addition event")
+		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice and Bob should receive a group addition event")
+	}
+
+	// Alice, Bob, and Carol should have the same state
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Alice and Carol should have the same group state")
+	assert.True(t, bobSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Bob and Carol should have the same group state")
+
+	assert.True(t, aliceSessionDriver.GetGroupState().Equals(**aliceSessionDriver.GetMlsMultiTree().MlsState), "Alice and Alice's MlsMultiTree should have the same group state")
+
+	// Alice, Bob, and Carol should have the same MLS tree root
+	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
+		assert.Equal(t, aliceSessionDriver.GetGroupState().Tree.RootHash(), sessionDriver.GetGroupState().Tree.RootHash(), "Alice, Bob, and Carol should have the same MLS tree root")
+
+		// Compare current root
+		aliceTreeRoot, err := aliceSessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
+		assert.Nil(t, err, "Alice should have the tree root")
+		otherTreeRoot, err := sessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
+		assert.Nil(t, err, "Other should have the tree root")
+		assert.Equal(t, aliceTreeRoot.Secret, otherTreeRoot.Secret, "Alice, Bob, and Carol should have the same MLS tree root")
+	}
+
+	// Alice invite chatbot1 to the group
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbot1.GetChatbotID(), false, false)
+
+	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_CHATBOT_ADDITION, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+
+	// Chatbot1 should receive GROUP_CHATBOT_INVIATION event
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, msgc.EventType, pb.ServerEventType_GROUP_CHATBOT_INVITATION, "Chatbot1 should receive a GROUP_CHATBOT_INVIATION event")
+
+	// Chatbot1 should be in the group
+	chatbot1SessionDriver, err := chatbot1.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot1 should have the group session")
+	assert.Contains(t, aliceSessionDriver.GetGroupChatbots(), chatbot1.GetChatbotID(), "Chatbot1 should be in the chatbot list")
+
+	// Chatbot1's state should match Alice, Bob, and Carol's state
+	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
+		assert.True(t, chatbot1SessionDriver.GetGroupState().Equals(*sessionDriver.GetGroupState()), "Chatbot1's state should match Alice, Bob, and Carol's state")
+	}
+
+	// Carol send a message
+	err = carol.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	assert.Nil(t, err, "Carol should be able to send a Message to the group")
+
+	// Alice and Bob should receive the Message
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from Carol")
+		assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Should receive a group text Message from Carol")
+		assert.Equal(t, "Hello everyone! I'm Carol.", string(msg.Message), "Should receive a group text Message from Carol")
+	}
+
+	// Chatbot1 should receive the Message
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a Message from Alice")
+	assert.Equal(t, msgc.MessageType, pb.MessageType_TEXT_MESSAGE, "Chatbot1 should receive a group text Message from Alice")
+	assert.Equal(t, "Hello everyone! I'm Carol.", string(msgc.Message), "Chatbot1 should receive a group text Message from Alice")
+
+	// Chatbot1's state should match Alice, Bob, and Carol's state
+	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
+		assert.True(t, chatbot1SessionDriver.GetGroupState().Equals(*sessionDriver.GetGroupState()), "Chatbot1's state should match Alice, Bob, and Carol's state")
+	}
+
+	// Alice, Bob, and Carol should have the same MLS tree root
+	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
+		assert.Equal(t, aliceSessionDriver.GetGroupState().Tree.RootHash(), sessionDriver.GetGroupState().Tree.RootHash(), "Alice, Bob, and Carol should have the same MLS tree root")
+
+		// Compare current root
+		aliceTreeRoot, err := aliceSessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
+		assert.Nil(t, err, "Alice should have the tree root")
+		otherTreeRoot, err := sessionDriver.GetMlsMultiTree().GetTreeKEMRoot()
+		assert.Nil(t, err, "Other should have the tree root")
+		assert.Equal(t, aliceTreeRoot.Secret, otherTreeRoot.Secret, "Alice, Bob, and Carol should have the same MLS tree root")
+	}
+
+	// Add chatbot 4 to the group.
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbot4.GetChatbotID(), false, false)
+
+	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_CHATBOT_ADDITION, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+
+	// Chatbot4 should receive GROUP_CHATBOT_INVIATION event
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot4.GetMessageChan())
+	assert.True(t, success, "Chatbot4 should receive a GROUP_CHATBOT_INVIATION event")
+
+	// Chatbot4 should be in the group
+	chatbot4SessionDriver, err := chatbot4.Client.GetMlsGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Chatbot4 should have the group session")
 
 	// Alice, Bob, and Carol should have the same MLS tree root
 	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
@@ -1402,7 +2601,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.Equal(t, aliceSessionDriver.GetGroupState().Tree.RootHash(), chatbot4SessionDriver.GetGroupState().Tree.RootHash(), "Alice and Chatbot4 should have the same MLS tree root")
 
 	// Add Chatbot 2 to the group and set as IGA bot.
-	alice.RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbot2.GetChatbotID(), true, false)
+	alice.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbot2.GetChatbotID(), true, false)
 
 	// Alice, Bob, and Carol should have the same MLS tree root
 	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
@@ -1455,7 +2654,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	}
 
 	// Chatbot2 tries to send a message to the group
-	err = chatbot2.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
+	err = chatbot2.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Chatbot2."), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Chatbot2 should be able to send a Message to the group")
 
 	// Alice, Bob, and Carol should receive the Message
@@ -1485,7 +2684,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	}
 
 	// Bob tries to send a message to both chatbot
-	err = bob.SendMlsGroupMessage(groupId, []byte("This is an IGA message. Chatbot 2 should receive it without learning my identity"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot4.GetChatbotID()}, false)
+	err = bob.SendMlsGroupMessage(ctx, groupId, []byte("This is an IGA message. Chatbot 2 should receive it without learning my identity"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot4.GetChatbotID()}, false)
 	assert.Nil(t, err, "Bob should be able to send an IGA message to chatbots")
 
 	// Alice and Carol should receive the message.
@@ -1522,7 +2721,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	//}
 
 	// Bob invites Chatbot3 with pseudonymity
-	bob.RequestInviteChatbotToGroup(groupId, pb.GroupType_MLS, chatbot3.GetChatbotID(), true, true)
+	bob.RequestInviteChatbotToGroup(ctx, groupId, pb.GroupType_MLS, chatbot3.GetChatbotID(), true, true)
 
 	// Alice, Bob, and Carol should receive GROUP_CHATBOT_ADDITION event
 	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
@@ -1541,7 +2740,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Chatbot3 should have the group session")
 
 	// Alice issue a pseudonym
-	err = alice.CreateAndRegisterMlsPseudonym(groupId, chatbot3.GetChatbotID())
+	err = alice.CreateAndRegisterMlsPseudonym(ctx, groupId, chatbot3.GetChatbotID())
 	assert.Nil(t, err, "Alice should be able to issue a pseudonym")
 
 	// Chatbot3 should receive a pseudonym registration message from Alice
@@ -1583,7 +2782,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	}
 
 	// Alice send a message to the group
-	err = alice.SendMlsGroupMessage(groupId, []byte("Hello everyone! Chatbot 3 would only see my pseudonym."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID(), chatbot4.GetChatbotID()}, false)
+	err = alice.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! Chatbot 3 would only see my pseudonym."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID(), chatbot4.GetChatbotID()}, false)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob and Carol should receive the Message
@@ -1612,7 +2811,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	//}
 
 	// Bob register a pseudonym
-	err = bob.CreateAndRegisterMlsPseudonym(groupId, chatbot3.GetChatbotID())
+	err = bob.CreateAndRegisterMlsPseudonym(ctx, groupId, chatbot3.GetChatbotID())
 	assert.Nil(t, err, "Bob should be able to issue a pseudonym")
 
 	// Chatbot3 should receive a pseudonym registration message from Bob
@@ -1621,7 +2820,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.Equal(t, msgc.MessageType, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, "Chatbot3 should receive a pseudonym registration message from Bob")
 
 	// Bob sends a message with hiding triggers
-	err = bob.SendMlsGroupMessage(groupId, []byte("Only for chatbot1 and chatbot4."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot4.GetChatbotID()}, true)
+	err = bob.SendMlsGroupMessage(ctx, groupId, []byte("Only for chatbot1 and chatbot4."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID(), chatbot4.GetChatbotID()}, true)
 	assert.Nil(t, err, "Bob should be able to send a Message to the group")
 
 	// Alice and Carol should receive the Message
@@ -1648,6 +2847,60 @@ func TestMlsGroupMessage(t *testing.T) {
 		assert.NotEqual(t, "Only for chatbot1 and chatbot4.", string(msgc.Message), "Chatbots should receive a SKIP Message from Bob")
 	}
 
+	// Alice sends a message with hiding triggers to chatbot 2 only
+	err = alice.SendMlsGroupMessage(ctx, groupId, []byte("Only for chatbot2."), pb.MessageType_TEXT_MESSAGE, []string{chatbot2.GetChatbotID()}, true)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+
+	// Bob and Carol should receive the Message
+	for _, c := range []<-chan user.OutputMessage{bob.GetMessageChan(), carol.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msg.MessageType, "Should receive a group text Message from Alice")
+		assert.Equal(t, "Only for chatbot2.", string(msg.Message), "Should receive a group text Message from Alice")
+	}
+
+	// Chatbot2 should receive the original message.
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot2.GetMessageChan())
+	assert.True(t, success, "Chatbot2 should receive a Message from Alice")
+	assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot2 should receive a group text Message from Alice")
+	assert.Equal(t, "Only for chatbot2.", string(msgc.Message), "Chatbot2 should receive a group text Message from Alice")
+
+	// Chatbot1 and chatbot4 are not IGA, and should only learn that the message is not for them.
+	for _, c := range []<-chan OutputMessage{chatbot1.GetMessageChan(), chatbot4.GetMessageChan()} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(c)
+		assert.True(t, success, "Chatbots should receive a Message from Alice")
+		assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbots should receive a SKIP Message from Alice")
+		assert.Equal(t, client.HiddenTriggerNotForYou, msgc.Message, "Chatbots should only learn that the message is not for them")
+	}
+
+	// Chatbot3 should receive the message with type SKIP.
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a Message from Alice")
+	assert.Equal(t, pb.MessageType_SKIP, msgc.MessageType, "Chatbot3 should receive a SKIP Message from Alice")
+
+	// Chatbot1 and chatbot4 should still be in sync with the group, even though they were not triggered.
+	for _, sessionDriver := range []*client.MlsGroupSessionDriver{aliceSessionDriver, bobSessionDriver, carolSessionDriver} {
+		assert.True(t, sessionDriver.GetGroupState().Equals(*chatbot1SessionDriver.GetGroupState()), "Chatbot1 should match Alice, Bob, and Carol's MLS state")
+		assert.True(t, sessionDriver.GetGroupState().Equals(*chatbot4SessionDriver.GetGroupState()), "Chatbot4 should match Alice, Bob, and Carol's MLS state")
+	}
+
+	// The message for chatbot1, which it triggers, should look the same as the one for chatbot4, which it does not.
+	// This message is only generated and never sent, so this check must stay at the end of the test.
+	hiddenWrapper, err := alice.GenerateMlsGroupMessageCipherText(groupId, []byte("Only for chatbot1."), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, true)
+	assert.Nil(t, err, "Alice should be able to generate a hide trigger message")
+	var triggeredChatbotWrapper, notTriggeredChatbotWrapper *pb.MessageWrapper
+	for _, chatbotMessage := range hiddenWrapper.GetChatbotMessages() {
+		switch chatbotMessage.GetChatbotID() {
+		case chatbot1.GetChatbotID():
+			triggeredChatbotWrapper = chatbotMessage.GetMessageWrapper()
+		case chatbot4.GetChatbotID():
+			notTriggeredChatbotWrapper = chatbotMessage.GetMessageWrapper()
+		}
+	}
+	assert.NotNil(t, triggeredChatbotWrapper, "Chatbot1 should receive a message")
+	assert.NotNil(t, notTriggeredChatbotWrapper, "Chatbot4 should receive a message")
+	assertSameStructure(t, triggeredChatbotWrapper.ProtoReflect(), notTriggeredChatbotWrapper.ProtoReflect(), "MessageWrapper")
+
 	// Should receive validation messages from the chatbot 1 and 4
 	//for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan(), carol.GetMessageChan()} {
 	//	for i := 0; i < 2; i++ {
@@ -1659,6 +2912,297 @@ func TestMlsGroupMessage(t *testing.T) {
 
 }
 
+// TestRestoreChatbot tests that a chatbot killed in the middle of a conversation and restarted from its stored state
+// keeps decrypting the IGA and pseudonymous messages of its groups.
+func TestRestoreChatbot(t *testing.T) {
+	ctx := context.Background()
+	setup()
+	statePath := filepath.Join(t.TempDir(), "chatbot3.state")
+	chatbot3.SetStateStore(stores.NewEncryptedFileStore(statePath, []byte("chatbot3's passphrase")))
+
+	// Alice and Bob are in a server-side group with chatbot3 as a pseudonymous IGA chatbot
+	serverSideGroupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a server-side group")
+	alice.RequestInviteUserToGroup(ctx, serverSideGroupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+	err = bob.DistributeSelfSenderKeyToAll(ctx, serverSideGroupId)
+	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	alice.RequestInviteChatbotToGroup(ctx, serverSideGroupId, pb.GroupType_SERVER_SIDE, chatbot3.GetChatbotID(), true, true)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot3 should receive a GROUP_CHATBOT_INVIATION event")
+
+	err = alice.CreateAndRegisterServerSidePseudonym(ctx, serverSideGroupId, chatbot3.GetChatbotID())
+	assert.Nil(t, err, "Alice should be able to issue a pseudonym")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a pseudonym registration message from Alice")
+	assert.Equal(t, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, msgc.MessageType, "Chatbot3 should receive a pseudonym registration message from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a pseudonym registration message from Alice")
+	assert.Equal(t, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, msg.MessageType, "Bob should receive a pseudonym registration message from Alice")
+
+	time.Sleep(500 * time.Millisecond) // TODO: fix race condition
+
+	err = alice.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Hello Chatbot 3! I'm Alice."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a Message from Alice")
+	assert.Equal(t, "Hello Chatbot 3! I'm Alice.", string(msgc.Message), "Chatbot3 should receive a group text Message from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Hello Chatbot 3! I'm Alice.", string(msg.Message), "Bob should receive a group text Message from Alice")
+
+	// Chatbot3 answers, which updates its root
+	err = chatbot3.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Hello everyone! I'm Chatbot3."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Chatbot3 should be able to send a Message to the group")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from Chatbot3")
+		assert.Equal(t, "Hello everyone! I'm Chatbot3.", string(msg.Message), "Should receive a group text Message from Chatbot3")
+	}
+
+	// Alice and Bob are also in an MLS group with chatbot3 as an IGA chatbot
+	mlsGroupId, err := alice.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Alice should be able to create an MLS group")
+	alice.RequestInviteUserToGroup(ctx, mlsGroupId, pb.GroupType_MLS, bob.GetUserID())
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	alice.RequestInviteChatbotToGroup(ctx, mlsGroupId, pb.GroupType_MLS, chatbot3.GetChatbotID(), true, false)
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+	}
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a GROUP_CHATBOT_INVIATION event")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot3 should receive a GROUP_CHATBOT_INVIATION event")
+
+	err = bob.SendMlsGroupMessage(ctx, mlsGroupId, []byte("Hello Chatbot 3! I'm Bob."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	assert.Nil(t, err, "Bob should be able to send a message to the group")
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot3.GetMessageChan())
+	assert.True(t, success, "Chatbot3 should receive a Message from Bob")
+	assert.Equal(t, "Hello Chatbot 3! I'm Bob.", string(msgc.Message), "Chatbot3 should receive a group text Message from Bob")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a Message from Bob")
+	assert.Equal(t, "Hello Chatbot 3! I'm Bob.", string(msg.Message), "Alice should receive a group text Message from Bob")
+
+	// Chatbot3 is killed in the middle of the conversation, without the deactivation persisting its state on the way
+	// out, and the members keep writing to it
+	assert.Nil(t, chatbot3.closeConnection(), "Chatbot3 should be killed")
+	time.Sleep(200 * time.Millisecond) // Let the server notice that the streams of chatbot3 are gone
+
+	err = alice.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Are you still there, Chatbot 3?"), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Are you still there, Chatbot 3?", string(msg.Message), "Bob should receive a group text Message from Alice")
+	err = bob.SendMlsGroupMessage(ctx, mlsGroupId, []byte("Chatbot 3, are you there?"), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	assert.Nil(t, err, "Bob should be able to send a message to the group")
+	msg, success = timeOutReadFromUserMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a Message from Bob")
+	assert.Equal(t, "Chatbot 3, are you there?", string(msg.Message), "Alice should receive a group text Message from Bob")
+
+	// The state cannot be restored without the passphrase
+	_, err = RestoreClientSideChatbotBufconn(ctx, stores.NewEncryptedFileStore(statePath, []byte("wrong passphrase")), dialer())
+	assert.ErrorIs(t, err, stores.ErrWrongPassphrase, "Chatbot3 should not be restored with a wrong passphrase")
+
+	// Chatbot3 restarts from its stored state and decrypts what was sent while it was down
+	restoredChatbot3, err := RestoreClientSideChatbotBufconn(ctx, stores.NewEncryptedFileStore(statePath, []byte("chatbot3's passphrase")), dialer())
+	assert.Nil(t, err, "Chatbot3 should be restored from its stored state")
+	assert.Equal(t, chatbot3.GetChatbotID(), restoredChatbot3.GetChatbotID(), "The restored chatbot3 should have the same chatbot ID")
+	for _, expected := range []string{"Are you still there, Chatbot 3?", "Chatbot 3, are you there?"} {
+		msgc, success = timeOutReadFromChatbotMessageChannel(restoredChatbot3.GetMessageChan())
+		assert.True(t, success, "The restored chatbot3 should receive the message sent while it was down")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "The restored chatbot3 should receive a text message")
+		assert.Equal(t, expected, string(msgc.Message), "The restored chatbot3 should decrypt the message sent while it was down")
+	}
+
+	// And keeps talking in both groups
+	err = restoredChatbot3.SendMlsGroupMessage(ctx, mlsGroupId, []byte("I'm back!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "The restored chatbot3 should be able to send a message to the MLS group")
+	for _, c := range []<-chan user.OutputMessage{alice.GetMessageChan(), bob.GetMessageChan()} {
+		msg, success = timeOutReadFromUserMessageChannel(c)
+		assert.True(t, success, "Should receive a Message from the restored chatbot3")
+		assert.Equal(t, "I'm back!", string(msg.Message), "Should receive a group text Message from the restored chatbot3")
+	}
+	err = alice.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Welcome back, Chatbot 3."), pb.MessageType_TEXT_MESSAGE, []string{chatbot3.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a Message to the group")
+	msgc, success = timeOutReadFromChatbotMessageChannel(restoredChatbot3.GetMessageChan())
+	assert.True(t, success, "The restored chatbot3 should receive a Message from Alice")
+	assert.Equal(t, "Welcome back, Chatbot 3.", string(msgc.Message), "The restored chatbot3 should receive a group text Message from Alice")
+	msg, success = timeOutReadFromUserMessageChannel(bob.GetMessageChan())
+	assert.True(t, success, "Bob should receive a Message from Alice")
+	assert.Equal(t, "Welcome back, Chatbot 3.", string(msg.Message), "Bob should receive a group text Message from Alice")
+
+	aliceServerSideDriver, _ := alice.Client.GetServerSideGroupSessionDriver(serverSideGroupId)
+	chatbotServerSideDriver, _ := restoredChatbot3.Client.GetServerSideGroupSessionDriver(serverSideGroupId)
+	assert.Equal(t, aliceServerSideDriver.GetMultiTreeKEM().GetRootSecret(chatbot3.GetChatbotID()), chatbotServerSideDriver.GetMultiTreeKEMExternal().GetRootSecret(), "The restored chatbot3 should follow the root of Alice")
+	aliceMlsDriver, _ := alice.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	chatbotMlsDriver, _ := restoredChatbot3.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	assert.Equal(t, aliceMlsDriver.GetMlsMultiTree().GetRootSecret(chatbot3.GetChatbotID()), chatbotMlsDriver.GetMlsMultiTreeExternal().GetRootSecret(), "The restored chatbot3 should follow the MLS root of Alice")
+}
+
+// setupConcurrentGroup creates a group of Alice, Bob, Carol, and David with chatbot1, chatbot2, and chatbot3 as IGA
+// chatbots.
+func setupConcurrentGroup(t *testing.T, groupType pb.GroupType) string {
+	ctx := context.Background()
+	groupId, err := alice.CreateGroup(ctx, groupType)
+	assert.Nil(t, err, "Alice should be able to create a group")
+
+	members := []*user.ClientSideUser{alice}
+	for _, member := range []*user.ClientSideUser{bob, carol, david} {
+		alice.RequestInviteUserToGroup(ctx, groupId, groupType, member.GetUserID())
+		msg, success := timeOutReadFromUserMessageChannel(member.GetMessageChan())
+		assert.True(t, success, "Member should receive a group invitation from Alice")
+		assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Member should receive a group invitation from Alice")
+		for _, existing := range members {
+			msg, success = timeOutReadFromUserMessageChannel(existing.GetMessageChan())
+			assert.True(t, success, "Should receive a group addition event")
+			assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Should receive a group addition event")
+		}
+
+		if groupType == pb.GroupType_SERVER_SIDE {
+			err = member.DistributeSelfSenderKeyToAll(ctx, groupId)
+			assert.Nil(t, err, "Member should be able to distribute the sender key to all")
+			for _, existing := range members {
+				msg, success = timeOutReadFromUserMessageChannel(existing.GetMessageChan())
+				assert.True(t, success, "Should receive a sender key distribution message from the new member")
+				assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the new member")
+			}
+			for range members {
+				msg, success = timeOutReadFromUserMessageChannel(member.GetMessageChan())
+				assert.True(t, success, "New member should receive the sender key distribution messages of the group")
+				assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "New member should receive the sender key distribution messages of the group")
+			}
+		}
+		members = append(members, member)
+	}
+
+	for _, cb := range []*ClientSideChatbot{chatbot1, chatbot2, chatbot3} {
+		alice.RequestInviteChatbotToGroup(ctx, groupId, groupType, cb.GetChatbotID(), true, false)
+		for _, member := range members {
+			msg, success := timeOutReadFromUserMessageChannel(member.GetMessageChan())
+			assert.True(t, success, "Should receive a GROUP_CHATBOT_ADDITION event")
+			assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_ADDITION, msg.EventType, "Should receive a GROUP_CHATBOT_ADDITION event")
+		}
+		msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+		assert.True(t, success, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+		assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, msgc.EventType, "Chatbot should receive a GROUP_CHATBOT_INVIATION event")
+	}
+
+	return groupId
+}
+
+// sendConcurrentGroupMessages lets every member send IGA messages to all chatbots, and chatbot1 send messages to the
+// group, all at the same time. Every message has to be delivered to everyone despite the concurrent key updates.
+func sendConcurrentGroupMessages(t *testing.T, groupId string, sendMember func(*user.ClientSideUser, string, []string) error, sendChatbot func(*ClientSideChatbot, string) error) {
+	const memberMessages, chatbotMessages = 5, 2
+	members := []*user.ClientSideUser{alice, bob, carol, david}
+	chatbots := []*ClientSideChatbot{chatbot1, chatbot2, chatbot3}
+	chatbotIDs := []string{chatbot1.GetChatbotID(), chatbot2.GetChatbotID(), chatbot3.GetChatbotID()}
+
+	var wg sync.WaitGroup
+	for _, member := range members {
+		for i := 0; i < memberMessages; i++ {
+			wg.Add(1)
+			go func(member *user.ClientSideUser, message string) {
+				defer wg.Done()
+				assert.Nil(t, sendMember(member, message, chatbotIDs), "Member should be able to send a Message to the group")
+			}(member, fmt.Sprintf("%v %v", member.GetUserID(), i))
+		}
+	}
+	for i := 0; i < chatbotMessages; i++ {
+		wg.Add(1)
+		go func(message string) {
+			defer wg.Done()
+			assert.Nil(t, sendChatbot(chatbot1, message), "Chatbot1 should be able to send a Message to the group")
+		}(fmt.Sprintf("%v %v", chatbot1.GetChatbotID(), i))
+	}
+	wg.Wait()
+
+	// Every member receives the messages of everyone else
+	for _, member := range members {
+		received := make(map[string]bool)
+		for i := 0; i < (len(members)-1)*memberMessages+chatbotMessages; i++ {
+			msg, success := timeOutReadFromUserMessageChannel(member.GetMessageChan())
+			assert.True(t, success, "Member should receive every Message")
+			assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msg.MessageType, "Member should receive group text Messages")
+			received[string(msg.Message)] = true
+		}
+		for _, sender := range members {
+			for i := 0; i < memberMessages; i++ {
+				message := fmt.Sprintf("%v %v", sender.GetUserID(), i)
+				assert.Equal(t, sender != member, received[message], "Member should receive the Messages of the others")
+			}
+		}
+		for i := 0; i < chatbotMessages; i++ {
+			assert.True(t, received[fmt.Sprintf("%v %v", chatbot1.GetChatbotID(), i)], "Member should receive the Messages of chatbot1")
+		}
+	}
+
+	// Every chatbot receives every message of the members
+	for _, cb := range chatbots {
+		received := make(map[string]bool)
+		for i := 0; i < len(members)*memberMessages; i++ {
+			msgc, success := timeOutReadFromChatbotMessageChannel(cb.GetMessageChan())
+			assert.True(t, success, "Chatbot should receive every Message")
+			assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msgc.MessageType, "Chatbot should receive group text Messages")
+			received[string(msgc.Message)] = true
+		}
+		assert.Equal(t, len(members)*memberMessages, len(received), "Chatbot should receive every Message of the members")
+	}
+}
+
+func TestReplayRejection(t *testing.T) {
+	ctx := context.Background()
+	setup()
+
+	err := alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(chatbot1.GetChatbotID(), 1), []byte("Hello Chatbot1!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Alice should be able to send message to chatbot1")
+	msgc, success := timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive a message from Alice")
+	assert.NotEmpty(t, msgc.MessageID, "The message should have a MessageID")
+
+	_, err = chatbot1.ParseMessageWrapper(ctx, &pb.MessageWrapper{SenderID: alice.GetUserID(), RecipientID: chatbot1.GetChatbotID(), MessageID: msgc.MessageID})
+	assert.ErrorIs(t, err, ErrReplayedMessage, "Chatbot1 should reject the message delivered again")
+
+	// A group message delivered again is rejected before it reaches the ratchet.
+	groupId := setupConcurrentGroup(t, pb.GroupType_MLS)
+	err = alice.SendMlsGroupMessage(ctx, groupId, []byte("Hello group!"), pb.MessageType_TEXT_MESSAGE, []string{chatbot1.GetChatbotID()}, false)
+	assert.Nil(t, err, "Alice should be able to send a message to the group")
+	for _, member := range []*user.ClientSideUser{bob, carol, david} {
+		_, success := timeOutReadFromUserMessageChannel(member.GetMessageChan())
+		assert.True(t, success, "Member should receive the message of Alice")
+	}
+	msgc, success = timeOutReadFromChatbotMessageChannel(chatbot1.GetMessageChan())
+	assert.True(t, success, "Chatbot1 should receive the message of Alice")
+	assert.Equal(t, "Hello group!", string(msgc.Message), "Chatbot1 should receive the message of Alice")
+
+	_, err = chatbot1.ParseMessageWrapper(ctx, &pb.MessageWrapper{SenderID: alice.GetUserID(), RecipientID: groupId, MessageID: msgc.MessageID})
+	assert.ErrorIs(t, err, ErrReplayedMessage, "Chatbot1 should reject the group message delivered again")
+}
+
 func TestSendMessageToNonExistentUser(t *testing.T) {
 	// TODO
 }
@@ -1680,16 +3224,18 @@ func dialer() func(context.Context, string) (net.Conn, error) {
 
 // createClientSideUserWithRandomUserID create a client side user with a random user ID.
 func createClientSideUserWithRandomUserID(prefix string) *user.ClientSideUser {
+	ctx := context.Background()
 	userID := prefix + randomString(8)
 	//user, _ := user.NewClientSideUser(userID, "localhost:50051", true)
-	return user.NewClientSideUserBufconn(userID, dialer(), true)
+	return user.NewClientSideUserBufconn(ctx, userID, dialer(), true)
 }
 
 // createClientSideChatbotWithRandomUserID create a client side chatbot with a random user ID.
 func createClientSideChatbotWithRandomUserID(prefix string) *ClientSideChatbot {
+	ctx := context.Background()
 	chatbotID := prefix + "-" + randomString(8)
 	//chatbot, _ := NewClientSideChatbot(chatbotID, "localhost:50051", true)
-	chatbot := NewClientSideChatbotBufconn(chatbotID, dialer(), true)
+	chatbot := NewClientSideChatbotBufconn(ctx, chatbotID, dialer(), true)
 	return chatbot
 }
 
@@ -1727,6 +3273,47 @@ func timeOutReadFromUserMessageChannel(ch <-chan user.OutputMessage) (user.Outpu
 	}
 }
 
+// goroutines returns the stacks of the running goroutines by their ID.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		stacks[strings.Fields(stack)[1]] = stack
+	}
+	return stacks
+}
+
+// assertNoLeakedGoroutines fails the test if goroutines that were not running before are still running once they had
+// time to exit.
+func assertNoLeakedGoroutines(t *testing.T, before map[string]string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var leaked []string
+		for id, stack := range goroutines() {
+			if _, exists := before[id]; !exists {
+				leaked = append(leaked, stack)
+			}
+		}
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("%v goroutines leaked:\n\n%v", len(leaked), strings.Join(leaked, "\n\n"))
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
 func treekemNodeEqual(n1, n2 *treekem.Node) bool {
 	return string(n1.Public) == string(n2.Public) && string(n1.SignPublic) == string(n2.SignPublic)
 }
@@ -1768,3 +3355,59 @@ func multiTreeKemEqual(mt1, mt2 *treekem.MultiTreeKEM) bool {
 func multiTreeKemExternalEqual(mt *treekem.MultiTreeKEM, mtRootID string, mte *treekem.MultiTreeKEMExternal) bool {
 	return bytes.Equal(mte.GetRootPublic(), mt.GetRootPublic(mtRootID))
 }
+
+// assertSameStructure asserts that two messages only differ in their contents: the same fields are set, maps have the
+// same keys, lists the same lengths, and bytes and strings the same lengths. Scalars may differ.
+func assertSameStructure(t *testing.T, expected protoreflect.Message, actual protoreflect.Message, path string) {
+	t.Helper()
+	fields := expected.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := path + "." + string(field.Name())
+		if !assert.Equal(t, expected.Has(field), actual.Has(field), "%v should be set in both messages or in neither", name) || !expected.Has(field) {
+			continue
+		}
+
+		switch {
+		case field.IsMap():
+			expectedMap, actualMap := expected.Get(field).Map(), actual.Get(field).Map()
+			var expectedKeys, actualKeys []string
+			expectedMap.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				expectedKeys = append(expectedKeys, key.String())
+				return true
+			})
+			actualMap.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+				actualKeys = append(actualKeys, key.String())
+				return true
+			})
+			assert.ElementsMatch(t, expectedKeys, actualKeys, "%v should have the same keys", name)
+			expectedMap.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				if actualMap.Has(key) {
+					assertSameValueStructure(t, field.MapValue(), value, actualMap.Get(key), name+"["+key.String()+"]")
+				}
+				return true
+			})
+		case field.IsList():
+			expectedList, actualList := expected.Get(field).List(), actual.Get(field).List()
+			if assert.Equal(t, expectedList.Len(), actualList.Len(), "%v should have the same length", name) {
+				for j := 0; j < expectedList.Len(); j++ {
+					assertSameValueStructure(t, field, expectedList.Get(j), actualList.Get(j), fmt.Sprintf("%v[%v]", name, j))
+				}
+			}
+		default:
+			assertSameValueStructure(t, field, expected.Get(field), actual.Get(field), name)
+		}
+	}
+}
+
+func assertSameValueStructure(t *testing.T, field protoreflect.FieldDescriptor, expected protoreflect.Value, actual protoreflect.Value, name string) {
+	t.Helper()
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		assertSameStructure(t, expected.Message(), actual.Message(), name)
+	case protoreflect.BytesKind:
+		assert.Equal(t, len(expected.Bytes()), len(actual.Bytes()), "%v should have the same length", name)
+	case protoreflect.StringKind:
+		assert.Equal(t, len(expected.String()), len(actual.String()), "%v should have the same length", name)
+	}
+}
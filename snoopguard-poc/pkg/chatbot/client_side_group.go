@@ -4,6 +4,8 @@ import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
+	"context"
+	"errors"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -12,12 +14,19 @@ import (
 /*
 PostCreateClientSideGroup is called when a new client-side group is created.
 */
-func (csc *ClientSideChatbot) PostCreateClientSideGroup(groupID string, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte) (*client.ClientSideGroupSessionDriver, error) {
+func (csc *ClientSideChatbot) PostCreateClientSideGroup(groupID string, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, cipherSuite treekem.CipherSuiteID, hybridKEM bool) (*client.ClientSideGroupSessionDriver, error) {
 	sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Set up the cipher suite of the group
+	err = sessionDriver.SetCipherSuite(cipherSuite)
+	if err != nil {
+		logger.Error("Failed to set cipher suite: ", err)
+		return nil, err
+	}
+
 	// Set up MultiTreeKEMExternal
 	err = sessionDriver.InitiateMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf)
 	if err != nil {
@@ -25,10 +34,35 @@ func (csc *ClientSideChatbot) PostCreateClientSideGroup(groupID string, treekemR
 		return nil, err
 	}
 
+	// Use the hybrid KEM with the tree if the group opted in to it for this chatbot
+	err = sessionDriver.GetMultiTreeKEMExternal().SetHybridKEM(hybridKEM)
+	if err != nil {
+		logger.Error("Failed to set up hybrid KEM: ", err)
+		return nil, err
+	}
+
 	return sessionDriver, nil
 }
 
-func (csc *ClientSideChatbot) SendClientSideGroupMessage(groupID string, message []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendClientSideGroupMessage(ctx context.Context, groupID string, message []byte, messageType pb.MessageType) error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	err := csc.sendClientSideGroupMessage(ctx, groupID, message, messageType)
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	return err
+}
+
+/*
+sendClientSideGroupMessage sends a message to a client-side group while the mutex is held.
+*/
+func (csc *ClientSideChatbot) sendClientSideGroupMessage(ctx context.Context, groupID string, message []byte, messageType pb.MessageType) error {
+	if err := csc.checkMayPost(groupID); err != nil {
+		logger.Error(err)
+		return err
+	}
 	groupDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
 	if err != nil {
 		logger.Error(err)
@@ -42,6 +76,8 @@ func (csc *ClientSideChatbot) SendClientSideGroupMessage(groupID string, message
 		return err
 	}
 
+	// Every copy carries the same MessageID, encrypted along with it.
+	messageID := client.NewMessageID()
 	messages := make(map[string]*pb.MessageWrapper)
 	receivingIDs := groupDriver.GetGroupParticipants()
 	for _, participantID := range receivingIDs {
@@ -61,6 +97,7 @@ func (csc *ClientSideChatbot) SendClientSideGroupMessage(groupID string, message
 		packedMessage := &pb.Message{
 			Message:     packedCSGMsgMarshalled,
 			MessageType: pb.MessageType_CLIENT_SIDE_GROUP_MESSAGE,
+			MessageID:   messageID,
 		}
 
 		packedMessageMarshal, err := proto.Marshal(packedMessage)
@@ -73,7 +110,7 @@ func (csc *ClientSideChatbot) SendClientSideGroupMessage(groupID string, message
 		sessionDriver, err := csc.Client.GetSessionDriver(protocol.NewSignalAddress(participantID, 1))
 		if err != nil {
 			logger.Info("Sending message to user without session ", participantID)
-			sessionDriver, err = csc.CreateIndividualSession(protocol.NewSignalAddress(participantID, 1))
+			sessionDriver, err = csc.CreateIndividualSession(ctx, protocol.NewSignalAddress(participantID, 1))
 			if err != nil {
 				logger.Error("Failed to create session with ", participantID, ": ", err)
 				return err
@@ -90,35 +127,79 @@ func (csc *ClientSideChatbot) SendClientSideGroupMessage(groupID string, message
 				ChatbotUpdate:   treekem.ECKEMCipherTextPbConvert(&chatbotUpdate),
 				NewCbPubKey:     newCbPubKey,
 				NewCbSignPubKey: newCbSignPubKey,
+				Epoch:           groupDriver.GetMultiTreeKEMExternal().GetEpoch(),
+				TranscriptHash:  groupDriver.GetMultiTreeKEMExternal().GetTranscriptHash(),
 			},
+			MessageID: messageID,
 		}
 
 		messages[participantID] = messageWrapper
 	}
 
-	return csc.Client.SendClientSideGroupMessage(groupID, messages)
+	return csc.Client.SendClientSideGroupMessage(ctx, groupID, messages)
 }
 
-func (csc *ClientSideChatbot) HandleClientSideGroupMessage(message []byte, senderID string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack) ([]byte, pb.MessageType) {
-	groupId, groupMessage, groupMessageType := csc.Client.ParseClientSideGroupMessage(message, senderID)
-
-	// Handle treekem key update
-	sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupId)
+/*
+HandleClientSideGroupMessage handles the incoming client-side group message and the key update that comes with it. The
+message is returned along with the error if only its key update failed, unless the key update was not authenticated.
+*/
+func (csc *ClientSideChatbot) HandleClientSideGroupMessage(ctx context.Context, message []byte, senderID string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack) (string, []byte, pb.MessageType, error) {
+	groupId, groupMessage, groupMessageType, err := csc.Client.ParseClientSideGroupMessage(message, senderID)
 	if err != nil {
-		logger.Error("Not in the group: ", groupId)
+		return groupId, nil, -1, err
 	}
 
 	if treeKEMKeyUpdatePack != nil {
 		logger.Info("Received TreeKEM update from ", senderID, " for client-side group ", groupId)
 
-		updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
-		err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, treeKEMKeyUpdatePack.GetNewRootPubKey(), treeKEMKeyUpdatePack.GetNewRootSignPubKey())
-		if err != nil {
-			logger.Error("UpdateTreeKEMUserKey failed: ", groupId, err)
+		err := csc.handleClientSideTreeKEMKeyUpdate(ctx, groupId, senderID, treeKEMKeyUpdatePack)
+		if errors.Is(err, treekem.ErrUnauthenticatedKeyUpdate) {
+			return groupId, nil, -1, err
 		}
+		if err != nil && !errors.Is(err, errKeyUpdateBuffered) {
+			return groupId, groupMessage, groupMessageType, err
+		}
+	}
+
+	return groupId, groupMessage, groupMessageType, nil
+}
+
+/*
+handleClientSideTreeKEMKeyUpdate handles the key update of the root the chatbot shares with a client-side group. The
+message carrying it was already decrypted, so an update ahead of the root is buffered on its own.
+*/
+func (csc *ClientSideChatbot) handleClientSideTreeKEMKeyUpdate(ctx context.Context, groupId string, senderID string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack) error {
+	sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupId)
+	if err != nil {
+		logger.Error("Not in the group: ", groupId)
+		return err
 	}
 
-	return groupMessage, groupMessageType
+	externalRoot := sessionDriver.GetMultiTreeKEMExternal()
+	pendingMessageWrapper := &pb.MessageWrapper{
+		SenderID:             senderID,
+		RecipientID:          groupId,
+		TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+	}
+	err = csc.checkTreeKEMKeyUpdateEpoch(ctx, groupId, pb.GroupType_CLIENT_SIDE, externalRoot, treeKEMKeyUpdatePack, pendingMessageWrapper)
+	if err != nil {
+		return err
+	}
+
+	// Chatbots in client-side groups never have IGA, so the signature of the sender is always required.
+	err = csc.verifyTreeKEMKeyUpdatePack(groupId, senderID, treeKEMKeyUpdatePack, externalRoot.GetUpdateMACKey(), true)
+	if err != nil {
+		return err
+	}
+
+	updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
+	err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, newRootPubKeyOf(treeKEMKeyUpdatePack, externalRoot.IsHybridKEM()), treeKEMKeyUpdatePack.GetNewRootSignPubKey(),
+		treeKEMKeyUpdatePack.GetChatbotEpochs()[csc.chatbotID], treeKEMKeyUpdatePack.GetChatbotTranscriptHashes()[csc.chatbotID])
+	err = csc.finishTreeKEMKeyUpdate(ctx, groupId, pb.GroupType_CLIENT_SIDE, externalRoot, err)
+	if err != nil {
+		logger.Error("UpdateTreeKEMUserKey failed: ", groupId, err)
+	}
+	return err
 }
 
 //func (csc *ClientSideChatbot) HandleClientSideGroupIGAMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
@@ -1,7 +1,9 @@
 package chatbot
 
 import (
+	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
 	"context"
 	"fmt"
 	"github.com/golang/protobuf/proto"
@@ -10,10 +12,11 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"io"
+	"time"
 )
 
 /*
-SetupChatServiceClient set up the chatServiceClient and chatServiceClientCtx into the ClientSideChatbot.
+SetupChatServiceClient set up the chatServiceClient into the ClientSideChatbot.
 Note that this function is not called when the ClientSideChatbot is created. It needs to be called explicitly.
 The close() will be returned. One should defer the close() after calling this function.
 */
@@ -25,35 +28,37 @@ func (csc *ClientSideChatbot) SetupChatServiceClient(addr string) func() error {
 	}
 
 	csc.chatServiceClient = pb.NewChatServiceClient(conn)
-	csc.chatServiceClientCtx = context.Background()
+	csc.chatServiceConn = conn
 
 	return conn.Close
 }
 
 /*
 SetupMessageStreamService set up the message stream service and return two channels, one for receiving messages, and one for receiving done signal.
+The stream is closed once the given context is done.
 */
-func (csc *ClientSideChatbot) SetupMessageStreamService() (chan *pb.MessageWrapper, chan bool) {
+func (csc *ClientSideChatbot) SetupMessageStreamService(ctx context.Context) (chan *pb.MessageWrapper, chan bool) {
 	// Set up a stream to the server.
-	messageStream, err := csc.chatServiceClient.MessageStream(csc.chatServiceClientCtx, &pb.MessageStreamInit{UserID: csc.chatbotID})
+	messageStream, err := csc.chatServiceClient.MessageStream(ctx, &pb.MessageStreamInit{UserID: csc.chatbotID})
 
+	messageStreamDone := make(chan bool, 1)
+	messageStreamChan := make(chan *pb.MessageWrapper)
 	if err != nil {
 		logger.Error("MessageStream failed: ", err)
+		messageStreamDone <- true
+		return messageStreamChan, messageStreamDone
 	}
-
-	messageStreamDone := make(chan bool)
-	messageStreamChan := make(chan *pb.MessageWrapper)
 	go func() {
+		// Everything received is handed over before the done signal, so that nothing is lost when the stream is closed
+		defer func() { messageStreamDone <- true }()
 		for {
 			resp, err := messageStream.Recv()
 			if err == io.EOF {
 				logger.Info("MessageStream EOF received")
-				messageStreamDone <- true
 				return
 			}
 			if err != nil {
 				//logger.Error("MessageStream cannot receive ", err)
-				messageStreamDone <- true
 				return
 			}
 			logger.Info("MessageStream received: ", resp)
@@ -66,28 +71,30 @@ func (csc *ClientSideChatbot) SetupMessageStreamService() (chan *pb.MessageWrapp
 
 /*
 SetupServerEventStreamService set up the server event stream service and return two channels, one for receiving messages, and one for receiving done signal.
+The stream is closed once the given context is done.
 */
-func (csc *ClientSideChatbot) SetupServerEventStreamService() (chan *pb.ServerEvent, chan bool) {
+func (csc *ClientSideChatbot) SetupServerEventStreamService(ctx context.Context) (chan *pb.ServerEvent, chan bool) {
 	// Set up a stream to the server.
-	serverEventStream, err := csc.chatServiceClient.ServerEventStream(csc.chatServiceClientCtx, &pb.ServerEventStreamInit{UserID: csc.chatbotID})
+	serverEventStream, err := csc.chatServiceClient.ServerEventStream(ctx, &pb.ServerEventStreamInit{UserID: csc.chatbotID})
 
+	serverEventStreamDone := make(chan bool, 1)
+	serverEventStreamChan := make(chan *pb.ServerEvent)
 	if err != nil {
 		logger.Error("ServerEventStream failed: ", err)
+		serverEventStreamDone <- true
+		return serverEventStreamChan, serverEventStreamDone
 	}
-
-	serverEventStreamDone := make(chan bool)
-	serverEventStreamChan := make(chan *pb.ServerEvent)
 	go func() {
+		// Everything received is handed over before the done signal, so that nothing is lost when the stream is closed
+		defer func() { serverEventStreamDone <- true }()
 		for {
 			resp, err := serverEventStream.Recv()
 			if err == io.EOF {
 				logger.Info("ServerEventStream EOF received")
-				serverEventStreamDone <- true
 				return
 			}
 			if err != nil {
 				//logger.Error("ServerEventStream cannot receive ", err)
-				serverEventStreamDone <- true
 				return
 			}
 			logger.Info("ServerEventStream received: ", resp)
@@ -98,54 +105,227 @@ func (csc *ClientSideChatbot) SetupServerEventStreamService() (chan *pb.ServerEv
 	return serverEventStreamChan, serverEventStreamDone
 }
 
+// streamCloseTimeout is how long deactivation waits for the server to end the streams before cancelling them.
+const streamCloseTimeout = 5 * time.Second
+
+// handleTimeout bounds the requests sent to the server while handling a single message or server event.
+const handleTimeout = 30 * time.Second
+
+/*
+closeStreams asks the server to end the streams once it sent what it already took off the queues.
+*/
+func (csc *ClientSideChatbot) closeStreams(ctx context.Context) bool {
+	resp, err := csc.chatServiceClient.CloseStreams(ctx, &pb.CloseStreamsRequest{UserID: csc.chatbotID})
+	if err != nil {
+		logger.Error("Failed to close streams: ", err)
+		return false
+	}
+	if !resp.GetSuccess() {
+		logger.Error("Failed to close streams: ", resp.GetErrorMessage())
+		return false
+	}
+	return true
+}
+
 /*
-ListenToStreams for messages and server events. This should be executed in a goroutine.
+startListening listens to the streams in a goroutine until the chatbot is deactivated.
 */
-func (csc *ClientSideChatbot) ListenToStreams() {
-	messageStreamChan, messageStreamDone := csc.SetupMessageStreamService()
-	serverEventStreamChan, serverEventStreamDone := csc.SetupServerEventStreamService()
+func (csc *ClientSideChatbot) startListening() {
+	csc.lifecycle, csc.cancelLifecycle = context.WithCancel(context.Background())
+	csc.listenerDone = make(chan struct{})
+	go func() {
+		defer close(csc.listenerDone)
+		csc.ListenToStreams(csc.lifecycle)
+	}()
+}
+
+/*
+goBackground runs f in a goroutine, for work that outlives the handling of the current message or event. Its context is
+bounded by handleTimeout and cancelled once the chatbot is deactivated, which waits for it to return.
+*/
+func (csc *ClientSideChatbot) goBackground(f func(ctx context.Context)) {
+	lifecycle := csc.lifecycle
+	if lifecycle == nil {
+		// The chatbot is not listening to the streams, so it is never deactivated.
+		lifecycle = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(lifecycle, handleTimeout)
+	csc.background.Add(1)
+	go func() {
+		defer csc.background.Done()
+		defer cancel()
+		f(ctx)
+	}()
+}
+
+/*
+ListenToStreams for messages and server events until the chatbot is deactivated or ctx is done. This should be executed
+in a goroutine.
+*/
+func (csc *ClientSideChatbot) ListenToStreams(ctx context.Context) {
+	// The streams are closed on deactivation, so that the server keeps the messages for the next session
+	streamCtx, cancelStreams := context.WithCancel(ctx)
+	defer cancelStreams()
+	messageStreamChan, messageStreamDone := csc.SetupMessageStreamService(streamCtx)
+	serverEventStreamChan, serverEventStreamDone := csc.SetupServerEventStreamService(streamCtx)
+
+	// drainStreams handles what the streams deliver until both end. They are cancelled once closeCtx is done or the
+	// server does not end them in time.
+	drainStreams := func(closeCtx context.Context) {
+		closeTimeout := time.After(streamCloseTimeout)
+		closeDone := closeCtx.Done()
+		for messageStreamDone != nil || serverEventStreamDone != nil {
+			select {
+			case <-closeTimeout:
+				cancelStreams()
+			case <-closeDone:
+				cancelStreams()
+				closeDone = nil
+			case messageData := <-messageStreamChan:
+				csc.handleStreamMessage(ctx, messageData)
+			case eventData := <-serverEventStreamChan:
+				csc.handleStreamEvent(ctx, eventData)
+			case <-messageStreamDone:
+				messageStreamChan, messageStreamDone = nil, nil
+			case <-serverEventStreamDone:
+				serverEventStreamChan, serverEventStreamDone = nil, nil
+			}
+		}
+		if err := csc.Persist(); err != nil {
+			logger.Error("Failed to persist state: ", err)
+		}
+	}
 
 	for {
 		select {
 		case messageData := <-messageStreamChan:
-			output, messageType := csc.ParseMessageWrapper(messageData)
-			if output != nil {
-				csc.messageChan <- OutputMessage{Message: output, MessageType: messageType}
-			}
+			csc.handleStreamMessage(ctx, messageData)
 		case eventData := <-serverEventStreamChan:
-			output, eventType := csc.ParseServerEvent(eventData)
-			if output != nil {
-				csc.messageChan <- OutputMessage{Message: output, EventType: eventType}
-			}
+			csc.handleStreamEvent(ctx, eventData)
 		case <-messageStreamDone:
 			logger.Info("messageStreamDone received")
+			messageStreamChan, messageStreamDone = nil, nil
 		case <-serverEventStreamDone:
 			logger.Info("eventStreamDone received")
-		case <-csc.deactivateChan:
+			serverEventStreamChan, serverEventStreamDone = nil, nil
+		case <-ctx.Done():
+			logger.Info("Stopped listening to streams: ", ctx.Err())
+			drainStreams(ctx)
+			return
+		case deactivateCtx := <-csc.deactivateChan:
 			logger.Info("Deactivating chatbot")
+			// Ask the server to end the streams and handle what it sent up to their end, as the server considers it
+			// delivered.
+			if !csc.closeStreams(deactivateCtx) {
+				cancelStreams()
+			}
+			drainStreams(deactivateCtx)
+			// The chatbot stops listening even if nobody reads the full message channel
+			select {
+			case csc.messageChan <- OutputMessage{Message: []byte("Deactivate")}:
+			default:
+				logger.Warning("Dropped the deactivate message as the message channel is full")
+			}
 			return
 		}
 	}
 }
 
 /*
-ParseMessageWrapper parses the given messageWrapper and handles it.
+handleStreamMessage handles a message received from the message stream and persists the resulting state.
+*/
+func (csc *ClientSideChatbot) handleStreamMessage(ctx context.Context, messageData *pb.MessageWrapper) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	csc.mutex.Lock()
+	output, err := csc.ParseMessageWrapper(ctx, messageData)
+	if err != nil {
+		client.ReportError(csc.errorChan, &HandlingError{
+			GroupID:   messageData.GetRecipientID(),
+			SenderID:  messageData.GetSenderID(),
+			MessageID: messageData.GetMessageID(),
+			Err:       err,
+		})
+	}
+	var outputs []OutputMessage
+	if output != nil {
+		outputs = append(outputs, *output)
+	}
+	outputs = append(outputs, csc.handleReadyKeyUpdates(ctx)...)
+	if messageData.GetKeyUpdateSeq() > 0 {
+		csc.Client.GetKeyUpdateSequence().Advance(messageData.GetRecipientID(), messageData.GetKeyUpdateSeq())
+	}
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	csc.mutex.Unlock()
+
+	// The messages are output once the state they leave is persisted, so that a chatbot killed after outputting them
+	// does not restart from before them.
+	for _, output := range outputs {
+		csc.messageChan <- output
+	}
+}
+
+/*
+handleStreamEvent handles a server event received from the server event stream and persists the resulting state.
+*/
+func (csc *ClientSideChatbot) handleStreamEvent(ctx context.Context, eventData *pb.ServerEvent) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	csc.mutex.Lock()
+	output, err := csc.ParseServerEvent(ctx, eventData)
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	csc.mutex.Unlock()
+	if err != nil {
+		handlingError := &HandlingError{Event: eventData, Err: err}
+		if output != nil {
+			handlingError.GroupID, handlingError.SenderID = output.GroupID, output.SenderID
+		}
+		client.ReportError(csc.errorChan, handlingError)
+	}
+	if output != nil {
+		csc.messageChan <- *output
+	}
+}
+
+/*
+ParseMessageWrapper parses the given messageWrapper and handles it. The output is returned along with the error if only
+the key update of the message failed. A message that was already received is rejected with ErrReplayedMessage, and one
+not carrying the MessageID it was encrypted with is rejected with ErrInvalidMessageID.
 */
-func (csc *ClientSideChatbot) ParseMessageWrapper(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csc *ClientSideChatbot) ParseMessageWrapper(ctx context.Context, messageWrapper *pb.MessageWrapper) (*OutputMessage, error) {
+	if err := csc.Client.GetReplayWindow().Check(messageWrapper); err != nil {
+		logger.Warning("Dropped message: ", err)
+		return nil, err
+	}
+	return csc.parseMessageWrapper(ctx, messageWrapper)
+}
+
+/*
+parseMessageWrapper parses the given messageWrapper and handles it, without checking whether it was already received.
+*/
+func (csc *ClientSideChatbot) parseMessageWrapper(ctx context.Context, messageWrapper *pb.MessageWrapper) (*OutputMessage, error) {
 	// If recipient ID is not user ID, it should be the server-side group ID or MLS group ID
 	if messageWrapper.RecipientID != csc.chatbotID {
 		// check if this is a server-side group
 		if _, err := csc.Client.GetServerSideGroupSessionDriver(messageWrapper.RecipientID); err == nil {
-			return csc.HandleServerSideGroupMessage(messageWrapper)
+			message, messageType, err := csc.HandleServerSideGroupMessage(ctx, messageWrapper)
+			return newMessageOutput(messageWrapper, messageWrapper.RecipientID, message, messageType), err
 		}
 
 		// check if this is a MLS group
 		if _, err := csc.Client.GetMlsGroupSessionDriver(messageWrapper.RecipientID); err == nil {
-			return csc.HandleMlsGroupMessage(messageWrapper)
+			message, messageType, err := csc.HandleMlsGroupMessage(ctx, messageWrapper)
+			return newMessageOutput(messageWrapper, messageWrapper.RecipientID, message, messageType), err
 		}
 
 		logger.Error("Received message with unknown recipient ID: ", messageWrapper.RecipientID)
-		return nil, -1
+		return nil, fmt.Errorf("%w: unknown recipient %v", ErrNotInGroup, messageWrapper.RecipientID)
 	}
 
 	// If is individual message
@@ -158,73 +338,80 @@ func (csc *ClientSideChatbot) ParseMessageWrapper(messageWrapper *pb.MessageWrap
 		sessionDriver, err := csc.Client.GetSessionDriver(protocol.NewSignalAddress(messageWrapper.SenderID, 1))
 		if err != nil {
 			logger.Debug("Received message from user without session ", messageWrapper.SenderID)
-			prekeyBundle, err := csc.Client.GetOthersPreKeyBundle(messageWrapper.SenderID)
+			prekeyBundle, err := csc.Client.GetOthersPreKeyBundle(ctx, messageWrapper.SenderID)
 			if err != nil {
-				panic("")
+				return nil, fmt.Errorf("%w with %v: %w", ErrNoSession, messageWrapper.SenderID, err)
 			}
 			sessionDriver, err = csc.Client.CreateSessionAndDriver(protocol.NewSignalAddress(messageWrapper.SenderID, 1), prekeyBundle)
 			if err != nil {
-				panic("")
+				return nil, fmt.Errorf("%w with %v: %w", ErrNoSession, messageWrapper.SenderID, err)
 			}
 		}
 
-		message, messageType, err := sessionDriver.DecryptMessage(messageWrapper.SenderID, messageWrapper.RecipientID, messageWrapper.EncryptedMessage, messageWrapper.HasPreKey)
+		message, messageType, err := sessionDriver.DecryptMessage(messageWrapper.SenderID, messageWrapper.RecipientID, messageWrapper.GetMessageID(), messageWrapper.EncryptedMessage, messageWrapper.HasPreKey)
 		if err != nil {
 			logger.Error("Error decrypting Message: ", err)
-			return nil, -1
+			return nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 		}
 
 		switch messageType {
 		case pb.MessageType_TEXT_MESSAGE:
 			logger.Info(fmt.Sprintf("Received text message from %v: %v", messageWrapper.SenderID, string(message)))
-			return message, messageType
+			return newMessageOutput(messageWrapper, "", message, messageType), nil
 
 		case pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE:
 			groupID, bounceBack, err := csc.Client.ParseSenderKeyDistributionMessage(message, messageWrapper.SenderID)
 			if err != nil {
 				logger.Error("Failed to parse sender key message: ", err)
+				return nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 			}
 
 			if bounceBack {
-				err = csc.DistributeSelfSenderKeyToUserID(messageWrapper.SenderID, groupID, false)
+				err = csc.DistributeSelfSenderKeyToUserID(ctx, messageWrapper.SenderID, groupID, false)
 				if err != nil {
 					logger.Error("Failed to distribute self sender key to user: ", err)
 				}
 			}
-			return message, messageType
+			return newMessageOutput(messageWrapper, "", message, messageType), err
 
 		case pb.MessageType_CLIENT_SIDE_GROUP_MESSAGE:
-			return csc.HandleClientSideGroupMessage(message, messageWrapper.SenderID, messageWrapper.GetTreeKEMKeyUpdatePack())
+			groupID, groupMessage, groupMessageType, err := csc.HandleClientSideGroupMessage(ctx, message, messageWrapper.SenderID, messageWrapper.GetTreeKEMKeyUpdatePack())
+			return newMessageOutput(messageWrapper, groupID, groupMessage, groupMessageType), err
 		case pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE:
 			logger.Error("Received pseudonym registration message without IGA from ", messageWrapper.SenderID, "as a chatbot.")
-			return message, messageType
+			return newMessageOutput(messageWrapper, "", message, messageType), nil
+		case pb.MessageType_ROOT_RECOVERY:
+			rootRecovery, rootRecoveryType, err := csc.HandleRootRecovery(message, messageWrapper.SenderID)
+			return newMessageOutput(messageWrapper, "", rootRecovery, rootRecoveryType), err
 		}
 	}
-	return nil, -1
+	return nil, nil
 }
 
 /*
 ParseServerEventRaw parses the given serverEventRaw and handles it.
 */
-func (csc *ClientSideChatbot) ParseServerEventRaw(serverEventRaw []byte) {
+func (csc *ClientSideChatbot) ParseServerEventRaw(ctx context.Context, serverEventRaw []byte) error {
 	serverEvent := &pb.ServerEvent{}
 	err := proto.Unmarshal(serverEventRaw, serverEvent)
 	if err != nil {
 		logger.Error("ParseMessage failed: ", err)
-		return
+		return err
 	}
-	csc.ParseServerEvent(serverEvent)
+	_, err = csc.ParseServerEvent(ctx, serverEvent)
+	return err
 }
 
 /*
-ParseServerEvent parse the incoming server events.
+ParseServerEvent parse the incoming server events. The output is returned along with the error if the chatbot failed to
+apply the event.
 */
-func (csc *ClientSideChatbot) ParseServerEvent(serverEvent *pb.ServerEvent) ([]byte, pb.ServerEventType) {
+func (csc *ClientSideChatbot) ParseServerEvent(ctx context.Context, serverEvent *pb.ServerEvent) (*OutputMessage, error) {
 	logger.Info("Received server event: ", serverEvent.GetEventType(), serverEvent.String())
 
 	switch serverEvent.GetEventType() {
 	case pb.ServerEventType_GROUP_CHATBOT_INVITATION:
-		csc.JoinGroup(
+		err := csc.JoinGroup(
 			serverEvent.GetGroupChatbotInvitation().GetGroupID(),
 			serverEvent.GetGroupChatbotInvitation().GetGroupType(),
 			serverEvent.GetGroupChatbotInvitation().GetParticipantIDs(),
@@ -235,20 +422,23 @@ func (csc *ClientSideChatbot) ParseServerEvent(serverEvent *pb.ServerEvent) ([]b
 			serverEvent.GetGroupChatbotInvitation().GetChatbotInitLeaf(),
 			serverEvent.GetGroupChatbotInvitation().GetMlsWelcomeMessage(),
 			serverEvent.GetGroupChatbotInvitation().GetMlsKeyPackageID(),
+			treekem.CipherSuiteID(serverEvent.GetGroupChatbotInvitation().GetCipherSuite()),
+			serverEvent.GetGroupChatbotInvitation().GetHybridKEM(),
 		)
-		return []byte(serverEvent.GetGroupChatbotInvitation().GetGroupID()), pb.ServerEventType_GROUP_CHATBOT_INVITATION
+		csc.SetGroupScopes(serverEvent.GetGroupChatbotInvitation().GetGroupID(), serverEvent.GetGroupChatbotInvitation().GetScopes())
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_ADDITION:
-		csc.AddUserToGroup(
+		err := csc.AddUserToGroup(
 			serverEvent.GetGroupAddition().GetGroupID(),
 			serverEvent.GetGroupAddition().GetGroupType(),
 			serverEvent.GetGroupAddition().GetAddedID(),
 			serverEvent.GetGroupAddition().GetParticipantIDs(),
 			serverEvent.GetGroupAddition().GetMlsUserAdd(),
 			serverEvent.GetGroupAddition().GetMlsAddCommit())
-		return []byte(serverEvent.GetGroupAddition().GetGroupID()), pb.ServerEventType_GROUP_ADDITION
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_CHATBOT_ADDITION:
 		if !serverEvent.GetGroupChatbotAddition().GetIsIGA() {
-			csc.AddChatbotToGroup(
+			return nil, csc.AddChatbotToGroup(
 				serverEvent.GetGroupChatbotAddition().GetGroupID(),
 				serverEvent.GetGroupChatbotAddition().GetGroupType(),
 				serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(),
@@ -257,17 +447,32 @@ func (csc *ClientSideChatbot) ParseServerEvent(serverEvent *pb.ServerEvent) ([]b
 		}
 
 	case pb.ServerEventType_GROUP_REMOVAL:
-		csc.RemoveUserFromGroup(
+		// A removal that does not tell who was removed reaches a chatbot whose scopes hide membership changes, only for
+		// it to rotate its sender key, so it is not output.
+		if serverEvent.GetGroupRemoval().GetRemovedID() == "" {
+			return nil, csc.rotateSenderKeyAfterHiddenRemoval(ctx, serverEvent.GetGroupRemoval().GetGroupID(), serverEvent.GetGroupRemoval().GetParticipantIDs())
+		}
+		err := csc.RemoveUserFromGroup(
 			serverEvent.GetGroupRemoval().GetGroupID(),
 			serverEvent.GetGroupRemoval().GetGroupType(),
 			serverEvent.GetGroupRemoval().GetRemovedID(),
 			serverEvent.GetGroupRemoval().GetParticipantIDs(),
 			serverEvent.GetGroupRemoval().GetMlsRemove(),
 			serverEvent.GetGroupRemoval().GetMlsRemoveCommit())
-		return []byte(serverEvent.GetGroupRemoval().GetGroupID()), pb.ServerEventType_GROUP_REMOVAL
+		// The removed member knows the current sender key, so it is rotated.
+		if err == nil && serverEvent.GetGroupRemoval().GetGroupType() == pb.GroupType_SERVER_SIDE {
+			err = csc.RotateSelfSenderKey(ctx, serverEvent.GetGroupRemoval().GetGroupID())
+			if err != nil {
+				logger.Error("Failed to rotate sender key after removal: ", err)
+			}
+		}
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_CHATBOT_REMOVAL:
-		csc.LeaveGroup(serverEvent.GetGroupRemoval().GetGroupID(), serverEvent.GetGroupRemoval().GetGroupType())
-		return []byte(serverEvent.GetGroupRemoval().GetGroupID()), pb.ServerEventType_GROUP_CHATBOT_REMOVAL
+		csc.LeaveGroup(serverEvent.GetGroupChatbotRemoval().GetGroupID(), serverEvent.GetGroupChatbotRemoval().GetGroupType())
+		return client.NewEventOutput(serverEvent), nil
+	case pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE:
+		csc.SetGroupScopes(serverEvent.GetGroupChatbotScopeUpdate().GetGroupID(), serverEvent.GetGroupChatbotScopeUpdate().GetScopes())
+		return client.NewEventOutput(serverEvent), nil
 	}
-	return nil, -1
+	return nil, nil
 }
@@ -0,0 +1,20 @@
+package chatbot
+
+import (
+	"chatbot-poc-go/pkg/client"
+)
+
+// The errors the chatbot returns and reports on the error channel. See the client package for their meaning.
+var (
+	ErrNotInGroup       = client.ErrNotInGroup
+	ErrNoSession        = client.ErrNoSession
+	ErrDesync           = client.ErrDesync
+	ErrBadSignature     = client.ErrBadSignature
+	ErrUnknownPseudonym = client.ErrUnknownPseudonym
+	ErrUndecryptable    = client.ErrUndecryptable
+	ErrReplayedMessage  = client.ErrReplayedMessage
+	ErrInvalidMessageID = client.ErrInvalidMessageID
+)
+
+// HandlingError reports an incoming message or server event the chatbot failed to handle.
+type HandlingError = client.HandlingError
@@ -2,15 +2,17 @@ package chatbot
 
 import (
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"fmt"
 	syntax "github.com/cisco/go-tls-syntax"
 	"github.com/s3131212/go-mls"
 	"go.mau.fi/libsignal/logger"
 )
 
 /*
-JoinGroup joins a group, either server side or client side, and return the group id.
+JoinGroup joins a group, either server side or client side.
 */
-func (csc *ClientSideChatbot) JoinGroup(groupID string, groupType pb.GroupType, participantIDs []string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, welcomeMessageSerialized []byte, keyPackageId uint32) {
+func (csc *ClientSideChatbot) JoinGroup(groupID string, groupType pb.GroupType, participantIDs []string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, welcomeMessageSerialized []byte, keyPackageId uint32, cipherSuite treekem.CipherSuiteID, hybridKEM bool) error {
 	logger.Info("Joining group: ", groupID, " with type: ", groupType, " and participant IDs: ", participantIDs)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -18,37 +20,37 @@ func (csc *ClientSideChatbot) JoinGroup(groupID string, groupType pb.GroupType,
 		_, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		csc.Client.JoinGroup(groupID, groupType, participantIDs, nil)
 
-		_, err = csc.PostCreateServerSideGroup(groupID, isIGA, isPseudo, treekemRootPub, treekemRootSignPub, initLeaf)
+		_, err = csc.PostCreateServerSideGroup(groupID, isIGA, isPseudo, treekemRootPub, treekemRootSignPub, initLeaf, cipherSuite, hybridKEM)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	case pb.GroupType_CLIENT_SIDE:
 		// Check if already in the group
 		_, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		csc.Client.JoinGroup(groupID, groupType, participantIDs, nil)
 
-		_, err = csc.PostCreateClientSideGroup(groupID, treekemRootPub, treekemRootSignPub, initLeaf)
+		_, err = csc.PostCreateClientSideGroup(groupID, treekemRootPub, treekemRootSignPub, initLeaf, cipherSuite, hybridKEM)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	case pb.GroupType_MLS:
 		// Check if already in the group
 		_, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		// Deserialize Welcome
@@ -59,19 +61,19 @@ func (csc *ClientSideChatbot) JoinGroup(groupID string, groupType pb.GroupType,
 
 		csc.Client.JoinGroup(groupID, groupType, participantIDs, nil)
 
-		_, err = csc.PostCreateMlsGroup(groupID, isIGA, isPseudo, treekemRootPub, treekemRootSignPub, initLeaf, welcome, keyPackageId)
+		_, err = csc.PostCreateMlsGroup(groupID, isIGA, isPseudo, treekemRootPub, treekemRootSignPub, initLeaf, welcome, keyPackageId, cipherSuite)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	}
-
+	return nil
 }
 
 /*
 AddUserToGroup adds a user to the group.
 */
-func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupType, addedID string, participantIDs []string, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) {
+func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupType, addedID string, participantIDs []string, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) error {
 	logger.Info("Adding user: ", addedID, " to group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -79,7 +81,7 @@ func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -94,7 +96,7 @@ func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -105,7 +107,7 @@ func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -123,36 +125,37 @@ func (csc *ClientSideChatbot) AddUserToGroup(groupID string, groupType pb.GroupT
 		err = sessionDriver.AddUser(&mlsUserAdd, &mlsCommit)
 		if err != nil {
 			logger.Error("Failed to add user to MLS group: ", err)
-			return
+			return err
 		}
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
 	}
+	return nil
 }
 
 /*
 AddChatbotToGroup adds the chatbot to the group by calling AddUserToGroup
 */
-func (csc *ClientSideChatbot) AddChatbotToGroup(groupID string, groupType pb.GroupType, chatbotID string, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) {
+func (csc *ClientSideChatbot) AddChatbotToGroup(groupID string, groupType pb.GroupType, chatbotID string, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) error {
 	if groupType != pb.GroupType_MLS {
 		logger.Error("Chatbot can only be added to MLS group")
-		return
+		return fmt.Errorf("chatbot %v can only be added to MLS group, not %v", chatbotID, groupType)
 	}
 
 	// New participant IDs = participantIDs + chatbotID
 	sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 	if err != nil {
 		logger.Error("Not in the group: ", groupID)
-		return
+		return err
 	}
 
 	participantIDs := append(sessionDriver.GetGroupParticipants(), chatbotID)
-	csc.AddUserToGroup(groupID, groupType, chatbotID, participantIDs, mlsUserAddSerialized, mlsCommitSerialized)
+	return csc.AddUserToGroup(groupID, groupType, chatbotID, participantIDs, mlsUserAddSerialized, mlsCommitSerialized)
 }
 
 /*
 RemoveUserFromGroup removes user from the group's participant list.
 */
-func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.GroupType, removedID string, participantIDs []string, mlsRemoveSerialized []byte, mlsRemoveCommitSerialized []byte) {
+func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.GroupType, removedID string, participantIDs []string, mlsRemoveSerialized []byte, mlsRemoveCommitSerialized []byte) error {
 	logger.Info("Removing user: ", removedID, " from group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -160,7 +163,7 @@ func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.G
 		sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
@@ -172,7 +175,7 @@ func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.G
 		sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
@@ -184,7 +187,7 @@ func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.G
 		sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Deserialize MLSRemove and MLSRemoveCommit
@@ -200,10 +203,11 @@ func (csc *ClientSideChatbot) RemoveUserFromGroup(groupID string, groupType pb.G
 		err = sessionDriver.RemoveUser(&mlsRemove, &mlsRemoveCommit)
 		if err != nil {
 			logger.Error("Failed to remove user from MLS group: ", err)
-			return
+			return err
 		}
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
 	}
+	return nil
 }
 
 /*
@@ -213,3 +217,44 @@ func (csc *ClientSideChatbot) LeaveGroup(groupID string, groupType pb.GroupType)
 	logger.Info("Leaving group: ", groupID)
 	csc.Client.LeaveGroup(groupID, groupType)
 }
+
+/*
+newRootPubKeyOf returns the key of the new TreeKEM root in the update pack that the chatbot encrypts its updates to,
+which is the hybrid one if the chatbot uses the hybrid KEM.
+*/
+func newRootPubKeyOf(treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, hybridKEM bool) []byte {
+	if hybridKEM {
+		return treeKEMKeyUpdatePack.GetNewRootHybridPubKey()
+	}
+	return treeKEMKeyUpdatePack.GetNewRootPubKey()
+}
+
+/*
+verifyTreeKEMKeyUpdatePack checks that the key update pack comes from a member of the group, so that the server cannot
+inject a root of its own. The MAC keyed by the root the chatbot shares with the group before the update is always
+required. Chatbots without IGA know the sender anyway and additionally require the signature of the sender.
+macKey has to be taken before the update is handled.
+*/
+func (csc *ClientSideChatbot) verifyTreeKEMKeyUpdatePack(groupID string, senderID string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, macKey []byte, requireSignature bool) error {
+	tbs, err := treekem.KeyUpdatePackTBS(groupID, treeKEMKeyUpdatePack)
+	if err != nil {
+		logger.Error("Failed to encode key update pack: ", err)
+		return err
+	}
+
+	err = treekem.VerifyKeyUpdateMAC(macKey, tbs, treeKEMKeyUpdatePack.GetChatbotMACs()[csc.chatbotID])
+	if err != nil {
+		logger.Error("Rejected TreeKEM update with invalid MAC in group ", groupID)
+		return err
+	}
+
+	if requireSignature {
+		err = csc.Client.VerifyTreeKEMKeyUpdatePackSignature(groupID, senderID, treeKEMKeyUpdatePack)
+		if err != nil {
+			logger.Error("Rejected TreeKEM update from ", senderID, " in group ", groupID, ": ", err)
+			return err
+		}
+	}
+
+	return nil
+}
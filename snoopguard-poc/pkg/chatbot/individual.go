@@ -3,6 +3,7 @@ package chatbot
 import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -11,8 +12,8 @@ import (
 /*
 CreateIndividualSession creates a session with a recipient.
 */
-func (csc *ClientSideChatbot) CreateIndividualSession(recipientAddress *protocol.SignalAddress) (*client.ClientSessionDriver, error) {
-	preKeyBundle, err := csc.Client.GetOthersPreKeyBundle(recipientAddress.Name())
+func (csc *ClientSideChatbot) CreateIndividualSession(ctx context.Context, recipientAddress *protocol.SignalAddress) (*client.ClientSessionDriver, error) {
+	preKeyBundle, err := csc.Client.GetOthersPreKeyBundle(ctx, recipientAddress.Name())
 	if err != nil {
 		return nil, err
 	}
@@ -23,11 +24,27 @@ func (csc *ClientSideChatbot) CreateIndividualSession(recipientAddress *protocol
 /*
 SendIndividualMessage send a message to a recipient given that the client session is already established.
 */
-func (csc *ClientSideChatbot) SendIndividualMessage(recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendIndividualMessage(ctx context.Context, recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	err := csc.sendIndividualMessage(ctx, recipientAddress, message, messageType)
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	return err
+}
+
+/*
+sendIndividualMessage send a message to a recipient while the mutex is held.
+*/
+func (csc *ClientSideChatbot) sendIndividualMessage(ctx context.Context, recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) error {
 	logger.Debug("Sending individual message: ", string(message))
+	messageID := client.NewMessageID()
 	packedMessage := &pb.Message{
 		Message:     message,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 
 	packedMessageMarshal, err := proto.Marshal(packedMessage)
@@ -37,7 +54,7 @@ func (csc *ClientSideChatbot) SendIndividualMessage(recipientAddress *protocol.S
 	}
 	sessionDriver, err := csc.Client.GetSessionDriver(recipientAddress)
 	if err != nil {
-		sessionDriver, err = csc.CreateIndividualSession(recipientAddress)
+		sessionDriver, err = csc.CreateIndividualSession(ctx, recipientAddress)
 		if err != nil {
 			logger.Error("Error creating session with ", recipientAddress.Name(), ": ", err)
 			return err
@@ -49,9 +66,10 @@ func (csc *ClientSideChatbot) SendIndividualMessage(recipientAddress *protocol.S
 		RecipientID:      recipientAddress.Name(),
 		EncryptedMessage: encryptedMessage.Serialize(),
 		HasPreKey:        encryptedMessage.Type() == protocol.PREKEY_TYPE,
+		MessageID:        messageID,
 	}
 
 	logger.Debug("Sending message to server: ", packedMessageWrapper.String())
 
-	return csc.Client.SendIndividualMessage(recipientAddress, packedMessageWrapper)
+	return csc.Client.SendIndividualMessage(ctx, recipientAddress, packedMessageWrapper)
 }
@@ -0,0 +1,37 @@
+package chatbot
+
+import (
+	"chatbot-poc-go/pkg/client"
+	"context"
+	"errors"
+	"go.mau.fi/libsignal/logger"
+)
+
+/*
+sendGroupMessageWithKeyUpdate issues and sends a group message that carries a key update, issuing it again after every
+key update conflict as client.KeyUpdateSequence.RetryConflicts describes.
+*/
+func (csc *ClientSideChatbot) sendGroupMessageWithKeyUpdate(ctx context.Context, groupID string, snapshot func() func(), send func() error) error {
+	return csc.Client.GetKeyUpdateSequence().RetryConflicts(ctx, groupID, func() error {
+		return csc.sendGroupMessageOrRollback(snapshot, send)
+	})
+}
+
+/*
+sendGroupMessageOrRollback issues and sends a group message while no incoming message is handled, and rolls the group
+state back to the given snapshot if the server rejected its key update. The resulting state is persisted.
+*/
+func (csc *ClientSideChatbot) sendGroupMessageOrRollback(snapshot func() func(), send func() error) error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	rollback := snapshot()
+	err := send()
+	if errors.Is(err, client.ErrKeyUpdateConflict) {
+		rollback()
+	}
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	return err
+}
@@ -5,6 +5,8 @@ import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
 	"chatbot-poc-go/pkg/util"
+	"context"
+	"errors"
 	"fmt"
 	syntax "github.com/cisco/go-tls-syntax"
 	"github.com/s3131212/go-mls"
@@ -15,12 +17,19 @@ import (
 /*
 PostCreateMlsGroup is called when a new MLS group is created.
 */
-func (csc *ClientSideChatbot) PostCreateMlsGroup(groupID string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, welcome mls.Welcome, keyPackageId uint32) (*client.MlsGroupSessionDriver, error) {
+func (csc *ClientSideChatbot) PostCreateMlsGroup(groupID string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, welcome mls.Welcome, keyPackageId uint32, cipherSuite treekem.CipherSuiteID) (*client.MlsGroupSessionDriver, error) {
 	sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Set up the cipher suite of the group
+	err = sessionDriver.SetCipherSuite(cipherSuite)
+	if err != nil {
+		logger.Error("Failed to set cipher suite: ", err)
+		return nil, err
+	}
+
 	if !isIGA {
 		// If the welcome message is not set, set up the group state from empty
 		if welcome.Secrets == nil {
@@ -47,13 +56,13 @@ func (csc *ClientSideChatbot) PostCreateMlsGroup(groupID string, isIGA bool, isP
 /*
 HandleMlsGroupMessage handles the incoming MLS group message.
 */
-func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csc *ClientSideChatbot) HandleMlsGroupMessage(ctx context.Context, messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType, error) {
 	groupId, senderId := messageWrapper.RecipientID, messageWrapper.SenderID
 
 	sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupId)
 	if err != nil {
 		logger.Error("Received message from MlS group without session ", groupId)
-		return nil, -1
+		return nil, -1, err
 	}
 
 	// Update CMRT
@@ -62,10 +71,32 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 		if treeKEMKeyUpdatePack != nil && treeKEMKeyUpdatePack.GetNewRootPubKey() != nil {
 			logger.Info("Chatbot Received TreeKEM update from ", messageWrapper.SenderID, " for MLS group ", messageWrapper.RecipientID)
 
-			updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
-			err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, treeKEMKeyUpdatePack.GetNewRootPubKey(), treeKEMKeyUpdatePack.GetNewRootSignPubKey())
+			externalRoot := sessionDriver.GetMlsMultiTreeExternal()
+			err = csc.checkTreeKEMKeyUpdateEpoch(ctx, groupId, pb.GroupType_MLS, externalRoot, treeKEMKeyUpdatePack, messageWrapper)
+			if errors.Is(err, errKeyUpdateBuffered) {
+				return nil, -1, nil
+			} else if err != nil {
+				return nil, -1, err
+			}
+
+			// Only chatbots with IGA handle key updates in MLS groups, so the MAC is the only authentication.
+			err = csc.verifyTreeKEMKeyUpdatePack(groupId, senderId, treeKEMKeyUpdatePack, externalRoot.GetUpdateMACKey(), false)
 			if err != nil {
-				logger.Warning("UpdateTreeKEMUserKey failed, maybe this is a skipped message? ", messageWrapper.RecipientID, err)
+				return nil, -1, err
+			}
+
+			updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
+			err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, treeKEMKeyUpdatePack.GetNewRootPubKey(), treeKEMKeyUpdatePack.GetNewRootSignPubKey(),
+				treeKEMKeyUpdatePack.GetChatbotEpochs()[csc.chatbotID], treeKEMKeyUpdatePack.GetChatbotTranscriptHashes()[csc.chatbotID])
+			err = csc.finishTreeKEMKeyUpdate(ctx, groupId, pb.GroupType_MLS, externalRoot, err)
+			if errors.Is(err, treekem.ErrDesync) {
+				return nil, -1, err
+			} else if errors.Is(err, treekem.ErrUndecryptableKeyUpdate) {
+				// Chatbots the message does not trigger get a dummy update when triggers are hidden.
+				logger.Info("Skipped TreeKEM update not encrypted to the chatbot in group ", messageWrapper.RecipientID)
+				return []byte("Invalid message"), pb.MessageType_SKIP, nil
+			} else if err != nil {
+				logger.Warning("UpdateTreeKEMUserKey failed: ", messageWrapper.RecipientID, err)
 
 				/* We no longer need to send a validation message after the protocol update.
 				// Send a validation message to the group broadcasting that the message is invalid and therefore is skipped.
@@ -77,7 +108,7 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 					}
 				}
 				*/
-				return []byte("Invalid message"), pb.MessageType_SKIP
+				return []byte("Invalid message"), pb.MessageType_SKIP, err
 			}
 		}
 	}
@@ -85,16 +116,19 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 	if messageWrapper.GetIsPseudo() {
 		if !sessionDriver.GetChatbotIsPseudo(csc.chatbotID) {
 			logger.Error("Received pseudonym message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not pseudo.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("chatbot %v is not pseudonymous in group %v", csc.chatbotID, messageWrapper.RecipientID)
 		}
 
 		pseudoUser, exists := csc.groupPseudonyms[messageWrapper.RecipientID][messageWrapper.SenderID]
 		if !exists {
 			logger.Error("Received pseudonym message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " using pseudonym ", messageWrapper.SenderID, " but the pseudonym is not registered.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("%w: %v in group %v", ErrUnknownPseudonym, messageWrapper.SenderID, messageWrapper.RecipientID)
 		}
 
-		message, messageType := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, pseudoUser.SigningPubKey)
+		message, messageType, err := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, pseudoUser.SigningPubKey, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
 		logger.Info(fmt.Sprintf("Received pseudonym message in MLS group %v from pseudoUser %v: %v", messageWrapper.RecipientID, messageWrapper.SenderID, string(message)))
 
 		/* We no longer need to send a validation message after the protocol update.
@@ -106,14 +140,17 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 		}
 		*/
 
-		return message, messageType
+		return message, messageType, nil
 	} else if messageWrapper.GetIsIGA() {
 		if !sessionDriver.GetChatbotIsIGA(csc.chatbotID) {
 			logger.Error("Received IGA message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not IGA.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("chatbot %v has no IGA in group %v", csc.chatbotID, messageWrapper.RecipientID)
 		}
 
-		message, messageType := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, nil)
+		message, messageType, err := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, nil, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
 		logger.Info(fmt.Sprintf("Received IGA message in MLS group %v: %v", messageWrapper.RecipientID, string(message)))
 
 		// Pseudonym registration message would be sent in IGA channel. This is the only case when user send IGA message to pseudonymity-enabled bot.
@@ -122,7 +159,7 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 
 			if !sessionDriver.GetChatbotIsPseudo(csc.chatbotID) {
 				logger.Error("Received pseudonym registration message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not pseudo.")
-				return nil, -1
+				return nil, -1, fmt.Errorf("chatbot %v is not pseudonymous in group %v", csc.chatbotID, messageWrapper.RecipientID)
 			}
 
 			// Unpack message as PseudonymRegistrationMessage
@@ -130,7 +167,7 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 			err := proto.Unmarshal(message, pseudonymRegistrationMessage)
 			if err != nil {
 				logger.Error("Error unmarshalling message: ", err)
-				return nil, -1
+				return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 			}
 
 			// Update group participants
@@ -159,15 +196,43 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 			*/
 		}
 
-		return message, messageType
+		return message, messageType, nil
+	} else if messageWrapper.GetHiddenTrigger() {
+		// Handle the commit first, as it is delivered whether or not the message is for this chatbot.
+		commit := &mls.MLSPlaintext{}
+		_, err = syntax.Unmarshal(messageWrapper.GetMlsCommit(), commit)
+		if err != nil {
+			logger.Error(err)
+			return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+		}
+		err = sessionDriver.HandleCommit(commit, messageWrapper.SenderID)
+		if err != nil {
+			logger.Error(err)
+			return nil, -1, fmt.Errorf("%w: %w", ErrDesync, err)
+		}
+
+		senderIdentityKey, err := csc.Client.GetRemoteIdentityKey(ctx, messageWrapper.SenderID)
+		if err != nil {
+			logger.Error("No identity key of the sender of the hidden trigger message: ", err)
+			return nil, -1, err
+		}
+		message, messageType, err := sessionDriver.ParseHiddenTriggerMessage(messageWrapper.EncryptedMessage, senderIdentityKey, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
+		logger.Info(fmt.Sprintf("Received hidden trigger message in MLS group %v with type %v: %v", messageWrapper.RecipientID, messageType.String(), string(message)))
+		return message, messageType, nil
 	} else {
 		deserializedCiphertext, err := util.DeserializeMLSCiphertext(messageWrapper.EncryptedMessage)
 		if err != nil {
-			return nil, -1
+			return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 		}
 
 		// Forward the message to the MLS group handler.
-		message, messageType, _ := sessionDriver.ParseEncryptedMessage(senderId, deserializedCiphertext)
+		message, messageType, _, err := sessionDriver.ParseEncryptedMessage(senderId, deserializedCiphertext, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
 		logger.Info(fmt.Sprintf("Received message from %v in MLS group %v with type %v: %v", messageWrapper.SenderID, messageWrapper.RecipientID, messageType.String(), string(message)))
 
 		// Handle the commit
@@ -175,15 +240,15 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 		_, err = syntax.Unmarshal(messageWrapper.GetMlsCommit(), commit)
 		if err != nil {
 			logger.Error(err)
-			return nil, -1
+			return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 		}
 		err = sessionDriver.HandleCommit(commit, messageWrapper.SenderID)
 		if err != nil {
 			logger.Error(err)
-			return nil, -1
+			return nil, -1, fmt.Errorf("%w: %w", ErrDesync, err)
 		}
 
-		return message, messageType
+		return message, messageType, nil
 	}
 
 }
@@ -191,8 +256,27 @@ func (csc *ClientSideChatbot) HandleMlsGroupMessage(messageWrapper *pb.MessageWr
 /*
 SendMlsGroupMessage sends a message to an MLS group.
 */
-func (csc *ClientSideChatbot) SendMlsGroupMessage(groupID string, messageRaw []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendMlsGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType) error {
+	sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return csc.sendGroupMessageWithKeyUpdate(ctx, groupID, sessionDriver.SnapshotKeyUpdateState, func() error {
+		return csc.sendMlsGroupMessage(ctx, groupID, messageRaw, messageType)
+	})
+}
+
+/*
+sendMlsGroupMessage issues and sends a single attempt of a group message.
+*/
+func (csc *ClientSideChatbot) sendMlsGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType) error {
 	logger.Info("Sending message to MLS group ", groupID, ": ", string(messageRaw))
+	if err := csc.checkMayPost(groupID); err != nil {
+		logger.Error(err)
+		return err
+	}
 	sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
 	if err != nil {
 		logger.Error(err)
@@ -205,6 +289,8 @@ func (csc *ClientSideChatbot) SendMlsGroupMessage(groupID string, messageRaw []b
 	var newCbSignPubKey []byte
 	var chatbotKeyUpdatePack *pb.MultiTreeKEMExternalKeyUpdatePack
 
+	// The ciphertext is bound to the MessageID of the message.
+	messageID := client.NewMessageID()
 	var serializedCipherText []byte
 	var serializedCommit []byte
 
@@ -214,14 +300,20 @@ func (csc *ClientSideChatbot) SendMlsGroupMessage(groupID string, messageRaw []b
 			logger.Error(err)
 			return err
 		}
-		serializedCipherText = sessionDriver.EncryptMessageByMlsMultiTreeExternalRoot(messageRaw, messageType, sessionDriver.GetMlsMultiTreeExternal().GetSelfNode().SignPrivate).Serialize()
+		cipherText, err := sessionDriver.EncryptMessageByMlsMultiTreeExternalRoot(messageRaw, messageType, messageID, sessionDriver.GetMlsMultiTreeExternal().GetSelfNode().SignPrivate)
+		if err != nil {
+			return err
+		}
+		serializedCipherText = cipherText.Serialize()
 		chatbotKeyUpdatePack = &pb.MultiTreeKEMExternalKeyUpdatePack{
 			ChatbotUpdate:   treekem.ECKEMCipherTextPbConvert(&chatbotUpdate),
 			NewCbPubKey:     newCbPubKey,
 			NewCbSignPubKey: newCbSignPubKey,
+			Epoch:           sessionDriver.GetMlsMultiTreeExternal().GetEpoch(),
+			TranscriptHash:  sessionDriver.GetMlsMultiTreeExternal().GetTranscriptHash(),
 		}
 	} else {
-		ct, commit, err := sessionDriver.EncryptMessage(messageRaw, messageType, nil)
+		ct, commit, err := sessionDriver.EncryptMessage(messageRaw, messageType, messageID, nil)
 		if err != nil {
 			logger.Error("Failed to encrypt message: ", err)
 			return err
@@ -247,15 +339,16 @@ func (csc *ClientSideChatbot) SendMlsGroupMessage(groupID string, messageRaw []b
 		IsIGA:                sessionDriver.GetChatbotIsIGA(csc.chatbotID),
 		ChatbotKeyUpdatePack: chatbotKeyUpdatePack,
 		MlsCommit:            serializedCommit,
+		MessageID:            messageID,
 	}
 
-	return csc.Client.SendMlsGroupMessage(groupID, messageWrapper)
+	return csc.Client.SendMlsGroupMessage(ctx, groupID, messageWrapper)
 }
 
 /*
 SendMlsValidationMessage sends the input message to the group with the message type of pb.MessageType_VALIDATION_MESSAGE.
 */
-func (csc *ClientSideChatbot) SendMlsValidationMessage(groupID string, message []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendMlsValidationMessage(ctx context.Context, groupID string, message []byte, messageType pb.MessageType) error {
 	logger.Info("Sending validation message to group ", groupID, ": ", string(message))
 
 	validationMessage := &pb.ValidationMessage{
@@ -270,5 +363,5 @@ func (csc *ClientSideChatbot) SendMlsValidationMessage(groupID string, message [
 		return err
 	}
 
-	return csc.SendMlsGroupMessage(groupID, validationMessageMarshal, pb.MessageType_VALIDATION_MESSAGE)
+	return csc.SendMlsGroupMessage(ctx, groupID, validationMessageMarshal, pb.MessageType_VALIDATION_MESSAGE)
 }
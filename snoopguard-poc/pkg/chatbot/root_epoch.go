@@ -0,0 +1,255 @@
+package chatbot
+
+import (
+	"chatbot-poc-go/pkg/client"
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"go.mau.fi/libsignal/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+externalRoot is the root the chatbot shares with a group, kept by MultiTreeKEMExternal or MlsMultiTreeExternal.
+*/
+type externalRoot interface {
+	CheckEpoch(epoch uint64) error
+	GetEpoch() uint64
+	Recover(recovery treekem.ExternalNodeRecovery) error
+}
+
+// errKeyUpdateBuffered is returned when a key update pack is ahead of the root of the chatbot and was buffered to be
+// handled once the updates before it arrive.
+var errKeyUpdateBuffered = errors.New("key update pack buffered")
+
+/*
+getGroupExternalRoot returns the root the chatbot shares with the group, and the IDs of the members of the group who
+can recover it.
+*/
+func (csc *ClientSideChatbot) getGroupExternalRoot(groupID string, groupType pb.GroupType) (externalRoot, []string, error) {
+	var root externalRoot
+	var participantIDs, chatbotIDs []string
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, nil, err
+		}
+		root, participantIDs, chatbotIDs = sessionDriver.GetMultiTreeKEMExternal(), sessionDriver.GetGroupParticipants(), sessionDriver.GetGroupChatbots()
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csc.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, nil, err
+		}
+		root, participantIDs, chatbotIDs = sessionDriver.GetMultiTreeKEMExternal(), sessionDriver.GetGroupParticipants(), sessionDriver.GetGroupChatbots()
+	case pb.GroupType_MLS:
+		sessionDriver, err := csc.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, nil, err
+		}
+		root, participantIDs, chatbotIDs = sessionDriver.GetMlsMultiTreeExternal(), sessionDriver.GetGroupParticipants(), sessionDriver.GetGroupChatbots()
+	default:
+		return nil, nil, fmt.Errorf("unknown group type %v", groupType)
+	}
+
+	// Pseudonyms and chatbots do not know the root.
+	memberIDs := make([]string, 0, len(participantIDs))
+	for _, participantID := range participantIDs {
+		_, isPseudonym := csc.groupPseudonyms[groupID][participantID]
+		if participantID != csc.chatbotID && !isPseudonym && !util.ContainString(participantID, chatbotIDs) {
+			memberIDs = append(memberIDs, participantID)
+		}
+	}
+	return root, memberIDs, nil
+}
+
+/*
+checkTreeKEMKeyUpdateEpoch checks the epoch the key update pack moves the root of the chatbot to. It has to be called
+before the pack is authenticated, as the MAC key of an update ahead of the root is not known yet. An update ahead of the
+root is buffered as the given messageWrapper until the updates before it arrive; if too many of them are missing, the
+chatbot asks a member to recover the root instead. It returns nil only if the update is the next one, and an error
+wrapping errKeyUpdateBuffered if the update was buffered.
+*/
+func (csc *ClientSideChatbot) checkTreeKEMKeyUpdateEpoch(ctx context.Context, groupID string, groupType pb.GroupType, root externalRoot, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, messageWrapper *pb.MessageWrapper) error {
+	epoch := treeKEMKeyUpdatePack.GetChatbotEpochs()[csc.chatbotID]
+	err := root.CheckEpoch(epoch)
+	if errors.Is(err, treekem.ErrDesync) {
+		if csc.Client.GetPendingKeyUpdates().Add(groupID, csc.chatbotID, root.GetEpoch(), epoch, messageWrapper) {
+			logger.Warning("Buffered TreeKEM update in group ", groupID, ": ", err)
+			return fmt.Errorf("%w: %w", errKeyUpdateBuffered, err)
+		} else {
+			logger.Error("Too many TreeKEM updates missing in group ", groupID, ": ", err)
+			csc.requestRootRecoveryOnce(ctx, groupID, groupType)
+		}
+	} else if err != nil {
+		logger.Error("Rejected TreeKEM update in group ", groupID, ": ", err)
+	}
+	return err
+}
+
+/*
+finishTreeKEMKeyUpdate takes the result of handling a key update of the root. If the transcript hash did not match, the
+chatbot asks a member to recover the root. Otherwise, the buffered update following it is queued to be handled next.
+*/
+func (csc *ClientSideChatbot) finishTreeKEMKeyUpdate(ctx context.Context, groupID string, groupType pb.GroupType, root externalRoot, err error) error {
+	if errors.Is(err, treekem.ErrDesync) {
+		logger.Error("TreeKEM update in group ", groupID, " does not follow the local root: ", err)
+		csc.requestRootRecoveryOnce(ctx, groupID, groupType)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	csc.queueBufferedKeyUpdate(groupID, root.GetEpoch()+1)
+	return nil
+}
+
+/*
+queueBufferedKeyUpdate queues the buffered key updates of the root to the given epoch, if any, to be handled after the
+current message. The first one that is authenticated moves the root, the others are rejected when they are handled.
+*/
+func (csc *ClientSideChatbot) queueBufferedKeyUpdate(groupID string, epoch uint64) {
+	csc.readyKeyUpdates = append(csc.readyKeyUpdates, csc.Client.GetPendingKeyUpdates().Take(groupID, csc.chatbotID, epoch)...)
+}
+
+/*
+handleReadyKeyUpdates handles the buffered key updates queued by the previous message, and the ones they queue in turn.
+It returns the outputs of the messages carrying them.
+*/
+func (csc *ClientSideChatbot) handleReadyKeyUpdates(ctx context.Context) []OutputMessage {
+	var outputs []OutputMessage
+	for len(csc.readyKeyUpdates) > 0 {
+		messageWrapper := csc.readyKeyUpdates[0]
+		csc.readyKeyUpdates = csc.readyKeyUpdates[1:]
+
+		// Messages of client-side groups were already decrypted and delivered, so only their key updates are left.
+		if _, err := csc.Client.GetClientSideGroupSessionDriver(messageWrapper.RecipientID); err == nil {
+			err := csc.handleClientSideTreeKEMKeyUpdate(ctx, messageWrapper.RecipientID, messageWrapper.SenderID, messageWrapper.GetTreeKEMKeyUpdatePack())
+			if err != nil {
+				client.ReportError(csc.errorChan, &HandlingError{GroupID: messageWrapper.RecipientID, SenderID: messageWrapper.SenderID, MessageID: messageWrapper.GetMessageID(), Err: err})
+			}
+			continue
+		}
+
+		output, err := csc.parseMessageWrapper(ctx, messageWrapper)
+		if err != nil {
+			client.ReportError(csc.errorChan, &HandlingError{GroupID: messageWrapper.RecipientID, SenderID: messageWrapper.SenderID, MessageID: messageWrapper.GetMessageID(), Err: err})
+		}
+		if output != nil {
+			outputs = append(outputs, *output)
+		}
+	}
+	return outputs
+}
+
+/*
+RequestRootRecovery asks a member of the group to send the current root the chatbot shares with the group, after the
+chatbot lost track of it, e.g. because key updates were lost or the transcript hash did not match. The chatbot learns
+which member answered.
+*/
+func (csc *ClientSideChatbot) RequestRootRecovery(ctx context.Context, groupID string, groupType pb.GroupType, memberID string) error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	err := csc.requestRootRecovery(ctx, groupID, groupType, memberID)
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	return err
+}
+
+/*
+requestRootRecovery asks a member of the group to recover the root while the mutex is held.
+*/
+func (csc *ClientSideChatbot) requestRootRecovery(ctx context.Context, groupID string, groupType pb.GroupType, memberID string) error {
+	root, _, err := csc.getGroupExternalRoot(groupID, groupType)
+	if err != nil {
+		logger.Error("Failed to get root of group ", groupID, ": ", err)
+		return err
+	}
+
+	rootRecoveryRequest, err := proto.Marshal(&pb.RootRecoveryRequest{
+		GroupID:   groupID,
+		GroupType: groupType,
+		Epoch:     root.GetEpoch(),
+	})
+	if err != nil {
+		logger.Error("Error marshalling root recovery request: ", err)
+		return err
+	}
+
+	logger.Info("Requesting root recovery of group ", groupID, " from ", memberID)
+	csc.rootRecoveries[groupID] = memberID
+	return csc.sendIndividualMessage(ctx, protocol.NewSignalAddress(memberID, 1), rootRecoveryRequest, pb.MessageType_ROOT_RECOVERY_REQUEST)
+}
+
+/*
+requestRootRecoveryOnce asks a member of the group to recover the root, unless a request is already pending.
+*/
+func (csc *ClientSideChatbot) requestRootRecoveryOnce(ctx context.Context, groupID string, groupType pb.GroupType) {
+	if _, requested := csc.rootRecoveries[groupID]; requested {
+		return
+	}
+
+	// Chatbots with IGA do not know the members, so the application has to pick one with RequestRootRecovery.
+	_, memberIDs, err := csc.getGroupExternalRoot(groupID, groupType)
+	if err != nil || len(memberIDs) == 0 {
+		logger.Error("No known member to recover the root of group ", groupID, " from")
+		return
+	}
+
+	err = csc.requestRootRecovery(ctx, groupID, groupType, memberIDs[0])
+	if err != nil {
+		logger.Error("Failed to request root recovery of group ", groupID, ": ", err)
+	}
+}
+
+/*
+HandleRootRecovery resumes the root the chatbot shares with a group at the state sent by a member, and handles the
+buffered key updates that follow it. Only the member the chatbot asked or a member it knows may send the root.
+*/
+func (csc *ClientSideChatbot) HandleRootRecovery(message []byte, senderID string) ([]byte, pb.MessageType, error) {
+	rootRecovery := &pb.RootRecovery{}
+	err := proto.Unmarshal(message, rootRecovery)
+	if err != nil {
+		logger.Error("Error unmarshalling root recovery: ", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+
+	groupID := rootRecovery.GetGroupID()
+	root, memberIDs, err := csc.getGroupExternalRoot(groupID, rootRecovery.GetGroupType())
+	if err != nil {
+		logger.Error("Received root recovery for unknown group ", groupID)
+		return nil, -1, err
+	}
+	if csc.rootRecoveries[groupID] != senderID && !util.ContainString(senderID, memberIDs) {
+		logger.Error("Rejected root recovery from ", senderID, " who was not asked and is not a known member of group ", groupID)
+		return nil, -1, fmt.Errorf("root recovery of group %v from %v who was not asked and is not a known member", groupID, senderID)
+	}
+
+	err = root.Recover(treekem.ExternalNodeRecovery{
+		RootEpoch: treekem.RootEpoch{
+			Epoch:          rootRecovery.GetEpoch(),
+			TranscriptHash: rootRecovery.GetTranscriptHash(),
+		},
+		RootSecret:            rootRecovery.GetRootSecret(),
+		TreeKEMRootPublic:     rootRecovery.GetTreeKemRootPubKey(),
+		TreeKEMRootSignPublic: rootRecovery.GetTreeKemRootSignPubKey(),
+	})
+	if err != nil {
+		logger.Error("Rejected root recovery from ", senderID, " for group ", groupID, ": ", err)
+		return nil, -1, err
+	}
+	logger.Info("Recovered root of group ", groupID, " at epoch ", root.GetEpoch(), " from ", senderID)
+
+	delete(csc.rootRecoveries, groupID)
+	csc.Client.GetPendingKeyUpdates().DropUpTo(groupID, csc.chatbotID, root.GetEpoch())
+	csc.queueBufferedKeyUpdate(groupID, root.GetEpoch()+1)
+
+	return []byte(groupID), pb.MessageType_ROOT_RECOVERY, nil
+}
@@ -0,0 +1,31 @@
+package chatbot
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"fmt"
+)
+
+/*
+SetGroupScopes records the access scopes granted to the chatbot in the given group.
+*/
+func (csc *ClientSideChatbot) SetGroupScopes(groupID string, scopes *pb.ChatbotScopes) {
+	csc.groupScopes[groupID] = scopes
+}
+
+/*
+GetGroupScopes returns the access scopes granted to the chatbot in the given group, or nil if the group is unknown.
+*/
+func (csc *ClientSideChatbot) GetGroupScopes(groupID string) *pb.ChatbotScopes {
+	return csc.groupScopes[groupID]
+}
+
+/*
+checkMayPost returns an error if the chatbot is not allowed to post to the given group.
+Members drop such messages anyway, so this only saves the chatbot from sending them.
+*/
+func (csc *ClientSideChatbot) checkMayPost(groupID string) error {
+	if scopes, exists := csc.groupScopes[groupID]; exists && !scopes.GetMayPost() {
+		return fmt.Errorf("chatbot %v is not allowed to post in group %v", csc.chatbotID, groupID)
+	}
+	return nil
+}
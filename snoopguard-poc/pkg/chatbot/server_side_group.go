@@ -4,6 +4,9 @@ import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"errors"
 	"fmt"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
@@ -13,12 +16,19 @@ import (
 /*
 PostCreateServerSideGroup is called when a new client-side group is created.
 */
-func (csc *ClientSideChatbot) PostCreateServerSideGroup(groupID string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte) (*client.ServerSideGroupSessionDriver, error) {
+func (csc *ClientSideChatbot) PostCreateServerSideGroup(groupID string, isIGA bool, isPseudo bool, treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte, cipherSuite treekem.CipherSuiteID, hybridKEM bool) (*client.ServerSideGroupSessionDriver, error) {
 	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Set up the cipher suite of the group
+	err = sessionDriver.SetCipherSuite(cipherSuite)
+	if err != nil {
+		logger.Error("Failed to set cipher suite: ", err)
+		return nil, err
+	}
+
 	// Set up MultiTreeKEMExternal
 	err = sessionDriver.InitiateMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf)
 	if err != nil {
@@ -26,6 +36,13 @@ func (csc *ClientSideChatbot) PostCreateServerSideGroup(groupID string, isIGA bo
 		return nil, err
 	}
 
+	// Use the hybrid KEM with the tree if the group opted in to it for this chatbot
+	err = sessionDriver.GetMultiTreeKEMExternal().SetHybridKEM(hybridKEM)
+	if err != nil {
+		logger.Error("Failed to set up hybrid KEM: ", err)
+		return nil, err
+	}
+
 	// Setup IGA config
 	sessionDriver.SetChatbotIsIGA(csc.chatbotID, isIGA)
 	sessionDriver.SetChatbotIsPseudo(csc.chatbotID, isPseudo)
@@ -37,7 +54,7 @@ func (csc *ClientSideChatbot) PostCreateServerSideGroup(groupID string, isIGA bo
 HandleServerSideGroupMessage handles the incoming server-side group message.
 Todo: modularize this function. It's too big now.
 */
-func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csc *ClientSideChatbot) HandleServerSideGroupMessage(ctx context.Context, messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType, error) {
 	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(messageWrapper.RecipientID)
 	if err != nil {
 		logger.Debug("Received message from server-side fanout group without session ", messageWrapper.RecipientID)
@@ -48,10 +65,32 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 	if treeKEMKeyUpdatePack != nil && treeKEMKeyUpdatePack.GetNewRootPubKey() != nil {
 		logger.Info("Chatbot Received TreeKEM update from ", messageWrapper.SenderID, " for server-side group ", messageWrapper.RecipientID)
 
-		updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
-		err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, treeKEMKeyUpdatePack.GetNewRootPubKey(), treeKEMKeyUpdatePack.GetNewRootSignPubKey())
+		externalRoot := sessionDriver.GetMultiTreeKEMExternal()
+		err = csc.checkTreeKEMKeyUpdateEpoch(ctx, messageWrapper.RecipientID, pb.GroupType_SERVER_SIDE, externalRoot, treeKEMKeyUpdatePack, messageWrapper)
+		if errors.Is(err, errKeyUpdateBuffered) {
+			return nil, -1, nil
+		} else if err != nil {
+			return nil, -1, err
+		}
+
+		requireSignature := !sessionDriver.GetChatbotIsIGA(csc.chatbotID) && !sessionDriver.GetChatbotIsPseudo(csc.chatbotID)
+		err = csc.verifyTreeKEMKeyUpdatePack(messageWrapper.RecipientID, messageWrapper.SenderID, treeKEMKeyUpdatePack, externalRoot.GetUpdateMACKey(), requireSignature)
 		if err != nil {
-			logger.Warning("UpdateTreeKEMUserKey failed, maybe this is a skipped message? ", messageWrapper.RecipientID, err)
+			return nil, -1, err
+		}
+
+		updateMessage := treekem.PbECKEMCipherTextConvert(treeKEMKeyUpdatePack.GetChatbotUpdateCiphertexts().GetCiphertexts()[csc.chatbotID])
+		err = sessionDriver.HandleTreeKEMUserKeyUpdate(updateMessage, newRootPubKeyOf(treeKEMKeyUpdatePack, externalRoot.IsHybridKEM()), treeKEMKeyUpdatePack.GetNewRootSignPubKey(),
+			treeKEMKeyUpdatePack.GetChatbotEpochs()[csc.chatbotID], treeKEMKeyUpdatePack.GetChatbotTranscriptHashes()[csc.chatbotID])
+		err = csc.finishTreeKEMKeyUpdate(ctx, messageWrapper.RecipientID, pb.GroupType_SERVER_SIDE, externalRoot, err)
+		if errors.Is(err, treekem.ErrDesync) {
+			return nil, -1, err
+		} else if errors.Is(err, treekem.ErrUndecryptableKeyUpdate) {
+			// Chatbots the message does not trigger get a dummy update when triggers are hidden.
+			logger.Info("Skipped TreeKEM update not encrypted to the chatbot in group ", messageWrapper.RecipientID)
+			return []byte("Invalid message"), pb.MessageType_SKIP, nil
+		} else if err != nil {
+			logger.Warning("UpdateTreeKEMUserKey failed: ", messageWrapper.RecipientID, err)
 
 			/* We no longer need to send a validation message after the protocol update.
 			// Send a validation message to the group broadcasting that the message is invalid and therefore is skipped.
@@ -64,23 +103,26 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 			}
 			*/
 
-			return []byte("Invalid message"), pb.MessageType_SKIP
+			return []byte("Invalid message"), pb.MessageType_SKIP, err
 		}
 	}
 
 	if messageWrapper.GetIsPseudo() {
 		if !sessionDriver.GetChatbotIsPseudo(csc.chatbotID) {
 			logger.Error("Received pseudonym message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not pseudo.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("chatbot %v is not pseudonymous in group %v", csc.chatbotID, messageWrapper.RecipientID)
 		}
 
 		pseudoUser, exists := csc.groupPseudonyms[messageWrapper.RecipientID][messageWrapper.SenderID]
 		if !exists {
 			logger.Error("Received pseudonym message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " using pseudonym ", messageWrapper.SenderID, " but the pseudonym is not registered.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("%w: %v in group %v", ErrUnknownPseudonym, messageWrapper.SenderID, messageWrapper.RecipientID)
 		}
 
-		message, messageType := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, pseudoUser.SigningPubKey)
+		message, messageType, err := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, pseudoUser.SigningPubKey, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
 		logger.Info(fmt.Sprintf("Received pseudonym message in server-side group %v from pseudoUser %v: %v", messageWrapper.RecipientID, messageWrapper.SenderID, string(message)))
 
 		/* We no longer need to send a validation message after the protocol update.
@@ -92,14 +134,17 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 		}
 		*/
 
-		return message, messageType
+		return message, messageType, nil
 	} else if messageWrapper.GetIsIGA() {
 		if !sessionDriver.GetChatbotIsIGA(csc.chatbotID) {
 			logger.Error("Received IGA message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not IGA.")
-			return nil, -1
+			return nil, -1, fmt.Errorf("chatbot %v has no IGA in group %v", csc.chatbotID, messageWrapper.RecipientID)
 		}
 
-		message, messageType := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, nil)
+		message, messageType, err := sessionDriver.ParseEncryptedIGAMessage(messageWrapper.EncryptedMessage, nil, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
 		logger.Info(fmt.Sprintf("Received IGA message in server-side group %v: %v", messageWrapper.RecipientID, string(message)))
 
 		// Pseudonym registration message would be sent in IGA channel. This is the only case when user send IGA message to pseudonymity-enabled bot.
@@ -108,7 +153,7 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 
 			if !sessionDriver.GetChatbotIsPseudo(csc.chatbotID) {
 				logger.Error("Received pseudonym registration message for chatbot ", csc.chatbotID, " in group ", messageWrapper.RecipientID, " but chatbot is not pseudo.")
-				return nil, -1
+				return nil, -1, fmt.Errorf("chatbot %v is not pseudonymous in group %v", csc.chatbotID, messageWrapper.RecipientID)
 			}
 
 			// Unpack message as PseudonymRegistrationMessage
@@ -116,7 +161,7 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 			err := proto.Unmarshal(message, pseudonymRegistrationMessage)
 			if err != nil {
 				logger.Error("Error unmarshalling message: ", err)
-				return nil, -1
+				return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 			}
 
 			// Update group participants
@@ -142,25 +187,59 @@ func (csc *ClientSideChatbot) HandleServerSideGroupMessage(messageWrapper *pb.Me
 		}
 		*/
 
-		return message, messageType
+		return message, messageType, nil
+	} else if messageWrapper.GetHiddenTrigger() {
+		senderIdentityKey, err := csc.Client.GetRemoteIdentityKey(ctx, messageWrapper.SenderID)
+		if err != nil {
+			logger.Error("No identity key of the sender of the hidden trigger message: ", err)
+			return nil, -1, err
+		}
+		message, messageType, err := sessionDriver.ParseHiddenTriggerMessage(messageWrapper.EncryptedMessage, senderIdentityKey, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
+		}
+		logger.Info(fmt.Sprintf("Received hidden trigger message in server-side group %v with type %v: %v", messageWrapper.RecipientID, messageType.String(), string(message)))
+		return message, messageType, nil
 	} else {
 		// Forward the message to the server-side group handler.
 		msg, err := csc.Client.ParseSenderKeyMessage(messageWrapper.EncryptedMessage) // Convert to SenderKeyMessage
 		if err != nil {
 			logger.Error("Failed to decode sender key message ", err)
-			panic("")
+			return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+		}
+		message, messageType, _, err := sessionDriver.ParseEncryptedMessage(messageWrapper.SenderID, msg, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
 		}
-		message, messageType, _ := sessionDriver.ParseEncryptedMessage(messageWrapper.SenderID, msg)
 		logger.Info(fmt.Sprintf("Received message from %v in server-side group %v with type %v: %v", messageWrapper.SenderID, messageWrapper.RecipientID, messageType.String(), string(message)))
-		return message, messageType
+		return message, messageType, nil
 	}
 }
 
 /*
 SendServerSideGroupMessage sends a message to a server-side group.
 */
-func (csc *ClientSideChatbot) SendServerSideGroupMessage(groupID string, messageRaw []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendServerSideGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType) error {
+	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return csc.sendGroupMessageWithKeyUpdate(ctx, groupID, sessionDriver.SnapshotKeyUpdateState, func() error {
+		return csc.sendServerSideGroupMessage(ctx, groupID, messageRaw, messageType)
+	})
+}
+
+/*
+sendServerSideGroupMessage issues and sends a single attempt of a group message.
+*/
+func (csc *ClientSideChatbot) sendServerSideGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType) error {
 	logger.Info("Sending message to server-side group ", groupID, ": ", string(messageRaw))
+	if err := csc.checkMayPost(groupID); err != nil {
+		logger.Error(err)
+		return err
+	}
 	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return err
@@ -172,6 +251,8 @@ func (csc *ClientSideChatbot) SendServerSideGroupMessage(groupID string, message
 	var newCbSignPubKey []byte
 	var chatbotKeyUpdatePack *pb.MultiTreeKEMExternalKeyUpdatePack
 
+	// The ciphertext is bound to the MessageID of the message.
+	messageID := client.NewMessageID()
 	var ct []byte
 	if sessionDriver.GetChatbotIsIGA(csc.chatbotID) {
 		chatbotUpdate, newCbPubKey, newCbSignPubKey, err = sessionDriver.GenerateMultiTreeKEMExternalKeyUpdate()
@@ -179,14 +260,32 @@ func (csc *ClientSideChatbot) SendServerSideGroupMessage(groupID string, message
 			logger.Error(err)
 			return err
 		}
-		ct = sessionDriver.EncryptMessageByMultiTreeKEMExternalRoot(messageRaw, messageType, sessionDriver.GetMultiTreeKEMExternal().GetSelfNode().SignPrivate).Serialize()
+		cipherText, err := sessionDriver.EncryptMessageByMultiTreeKEMExternalRoot(messageRaw, messageType, messageID, sessionDriver.GetMultiTreeKEMExternal().GetSelfNode().SignPrivate)
+		if err != nil {
+			return err
+		}
+		ct = cipherText.Serialize()
 		chatbotKeyUpdatePack = &pb.MultiTreeKEMExternalKeyUpdatePack{
 			ChatbotUpdate:   treekem.ECKEMCipherTextPbConvert(&chatbotUpdate),
 			NewCbPubKey:     newCbPubKey,
 			NewCbSignPubKey: newCbSignPubKey,
+			Epoch:           sessionDriver.GetMultiTreeKEMExternal().GetEpoch(),
+			TranscriptHash:  sessionDriver.GetMultiTreeKEMExternal().GetTranscriptHash(),
 		}
 	} else {
-		ct = sessionDriver.EncryptMessageBySendingSession(messageRaw, messageType, nil).SignedSerialize()
+		// Rotate the sender key once it has been used for long enough.
+		if sessionDriver.SenderKeyRotationDue() {
+			err = csc.RotateSelfSenderKey(ctx, groupID)
+			if err != nil {
+				logger.Error("Failed to rotate sender key: ", err)
+				return err
+			}
+		}
+		cipherText, err := sessionDriver.EncryptMessageBySendingSession(messageRaw, messageType, messageID, nil)
+		if err != nil {
+			return err
+		}
+		ct = cipherText.SignedSerialize()
 	}
 
 	messageWrapper := &pb.MessageWrapper{
@@ -197,15 +296,16 @@ func (csc *ClientSideChatbot) SendServerSideGroupMessage(groupID string, message
 		HasPreKey:            false,
 		IsIGA:                sessionDriver.GetChatbotIsIGA(csc.chatbotID),
 		ChatbotKeyUpdatePack: chatbotKeyUpdatePack,
+		MessageID:            messageID,
 	}
 
-	return csc.Client.SendServerSideGroupMessage(groupID, messageWrapper)
+	return csc.Client.SendServerSideGroupMessage(ctx, groupID, messageWrapper)
 }
 
 /*
 DistributeSelfSenderKeyToUserID sends the own sender key to the given userID.
 */
-func (csc *ClientSideChatbot) DistributeSelfSenderKeyToUserID(userID string, groupID string, bounceBack bool) error {
+func (csc *ClientSideChatbot) DistributeSelfSenderKeyToUserID(ctx context.Context, userID string, groupID string, bounceBack bool) error {
 	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return err
@@ -227,13 +327,66 @@ func (csc *ClientSideChatbot) DistributeSelfSenderKeyToUserID(userID string, gro
 		return err
 	}
 
-	return csc.SendIndividualMessage(protocol.NewSignalAddress(userID, 1), msgMarshal, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE)
+	return csc.sendIndividualMessage(ctx, protocol.NewSignalAddress(userID, 1), msgMarshal, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE)
 }
 
 /*
 DistributeSelfSenderKeyToAll sends the own sender key to all group participants.
 */
-func (csc *ClientSideChatbot) DistributeSelfSenderKeyToAll(groupID string) error {
+func (csc *ClientSideChatbot) DistributeSelfSenderKeyToAll(ctx context.Context, groupID string) error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	err := csc.distributeSelfSenderKeyToAll(ctx, groupID, true)
+	if err := csc.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	return err
+}
+
+/*
+RotateSelfSenderKey replaces the own sender key with a fresh one and distributes it to the remaining group participants,
+so that a removed participant cannot decrypt the later messages. IGA chatbots do not use sender keys.
+*/
+func (csc *ClientSideChatbot) RotateSelfSenderKey(ctx context.Context, groupID string) error {
+	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return err
+	}
+	if sessionDriver.GetChatbotIsIGA(csc.chatbotID) {
+		return nil
+	}
+
+	logger.Info("Rotating sender key in group ", groupID)
+	sessionDriver.RotateSelfSenderKey()
+
+	// The participants already have our sender key, so they do not need to bounce back theirs.
+	return csc.distributeSelfSenderKeyToAll(ctx, groupID, false)
+}
+
+/*
+rotateSenderKeyAfterHiddenRemoval drops the sessions of the participants no longer in the group, and rotates the own
+sender key for the remaining ones. The chatbot is not told who was removed, as its scopes hide membership changes.
+*/
+func (csc *ClientSideChatbot) rotateSenderKeyAfterHiddenRemoval(ctx context.Context, groupID string, participantIDs []string) error {
+	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return err
+	}
+	for _, participantID := range sessionDriver.GetGroupParticipants() {
+		if !util.ContainString(participantID, participantIDs) {
+			sessionDriver.RemoveUserSession(participantID)
+		}
+	}
+	sessionDriver.UpdateGroupParticipantIDs(participantIDs)
+
+	return csc.RotateSelfSenderKey(ctx, groupID)
+}
+
+/*
+distributeSelfSenderKeyToAll sends the own sender key to all group participants.
+*/
+func (csc *ClientSideChatbot) distributeSelfSenderKeyToAll(ctx context.Context, groupID string, bounceBack bool) error {
 	sessionDriver, err := csc.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return err
@@ -245,7 +398,7 @@ func (csc *ClientSideChatbot) DistributeSelfSenderKeyToAll(groupID string) error
 	}
 
 	for _, userID := range sessionDriver.GetGroupParticipants() {
-		err = csc.DistributeSelfSenderKeyToUserID(userID, groupID, true)
+		err = csc.DistributeSelfSenderKeyToUserID(ctx, userID, groupID, bounceBack)
 		if err != nil {
 			logger.Error("Error sending sender key distribution message to ", userID, ": ", err)
 			return err
@@ -258,7 +411,7 @@ func (csc *ClientSideChatbot) DistributeSelfSenderKeyToAll(groupID string) error
 /*
 SendServerSideValidationMessage sends the input message to the group with the message type of pb.MessageType_VALIDATION_MESSAGE.
 */
-func (csc *ClientSideChatbot) SendServerSideValidationMessage(groupID string, message []byte, messageType pb.MessageType) error {
+func (csc *ClientSideChatbot) SendServerSideValidationMessage(ctx context.Context, groupID string, message []byte, messageType pb.MessageType) error {
 	logger.Info("Sending validation message to group ", groupID, ": ", string(message))
 
 	validationMessage := &pb.ValidationMessage{
@@ -273,5 +426,5 @@ func (csc *ClientSideChatbot) SendServerSideValidationMessage(groupID string, me
 		return err
 	}
 
-	return csc.SendServerSideGroupMessage(groupID, validationMessageMarshal, pb.MessageType_VALIDATION_MESSAGE)
+	return csc.SendServerSideGroupMessage(ctx, groupID, validationMessageMarshal, pb.MessageType_VALIDATION_MESSAGE)
 }
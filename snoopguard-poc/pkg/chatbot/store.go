@@ -0,0 +1,195 @@
+package chatbot
+
+import (
+	"chatbot-poc-go/pkg/client"
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
+	"context"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"net"
+)
+
+// StoredClientSideChatbotVersion is the version of the format Persist writes.
+const StoredClientSideChatbotVersion = 1
+
+/*
+SetStateStore sets the store the chatbot persists its state to. Once set, the state is persisted after every message
+and server event the chatbot handles, after every message it sends, and when the chatbot is deactivated.
+*/
+func (csc *ClientSideChatbot) SetStateStore(stateStore stores.StateStore) {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	csc.stateStore = stateStore
+}
+
+/*
+Persist saves the state of the chatbot to its state store, from which it is restored by RestoreClientSideChatbot.
+*/
+func (csc *ClientSideChatbot) Persist() error {
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+
+	return csc.persistLocked()
+}
+
+/*
+persistLocked saves the state of the chatbot to its state store, if one is set. The caller must hold the mutex.
+*/
+func (csc *ClientSideChatbot) persistLocked() error {
+	if csc.stateStore == nil {
+		return nil
+	}
+
+	stored, err := csc.export()
+	if err != nil {
+		return err
+	}
+	serialized, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return csc.stateStore.Save(serialized)
+}
+
+/*
+export returns the state of the chatbot: its client, the pseudonyms of its groups, its routing and scopes, and the key
+updates and root recoveries in progress.
+*/
+func (csc *ClientSideChatbot) export() (*pb.StoredClientSideChatbot, error) {
+	storedClient, err := csc.Client.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &pb.StoredClientSideChatbot{
+		Version:         StoredClientSideChatbotVersion,
+		Client:          storedClient,
+		Routing:         csc.chatbotRouting,
+		GroupScopes:     csc.groupScopes,
+		ReadyKeyUpdates: csc.readyKeyUpdates,
+		RootRecoveries:  csc.rootRecoveries,
+	}
+	for groupID, pseudonyms := range csc.groupPseudonyms {
+		for pseudoUserID, pseudoUser := range pseudonyms {
+			stored.Pseudonyms = append(stored.Pseudonyms, &pb.StoredPseudoUser{
+				GroupID:          groupID,
+				PseudoUserID:     pseudoUserID,
+				SigningPublicKey: pseudoUser.SigningPubKey,
+			})
+		}
+	}
+
+	return stored, nil
+}
+
+/*
+loadClientSideChatbot recreates a chatbot from its state store, without connecting it to the server.
+*/
+func loadClientSideChatbot(stateStore stores.StateStore) (*ClientSideChatbot, error) {
+	serialized, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	stored := &pb.StoredClientSideChatbot{}
+	if err := proto.Unmarshal(serialized, stored); err != nil {
+		return nil, err
+	}
+	if stored.GetVersion() != StoredClientSideChatbotVersion {
+		return nil, fmt.Errorf("unsupported stored chatbot version %v", stored.GetVersion())
+	}
+
+	clientObj, err := client.RestoreClient(stored.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	csc := &ClientSideChatbot{
+		Client:          clientObj,
+		chatbotID:       clientObj.GetUserID(),
+		messageChan:     make(chan OutputMessage, 100),
+		errorChan:       make(chan error, 100),
+		deactivateChan:  make(chan context.Context, 1),
+		groupPseudonyms: make(map[string]map[string]*PseudoUser),
+		chatbotRouting:  stored.GetRouting(),
+		groupScopes:     make(map[string]*pb.ChatbotScopes),
+		readyKeyUpdates: stored.GetReadyKeyUpdates(),
+		rootRecoveries:  make(map[string]string),
+		stateStore:      stateStore,
+	}
+
+	for _, storedPseudonym := range stored.GetPseudonyms() {
+		if _, exists := csc.groupPseudonyms[storedPseudonym.GetGroupID()]; !exists {
+			csc.groupPseudonyms[storedPseudonym.GetGroupID()] = make(map[string]*PseudoUser)
+		}
+		csc.groupPseudonyms[storedPseudonym.GetGroupID()][storedPseudonym.GetPseudoUserID()] = &PseudoUser{
+			PseudoUserID:  storedPseudonym.GetPseudoUserID(),
+			SigningPubKey: storedPseudonym.GetSigningPublicKey(),
+		}
+	}
+	for groupID, scopes := range stored.GetGroupScopes() {
+		csc.groupScopes[groupID] = scopes
+	}
+	for groupID, memberID := range stored.GetRootRecoveries() {
+		csc.rootRecoveries[groupID] = memberID
+	}
+
+	return csc, nil
+}
+
+/*
+RestoreClientSideChatbot recreates the chatbot persisted to the state store and connects it to the server at
+chatServiceAddress. The chatbot keeps persisting to the same store. The close() of the connection is returned as well.
+*/
+func RestoreClientSideChatbot(ctx context.Context, stateStore stores.StateStore, chatServiceAddress string) (*ClientSideChatbot, func() error, error) {
+	csc, err := loadClientSideChatbot(stateStore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeChatServiceClient := csc.SetupChatServiceClient(chatServiceAddress)
+	csc.Client.SetChatServiceClient(&csc.chatServiceClient)
+
+	if !csc.RegisterChatbotToServer(ctx) {
+		closeChatServiceClient()
+		return nil, nil, fmt.Errorf("chatbot registration failed")
+	}
+
+	csc.startListening()
+
+	return csc, closeChatServiceClient, nil
+}
+
+/*
+RestoreClientSideChatbotBufconn recreates the chatbot persisted to the state store and connects it to the server
+through the given dialer. The chatbot keeps persisting to the same store.
+*/
+func RestoreClientSideChatbotBufconn(ctx context.Context, stateStore stores.StateStore, dialer func(context.Context, string) (net.Conn, error)) (*ClientSideChatbot, error) {
+	csc, err := loadClientSideChatbot(stateStore)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, "", grpc.WithInsecure(), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, err
+	}
+
+	serviceClient := pb.NewChatServiceClient(conn)
+	csc.Client.SetChatServiceClient(&serviceClient)
+	csc.chatServiceClient = serviceClient
+	csc.chatServiceConn = conn
+
+	if !csc.RegisterChatbotToServer(ctx) {
+		logger.Error("Chatbot registration failed")
+		conn.Close()
+		return nil, fmt.Errorf("chatbot registration failed")
+	}
+
+	csc.startListening()
+
+	return csc, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"go.mau.fi/libsignal/ecc"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+chatbotRoutingTBS returns the bytes the inviter signs the routing of a chatbot on: the group, the chatbot, the inviter
+and the routing.
+*/
+func chatbotRoutingTBS(groupID string, chatbotID string, signerID string, routing *pb.ChatbotRouting) ([]byte, error) {
+	routingBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(routing)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs := []byte("snoopguard chatbot routing")
+	for _, id := range []string{groupID, chatbotID, signerID} {
+		tbs = binary.BigEndian.AppendUint32(tbs, uint32(len(id)))
+		tbs = append(tbs, id...)
+	}
+	return append(tbs, routingBytes...), nil
+}
+
+/*
+GetChatbotRouting fetches the mention names and command prefixes the chatbot registered at the server.
+*/
+func (client *Client) GetChatbotRouting(ctx context.Context, chatbotID string) (*pb.ChatbotRouting, error) {
+	res, err := client.chatServiceClient.GetChatbot(ctx, &pb.GetChatbotRequest{
+		ChatbotID: chatbotID,
+	})
+	if err != nil {
+		logger.Error("GetChatbot failed: ", err)
+		return nil, err
+	}
+	if !res.GetSuccess() {
+		return nil, fmt.Errorf("failed to get chatbot %v: %v", chatbotID, res.GetErrorMessage())
+	}
+	if res.GetChatbotRouting() == nil {
+		return &pb.ChatbotRouting{}, nil
+	}
+	return res.GetChatbotRouting(), nil
+}
+
+/*
+SignChatbotRouting signs the routing of a chatbot in the group with the identity key of the user, who invites the
+chatbot. The members route messages to the chatbot only by a routing signed by a member.
+*/
+func (client *Client) SignChatbotRouting(groupID string, chatbotID string, routing *pb.ChatbotRouting) (*pb.SignedChatbotRouting, error) {
+	tbs, err := chatbotRoutingTBS(groupID, chatbotID, client.userID, routing)
+	if err != nil {
+		return nil, err
+	}
+	sig := ecc.CalculateSignature(client.GetIdentityKey().PrivateKey(), tbs)
+
+	return &pb.SignedChatbotRouting{
+		SignerID:  client.userID,
+		Routing:   routing,
+		Signature: sig[:],
+	}, nil
+}
+
+/*
+VerifyChatbotRouting checks that the routing of a chatbot in the group is signed by the identity key of its signer.
+Whether the signer may set the routing is up to the caller.
+*/
+func (client *Client) VerifyChatbotRouting(ctx context.Context, groupID string, chatbotID string, signed *pb.SignedChatbotRouting) error {
+	if len(signed.GetSignature()) != 64 {
+		return fmt.Errorf("%w: routing of %v is not signed", ErrBadSignature, chatbotID)
+	}
+
+	identityKey := client.GetIdentityKey().PublicKey()
+	if signed.GetSignerID() != client.userID {
+		remoteIdentityKey, err := client.GetRemoteIdentityKey(ctx, signed.GetSignerID())
+		if err != nil {
+			return err
+		}
+		identityKey = remoteIdentityKey
+	}
+
+	tbs, err := chatbotRoutingTBS(groupID, chatbotID, signed.GetSignerID(), signed.GetRouting())
+	if err != nil {
+		return err
+	}
+	if !ecc.VerifySignature(identityKey.PublicKey(), tbs, [64]byte(signed.GetSignature())) {
+		return fmt.Errorf("%w: routing of %v from %v", ErrBadSignature, chatbotID, signed.GetSignerID())
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"go.mau.fi/libsignal/ecc"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+chatbotScopesTBS returns the bytes the inviter signs the scopes of a chatbot on: the group, the chatbot, the inviter, the
+version and the scopes.
+*/
+func chatbotScopesTBS(groupID string, chatbotID string, signerID string, version uint64, scopes *pb.ChatbotScopes) ([]byte, error) {
+	scopesBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs := []byte("snoopguard chatbot scopes")
+	for _, id := range []string{groupID, chatbotID, signerID} {
+		tbs = binary.BigEndian.AppendUint32(tbs, uint32(len(id)))
+		tbs = append(tbs, id...)
+	}
+	tbs = binary.BigEndian.AppendUint64(tbs, version)
+	return append(tbs, scopesBytes...), nil
+}
+
+/*
+SignChatbotScopes signs the given version of the scopes of a chatbot in the group with the identity key of the user, who
+invited the chatbot. The members only take scopes signed by the inviter, each version newer than the last.
+*/
+func (client *Client) SignChatbotScopes(groupID string, chatbotID string, scopes *pb.ChatbotScopes, version uint64) (*pb.SignedChatbotScopes, error) {
+	tbs, err := chatbotScopesTBS(groupID, chatbotID, client.userID, version, scopes)
+	if err != nil {
+		return nil, err
+	}
+	sig := ecc.CalculateSignature(client.GetIdentityKey().PrivateKey(), tbs)
+
+	return &pb.SignedChatbotScopes{
+		SignerID:  client.userID,
+		Scopes:    scopes,
+		Version:   version,
+		Signature: sig[:],
+	}, nil
+}
+
+/*
+VerifyChatbotScopes checks that the scopes of a chatbot in the group are signed by the identity key of their signer.
+Whether the signer may set the scopes, and whether the version is new, is up to the caller.
+*/
+func (client *Client) VerifyChatbotScopes(ctx context.Context, groupID string, chatbotID string, signed *pb.SignedChatbotScopes) error {
+	if len(signed.GetSignature()) != 64 {
+		return fmt.Errorf("%w: scopes of %v are not signed", ErrBadSignature, chatbotID)
+	}
+
+	identityKey := client.GetIdentityKey().PublicKey()
+	if signed.GetSignerID() != client.userID {
+		remoteIdentityKey, err := client.GetRemoteIdentityKey(ctx, signed.GetSignerID())
+		if err != nil {
+			return err
+		}
+		identityKey = remoteIdentityKey
+	}
+
+	tbs, err := chatbotScopesTBS(groupID, chatbotID, signed.GetSignerID(), signed.GetVersion(), signed.GetScopes())
+	if err != nil {
+		return err
+	}
+	if !ecc.VerifySignature(identityKey.PublicKey(), tbs, [64]byte(signed.GetSignature())) {
+		return fmt.Errorf("%w: scopes of %v from %v", ErrBadSignature, chatbotID, signed.GetSignerID())
+	}
+
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"go.mau.fi/libsignal/serialize"
 	"go.mau.fi/libsignal/util/optional"
 	"google.golang.org/protobuf/proto"
+	"sort"
 	"sync"
 )
 
@@ -30,10 +31,13 @@ type Client struct {
 	clientSideGroupSessionDrivers map[string]*ClientSideGroupSessionDriver
 	mlsGroupSessionDrivers        map[string]*MlsGroupSessionDriver
 
+	pendingKeyUpdates *PendingKeyUpdates
+	keyUpdateSequence *KeyUpdateSequence
+	replayWindow      *ReplayWindow
+
 	user *util.User
 
-	chatServiceClient    pb.ChatServiceClient
-	chatServiceClientCtx context.Context
+	chatServiceClient pb.ChatServiceClient
 }
 
 /*
@@ -47,16 +51,18 @@ func NewClient(userID string) *Client {
 		serverSideGroupSessionDrivers: make(map[string]*ServerSideGroupSessionDriver),
 		clientSideGroupSessionDrivers: make(map[string]*ClientSideGroupSessionDriver),
 		mlsGroupSessionDrivers:        make(map[string]*MlsGroupSessionDriver),
+		pendingKeyUpdates:             NewPendingKeyUpdates(),
+		keyUpdateSequence:             NewKeyUpdateSequence(),
+		replayWindow:                  NewReplayWindow(),
 		user:                          util.NewUser(userID, 1, serialize.NewProtoBufSerializer()),
 	}
 }
 
 /*
-SetChatServiceClient injects the chatServiceClient and chatServiceClientCtx into the Client.
+SetChatServiceClient injects the chatServiceClient into the Client.
 */
-func (client *Client) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient, chatServiceClientCtx *context.Context) {
+func (client *Client) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient) {
 	client.chatServiceClient = *chatServiceClient
-	client.chatServiceClientCtx = *chatServiceClientCtx
 }
 
 /*
@@ -76,11 +82,11 @@ func (client *Client) GetRegistrationID() uint32 {
 /*
 UploadPreKeyByID uploads the serialized preKey determined by the preKeyID(uint32) to the server.
 */
-func (client *Client) UploadPreKeyByID(preKeyID uint32) bool {
+func (client *Client) UploadPreKeyByID(ctx context.Context, preKeyID uint32) bool {
 	//logger.Info("Uploading preKey for User: ", client.userID)
 
 	// Upload preKey
-	res, err := client.chatServiceClient.UploadPreKey(client.chatServiceClientCtx, &pb.UploadPreKeyRequest{
+	res, err := client.chatServiceClient.UploadPreKey(ctx, &pb.UploadPreKeyRequest{
 		UserID:   client.userID,
 		PreKey:   client.user.GetPreKey(preKeyID).KeyPair().PublicKey().Serialize(),
 		PreKeyID: preKeyID,
@@ -100,12 +106,12 @@ func (client *Client) UploadPreKeyByID(preKeyID uint32) bool {
 /*
 UploadSignedPreKeyByID uploads the serialized signedPreKey determined by the signedPreKeyID(uint32) to the server.
 */
-func (client *Client) UploadSignedPreKeyByID(signedPreKeyID uint32) bool {
+func (client *Client) UploadSignedPreKeyByID(ctx context.Context, signedPreKeyID uint32) bool {
 	//logger.Info("Uploading signedPreKey for User: ", client.userID)
 
 	// Upload SignedPreKey
 	sig := client.user.GetSignedPreKey(signedPreKeyID).Signature()
-	res, err := client.chatServiceClient.UploadSignedPreKey(client.chatServiceClientCtx, &pb.UploadSignedPreKeyRequest{
+	res, err := client.chatServiceClient.UploadSignedPreKey(ctx, &pb.UploadSignedPreKeyRequest{
 		UserID:          client.userID,
 		SignedPreKey:    client.user.GetSignedPreKey(signedPreKeyID).KeyPair().PublicKey().Serialize(),
 		SignedPreKeySig: sig[:],
@@ -147,11 +153,11 @@ func (client *Client) GetSelfPreKeyBundle(preKeyID uint32, signedPreKeyID uint32
 /*
 GetOthersPreKeyBundle gets the preKeyBundle of the given recipientAddress from the server.
 */
-func (client *Client) GetOthersPreKeyBundle(recipientID string) (*prekey.Bundle, error) {
+func (client *Client) GetOthersPreKeyBundle(ctx context.Context, recipientID string) (*prekey.Bundle, error) {
 	logger.Info("Getting preKeyBundle for User: ", recipientID)
 
 	// Get others preKey
-	resPreKey, err := client.chatServiceClient.FetchPreKey(client.chatServiceClientCtx, &pb.FetchPreKeyRequest{
+	resPreKey, err := client.chatServiceClient.FetchPreKey(ctx, &pb.FetchPreKeyRequest{
 		UserID: recipientID,
 	})
 	if err != nil {
@@ -160,7 +166,7 @@ func (client *Client) GetOthersPreKeyBundle(recipientID string) (*prekey.Bundle,
 	}
 
 	// Get others signedPreKey
-	resSignedPreKey, err := client.chatServiceClient.FetchSignedPreKey(client.chatServiceClientCtx, &pb.FetchSignedPreKeyRequest{
+	resSignedPreKey, err := client.chatServiceClient.FetchSignedPreKey(ctx, &pb.FetchSignedPreKeyRequest{
 		UserID: recipientID,
 	})
 	if err != nil {
@@ -169,7 +175,7 @@ func (client *Client) GetOthersPreKeyBundle(recipientID string) (*prekey.Bundle,
 	}
 
 	// Get others user info
-	resUserInfo, err := client.chatServiceClient.GetUser(client.chatServiceClientCtx, &pb.GetUserRequest{
+	resUserInfo, err := client.chatServiceClient.GetUser(ctx, &pb.GetUserRequest{
 		UserID: recipientID,
 	})
 	if err != nil {
@@ -189,6 +195,30 @@ func (client *Client) GetOthersPreKeyBundle(recipientID string) (*prekey.Bundle,
 	), nil
 }
 
+/*
+GetRemoteIdentityKey returns the identity key of a user. It is taken from the pairwise session with the user if there
+is one, and otherwise from the server, which the pairwise sessions are established with as well.
+*/
+func (client *Client) GetRemoteIdentityKey(ctx context.Context, remoteID string) (*identity.Key, error) {
+	if sessionDriver, err := client.GetSessionDriver(protocol.NewSignalAddress(remoteID, 1)); err == nil {
+		if identityKey := sessionDriver.GetRemoteIdentityKey(); identityKey != nil {
+			return identityKey, nil
+		}
+	}
+
+	resUserInfo, err := client.chatServiceClient.GetUser(ctx, &pb.GetUserRequest{
+		UserID: remoteID,
+	})
+	if err != nil {
+		logger.Error("GetUser failed: ", err)
+		return nil, err
+	}
+	if len(resUserInfo.GetIdentityKeyPublic()) != 33 {
+		return nil, fmt.Errorf("%w: unknown identity key of %v", ErrNoSession, remoteID)
+	}
+	return identity.NewKey(ecc.NewDjbECPublicKey([32]byte(resUserInfo.GetIdentityKeyPublic()[1:]))), nil
+}
+
 /*
 GenerateMLSKeyPackage generates the MLS key package.
 */
@@ -206,7 +236,7 @@ func (client *Client) GetSelfMLSKeyPackage(id uint32) mls.KeyPackage {
 /*
 UploadMLSKeyPackage uploads the MLS key package to the server.
 */
-func (client *Client) UploadMLSKeyPackage(id uint32) bool {
+func (client *Client) UploadMLSKeyPackage(ctx context.Context, id uint32) bool {
 	//logger.Info("Uploading MLS key package for User: ", client.userID)
 	serializedKp, err := util.SerializeMLSKeyPackage(client.user.GetMLSKeyPackage(id))
 	if err != nil {
@@ -215,7 +245,7 @@ func (client *Client) UploadMLSKeyPackage(id uint32) bool {
 	}
 
 	// Upload MLS key package
-	res, err := client.chatServiceClient.UploadMLSKeyPackage(client.chatServiceClientCtx, &pb.UploadMLSKeyPackageRequest{
+	res, err := client.chatServiceClient.UploadMLSKeyPackage(ctx, &pb.UploadMLSKeyPackageRequest{
 		UserID:          client.userID,
 		MlsKeyPackage:   serializedKp,
 		MlsKeyPackageId: id,
@@ -235,11 +265,11 @@ func (client *Client) UploadMLSKeyPackage(id uint32) bool {
 /*
 GetOthersMLSKeyPackage gets the MLS key package of the given recipientID from the server.
 */
-func (client *Client) GetOthersMLSKeyPackage(recipientID string) (mls.KeyPackage, uint32, error) {
+func (client *Client) GetOthersMLSKeyPackage(ctx context.Context, recipientID string) (mls.KeyPackage, uint32, error) {
 	logger.Info("Getting MLS key package for User: ", recipientID)
 
 	// Get others MLS key package
-	res, err := client.chatServiceClient.FetchMLSKeyPackage(client.chatServiceClientCtx, &pb.FetchMLSKeyPackageRequest{
+	res, err := client.chatServiceClient.FetchMLSKeyPackage(ctx, &pb.FetchMLSKeyPackageRequest{
 		UserID: recipientID,
 	})
 	if err != nil {
@@ -270,7 +300,7 @@ func (client *Client) CreateSessionAndDriver(recipientAddress *protocol.SignalAd
 	session := NewClientSessionDriver(client.userID, recipientAddress.Name(), sessionWrapper)
 	client.clientSessionDrivers.Store(recipientAddress.Name(), session)
 
-	session.SetChatServiceClient(&client.chatServiceClient, &client.chatServiceClientCtx)
+	session.SetChatServiceClient(&client.chatServiceClient)
 	return session, nil
 }
 
@@ -281,7 +311,7 @@ func (client *Client) GetSessionDriver(recipientAddress *protocol.SignalAddress)
 	if session, exists := client.clientSessionDrivers.Load(recipientAddress.Name()); exists {
 		return session.(*ClientSessionDriver), nil
 	}
-	return nil, fmt.Errorf("session not found")
+	return nil, fmt.Errorf("%w with %v", ErrNoSession, recipientAddress.Name())
 }
 
 /*
@@ -295,7 +325,7 @@ func (client *Client) CreateServerSideGroupSessionAndDriver(groupID string, grou
 	logger.Info("Creating server-side group session for ", groupID)
 	groupSession := util.NewGroupChatServerSideFanout(client.user, protocol.NewSenderKeyName(groupID, client.address))
 	client.serverSideGroupSessionDrivers[groupID] = NewServerSideGroupSessionDriver(client.userID, groupID, groupSession, groupParticipantIDs, groupChatbotIDs)
-	client.serverSideGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient, &client.chatServiceClientCtx)
+	client.serverSideGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient)
 	client.serverSideGroupSessionDrivers[groupID].SetSendIndividualMessage(client.SendIndividualMessage)
 
 	return client.serverSideGroupSessionDrivers[groupID]
@@ -308,7 +338,7 @@ func (client *Client) GetServerSideGroupSessionDriver(groupID string) (*ServerSi
 	if session, exists := client.serverSideGroupSessionDrivers[groupID]; exists {
 		return session, nil
 	}
-	return nil, fmt.Errorf("server-side group session not found")
+	return nil, fmt.Errorf("%w: no server-side group session for %v", ErrNotInGroup, groupID)
 }
 
 /*
@@ -322,7 +352,7 @@ func (client *Client) CreateClientSideGroupSessionAndDriver(groupID string, grou
 	logger.Info("Creating client-side group session for ", groupID)
 	groupSession := util.NewGroupChatClientSideFanout(client.user, groupID)
 	client.clientSideGroupSessionDrivers[groupID] = NewClientSideGroupSessionDriver(client.userID, groupID, groupSession, groupParticipantIDs, groupChatbotIDs)
-	client.clientSideGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient, &client.chatServiceClientCtx)
+	client.clientSideGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient)
 	client.clientSideGroupSessionDrivers[groupID].SetSendIndividualMessage(client.SendIndividualMessage)
 	return client.clientSideGroupSessionDrivers[groupID]
 }
@@ -334,7 +364,7 @@ func (client *Client) GetClientSideGroupSessionDriver(groupID string) (*ClientSi
 	if session, exists := client.clientSideGroupSessionDrivers[groupID]; exists {
 		return session, nil
 	}
-	return nil, fmt.Errorf("client-side group session not found")
+	return nil, fmt.Errorf("%w: no client-side group session for %v", ErrNotInGroup, groupID)
 }
 
 /*
@@ -348,7 +378,7 @@ func (client *Client) CreateMlsGroupSessionAndDriver(groupID string, groupPartic
 	logger.Info("Creating MLS group session for ", groupID)
 
 	client.mlsGroupSessionDrivers[groupID] = NewMlsGroupSessionDriver(client.userID, groupID, groupParticipantIDs, groupChatbotIDs)
-	client.mlsGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient, &client.chatServiceClientCtx)
+	client.mlsGroupSessionDrivers[groupID].SetChatServiceClient(&client.chatServiceClient)
 	client.mlsGroupSessionDrivers[groupID].SetSendIndividualMessage(client.SendIndividualMessage)
 	return client.mlsGroupSessionDrivers[groupID]
 }
@@ -360,7 +390,30 @@ func (client *Client) GetMlsGroupSessionDriver(groupID string) (*MlsGroupSession
 	if session, exists := client.mlsGroupSessionDrivers[groupID]; exists {
 		return session, nil
 	}
-	return nil, fmt.Errorf("MLS group session not found")
+	return nil, fmt.Errorf("%w: no MLS group session for %v", ErrNotInGroup, groupID)
+}
+
+/*
+GetGroupIDs returns the IDs of the groups of the given type the client is in, in order.
+*/
+func (client *Client) GetGroupIDs(groupType pb.GroupType) []string {
+	var groupIDs []string
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		for groupID := range client.serverSideGroupSessionDrivers {
+			groupIDs = append(groupIDs, groupID)
+		}
+	case pb.GroupType_CLIENT_SIDE:
+		for groupID := range client.clientSideGroupSessionDrivers {
+			groupIDs = append(groupIDs, groupID)
+		}
+	case pb.GroupType_MLS:
+		for groupID := range client.mlsGroupSessionDrivers {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	sort.Strings(groupIDs)
+	return groupIDs
 }
 
 /*
@@ -393,12 +446,12 @@ func (client *Client) SetMlsGroupStateFromEmpty(groupID string, keyPackageId uin
 /*
 SendIndividualMessage sends an individual Message and its MessageType to the given recipientAddress.
 */
-func (client *Client) SendIndividualMessage(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
+func (client *Client) SendIndividualMessage(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
 
 	sessionDriver, exists := client.clientSessionDrivers.Load(recipientAddress.Name())
 	if !exists {
 		logger.Debug("Send individual Message but session not found, creating one: ", recipientAddress.Name())
-		prekeyBundle, err := client.GetOthersPreKeyBundle(recipientAddress.Name())
+		prekeyBundle, err := client.GetOthersPreKeyBundle(ctx, recipientAddress.Name())
 		if err != nil {
 			return err
 		}
@@ -409,13 +462,13 @@ func (client *Client) SendIndividualMessage(recipientAddress *protocol.SignalAdd
 	}
 
 	logger.Info(fmt.Sprintf("Send individual Message to %s: %s", recipientAddress.Name(), messageWrapper.String()))
-	return sessionDriver.(*ClientSessionDriver).SendMessage(messageWrapper)
+	return sessionDriver.(*ClientSessionDriver).SendMessage(ctx, messageWrapper)
 }
 
 /*
 SendServerSideGroupMessage sends a server-side group Message and its MessageType to the given groupID.
 */
-func (client *Client) SendServerSideGroupMessage(groupID string, message *pb.MessageWrapper) error {
+func (client *Client) SendServerSideGroupMessage(ctx context.Context, groupID string, message *pb.MessageWrapper) error {
 	sessionDriver, exist := client.serverSideGroupSessionDrivers[groupID]
 	if !exist {
 		logger.Debug("Send server-side group Message but session not found, creating one: ", groupID)
@@ -423,26 +476,26 @@ func (client *Client) SendServerSideGroupMessage(groupID string, message *pb.Mes
 	}
 
 	logger.Info(fmt.Sprintf("Send server-side group Message to group %s: %s", groupID, message.String()))
-	return sessionDriver.SendMessage(message)
+	return client.sendGroupMessage(ctx, groupID, message, sessionDriver.SendMessage)
 }
 
 /*
 SendClientSideGroupMessage sends a client-side group Message and its MessageType to the given groupID, that is, to all members in pairwise.
 */
-func (client *Client) SendClientSideGroupMessage(groupID string, messages map[string]*pb.MessageWrapper) error {
+func (client *Client) SendClientSideGroupMessage(ctx context.Context, groupID string, messages map[string]*pb.MessageWrapper) error {
 	sessionDriver, exist := client.clientSideGroupSessionDrivers[groupID]
 	if !exist {
 		logger.Info("Send client-side group Message but session not found, creating one: ", groupID)
 		sessionDriver = client.CreateClientSideGroupSessionAndDriver(groupID, []string{}, []string{})
 	}
 
-	return sessionDriver.SendMessage(messages)
+	return sessionDriver.SendMessage(ctx, messages)
 }
 
 /*
 SendMlsGroupMessage sends a MLS group Message and its MessageType to the given groupID.
 */
-func (client *Client) SendMlsGroupMessage(groupID string, message *pb.MessageWrapper) error {
+func (client *Client) SendMlsGroupMessage(ctx context.Context, groupID string, message *pb.MessageWrapper) error {
 	sessionDriver, exist := client.mlsGroupSessionDrivers[groupID]
 	if !exist {
 		logger.Error("Send MLS group Message but session not found: ", groupID)
@@ -450,7 +503,59 @@ func (client *Client) SendMlsGroupMessage(groupID string, message *pb.MessageWra
 	}
 
 	logger.Info(fmt.Sprintf("Send MLS group Message to group %s: %s", groupID, message.String()))
-	return sessionDriver.SendMessage(message)
+	return client.sendGroupMessage(ctx, groupID, message, sessionDriver.SendMessage)
+}
+
+/*
+ResendGroupMessage sends a server-side or MLS group Message again exactly as it was sent before, when it is not known
+whether the server accepted it. The server recognizes the Message by its MessageID and does not fan it out twice.
+*/
+func (client *Client) ResendGroupMessage(ctx context.Context, groupID string, groupType pb.GroupType, message *pb.MessageWrapper) error {
+	var send func(context.Context, *pb.MessageWrapper) error
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return err
+		}
+		send = sessionDriver.SendMessage
+	case pb.GroupType_MLS:
+		sessionDriver, err := client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			return err
+		}
+		send = sessionDriver.SendMessage
+	default:
+		return fmt.Errorf("cannot resend a message to a %v group", groupType)
+	}
+
+	logger.Info(fmt.Sprintf("Resend group Message %v to group %s", message.GetMessageID(), groupID))
+	err := send(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	client.keyUpdateSequence.Advance(groupID, message.GetKeyUpdateSeq())
+	return nil
+}
+
+/*
+sendGroupMessage sends a group Message with the given send function. The Message tells the server the last key update
+the client handled, so that the server can reject a key update issued before a concurrent one it already delivered. It
+has to carry the MessageID it was encrypted with, by which it can be sent again with ResendGroupMessage.
+*/
+func (client *Client) sendGroupMessage(ctx context.Context, groupID string, message *pb.MessageWrapper, send func(context.Context, *pb.MessageWrapper) error) error {
+	if err := requireMessageID(message); err != nil {
+		return err
+	}
+	message.KeyUpdateBase = client.keyUpdateSequence.Get(groupID)
+	err := send(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	client.keyUpdateSequence.Advance(groupID, message.GetKeyUpdateSeq())
+	return nil
 }
 
 /*
@@ -493,12 +598,12 @@ func (client *Client) ParseSenderKeyMessage(messageRaw []byte) (*protocol.Sender
 /*
 ParseClientSideGroupMessage parses the incoming client-side group Message.
 */
-func (client *Client) ParseClientSideGroupMessage(clientSideGroupMessageRaw []byte, senderID string) (string, []byte, pb.MessageType) {
+func (client *Client) ParseClientSideGroupMessage(clientSideGroupMessageRaw []byte, senderID string) (string, []byte, pb.MessageType, error) {
 	clientSideGroupMessage := &pb.ClientSideGroupMessage{}
 	err := proto.Unmarshal(clientSideGroupMessageRaw, clientSideGroupMessage)
 	if err != nil {
 		logger.Error("Failed to decode client side group Message: ", err)
-		panic("")
+		return "", nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	session, exist := client.clientSideGroupSessionDrivers[clientSideGroupMessage.GroupID]
@@ -511,7 +616,7 @@ func (client *Client) ParseClientSideGroupMessage(clientSideGroupMessageRaw []by
 
 	logger.Info(fmt.Sprintf("Received Message from %v in client-side group %v: %v", senderID, clientSideGroupMessage.GroupID, string(groupMessage)))
 
-	return clientSideGroupMessage.GroupID, groupMessage, groupMessageType
+	return clientSideGroupMessage.GroupID, groupMessage, groupMessageType, nil
 }
 
 /*
@@ -564,3 +669,24 @@ GetUserID returns the userID.
 func (client *Client) GetUserID() string {
 	return client.userID
 }
+
+/*
+GetPendingKeyUpdates returns the buffer of messages whose key updates arrived ahead of their epoch.
+*/
+func (client *Client) GetPendingKeyUpdates() *PendingKeyUpdates {
+	return client.pendingKeyUpdates
+}
+
+/*
+GetKeyUpdateSequence returns the sequence numbers of the last key updates handled in the groups.
+*/
+func (client *Client) GetKeyUpdateSequence() *KeyUpdateSequence {
+	return client.keyUpdateSequence
+}
+
+/*
+GetReplayWindow returns the messages received last, by which the messages delivered again are rejected.
+*/
+func (client *Client) GetReplayWindow() *ReplayWindow {
+	return client.replayWindow
+}
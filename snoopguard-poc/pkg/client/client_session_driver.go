@@ -4,7 +4,8 @@ import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/util"
 	"context"
-	"fmt"
+	"errors"
+	"go.mau.fi/libsignal/keys/identity"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -18,8 +19,7 @@ type ClientSessionDriver struct {
 	recipientID string
 	session     *util.SessionWrapper
 
-	chatServiceClient    *pb.ChatServiceClient
-	chatServiceClientCtx *context.Context
+	chatServiceClient *pb.ChatServiceClient
 }
 
 /*
@@ -35,18 +35,24 @@ func NewClientSessionDriver(userID string, recipientID string, session *util.Ses
 }
 
 /*
-SetChatServiceClient inject the chatServiceClient and chatServiceClientCtx into the ClientSessionDriver.
+SetChatServiceClient inject the chatServiceClient into the ClientSessionDriver.
 */
-func (ClientSessionDriver *ClientSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient, chatServiceClientCtx *context.Context) {
+func (ClientSessionDriver *ClientSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient) {
 	ClientSessionDriver.chatServiceClient = chatServiceClient
-	ClientSessionDriver.chatServiceClientCtx = chatServiceClientCtx
 }
 
-func (ClientSessionDriver *ClientSessionDriver) SendMessage(messageWrapper *pb.MessageWrapper) error {
+/*
+SendMessage sends an individual Message to the server. It has to carry the MessageID it was encrypted with, so that it is
+not delivered twice if it is sent again.
+*/
+func (ClientSessionDriver *ClientSessionDriver) SendMessage(ctx context.Context, messageWrapper *pb.MessageWrapper) error {
+	if err := requireMessageID(messageWrapper); err != nil {
+		return err
+	}
 	logger.Debug("Sending individual Message: ", messageWrapper.String())
 
 	// Send Message to server
-	res, err := (*ClientSessionDriver.chatServiceClient).SendMessage(*ClientSessionDriver.chatServiceClientCtx, messageWrapper)
+	res, err := (*ClientSessionDriver.chatServiceClient).SendMessage(ctx, messageWrapper)
 	if err != nil {
 		logger.Error("Error sending Message to server: ", err)
 		return err
@@ -54,7 +60,7 @@ func (ClientSessionDriver *ClientSessionDriver) SendMessage(messageWrapper *pb.M
 
 	if res.ErrorMessage != "" {
 		logger.Error("Error sending Message to server: ", res.ErrorMessage)
-		return fmt.Errorf(res.ErrorMessage)
+		return errors.New(res.ErrorMessage)
 	}
 	return nil
 }
@@ -64,18 +70,36 @@ func (ClientSessionDriver *ClientSessionDriver) EncryptMessage(messageRaw []byte
 
 }
 
-func (ClientSessionDriver *ClientSessionDriver) DecryptMessage(senderID string, recipientID string, encryptedMessage []byte, hasPreKey bool) ([]byte, pb.MessageType, error) {
-	decryptedMessage, err := ClientSessionDriver.session.DecryptMsg(ClientSessionDriver.session.ParseRawMessage(encryptedMessage, hasPreKey))
+/*
+GetRemoteIdentityKey returns the identity key of the recipient the session was established with, or nil if the session
+is not established yet.
+*/
+func (ClientSessionDriver *ClientSessionDriver) GetRemoteIdentityKey() *identity.Key {
+	return ClientSessionDriver.session.RemoteIdentityKey()
+}
+
+/*
+DecryptMessage decrypts an individual Message, which has to be encrypted with the given MessageID.
+*/
+func (ClientSessionDriver *ClientSessionDriver) DecryptMessage(senderID string, recipientID string, messageID string, encryptedMessage []byte, hasPreKey bool) ([]byte, pb.MessageType, error) {
+	ciphertextMessage, err := ClientSessionDriver.session.ParseRawMessage(encryptedMessage, hasPreKey)
+	if err != nil {
+		return nil, -1, err
+	}
+	decryptedMessage, err := ClientSessionDriver.session.DecryptMsg(ciphertextMessage)
 	if err != nil {
 		logger.Error("Error decrypting Message: ", err)
 		return nil, -1, err
 	}
 	logger.Debug("Decrypted Message: ", decryptedMessage)
 	message := &pb.Message{}
-	if proto.Unmarshal(decryptedMessage, message) != nil {
+	if err := proto.Unmarshal(decryptedMessage, message); err != nil {
 		logger.Error("Error unmarshalling Message")
 		return nil, -1, err
 	}
+	if err := checkMessageID(message, messageID); err != nil {
+		return nil, -1, err
+	}
 
 	return message.Message, message.MessageType, nil
 }
@@ -22,11 +22,11 @@ type ClientSideGroupSessionDriver struct {
 	treekemState         *treekem.TreeKEMState
 	multiTreeKEM         *treekem.MultiTreeKEM
 	multiTreeKEMExternal *treekem.MultiTreeKEMExternal
+	cipherSuite          treekem.CipherSuite
 
-	sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
+	sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
 
-	chatServiceClient    *pb.ChatServiceClient
-	chatServiceClientCtx *context.Context
+	chatServiceClient *pb.ChatServiceClient
 }
 
 /*
@@ -41,7 +41,7 @@ func NewClientSideGroupSessionDriver(userID string, groupID string, groupChatHan
 		groupChatbots:     groupChatbots,
 		chatbotIsIGA:      make(map[string]bool),
 		chatbotIsPseudo:   make(map[string]bool),
-		sendIndividualMessage: func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
+		sendIndividualMessage: func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
 			// Print not implemented error
 			logger.Error("Not implemented: sendIndividualMessage")
 			return fmt.Errorf("not implemented: sendIndividualMessage")
@@ -52,17 +52,16 @@ func NewClientSideGroupSessionDriver(userID string, groupID string, groupChatHan
 }
 
 /*
-SetChatServiceClient inject the chatServiceClient and chatServiceClientCtx into the ClientSideGroupSessionDriver.
+SetChatServiceClient inject the chatServiceClient into the ClientSideGroupSessionDriver.
 */
-func (csgsd *ClientSideGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient, chatServiceClientCtx *context.Context) {
+func (csgsd *ClientSideGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient) {
 	csgsd.chatServiceClient = chatServiceClient
-	csgsd.chatServiceClientCtx = chatServiceClientCtx
 }
 
 /*
 SetSendIndividualMessage inject the sendIndividualMessage function into the ClientSideGroupSessionDriver.
 */
-func (csgsd *ClientSideGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
+func (csgsd *ClientSideGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
 	csgsd.sendIndividualMessage = sendIndividualMessage
 }
 
@@ -105,9 +104,10 @@ func (csgsd *ClientSideGroupSessionDriver) SetChatbotIsPseudo(chatbotId string,
 }
 
 /*
-SendMessage creates an ClientSideGroupMessage and sends it using sendIndividualMessage.
+SendMessage creates an ClientSideGroupMessage and sends it using sendIndividualMessage. The copies of the Message all
+carry the same MessageID.
 */
-func (csgsd *ClientSideGroupSessionDriver) SendMessage(messages map[string]*pb.MessageWrapper) error {
+func (csgsd *ClientSideGroupSessionDriver) SendMessage(ctx context.Context, messages map[string]*pb.MessageWrapper) error {
 	// Send it to all participants
 	for _, pid := range csgsd.groupParticipants {
 		if pid == csgsd.userID {
@@ -123,7 +123,7 @@ func (csgsd *ClientSideGroupSessionDriver) SendMessage(messages map[string]*pb.M
 		recipientAddress := protocol.NewSignalAddress(pid, 1)
 
 		// Send Message
-		err := csgsd.sendIndividualMessage(recipientAddress, message)
+		err := csgsd.sendIndividualMessage(ctx, recipientAddress, message)
 		if err != nil {
 			return err
 		}
@@ -144,7 +144,7 @@ func (csgsd *ClientSideGroupSessionDriver) SendMessage(messages map[string]*pb.M
 		recipientAddress := protocol.NewSignalAddress(pid, 1)
 
 		// Send Message
-		err := csgsd.sendIndividualMessage(recipientAddress, message)
+		err := csgsd.sendIndividualMessage(ctx, recipientAddress, message)
 		if err != nil {
 			return err
 		}
@@ -182,12 +182,39 @@ func (csgsd *ClientSideGroupSessionDriver) RemoveUserSession(removedID string) {
 	// NOP
 }
 
+/*
+GetCipherSuite returns the cipher suite the CMRT of the group runs with.
+*/
+func (csgsd *ClientSideGroupSessionDriver) GetCipherSuite() treekem.CipherSuite {
+	if csgsd.cipherSuite == nil {
+		return treekem.MustCipherSuite(treekem.DefaultCipherSuite)
+	}
+	return csgsd.cipherSuite
+}
+
+/*
+SetCipherSuite sets the cipher suite negotiated for the group. It must be set before the CMRT is initiated.
+*/
+func (csgsd *ClientSideGroupSessionDriver) SetCipherSuite(id treekem.CipherSuiteID) error {
+	suite, err := treekem.CipherSuiteByID(id)
+	if err != nil {
+		return err
+	}
+	csgsd.cipherSuite = suite
+	return nil
+}
+
 /*
 InitiateTreeKEM initiates the TreeKEM.
 */
 func (csgsd *ClientSideGroupSessionDriver) InitiateTreeKEM(gik treekem.GroupInitKey, initLeaf []byte) error {
 	logger.Info("Initiating TreeKEM for group: ", csgsd.groupID)
 
+	if err := csgsd.SetCipherSuite(gik.CipherSuite); err != nil {
+		logger.Error("Error initiating TreeKEM: ", err)
+		return err
+	}
+
 	if gik.Size != 0 {
 		// Reconstruct an existing TreeKEM
 		logger.Info("Reconstructing TreeKEM from GroupInitKey")
@@ -200,7 +227,8 @@ func (csgsd *ClientSideGroupSessionDriver) InitiateTreeKEM(gik treekem.GroupInit
 	} else {
 		// Create a new TreeKEM
 		logger.Info("Creating new TreeKEM")
-		csgsd.treekemState = treekem.TreeKEMStateOneMemberGroup(initLeaf)
+		csgsd.treekemState = treekem.TreeKEMStateOneMemberGroupWithCipherSuite(csgsd.GetCipherSuite(), initLeaf)
+		csgsd.treekemState.SetGroupID(csgsd.groupID)
 	}
 	return nil
 }
@@ -283,6 +311,39 @@ func (csgsd *ClientSideGroupSessionDriver) AddExternalNodeToMultiTreeKEM(id stri
 	return csgsd.multiTreeKEM.AddExternalNode(id, ct)
 }
 
+/*
+RemoveExternalNodeFromMultiTreeKEM removes an external node from the MultiTreeKEM.
+*/
+func (csgsd *ClientSideGroupSessionDriver) RemoveExternalNodeFromMultiTreeKEM(id string) error {
+	return csgsd.multiTreeKEM.RemoveExternalNode(id)
+}
+
+/*
+SetChatbotHybridKEM sets whether the TreeKEM ciphertexts to and from the chatbot use the hybrid ML-KEM-768 + X25519 KEM.
+It has to be set before the chatbot is added to the MultiTreeKEM.
+*/
+func (csgsd *ClientSideGroupSessionDriver) SetChatbotHybridKEM(chatbotId string, hybridKEM bool) {
+	csgsd.multiTreeKEM.SetExternalNodeHybridKEM(chatbotId, hybridKEM)
+}
+
+/*
+GetChatbotHybridKEM returns whether the TreeKEM ciphertexts to and from the chatbot use the hybrid KEM.
+*/
+func (csgsd *ClientSideGroupSessionDriver) GetChatbotHybridKEM(chatbotId string) bool {
+	return csgsd.multiTreeKEM.IsExternalNodeHybridKEM(chatbotId)
+}
+
+/*
+GetTreeKEMRootHybridPublicKey returns the hybrid public key of the TreeKEM root, or nil if no chatbot of the group uses
+the hybrid KEM.
+*/
+func (csgsd *ClientSideGroupSessionDriver) GetTreeKEMRootHybridPublicKey() ([]byte, error) {
+	if !csgsd.multiTreeKEM.HasHybridKEMExternalNode() {
+		return nil, nil
+	}
+	return csgsd.multiTreeKEM.GetTreeKEMRootHybridPublic()
+}
+
 /*
 GetTreeKEMState returns the TreeKEM state.
 */
@@ -301,7 +362,8 @@ func (csgsd *ClientSideGroupSessionDriver) GetMultiTreeKEM() *treekem.MultiTreeK
 InitiateMultiTreeKEMExternal initiates the MultiTreeKEMExternal.
 */
 func (csgsd *ClientSideGroupSessionDriver) InitiateMultiTreeKEMExternal(treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte) error {
-	csgsd.multiTreeKEMExternal = treekem.NewMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf)
+	csgsd.multiTreeKEMExternal = treekem.NewMultiTreeKEMExternalWithCipherSuite(csgsd.GetCipherSuite(), treekemRootPub, treekemRootSignPub, initLeaf)
+	csgsd.multiTreeKEMExternal.SetGroupID(csgsd.groupID)
 	return nil
 }
 
@@ -320,15 +382,15 @@ func (csgsd *ClientSideGroupSessionDriver) GenerateMultiTreeKEMExternalKeyUpdate
 }
 
 /*
-HandleMultiTreeKEMExternalKeyUpdate handles the key update from the MultiTreeKEMExternal.
+HandleMultiTreeKEMExternalKeyUpdate handles the key update from the MultiTreeKEMExternal, which moves its root to the given epoch.
 */
-func (csgsd *ClientSideGroupSessionDriver) HandleMultiTreeKEMExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte) error {
-	return csgsd.multiTreeKEM.HandleExternalNodeUpdate(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+func (csgsd *ClientSideGroupSessionDriver) HandleMultiTreeKEMExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return csgsd.multiTreeKEM.HandleExternalNodeUpdateAtEpoch(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey, epoch, transcriptHash)
 }
 
 /*
-HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MultiTreeKEM.
+HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MultiTreeKEM, which moves the root to the given epoch.
 */
-func (csgsd *ClientSideGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
-	return csgsd.multiTreeKEMExternal.HandleTreeKEMUpdate(updateMessage, newPubKey, newSignPubKey)
+func (csgsd *ClientSideGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return csgsd.multiTreeKEMExternal.HandleTreeKEMUpdateAtEpoch(updateMessage, newPubKey, newSignPubKey, epoch, transcriptHash)
 }
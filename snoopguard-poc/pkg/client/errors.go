@@ -0,0 +1,35 @@
+package client
+
+import (
+	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
+	"errors"
+)
+
+// Errors returned when the client fails to handle a message or a membership change. Callers tell them apart with
+// errors.Is, as they are wrapped with the details of the failure.
+var (
+	// ErrNotInGroup is returned when the client has no session for the group.
+	ErrNotInGroup = errors.New("not in group")
+	// ErrNoSession is returned when the client has no pairwise session with the user or chatbot.
+	ErrNoSession = errors.New("no session")
+	// ErrDesync is returned when a key update does not follow the local state of the group.
+	ErrDesync = treekem.ErrDesync
+	// ErrBadSignature is returned when a message or key update is not signed by its sender.
+	ErrBadSignature = util.ErrBadSignature
+	// ErrUnknownPseudonym is returned when a message is sent under a pseudonym that was not registered.
+	ErrUnknownPseudonym = errors.New("unknown pseudonym")
+	// ErrUndecryptable is returned when a message cannot be decrypted or decoded.
+	ErrUndecryptable = errors.New("undecryptable message")
+	// ErrReplayedMessage is returned when a message was already received, and is dropped.
+	ErrReplayedMessage = errors.New("replayed message")
+	// ErrInvalidMessageID is returned when a message has no MessageID, or not the one it was encrypted with.
+	ErrInvalidMessageID = errors.New("invalid message ID")
+)
+
+// ErrMessageRejected is returned when the server refuses a group message for another reason than a key update conflict,
+// so that it was not delivered to anyone.
+var ErrMessageRejected = errors.New("message rejected")
+
+// ErrRequestRejected is returned when the server refuses a request to change the members or chatbots of a group.
+var ErrRequestRejected = errors.New("request rejected")
@@ -0,0 +1,44 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+)
+
+/*
+HandlingError reports an incoming message or server event the client failed to handle. A message whose key update
+failed may still have been output.
+*/
+type HandlingError struct {
+	// GroupID is the group of the message or event, and is empty for individual messages.
+	GroupID   string
+	SenderID  string
+	MessageID string
+	// Event is the server event that failed, and is nil for messages.
+	Event *pb.ServerEvent
+	Err   error
+}
+
+func (e *HandlingError) Error() string {
+	if e.Event != nil {
+		return fmt.Sprintf("failed to handle %v in group %v: %v", e.Event.GetEventType(), e.GroupID, e.Err)
+	}
+	return fmt.Sprintf("failed to handle message %v from %v: %v", e.MessageID, e.SenderID, e.Err)
+}
+
+func (e *HandlingError) Unwrap() error {
+	return e.Err
+}
+
+/*
+ReportError sends the error to the error channel. The error is dropped if nobody reads the channel and it is full, so
+that an unread channel never blocks the handling of messages.
+*/
+func ReportError(errorChan chan error, handlingError *HandlingError) {
+	select {
+	case errorChan <- handlingError:
+	default:
+		logger.Warning("Dropped error as the error channel is full: ", handlingError)
+	}
+}
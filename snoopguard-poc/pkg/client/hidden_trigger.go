@@ -0,0 +1,126 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"encoding/binary"
+	"fmt"
+	"go.mau.fi/libsignal/ecc"
+	"go.mau.fi/libsignal/keys/identity"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+HiddenTriggerNotForYou is the message body reported to a chatbot when a hidden-trigger message was not addressed to it.
+*/
+var HiddenTriggerNotForYou = []byte("Not for you")
+
+/*
+hiddenTriggerTBS returns the bytes the sender signs a hidden-trigger message on: the group, the chatbot it is sealed for
+and the message without its signature.
+*/
+func hiddenTriggerTBS(groupID string, chatbotID string, message *pb.Message) ([]byte, error) {
+	unsigned := proto.Clone(message).(*pb.Message)
+	unsigned.Signature = nil
+
+	messageBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs := []byte("snoopguard hidden trigger")
+	tbs = binary.BigEndian.AppendUint32(tbs, uint32(len(groupID)))
+	tbs = append(tbs, groupID...)
+	tbs = binary.BigEndian.AppendUint32(tbs, uint32(len(chatbotID)))
+	tbs = append(tbs, chatbotID...)
+	return append(tbs, messageBytes...), nil
+}
+
+/*
+sealHiddenTriggerMessage signs the given message with the identity key of the sender and seals it for a single non-IGA
+chatbot under the chatbot's external node key. The chatbot knows the sender anyway, and the signature is sealed with the
+message, so only the chatbot sees it. If the chatbot is not triggered, a message of the same length is sealed to a
+throwaway key instead, so that the server cannot tell the two cases apart and the chatbot learns nothing but that the
+message was not for it. Chatbots using the hybrid KEM are sealed to with the hybrid KEM, dummies included.
+*/
+func sealHiddenTriggerMessage(groupID string, chatbotID string, suite treekem.CipherSuite, messageRaw []byte, messageType pb.MessageType, messageID string, senderIdentityKey *identity.KeyPair, chatbotPubKey []byte, hybridKEM bool, triggered bool) ([]byte, error) {
+	message := &pb.Message{
+		Message:     messageRaw,
+		MessageType: messageType,
+		MessageID:   messageID,
+	}
+	tbs, err := hiddenTriggerTBS(groupID, chatbotID, message)
+	if err != nil {
+		return nil, err
+	}
+	signature := ecc.CalculateSignature(senderIdentityKey.PrivateKey(), tbs)
+	message.Signature = signature[:]
+
+	messageMarshal, err := proto.Marshal(message)
+	if err != nil {
+		logger.Error("Error marshalling Message: ", err)
+		return nil, err
+	}
+
+	var ct treekem.ECKEMCipherText
+	if triggered {
+		if chatbotPubKey == nil {
+			return nil, fmt.Errorf("no external node key for the chatbot")
+		}
+		if hybridKEM {
+			ct, err = treekem.HybridECKEMEncrypt(suite, messageMarshal, chatbotPubKey, treekem.ExternalNodeContext(groupID))
+		} else {
+			ct, err = treekem.ECKEMEncrypt(suite, messageMarshal, chatbotPubKey, treekem.ExternalNodeContext(groupID))
+		}
+	} else if hybridKEM {
+		ct, err = treekem.HybridECKEMEncryptDummy(suite, len(messageMarshal))
+	} else {
+		ct, err = treekem.ECKEMEncryptDummy(suite, len(messageMarshal))
+	}
+	if err != nil {
+		logger.Error("Error sealing hidden trigger message: ", err)
+		return nil, err
+	}
+
+	return proto.Marshal(treekem.ECKEMCipherTextPbConvert(&ct))
+}
+
+/*
+openHiddenTriggerMessage opens a message sealed by sealHiddenTriggerMessage and checks that it is signed by the identity
+key of the sender and encrypted with the given MessageID. Any message that cannot be opened by the chatbot's external
+node key is reported as a SKIP message carrying HiddenTriggerNotForYou, and a message that is not signed by the sender
+is rejected with ErrBadSignature.
+*/
+func openHiddenTriggerMessage(groupID string, chatbotID string, suite treekem.CipherSuite, encryptedMessageRaw []byte, chatbotPrivKey []byte, senderIdentityKey *identity.Key, messageID string) ([]byte, pb.MessageType, error) {
+	ctPb := &pb.ECKEMCipherText{}
+	if err := proto.Unmarshal(encryptedMessageRaw, ctPb); err != nil {
+		logger.Error("Failed to decode hidden trigger message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+
+	decryptedMessage, err := treekem.ECKEMDecrypt(suite, treekem.PbECKEMCipherTextConvert(ctPb), chatbotPrivKey, treekem.ExternalNodeContext(groupID))
+	if err != nil {
+		logger.Debug("Hidden trigger message is not for this chatbot.")
+		return HiddenTriggerNotForYou, pb.MessageType_SKIP, nil
+	}
+
+	packedMessage := &pb.Message{}
+	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
+		logger.Error("Failed to decode Message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+
+	tbs, err := hiddenTriggerTBS(groupID, chatbotID, packedMessage)
+	if err != nil {
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if len(packedMessage.GetSignature()) != 64 || !ecc.VerifySignature(senderIdentityKey.PublicKey(), tbs, [64]byte(packedMessage.GetSignature())) {
+		return nil, -1, fmt.Errorf("%w: hidden trigger message in group %v", ErrBadSignature, groupID)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, err
+	}
+
+	return packedMessage.Message, packedMessage.MessageType, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"fmt"
+	"go.mau.fi/libsignal/ecc"
+	"go.mau.fi/libsignal/logger"
+	"go.mau.fi/libsignal/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+SignTreeKEMKeyUpdatePack returns a copy of the key update pack signed with the identity key of the user. Only packs for
+recipients who know the sender anyway, i.e. the members and the chatbots without IGA, may be signed.
+*/
+func (client *Client) SignTreeKEMKeyUpdatePack(groupID string, pack *pb.TreeKEMKeyUpdatePack) (*pb.TreeKEMKeyUpdatePack, error) {
+	signed := proto.Clone(pack).(*pb.TreeKEMKeyUpdatePack)
+
+	tbs, err := treekem.KeyUpdatePackTBS(groupID, signed)
+	if err != nil {
+		return nil, err
+	}
+	sig := ecc.CalculateSignature(client.GetIdentityKey().PrivateKey(), tbs)
+	signed.Signature = sig[:]
+
+	return signed, nil
+}
+
+/*
+VerifyTreeKEMKeyUpdatePackSignature checks that the key update pack is signed by the identity key of the sender. The
+identity key is taken from the pairwise session with the sender, so the server cannot substitute it.
+*/
+func (client *Client) VerifyTreeKEMKeyUpdatePackSignature(groupID string, senderID string, pack *pb.TreeKEMKeyUpdatePack) error {
+	if len(pack.GetSignature()) != 64 {
+		return fmt.Errorf("%w: missing signature from %v", treekem.ErrUnauthenticatedKeyUpdate, senderID)
+	}
+
+	sessionDriver, err := client.GetSessionDriver(protocol.NewSignalAddress(senderID, 1))
+	if err != nil {
+		logger.Error("No session with the sender of the key update: ", senderID)
+		return fmt.Errorf("%w: no session with %v", treekem.ErrUnauthenticatedKeyUpdate, senderID)
+	}
+	identityKey := sessionDriver.GetRemoteIdentityKey()
+	if identityKey == nil {
+		return fmt.Errorf("%w: unknown identity key of %v", treekem.ErrUnauthenticatedKeyUpdate, senderID)
+	}
+
+	tbs, err := treekem.KeyUpdatePackTBS(groupID, pack)
+	if err != nil {
+		return err
+	}
+	if !ecc.VerifySignature(identityKey.PublicKey(), tbs, [64]byte(pack.GetSignature())) {
+		return fmt.Errorf("%w: %w from %v", treekem.ErrUnauthenticatedKeyUpdate, ErrBadSignature, senderID)
+	}
+
+	return nil
+}
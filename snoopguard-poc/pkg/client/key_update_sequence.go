@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"sync"
+	"time"
+)
+
+// ErrKeyUpdateConflict is returned when the server rejects a key update because it ordered a concurrent key update of
+// the group before it.
+var ErrKeyUpdateConflict = errors.New("key update conflict")
+
+// MaxKeyUpdateConflictRetries is the number of times a group message is issued again after its key update lost
+// against a concurrent one.
+const MaxKeyUpdateConflictRetries = 8
+
+// KeyUpdateConflictTimeout is how long a sender waits to handle the key update its own one lost against.
+const KeyUpdateConflictTimeout = 10 * time.Second
+
+/*
+KeyUpdateConflictError describes a key update the server rejected, because the sender issued it before handling every
+key update the server had already delivered to it. The sender has to roll its own update back, handle the key updates
+up to KeyUpdateSeq, and issue the update again.
+*/
+type KeyUpdateConflictError struct {
+	GroupID      string
+	KeyUpdateSeq uint64
+}
+
+/*
+Error returns the description of the conflict.
+*/
+func (e *KeyUpdateConflictError) Error() string {
+	return fmt.Sprintf("%v in group %v: key update %v was not handled yet", ErrKeyUpdateConflict, e.GroupID, e.KeyUpdateSeq)
+}
+
+/*
+Is reports whether the target is ErrKeyUpdateConflict.
+*/
+func (e *KeyUpdateConflictError) Is(target error) bool {
+	return target == ErrKeyUpdateConflict
+}
+
+/*
+KeyUpdateSequence tracks, for every group, the sequence number of the last key update the client handled. The server
+assigns the sequence numbers in the order it fans the key updates out, so every member applies them in the same order.
+*/
+type KeyUpdateSequence struct {
+	seqs    map[string]uint64
+	waiters map[string][]chan struct{}
+	mutex   sync.Mutex
+}
+
+/*
+NewKeyUpdateSequence creates a KeyUpdateSequence that has not handled any key update yet.
+*/
+func NewKeyUpdateSequence() *KeyUpdateSequence {
+	return &KeyUpdateSequence{
+		seqs:    make(map[string]uint64),
+		waiters: make(map[string][]chan struct{}),
+	}
+}
+
+/*
+Get returns the sequence number of the last key update handled in the group.
+*/
+func (k *KeyUpdateSequence) Get(groupID string) uint64 {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.seqs[groupID]
+}
+
+/*
+Advance records that the key update with the given sequence number was handled in the group, and wakes up everyone
+waiting for it. Sequence numbers that are not ahead of the current one are ignored.
+*/
+func (k *KeyUpdateSequence) Advance(groupID string, seq uint64) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if seq <= k.seqs[groupID] {
+		return
+	}
+	k.seqs[groupID] = seq
+	for _, waiter := range k.waiters[groupID] {
+		close(waiter)
+	}
+	delete(k.waiters, groupID)
+}
+
+/*
+Wait blocks until the key update with the given sequence number was handled in the group. It returns false if that did
+not happen within the timeout or before ctx is done.
+*/
+func (k *KeyUpdateSequence) Wait(ctx context.Context, groupID string, seq uint64, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		k.mutex.Lock()
+		if k.seqs[groupID] >= seq {
+			k.mutex.Unlock()
+			return true
+		}
+		waiter := make(chan struct{})
+		k.waiters[groupID] = append(k.waiters[groupID], waiter)
+		k.mutex.Unlock()
+
+		select {
+		case <-waiter:
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+/*
+RetryConflicts sends a group message that carries a key update. The server orders the key updates of a group first
+come, first served: a key update issued before the sender handled every key update already delivered to it is rejected
+with a KeyUpdateConflictError. send has to roll the rejected update back; RetryConflicts then waits until the key update
+it lost against was handled, and calls send again to issue the message on top of it. It gives up and returns the
+conflict after MaxKeyUpdateConflictRetries retries, or if the key update is not handled within
+KeyUpdateConflictTimeout.
+*/
+func (k *KeyUpdateSequence) RetryConflicts(ctx context.Context, groupID string, send func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := send()
+
+		var conflictErr *KeyUpdateConflictError
+		if !errors.As(err, &conflictErr) || attempt >= MaxKeyUpdateConflictRetries {
+			return err
+		}
+
+		logger.Warning("Key update in group ", groupID, " lost against a concurrent one, sending again once key update ", conflictErr.KeyUpdateSeq, " is handled")
+		if !k.Wait(ctx, groupID, conflictErr.KeyUpdateSeq, KeyUpdateConflictTimeout) {
+			return err
+		}
+	}
+}
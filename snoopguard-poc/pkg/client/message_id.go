@@ -0,0 +1,106 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/util"
+	"fmt"
+	"sync"
+)
+
+// ReplayWindowSize is how many received messages the client remembers to reject the copies delivered again.
+const ReplayWindowSize = 4096
+
+/*
+NewMessageID generates the MessageID a sender gives a message. The server recognizes a message sent again by it, and
+the recipients a message delivered again. The sender chooses it before encrypting the message, as it is encrypted along
+with it.
+*/
+func NewMessageID() string {
+	return util.RandomString(16)
+}
+
+/*
+requireMessageID returns an error wrapping ErrInvalidMessageID if the sender did not give the Message a MessageID, which
+the recipients would reject.
+*/
+func requireMessageID(message *pb.MessageWrapper) error {
+	if message.GetMessageID() == "" {
+		return fmt.Errorf("%w: message to %v has no MessageID", ErrInvalidMessageID, message.GetRecipientID())
+	}
+	return nil
+}
+
+/*
+checkMessageID returns an error wrapping ErrInvalidMessageID unless the decrypted Message was encrypted with the
+MessageID its MessageWrapper carries. Otherwise, the server could give a message delivered again a new MessageID to get
+it past the ReplayWindow.
+*/
+func checkMessageID(message *pb.Message, messageID string) error {
+	if message.GetMessageID() != messageID {
+		return fmt.Errorf("%w: message %v was encrypted as %v", ErrInvalidMessageID, messageID, message.GetMessageID())
+	}
+	return nil
+}
+
+/*
+receivedMessageKey identifies a received message by its sender, its recipient and its MessageID.
+*/
+type receivedMessageKey struct {
+	senderID    string
+	recipientID string
+	messageID   string
+}
+
+/*
+ReplayWindow remembers the last ReplayWindowSize messages the client received, so that a message delivered again, e.g.
+after a retry or when a stream resumes, is not handled twice. Handling a message twice breaks the Sender Keys ratchets
+and the MLS commits.
+*/
+type ReplayWindow struct {
+	received map[receivedMessageKey]bool
+	// order holds the received messages oldest first.
+	order []receivedMessageKey
+	mutex sync.Mutex
+}
+
+/*
+NewReplayWindow creates a ReplayWindow that has not received any message yet.
+*/
+func NewReplayWindow() *ReplayWindow {
+	return &ReplayWindow{
+		received: make(map[receivedMessageKey]bool),
+	}
+}
+
+/*
+Check records that the Message was received, and returns an error wrapping ErrReplayedMessage if it was received
+before. A Message without a MessageID cannot be told apart from others and is rejected with ErrInvalidMessageID.
+*/
+func (r *ReplayWindow) Check(message *pb.MessageWrapper) error {
+	if message.GetMessageID() == "" {
+		return fmt.Errorf("%w: message from %v to %v has no MessageID", ErrInvalidMessageID, message.GetSenderID(), message.GetRecipientID())
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := receivedMessageKey{message.GetSenderID(), message.GetRecipientID(), message.GetMessageID()}
+	if r.received[key] {
+		return fmt.Errorf("%w: message %v from %v to %v", ErrReplayedMessage, key.messageID, key.senderID, key.recipientID)
+	}
+	r.add(key)
+	return nil
+}
+
+/*
+add remembers a received message, and forgets the oldest one once ReplayWindowSize messages are remembered. The caller
+must hold the mutex.
+*/
+func (r *ReplayWindow) add(key receivedMessageKey) {
+	r.received[key] = true
+	r.order = append(r.order, key)
+	if len(r.order) > ReplayWindowSize {
+		delete(r.received, r.order[0])
+		r.order = r.order[1:]
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/s3131212/go-mls"
+	"go.mau.fi/libsignal/keys/identity"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -27,11 +28,11 @@ type MlsGroupSessionDriver struct {
 	memberToLeafIndex    map[string]uint32
 	mlsMultiTree         *treekem.MlsMultiTree
 	mlsMultiTreeExternal *treekem.MlsMultiTreeExternal
+	cipherSuite          treekem.CipherSuite
 
-	sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
+	sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
 
-	chatServiceClient    *pb.ChatServiceClient
-	chatServiceClientCtx *context.Context
+	chatServiceClient *pb.ChatServiceClient
 }
 
 /*
@@ -47,7 +48,7 @@ func NewMlsGroupSessionDriver(userID string, groupID string, groupParticipants [
 		chatbotIsPseudo:   make(map[string]bool),
 		groupChatState:    nil,
 		memberToLeafIndex: make(map[string]uint32),
-		sendIndividualMessage: func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
+		sendIndividualMessage: func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
 			// Print not implemented error
 			logger.Error("Not implemented: sendIndividualMessage")
 			return fmt.Errorf("not implemented: sendIndividualMessage")
@@ -58,17 +59,16 @@ func NewMlsGroupSessionDriver(userID string, groupID string, groupParticipants [
 }
 
 /*
-SetChatServiceClient inject the chatServiceClient and chatServiceClientCtx into the ServerSideGroupSessionDriver.
+SetChatServiceClient inject the chatServiceClient into the ServerSideGroupSessionDriver.
 */
-func (mgsd *MlsGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient, chatServiceClientCtx *context.Context) {
+func (mgsd *MlsGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient) {
 	mgsd.chatServiceClient = chatServiceClient
-	mgsd.chatServiceClientCtx = chatServiceClientCtx
 }
 
 /*
 SetSendIndividualMessage inject the sendIndividualMessage function into the ServerSideGroupSessionDriver.
 */
-func (mgsd *MlsGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
+func (mgsd *MlsGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
 	mgsd.sendIndividualMessage = sendIndividualMessage
 }
 
@@ -134,24 +134,53 @@ func (mgsd *MlsGroupSessionDriver) SetChatbotIsPseudo(chatbotId string, isPsuedo
 	mgsd.chatbotIsPseudo[chatbotId] = isPsuedo
 }
 
+/*
+cloneMlsState returns a copy of the MLS state that survives a commit issued from the original. Commits replace the MLS
+state instead of changing it, except for the extensions of the committer's own leaf, which the new state shares with the
+old one and overwrites; the copy gets its own. The key schedule stays shared, so ratchet steps taken for messages sent
+from the original are not reused.
+*/
+func cloneMlsState(state *mls.State) *mls.State {
+	if state == nil {
+		return nil
+	}
+	clone := *state
+	clone.Tree = state.Tree.Clone()
+	for _, node := range clone.Tree.Nodes {
+		if node.Node != nil && node.Node.Leaf != nil {
+			node.Node.Leaf.Extensions.Entries = append([]mls.Extension(nil), node.Node.Leaf.Extensions.Entries...)
+		}
+	}
+	return &clone
+}
+
 /*
 SendMessage sends a Message to the server-side group.
 */
-func (mgsd *MlsGroupSessionDriver) SendMessage(messageRaw *pb.MessageWrapper) error {
+func (mgsd *MlsGroupSessionDriver) SendMessage(ctx context.Context, messageRaw *pb.MessageWrapper) error {
 	logger.Debug("Sending Message to MLS group: ", messageRaw.String())
 
 	// Send Message
-	res, err := (*mgsd.chatServiceClient).SendMessage(*mgsd.chatServiceClientCtx, messageRaw)
+	res, err := (*mgsd.chatServiceClient).SendMessage(ctx, messageRaw)
 	if err != nil {
 		logger.Error("Error sending Message to MLS group: ", err)
 		return err
 	}
 
+	if res.GetKeyUpdateConflict() {
+		err = &KeyUpdateConflictError{GroupID: mgsd.groupID, KeyUpdateSeq: res.GetKeyUpdateSeq()}
+		logger.Warning("Error sending Message to MLS group: ", err)
+		return err
+	}
+
 	if res.ErrorMessage != "" {
 		logger.Error("Error sending Message to MLS group: ", res.ErrorMessage)
-		return fmt.Errorf(res.ErrorMessage)
+		return fmt.Errorf("%w: %v", ErrMessageRejected, res.ErrorMessage)
 	}
 
+	// The others apply the key update of the Message in the order the server assigned to it.
+	messageRaw.KeyUpdateSeq = res.GetKeyUpdateSeq()
+
 	logger.Info("Received response from MLS group: ", res.String())
 	return nil
 }
@@ -176,16 +205,17 @@ func (mgsd *MlsGroupSessionDriver) HandleCommit(commit *mls.MLSPlaintext, sender
 /*
 EncryptMessage encrypts (protects) the given Message by the sending session.
 */
-func (mgsd *MlsGroupSessionDriver) EncryptMessage(messageRaw []byte, messageType pb.MessageType, receivingChatbotIds []string) (*mls.MLSCiphertext, *mls.MLSPlaintext, error) {
+func (mgsd *MlsGroupSessionDriver) EncryptMessage(messageRaw []byte, messageType pb.MessageType, messageID string, receivingChatbotIds []string) (*mls.MLSCiphertext, *mls.MLSPlaintext, error) {
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 		ChatbotIDs:  receivingChatbotIds,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return nil, nil, err
 	}
 
 	ct, err := mgsd.groupChatState.Protect(messageMarshal)
@@ -232,11 +262,26 @@ func (mgsd *MlsGroupSessionDriver) GetWelcomeMessage(kp mls.KeyPackage) (*mls.We
 	return welcome, add, addCommit, nil
 }
 
+/*
+leafIndexOf returns the leaf index of the member, looked up by the identity of the credentials in the tree, so that
+members who have not committed yet or were re-added are found as well.
+*/
+func (mgsd *MlsGroupSessionDriver) leafIndexOf(memberID string) (uint32, bool) {
+	for i := mls.LeafIndex(0); mls.LeafCount(i) < mgsd.groupChatState.Tree.Size(); i++ {
+		keyPackage, exist := mgsd.groupChatState.Tree.KeyPackage(i)
+		if exist && string(keyPackage.Credential.Identity()) == memberID {
+			return uint32(i), true
+		}
+	}
+	leafIndex, exist := mgsd.memberToLeafIndex[memberID]
+	return leafIndex, exist
+}
+
 /*
 GetRemoveMessage returns the remove Message.
 */
 func (mgsd *MlsGroupSessionDriver) GetRemoveMessage(removedID string) (*mls.MLSPlaintext, *mls.MLSPlaintext, error) {
-	removedLeafIndex, exist := mgsd.memberToLeafIndex[removedID]
+	removedLeafIndex, exist := mgsd.leafIndexOf(removedID)
 	if !exist {
 		logger.Error("RemovedLeafIndex does not exist.")
 		return nil, nil, fmt.Errorf("RemovedLeafIndex does not exist.")
@@ -268,60 +313,63 @@ func (mgsd *MlsGroupSessionDriver) GetRemoveMessage(removedID string) (*mls.MLSP
 /*
 EncryptMessageByMlsMultiTreeRoot encrypts the given message by the multi-treekem's root. (For IGA)
 */
-func (mgsd *MlsGroupSessionDriver) EncryptMessageByMlsMultiTreeRoot(messageRaw []byte, messageType pb.MessageType, externalId string, signPrivKey []byte) util.CipherText {
+func (mgsd *MlsGroupSessionDriver) EncryptMessageByMlsMultiTreeRoot(messageRaw []byte, messageType pb.MessageType, messageID string, externalId string, signPrivKey []byte) (util.CipherText, error) {
 	//logger.Error("EncryptMessageByMlsMultiTreeRoot is not implemented yet.")
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
 	logger.Info("Encrypting Message: ", messageMarshal, " using key", mgsd.GetMlsMultiTree().GetRootSecret(externalId))
 
-	ct, err := util.Encrypt(messageMarshal, mgsd.GetMlsMultiTree().GetRootSecret(externalId), signPrivKey)
+	ct, err := util.EncryptWithSuite(mgsd.GetCipherSuite(), messageMarshal, mgsd.GetMlsMultiTree().GetRootSecret(externalId), signPrivKey)
 	if err != nil {
 		logger.Error("Error encrypting Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	return ct
+	return ct, nil
 }
 
 /*
 EncryptMessageByMlsMultiTreeExternalRoot encrypts the given message by the multi-treekem's external root. (For IGA)
 */
-func (mgsd *MlsGroupSessionDriver) EncryptMessageByMlsMultiTreeExternalRoot(messageRaw []byte, messageType pb.MessageType, signPrivKey []byte) util.CipherText {
+func (mgsd *MlsGroupSessionDriver) EncryptMessageByMlsMultiTreeExternalRoot(messageRaw []byte, messageType pb.MessageType, messageID string, signPrivKey []byte) (util.CipherText, error) {
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 	logger.Info("Encrypting Message: ", messageMarshal, " using key", mgsd.GetMlsMultiTreeExternal().GetRootSecret())
-	ct, err := util.Encrypt(messageMarshal, mgsd.GetMlsMultiTreeExternal().GetRootSecret(), signPrivKey)
+	ct, err := util.EncryptWithSuite(mgsd.GetCipherSuite(), messageMarshal, mgsd.GetMlsMultiTreeExternal().GetRootSecret(), signPrivKey)
 	if err != nil {
 		logger.Error("Error encrypting Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	return ct
+	return ct, nil
 }
 
 /*
-ParseEncryptedMessage parses the given messageRaw and handles it (either do the specific task or output the Message) as well as return the Message.
+ParseEncryptedMessage parses the given messageRaw and handles it (either do the specific task or output the Message) as well as return the Message. The Message has to
+be encrypted with the given MessageID.
 */
-func (mgsd *MlsGroupSessionDriver) ParseEncryptedMessage(senderID string, encryptedMessage mls.MLSCiphertext) ([]byte, pb.MessageType, []string) {
+func (mgsd *MlsGroupSessionDriver) ParseEncryptedMessage(senderID string, encryptedMessage mls.MLSCiphertext, messageID string) ([]byte, pb.MessageType, []string, error) {
 	decryptedMessage, err := mgsd.groupChatState.Unprotect(&encryptedMessage)
 	if err != nil {
 		logger.Error("Failed to decrypt the Message", err)
-		return nil, -1, nil
+		return nil, -1, nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	packedMessage := &pb.Message{}
@@ -330,26 +378,31 @@ func (mgsd *MlsGroupSessionDriver) ParseEncryptedMessage(senderID string, encryp
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, nil, err
 	}
 
-	return packedMessage.Message, packedMessage.MessageType, packedMessage.ChatbotIDs
+	return packedMessage.Message, packedMessage.MessageType, packedMessage.ChatbotIDs, nil
 }
 
 /*
-ParseEncryptedIGAMessage parses the given messageRaw and handles it as an IGA message.
+ParseEncryptedIGAMessage parses the given messageRaw and handles it as an IGA message. The Message has to be encrypted with the given
+MessageID.
 */
-func (mgsd *MlsGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMessageRaw []byte, signPubKey []byte) ([]byte, pb.MessageType) {
+func (mgsd *MlsGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMessageRaw []byte, signPubKey []byte, messageID string) ([]byte, pb.MessageType, error) {
 	if mgsd.GetMlsMultiTreeExternal() == nil {
 		logger.Error("No MlsMultiTreeExternal")
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: no MlsMultiTreeExternal", ErrNotInGroup)
 	}
 
 	logger.Info("Decrypting IGA Message: ", encryptedMessageRaw, " using key", mgsd.GetMlsMultiTreeExternal().GetRootSecret())
 
-	decryptedMessage, err := util.Decrypt(util.DeserializeCipherText(encryptedMessageRaw), mgsd.GetMlsMultiTreeExternal().GetRootSecret(), signPubKey)
+	decryptedMessage, err := util.DecryptWithSuite(mgsd.GetCipherSuite(), util.DeserializeCipherText(encryptedMessageRaw), mgsd.GetMlsMultiTreeExternal().GetRootSecret(), signPubKey)
 	if err != nil {
 		logger.Error("Failed to decrypt the IGA message", err)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	packedMessage := &pb.Message{}
@@ -358,39 +411,42 @@ func (mgsd *MlsGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMessageRaw
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, err
 	}
 
 	if packedMessage.MessageType == pb.MessageType_TEXT_MESSAGE {
 		logger.Debug("Parsed text Message: ", packedMessage.Message)
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else if packedMessage.MessageType == pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE {
 		logger.Debug("Parsed pseudonym registration Message: ", packedMessage.Message)
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else if packedMessage.MessageType == pb.MessageType_SKIP {
 		logger.Debug("Parsed skipped message.")
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else {
 		logger.Error("Unknown Message type: ", packedMessage.MessageType)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: unknown IGA message type %v", ErrUndecryptable, packedMessage.MessageType)
 	}
-
-	return nil, -1
 }
 
 /*
-ParseEncryptedExternalIGAMessage parses the given messageRaw and handles it as an IGA message from the chatbot.
+ParseEncryptedExternalIGAMessage parses the given messageRaw and handles it as an IGA message from the chatbot. The Message has to
+be encrypted with the given MessageID.
 */
-func (mgsd *MlsGroupSessionDriver) ParseEncryptedExternalIGAMessage(encryptedMessageRaw []byte, externalId string) ([]byte, pb.MessageType) {
+func (mgsd *MlsGroupSessionDriver) ParseEncryptedExternalIGAMessage(encryptedMessageRaw []byte, externalId string, messageID string) ([]byte, pb.MessageType, error) {
 	if mgsd.GetMlsMultiTree() == nil {
 		logger.Error("No MlsMultiTree")
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: no MlsMultiTree", ErrNotInGroup)
 	}
 
 	logger.Info("Decrypting IGA Message: ", encryptedMessageRaw, " using key", mgsd.GetMlsMultiTree().GetRootSecret(externalId))
-	decryptedMessage, err := util.Decrypt(util.DeserializeCipherText(encryptedMessageRaw), mgsd.GetMlsMultiTree().GetRootSecret(externalId), mgsd.GetMlsMultiTree().GetExternalNode(externalId).SignPublic)
+	decryptedMessage, err := util.DecryptWithSuite(mgsd.GetCipherSuite(), util.DeserializeCipherText(encryptedMessageRaw), mgsd.GetMlsMultiTree().GetRootSecret(externalId), mgsd.GetMlsMultiTree().GetExternalNode(externalId).SignPublic)
 	if err != nil {
 		logger.Error("Failed to decrypt the IGA message", err)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	packedMessage := &pb.Message{}
@@ -399,9 +455,40 @@ func (mgsd *MlsGroupSessionDriver) ParseEncryptedExternalIGAMessage(encryptedMes
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, err
 	}
 
-	return packedMessage.Message, packedMessage.MessageType
+	return packedMessage.Message, packedMessage.MessageType, nil
+}
+
+/*
+EncryptMessageForHiddenTrigger encrypts the given message for a non-IGA chatbot when hiding triggers. A triggered chatbot
+can decrypt the message with its external node key, while any other chatbot receives an indistinguishable dummy. The
+message is signed with the identity key of the sender.
+*/
+func (mgsd *MlsGroupSessionDriver) EncryptMessageForHiddenTrigger(messageRaw []byte, messageType pb.MessageType, messageID string, chatbotId string, triggered bool, senderIdentityKey *identity.KeyPair) ([]byte, error) {
+	if mgsd.GetMlsMultiTree() == nil {
+		logger.Error("No MlsMultiTree")
+		return nil, fmt.Errorf("no MlsMultiTree")
+	}
+
+	return sealHiddenTriggerMessage(mgsd.groupID, chatbotId, mgsd.GetCipherSuite(), messageRaw, messageType, messageID, senderIdentityKey, mgsd.GetMlsMultiTree().GetExternalNode(chatbotId).Public, false, triggered)
+}
+
+/*
+ParseHiddenTriggerMessage parses the given messageRaw as a hidden-trigger message. Messages not addressed to this chatbot
+are reported as SKIP messages, and messages not signed by the identity key of the sender are rejected.
+*/
+func (mgsd *MlsGroupSessionDriver) ParseHiddenTriggerMessage(encryptedMessageRaw []byte, senderIdentityKey *identity.Key, messageID string) ([]byte, pb.MessageType, error) {
+	if mgsd.GetMlsMultiTreeExternal() == nil {
+		logger.Error("No MlsMultiTreeExternal")
+		return nil, -1, fmt.Errorf("%w: no MlsMultiTreeExternal", ErrNotInGroup)
+	}
+
+	return openHiddenTriggerMessage(mgsd.groupID, mgsd.userID, mgsd.GetCipherSuite(), encryptedMessageRaw, mgsd.GetMlsMultiTreeExternal().GetSelfNode().Private, senderIdentityKey, messageID)
 }
 
 /*
@@ -459,11 +546,34 @@ func (mgsd *MlsGroupSessionDriver) UpdateGroupChatbotIDs(groupChatbotIDs []strin
 	mgsd.groupChatbots = groupChatbotIDs
 }
 
+/*
+GetCipherSuite returns the cipher suite the CMRT of the group runs with.
+*/
+func (mgsd *MlsGroupSessionDriver) GetCipherSuite() treekem.CipherSuite {
+	if mgsd.cipherSuite == nil {
+		return treekem.MustCipherSuite(treekem.DefaultCipherSuite)
+	}
+	return mgsd.cipherSuite
+}
+
+/*
+SetCipherSuite sets the cipher suite negotiated for the group. It must be set before the CMRT is initiated.
+*/
+func (mgsd *MlsGroupSessionDriver) SetCipherSuite(id treekem.CipherSuiteID) error {
+	suite, err := treekem.CipherSuiteByID(id)
+	if err != nil {
+		return err
+	}
+	mgsd.cipherSuite = suite
+	return nil
+}
+
 /*
 InitiateMlsMultiTree initiates the MlsMultiTree.
 */
 func (mgsd *MlsGroupSessionDriver) InitiateMlsMultiTree() error {
-	mgsd.mlsMultiTree = treekem.NewMlsMultiTree(&mgsd.groupChatState)
+	mgsd.mlsMultiTree = treekem.NewMlsMultiTreeWithCipherSuite(mgsd.GetCipherSuite(), &mgsd.groupChatState)
+	mgsd.mlsMultiTree.SetGroupID(mgsd.groupID)
 	return nil
 }
 
@@ -481,6 +591,33 @@ func (mgsd *MlsGroupSessionDriver) GenerateMlsMultiTreeKeyUpdate(chatbotIds []st
 	return mgsd.mlsMultiTree.UpdateTreeKEM(chatbotIds)
 }
 
+/*
+SnapshotKeyUpdateState returns a function that rolls the MLS state, MlsMultiTree and MlsMultiTreeExternal of the group
+back to their current state. It is used to undo an own commit or key update the server rejected.
+*/
+func (mgsd *MlsGroupSessionDriver) SnapshotKeyUpdateState() func() {
+	groupChatState := cloneMlsState(mgsd.groupChatState)
+	mlsMultiTree, mlsMultiTreeExternal := mgsd.mlsMultiTree, mgsd.mlsMultiTreeExternal
+	var mlsMultiTreeSnapshot *treekem.MlsMultiTreeSnapshot
+	if mlsMultiTree != nil {
+		mlsMultiTreeSnapshot = mlsMultiTree.Snapshot()
+	}
+	var mlsMultiTreeExternalSnapshot *treekem.MlsMultiTreeExternalSnapshot
+	if mlsMultiTreeExternal != nil {
+		mlsMultiTreeExternalSnapshot = mlsMultiTreeExternal.Snapshot()
+	}
+
+	return func() {
+		mgsd.groupChatState = groupChatState
+		if mlsMultiTree != nil {
+			mlsMultiTree.Restore(mlsMultiTreeSnapshot)
+		}
+		if mlsMultiTreeExternal != nil {
+			mlsMultiTreeExternal.Restore(mlsMultiTreeExternalSnapshot)
+		}
+	}
+}
+
 /*
 GenerateExternalNodeJoin generates the MlsMultiTreeExternal join request.
 */
@@ -509,6 +646,13 @@ func (mgsd *MlsGroupSessionDriver) AddExternalNodeToMlsMultiTree(id string, ct t
 	return mgsd.mlsMultiTree.AddExternalNode(id, ct)
 }
 
+/*
+RemoveExternalNodeFromMlsMultiTree removes an external node from the MlsMultiTree.
+*/
+func (mgsd *MlsGroupSessionDriver) RemoveExternalNodeFromMlsMultiTree(id string) error {
+	return mgsd.mlsMultiTree.RemoveExternalNode(id)
+}
+
 /*
 GetMlsMultiTree returns the MlsMultiTree.
 */
@@ -520,7 +664,8 @@ func (mgsd *MlsGroupSessionDriver) GetMlsMultiTree() *treekem.MlsMultiTree {
 InitiateMlsMultiTreeExternal initiates the MlsMultiTreeExternal.
 */
 func (mgsd *MlsGroupSessionDriver) InitiateMlsMultiTreeExternal(treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte) error {
-	mgsd.mlsMultiTreeExternal = treekem.NewMlsMultiTreeExternal(treekemRootPub, treekemRootSignPub, initLeaf)
+	mgsd.mlsMultiTreeExternal = treekem.NewMlsMultiTreeExternalWithCipherSuite(mgsd.GetCipherSuite(), treekemRootPub, treekemRootSignPub, initLeaf)
+	mgsd.mlsMultiTreeExternal.SetGroupID(mgsd.groupID)
 	return nil
 }
 
@@ -539,15 +684,15 @@ func (mgsd *MlsGroupSessionDriver) GenerateMlsMultiTreeExternalKeyUpdate() (tree
 }
 
 /*
-HandleMlsMultiTreeExternalKeyUpdate handles the key update from the MlsMultiTreeExternal.
+HandleMlsMultiTreeExternalKeyUpdate handles the key update from the MlsMultiTreeExternal, which moves its root to the given epoch.
 */
-func (mgsd *MlsGroupSessionDriver) HandleMlsMultiTreeExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte) error {
-	return mgsd.mlsMultiTree.HandleExternalNodeUpdate(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+func (mgsd *MlsGroupSessionDriver) HandleMlsMultiTreeExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return mgsd.mlsMultiTree.HandleExternalNodeUpdateAtEpoch(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey, epoch, transcriptHash)
 }
 
 /*
-HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MlsMultiTree.
+HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MlsMultiTree, which moves the root to the given epoch.
 */
-func (mgsd *MlsGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
-	return mgsd.mlsMultiTreeExternal.HandleTreeKEMUpdate(updateMessage, newPubKey, newSignPubKey)
+func (mgsd *MlsGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return mgsd.mlsMultiTreeExternal.HandleTreeKEMUpdateAtEpoch(updateMessage, newPubKey, newSignPubKey, epoch, transcriptHash)
 }
@@ -0,0 +1,132 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"time"
+)
+
+/*
+OutputMessage is used in the message channel to denote the message or the server event received by a user or a chatbot.
+Messages carry Message and MessageType, and events carry EventType, the fields decoded from the event and Event.
+*/
+type OutputMessage struct {
+	Message     []byte
+	MessageType pb.MessageType
+	EventType   pb.ServerEventType
+
+	// GroupID is the group of the message or event, and is empty for individual messages.
+	GroupID string
+	// SenderID is the user or chatbot that sent the message or caused the event. It is the pseudonym of a pseudonymous
+	// sender, and is empty if the sender is hidden.
+	SenderID string
+	Channel  pb.Channel
+	// Timestamp is when the server accepted the message or emitted the event.
+	Timestamp time.Time
+	MessageID string
+
+	// GroupType is the type of the group of the event.
+	GroupType pb.GroupType
+	// MemberID is the user added to or removed from the group.
+	MemberID string
+	// ChatbotID is the chatbot added to or removed from the group, or whose scopes are updated.
+	ChatbotID string
+	// ParticipantIDs are the members of the group once the event is applied, if the event lists them.
+	ParticipantIDs []string
+	// ChatbotIDs are the chatbots of the group once the event is applied, if the event lists them.
+	ChatbotIDs []string
+	// Scopes are the scopes the event grants the chatbot. Members apply them only once they verified the signature of
+	// the inviter, and report the event as failed otherwise.
+	Scopes *pb.ChatbotScopes
+	// Event is the raw server event, and is nil for messages.
+	Event *pb.ServerEvent
+}
+
+/*
+NewMessageOutput returns the output of a message received in the group, or nil if there is nothing to output.
+*/
+func NewMessageOutput(messageWrapper *pb.MessageWrapper, groupID string, message []byte, messageType pb.MessageType) *OutputMessage {
+	if message == nil {
+		return nil
+	}
+
+	channel := pb.Channel_GROUP_CHANNEL
+	switch {
+	case groupID == "":
+		channel = pb.Channel_INDIVIDUAL_CHANNEL
+	case messageWrapper.GetIsPseudo():
+		channel = pb.Channel_PSEUDO_CHANNEL
+	case messageWrapper.GetIsIGA():
+		channel = pb.Channel_IGA_CHANNEL
+	}
+
+	return &OutputMessage{
+		Message:     message,
+		MessageType: messageType,
+		GroupID:     groupID,
+		SenderID:    messageWrapper.GetSenderID(),
+		Channel:     channel,
+		Timestamp:   serverTime(messageWrapper.GetTimestamp()),
+		MessageID:   messageWrapper.GetMessageID(),
+	}
+}
+
+/*
+NewEventOutput returns the output of a server event about a group, with the fields of its payload decoded.
+*/
+func NewEventOutput(serverEvent *pb.ServerEvent) *OutputMessage {
+	output := &OutputMessage{
+		EventType: serverEvent.GetEventType(),
+		Timestamp: serverTime(serverEvent.GetTimestamp()),
+		Event:     serverEvent,
+	}
+
+	switch serverEvent.GetEventType() {
+	case pb.ServerEventType_GROUP_INVITATION:
+		event := serverEvent.GetGroupInvitation()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.ParticipantIDs = event.GetParticipantIDs()
+		output.ChatbotIDs = event.GetChatbotIDs()
+	case pb.ServerEventType_GROUP_ADDITION:
+		event := serverEvent.GetGroupAddition()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.MemberID = event.GetAddedID()
+		output.ParticipantIDs = event.GetParticipantIDs()
+	case pb.ServerEventType_GROUP_REMOVAL:
+		event := serverEvent.GetGroupRemoval()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.MemberID = event.GetRemovedID()
+		output.ParticipantIDs = event.GetParticipantIDs()
+	case pb.ServerEventType_GROUP_CHATBOT_INVITATION:
+		event := serverEvent.GetGroupChatbotInvitation()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.ParticipantIDs = event.GetParticipantIDs()
+		output.Scopes = event.GetScopes()
+	case pb.ServerEventType_GROUP_CHATBOT_ADDITION:
+		event := serverEvent.GetGroupChatbotAddition()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.ChatbotID = event.GetAddedChatbotID()
+		output.ChatbotIDs = event.GetChatbotIDs()
+		output.Scopes = event.GetScopes()
+	case pb.ServerEventType_GROUP_CHATBOT_REMOVAL:
+		event := serverEvent.GetGroupChatbotRemoval()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.ChatbotID = event.GetRemovedChatbotID()
+		output.ChatbotIDs = event.GetChatbotIDs()
+	case pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE:
+		event := serverEvent.GetGroupChatbotScopeUpdate()
+		output.GroupID, output.SenderID, output.GroupType = event.GetGroupID(), event.GetSenderID(), event.GetGroupType()
+		output.ChatbotID = event.GetChatbotID()
+		output.Scopes = event.GetScopes()
+	}
+	return output
+}
+
+/*
+serverTime converts a timestamp set by the server. A timestamp the server did not set is the zero time.
+*/
+func serverTime(timestamp int64) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(timestamp)
+}
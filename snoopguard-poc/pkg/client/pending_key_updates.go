@@ -0,0 +1,111 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"sync"
+)
+
+// MaxPendingKeyUpdates is the number of epochs ahead of a root that updates are buffered for. Beyond it, the receiver
+// gives up waiting for the missing updates and asks for the root to be recovered.
+const MaxPendingKeyUpdates = 16
+
+// MaxPendingKeyUpdateCandidates is the number of messages buffered per epoch of a root. The messages are not
+// authenticated until the updates before them are handled, so a forged one must not take the place of the genuine one.
+const MaxPendingKeyUpdateCandidates = 4
+
+/*
+pendingKeyUpdateKey identifies the buffered messages by the group, the ID of the external node whose root they update,
+and the epoch they move the root to.
+*/
+type pendingKeyUpdateKey struct {
+	groupID string
+	rootID  string
+	epoch   uint64
+}
+
+/*
+PendingKeyUpdates buffers the messages carrying a key update of a root that arrived ahead of their epoch, until the
+updates before them are handled.
+*/
+type PendingKeyUpdates struct {
+	messages map[pendingKeyUpdateKey][]*pb.MessageWrapper
+	mutex    sync.Mutex
+}
+
+/*
+NewPendingKeyUpdates creates an empty PendingKeyUpdates.
+*/
+func NewPendingKeyUpdates() *PendingKeyUpdates {
+	return &PendingKeyUpdates{
+		messages: make(map[pendingKeyUpdateKey][]*pb.MessageWrapper),
+	}
+}
+
+/*
+Add buffers the message updating the root of rootID in the group from currentEpoch to the given epoch, next to the
+other candidates for that epoch. It returns false if the epoch is too far ahead of the root or the candidates for the
+epoch are full, in which case the message is dropped.
+*/
+func (p *PendingKeyUpdates) Add(groupID string, rootID string, currentEpoch uint64, epoch uint64, messageWrapper *pb.MessageWrapper) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if epoch <= currentEpoch || epoch-currentEpoch > MaxPendingKeyUpdates {
+		return false
+	}
+	key := pendingKeyUpdateKey{groupID, rootID, epoch}
+	if len(p.messages[key]) >= MaxPendingKeyUpdateCandidates {
+		return false
+	}
+	p.messages[key] = append(p.messages[key], messageWrapper)
+	return true
+}
+
+/*
+Take removes and returns the buffered messages updating the root of rootID in the group to the given epoch, in the order
+they arrived. The caller handles them in turn until one is authenticated, and discards the ones that are not.
+*/
+func (p *PendingKeyUpdates) Take(groupID string, rootID string, epoch uint64) []*pb.MessageWrapper {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := pendingKeyUpdateKey{groupID, rootID, epoch}
+	messageWrappers := p.messages[key]
+	delete(p.messages, key)
+	return messageWrappers
+}
+
+/*
+Len returns the number of buffered messages updating the root of rootID in the group.
+*/
+func (p *PendingKeyUpdates) Len(groupID string, rootID string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.lenLocked(groupID, rootID)
+}
+
+/*
+DropUpTo drops the buffered messages updating the root of rootID in the group to the given epoch or before, which can
+no longer be handled once the root moved past them.
+*/
+func (p *PendingKeyUpdates) DropUpTo(groupID string, rootID string, epoch uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for key := range p.messages {
+		if key.groupID == groupID && key.rootID == rootID && key.epoch <= epoch {
+			delete(p.messages, key)
+		}
+	}
+}
+
+func (p *PendingKeyUpdates) lenLocked(groupID string, rootID string) int {
+	n := 0
+	for key := range p.messages {
+		if key.groupID == groupID && key.rootID == rootID {
+			n += len(p.messages[key])
+		}
+	}
+	return n
+}
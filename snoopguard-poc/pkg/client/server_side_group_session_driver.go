@@ -6,11 +6,15 @@ import (
 	"chatbot-poc-go/pkg/util"
 	"context"
 	"fmt"
+	"go.mau.fi/libsignal/keys/identity"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
 )
 
+// DefaultSenderKeyRotationInterval is the number of messages a sender encrypts with its sender key before rotating it.
+const DefaultSenderKeyRotationInterval = 100
+
 /*
 ServerSideGroupSessionDriver handles the server-side group session.
 */
@@ -23,14 +27,18 @@ type ServerSideGroupSessionDriver struct {
 	chatbotIsIGA      map[string]bool
 	chatbotIsPseudo   map[string]bool
 
+	sentMessageCount          int
+	senderKeyRotationInterval int
+
 	treekemState         *treekem.TreeKEMState
+	treekemIndices       map[string]int
 	multiTreeKEM         *treekem.MultiTreeKEM
 	multiTreeKEMExternal *treekem.MultiTreeKEMExternal
+	cipherSuite          treekem.CipherSuite
 
-	sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
+	sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error
 
-	chatServiceClient    *pb.ChatServiceClient
-	chatServiceClientCtx *context.Context
+	chatServiceClient *pb.ChatServiceClient
 }
 
 /*
@@ -45,7 +53,10 @@ func NewServerSideGroupSessionDriver(userID string, groupID string, groupChatHan
 		groupChatbots:     groupChatbotIDs,
 		chatbotIsIGA:      make(map[string]bool),
 		chatbotIsPseudo:   make(map[string]bool),
-		sendIndividualMessage: func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
+		treekemIndices:    make(map[string]int),
+
+		senderKeyRotationInterval: DefaultSenderKeyRotationInterval,
+		sendIndividualMessage: func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error {
 			// Print not implemented error
 			logger.Error("Not implemented: sendIndividualMessage")
 			return fmt.Errorf("not implemented: sendIndividualMessage")
@@ -58,17 +69,16 @@ func NewServerSideGroupSessionDriver(userID string, groupID string, groupChatHan
 }
 
 /*
-SetChatServiceClient inject the chatServiceClient and chatServiceClientCtx into the ServerSideGroupSessionDriver.
+SetChatServiceClient inject the chatServiceClient into the ServerSideGroupSessionDriver.
 */
-func (ssgsd *ServerSideGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient, chatServiceClientCtx *context.Context) {
+func (ssgsd *ServerSideGroupSessionDriver) SetChatServiceClient(chatServiceClient *pb.ChatServiceClient) {
 	ssgsd.chatServiceClient = chatServiceClient
-	ssgsd.chatServiceClientCtx = chatServiceClientCtx
 }
 
 /*
 SetSendIndividualMessage inject the sendIndividualMessage function into the ServerSideGroupSessionDriver.
 */
-func (ssgsd *ServerSideGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
+func (ssgsd *ServerSideGroupSessionDriver) SetSendIndividualMessage(sendIndividualMessage func(ctx context.Context, recipientAddress *protocol.SignalAddress, messageWrapper *pb.MessageWrapper) error) {
 	ssgsd.sendIndividualMessage = sendIndividualMessage
 }
 
@@ -130,21 +140,30 @@ func (ssgsd *ServerSideGroupSessionDriver) HasUserReceivingSession(userID string
 /*
 SendMessage sends a Message to the server-side group.
 */
-func (ssgsd *ServerSideGroupSessionDriver) SendMessage(messageRaw *pb.MessageWrapper) error {
+func (ssgsd *ServerSideGroupSessionDriver) SendMessage(ctx context.Context, messageRaw *pb.MessageWrapper) error {
 	logger.Debug("Sending Message to server-side group: ", messageRaw.String())
 
 	// Send Message
-	res, err := (*ssgsd.chatServiceClient).SendMessage(*ssgsd.chatServiceClientCtx, messageRaw)
+	res, err := (*ssgsd.chatServiceClient).SendMessage(ctx, messageRaw)
 	if err != nil {
 		logger.Error("Error sending Message to server-side group: ", err)
 		return err
 	}
 
+	if res.GetKeyUpdateConflict() {
+		err = &KeyUpdateConflictError{GroupID: ssgsd.groupID, KeyUpdateSeq: res.GetKeyUpdateSeq()}
+		logger.Warning("Error sending Message to server-side group: ", err)
+		return err
+	}
+
 	if res.ErrorMessage != "" {
 		logger.Error("Error sending Message to server-side group: ", res.ErrorMessage)
-		return fmt.Errorf(res.ErrorMessage)
+		return fmt.Errorf("%w: %v", ErrMessageRejected, res.ErrorMessage)
 	}
 
+	// The others apply the key update of the Message in the order the server assigned to it.
+	messageRaw.KeyUpdateSeq = res.GetKeyUpdateSeq()
+
 	logger.Info("Received response from server-side group: ", res.String())
 	return nil
 }
@@ -152,75 +171,80 @@ func (ssgsd *ServerSideGroupSessionDriver) SendMessage(messageRaw *pb.MessageWra
 /*
 EncryptMessageBySendingSession encrypts the given Message by the sending session.
 */
-func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageBySendingSession(messageRaw []byte, messageType pb.MessageType, receivingChatbotIds []string) protocol.GroupCiphertextMessage {
+func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageBySendingSession(messageRaw []byte, messageType pb.MessageType, messageID string, receivingChatbotIds []string) (protocol.GroupCiphertextMessage, error) {
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 		ChatbotIDs:  receivingChatbotIds,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return nil, err
 	}
 
-	return ssgsd.groupChatHandler.GetSendingGroupSession().EncryptGroupMessage(messageMarshal)
+	ssgsd.sentMessageCount++
+	return ssgsd.groupChatHandler.GetSendingGroupSession().EncryptGroupMessage(messageMarshal), nil
 }
 
 /*
 EncryptMessageByMultiTreeKEMRoot encrypts the given message by the multi-treekem's root. (For IGA)
 */
-func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageByMultiTreeKEMRoot(messageRaw []byte, messageType pb.MessageType, receivingChatbotIds []string, externalId string, signPrivKey []byte) util.CipherText {
+func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageByMultiTreeKEMRoot(messageRaw []byte, messageType pb.MessageType, messageID string, receivingChatbotIds []string, externalId string, signPrivKey []byte) (util.CipherText, error) {
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 		ChatbotIDs:  receivingChatbotIds,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	ct, err := util.Encrypt(messageMarshal, ssgsd.GetMultiTreeKEM().GetRootSecret(externalId), signPrivKey)
+	ct, err := util.EncryptWithSuite(ssgsd.GetCipherSuite(), messageMarshal, ssgsd.GetMultiTreeKEM().GetRootSecret(externalId), signPrivKey)
 	if err != nil {
 		logger.Error("Error encrypting Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	return ct
+	return ct, nil
 }
 
 /*
 EncryptMessageByMultiTreeKEMExternalRoot encrypts the given message by the multi-treekem's external root. (For IGA)
 */
-func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageByMultiTreeKEMExternalRoot(messageRaw []byte, messageType pb.MessageType, signPrivKey []byte) util.CipherText {
+func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageByMultiTreeKEMExternalRoot(messageRaw []byte, messageType pb.MessageType, messageID string, signPrivKey []byte) (util.CipherText, error) {
 	message := &pb.Message{
 		Message:     messageRaw,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 	messageMarshal, err := proto.Marshal(message)
 	if err != nil {
 		logger.Error("Error marshalling Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	ct, err := util.Encrypt(messageMarshal, ssgsd.GetMultiTreeKEMExternal().GetRootSecret(), signPrivKey)
+	ct, err := util.EncryptWithSuite(ssgsd.GetCipherSuite(), messageMarshal, ssgsd.GetMultiTreeKEMExternal().GetRootSecret(), signPrivKey)
 	if err != nil {
 		logger.Error("Error encrypting Message: ", err)
-		panic("")
+		return util.CipherText{}, err
 	}
 
-	return ct
+	return ct, nil
 }
 
 /*
-ParseEncryptedMessage parses the given messageRaw and handles it (either do the specific task or output the Message) as well as return the Message.
+ParseEncryptedMessage parses the given messageRaw and handles it (either do the specific task or output the Message) as well as return the Message. The Message has to
+be encrypted with the given MessageID.
 */
-func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedMessage(senderID string, encryptedMessage protocol.GroupCiphertextMessage) ([]byte, pb.MessageType, []string) {
+func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedMessage(senderID string, encryptedMessage protocol.GroupCiphertextMessage, messageID string) ([]byte, pb.MessageType, []string, error) {
 	if ssgsd.groupChatHandler.GetReceivingGroupSession(senderID) == nil {
 		logger.Error("No receiving group session for senderID: ", senderID)
-		return nil, -1, nil
+		return nil, -1, nil, fmt.Errorf("%w: no sender key of %v", ErrNoSession, senderID)
 	}
 
 	decryptedMessage := ssgsd.groupChatHandler.GetReceivingGroupSession(senderID).DecryptGroupMessage(encryptedMessage)
@@ -230,24 +254,29 @@ func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedMessage(senderID string
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, nil, err
 	}
 
-	return packedMessage.Message, packedMessage.MessageType, packedMessage.ChatbotIDs
+	return packedMessage.Message, packedMessage.MessageType, packedMessage.ChatbotIDs, nil
 }
 
 /*
-ParseEncryptedIGAMessage parses the given messageRaw and handles it as an IGA message.
+ParseEncryptedIGAMessage parses the given messageRaw and handles it as an IGA message. The Message has to be encrypted with the given
+MessageID.
 */
-func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMessageRaw []byte, signPubKey []byte) ([]byte, pb.MessageType) {
+func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMessageRaw []byte, signPubKey []byte, messageID string) ([]byte, pb.MessageType, error) {
 	if ssgsd.GetMultiTreeKEMExternal() == nil {
 		logger.Error("No MultiTreeKEMExternal")
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: no MultiTreeKEMExternal", ErrNotInGroup)
 	}
 
-	decryptedMessage, err := util.Decrypt(util.DeserializeCipherText(encryptedMessageRaw), ssgsd.GetMultiTreeKEMExternal().GetRootSecret(), signPubKey)
+	decryptedMessage, err := util.DecryptWithSuite(ssgsd.GetCipherSuite(), util.DeserializeCipherText(encryptedMessageRaw), ssgsd.GetMultiTreeKEMExternal().GetRootSecret(), signPubKey)
 	if err != nil {
 		logger.Error("Failed to decrypt the IGA message", err)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	packedMessage := &pb.Message{}
@@ -256,36 +285,41 @@ func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedIGAMessage(encryptedMes
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, err
 	}
 
 	if packedMessage.MessageType == pb.MessageType_TEXT_MESSAGE {
 		logger.Debug("Parsed text Message: ", packedMessage.Message)
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else if packedMessage.MessageType == pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE {
 		logger.Debug("Parsed pseudonym registration Message: ", packedMessage.Message)
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else if packedMessage.MessageType == pb.MessageType_SKIP {
 		logger.Debug("Parsed skipped message.")
-		return packedMessage.Message, packedMessage.MessageType
+		return packedMessage.Message, packedMessage.MessageType, nil
 	} else {
 		logger.Error("Unknown Message type: ", packedMessage.MessageType)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: unknown IGA message type %v", ErrUndecryptable, packedMessage.MessageType)
 	}
 }
 
 /*
-ParseEncryptedExternalIGAMessage parses the given messageRaw and handles it as an IGA message from the chatbot.
+ParseEncryptedExternalIGAMessage parses the given messageRaw and handles it as an IGA message from the chatbot. The Message has to
+be encrypted with the given MessageID.
 */
-func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedExternalIGAMessage(encryptedMessageRaw []byte, externalId string) ([]byte, pb.MessageType) {
+func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedExternalIGAMessage(encryptedMessageRaw []byte, externalId string, messageID string) ([]byte, pb.MessageType, error) {
 	if ssgsd.GetMultiTreeKEM() == nil {
 		logger.Error("No MultiTreeKEM")
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: no MultiTreeKEM", ErrNotInGroup)
 	}
 
-	decryptedMessage, err := util.Decrypt(util.DeserializeCipherText(encryptedMessageRaw), ssgsd.GetMultiTreeKEM().GetRootSecret(externalId), ssgsd.GetMultiTreeKEM().GetExternalNode(externalId).SignPublic)
+	decryptedMessage, err := util.DecryptWithSuite(ssgsd.GetCipherSuite(), util.DeserializeCipherText(encryptedMessageRaw), ssgsd.GetMultiTreeKEM().GetRootSecret(externalId), ssgsd.GetMultiTreeKEM().GetExternalNode(externalId).SignPublic)
 	if err != nil {
 		logger.Error("Failed to decrypt the IGA message", err)
-		return nil, -1
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 	}
 
 	packedMessage := &pb.Message{}
@@ -294,9 +328,40 @@ func (ssgsd *ServerSideGroupSessionDriver) ParseEncryptedExternalIGAMessage(encr
 
 	if err := proto.Unmarshal(decryptedMessage, packedMessage); err != nil {
 		logger.Error("Failed to decode Message", err)
+		return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+	}
+	if err := checkMessageID(packedMessage, messageID); err != nil {
+		return nil, -1, err
+	}
+
+	return packedMessage.Message, packedMessage.MessageType, nil
+}
+
+/*
+EncryptMessageForHiddenTrigger encrypts the given message for a non-IGA chatbot when hiding triggers. A triggered chatbot
+can decrypt the message with its external node key, while any other chatbot receives an indistinguishable dummy. The
+message is signed with the identity key of the sender.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) EncryptMessageForHiddenTrigger(messageRaw []byte, messageType pb.MessageType, messageID string, chatbotId string, triggered bool, senderIdentityKey *identity.KeyPair) ([]byte, error) {
+	if ssgsd.GetMultiTreeKEM() == nil {
+		logger.Error("No MultiTreeKEM")
+		return nil, fmt.Errorf("no MultiTreeKEM")
 	}
 
-	return packedMessage.Message, packedMessage.MessageType
+	return sealHiddenTriggerMessage(ssgsd.groupID, chatbotId, ssgsd.GetCipherSuite(), messageRaw, messageType, messageID, senderIdentityKey, ssgsd.GetMultiTreeKEM().GetExternalNode(chatbotId).Public, ssgsd.GetMultiTreeKEM().IsExternalNodeHybridKEM(chatbotId), triggered)
+}
+
+/*
+ParseHiddenTriggerMessage parses the given messageRaw as a hidden-trigger message. Messages not addressed to this chatbot
+are reported as SKIP messages, and messages not signed by the identity key of the sender are rejected.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) ParseHiddenTriggerMessage(encryptedMessageRaw []byte, senderIdentityKey *identity.Key, messageID string) ([]byte, pb.MessageType, error) {
+	if ssgsd.GetMultiTreeKEMExternal() == nil {
+		logger.Error("No MultiTreeKEMExternal")
+		return nil, -1, fmt.Errorf("%w: no MultiTreeKEMExternal", ErrNotInGroup)
+	}
+
+	return openHiddenTriggerMessage(ssgsd.groupID, ssgsd.userID, ssgsd.GetCipherSuite(), encryptedMessageRaw, ssgsd.GetMultiTreeKEMExternal().GetSelfNode().Private, senderIdentityKey, messageID)
 }
 
 /*
@@ -313,6 +378,30 @@ func (ssgsd *ServerSideGroupSessionDriver) GetSelfSenderKey() *protocol.SenderKe
 	return ssgsd.groupChatHandler.GetSendingGroupSession().DistributeSenderKey()
 }
 
+/*
+RotateSelfSenderKey replaces the user's sender key of the server-side group session with a fresh one, and returns it.
+The new sender key has to be distributed to the remaining participants.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) RotateSelfSenderKey() *protocol.SenderKeyDistributionMessage {
+	ssgsd.sentMessageCount = 0
+	return ssgsd.groupChatHandler.GetSendingGroupSession().RotateSenderKey()
+}
+
+/*
+SetSenderKeyRotationInterval sets the number of messages after which the sender key is rotated. A non-positive interval
+disables the periodic rotation.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SetSenderKeyRotationInterval(interval int) {
+	ssgsd.senderKeyRotationInterval = interval
+}
+
+/*
+SenderKeyRotationDue returns whether the sender key has been used for the number of messages of the rotation interval.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SenderKeyRotationDue() bool {
+	return ssgsd.senderKeyRotationInterval > 0 && ssgsd.sentMessageCount >= ssgsd.senderKeyRotationInterval
+}
+
 /*
 UpdateGroupParticipantIDs updates the group participant IDs.
 */
@@ -334,12 +423,39 @@ func (ssgsd *ServerSideGroupSessionDriver) RemoveUserSession(removedID string) {
 	ssgsd.groupChatHandler.RemoveReceivingGroupSession(removedID)
 }
 
+/*
+GetCipherSuite returns the cipher suite the CMRT of the group runs with.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetCipherSuite() treekem.CipherSuite {
+	if ssgsd.cipherSuite == nil {
+		return treekem.MustCipherSuite(treekem.DefaultCipherSuite)
+	}
+	return ssgsd.cipherSuite
+}
+
+/*
+SetCipherSuite sets the cipher suite negotiated for the group. It must be set before the CMRT is initiated.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SetCipherSuite(id treekem.CipherSuiteID) error {
+	suite, err := treekem.CipherSuiteByID(id)
+	if err != nil {
+		return err
+	}
+	ssgsd.cipherSuite = suite
+	return nil
+}
+
 /*
 InitiateTreeKEM initiates the TreeKEM.
 */
 func (ssgsd *ServerSideGroupSessionDriver) InitiateTreeKEM(gik treekem.GroupInitKey, initLeaf []byte) error {
 	logger.Info("Initiating TreeKEM for group: ", ssgsd.groupID)
 
+	if err := ssgsd.SetCipherSuite(gik.CipherSuite); err != nil {
+		logger.Error("Error initiating TreeKEM: ", err)
+		return err
+	}
+
 	if gik.Size != 0 {
 		// Reconstruct an existing TreeKEM
 		logger.Info("Reconstructing TreeKEM from GroupInitKey")
@@ -352,8 +468,10 @@ func (ssgsd *ServerSideGroupSessionDriver) InitiateTreeKEM(gik treekem.GroupInit
 	} else {
 		// Create a new TreeKEM
 		logger.Info("Creating new TreeKEM")
-		ssgsd.treekemState = treekem.TreeKEMStateOneMemberGroup(initLeaf)
+		ssgsd.treekemState = treekem.TreeKEMStateOneMemberGroupWithCipherSuite(ssgsd.GetCipherSuite(), initLeaf)
+		ssgsd.treekemState.SetGroupID(ssgsd.groupID)
 	}
+	ssgsd.treekemIndices[ssgsd.userID] = ssgsd.treekemState.Index()
 	return nil
 }
 
@@ -393,6 +511,69 @@ func (ssgsd *ServerSideGroupSessionDriver) AddUserToTreeKEM(userAdd *treekem.Use
 	ssgsd.treekemState.HandleUserAdd(*userAdd)
 }
 
+/*
+GetTreeKEMIndex returns the TreeKEM leaf index of the given user.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetTreeKEMIndex(userID string) (int, bool) {
+	index, ok := ssgsd.treekemIndices[userID]
+	return index, ok
+}
+
+/*
+SetTreeKEMIndex sets the TreeKEM leaf index of the given user.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SetTreeKEMIndex(userID string, index int) {
+	ssgsd.treekemIndices[userID] = index
+}
+
+/*
+GetTreeKEMIndices returns the TreeKEM leaf indices of all known users.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetTreeKEMIndices() map[string]int {
+	return ssgsd.treekemIndices
+}
+
+/*
+GetTreeKEMPublicTree returns the public part of the TreeKEM, which an invited user needs to remove other members.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetTreeKEMPublicTree() map[int]*treekem.Node {
+	return ssgsd.treekemState.PublicTree()
+}
+
+/*
+HandleTreeKEMPublicTree merges the public part of the TreeKEM received with the group invitation.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) HandleTreeKEMPublicTree(nodes map[int]*treekem.Node) {
+	ssgsd.treekemState.HandlePublicTree(nodes)
+}
+
+/*
+RemoveUserFromTreeKEM generates a UserRemove that blanks the leaf of the given user.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) RemoveUserFromTreeKEM(userID string) (*treekem.UserRemove, error) {
+	index, ok := ssgsd.treekemIndices[userID]
+	if !ok {
+		return nil, fmt.Errorf("unknown TreeKEM index of user %v", userID)
+	}
+	return ssgsd.multiTreeKEM.RemoveTreeKEMUser(index)
+}
+
+/*
+HandleTreeKEMUserRemove handles the TreeKEM user remove request.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) HandleTreeKEMUserRemove(userRemove *treekem.UserRemove) error {
+	err := ssgsd.multiTreeKEM.HandleTreeKEMRemove(userRemove)
+	if err != nil {
+		return err
+	}
+	for userID, index := range ssgsd.treekemIndices {
+		if index == userRemove.Index {
+			delete(ssgsd.treekemIndices, userID)
+		}
+	}
+	return nil
+}
+
 /*
 UpdateTreeKEMUserKey handles the TreeKEM key update request.
 */
@@ -407,6 +588,31 @@ func (ssgsd *ServerSideGroupSessionDriver) GenerateMultiTreeKEMKeyUpdate(chatbot
 	return ssgsd.multiTreeKEM.UpdateTreeKEM(chatbotIds)
 }
 
+/*
+SnapshotKeyUpdateState returns a function that rolls the TreeKEM, MultiTreeKEM and MultiTreeKEMExternal of the group
+back to their current state. It is used to undo an own key update the server rejected.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SnapshotKeyUpdateState() func() {
+	multiTreeKEM, multiTreeKEMExternal := ssgsd.multiTreeKEM, ssgsd.multiTreeKEMExternal
+	var multiTreeKEMSnapshot *treekem.MultiTreeKEMSnapshot
+	if multiTreeKEM != nil {
+		multiTreeKEMSnapshot = multiTreeKEM.Snapshot()
+	}
+	var multiTreeKEMExternalSnapshot *treekem.MultiTreeKEMExternalSnapshot
+	if multiTreeKEMExternal != nil {
+		multiTreeKEMExternalSnapshot = multiTreeKEMExternal.Snapshot()
+	}
+
+	return func() {
+		if multiTreeKEM != nil {
+			multiTreeKEM.Restore(multiTreeKEMSnapshot)
+		}
+		if multiTreeKEMExternal != nil {
+			multiTreeKEMExternal.Restore(multiTreeKEMExternalSnapshot)
+		}
+	}
+}
+
 /*
 GenerateExternalNodeJoin generates the MultiTreeKEMExternal join request.
 */
@@ -435,6 +641,39 @@ func (ssgsd *ServerSideGroupSessionDriver) AddExternalNodeToMultiTreeKEM(id stri
 	return ssgsd.multiTreeKEM.AddExternalNode(id, ct)
 }
 
+/*
+RemoveExternalNodeFromMultiTreeKEM removes an external node from the MultiTreeKEM.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) RemoveExternalNodeFromMultiTreeKEM(id string) error {
+	return ssgsd.multiTreeKEM.RemoveExternalNode(id)
+}
+
+/*
+SetChatbotHybridKEM sets whether the TreeKEM ciphertexts to and from the chatbot use the hybrid ML-KEM-768 + X25519 KEM.
+It has to be set before the chatbot is added to the MultiTreeKEM.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) SetChatbotHybridKEM(chatbotId string, hybridKEM bool) {
+	ssgsd.multiTreeKEM.SetExternalNodeHybridKEM(chatbotId, hybridKEM)
+}
+
+/*
+GetChatbotHybridKEM returns whether the TreeKEM ciphertexts to and from the chatbot use the hybrid KEM.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetChatbotHybridKEM(chatbotId string) bool {
+	return ssgsd.multiTreeKEM.IsExternalNodeHybridKEM(chatbotId)
+}
+
+/*
+GetTreeKEMRootHybridPublicKey returns the hybrid public key of the TreeKEM root, or nil if no chatbot of the group uses
+the hybrid KEM.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) GetTreeKEMRootHybridPublicKey() ([]byte, error) {
+	if !ssgsd.multiTreeKEM.HasHybridKEMExternalNode() {
+		return nil, nil
+	}
+	return ssgsd.multiTreeKEM.GetTreeKEMRootHybridPublic()
+}
+
 /*
 GetTreeKEMState returns the TreeKEM state.
 */
@@ -453,7 +692,8 @@ func (ssgsd *ServerSideGroupSessionDriver) GetMultiTreeKEM() *treekem.MultiTreeK
 InitiateMultiTreeKEMExternal initiates the MultiTreeKEMExternal.
 */
 func (ssgsd *ServerSideGroupSessionDriver) InitiateMultiTreeKEMExternal(treekemRootPub []byte, treekemRootSignPub []byte, initLeaf []byte) error {
-	ssgsd.multiTreeKEMExternal = treekem.NewMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf)
+	ssgsd.multiTreeKEMExternal = treekem.NewMultiTreeKEMExternalWithCipherSuite(ssgsd.GetCipherSuite(), treekemRootPub, treekemRootSignPub, initLeaf)
+	ssgsd.multiTreeKEMExternal.SetGroupID(ssgsd.groupID)
 	return nil
 }
 
@@ -472,15 +712,15 @@ func (ssgsd *ServerSideGroupSessionDriver) GenerateMultiTreeKEMExternalKeyUpdate
 }
 
 /*
-HandleMultiTreeKEMExternalKeyUpdate handles the key update from the MultiTreeKEMExternal.
+HandleMultiTreeKEMExternalKeyUpdate handles the key update from the MultiTreeKEMExternal, which moves its root to the given epoch.
 */
-func (ssgsd *ServerSideGroupSessionDriver) HandleMultiTreeKEMExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte) error {
-	return ssgsd.multiTreeKEM.HandleExternalNodeUpdate(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+func (ssgsd *ServerSideGroupSessionDriver) HandleMultiTreeKEMExternalKeyUpdate(chatbotId string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return ssgsd.multiTreeKEM.HandleExternalNodeUpdateAtEpoch(chatbotId, chatbotUpdate, newCbPubKey, newCbSignPubKey, epoch, transcriptHash)
 }
 
 /*
-HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MultiTreeKEM.
+HandleTreeKEMUserKeyUpdate handles the TreeKEM key update request for MultiTreeKEM, which moves the root to the given epoch.
 */
-func (ssgsd *ServerSideGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
-	return ssgsd.multiTreeKEMExternal.HandleTreeKEMUpdate(updateMessage, newPubKey, newSignPubKey)
+func (ssgsd *ServerSideGroupSessionDriver) HandleTreeKEMUserKeyUpdate(updateMessage treekem.ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	return ssgsd.multiTreeKEMExternal.HandleTreeKEMUpdateAtEpoch(updateMessage, newPubKey, newSignPubKey, epoch, transcriptHash)
 }
@@ -0,0 +1,393 @@
+package client
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
+	"fmt"
+	"go.mau.fi/libsignal/protocol"
+	"go.mau.fi/libsignal/serialize"
+)
+
+// StoredClientVersion is the version of the format Export writes.
+const StoredClientVersion = 1
+
+/*
+Export returns the state of the client: the underlying user, the sessions, every group with its TreeKEM, CMRT and MLS
+state, the key updates that are handled or buffered, and the messages received last. The client is recreated from it
+with RestoreClient.
+*/
+func (client *Client) Export() (*pb.StoredClient, error) {
+	storedUser, err := client.user.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &pb.StoredClient{
+		Version:       StoredClientVersion,
+		User:          storedUser,
+		KeyUpdateSeqs: make(map[string]uint64),
+	}
+
+	client.clientSessionDrivers.Range(func(recipientID, _ any) bool {
+		stored.Sessions = append(stored.Sessions, recipientID.(string))
+		return true
+	})
+
+	for _, driver := range client.serverSideGroupSessionDrivers {
+		storedGroup, err := driver.export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export server-side group %v: %w", driver.groupID, err)
+		}
+		stored.Groups = append(stored.Groups, storedGroup)
+	}
+	for _, driver := range client.clientSideGroupSessionDrivers {
+		storedGroup, err := driver.export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export client-side group %v: %w", driver.groupID, err)
+		}
+		stored.Groups = append(stored.Groups, storedGroup)
+	}
+	for _, driver := range client.mlsGroupSessionDrivers {
+		storedGroup, err := driver.export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export MLS group %v: %w", driver.groupID, err)
+		}
+		stored.Groups = append(stored.Groups, storedGroup)
+	}
+
+	client.pendingKeyUpdates.mutex.Lock()
+	for key, messageWrappers := range client.pendingKeyUpdates.messages {
+		for _, messageWrapper := range messageWrappers {
+			stored.PendingKeyUpdates = append(stored.PendingKeyUpdates, &pb.StoredPendingKeyUpdate{
+				GroupID: key.groupID,
+				RootID:  key.rootID,
+				Epoch:   key.epoch,
+				Message: messageWrapper,
+			})
+		}
+	}
+	client.pendingKeyUpdates.mutex.Unlock()
+
+	client.keyUpdateSequence.mutex.Lock()
+	for groupID, seq := range client.keyUpdateSequence.seqs {
+		stored.KeyUpdateSeqs[groupID] = seq
+	}
+	client.keyUpdateSequence.mutex.Unlock()
+
+	client.replayWindow.mutex.Lock()
+	for _, key := range client.replayWindow.order {
+		stored.ReceivedMessages = append(stored.ReceivedMessages, &pb.StoredReceivedMessage{
+			SenderID:    key.senderID,
+			RecipientID: key.recipientID,
+			MessageID:   key.messageID,
+		})
+	}
+	client.replayWindow.mutex.Unlock()
+
+	return stored, nil
+}
+
+/*
+RestoreClient recreates a client exported with Export. The chat service client has to be injected with
+SetChatServiceClient before the client is used.
+*/
+func RestoreClient(stored *pb.StoredClient) (*Client, error) {
+	if stored.GetVersion() != StoredClientVersion {
+		return nil, fmt.Errorf("unsupported stored client version %v", stored.GetVersion())
+	}
+
+	user, err := util.RestoreUser(stored.GetUser(), serialize.NewProtoBufSerializer())
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewClient(user.UserID)
+	client.user = user
+
+	for _, recipientID := range stored.GetSessions() {
+		if _, err := client.CreateSessionAndDriver(protocol.NewSignalAddress(recipientID, 1), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, storedGroup := range stored.GetGroups() {
+		var err error
+		switch storedGroup.GetType() {
+		case pb.GroupType_SERVER_SIDE:
+			err = client.CreateServerSideGroupSessionAndDriver(storedGroup.GetGroupID(), storedGroup.GetParticipants(), storedGroup.GetChatbots()).restore(storedGroup)
+		case pb.GroupType_CLIENT_SIDE:
+			err = client.CreateClientSideGroupSessionAndDriver(storedGroup.GetGroupID(), storedGroup.GetParticipants(), storedGroup.GetChatbots()).restore(storedGroup)
+		case pb.GroupType_MLS:
+			err = client.CreateMlsGroupSessionAndDriver(storedGroup.GetGroupID(), storedGroup.GetParticipants(), storedGroup.GetChatbots()).restore(storedGroup)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore group %v: %w", storedGroup.GetGroupID(), err)
+		}
+	}
+
+	for _, pending := range stored.GetPendingKeyUpdates() {
+		key := pendingKeyUpdateKey{pending.GetGroupID(), pending.GetRootID(), pending.GetEpoch()}
+		client.pendingKeyUpdates.messages[key] = append(client.pendingKeyUpdates.messages[key], pending.GetMessage())
+	}
+	for groupID, seq := range stored.GetKeyUpdateSeqs() {
+		client.keyUpdateSequence.seqs[groupID] = seq
+	}
+	for _, received := range stored.GetReceivedMessages() {
+		client.replayWindow.add(receivedMessageKey{received.GetSenderID(), received.GetRecipientID(), received.GetMessageID()})
+	}
+
+	return client, nil
+}
+
+/*
+ExportGroup returns the state of a group, to which it is rolled back with RestoreGroup.
+*/
+func (client *Client) ExportGroup(groupID string) (*pb.StoredGroup, error) {
+	if driver, exists := client.serverSideGroupSessionDrivers[groupID]; exists {
+		return driver.export()
+	}
+	if driver, exists := client.clientSideGroupSessionDrivers[groupID]; exists {
+		return driver.export()
+	}
+	if driver, exists := client.mlsGroupSessionDrivers[groupID]; exists {
+		return driver.export()
+	}
+	return nil, fmt.Errorf("%w: no group session for %v", ErrNotInGroup, groupID)
+}
+
+/*
+RestoreGroup replaces the state of a group with one exported with ExportGroup. The sender keys of server-side groups
+are kept by the user, and are not rolled back.
+*/
+func (client *Client) RestoreGroup(stored *pb.StoredGroup) error {
+	groupID := stored.GetGroupID()
+	switch stored.GetType() {
+	case pb.GroupType_SERVER_SIDE:
+		delete(client.serverSideGroupSessionDrivers, groupID)
+		return client.CreateServerSideGroupSessionAndDriver(groupID, stored.GetParticipants(), stored.GetChatbots()).restore(stored)
+	case pb.GroupType_CLIENT_SIDE:
+		delete(client.clientSideGroupSessionDrivers, groupID)
+		return client.CreateClientSideGroupSessionAndDriver(groupID, stored.GetParticipants(), stored.GetChatbots()).restore(stored)
+	case pb.GroupType_MLS:
+		delete(client.mlsGroupSessionDrivers, groupID)
+		return client.CreateMlsGroupSessionAndDriver(groupID, stored.GetParticipants(), stored.GetChatbots()).restore(stored)
+	}
+	return fmt.Errorf("unknown group type %v", stored.GetType())
+}
+
+/*
+export returns the state of the server-side group.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) export() (*pb.StoredGroup, error) {
+	stored := &pb.StoredGroup{
+		Type:                      pb.GroupType_SERVER_SIDE,
+		GroupID:                   ssgsd.groupID,
+		Participants:              ssgsd.groupParticipants,
+		Chatbots:                  ssgsd.groupChatbots,
+		ChatbotIsIGA:              ssgsd.chatbotIsIGA,
+		ChatbotIsPseudo:           ssgsd.chatbotIsPseudo,
+		CipherSuite:               uint32(ssgsd.GetCipherSuite().ID()),
+		SenderKeyParticipants:     ssgsd.groupChatHandler.GetReceivingParticipantIDs(),
+		SentMessageCount:          int64(ssgsd.sentMessageCount),
+		SenderKeyRotationInterval: int64(ssgsd.senderKeyRotationInterval),
+		TreeKEMIndices:            make(map[string]int64),
+	}
+	for userID, index := range ssgsd.treekemIndices {
+		stored.TreeKEMIndices[userID] = int64(index)
+	}
+
+	var err error
+	if stored.TreeKEMState, stored.MultiTreeKEM, stored.MultiTreeKEMExternal, err = exportTreeKEM(ssgsd.treekemState, ssgsd.multiTreeKEM, ssgsd.multiTreeKEMExternal); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+/*
+restore loads the state of the server-side group into the driver. The sender keys themselves are restored with the user.
+*/
+func (ssgsd *ServerSideGroupSessionDriver) restore(stored *pb.StoredGroup) error {
+	if err := ssgsd.SetCipherSuite(treekem.CipherSuiteID(stored.GetCipherSuite())); err != nil {
+		return err
+	}
+	for chatbotID, isIGA := range stored.GetChatbotIsIGA() {
+		ssgsd.chatbotIsIGA[chatbotID] = isIGA
+	}
+	for chatbotID, isPseudo := range stored.GetChatbotIsPseudo() {
+		ssgsd.chatbotIsPseudo[chatbotID] = isPseudo
+	}
+	for _, participantID := range stored.GetSenderKeyParticipants() {
+		ssgsd.groupChatHandler.RestoreReceivingGroupSession(participantID)
+	}
+	ssgsd.sentMessageCount = int(stored.GetSentMessageCount())
+	ssgsd.senderKeyRotationInterval = int(stored.GetSenderKeyRotationInterval())
+	for userID, index := range stored.GetTreeKEMIndices() {
+		ssgsd.treekemIndices[userID] = int(index)
+	}
+
+	var err error
+	ssgsd.treekemState, ssgsd.multiTreeKEM, ssgsd.multiTreeKEMExternal, err = restoreTreeKEM(stored)
+	return err
+}
+
+/*
+export returns the state of the client-side group.
+*/
+func (csgsd *ClientSideGroupSessionDriver) export() (*pb.StoredGroup, error) {
+	stored := &pb.StoredGroup{
+		Type:            pb.GroupType_CLIENT_SIDE,
+		GroupID:         csgsd.groupID,
+		Participants:    csgsd.groupParticipants,
+		Chatbots:        csgsd.groupChatbots,
+		ChatbotIsIGA:    csgsd.chatbotIsIGA,
+		ChatbotIsPseudo: csgsd.chatbotIsPseudo,
+		CipherSuite:     uint32(csgsd.GetCipherSuite().ID()),
+	}
+
+	var err error
+	if stored.TreeKEMState, stored.MultiTreeKEM, stored.MultiTreeKEMExternal, err = exportTreeKEM(csgsd.treekemState, csgsd.multiTreeKEM, csgsd.multiTreeKEMExternal); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+/*
+restore loads the state of the client-side group into the driver.
+*/
+func (csgsd *ClientSideGroupSessionDriver) restore(stored *pb.StoredGroup) error {
+	if err := csgsd.SetCipherSuite(treekem.CipherSuiteID(stored.GetCipherSuite())); err != nil {
+		return err
+	}
+	for chatbotID, isIGA := range stored.GetChatbotIsIGA() {
+		csgsd.chatbotIsIGA[chatbotID] = isIGA
+	}
+	for chatbotID, isPseudo := range stored.GetChatbotIsPseudo() {
+		csgsd.chatbotIsPseudo[chatbotID] = isPseudo
+	}
+
+	var err error
+	csgsd.treekemState, csgsd.multiTreeKEM, csgsd.multiTreeKEMExternal, err = restoreTreeKEM(stored)
+	return err
+}
+
+/*
+export returns the state of the MLS group.
+*/
+func (mgsd *MlsGroupSessionDriver) export() (*pb.StoredGroup, error) {
+	stored := &pb.StoredGroup{
+		Type:              pb.GroupType_MLS,
+		GroupID:           mgsd.groupID,
+		Participants:      mgsd.groupParticipants,
+		Chatbots:          mgsd.groupChatbots,
+		ChatbotIsIGA:      mgsd.chatbotIsIGA,
+		ChatbotIsPseudo:   mgsd.chatbotIsPseudo,
+		CipherSuite:       uint32(mgsd.GetCipherSuite().ID()),
+		MemberToLeafIndex: mgsd.memberToLeafIndex,
+	}
+
+	var err error
+	if mgsd.groupChatState != nil {
+		if stored.MlsStatePublic, stored.MlsStateSecrets, err = util.SerializeMLSState(mgsd.groupChatState); err != nil {
+			return nil, err
+		}
+	}
+	if mgsd.mlsMultiTree != nil {
+		if stored.MlsMultiTree, err = mgsd.mlsMultiTree.Marshal(); err != nil {
+			return nil, err
+		}
+	}
+	if mgsd.mlsMultiTreeExternal != nil {
+		if stored.MlsMultiTreeExternal, err = mgsd.mlsMultiTreeExternal.Marshal(); err != nil {
+			return nil, err
+		}
+	}
+	return stored, nil
+}
+
+/*
+restore loads the state of the MLS group into the driver.
+*/
+func (mgsd *MlsGroupSessionDriver) restore(stored *pb.StoredGroup) error {
+	if err := mgsd.SetCipherSuite(treekem.CipherSuiteID(stored.GetCipherSuite())); err != nil {
+		return err
+	}
+	for chatbotID, isIGA := range stored.GetChatbotIsIGA() {
+		mgsd.chatbotIsIGA[chatbotID] = isIGA
+	}
+	for chatbotID, isPseudo := range stored.GetChatbotIsPseudo() {
+		mgsd.chatbotIsPseudo[chatbotID] = isPseudo
+	}
+	for memberID, leafIndex := range stored.GetMemberToLeafIndex() {
+		mgsd.memberToLeafIndex[memberID] = leafIndex
+	}
+
+	var err error
+	if stored.GetMlsStatePublic() != nil {
+		if mgsd.groupChatState, err = util.DeserializeMLSState(stored.GetMlsStatePublic(), stored.GetMlsStateSecrets()); err != nil {
+			return err
+		}
+	}
+	if stored.GetMlsMultiTree() != nil {
+		// The MlsMultiTree follows the MLS state of the driver, as it does when it is initiated
+		if mgsd.mlsMultiTree, err = treekem.UnmarshalMlsMultiTree(stored.GetMlsMultiTree(), &mgsd.groupChatState); err != nil {
+			return err
+		}
+	}
+	if stored.GetMlsMultiTreeExternal() != nil {
+		if mgsd.mlsMultiTreeExternal, err = treekem.UnmarshalMlsMultiTreeExternal(stored.GetMlsMultiTreeExternal()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+exportTreeKEM serializes the TreeKEM, MultiTreeKEM and MultiTreeKEMExternal of a group. The TreeKEM is serialized as
+part of the MultiTreeKEM once the latter wraps it.
+*/
+func exportTreeKEM(treekemState *treekem.TreeKEMState, multiTreeKEM *treekem.MultiTreeKEM, multiTreeKEMExternal *treekem.MultiTreeKEMExternal) ([]byte, []byte, []byte, error) {
+	var storedTreeKEM, storedMultiTreeKEM, storedMultiTreeKEMExternal []byte
+	var err error
+	if multiTreeKEM != nil {
+		if storedMultiTreeKEM, err = multiTreeKEM.Marshal(); err != nil {
+			return nil, nil, nil, err
+		}
+	} else if treekemState != nil {
+		if storedTreeKEM, err = treekemState.Marshal(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if multiTreeKEMExternal != nil {
+		if storedMultiTreeKEMExternal, err = multiTreeKEMExternal.Marshal(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return storedTreeKEM, storedMultiTreeKEM, storedMultiTreeKEMExternal, nil
+}
+
+/*
+restoreTreeKEM loads the TreeKEM, MultiTreeKEM and MultiTreeKEMExternal serialized by exportTreeKEM.
+*/
+func restoreTreeKEM(stored *pb.StoredGroup) (*treekem.TreeKEMState, *treekem.MultiTreeKEM, *treekem.MultiTreeKEMExternal, error) {
+	var treekemState *treekem.TreeKEMState
+	var multiTreeKEM *treekem.MultiTreeKEM
+	var multiTreeKEMExternal *treekem.MultiTreeKEMExternal
+	var err error
+	if stored.GetMultiTreeKEM() != nil {
+		if multiTreeKEM, err = treekem.UnmarshalMultiTreeKEM(stored.GetMultiTreeKEM()); err != nil {
+			return nil, nil, nil, err
+		}
+		treekemState = multiTreeKEM.GetTreeKEM()
+	} else if stored.GetTreeKEMState() != nil {
+		if treekemState, err = treekem.UnmarshalTreeKEMState(stored.GetTreeKEMState()); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if stored.GetMultiTreeKEMExternal() != nil {
+		if multiTreeKEMExternal, err = treekem.UnmarshalMultiTreeKEMExternal(stored.GetMultiTreeKEMExternal()); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return treekemState, multiTreeKEM, multiTreeKEMExternal, nil
+}
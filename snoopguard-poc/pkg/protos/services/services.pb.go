@@ -79,17 +79,27 @@ const (
 	MessageType_PSEUDONYM_REGISTRATION_MESSAGE  MessageType = 3
 	MessageType_VALIDATION_MESSAGE              MessageType = 4
 	MessageType_SKIP                            MessageType = 5
+	MessageType_ROOT_RECOVERY_REQUEST           MessageType = 6
+	MessageType_ROOT_RECOVERY                   MessageType = 7
+	MessageType_GROUP_REJOIN_REQUEST            MessageType = 8
+	MessageType_DELIVERY_RECEIPT                MessageType = 9
+	MessageType_READ_RECEIPT                    MessageType = 10
 )
 
 // Enum value maps for MessageType.
 var (
 	MessageType_name = map[int32]string{
-		0: "TEXT_MESSAGE",
-		1: "SENDER_KEY_DISTRIBUTION_MESSAGE",
-		2: "CLIENT_SIDE_GROUP_MESSAGE",
-		3: "PSEUDONYM_REGISTRATION_MESSAGE",
-		4: "VALIDATION_MESSAGE",
-		5: "SKIP",
+		0:  "TEXT_MESSAGE",
+		1:  "SENDER_KEY_DISTRIBUTION_MESSAGE",
+		2:  "CLIENT_SIDE_GROUP_MESSAGE",
+		3:  "PSEUDONYM_REGISTRATION_MESSAGE",
+		4:  "VALIDATION_MESSAGE",
+		5:  "SKIP",
+		6:  "ROOT_RECOVERY_REQUEST",
+		7:  "ROOT_RECOVERY",
+		8:  "GROUP_REJOIN_REQUEST",
+		9:  "DELIVERY_RECEIPT",
+		10: "READ_RECEIPT",
 	}
 	MessageType_value = map[string]int32{
 		"TEXT_MESSAGE":                    0,
@@ -98,6 +108,11 @@ var (
 		"PSEUDONYM_REGISTRATION_MESSAGE":  3,
 		"VALIDATION_MESSAGE":              4,
 		"SKIP":                            5,
+		"ROOT_RECOVERY_REQUEST":           6,
+		"ROOT_RECOVERY":                   7,
+		"GROUP_REJOIN_REQUEST":            8,
+		"DELIVERY_RECEIPT":                9,
+		"READ_RECEIPT":                    10,
 	}
 )
 
@@ -128,15 +143,70 @@ func (MessageType) EnumDescriptor() ([]byte, []int) {
 	return file_protos_services_services_proto_rawDescGZIP(), []int{1}
 }
 
+// The channel a message was received over. Messages over the IGA channel do not reveal their sender, and messages over
+// the pseudonymous channel reveal only the pseudonym of their sender.
+type Channel int32
+
+const (
+	Channel_GROUP_CHANNEL      Channel = 0
+	Channel_INDIVIDUAL_CHANNEL Channel = 1
+	Channel_IGA_CHANNEL        Channel = 2
+	Channel_PSEUDO_CHANNEL     Channel = 3
+)
+
+// Enum value maps for Channel.
+var (
+	Channel_name = map[int32]string{
+		0: "GROUP_CHANNEL",
+		1: "INDIVIDUAL_CHANNEL",
+		2: "IGA_CHANNEL",
+		3: "PSEUDO_CHANNEL",
+	}
+	Channel_value = map[string]int32{
+		"GROUP_CHANNEL":      0,
+		"INDIVIDUAL_CHANNEL": 1,
+		"IGA_CHANNEL":        2,
+		"PSEUDO_CHANNEL":     3,
+	}
+)
+
+func (x Channel) Enum() *Channel {
+	p := new(Channel)
+	*p = x
+	return p
+}
+
+func (x Channel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Channel) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_services_services_proto_enumTypes[2].Descriptor()
+}
+
+func (Channel) Type() protoreflect.EnumType {
+	return &file_protos_services_services_proto_enumTypes[2]
+}
+
+func (x Channel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Channel.Descriptor instead.
+func (Channel) EnumDescriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{2}
+}
+
 type ServerEventType int32
 
 const (
-	ServerEventType_GROUP_INVITATION         ServerEventType = 0
-	ServerEventType_GROUP_ADDITION           ServerEventType = 1
-	ServerEventType_GROUP_REMOVAL            ServerEventType = 2
-	ServerEventType_GROUP_CHATBOT_INVITATION ServerEventType = 3
-	ServerEventType_GROUP_CHATBOT_ADDITION   ServerEventType = 4
-	ServerEventType_GROUP_CHATBOT_REMOVAL    ServerEventType = 5
+	ServerEventType_GROUP_INVITATION           ServerEventType = 0
+	ServerEventType_GROUP_ADDITION             ServerEventType = 1
+	ServerEventType_GROUP_REMOVAL              ServerEventType = 2
+	ServerEventType_GROUP_CHATBOT_INVITATION   ServerEventType = 3
+	ServerEventType_GROUP_CHATBOT_ADDITION     ServerEventType = 4
+	ServerEventType_GROUP_CHATBOT_REMOVAL      ServerEventType = 5
+	ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE ServerEventType = 6
 )
 
 // Enum value maps for ServerEventType.
@@ -148,14 +218,16 @@ var (
 		3: "GROUP_CHATBOT_INVITATION",
 		4: "GROUP_CHATBOT_ADDITION",
 		5: "GROUP_CHATBOT_REMOVAL",
+		6: "GROUP_CHATBOT_SCOPE_UPDATE",
 	}
 	ServerEventType_value = map[string]int32{
-		"GROUP_INVITATION":         0,
-		"GROUP_ADDITION":           1,
-		"GROUP_REMOVAL":            2,
-		"GROUP_CHATBOT_INVITATION": 3,
-		"GROUP_CHATBOT_ADDITION":   4,
-		"GROUP_CHATBOT_REMOVAL":    5,
+		"GROUP_INVITATION":           0,
+		"GROUP_ADDITION":             1,
+		"GROUP_REMOVAL":              2,
+		"GROUP_CHATBOT_INVITATION":   3,
+		"GROUP_CHATBOT_ADDITION":     4,
+		"GROUP_CHATBOT_REMOVAL":      5,
+		"GROUP_CHATBOT_SCOPE_UPDATE": 6,
 	}
 )
 
@@ -170,11 +242,11 @@ func (x ServerEventType) String() string {
 }
 
 func (ServerEventType) Descriptor() protoreflect.EnumDescriptor {
-	return file_protos_services_services_proto_enumTypes[2].Descriptor()
+	return file_protos_services_services_proto_enumTypes[3].Descriptor()
 }
 
 func (ServerEventType) Type() protoreflect.EnumType {
-	return &file_protos_services_services_proto_enumTypes[2]
+	return &file_protos_services_services_proto_enumTypes[3]
 }
 
 func (x ServerEventType) Number() protoreflect.EnumNumber {
@@ -183,7 +255,7 @@ func (x ServerEventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ServerEventType.Descriptor instead.
 func (ServerEventType) EnumDescriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{2}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{3}
 }
 
 // Upload PreKey
@@ -1278,9 +1350,10 @@ type SetChatbotRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChatbotID         string `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
-	IdentityKeyPublic []byte `protobuf:"bytes,2,opt,name=identityKeyPublic,proto3" json:"identityKeyPublic,omitempty"`
-	RegistrationID    uint32 `protobuf:"varint,3,opt,name=registrationID,proto3" json:"registrationID,omitempty"`
+	ChatbotID         string          `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
+	IdentityKeyPublic []byte          `protobuf:"bytes,2,opt,name=identityKeyPublic,proto3" json:"identityKeyPublic,omitempty"`
+	RegistrationID    uint32          `protobuf:"varint,3,opt,name=registrationID,proto3" json:"registrationID,omitempty"`
+	ChatbotRouting    *ChatbotRouting `protobuf:"bytes,4,opt,name=chatbotRouting,proto3" json:"chatbotRouting,omitempty"`
 }
 
 func (x *SetChatbotRequest) Reset() {
@@ -1336,6 +1409,133 @@ func (x *SetChatbotRequest) GetRegistrationID() uint32 {
 	return 0
 }
 
+func (x *SetChatbotRequest) GetChatbotRouting() *ChatbotRouting {
+	if x != nil {
+		return x.ChatbotRouting
+	}
+	return nil
+}
+
+type ChatbotRouting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MentionNames    []string `protobuf:"bytes,1,rep,name=mentionNames,proto3" json:"mentionNames,omitempty"`
+	CommandPrefixes []string `protobuf:"bytes,2,rep,name=commandPrefixes,proto3" json:"commandPrefixes,omitempty"`
+}
+
+func (x *ChatbotRouting) Reset() {
+	*x = ChatbotRouting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatbotRouting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatbotRouting) ProtoMessage() {}
+
+func (x *ChatbotRouting) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatbotRouting.ProtoReflect.Descriptor instead.
+func (*ChatbotRouting) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChatbotRouting) GetMentionNames() []string {
+	if x != nil {
+		return x.MentionNames
+	}
+	return nil
+}
+
+func (x *ChatbotRouting) GetCommandPrefixes() []string {
+	if x != nil {
+		return x.CommandPrefixes
+	}
+	return nil
+}
+
+// The routing of a chatbot in a group, signed by the member who invited it, so that the server cannot change which
+// messages trigger the chatbot.
+type SignedChatbotRouting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignerID  string          `protobuf:"bytes,1,opt,name=signerID,proto3" json:"signerID,omitempty"`
+	Routing   *ChatbotRouting `protobuf:"bytes,2,opt,name=routing,proto3" json:"routing,omitempty"`
+	Signature []byte          `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *SignedChatbotRouting) Reset() {
+	*x = SignedChatbotRouting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedChatbotRouting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedChatbotRouting) ProtoMessage() {}
+
+func (x *SignedChatbotRouting) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedChatbotRouting.ProtoReflect.Descriptor instead.
+func (*SignedChatbotRouting) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SignedChatbotRouting) GetSignerID() string {
+	if x != nil {
+		return x.SignerID
+	}
+	return ""
+}
+
+func (x *SignedChatbotRouting) GetRouting() *ChatbotRouting {
+	if x != nil {
+		return x.Routing
+	}
+	return nil
+}
+
+func (x *SignedChatbotRouting) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
 type SetChatbotResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1348,7 +1548,7 @@ type SetChatbotResponse struct {
 func (x *SetChatbotResponse) Reset() {
 	*x = SetChatbotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[19]
+		mi := &file_protos_services_services_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1361,7 +1561,7 @@ func (x *SetChatbotResponse) String() string {
 func (*SetChatbotResponse) ProtoMessage() {}
 
 func (x *SetChatbotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[19]
+	mi := &file_protos_services_services_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1374,7 +1574,7 @@ func (x *SetChatbotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetChatbotResponse.ProtoReflect.Descriptor instead.
 func (*SetChatbotResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{19}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *SetChatbotResponse) GetSuccess() bool {
@@ -1402,7 +1602,7 @@ type GetChatbotRequest struct {
 func (x *GetChatbotRequest) Reset() {
 	*x = GetChatbotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[20]
+		mi := &file_protos_services_services_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1415,7 +1615,7 @@ func (x *GetChatbotRequest) String() string {
 func (*GetChatbotRequest) ProtoMessage() {}
 
 func (x *GetChatbotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[20]
+	mi := &file_protos_services_services_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1428,7 +1628,7 @@ func (x *GetChatbotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetChatbotRequest.ProtoReflect.Descriptor instead.
 func (*GetChatbotRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{20}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetChatbotRequest) GetChatbotID() string {
@@ -1443,17 +1643,18 @@ type GetChatbotResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChatbotID         string `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
-	IdentityKeyPublic []byte `protobuf:"bytes,2,opt,name=identityKeyPublic,proto3" json:"identityKeyPublic,omitempty"`
-	RegistrationID    uint32 `protobuf:"varint,3,opt,name=registrationID,proto3" json:"registrationID,omitempty"`
-	Success           bool   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage      string `protobuf:"bytes,5,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	ChatbotID         string          `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
+	IdentityKeyPublic []byte          `protobuf:"bytes,2,opt,name=identityKeyPublic,proto3" json:"identityKeyPublic,omitempty"`
+	RegistrationID    uint32          `protobuf:"varint,3,opt,name=registrationID,proto3" json:"registrationID,omitempty"`
+	Success           bool            `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage      string          `protobuf:"bytes,5,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	ChatbotRouting    *ChatbotRouting `protobuf:"bytes,6,opt,name=chatbotRouting,proto3" json:"chatbotRouting,omitempty"`
 }
 
 func (x *GetChatbotResponse) Reset() {
 	*x = GetChatbotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[21]
+		mi := &file_protos_services_services_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1466,7 +1667,7 @@ func (x *GetChatbotResponse) String() string {
 func (*GetChatbotResponse) ProtoMessage() {}
 
 func (x *GetChatbotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[21]
+	mi := &file_protos_services_services_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1479,7 +1680,7 @@ func (x *GetChatbotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetChatbotResponse.ProtoReflect.Descriptor instead.
 func (*GetChatbotResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{21}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetChatbotResponse) GetChatbotID() string {
@@ -1517,6 +1718,13 @@ func (x *GetChatbotResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *GetChatbotResponse) GetChatbotRouting() *ChatbotRouting {
+	if x != nil {
+		return x.ChatbotRouting
+	}
+	return nil
+}
+
 type CreateGroupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1524,12 +1732,14 @@ type CreateGroupRequest struct {
 
 	InitiatorID string    `protobuf:"bytes,1,opt,name=initiatorID,proto3" json:"initiatorID,omitempty"`
 	GroupType   GroupType `protobuf:"varint,2,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	// The treekem.CipherSuiteID of the group. Zero selects the default suite.
+	CipherSuite uint32 `protobuf:"varint,3,opt,name=cipherSuite,proto3" json:"cipherSuite,omitempty"`
 }
 
 func (x *CreateGroupRequest) Reset() {
 	*x = CreateGroupRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[22]
+		mi := &file_protos_services_services_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1542,7 +1752,7 @@ func (x *CreateGroupRequest) String() string {
 func (*CreateGroupRequest) ProtoMessage() {}
 
 func (x *CreateGroupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[22]
+	mi := &file_protos_services_services_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1555,7 +1765,7 @@ func (x *CreateGroupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateGroupRequest.ProtoReflect.Descriptor instead.
 func (*CreateGroupRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{22}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CreateGroupRequest) GetInitiatorID() string {
@@ -1572,6 +1782,13 @@ func (x *CreateGroupRequest) GetGroupType() GroupType {
 	return GroupType_CLIENT_SIDE
 }
 
+func (x *CreateGroupRequest) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
 type CreateGroupResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1585,7 +1802,7 @@ type CreateGroupResponse struct {
 func (x *CreateGroupResponse) Reset() {
 	*x = CreateGroupResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[23]
+		mi := &file_protos_services_services_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1598,7 +1815,7 @@ func (x *CreateGroupResponse) String() string {
 func (*CreateGroupResponse) ProtoMessage() {}
 
 func (x *CreateGroupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[23]
+	mi := &file_protos_services_services_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1611,7 +1828,7 @@ func (x *CreateGroupResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateGroupResponse.ProtoReflect.Descriptor instead.
 func (*CreateGroupResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{23}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *CreateGroupResponse) GetGroupID() string {
@@ -1646,7 +1863,7 @@ type GetGroupRequest struct {
 func (x *GetGroupRequest) Reset() {
 	*x = GetGroupRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[24]
+		mi := &file_protos_services_services_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1659,7 +1876,7 @@ func (x *GetGroupRequest) String() string {
 func (*GetGroupRequest) ProtoMessage() {}
 
 func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[24]
+	mi := &file_protos_services_services_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1672,7 +1889,7 @@ func (x *GetGroupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetGroupRequest.ProtoReflect.Descriptor instead.
 func (*GetGroupRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{24}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetGroupRequest) GetGroupID() string {
@@ -1692,12 +1909,13 @@ type GetGroupResponse struct {
 	GroupType      GroupType `protobuf:"varint,3,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
 	Success        bool      `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
 	ErrorMessage   string    `protobuf:"bytes,5,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	CipherSuite    uint32    `protobuf:"varint,6,opt,name=cipherSuite,proto3" json:"cipherSuite,omitempty"`
 }
 
 func (x *GetGroupResponse) Reset() {
 	*x = GetGroupResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[25]
+		mi := &file_protos_services_services_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1710,7 +1928,7 @@ func (x *GetGroupResponse) String() string {
 func (*GetGroupResponse) ProtoMessage() {}
 
 func (x *GetGroupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[25]
+	mi := &file_protos_services_services_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1723,7 +1941,7 @@ func (x *GetGroupResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetGroupResponse.ProtoReflect.Descriptor instead.
 func (*GetGroupResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{25}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetGroupResponse) GetGroupID() string {
@@ -1761,6 +1979,13 @@ func (x *GetGroupResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *GetGroupResponse) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
 type InviteMemberRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1779,12 +2004,14 @@ type InviteMemberRequest struct {
 	MlsKeyPackageID            uint32                    `protobuf:"varint,11,opt,name=mlsKeyPackageID,proto3" json:"mlsKeyPackageID,omitempty"`
 	MlsUserAdd                 []byte                    `protobuf:"bytes,12,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
 	MlsAddCommit               []byte                    `protobuf:"bytes,13,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
+	TreeKEMIndices             map[string]uint32         `protobuf:"bytes,14,rep,name=treeKEMIndices,proto3" json:"treeKEMIndices,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	TreeKEMPublicTree          map[uint32]*TreeKEMNode   `protobuf:"bytes,15,rep,name=treeKEMPublicTree,proto3" json:"treeKEMPublicTree,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *InviteMemberRequest) Reset() {
 	*x = InviteMemberRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[26]
+		mi := &file_protos_services_services_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1797,7 +2024,7 @@ func (x *InviteMemberRequest) String() string {
 func (*InviteMemberRequest) ProtoMessage() {}
 
 func (x *InviteMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[26]
+	mi := &file_protos_services_services_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1810,7 +2037,7 @@ func (x *InviteMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InviteMemberRequest.ProtoReflect.Descriptor instead.
 func (*InviteMemberRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{26}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *InviteMemberRequest) GetGroupID() string {
@@ -1904,6 +2131,20 @@ func (x *InviteMemberRequest) GetMlsAddCommit() []byte {
 	return nil
 }
 
+func (x *InviteMemberRequest) GetTreeKEMIndices() map[string]uint32 {
+	if x != nil {
+		return x.TreeKEMIndices
+	}
+	return nil
+}
+
+func (x *InviteMemberRequest) GetTreeKEMPublicTree() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.TreeKEMPublicTree
+	}
+	return nil
+}
+
 type InviteMemberResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1916,7 +2157,7 @@ type InviteMemberResponse struct {
 func (x *InviteMemberResponse) Reset() {
 	*x = InviteMemberResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[27]
+		mi := &file_protos_services_services_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1929,7 +2170,7 @@ func (x *InviteMemberResponse) String() string {
 func (*InviteMemberResponse) ProtoMessage() {}
 
 func (x *InviteMemberResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[27]
+	mi := &file_protos_services_services_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1942,7 +2183,7 @@ func (x *InviteMemberResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InviteMemberResponse.ProtoReflect.Descriptor instead.
 func (*InviteMemberResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{27}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *InviteMemberResponse) GetSuccess() bool {
@@ -1964,17 +2205,18 @@ type RemoveMemberRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupID         string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	InitiatorID     string `protobuf:"bytes,2,opt,name=initiatorID,proto3" json:"initiatorID,omitempty"`
-	RemovedID       string `protobuf:"bytes,3,opt,name=removedID,proto3" json:"removedID,omitempty"`
-	MlsRemove       []byte `protobuf:"bytes,4,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
-	MlsRemoveCommit []byte `protobuf:"bytes,5,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
+	GroupID           string             `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	InitiatorID       string             `protobuf:"bytes,2,opt,name=initiatorID,proto3" json:"initiatorID,omitempty"`
+	RemovedID         string             `protobuf:"bytes,3,opt,name=removedID,proto3" json:"removedID,omitempty"`
+	MlsRemove         []byte             `protobuf:"bytes,4,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
+	MlsRemoveCommit   []byte             `protobuf:"bytes,5,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
+	TreeKEMUserRemove *TreeKEMUserRemove `protobuf:"bytes,6,opt,name=treeKEMUserRemove,proto3" json:"treeKEMUserRemove,omitempty"`
 }
 
 func (x *RemoveMemberRequest) Reset() {
 	*x = RemoveMemberRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[28]
+		mi := &file_protos_services_services_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1987,7 +2229,7 @@ func (x *RemoveMemberRequest) String() string {
 func (*RemoveMemberRequest) ProtoMessage() {}
 
 func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[28]
+	mi := &file_protos_services_services_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2000,7 +2242,7 @@ func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
 func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{28}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *RemoveMemberRequest) GetGroupID() string {
@@ -2038,6 +2280,13 @@ func (x *RemoveMemberRequest) GetMlsRemoveCommit() []byte {
 	return nil
 }
 
+func (x *RemoveMemberRequest) GetTreeKEMUserRemove() *TreeKEMUserRemove {
+	if x != nil {
+		return x.TreeKEMUserRemove
+	}
+	return nil
+}
+
 type RemoveMemberResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -2050,7 +2299,7 @@ type RemoveMemberResponse struct {
 func (x *RemoveMemberResponse) Reset() {
 	*x = RemoveMemberResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[29]
+		mi := &file_protos_services_services_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2063,7 +2312,7 @@ func (x *RemoveMemberResponse) String() string {
 func (*RemoveMemberResponse) ProtoMessage() {}
 
 func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[29]
+	mi := &file_protos_services_services_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2076,7 +2325,7 @@ func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveMemberResponse.ProtoReflect.Descriptor instead.
 func (*RemoveMemberResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{29}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *RemoveMemberResponse) GetSuccess() bool {
@@ -2111,12 +2360,17 @@ type InviteChatbotRequest struct {
 	MlsKeyPackageID    uint32           `protobuf:"varint,11,opt,name=mlsKeyPackageID,proto3" json:"mlsKeyPackageID,omitempty"`
 	MlsUserAdd         []byte           `protobuf:"bytes,12,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
 	MlsAddCommit       []byte           `protobuf:"bytes,13,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
+	Scopes             *ChatbotScopes   `protobuf:"bytes,14,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	// Whether the TreeKEM ciphertexts to and from the chatbot use the hybrid ML-KEM-768 + X25519 KEM.
+	HybridKEM            bool                  `protobuf:"varint,15,opt,name=hybridKEM,proto3" json:"hybridKEM,omitempty"`
+	SignedChatbotRouting *SignedChatbotRouting `protobuf:"bytes,16,opt,name=signedChatbotRouting,proto3" json:"signedChatbotRouting,omitempty"`
+	SignedScopes         *SignedChatbotScopes  `protobuf:"bytes,17,opt,name=signedScopes,proto3" json:"signedScopes,omitempty"`
 }
 
 func (x *InviteChatbotRequest) Reset() {
 	*x = InviteChatbotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[30]
+		mi := &file_protos_services_services_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2129,7 +2383,7 @@ func (x *InviteChatbotRequest) String() string {
 func (*InviteChatbotRequest) ProtoMessage() {}
 
 func (x *InviteChatbotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[30]
+	mi := &file_protos_services_services_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2142,7 +2396,7 @@ func (x *InviteChatbotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InviteChatbotRequest.ProtoReflect.Descriptor instead.
 func (*InviteChatbotRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{30}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *InviteChatbotRequest) GetGroupID() string {
@@ -2236,32 +2490,60 @@ func (x *InviteChatbotRequest) GetMlsAddCommit() []byte {
 	return nil
 }
 
-type InviteChatbotResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+func (x *InviteChatbotRequest) GetScopes() *ChatbotScopes {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
 }
 
-func (x *InviteChatbotResponse) Reset() {
-	*x = InviteChatbotResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[31]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *InviteChatbotRequest) GetHybridKEM() bool {
+	if x != nil {
+		return x.HybridKEM
 	}
+	return false
 }
 
-func (x *InviteChatbotResponse) String() string {
+func (x *InviteChatbotRequest) GetSignedChatbotRouting() *SignedChatbotRouting {
+	if x != nil {
+		return x.SignedChatbotRouting
+	}
+	return nil
+}
+
+func (x *InviteChatbotRequest) GetSignedScopes() *SignedChatbotScopes {
+	if x != nil {
+		return x.SignedScopes
+	}
+	return nil
+}
+
+type InviteChatbotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+}
+
+func (x *InviteChatbotResponse) Reset() {
+	*x = InviteChatbotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InviteChatbotResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*InviteChatbotResponse) ProtoMessage() {}
 
 func (x *InviteChatbotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[31]
+	mi := &file_protos_services_services_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2274,7 +2556,7 @@ func (x *InviteChatbotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InviteChatbotResponse.ProtoReflect.Descriptor instead.
 func (*InviteChatbotResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{31}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *InviteChatbotResponse) GetSuccess() bool {
@@ -2306,7 +2588,7 @@ type RemoveChatbotRequest struct {
 func (x *RemoveChatbotRequest) Reset() {
 	*x = RemoveChatbotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[32]
+		mi := &file_protos_services_services_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2319,7 +2601,7 @@ func (x *RemoveChatbotRequest) String() string {
 func (*RemoveChatbotRequest) ProtoMessage() {}
 
 func (x *RemoveChatbotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[32]
+	mi := &file_protos_services_services_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2332,7 +2614,7 @@ func (x *RemoveChatbotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveChatbotRequest.ProtoReflect.Descriptor instead.
 func (*RemoveChatbotRequest) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{32}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *RemoveChatbotRequest) GetGroupID() string {
@@ -2382,7 +2664,7 @@ type RemoveChatbotResponse struct {
 func (x *RemoveChatbotResponse) Reset() {
 	*x = RemoveChatbotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[33]
+		mi := &file_protos_services_services_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2395,7 +2677,7 @@ func (x *RemoveChatbotResponse) String() string {
 func (*RemoveChatbotResponse) ProtoMessage() {}
 
 func (x *RemoveChatbotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[33]
+	mi := &file_protos_services_services_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2408,7 +2690,7 @@ func (x *RemoveChatbotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveChatbotResponse.ProtoReflect.Descriptor instead.
 func (*RemoveChatbotResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{33}
+	return file_protos_services_services_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *RemoveChatbotResponse) GetSuccess() bool {
@@ -2425,31 +2707,36 @@ func (x *RemoveChatbotResponse) GetErrorMessage() string {
 	return ""
 }
 
-type MessageStreamInit struct {
+type ChatbotScopes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Commands         bool `protobuf:"varint,1,opt,name=commands,proto3" json:"commands,omitempty"`
+	Mentions         bool `protobuf:"varint,2,opt,name=mentions,proto3" json:"mentions,omitempty"`
+	AllMessages      bool `protobuf:"varint,3,opt,name=allMessages,proto3" json:"allMessages,omitempty"`
+	MembershipEvents bool `protobuf:"varint,4,opt,name=membershipEvents,proto3" json:"membershipEvents,omitempty"`
+	MayPost          bool `protobuf:"varint,5,opt,name=mayPost,proto3" json:"mayPost,omitempty"`
+	MayDirectMessage bool `protobuf:"varint,6,opt,name=mayDirectMessage,proto3" json:"mayDirectMessage,omitempty"`
 }
 
-func (x *MessageStreamInit) Reset() {
-	*x = MessageStreamInit{}
+func (x *ChatbotScopes) Reset() {
+	*x = ChatbotScopes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[34]
+		mi := &file_protos_services_services_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MessageStreamInit) String() string {
+func (x *ChatbotScopes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MessageStreamInit) ProtoMessage() {}
+func (*ChatbotScopes) ProtoMessage() {}
 
-func (x *MessageStreamInit) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[34]
+func (x *ChatbotScopes) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2460,101 +2747,83 @@ func (x *MessageStreamInit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MessageStreamInit.ProtoReflect.Descriptor instead.
-func (*MessageStreamInit) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use ChatbotScopes.ProtoReflect.Descriptor instead.
+func (*ChatbotScopes) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *MessageStreamInit) GetUserID() string {
+func (x *ChatbotScopes) GetCommands() bool {
 	if x != nil {
-		return x.UserID
+		return x.Commands
 	}
-	return ""
-}
-
-type SendMessageResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	return false
 }
 
-func (x *SendMessageResponse) Reset() {
-	*x = SendMessageResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[35]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *ChatbotScopes) GetMentions() bool {
+	if x != nil {
+		return x.Mentions
 	}
+	return false
 }
 
-func (x *SendMessageResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*SendMessageResponse) ProtoMessage() {}
-
-func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[35]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *ChatbotScopes) GetAllMessages() bool {
+	if x != nil {
+		return x.AllMessages
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{35}
+func (x *ChatbotScopes) GetMembershipEvents() bool {
+	if x != nil {
+		return x.MembershipEvents
+	}
+	return false
 }
 
-func (x *SendMessageResponse) GetSuccess() bool {
+func (x *ChatbotScopes) GetMayPost() bool {
 	if x != nil {
-		return x.Success
+		return x.MayPost
 	}
 	return false
 }
 
-func (x *SendMessageResponse) GetErrorMessage() string {
+func (x *ChatbotScopes) GetMayDirectMessage() bool {
 	if x != nil {
-		return x.ErrorMessage
+		return x.MayDirectMessage
 	}
-	return ""
+	return false
 }
 
-type Message struct {
+// The access scopes of a chatbot in a group, signed by the member who invited it. The version grows with every change,
+// so that the server can neither change the scopes nor replay older ones.
+type SignedChatbotScopes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MessageType MessageType `protobuf:"varint,1,opt,name=messageType,proto3,enum=Services.MessageType" json:"messageType,omitempty"`
-	Message     []byte      `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	ChatbotIDs  []string    `protobuf:"bytes,3,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
-	Signature   []byte      `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	SignerID  string         `protobuf:"bytes,1,opt,name=signerID,proto3" json:"signerID,omitempty"`
+	Scopes    *ChatbotScopes `protobuf:"bytes,2,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	Version   uint64         `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Signature []byte         `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
+func (x *SignedChatbotScopes) Reset() {
+	*x = SignedChatbotScopes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[36]
+		mi := &file_protos_services_services_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message) String() string {
+func (x *SignedChatbotScopes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message) ProtoMessage() {}
+func (*SignedChatbotScopes) ProtoMessage() {}
 
-func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[36]
+func (x *SignedChatbotScopes) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2565,67 +2834,68 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use SignedChatbotScopes.ProtoReflect.Descriptor instead.
+func (*SignedChatbotScopes) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *Message) GetMessageType() MessageType {
+func (x *SignedChatbotScopes) GetSignerID() string {
 	if x != nil {
-		return x.MessageType
+		return x.SignerID
 	}
-	return MessageType_TEXT_MESSAGE
+	return ""
 }
 
-func (x *Message) GetMessage() []byte {
+func (x *SignedChatbotScopes) GetScopes() *ChatbotScopes {
 	if x != nil {
-		return x.Message
+		return x.Scopes
 	}
 	return nil
 }
 
-func (x *Message) GetChatbotIDs() []string {
+func (x *SignedChatbotScopes) GetVersion() uint64 {
 	if x != nil {
-		return x.ChatbotIDs
+		return x.Version
 	}
-	return nil
+	return 0
 }
 
-func (x *Message) GetSignature() []byte {
+func (x *SignedChatbotScopes) GetSignature() []byte {
 	if x != nil {
 		return x.Signature
 	}
 	return nil
 }
 
-type ChatbotMessage struct {
+type UpdateChatbotScopesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChatbotID        string          `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
-	MessageWrapper   *MessageWrapper `protobuf:"bytes,2,opt,name=messageWrapper,proto3" json:"messageWrapper,omitempty"`
-	UseNormalMessage bool            `protobuf:"varint,3,opt,name=useNormalMessage,proto3" json:"useNormalMessage,omitempty"`
-	ShouldSend       bool            `protobuf:"varint,4,opt,name=shouldSend,proto3" json:"shouldSend,omitempty"`
+	GroupID      string               `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	InitiatorID  string               `protobuf:"bytes,2,opt,name=initiatorID,proto3" json:"initiatorID,omitempty"`
+	ChatbotID    string               `protobuf:"bytes,3,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
+	Scopes       *ChatbotScopes       `protobuf:"bytes,4,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	SignedScopes *SignedChatbotScopes `protobuf:"bytes,5,opt,name=signedScopes,proto3" json:"signedScopes,omitempty"`
 }
 
-func (x *ChatbotMessage) Reset() {
-	*x = ChatbotMessage{}
+func (x *UpdateChatbotScopesRequest) Reset() {
+	*x = UpdateChatbotScopesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[37]
+		mi := &file_protos_services_services_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChatbotMessage) String() string {
+func (x *UpdateChatbotScopesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatbotMessage) ProtoMessage() {}
+func (*UpdateChatbotScopesRequest) ProtoMessage() {}
 
-func (x *ChatbotMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[37]
+func (x *UpdateChatbotScopesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2636,66 +2906,72 @@ func (x *ChatbotMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatbotMessage.ProtoReflect.Descriptor instead.
-func (*ChatbotMessage) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use UpdateChatbotScopesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChatbotScopesRequest) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ChatbotMessage) GetChatbotID() string {
+func (x *UpdateChatbotScopesRequest) GetGroupID() string {
 	if x != nil {
-		return x.ChatbotID
+		return x.GroupID
 	}
 	return ""
 }
 
-func (x *ChatbotMessage) GetMessageWrapper() *MessageWrapper {
+func (x *UpdateChatbotScopesRequest) GetInitiatorID() string {
 	if x != nil {
-		return x.MessageWrapper
+		return x.InitiatorID
 	}
-	return nil
+	return ""
 }
 
-func (x *ChatbotMessage) GetUseNormalMessage() bool {
+func (x *UpdateChatbotScopesRequest) GetChatbotID() string {
 	if x != nil {
-		return x.UseNormalMessage
+		return x.ChatbotID
 	}
-	return false
+	return ""
 }
 
-func (x *ChatbotMessage) GetShouldSend() bool {
+func (x *UpdateChatbotScopesRequest) GetScopes() *ChatbotScopes {
 	if x != nil {
-		return x.ShouldSend
+		return x.Scopes
 	}
-	return false
+	return nil
 }
 
-type ClientSideGroupMessage struct {
+func (x *UpdateChatbotScopesRequest) GetSignedScopes() *SignedChatbotScopes {
+	if x != nil {
+		return x.SignedScopes
+	}
+	return nil
+}
+
+type UpdateChatbotScopesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupID     string      `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	MessageType MessageType `protobuf:"varint,2,opt,name=messageType,proto3,enum=Services.MessageType" json:"messageType,omitempty"`
-	Message     []byte      `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
 }
 
-func (x *ClientSideGroupMessage) Reset() {
-	*x = ClientSideGroupMessage{}
+func (x *UpdateChatbotScopesResponse) Reset() {
+	*x = UpdateChatbotScopesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[38]
+		mi := &file_protos_services_services_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ClientSideGroupMessage) String() string {
+func (x *UpdateChatbotScopesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientSideGroupMessage) ProtoMessage() {}
+func (*UpdateChatbotScopesResponse) ProtoMessage() {}
 
-func (x *ClientSideGroupMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[38]
+func (x *UpdateChatbotScopesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2706,59 +2982,50 @@ func (x *ClientSideGroupMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientSideGroupMessage.ProtoReflect.Descriptor instead.
-func (*ClientSideGroupMessage) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{38}
-}
-
-func (x *ClientSideGroupMessage) GetGroupID() string {
-	if x != nil {
-		return x.GroupID
-	}
-	return ""
+// Deprecated: Use UpdateChatbotScopesResponse.ProtoReflect.Descriptor instead.
+func (*UpdateChatbotScopesResponse) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *ClientSideGroupMessage) GetMessageType() MessageType {
+func (x *UpdateChatbotScopesResponse) GetSuccess() bool {
 	if x != nil {
-		return x.MessageType
+		return x.Success
 	}
-	return MessageType_TEXT_MESSAGE
+	return false
 }
 
-func (x *ClientSideGroupMessage) GetMessage() []byte {
+func (x *UpdateChatbotScopesResponse) GetErrorMessage() string {
 	if x != nil {
-		return x.Message
+		return x.ErrorMessage
 	}
-	return nil
+	return ""
 }
 
-type SenderKeyDistributionMessage struct {
+type MessageStreamInit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupID                      string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	SenderKeyDistributionMessage []byte `protobuf:"bytes,2,opt,name=senderKeyDistributionMessage,proto3" json:"senderKeyDistributionMessage,omitempty"`
-	BounceBack                   bool   `protobuf:"varint,3,opt,name=bounceBack,proto3" json:"bounceBack,omitempty"`
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
 }
 
-func (x *SenderKeyDistributionMessage) Reset() {
-	*x = SenderKeyDistributionMessage{}
+func (x *MessageStreamInit) Reset() {
+	*x = MessageStreamInit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[39]
+		mi := &file_protos_services_services_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SenderKeyDistributionMessage) String() string {
+func (x *MessageStreamInit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SenderKeyDistributionMessage) ProtoMessage() {}
+func (*MessageStreamInit) ProtoMessage() {}
 
-func (x *SenderKeyDistributionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[39]
+func (x *MessageStreamInit) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2769,59 +3036,48 @@ func (x *SenderKeyDistributionMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SenderKeyDistributionMessage.ProtoReflect.Descriptor instead.
-func (*SenderKeyDistributionMessage) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use MessageStreamInit.ProtoReflect.Descriptor instead.
+func (*MessageStreamInit) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *SenderKeyDistributionMessage) GetGroupID() string {
+func (x *MessageStreamInit) GetUserID() string {
 	if x != nil {
-		return x.GroupID
+		return x.UserID
 	}
 	return ""
 }
 
-func (x *SenderKeyDistributionMessage) GetSenderKeyDistributionMessage() []byte {
-	if x != nil {
-		return x.SenderKeyDistributionMessage
-	}
-	return nil
-}
+type SendMessageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *SenderKeyDistributionMessage) GetBounceBack() bool {
-	if x != nil {
-		return x.BounceBack
-	}
-	return false
-}
-
-type PseudonymRegistrationMessage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	GroupID       string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	PseudoUserID  string `protobuf:"bytes,2,opt,name=pseudoUserID,proto3" json:"pseudoUserID,omitempty"`
-	SigningKeyPub []byte `protobuf:"bytes,3,opt,name=signingKeyPub,proto3" json:"signingKeyPub,omitempty"`
+	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	// The sequence number the server assigned to the key update of the message. If the key update was rejected because a
+	// concurrent one came first, the sequence number of the last key update delivered to the sender instead.
+	KeyUpdateSeq      uint64 `protobuf:"varint,3,opt,name=keyUpdateSeq,proto3" json:"keyUpdateSeq,omitempty"`
+	KeyUpdateConflict bool   `protobuf:"varint,4,opt,name=keyUpdateConflict,proto3" json:"keyUpdateConflict,omitempty"`
 }
 
-func (x *PseudonymRegistrationMessage) Reset() {
-	*x = PseudonymRegistrationMessage{}
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[40]
+		mi := &file_protos_services_services_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PseudonymRegistrationMessage) String() string {
+func (x *SendMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PseudonymRegistrationMessage) ProtoMessage() {}
+func (*SendMessageResponse) ProtoMessage() {}
 
-func (x *PseudonymRegistrationMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[40]
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2832,59 +3088,65 @@ func (x *PseudonymRegistrationMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PseudonymRegistrationMessage.ProtoReflect.Descriptor instead.
-func (*PseudonymRegistrationMessage) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *PseudonymRegistrationMessage) GetGroupID() string {
+func (x *SendMessageResponse) GetSuccess() bool {
 	if x != nil {
-		return x.GroupID
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *PseudonymRegistrationMessage) GetPseudoUserID() string {
+func (x *SendMessageResponse) GetErrorMessage() string {
 	if x != nil {
-		return x.PseudoUserID
+		return x.ErrorMessage
 	}
 	return ""
 }
 
-func (x *PseudonymRegistrationMessage) GetSigningKeyPub() []byte {
+func (x *SendMessageResponse) GetKeyUpdateSeq() uint64 {
 	if x != nil {
-		return x.SigningKeyPub
+		return x.KeyUpdateSeq
 	}
-	return nil
+	return 0
 }
 
-type ValidationMessage struct {
+func (x *SendMessageResponse) GetKeyUpdateConflict() bool {
+	if x != nil {
+		return x.KeyUpdateConflict
+	}
+	return false
+}
+
+// The messages a member acknowledges with a DELIVERY_RECEIPT or READ_RECEIPT, sent to the other members of the group.
+type Receipt struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupID             string      `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	PreviousMessage     []byte      `protobuf:"bytes,2,opt,name=previousMessage,proto3" json:"previousMessage,omitempty"`
-	PreviousMessageType MessageType `protobuf:"varint,3,opt,name=previousMessageType,proto3,enum=Services.MessageType" json:"previousMessageType,omitempty"`
+	MessageIDs []string `protobuf:"bytes,1,rep,name=MessageIDs,proto3" json:"MessageIDs,omitempty"`
 }
 
-func (x *ValidationMessage) Reset() {
-	*x = ValidationMessage{}
+func (x *Receipt) Reset() {
+	*x = Receipt{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[41]
+		mi := &file_protos_services_services_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ValidationMessage) String() string {
+func (x *Receipt) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidationMessage) ProtoMessage() {}
+func (*Receipt) ProtoMessage() {}
 
-func (x *ValidationMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[41]
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2895,67 +3157,48 @@ func (x *ValidationMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidationMessage.ProtoReflect.Descriptor instead.
-func (*ValidationMessage) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{41}
-}
-
-func (x *ValidationMessage) GetGroupID() string {
-	if x != nil {
-		return x.GroupID
-	}
-	return ""
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *ValidationMessage) GetPreviousMessage() []byte {
+func (x *Receipt) GetMessageIDs() []string {
 	if x != nil {
-		return x.PreviousMessage
+		return x.MessageIDs
 	}
 	return nil
 }
 
-func (x *ValidationMessage) GetPreviousMessageType() MessageType {
-	if x != nil {
-		return x.PreviousMessageType
-	}
-	return MessageType_TEXT_MESSAGE
-}
-
-type MessageWrapper struct {
+type Message struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID             string                             `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	RecipientID          string                             `protobuf:"bytes,2,opt,name=recipientID,proto3" json:"recipientID,omitempty"`
-	EncryptedMessage     []byte                             `protobuf:"bytes,3,opt,name=encryptedMessage,proto3" json:"encryptedMessage,omitempty"`
-	ChatbotMessages      []*ChatbotMessage                  `protobuf:"bytes,4,rep,name=chatbotMessages,proto3" json:"chatbotMessages,omitempty"`
-	ChatbotIds           []string                           `protobuf:"bytes,5,rep,name=ChatbotIds,proto3" json:"ChatbotIds,omitempty"`
-	HasPreKey            bool                               `protobuf:"varint,6,opt,name=hasPreKey,proto3" json:"hasPreKey,omitempty"`
-	IsIGA                bool                               `protobuf:"varint,7,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
-	IsPseudo             bool                               `protobuf:"varint,8,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
-	TreeKEMKeyUpdatePack *TreeKEMKeyUpdatePack              `protobuf:"bytes,9,opt,name=treeKEMKeyUpdatePack,proto3" json:"treeKEMKeyUpdatePack,omitempty"`
-	ChatbotKeyUpdatePack *MultiTreeKEMExternalKeyUpdatePack `protobuf:"bytes,10,opt,name=chatbotKeyUpdatePack,proto3" json:"chatbotKeyUpdatePack,omitempty"`
-	MlsCommit            []byte                             `protobuf:"bytes,11,opt,name=mlsCommit,proto3" json:"mlsCommit,omitempty"`
+	MessageType MessageType `protobuf:"varint,1,opt,name=messageType,proto3,enum=Services.MessageType" json:"messageType,omitempty"`
+	Message     []byte      `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ChatbotIDs  []string    `protobuf:"bytes,3,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
+	Signature   []byte      `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The MessageID of the MessageWrapper, encrypted along with the message so that the server cannot change it.
+	MessageID string `protobuf:"bytes,5,opt,name=messageID,proto3" json:"messageID,omitempty"`
 }
 
-func (x *MessageWrapper) Reset() {
-	*x = MessageWrapper{}
+func (x *Message) Reset() {
+	*x = Message{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[42]
+		mi := &file_protos_services_services_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MessageWrapper) String() string {
+func (x *Message) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MessageWrapper) ProtoMessage() {}
+func (*Message) ProtoMessage() {}
 
-func (x *MessageWrapper) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[42]
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2966,113 +3209,144 @@ func (x *MessageWrapper) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MessageWrapper.ProtoReflect.Descriptor instead.
-func (*MessageWrapper) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *MessageWrapper) GetSenderID() string {
+func (x *Message) GetMessageType() MessageType {
 	if x != nil {
-		return x.SenderID
+		return x.MessageType
 	}
-	return ""
+	return MessageType_TEXT_MESSAGE
 }
 
-func (x *MessageWrapper) GetRecipientID() string {
+func (x *Message) GetMessage() []byte {
 	if x != nil {
-		return x.RecipientID
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *MessageWrapper) GetEncryptedMessage() []byte {
+func (x *Message) GetChatbotIDs() []string {
 	if x != nil {
-		return x.EncryptedMessage
+		return x.ChatbotIDs
 	}
 	return nil
 }
 
-func (x *MessageWrapper) GetChatbotMessages() []*ChatbotMessage {
+func (x *Message) GetSignature() []byte {
 	if x != nil {
-		return x.ChatbotMessages
+		return x.Signature
 	}
 	return nil
 }
 
-func (x *MessageWrapper) GetChatbotIds() []string {
+func (x *Message) GetMessageID() string {
 	if x != nil {
-		return x.ChatbotIds
+		return x.MessageID
 	}
-	return nil
+	return ""
 }
 
-func (x *MessageWrapper) GetHasPreKey() bool {
-	if x != nil {
-		return x.HasPreKey
+type ChatbotMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChatbotID        string          `protobuf:"bytes,1,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
+	MessageWrapper   *MessageWrapper `protobuf:"bytes,2,opt,name=messageWrapper,proto3" json:"messageWrapper,omitempty"`
+	UseNormalMessage bool            `protobuf:"varint,3,opt,name=useNormalMessage,proto3" json:"useNormalMessage,omitempty"`
+	ShouldSend       bool            `protobuf:"varint,4,opt,name=shouldSend,proto3" json:"shouldSend,omitempty"`
+}
+
+func (x *ChatbotMessage) Reset() {
+	*x = ChatbotMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return false
 }
 
-func (x *MessageWrapper) GetIsIGA() bool {
-	if x != nil {
-		return x.IsIGA
+func (x *ChatbotMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatbotMessage) ProtoMessage() {}
+
+func (x *ChatbotMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *MessageWrapper) GetIsPseudo() bool {
+// Deprecated: Use ChatbotMessage.ProtoReflect.Descriptor instead.
+func (*ChatbotMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ChatbotMessage) GetChatbotID() string {
 	if x != nil {
-		return x.IsPseudo
+		return x.ChatbotID
 	}
-	return false
+	return ""
 }
 
-func (x *MessageWrapper) GetTreeKEMKeyUpdatePack() *TreeKEMKeyUpdatePack {
+func (x *ChatbotMessage) GetMessageWrapper() *MessageWrapper {
 	if x != nil {
-		return x.TreeKEMKeyUpdatePack
+		return x.MessageWrapper
 	}
 	return nil
 }
 
-func (x *MessageWrapper) GetChatbotKeyUpdatePack() *MultiTreeKEMExternalKeyUpdatePack {
+func (x *ChatbotMessage) GetUseNormalMessage() bool {
 	if x != nil {
-		return x.ChatbotKeyUpdatePack
+		return x.UseNormalMessage
 	}
-	return nil
+	return false
 }
 
-func (x *MessageWrapper) GetMlsCommit() []byte {
+func (x *ChatbotMessage) GetShouldSend() bool {
 	if x != nil {
-		return x.MlsCommit
+		return x.ShouldSend
 	}
-	return nil
+	return false
 }
 
-type ServerEventStreamInit struct {
+type ClientSideGroupMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	GroupID     string      `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	MessageType MessageType `protobuf:"varint,2,opt,name=messageType,proto3,enum=Services.MessageType" json:"messageType,omitempty"`
+	Message     []byte      `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *ServerEventStreamInit) Reset() {
-	*x = ServerEventStreamInit{}
+func (x *ClientSideGroupMessage) Reset() {
+	*x = ClientSideGroupMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[43]
+		mi := &file_protos_services_services_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServerEventStreamInit) String() string {
+func (x *ClientSideGroupMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerEventStreamInit) ProtoMessage() {}
+func (*ClientSideGroupMessage) ProtoMessage() {}
 
-func (x *ServerEventStreamInit) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[43]
+func (x *ClientSideGroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3083,56 +3357,59 @@ func (x *ServerEventStreamInit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerEventStreamInit.ProtoReflect.Descriptor instead.
-func (*ServerEventStreamInit) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use ClientSideGroupMessage.ProtoReflect.Descriptor instead.
+func (*ClientSideGroupMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *ServerEventStreamInit) GetUserID() string {
+func (x *ClientSideGroupMessage) GetGroupID() string {
 	if x != nil {
-		return x.UserID
+		return x.GroupID
 	}
 	return ""
 }
 
-type GroupInvitation struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	SenderID                   string                    `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID                    string                    `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	ParticipantIDs             []string                  `protobuf:"bytes,3,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
-	ChatbotIDs                 []string                  `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
-	ChatbotIsIGA               map[string]bool           `protobuf:"bytes,5,rep,name=chatbotIsIGA,proto3" json:"chatbotIsIGA,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	ChatbotIsPseudo            map[string]bool           `protobuf:"bytes,6,rep,name=chatbotIsPseudo,proto3" json:"chatbotIsPseudo,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	TreeKEMGroupInitKey        *TreeKEMGroupInitKey      `protobuf:"bytes,7,opt,name=treeKEMGroupInitKey,proto3" json:"treeKEMGroupInitKey,omitempty"`
-	TreeKEMInitLeaf            []byte                    `protobuf:"bytes,8,opt,name=treeKEMInitLeaf,proto3" json:"treeKEMInitLeaf,omitempty"`
-	ChatbotPubKeys             map[string][]byte         `protobuf:"bytes,9,rep,name=chatbotPubKeys,proto3" json:"chatbotPubKeys,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	ChatbotSignPubKeys         map[string][]byte         `protobuf:"bytes,10,rep,name=chatbotSignPubKeys,proto3" json:"chatbotSignPubKeys,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	LastTreeKemRootCiphertexts *ECKEMCipherTextStringMap `protobuf:"bytes,11,opt,name=lastTreeKemRootCiphertexts,proto3" json:"lastTreeKemRootCiphertexts,omitempty"`
-	MlsWelcomeMessage          []byte                    `protobuf:"bytes,12,opt,name=MlsWelcomeMessage,proto3" json:"MlsWelcomeMessage,omitempty"`
-	MlsKeyPackageID            uint32                    `protobuf:"varint,13,opt,name=MlsKeyPackageID,proto3" json:"MlsKeyPackageID,omitempty"`
-	GroupType                  GroupType                 `protobuf:"varint,14,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+func (x *ClientSideGroupMessage) GetMessageType() MessageType {
+	if x != nil {
+		return x.MessageType
+	}
+	return MessageType_TEXT_MESSAGE
 }
 
-func (x *GroupInvitation) Reset() {
-	*x = GroupInvitation{}
+func (x *ClientSideGroupMessage) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type SenderKeyDistributionMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID                      string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	SenderKeyDistributionMessage []byte `protobuf:"bytes,2,opt,name=senderKeyDistributionMessage,proto3" json:"senderKeyDistributionMessage,omitempty"`
+	BounceBack                   bool   `protobuf:"varint,3,opt,name=bounceBack,proto3" json:"bounceBack,omitempty"`
+}
+
+func (x *SenderKeyDistributionMessage) Reset() {
+	*x = SenderKeyDistributionMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[44]
+		mi := &file_protos_services_services_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupInvitation) String() string {
+func (x *SenderKeyDistributionMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupInvitation) ProtoMessage() {}
+func (*SenderKeyDistributionMessage) ProtoMessage() {}
 
-func (x *GroupInvitation) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[44]
+func (x *SenderKeyDistributionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3143,141 +3420,109 @@ func (x *GroupInvitation) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupInvitation.ProtoReflect.Descriptor instead.
-func (*GroupInvitation) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{44}
-}
-
-func (x *GroupInvitation) GetSenderID() string {
-	if x != nil {
-		return x.SenderID
-	}
-	return ""
+// Deprecated: Use SenderKeyDistributionMessage.ProtoReflect.Descriptor instead.
+func (*SenderKeyDistributionMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *GroupInvitation) GetGroupID() string {
+func (x *SenderKeyDistributionMessage) GetGroupID() string {
 	if x != nil {
 		return x.GroupID
 	}
 	return ""
 }
 
-func (x *GroupInvitation) GetParticipantIDs() []string {
-	if x != nil {
-		return x.ParticipantIDs
-	}
-	return nil
-}
-
-func (x *GroupInvitation) GetChatbotIDs() []string {
-	if x != nil {
-		return x.ChatbotIDs
-	}
-	return nil
-}
-
-func (x *GroupInvitation) GetChatbotIsIGA() map[string]bool {
+func (x *SenderKeyDistributionMessage) GetSenderKeyDistributionMessage() []byte {
 	if x != nil {
-		return x.ChatbotIsIGA
+		return x.SenderKeyDistributionMessage
 	}
 	return nil
 }
 
-func (x *GroupInvitation) GetChatbotIsPseudo() map[string]bool {
+func (x *SenderKeyDistributionMessage) GetBounceBack() bool {
 	if x != nil {
-		return x.ChatbotIsPseudo
+		return x.BounceBack
 	}
-	return nil
+	return false
 }
 
-func (x *GroupInvitation) GetTreeKEMGroupInitKey() *TreeKEMGroupInitKey {
-	if x != nil {
-		return x.TreeKEMGroupInitKey
-	}
-	return nil
-}
+// GroupRejoinRequest is sent by a user restored from a backup to a member of an MLS group, asking it to remove and
+// re-add the user, as the MLS state in the backup may be outdated.
+type GroupRejoinRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *GroupInvitation) GetTreeKEMInitLeaf() []byte {
-	if x != nil {
-		return x.TreeKEMInitLeaf
-	}
-	return nil
+	GroupID string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
 }
 
-func (x *GroupInvitation) GetChatbotPubKeys() map[string][]byte {
-	if x != nil {
-		return x.ChatbotPubKeys
+func (x *GroupRejoinRequest) Reset() {
+	*x = GroupRejoinRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *GroupInvitation) GetChatbotSignPubKeys() map[string][]byte {
-	if x != nil {
-		return x.ChatbotSignPubKeys
-	}
-	return nil
+func (x *GroupRejoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GroupInvitation) GetLastTreeKemRootCiphertexts() *ECKEMCipherTextStringMap {
-	if x != nil {
-		return x.LastTreeKemRootCiphertexts
-	}
-	return nil
-}
+func (*GroupRejoinRequest) ProtoMessage() {}
 
-func (x *GroupInvitation) GetMlsWelcomeMessage() []byte {
-	if x != nil {
-		return x.MlsWelcomeMessage
+func (x *GroupRejoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GroupInvitation) GetMlsKeyPackageID() uint32 {
-	if x != nil {
-		return x.MlsKeyPackageID
-	}
-	return 0
+// Deprecated: Use GroupRejoinRequest.ProtoReflect.Descriptor instead.
+func (*GroupRejoinRequest) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *GroupInvitation) GetGroupType() GroupType {
+func (x *GroupRejoinRequest) GetGroupID() string {
 	if x != nil {
-		return x.GroupType
+		return x.GroupID
 	}
-	return GroupType_CLIENT_SIDE
+	return ""
 }
 
-type GroupAddition struct {
+// RootRecoveryRequest is sent by a chatbot that lost track of its root to a member of the group.
+type RootRecoveryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID       string          `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID        string          `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	AddedID        string          `protobuf:"bytes,3,opt,name=addedID,proto3" json:"addedID,omitempty"`
-	ParticipantIDs []string        `protobuf:"bytes,4,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
-	TreeKEMUserAdd *TreeKEMUserAdd `protobuf:"bytes,5,opt,name=treeKEMUserAdd,proto3" json:"treeKEMUserAdd,omitempty"`
-	MlsUserAdd     []byte          `protobuf:"bytes,6,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
-	MlsAddCommit   []byte          `protobuf:"bytes,7,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
-	GroupType      GroupType       `protobuf:"varint,8,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	GroupID   string    `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	GroupType GroupType `protobuf:"varint,2,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	Epoch     uint64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
 }
 
-func (x *GroupAddition) Reset() {
-	*x = GroupAddition{}
+func (x *RootRecoveryRequest) Reset() {
+	*x = RootRecoveryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[45]
+		mi := &file_protos_services_services_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupAddition) String() string {
+func (x *RootRecoveryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupAddition) ProtoMessage() {}
+func (*RootRecoveryRequest) ProtoMessage() {}
 
-func (x *GroupAddition) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[45]
+func (x *RootRecoveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3288,98 +3533,64 @@ func (x *GroupAddition) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupAddition.ProtoReflect.Descriptor instead.
-func (*GroupAddition) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{45}
-}
-
-func (x *GroupAddition) GetSenderID() string {
-	if x != nil {
-		return x.SenderID
-	}
-	return ""
+// Deprecated: Use RootRecoveryRequest.ProtoReflect.Descriptor instead.
+func (*RootRecoveryRequest) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *GroupAddition) GetGroupID() string {
+func (x *RootRecoveryRequest) GetGroupID() string {
 	if x != nil {
 		return x.GroupID
 	}
 	return ""
 }
 
-func (x *GroupAddition) GetAddedID() string {
-	if x != nil {
-		return x.AddedID
-	}
-	return ""
-}
-
-func (x *GroupAddition) GetParticipantIDs() []string {
-	if x != nil {
-		return x.ParticipantIDs
-	}
-	return nil
-}
-
-func (x *GroupAddition) GetTreeKEMUserAdd() *TreeKEMUserAdd {
-	if x != nil {
-		return x.TreeKEMUserAdd
-	}
-	return nil
-}
-
-func (x *GroupAddition) GetMlsUserAdd() []byte {
-	if x != nil {
-		return x.MlsUserAdd
-	}
-	return nil
-}
-
-func (x *GroupAddition) GetMlsAddCommit() []byte {
+func (x *RootRecoveryRequest) GetGroupType() GroupType {
 	if x != nil {
-		return x.MlsAddCommit
+		return x.GroupType
 	}
-	return nil
+	return GroupType_CLIENT_SIDE
 }
 
-func (x *GroupAddition) GetGroupType() GroupType {
+func (x *RootRecoveryRequest) GetEpoch() uint64 {
 	if x != nil {
-		return x.GroupType
+		return x.Epoch
 	}
-	return GroupType_CLIENT_SIDE
+	return 0
 }
 
-type GroupRemoval struct {
+// RootRecovery carries the current root a member shares with the chatbot, in response to a RootRecoveryRequest.
+type RootRecovery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID        string    `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID         string    `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	RemovedID       string    `protobuf:"bytes,3,opt,name=removedID,proto3" json:"removedID,omitempty"`
-	ParticipantIDs  []string  `protobuf:"bytes,4,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
-	MlsRemove       []byte    `protobuf:"bytes,5,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
-	MlsRemoveCommit []byte    `protobuf:"bytes,6,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
-	GroupType       GroupType `protobuf:"varint,7,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	GroupID               string    `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	GroupType             GroupType `protobuf:"varint,2,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	Epoch                 uint64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	TranscriptHash        []byte    `protobuf:"bytes,4,opt,name=transcriptHash,proto3" json:"transcriptHash,omitempty"`
+	RootSecret            []byte    `protobuf:"bytes,5,opt,name=rootSecret,proto3" json:"rootSecret,omitempty"`
+	TreeKemRootPubKey     []byte    `protobuf:"bytes,6,opt,name=treeKemRootPubKey,proto3" json:"treeKemRootPubKey,omitempty"`
+	TreeKemRootSignPubKey []byte    `protobuf:"bytes,7,opt,name=treeKemRootSignPubKey,proto3" json:"treeKemRootSignPubKey,omitempty"`
 }
 
-func (x *GroupRemoval) Reset() {
-	*x = GroupRemoval{}
+func (x *RootRecovery) Reset() {
+	*x = RootRecovery{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[46]
+		mi := &file_protos_services_services_proto_msgTypes[49]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupRemoval) String() string {
+func (x *RootRecovery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupRemoval) ProtoMessage() {}
+func (*RootRecovery) ProtoMessage() {}
 
-func (x *GroupRemoval) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[46]
+func (x *RootRecovery) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[49]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3390,95 +3601,87 @@ func (x *GroupRemoval) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupRemoval.ProtoReflect.Descriptor instead.
-func (*GroupRemoval) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{46}
+// Deprecated: Use RootRecovery.ProtoReflect.Descriptor instead.
+func (*RootRecovery) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *GroupRemoval) GetSenderID() string {
+func (x *RootRecovery) GetGroupID() string {
 	if x != nil {
-		return x.SenderID
+		return x.GroupID
 	}
 	return ""
 }
 
-func (x *GroupRemoval) GetGroupID() string {
+func (x *RootRecovery) GetGroupType() GroupType {
 	if x != nil {
-		return x.GroupID
+		return x.GroupType
 	}
-	return ""
+	return GroupType_CLIENT_SIDE
 }
 
-func (x *GroupRemoval) GetRemovedID() string {
+func (x *RootRecovery) GetEpoch() uint64 {
 	if x != nil {
-		return x.RemovedID
+		return x.Epoch
 	}
-	return ""
+	return 0
 }
 
-func (x *GroupRemoval) GetParticipantIDs() []string {
+func (x *RootRecovery) GetTranscriptHash() []byte {
 	if x != nil {
-		return x.ParticipantIDs
+		return x.TranscriptHash
 	}
 	return nil
 }
 
-func (x *GroupRemoval) GetMlsRemove() []byte {
+func (x *RootRecovery) GetRootSecret() []byte {
 	if x != nil {
-		return x.MlsRemove
+		return x.RootSecret
 	}
 	return nil
 }
 
-func (x *GroupRemoval) GetMlsRemoveCommit() []byte {
+func (x *RootRecovery) GetTreeKemRootPubKey() []byte {
 	if x != nil {
-		return x.MlsRemoveCommit
+		return x.TreeKemRootPubKey
 	}
 	return nil
 }
 
-func (x *GroupRemoval) GetGroupType() GroupType {
+func (x *RootRecovery) GetTreeKemRootSignPubKey() []byte {
 	if x != nil {
-		return x.GroupType
+		return x.TreeKemRootSignPubKey
 	}
-	return GroupType_CLIENT_SIDE
+	return nil
 }
 
-type GroupChatbotInvitation struct {
+type PseudonymRegistrationMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID           string    `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID            string    `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	ParticipantIDs     []string  `protobuf:"bytes,3,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
-	GroupType          GroupType `protobuf:"varint,4,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
-	IsIGA              bool      `protobuf:"varint,5,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
-	IsPseudo           bool      `protobuf:"varint,6,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
-	TreekemRootPub     []byte    `protobuf:"bytes,7,opt,name=treekemRootPub,proto3" json:"treekemRootPub,omitempty"`
-	TreekemRootSignPub []byte    `protobuf:"bytes,8,opt,name=treekemRootSignPub,proto3" json:"treekemRootSignPub,omitempty"`
-	ChatbotInitLeaf    []byte    `protobuf:"bytes,9,opt,name=chatbotInitLeaf,proto3" json:"chatbotInitLeaf,omitempty"`
-	MlsWelcomeMessage  []byte    `protobuf:"bytes,10,opt,name=MlsWelcomeMessage,proto3" json:"MlsWelcomeMessage,omitempty"`
-	MlsKeyPackageID    uint32    `protobuf:"varint,11,opt,name=MlsKeyPackageID,proto3" json:"MlsKeyPackageID,omitempty"`
+	GroupID       string `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	PseudoUserID  string `protobuf:"bytes,2,opt,name=pseudoUserID,proto3" json:"pseudoUserID,omitempty"`
+	SigningKeyPub []byte `protobuf:"bytes,3,opt,name=signingKeyPub,proto3" json:"signingKeyPub,omitempty"`
 }
 
-func (x *GroupChatbotInvitation) Reset() {
-	*x = GroupChatbotInvitation{}
+func (x *PseudonymRegistrationMessage) Reset() {
+	*x = PseudonymRegistrationMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[47]
+		mi := &file_protos_services_services_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupChatbotInvitation) String() string {
+func (x *PseudonymRegistrationMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupChatbotInvitation) ProtoMessage() {}
+func (*PseudonymRegistrationMessage) ProtoMessage() {}
 
-func (x *GroupChatbotInvitation) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[47]
+func (x *PseudonymRegistrationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3489,122 +3692,138 @@ func (x *GroupChatbotInvitation) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupChatbotInvitation.ProtoReflect.Descriptor instead.
-func (*GroupChatbotInvitation) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{47}
+// Deprecated: Use PseudonymRegistrationMessage.ProtoReflect.Descriptor instead.
+func (*PseudonymRegistrationMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *GroupChatbotInvitation) GetSenderID() string {
+func (x *PseudonymRegistrationMessage) GetGroupID() string {
 	if x != nil {
-		return x.SenderID
+		return x.GroupID
 	}
 	return ""
 }
 
-func (x *GroupChatbotInvitation) GetGroupID() string {
+func (x *PseudonymRegistrationMessage) GetPseudoUserID() string {
 	if x != nil {
-		return x.GroupID
+		return x.PseudoUserID
 	}
 	return ""
 }
 
-func (x *GroupChatbotInvitation) GetParticipantIDs() []string {
+func (x *PseudonymRegistrationMessage) GetSigningKeyPub() []byte {
 	if x != nil {
-		return x.ParticipantIDs
+		return x.SigningKeyPub
 	}
 	return nil
 }
 
-func (x *GroupChatbotInvitation) GetGroupType() GroupType {
-	if x != nil {
-		return x.GroupType
-	}
-	return GroupType_CLIENT_SIDE
+type ValidationMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID             string      `protobuf:"bytes,1,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	PreviousMessage     []byte      `protobuf:"bytes,2,opt,name=previousMessage,proto3" json:"previousMessage,omitempty"`
+	PreviousMessageType MessageType `protobuf:"varint,3,opt,name=previousMessageType,proto3,enum=Services.MessageType" json:"previousMessageType,omitempty"`
 }
 
-func (x *GroupChatbotInvitation) GetIsIGA() bool {
-	if x != nil {
-		return x.IsIGA
+func (x *ValidationMessage) Reset() {
+	*x = ValidationMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return false
 }
 
-func (x *GroupChatbotInvitation) GetIsPseudo() bool {
-	if x != nil {
-		return x.IsPseudo
-	}
-	return false
+func (x *ValidationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GroupChatbotInvitation) GetTreekemRootPub() []byte {
-	if x != nil {
-		return x.TreekemRootPub
+func (*ValidationMessage) ProtoMessage() {}
+
+func (x *ValidationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GroupChatbotInvitation) GetTreekemRootSignPub() []byte {
-	if x != nil {
-		return x.TreekemRootSignPub
-	}
-	return nil
+// Deprecated: Use ValidationMessage.ProtoReflect.Descriptor instead.
+func (*ValidationMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *GroupChatbotInvitation) GetChatbotInitLeaf() []byte {
+func (x *ValidationMessage) GetGroupID() string {
 	if x != nil {
-		return x.ChatbotInitLeaf
+		return x.GroupID
 	}
-	return nil
+	return ""
 }
 
-func (x *GroupChatbotInvitation) GetMlsWelcomeMessage() []byte {
+func (x *ValidationMessage) GetPreviousMessage() []byte {
 	if x != nil {
-		return x.MlsWelcomeMessage
+		return x.PreviousMessage
 	}
 	return nil
 }
 
-func (x *GroupChatbotInvitation) GetMlsKeyPackageID() uint32 {
+func (x *ValidationMessage) GetPreviousMessageType() MessageType {
 	if x != nil {
-		return x.MlsKeyPackageID
+		return x.PreviousMessageType
 	}
-	return 0
+	return MessageType_TEXT_MESSAGE
 }
 
-type GroupChatbotAddition struct {
+type MessageWrapper struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID          string           `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID           string           `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	AddedChatbotID    string           `protobuf:"bytes,3,opt,name=addedChatbotID,proto3" json:"addedChatbotID,omitempty"`
-	ChatbotIDs        []string         `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
-	GroupType         GroupType        `protobuf:"varint,5,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
-	IsIGA             bool             `protobuf:"varint,6,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
-	IsPseudo          bool             `protobuf:"varint,7,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
-	ChatbotCipherText *ECKEMCipherText `protobuf:"bytes,8,opt,name=chatbotCipherText,proto3" json:"chatbotCipherText,omitempty"`
-	MlsUserAdd        []byte           `protobuf:"bytes,9,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
-	MlsAddCommit      []byte           `protobuf:"bytes,10,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
+	SenderID             string                             `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	RecipientID          string                             `protobuf:"bytes,2,opt,name=recipientID,proto3" json:"recipientID,omitempty"`
+	EncryptedMessage     []byte                             `protobuf:"bytes,3,opt,name=encryptedMessage,proto3" json:"encryptedMessage,omitempty"`
+	ChatbotMessages      []*ChatbotMessage                  `protobuf:"bytes,4,rep,name=chatbotMessages,proto3" json:"chatbotMessages,omitempty"`
+	ChatbotIds           []string                           `protobuf:"bytes,5,rep,name=ChatbotIds,proto3" json:"ChatbotIds,omitempty"`
+	HasPreKey            bool                               `protobuf:"varint,6,opt,name=hasPreKey,proto3" json:"hasPreKey,omitempty"`
+	IsIGA                bool                               `protobuf:"varint,7,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
+	IsPseudo             bool                               `protobuf:"varint,8,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
+	TreeKEMKeyUpdatePack *TreeKEMKeyUpdatePack              `protobuf:"bytes,9,opt,name=treeKEMKeyUpdatePack,proto3" json:"treeKEMKeyUpdatePack,omitempty"`
+	ChatbotKeyUpdatePack *MultiTreeKEMExternalKeyUpdatePack `protobuf:"bytes,10,opt,name=chatbotKeyUpdatePack,proto3" json:"chatbotKeyUpdatePack,omitempty"`
+	MlsCommit            []byte                             `protobuf:"bytes,11,opt,name=mlsCommit,proto3" json:"mlsCommit,omitempty"`
+	HiddenTrigger        bool                               `protobuf:"varint,12,opt,name=hiddenTrigger,proto3" json:"hiddenTrigger,omitempty"`
+	// Set by the sender: the sequence number of the last key update of the group it handled before issuing its own.
+	KeyUpdateBase uint64 `protobuf:"varint,13,opt,name=keyUpdateBase,proto3" json:"keyUpdateBase,omitempty"`
+	// Set by the server: the sequence number it assigned to the key update the message carries, if any.
+	KeyUpdateSeq uint64 `protobuf:"varint,14,opt,name=keyUpdateSeq,proto3" json:"keyUpdateSeq,omitempty"`
+	// Set by the server: when it accepted the message, in Unix milliseconds, and the ID it gave the message.
+	Timestamp int64  `protobuf:"varint,15,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MessageID string `protobuf:"bytes,16,opt,name=messageID,proto3" json:"messageID,omitempty"`
 }
 
-func (x *GroupChatbotAddition) Reset() {
-	*x = GroupChatbotAddition{}
+func (x *MessageWrapper) Reset() {
+	*x = MessageWrapper{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[48]
+		mi := &file_protos_services_services_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupChatbotAddition) String() string {
+func (x *MessageWrapper) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupChatbotAddition) ProtoMessage() {}
+func (*MessageWrapper) ProtoMessage() {}
 
-func (x *GroupChatbotAddition) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[48]
+func (x *MessageWrapper) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3615,112 +3834,148 @@ func (x *GroupChatbotAddition) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupChatbotAddition.ProtoReflect.Descriptor instead.
-func (*GroupChatbotAddition) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{48}
+// Deprecated: Use MessageWrapper.ProtoReflect.Descriptor instead.
+func (*MessageWrapper) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *GroupChatbotAddition) GetSenderID() string {
+func (x *MessageWrapper) GetSenderID() string {
 	if x != nil {
 		return x.SenderID
 	}
 	return ""
 }
 
-func (x *GroupChatbotAddition) GetGroupID() string {
+func (x *MessageWrapper) GetRecipientID() string {
 	if x != nil {
-		return x.GroupID
+		return x.RecipientID
 	}
 	return ""
 }
 
-func (x *GroupChatbotAddition) GetAddedChatbotID() string {
+func (x *MessageWrapper) GetEncryptedMessage() []byte {
 	if x != nil {
-		return x.AddedChatbotID
+		return x.EncryptedMessage
 	}
-	return ""
+	return nil
 }
 
-func (x *GroupChatbotAddition) GetChatbotIDs() []string {
+func (x *MessageWrapper) GetChatbotMessages() []*ChatbotMessage {
 	if x != nil {
-		return x.ChatbotIDs
+		return x.ChatbotMessages
 	}
 	return nil
 }
 
-func (x *GroupChatbotAddition) GetGroupType() GroupType {
+func (x *MessageWrapper) GetChatbotIds() []string {
 	if x != nil {
-		return x.GroupType
+		return x.ChatbotIds
 	}
-	return GroupType_CLIENT_SIDE
+	return nil
 }
 
-func (x *GroupChatbotAddition) GetIsIGA() bool {
+func (x *MessageWrapper) GetHasPreKey() bool {
+	if x != nil {
+		return x.HasPreKey
+	}
+	return false
+}
+
+func (x *MessageWrapper) GetIsIGA() bool {
 	if x != nil {
 		return x.IsIGA
 	}
 	return false
 }
 
-func (x *GroupChatbotAddition) GetIsPseudo() bool {
+func (x *MessageWrapper) GetIsPseudo() bool {
 	if x != nil {
 		return x.IsPseudo
 	}
 	return false
 }
 
-func (x *GroupChatbotAddition) GetChatbotCipherText() *ECKEMCipherText {
+func (x *MessageWrapper) GetTreeKEMKeyUpdatePack() *TreeKEMKeyUpdatePack {
 	if x != nil {
-		return x.ChatbotCipherText
+		return x.TreeKEMKeyUpdatePack
 	}
 	return nil
 }
 
-func (x *GroupChatbotAddition) GetMlsUserAdd() []byte {
+func (x *MessageWrapper) GetChatbotKeyUpdatePack() *MultiTreeKEMExternalKeyUpdatePack {
 	if x != nil {
-		return x.MlsUserAdd
+		return x.ChatbotKeyUpdatePack
 	}
 	return nil
 }
 
-func (x *GroupChatbotAddition) GetMlsAddCommit() []byte {
+func (x *MessageWrapper) GetMlsCommit() []byte {
 	if x != nil {
-		return x.MlsAddCommit
+		return x.MlsCommit
 	}
 	return nil
 }
 
-type GroupChatbotRemoval struct {
+func (x *MessageWrapper) GetHiddenTrigger() bool {
+	if x != nil {
+		return x.HiddenTrigger
+	}
+	return false
+}
+
+func (x *MessageWrapper) GetKeyUpdateBase() uint64 {
+	if x != nil {
+		return x.KeyUpdateBase
+	}
+	return 0
+}
+
+func (x *MessageWrapper) GetKeyUpdateSeq() uint64 {
+	if x != nil {
+		return x.KeyUpdateSeq
+	}
+	return 0
+}
+
+func (x *MessageWrapper) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MessageWrapper) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
+type ServerEventStreamInit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SenderID         string    `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
-	GroupID          string    `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
-	RemovedChatbotID string    `protobuf:"bytes,3,opt,name=removedChatbotID,proto3" json:"removedChatbotID,omitempty"`
-	ChatbotIDs       []string  `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
-	MlsRemove        []byte    `protobuf:"bytes,5,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
-	MlsRemoveCommit  []byte    `protobuf:"bytes,6,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
-	GroupType        GroupType `protobuf:"varint,7,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
 }
 
-func (x *GroupChatbotRemoval) Reset() {
-	*x = GroupChatbotRemoval{}
+func (x *ServerEventStreamInit) Reset() {
+	*x = ServerEventStreamInit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[49]
+		mi := &file_protos_services_services_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupChatbotRemoval) String() string {
+func (x *ServerEventStreamInit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupChatbotRemoval) ProtoMessage() {}
+func (*ServerEventStreamInit) ProtoMessage() {}
 
-func (x *GroupChatbotRemoval) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[49]
+func (x *ServerEventStreamInit) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3731,94 +3986,168 @@ func (x *GroupChatbotRemoval) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupChatbotRemoval.ProtoReflect.Descriptor instead.
-func (*GroupChatbotRemoval) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{49}
+// Deprecated: Use ServerEventStreamInit.ProtoReflect.Descriptor instead.
+func (*ServerEventStreamInit) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GroupChatbotRemoval) GetSenderID() string {
+func (x *ServerEventStreamInit) GetUserID() string {
 	if x != nil {
-		return x.SenderID
+		return x.UserID
 	}
 	return ""
 }
 
-func (x *GroupChatbotRemoval) GetGroupID() string {
-	if x != nil {
-		return x.GroupID
-	}
-	return ""
+// Asks the server to end the streams of the user once it sent what it already took off the queues.
+type CloseStreamsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID string `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
 }
 
-func (x *GroupChatbotRemoval) GetRemovedChatbotID() string {
-	if x != nil {
-		return x.RemovedChatbotID
+func (x *CloseStreamsRequest) Reset() {
+	*x = CloseStreamsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *GroupChatbotRemoval) GetChatbotIDs() []string {
-	if x != nil {
-		return x.ChatbotIDs
-	}
-	return nil
+func (x *CloseStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GroupChatbotRemoval) GetMlsRemove() []byte {
-	if x != nil {
-		return x.MlsRemove
+func (*CloseStreamsRequest) ProtoMessage() {}
+
+func (x *CloseStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GroupChatbotRemoval) GetMlsRemoveCommit() []byte {
+// Deprecated: Use CloseStreamsRequest.ProtoReflect.Descriptor instead.
+func (*CloseStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CloseStreamsRequest) GetUserID() string {
 	if x != nil {
-		return x.MlsRemoveCommit
+		return x.UserID
 	}
-	return nil
+	return ""
 }
 
-func (x *GroupChatbotRemoval) GetGroupType() GroupType {
+type CloseStreamsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+}
+
+func (x *CloseStreamsResponse) Reset() {
+	*x = CloseStreamsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloseStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseStreamsResponse) ProtoMessage() {}
+
+func (x *CloseStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseStreamsResponse.ProtoReflect.Descriptor instead.
+func (*CloseStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CloseStreamsResponse) GetSuccess() bool {
 	if x != nil {
-		return x.GroupType
+		return x.Success
 	}
-	return GroupType_CLIENT_SIDE
+	return false
 }
 
-type ServerEvent struct {
+func (x *CloseStreamsResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type GroupInvitation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	EventType ServerEventType `protobuf:"varint,1,opt,name=eventType,proto3,enum=Services.ServerEventType" json:"eventType,omitempty"`
-	// Types that are assignable to EventData:
-	//
-	//	*ServerEvent_GroupInvitation
-	//	*ServerEvent_GroupAddition
-	//	*ServerEvent_GroupRemoval
-	//	*ServerEvent_GroupChatbotInvitation
-	//	*ServerEvent_GroupChatbotAddition
-	//	*ServerEvent_GroupChatbotRemoval
-	EventData isServerEvent_EventData `protobuf_oneof:"eventData"`
+	SenderID                   string                           `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID                    string                           `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	ParticipantIDs             []string                         `protobuf:"bytes,3,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
+	ChatbotIDs                 []string                         `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
+	ChatbotIsIGA               map[string]bool                  `protobuf:"bytes,5,rep,name=chatbotIsIGA,proto3" json:"chatbotIsIGA,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ChatbotIsPseudo            map[string]bool                  `protobuf:"bytes,6,rep,name=chatbotIsPseudo,proto3" json:"chatbotIsPseudo,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	TreeKEMGroupInitKey        *TreeKEMGroupInitKey             `protobuf:"bytes,7,opt,name=treeKEMGroupInitKey,proto3" json:"treeKEMGroupInitKey,omitempty"`
+	TreeKEMInitLeaf            []byte                           `protobuf:"bytes,8,opt,name=treeKEMInitLeaf,proto3" json:"treeKEMInitLeaf,omitempty"`
+	ChatbotPubKeys             map[string][]byte                `protobuf:"bytes,9,rep,name=chatbotPubKeys,proto3" json:"chatbotPubKeys,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ChatbotSignPubKeys         map[string][]byte                `protobuf:"bytes,10,rep,name=chatbotSignPubKeys,proto3" json:"chatbotSignPubKeys,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LastTreeKemRootCiphertexts *ECKEMCipherTextStringMap        `protobuf:"bytes,11,opt,name=lastTreeKemRootCiphertexts,proto3" json:"lastTreeKemRootCiphertexts,omitempty"`
+	MlsWelcomeMessage          []byte                           `protobuf:"bytes,12,opt,name=MlsWelcomeMessage,proto3" json:"MlsWelcomeMessage,omitempty"`
+	MlsKeyPackageID            uint32                           `protobuf:"varint,13,opt,name=MlsKeyPackageID,proto3" json:"MlsKeyPackageID,omitempty"`
+	GroupType                  GroupType                        `protobuf:"varint,14,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	ChatbotRoutings            map[string]*SignedChatbotRouting `protobuf:"bytes,15,rep,name=chatbotRoutings,proto3" json:"chatbotRoutings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ChatbotScopes              map[string]*ChatbotScopes        `protobuf:"bytes,16,rep,name=chatbotScopes,proto3" json:"chatbotScopes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	TreeKEMIndices             map[string]uint32                `protobuf:"bytes,17,rep,name=treeKEMIndices,proto3" json:"treeKEMIndices,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	TreeKEMPublicTree          map[uint32]*TreeKEMNode          `protobuf:"bytes,18,rep,name=treeKEMPublicTree,proto3" json:"treeKEMPublicTree,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CipherSuite                uint32                           `protobuf:"varint,19,opt,name=cipherSuite,proto3" json:"cipherSuite,omitempty"`
+	ChatbotHybridKEM           map[string]bool                  `protobuf:"bytes,20,rep,name=chatbotHybridKEM,proto3" json:"chatbotHybridKEM,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	SignedChatbotScopes        map[string]*SignedChatbotScopes  `protobuf:"bytes,21,rep,name=signedChatbotScopes,proto3" json:"signedChatbotScopes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The sequence number of the last key update of the group, which the invited member's state already covers.
+	KeyUpdateSeq uint64 `protobuf:"varint,22,opt,name=keyUpdateSeq,proto3" json:"keyUpdateSeq,omitempty"`
 }
 
-func (x *ServerEvent) Reset() {
-	*x = ServerEvent{}
+func (x *GroupInvitation) Reset() {
+	*x = GroupInvitation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[50]
+		mi := &file_protos_services_services_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServerEvent) String() string {
+func (x *GroupInvitation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerEvent) ProtoMessage() {}
+func (*GroupInvitation) ProtoMessage() {}
 
-func (x *ServerEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[50]
+func (x *GroupInvitation) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3829,135 +4158,197 @@ func (x *ServerEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
-func (*ServerEvent) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{50}
+// Deprecated: Use GroupInvitation.ProtoReflect.Descriptor instead.
+func (*GroupInvitation) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *ServerEvent) GetEventType() ServerEventType {
+func (x *GroupInvitation) GetSenderID() string {
 	if x != nil {
-		return x.EventType
+		return x.SenderID
 	}
-	return ServerEventType_GROUP_INVITATION
+	return ""
 }
 
-func (m *ServerEvent) GetEventData() isServerEvent_EventData {
-	if m != nil {
-		return m.EventData
+func (x *GroupInvitation) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *GroupInvitation) GetParticipantIDs() []string {
+	if x != nil {
+		return x.ParticipantIDs
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupInvitation() *GroupInvitation {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupInvitation); ok {
-		return x.GroupInvitation
+func (x *GroupInvitation) GetChatbotIDs() []string {
+	if x != nil {
+		return x.ChatbotIDs
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupAddition() *GroupAddition {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupAddition); ok {
-		return x.GroupAddition
+func (x *GroupInvitation) GetChatbotIsIGA() map[string]bool {
+	if x != nil {
+		return x.ChatbotIsIGA
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupRemoval() *GroupRemoval {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupRemoval); ok {
-		return x.GroupRemoval
+func (x *GroupInvitation) GetChatbotIsPseudo() map[string]bool {
+	if x != nil {
+		return x.ChatbotIsPseudo
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupChatbotInvitation() *GroupChatbotInvitation {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotInvitation); ok {
-		return x.GroupChatbotInvitation
+func (x *GroupInvitation) GetTreeKEMGroupInitKey() *TreeKEMGroupInitKey {
+	if x != nil {
+		return x.TreeKEMGroupInitKey
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupChatbotAddition() *GroupChatbotAddition {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotAddition); ok {
-		return x.GroupChatbotAddition
+func (x *GroupInvitation) GetTreeKEMInitLeaf() []byte {
+	if x != nil {
+		return x.TreeKEMInitLeaf
 	}
 	return nil
 }
 
-func (x *ServerEvent) GetGroupChatbotRemoval() *GroupChatbotRemoval {
-	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotRemoval); ok {
-		return x.GroupChatbotRemoval
+func (x *GroupInvitation) GetChatbotPubKeys() map[string][]byte {
+	if x != nil {
+		return x.ChatbotPubKeys
 	}
 	return nil
 }
 
-type isServerEvent_EventData interface {
-	isServerEvent_EventData()
+func (x *GroupInvitation) GetChatbotSignPubKeys() map[string][]byte {
+	if x != nil {
+		return x.ChatbotSignPubKeys
+	}
+	return nil
 }
 
-type ServerEvent_GroupInvitation struct {
-	GroupInvitation *GroupInvitation `protobuf:"bytes,2,opt,name=groupInvitation,proto3,oneof"`
+func (x *GroupInvitation) GetLastTreeKemRootCiphertexts() *ECKEMCipherTextStringMap {
+	if x != nil {
+		return x.LastTreeKemRootCiphertexts
+	}
+	return nil
 }
 
-type ServerEvent_GroupAddition struct {
-	GroupAddition *GroupAddition `protobuf:"bytes,3,opt,name=groupAddition,proto3,oneof"`
+func (x *GroupInvitation) GetMlsWelcomeMessage() []byte {
+	if x != nil {
+		return x.MlsWelcomeMessage
+	}
+	return nil
 }
 
-type ServerEvent_GroupRemoval struct {
-	GroupRemoval *GroupRemoval `protobuf:"bytes,4,opt,name=groupRemoval,proto3,oneof"`
+func (x *GroupInvitation) GetMlsKeyPackageID() uint32 {
+	if x != nil {
+		return x.MlsKeyPackageID
+	}
+	return 0
 }
 
-type ServerEvent_GroupChatbotInvitation struct {
-	GroupChatbotInvitation *GroupChatbotInvitation `protobuf:"bytes,5,opt,name=groupChatbotInvitation,proto3,oneof"`
+func (x *GroupInvitation) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
 }
 
-type ServerEvent_GroupChatbotAddition struct {
-	GroupChatbotAddition *GroupChatbotAddition `protobuf:"bytes,6,opt,name=groupChatbotAddition,proto3,oneof"`
+func (x *GroupInvitation) GetChatbotRoutings() map[string]*SignedChatbotRouting {
+	if x != nil {
+		return x.ChatbotRoutings
+	}
+	return nil
 }
 
-type ServerEvent_GroupChatbotRemoval struct {
-	GroupChatbotRemoval *GroupChatbotRemoval `protobuf:"bytes,7,opt,name=groupChatbotRemoval,proto3,oneof"`
+func (x *GroupInvitation) GetChatbotScopes() map[string]*ChatbotScopes {
+	if x != nil {
+		return x.ChatbotScopes
+	}
+	return nil
 }
 
-func (*ServerEvent_GroupInvitation) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetTreeKEMIndices() map[string]uint32 {
+	if x != nil {
+		return x.TreeKEMIndices
+	}
+	return nil
+}
 
-func (*ServerEvent_GroupAddition) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetTreeKEMPublicTree() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.TreeKEMPublicTree
+	}
+	return nil
+}
 
-func (*ServerEvent_GroupRemoval) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
 
-func (*ServerEvent_GroupChatbotInvitation) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetChatbotHybridKEM() map[string]bool {
+	if x != nil {
+		return x.ChatbotHybridKEM
+	}
+	return nil
+}
 
-func (*ServerEvent_GroupChatbotAddition) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetSignedChatbotScopes() map[string]*SignedChatbotScopes {
+	if x != nil {
+		return x.SignedChatbotScopes
+	}
+	return nil
+}
 
-func (*ServerEvent_GroupChatbotRemoval) isServerEvent_EventData() {}
+func (x *GroupInvitation) GetKeyUpdateSeq() uint64 {
+	if x != nil {
+		return x.KeyUpdateSeq
+	}
+	return 0
+}
 
-// TreeKEM
-type TreeKEMUserAdd struct {
+type GroupAddition struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Size        uint32                  `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
-	Ciphertexts []*ECKEMCipherTextMap   `protobuf:"bytes,2,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty"`
-	Nodes       map[uint32]*TreeKEMNode `protobuf:"bytes,3,rep,name=Nodes,proto3" json:"Nodes,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	SenderID       string          `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID        string          `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	AddedID        string          `protobuf:"bytes,3,opt,name=addedID,proto3" json:"addedID,omitempty"`
+	ParticipantIDs []string        `protobuf:"bytes,4,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
+	TreeKEMUserAdd *TreeKEMUserAdd `protobuf:"bytes,5,opt,name=treeKEMUserAdd,proto3" json:"treeKEMUserAdd,omitempty"`
+	MlsUserAdd     []byte          `protobuf:"bytes,6,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
+	MlsAddCommit   []byte          `protobuf:"bytes,7,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
+	GroupType      GroupType       `protobuf:"varint,8,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
 }
 
-func (x *TreeKEMUserAdd) Reset() {
-	*x = TreeKEMUserAdd{}
+func (x *GroupAddition) Reset() {
+	*x = GroupAddition{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[51]
+		mi := &file_protos_services_services_proto_msgTypes[57]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TreeKEMUserAdd) String() string {
+func (x *GroupAddition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TreeKEMUserAdd) ProtoMessage() {}
+func (*GroupAddition) ProtoMessage() {}
 
-func (x *TreeKEMUserAdd) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[51]
+func (x *GroupAddition) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[57]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3968,123 +4359,99 @@ func (x *TreeKEMUserAdd) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TreeKEMUserAdd.ProtoReflect.Descriptor instead.
-func (*TreeKEMUserAdd) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{51}
+// Deprecated: Use GroupAddition.ProtoReflect.Descriptor instead.
+func (*GroupAddition) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *TreeKEMUserAdd) GetSize() uint32 {
+func (x *GroupAddition) GetSenderID() string {
 	if x != nil {
-		return x.Size
+		return x.SenderID
 	}
-	return 0
+	return ""
 }
 
-func (x *TreeKEMUserAdd) GetCiphertexts() []*ECKEMCipherTextMap {
+func (x *GroupAddition) GetGroupID() string {
 	if x != nil {
-		return x.Ciphertexts
+		return x.GroupID
 	}
-	return nil
+	return ""
 }
 
-func (x *TreeKEMUserAdd) GetNodes() map[uint32]*TreeKEMNode {
+func (x *GroupAddition) GetAddedID() string {
 	if x != nil {
-		return x.Nodes
+		return x.AddedID
 	}
-	return nil
-}
-
-type TreeKEMUserUpdate struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	From        uint32                  `protobuf:"varint,1,opt,name=From,proto3" json:"From,omitempty"`
-	Ciphertexts []*ECKEMCipherTextMap   `protobuf:"bytes,2,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty"`
-	Nodes       map[uint32]*TreeKEMNode `protobuf:"bytes,3,rep,name=Nodes,proto3" json:"Nodes,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	return ""
 }
 
-func (x *TreeKEMUserUpdate) Reset() {
-	*x = TreeKEMUserUpdate{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[52]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *GroupAddition) GetParticipantIDs() []string {
+	if x != nil {
+		return x.ParticipantIDs
 	}
+	return nil
 }
 
-func (x *TreeKEMUserUpdate) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*TreeKEMUserUpdate) ProtoMessage() {}
-
-func (x *TreeKEMUserUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[52]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GroupAddition) GetTreeKEMUserAdd() *TreeKEMUserAdd {
+	if x != nil {
+		return x.TreeKEMUserAdd
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use TreeKEMUserUpdate.ProtoReflect.Descriptor instead.
-func (*TreeKEMUserUpdate) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{52}
+	return nil
 }
 
-func (x *TreeKEMUserUpdate) GetFrom() uint32 {
+func (x *GroupAddition) GetMlsUserAdd() []byte {
 	if x != nil {
-		return x.From
+		return x.MlsUserAdd
 	}
-	return 0
+	return nil
 }
 
-func (x *TreeKEMUserUpdate) GetCiphertexts() []*ECKEMCipherTextMap {
+func (x *GroupAddition) GetMlsAddCommit() []byte {
 	if x != nil {
-		return x.Ciphertexts
+		return x.MlsAddCommit
 	}
 	return nil
 }
 
-func (x *TreeKEMUserUpdate) GetNodes() map[uint32]*TreeKEMNode {
+func (x *GroupAddition) GetGroupType() GroupType {
 	if x != nil {
-		return x.Nodes
+		return x.GroupType
 	}
-	return nil
+	return GroupType_CLIENT_SIDE
 }
 
-type TreeKEMKeyUpdatePack struct {
+type GroupRemoval struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserUpdate               *TreeKEMUserUpdate        `protobuf:"bytes,1,opt,name=UserUpdate,proto3" json:"UserUpdate,omitempty"`
-	ChatbotUpdateCiphertexts *ECKEMCipherTextStringMap `protobuf:"bytes,2,opt,name=ChatbotUpdateCiphertexts,proto3" json:"ChatbotUpdateCiphertexts,omitempty"`
-	NewRootPubKey            []byte                    `protobuf:"bytes,3,opt,name=NewRootPubKey,proto3" json:"NewRootPubKey,omitempty"`
-	NewRootSignPubKey        []byte                    `protobuf:"bytes,4,opt,name=NewRootSignPubKey,proto3" json:"NewRootSignPubKey,omitempty"`
+	SenderID          string             `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID           string             `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	RemovedID         string             `protobuf:"bytes,3,opt,name=removedID,proto3" json:"removedID,omitempty"`
+	ParticipantIDs    []string           `protobuf:"bytes,4,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
+	MlsRemove         []byte             `protobuf:"bytes,5,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
+	MlsRemoveCommit   []byte             `protobuf:"bytes,6,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
+	GroupType         GroupType          `protobuf:"varint,7,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	TreeKEMUserRemove *TreeKEMUserRemove `protobuf:"bytes,8,opt,name=treeKEMUserRemove,proto3" json:"treeKEMUserRemove,omitempty"`
 }
 
-func (x *TreeKEMKeyUpdatePack) Reset() {
-	*x = TreeKEMKeyUpdatePack{}
+func (x *GroupRemoval) Reset() {
+	*x = GroupRemoval{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[53]
+		mi := &file_protos_services_services_proto_msgTypes[58]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TreeKEMKeyUpdatePack) String() string {
+func (x *GroupRemoval) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TreeKEMKeyUpdatePack) ProtoMessage() {}
+func (*GroupRemoval) ProtoMessage() {}
 
-func (x *TreeKEMKeyUpdatePack) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[53]
+func (x *GroupRemoval) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[58]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4095,66 +4462,97 @@ func (x *TreeKEMKeyUpdatePack) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TreeKEMKeyUpdatePack.ProtoReflect.Descriptor instead.
-func (*TreeKEMKeyUpdatePack) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{53}
+// Deprecated: Use GroupRemoval.ProtoReflect.Descriptor instead.
+func (*GroupRemoval) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *TreeKEMKeyUpdatePack) GetUserUpdate() *TreeKEMUserUpdate {
+func (x *GroupRemoval) GetSenderID() string {
 	if x != nil {
-		return x.UserUpdate
+		return x.SenderID
+	}
+	return ""
+}
+
+func (x *GroupRemoval) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *GroupRemoval) GetRemovedID() string {
+	if x != nil {
+		return x.RemovedID
+	}
+	return ""
+}
+
+func (x *GroupRemoval) GetParticipantIDs() []string {
+	if x != nil {
+		return x.ParticipantIDs
 	}
 	return nil
 }
 
-func (x *TreeKEMKeyUpdatePack) GetChatbotUpdateCiphertexts() *ECKEMCipherTextStringMap {
+func (x *GroupRemoval) GetMlsRemove() []byte {
 	if x != nil {
-		return x.ChatbotUpdateCiphertexts
+		return x.MlsRemove
 	}
 	return nil
 }
 
-func (x *TreeKEMKeyUpdatePack) GetNewRootPubKey() []byte {
+func (x *GroupRemoval) GetMlsRemoveCommit() []byte {
 	if x != nil {
-		return x.NewRootPubKey
+		return x.MlsRemoveCommit
 	}
 	return nil
 }
 
-func (x *TreeKEMKeyUpdatePack) GetNewRootSignPubKey() []byte {
+func (x *GroupRemoval) GetGroupType() GroupType {
 	if x != nil {
-		return x.NewRootSignPubKey
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
+}
+
+func (x *GroupRemoval) GetTreeKEMUserRemove() *TreeKEMUserRemove {
+	if x != nil {
+		return x.TreeKEMUserRemove
 	}
 	return nil
 }
 
-type MultiTreeKEMExternalKeyUpdatePack struct {
+type GroupChatbotScopeUpdate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChatbotUpdate   *ECKEMCipherText `protobuf:"bytes,1,opt,name=ChatbotUpdate,proto3" json:"ChatbotUpdate,omitempty"`
-	NewCbPubKey     []byte           `protobuf:"bytes,2,opt,name=NewCbPubKey,proto3" json:"NewCbPubKey,omitempty"`
-	NewCbSignPubKey []byte           `protobuf:"bytes,3,opt,name=NewCbSignPubKey,proto3" json:"NewCbSignPubKey,omitempty"`
+	SenderID     string               `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID      string               `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	ChatbotID    string               `protobuf:"bytes,3,opt,name=chatbotID,proto3" json:"chatbotID,omitempty"`
+	GroupType    GroupType            `protobuf:"varint,4,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	Scopes       *ChatbotScopes       `protobuf:"bytes,5,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	SignedScopes *SignedChatbotScopes `protobuf:"bytes,6,opt,name=signedScopes,proto3" json:"signedScopes,omitempty"`
 }
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) Reset() {
-	*x = MultiTreeKEMExternalKeyUpdatePack{}
+func (x *GroupChatbotScopeUpdate) Reset() {
+	*x = GroupChatbotScopeUpdate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[54]
+		mi := &file_protos_services_services_proto_msgTypes[59]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) String() string {
+func (x *GroupChatbotScopeUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MultiTreeKEMExternalKeyUpdatePack) ProtoMessage() {}
+func (*GroupChatbotScopeUpdate) ProtoMessage() {}
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[54]
+func (x *GroupChatbotScopeUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[59]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4165,114 +4563,91 @@ func (x *MultiTreeKEMExternalKeyUpdatePack) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MultiTreeKEMExternalKeyUpdatePack.ProtoReflect.Descriptor instead.
-func (*MultiTreeKEMExternalKeyUpdatePack) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{54}
+// Deprecated: Use GroupChatbotScopeUpdate.ProtoReflect.Descriptor instead.
+func (*GroupChatbotScopeUpdate) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) GetChatbotUpdate() *ECKEMCipherText {
+func (x *GroupChatbotScopeUpdate) GetSenderID() string {
 	if x != nil {
-		return x.ChatbotUpdate
+		return x.SenderID
 	}
-	return nil
+	return ""
 }
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) GetNewCbPubKey() []byte {
+func (x *GroupChatbotScopeUpdate) GetGroupID() string {
 	if x != nil {
-		return x.NewCbPubKey
+		return x.GroupID
 	}
-	return nil
+	return ""
 }
 
-func (x *MultiTreeKEMExternalKeyUpdatePack) GetNewCbSignPubKey() []byte {
+func (x *GroupChatbotScopeUpdate) GetChatbotID() string {
 	if x != nil {
-		return x.NewCbSignPubKey
-	}
-	return nil
-}
-
-type TreeKEMGroupInitKey struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Size     uint32                  `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
-	Frontier map[uint32]*TreeKEMNode `protobuf:"bytes,2,rep,name=Frontier,proto3" json:"Frontier,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-}
-
-func (x *TreeKEMGroupInitKey) Reset() {
-	*x = TreeKEMGroupInitKey{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[55]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		return x.ChatbotID
 	}
+	return ""
 }
 
-func (x *TreeKEMGroupInitKey) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*TreeKEMGroupInitKey) ProtoMessage() {}
-
-func (x *TreeKEMGroupInitKey) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[55]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GroupChatbotScopeUpdate) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use TreeKEMGroupInitKey.ProtoReflect.Descriptor instead.
-func (*TreeKEMGroupInitKey) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{55}
+	return GroupType_CLIENT_SIDE
 }
 
-func (x *TreeKEMGroupInitKey) GetSize() uint32 {
+func (x *GroupChatbotScopeUpdate) GetScopes() *ChatbotScopes {
 	if x != nil {
-		return x.Size
+		return x.Scopes
 	}
-	return 0
+	return nil
 }
 
-func (x *TreeKEMGroupInitKey) GetFrontier() map[uint32]*TreeKEMNode {
+func (x *GroupChatbotScopeUpdate) GetSignedScopes() *SignedChatbotScopes {
 	if x != nil {
-		return x.Frontier
+		return x.SignedScopes
 	}
 	return nil
 }
 
-type ECKEMCipherText struct {
+type GroupChatbotInvitation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Public     []byte `protobuf:"bytes,1,opt,name=Public,proto3" json:"Public,omitempty"`
-	IV         []byte `protobuf:"bytes,2,opt,name=IV,proto3" json:"IV,omitempty"`
-	CipherText []byte `protobuf:"bytes,3,opt,name=CipherText,proto3" json:"CipherText,omitempty"`
+	SenderID           string         `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID            string         `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	ParticipantIDs     []string       `protobuf:"bytes,3,rep,name=participantIDs,proto3" json:"participantIDs,omitempty"`
+	GroupType          GroupType      `protobuf:"varint,4,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	IsIGA              bool           `protobuf:"varint,5,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
+	IsPseudo           bool           `protobuf:"varint,6,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
+	TreekemRootPub     []byte         `protobuf:"bytes,7,opt,name=treekemRootPub,proto3" json:"treekemRootPub,omitempty"`
+	TreekemRootSignPub []byte         `protobuf:"bytes,8,opt,name=treekemRootSignPub,proto3" json:"treekemRootSignPub,omitempty"`
+	ChatbotInitLeaf    []byte         `protobuf:"bytes,9,opt,name=chatbotInitLeaf,proto3" json:"chatbotInitLeaf,omitempty"`
+	MlsWelcomeMessage  []byte         `protobuf:"bytes,10,opt,name=MlsWelcomeMessage,proto3" json:"MlsWelcomeMessage,omitempty"`
+	MlsKeyPackageID    uint32         `protobuf:"varint,11,opt,name=MlsKeyPackageID,proto3" json:"MlsKeyPackageID,omitempty"`
+	Scopes             *ChatbotScopes `protobuf:"bytes,12,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	CipherSuite        uint32         `protobuf:"varint,13,opt,name=cipherSuite,proto3" json:"cipherSuite,omitempty"`
+	HybridKEM          bool           `protobuf:"varint,14,opt,name=hybridKEM,proto3" json:"hybridKEM,omitempty"`
 }
 
-func (x *ECKEMCipherText) Reset() {
-	*x = ECKEMCipherText{}
+func (x *GroupChatbotInvitation) Reset() {
+	*x = GroupChatbotInvitation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[56]
+		mi := &file_protos_services_services_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ECKEMCipherText) String() string {
+func (x *GroupChatbotInvitation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ECKEMCipherText) ProtoMessage() {}
+func (*GroupChatbotInvitation) ProtoMessage() {}
 
-func (x *ECKEMCipherText) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[56]
+func (x *GroupChatbotInvitation) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4283,104 +4658,147 @@ func (x *ECKEMCipherText) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ECKEMCipherText.ProtoReflect.Descriptor instead.
-func (*ECKEMCipherText) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{56}
+// Deprecated: Use GroupChatbotInvitation.ProtoReflect.Descriptor instead.
+func (*GroupChatbotInvitation) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *ECKEMCipherText) GetPublic() []byte {
+func (x *GroupChatbotInvitation) GetSenderID() string {
 	if x != nil {
-		return x.Public
+		return x.SenderID
 	}
-	return nil
+	return ""
 }
 
-func (x *ECKEMCipherText) GetIV() []byte {
+func (x *GroupChatbotInvitation) GetGroupID() string {
 	if x != nil {
-		return x.IV
+		return x.GroupID
 	}
-	return nil
+	return ""
 }
 
-func (x *ECKEMCipherText) GetCipherText() []byte {
+func (x *GroupChatbotInvitation) GetParticipantIDs() []string {
 	if x != nil {
-		return x.CipherText
+		return x.ParticipantIDs
 	}
 	return nil
 }
 
-type ECKEMCipherTextMap struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *GroupChatbotInvitation) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
+}
 
-	Ciphertexts map[uint32]*ECKEMCipherText `protobuf:"bytes,1,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+func (x *GroupChatbotInvitation) GetIsIGA() bool {
+	if x != nil {
+		return x.IsIGA
+	}
+	return false
 }
 
-func (x *ECKEMCipherTextMap) Reset() {
-	*x = ECKEMCipherTextMap{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[57]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *GroupChatbotInvitation) GetIsPseudo() bool {
+	if x != nil {
+		return x.IsPseudo
 	}
+	return false
 }
 
-func (x *ECKEMCipherTextMap) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GroupChatbotInvitation) GetTreekemRootPub() []byte {
+	if x != nil {
+		return x.TreekemRootPub
+	}
+	return nil
 }
 
-func (*ECKEMCipherTextMap) ProtoMessage() {}
+func (x *GroupChatbotInvitation) GetTreekemRootSignPub() []byte {
+	if x != nil {
+		return x.TreekemRootSignPub
+	}
+	return nil
+}
 
-func (x *ECKEMCipherTextMap) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[57]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GroupChatbotInvitation) GetChatbotInitLeaf() []byte {
+	if x != nil {
+		return x.ChatbotInitLeaf
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ECKEMCipherTextMap.ProtoReflect.Descriptor instead.
-func (*ECKEMCipherTextMap) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{57}
+func (x *GroupChatbotInvitation) GetMlsWelcomeMessage() []byte {
+	if x != nil {
+		return x.MlsWelcomeMessage
+	}
+	return nil
 }
 
-func (x *ECKEMCipherTextMap) GetCiphertexts() map[uint32]*ECKEMCipherText {
+func (x *GroupChatbotInvitation) GetMlsKeyPackageID() uint32 {
 	if x != nil {
-		return x.Ciphertexts
+		return x.MlsKeyPackageID
+	}
+	return 0
+}
+
+func (x *GroupChatbotInvitation) GetScopes() *ChatbotScopes {
+	if x != nil {
+		return x.Scopes
 	}
 	return nil
 }
 
-type ECKEMCipherTextStringMap struct {
+func (x *GroupChatbotInvitation) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+func (x *GroupChatbotInvitation) GetHybridKEM() bool {
+	if x != nil {
+		return x.HybridKEM
+	}
+	return false
+}
+
+type GroupChatbotAddition struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ciphertexts map[string]*ECKEMCipherText `protobuf:"bytes,1,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	SenderID          string                `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID           string                `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	AddedChatbotID    string                `protobuf:"bytes,3,opt,name=addedChatbotID,proto3" json:"addedChatbotID,omitempty"`
+	ChatbotIDs        []string              `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
+	GroupType         GroupType             `protobuf:"varint,5,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
+	IsIGA             bool                  `protobuf:"varint,6,opt,name=isIGA,proto3" json:"isIGA,omitempty"`
+	IsPseudo          bool                  `protobuf:"varint,7,opt,name=isPseudo,proto3" json:"isPseudo,omitempty"`
+	ChatbotCipherText *ECKEMCipherText      `protobuf:"bytes,8,opt,name=chatbotCipherText,proto3" json:"chatbotCipherText,omitempty"`
+	MlsUserAdd        []byte                `protobuf:"bytes,9,opt,name=mlsUserAdd,proto3" json:"mlsUserAdd,omitempty"`
+	MlsAddCommit      []byte                `protobuf:"bytes,10,opt,name=mlsAddCommit,proto3" json:"mlsAddCommit,omitempty"`
+	ChatbotRouting    *SignedChatbotRouting `protobuf:"bytes,11,opt,name=chatbotRouting,proto3" json:"chatbotRouting,omitempty"`
+	Scopes            *ChatbotScopes        `protobuf:"bytes,12,opt,name=scopes,proto3" json:"scopes,omitempty"`
+	HybridKEM         bool                  `protobuf:"varint,13,opt,name=hybridKEM,proto3" json:"hybridKEM,omitempty"`
+	SignedScopes      *SignedChatbotScopes  `protobuf:"bytes,14,opt,name=signedScopes,proto3" json:"signedScopes,omitempty"`
 }
 
-func (x *ECKEMCipherTextStringMap) Reset() {
-	*x = ECKEMCipherTextStringMap{}
+func (x *GroupChatbotAddition) Reset() {
+	*x = GroupChatbotAddition{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[58]
+		mi := &file_protos_services_services_proto_msgTypes[61]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ECKEMCipherTextStringMap) String() string {
+func (x *GroupChatbotAddition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ECKEMCipherTextStringMap) ProtoMessage() {}
+func (*GroupChatbotAddition) ProtoMessage() {}
 
-func (x *ECKEMCipherTextStringMap) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[58]
+func (x *GroupChatbotAddition) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[61]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4391,47 +4809,140 @@ func (x *ECKEMCipherTextStringMap) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ECKEMCipherTextStringMap.ProtoReflect.Descriptor instead.
-func (*ECKEMCipherTextStringMap) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{58}
+// Deprecated: Use GroupChatbotAddition.ProtoReflect.Descriptor instead.
+func (*GroupChatbotAddition) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *ECKEMCipherTextStringMap) GetCiphertexts() map[string]*ECKEMCipherText {
+func (x *GroupChatbotAddition) GetSenderID() string {
 	if x != nil {
-		return x.Ciphertexts
+		return x.SenderID
+	}
+	return ""
+}
+
+func (x *GroupChatbotAddition) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *GroupChatbotAddition) GetAddedChatbotID() string {
+	if x != nil {
+		return x.AddedChatbotID
+	}
+	return ""
+}
+
+func (x *GroupChatbotAddition) GetChatbotIDs() []string {
+	if x != nil {
+		return x.ChatbotIDs
 	}
 	return nil
 }
 
-type TreeKEMNode struct {
+func (x *GroupChatbotAddition) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
+}
+
+func (x *GroupChatbotAddition) GetIsIGA() bool {
+	if x != nil {
+		return x.IsIGA
+	}
+	return false
+}
+
+func (x *GroupChatbotAddition) GetIsPseudo() bool {
+	if x != nil {
+		return x.IsPseudo
+	}
+	return false
+}
+
+func (x *GroupChatbotAddition) GetChatbotCipherText() *ECKEMCipherText {
+	if x != nil {
+		return x.ChatbotCipherText
+	}
+	return nil
+}
+
+func (x *GroupChatbotAddition) GetMlsUserAdd() []byte {
+	if x != nil {
+		return x.MlsUserAdd
+	}
+	return nil
+}
+
+func (x *GroupChatbotAddition) GetMlsAddCommit() []byte {
+	if x != nil {
+		return x.MlsAddCommit
+	}
+	return nil
+}
+
+func (x *GroupChatbotAddition) GetChatbotRouting() *SignedChatbotRouting {
+	if x != nil {
+		return x.ChatbotRouting
+	}
+	return nil
+}
+
+func (x *GroupChatbotAddition) GetScopes() *ChatbotScopes {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *GroupChatbotAddition) GetHybridKEM() bool {
+	if x != nil {
+		return x.HybridKEM
+	}
+	return false
+}
+
+func (x *GroupChatbotAddition) GetSignedScopes() *SignedChatbotScopes {
+	if x != nil {
+		return x.SignedScopes
+	}
+	return nil
+}
+
+type GroupChatbotRemoval struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Secret      []byte `protobuf:"bytes,1,opt,name=Secret,proto3" json:"Secret,omitempty"`
-	Public      []byte `protobuf:"bytes,2,opt,name=Public,proto3" json:"Public,omitempty"`
-	Private     []byte `protobuf:"bytes,3,opt,name=Private,proto3" json:"Private,omitempty"`
-	SignPublic  []byte `protobuf:"bytes,4,opt,name=SignPublic,proto3" json:"SignPublic,omitempty"`
-	SignPrivate []byte `protobuf:"bytes,5,opt,name=SignPrivate,proto3" json:"SignPrivate,omitempty"`
+	SenderID         string    `protobuf:"bytes,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	GroupID          string    `protobuf:"bytes,2,opt,name=groupID,proto3" json:"groupID,omitempty"`
+	RemovedChatbotID string    `protobuf:"bytes,3,opt,name=removedChatbotID,proto3" json:"removedChatbotID,omitempty"`
+	ChatbotIDs       []string  `protobuf:"bytes,4,rep,name=chatbotIDs,proto3" json:"chatbotIDs,omitempty"`
+	MlsRemove        []byte    `protobuf:"bytes,5,opt,name=mlsRemove,proto3" json:"mlsRemove,omitempty"`
+	MlsRemoveCommit  []byte    `protobuf:"bytes,6,opt,name=mlsRemoveCommit,proto3" json:"mlsRemoveCommit,omitempty"`
+	GroupType        GroupType `protobuf:"varint,7,opt,name=groupType,proto3,enum=Services.GroupType" json:"groupType,omitempty"`
 }
 
-func (x *TreeKEMNode) Reset() {
-	*x = TreeKEMNode{}
+func (x *GroupChatbotRemoval) Reset() {
+	*x = GroupChatbotRemoval{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_protos_services_services_proto_msgTypes[59]
+		mi := &file_protos_services_services_proto_msgTypes[62]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TreeKEMNode) String() string {
+func (x *GroupChatbotRemoval) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TreeKEMNode) ProtoMessage() {}
+func (*GroupChatbotRemoval) ProtoMessage() {}
 
-func (x *TreeKEMNode) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_services_services_proto_msgTypes[59]
+func (x *GroupChatbotRemoval) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[62]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4442,52 +4953,2847 @@ func (x *TreeKEMNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TreeKEMNode.ProtoReflect.Descriptor instead.
-func (*TreeKEMNode) Descriptor() ([]byte, []int) {
-	return file_protos_services_services_proto_rawDescGZIP(), []int{59}
+// Deprecated: Use GroupChatbotRemoval.ProtoReflect.Descriptor instead.
+func (*GroupChatbotRemoval) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *TreeKEMNode) GetSecret() []byte {
+func (x *GroupChatbotRemoval) GetSenderID() string {
 	if x != nil {
-		return x.Secret
+		return x.SenderID
 	}
-	return nil
+	return ""
 }
 
-func (x *TreeKEMNode) GetPublic() []byte {
+func (x *GroupChatbotRemoval) GetGroupID() string {
 	if x != nil {
-		return x.Public
+		return x.GroupID
 	}
-	return nil
+	return ""
 }
 
-func (x *TreeKEMNode) GetPrivate() []byte {
+func (x *GroupChatbotRemoval) GetRemovedChatbotID() string {
 	if x != nil {
-		return x.Private
+		return x.RemovedChatbotID
+	}
+	return ""
+}
+
+func (x *GroupChatbotRemoval) GetChatbotIDs() []string {
+	if x != nil {
+		return x.ChatbotIDs
 	}
 	return nil
 }
 
-func (x *TreeKEMNode) GetSignPublic() []byte {
+func (x *GroupChatbotRemoval) GetMlsRemove() []byte {
 	if x != nil {
-		return x.SignPublic
+		return x.MlsRemove
 	}
 	return nil
 }
 
-func (x *TreeKEMNode) GetSignPrivate() []byte {
+func (x *GroupChatbotRemoval) GetMlsRemoveCommit() []byte {
 	if x != nil {
-		return x.SignPrivate
+		return x.MlsRemoveCommit
 	}
 	return nil
 }
 
-var File_protos_services_services_proto protoreflect.FileDescriptor
+func (x *GroupChatbotRemoval) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
+}
 
-var file_protos_services_services_proto_rawDesc = []byte{
-	0x0a, 0x1e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x08, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x13, 0x55, 0x70,
+type ServerEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EventType ServerEventType `protobuf:"varint,1,opt,name=eventType,proto3,enum=Services.ServerEventType" json:"eventType,omitempty"`
+	// Types that are assignable to EventData:
+	//
+	//	*ServerEvent_GroupInvitation
+	//	*ServerEvent_GroupAddition
+	//	*ServerEvent_GroupRemoval
+	//	*ServerEvent_GroupChatbotInvitation
+	//	*ServerEvent_GroupChatbotAddition
+	//	*ServerEvent_GroupChatbotRemoval
+	//	*ServerEvent_GroupChatbotScopeUpdate
+	EventData isServerEvent_EventData `protobuf_oneof:"eventData"`
+	// When the server emitted the event, in Unix milliseconds.
+	Timestamp int64 `protobuf:"varint,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *ServerEvent) Reset() {
+	*x = ServerEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerEvent) ProtoMessage() {}
+
+func (x *ServerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
+func (*ServerEvent) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ServerEvent) GetEventType() ServerEventType {
+	if x != nil {
+		return x.EventType
+	}
+	return ServerEventType_GROUP_INVITATION
+}
+
+func (m *ServerEvent) GetEventData() isServerEvent_EventData {
+	if m != nil {
+		return m.EventData
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupInvitation() *GroupInvitation {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupInvitation); ok {
+		return x.GroupInvitation
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupAddition() *GroupAddition {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupAddition); ok {
+		return x.GroupAddition
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupRemoval() *GroupRemoval {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupRemoval); ok {
+		return x.GroupRemoval
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupChatbotInvitation() *GroupChatbotInvitation {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotInvitation); ok {
+		return x.GroupChatbotInvitation
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupChatbotAddition() *GroupChatbotAddition {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotAddition); ok {
+		return x.GroupChatbotAddition
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupChatbotRemoval() *GroupChatbotRemoval {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotRemoval); ok {
+		return x.GroupChatbotRemoval
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetGroupChatbotScopeUpdate() *GroupChatbotScopeUpdate {
+	if x, ok := x.GetEventData().(*ServerEvent_GroupChatbotScopeUpdate); ok {
+		return x.GroupChatbotScopeUpdate
+	}
+	return nil
+}
+
+func (x *ServerEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type isServerEvent_EventData interface {
+	isServerEvent_EventData()
+}
+
+type ServerEvent_GroupInvitation struct {
+	GroupInvitation *GroupInvitation `protobuf:"bytes,2,opt,name=groupInvitation,proto3,oneof"`
+}
+
+type ServerEvent_GroupAddition struct {
+	GroupAddition *GroupAddition `protobuf:"bytes,3,opt,name=groupAddition,proto3,oneof"`
+}
+
+type ServerEvent_GroupRemoval struct {
+	GroupRemoval *GroupRemoval `protobuf:"bytes,4,opt,name=groupRemoval,proto3,oneof"`
+}
+
+type ServerEvent_GroupChatbotInvitation struct {
+	GroupChatbotInvitation *GroupChatbotInvitation `protobuf:"bytes,5,opt,name=groupChatbotInvitation,proto3,oneof"`
+}
+
+type ServerEvent_GroupChatbotAddition struct {
+	GroupChatbotAddition *GroupChatbotAddition `protobuf:"bytes,6,opt,name=groupChatbotAddition,proto3,oneof"`
+}
+
+type ServerEvent_GroupChatbotRemoval struct {
+	GroupChatbotRemoval *GroupChatbotRemoval `protobuf:"bytes,7,opt,name=groupChatbotRemoval,proto3,oneof"`
+}
+
+type ServerEvent_GroupChatbotScopeUpdate struct {
+	GroupChatbotScopeUpdate *GroupChatbotScopeUpdate `protobuf:"bytes,8,opt,name=groupChatbotScopeUpdate,proto3,oneof"`
+}
+
+func (*ServerEvent_GroupInvitation) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupAddition) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupRemoval) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupChatbotInvitation) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupChatbotAddition) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupChatbotRemoval) isServerEvent_EventData() {}
+
+func (*ServerEvent_GroupChatbotScopeUpdate) isServerEvent_EventData() {}
+
+// TreeKEM
+type TreeKEMUserAdd struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size        uint32                  `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
+	Ciphertexts []*ECKEMCipherTextMap   `protobuf:"bytes,2,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty"`
+	Nodes       map[uint32]*TreeKEMNode `protobuf:"bytes,3,rep,name=Nodes,proto3" json:"Nodes,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TreeKEMUserAdd) Reset() {
+	*x = TreeKEMUserAdd{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMUserAdd) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMUserAdd) ProtoMessage() {}
+
+func (x *TreeKEMUserAdd) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMUserAdd.ProtoReflect.Descriptor instead.
+func (*TreeKEMUserAdd) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *TreeKEMUserAdd) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TreeKEMUserAdd) GetCiphertexts() []*ECKEMCipherTextMap {
+	if x != nil {
+		return x.Ciphertexts
+	}
+	return nil
+}
+
+func (x *TreeKEMUserAdd) GetNodes() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type TreeKEMUserUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From        uint32                  `protobuf:"varint,1,opt,name=From,proto3" json:"From,omitempty"`
+	Ciphertexts []*ECKEMCipherTextMap   `protobuf:"bytes,2,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty"`
+	Nodes       map[uint32]*TreeKEMNode `protobuf:"bytes,3,rep,name=Nodes,proto3" json:"Nodes,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TreeKEMUserUpdate) Reset() {
+	*x = TreeKEMUserUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMUserUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMUserUpdate) ProtoMessage() {}
+
+func (x *TreeKEMUserUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMUserUpdate.ProtoReflect.Descriptor instead.
+func (*TreeKEMUserUpdate) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *TreeKEMUserUpdate) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *TreeKEMUserUpdate) GetCiphertexts() []*ECKEMCipherTextMap {
+	if x != nil {
+		return x.Ciphertexts
+	}
+	return nil
+}
+
+func (x *TreeKEMUserUpdate) GetNodes() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type TreeKEMUserRemove struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index       uint32                  `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	Ciphertexts []*ECKEMCipherTextMap   `protobuf:"bytes,2,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty"`
+	Copath      map[uint32]*TreeKEMNode `protobuf:"bytes,3,rep,name=Copath,proto3" json:"Copath,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TreeKEMUserRemove) Reset() {
+	*x = TreeKEMUserRemove{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMUserRemove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMUserRemove) ProtoMessage() {}
+
+func (x *TreeKEMUserRemove) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMUserRemove.ProtoReflect.Descriptor instead.
+func (*TreeKEMUserRemove) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *TreeKEMUserRemove) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *TreeKEMUserRemove) GetCiphertexts() []*ECKEMCipherTextMap {
+	if x != nil {
+		return x.Ciphertexts
+	}
+	return nil
+}
+
+func (x *TreeKEMUserRemove) GetCopath() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.Copath
+	}
+	return nil
+}
+
+type TreeKEMKeyUpdatePack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserUpdate               *TreeKEMUserUpdate        `protobuf:"bytes,1,opt,name=UserUpdate,proto3" json:"UserUpdate,omitempty"`
+	ChatbotUpdateCiphertexts *ECKEMCipherTextStringMap `protobuf:"bytes,2,opt,name=ChatbotUpdateCiphertexts,proto3" json:"ChatbotUpdateCiphertexts,omitempty"`
+	NewRootPubKey            []byte                    `protobuf:"bytes,3,opt,name=NewRootPubKey,proto3" json:"NewRootPubKey,omitempty"`
+	NewRootSignPubKey        []byte                    `protobuf:"bytes,4,opt,name=NewRootSignPubKey,proto3" json:"NewRootSignPubKey,omitempty"`
+	// The hybrid public key of the new TreeKEM root, for the chatbots using the hybrid KEM.
+	NewRootHybridPubKey []byte `protobuf:"bytes,5,opt,name=NewRootHybridPubKey,proto3" json:"NewRootHybridPubKey,omitempty"`
+	// The signature of the sender's identity key over the pack, for the members and the chatbots without IGA.
+	Signature []byte `protobuf:"bytes,6,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	// The MAC over the pack for each chatbot, keyed by the root the chatbot shares with the group before the update, so
+	// that chatbots with IGA can authenticate the update without learning who sent it.
+	ChatbotMACs map[string][]byte `protobuf:"bytes,7,rep,name=ChatbotMACs,proto3" json:"ChatbotMACs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The epoch each chatbot's root moves to with the update, and the transcript hash over its roots so far.
+	ChatbotEpochs           map[string]uint64 `protobuf:"bytes,8,rep,name=ChatbotEpochs,proto3" json:"ChatbotEpochs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ChatbotTranscriptHashes map[string][]byte `protobuf:"bytes,9,rep,name=ChatbotTranscriptHashes,proto3" json:"ChatbotTranscriptHashes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TreeKEMKeyUpdatePack) Reset() {
+	*x = TreeKEMKeyUpdatePack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMKeyUpdatePack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMKeyUpdatePack) ProtoMessage() {}
+
+func (x *TreeKEMKeyUpdatePack) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMKeyUpdatePack.ProtoReflect.Descriptor instead.
+func (*TreeKEMKeyUpdatePack) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *TreeKEMKeyUpdatePack) GetUserUpdate() *TreeKEMUserUpdate {
+	if x != nil {
+		return x.UserUpdate
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetChatbotUpdateCiphertexts() *ECKEMCipherTextStringMap {
+	if x != nil {
+		return x.ChatbotUpdateCiphertexts
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetNewRootPubKey() []byte {
+	if x != nil {
+		return x.NewRootPubKey
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetNewRootSignPubKey() []byte {
+	if x != nil {
+		return x.NewRootSignPubKey
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetNewRootHybridPubKey() []byte {
+	if x != nil {
+		return x.NewRootHybridPubKey
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetChatbotMACs() map[string][]byte {
+	if x != nil {
+		return x.ChatbotMACs
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetChatbotEpochs() map[string]uint64 {
+	if x != nil {
+		return x.ChatbotEpochs
+	}
+	return nil
+}
+
+func (x *TreeKEMKeyUpdatePack) GetChatbotTranscriptHashes() map[string][]byte {
+	if x != nil {
+		return x.ChatbotTranscriptHashes
+	}
+	return nil
+}
+
+type MultiTreeKEMExternalKeyUpdatePack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChatbotUpdate   *ECKEMCipherText `protobuf:"bytes,1,opt,name=ChatbotUpdate,proto3" json:"ChatbotUpdate,omitempty"`
+	NewCbPubKey     []byte           `protobuf:"bytes,2,opt,name=NewCbPubKey,proto3" json:"NewCbPubKey,omitempty"`
+	NewCbSignPubKey []byte           `protobuf:"bytes,3,opt,name=NewCbSignPubKey,proto3" json:"NewCbSignPubKey,omitempty"`
+	// The epoch the chatbot's root moves to with the update, and the transcript hash over its roots so far.
+	Epoch          uint64 `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	TranscriptHash []byte `protobuf:"bytes,5,opt,name=TranscriptHash,proto3" json:"TranscriptHash,omitempty"`
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) Reset() {
+	*x = MultiTreeKEMExternalKeyUpdatePack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiTreeKEMExternalKeyUpdatePack) ProtoMessage() {}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiTreeKEMExternalKeyUpdatePack.ProtoReflect.Descriptor instead.
+func (*MultiTreeKEMExternalKeyUpdatePack) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) GetChatbotUpdate() *ECKEMCipherText {
+	if x != nil {
+		return x.ChatbotUpdate
+	}
+	return nil
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) GetNewCbPubKey() []byte {
+	if x != nil {
+		return x.NewCbPubKey
+	}
+	return nil
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) GetNewCbSignPubKey() []byte {
+	if x != nil {
+		return x.NewCbSignPubKey
+	}
+	return nil
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *MultiTreeKEMExternalKeyUpdatePack) GetTranscriptHash() []byte {
+	if x != nil {
+		return x.TranscriptHash
+	}
+	return nil
+}
+
+type TreeKEMGroupInitKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Size        uint32                  `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
+	Frontier    map[uint32]*TreeKEMNode `protobuf:"bytes,2,rep,name=Frontier,proto3" json:"Frontier,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	GroupID     string                  `protobuf:"bytes,3,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	Epoch       uint64                  `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	CipherSuite uint32                  `protobuf:"varint,5,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *TreeKEMGroupInitKey) Reset() {
+	*x = TreeKEMGroupInitKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMGroupInitKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMGroupInitKey) ProtoMessage() {}
+
+func (x *TreeKEMGroupInitKey) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMGroupInitKey.ProtoReflect.Descriptor instead.
+func (*TreeKEMGroupInitKey) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *TreeKEMGroupInitKey) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TreeKEMGroupInitKey) GetFrontier() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.Frontier
+	}
+	return nil
+}
+
+func (x *TreeKEMGroupInitKey) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *TreeKEMGroupInitKey) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *TreeKEMGroupInitKey) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+// The stored states below are versioned, so that the state a client persisted can still be loaded after the format
+// changes. Version is the version of the format the state was written with.
+type StoredRootEpoch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Epoch          uint64 `protobuf:"varint,1,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	TranscriptHash []byte `protobuf:"bytes,2,opt,name=TranscriptHash,proto3" json:"TranscriptHash,omitempty"`
+}
+
+func (x *StoredRootEpoch) Reset() {
+	*x = StoredRootEpoch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredRootEpoch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredRootEpoch) ProtoMessage() {}
+
+func (x *StoredRootEpoch) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredRootEpoch.ProtoReflect.Descriptor instead.
+func (*StoredRootEpoch) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *StoredRootEpoch) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *StoredRootEpoch) GetTranscriptHash() []byte {
+	if x != nil {
+		return x.TranscriptHash
+	}
+	return nil
+}
+
+type StoredTreeKEMState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     uint32                  `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	Size        uint32                  `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	Index       uint32                  `protobuf:"varint,3,opt,name=Index,proto3" json:"Index,omitempty"`
+	Nodes       map[uint32]*TreeKEMNode `protobuf:"bytes,4,rep,name=Nodes,proto3" json:"Nodes,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	GroupID     string                  `protobuf:"bytes,5,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	Epoch       uint64                  `protobuf:"varint,6,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	CipherSuite uint32                  `protobuf:"varint,7,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *StoredTreeKEMState) Reset() {
+	*x = StoredTreeKEMState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredTreeKEMState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredTreeKEMState) ProtoMessage() {}
+
+func (x *StoredTreeKEMState) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredTreeKEMState.ProtoReflect.Descriptor instead.
+func (*StoredTreeKEMState) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *StoredTreeKEMState) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredTreeKEMState) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *StoredTreeKEMState) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *StoredTreeKEMState) GetNodes() map[uint32]*TreeKEMNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *StoredTreeKEMState) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredTreeKEMState) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *StoredTreeKEMState) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+type StoredMultiTreeKEM struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version          uint32                      `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	TreeKEM          *StoredTreeKEMState         `protobuf:"bytes,2,opt,name=TreeKEM,proto3" json:"TreeKEM,omitempty"`
+	ExternalNodes    map[string]*TreeKEMNode     `protobuf:"bytes,3,rep,name=ExternalNodes,proto3" json:"ExternalNodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Roots            map[string]*TreeKEMNode     `protobuf:"bytes,4,rep,name=Roots,proto3" json:"Roots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LastTreeKEMRoots map[string]*TreeKEMNode     `protobuf:"bytes,5,rep,name=LastTreeKEMRoots,proto3" json:"LastTreeKEMRoots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Epochs           map[string]*StoredRootEpoch `protobuf:"bytes,6,rep,name=Epochs,proto3" json:"Epochs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	HybridKEM        map[string]bool             `protobuf:"bytes,7,rep,name=HybridKEM,proto3" json:"HybridKEM,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	CipherSuite      uint32                      `protobuf:"varint,8,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *StoredMultiTreeKEM) Reset() {
+	*x = StoredMultiTreeKEM{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredMultiTreeKEM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredMultiTreeKEM) ProtoMessage() {}
+
+func (x *StoredMultiTreeKEM) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredMultiTreeKEM.ProtoReflect.Descriptor instead.
+func (*StoredMultiTreeKEM) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *StoredMultiTreeKEM) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredMultiTreeKEM) GetTreeKEM() *StoredTreeKEMState {
+	if x != nil {
+		return x.TreeKEM
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetExternalNodes() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.ExternalNodes
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetRoots() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetLastTreeKEMRoots() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.LastTreeKEMRoots
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetEpochs() map[string]*StoredRootEpoch {
+	if x != nil {
+		return x.Epochs
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetHybridKEM() map[string]bool {
+	if x != nil {
+		return x.HybridKEM
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEM) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+type StoredMultiTreeKEMExternal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     uint32           `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	TreeKEMRoot *TreeKEMNode     `protobuf:"bytes,2,opt,name=TreeKEMRoot,proto3" json:"TreeKEMRoot,omitempty"`
+	SelfNode    *TreeKEMNode     `protobuf:"bytes,3,opt,name=SelfNode,proto3" json:"SelfNode,omitempty"`
+	Root        *TreeKEMNode     `protobuf:"bytes,4,opt,name=Root,proto3" json:"Root,omitempty"`
+	Epoch       *StoredRootEpoch `protobuf:"bytes,5,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	GroupID     string           `protobuf:"bytes,6,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	HybridKEM   bool             `protobuf:"varint,7,opt,name=HybridKEM,proto3" json:"HybridKEM,omitempty"`
+	CipherSuite uint32           `protobuf:"varint,8,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *StoredMultiTreeKEMExternal) Reset() {
+	*x = StoredMultiTreeKEMExternal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredMultiTreeKEMExternal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredMultiTreeKEMExternal) ProtoMessage() {}
+
+func (x *StoredMultiTreeKEMExternal) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredMultiTreeKEMExternal.ProtoReflect.Descriptor instead.
+func (*StoredMultiTreeKEMExternal) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *StoredMultiTreeKEMExternal) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredMultiTreeKEMExternal) GetTreeKEMRoot() *TreeKEMNode {
+	if x != nil {
+		return x.TreeKEMRoot
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEMExternal) GetSelfNode() *TreeKEMNode {
+	if x != nil {
+		return x.SelfNode
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEMExternal) GetRoot() *TreeKEMNode {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEMExternal) GetEpoch() *StoredRootEpoch {
+	if x != nil {
+		return x.Epoch
+	}
+	return nil
+}
+
+func (x *StoredMultiTreeKEMExternal) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredMultiTreeKEMExternal) GetHybridKEM() bool {
+	if x != nil {
+		return x.HybridKEM
+	}
+	return false
+}
+
+func (x *StoredMultiTreeKEMExternal) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+// The MLS state itself is not part of a stored MlsMultiTree, as it is kept by the MLS session.
+type StoredMlsMultiTree struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version       uint32                      `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	ExternalNodes map[string]*TreeKEMNode     `protobuf:"bytes,2,rep,name=ExternalNodes,proto3" json:"ExternalNodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Roots         map[string]*TreeKEMNode     `protobuf:"bytes,3,rep,name=Roots,proto3" json:"Roots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LastTreeRoots map[string]*TreeKEMNode     `protobuf:"bytes,4,rep,name=LastTreeRoots,proto3" json:"LastTreeRoots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Epochs        map[string]*StoredRootEpoch `protobuf:"bytes,5,rep,name=Epochs,proto3" json:"Epochs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	SelfPubKey    []byte                      `protobuf:"bytes,6,opt,name=SelfPubKey,proto3" json:"SelfPubKey,omitempty"`
+	SelfPrivKey   []byte                      `protobuf:"bytes,7,opt,name=SelfPrivKey,proto3" json:"SelfPrivKey,omitempty"`
+	GroupID       string                      `protobuf:"bytes,8,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	CipherSuite   uint32                      `protobuf:"varint,9,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *StoredMlsMultiTree) Reset() {
+	*x = StoredMlsMultiTree{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredMlsMultiTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredMlsMultiTree) ProtoMessage() {}
+
+func (x *StoredMlsMultiTree) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredMlsMultiTree.ProtoReflect.Descriptor instead.
+func (*StoredMlsMultiTree) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *StoredMlsMultiTree) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredMlsMultiTree) GetExternalNodes() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.ExternalNodes
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetRoots() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetLastTreeRoots() map[string]*TreeKEMNode {
+	if x != nil {
+		return x.LastTreeRoots
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetEpochs() map[string]*StoredRootEpoch {
+	if x != nil {
+		return x.Epochs
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetSelfPubKey() []byte {
+	if x != nil {
+		return x.SelfPubKey
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetSelfPrivKey() []byte {
+	if x != nil {
+		return x.SelfPrivKey
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTree) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredMlsMultiTree) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+type StoredMlsMultiTreeExternal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     uint32           `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	TreeKEMRoot *TreeKEMNode     `protobuf:"bytes,2,opt,name=TreeKEMRoot,proto3" json:"TreeKEMRoot,omitempty"`
+	SelfNode    *TreeKEMNode     `protobuf:"bytes,3,opt,name=SelfNode,proto3" json:"SelfNode,omitempty"`
+	Root        *TreeKEMNode     `protobuf:"bytes,4,opt,name=Root,proto3" json:"Root,omitempty"`
+	Epoch       *StoredRootEpoch `protobuf:"bytes,5,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	GroupID     string           `protobuf:"bytes,6,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	CipherSuite uint32           `protobuf:"varint,7,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+}
+
+func (x *StoredMlsMultiTreeExternal) Reset() {
+	*x = StoredMlsMultiTreeExternal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredMlsMultiTreeExternal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredMlsMultiTreeExternal) ProtoMessage() {}
+
+func (x *StoredMlsMultiTreeExternal) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredMlsMultiTreeExternal.ProtoReflect.Descriptor instead.
+func (*StoredMlsMultiTreeExternal) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *StoredMlsMultiTreeExternal) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredMlsMultiTreeExternal) GetTreeKEMRoot() *TreeKEMNode {
+	if x != nil {
+		return x.TreeKEMRoot
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTreeExternal) GetSelfNode() *TreeKEMNode {
+	if x != nil {
+		return x.SelfNode
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTreeExternal) GetRoot() *TreeKEMNode {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTreeExternal) GetEpoch() *StoredRootEpoch {
+	if x != nil {
+		return x.Epoch
+	}
+	return nil
+}
+
+func (x *StoredMlsMultiTreeExternal) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredMlsMultiTreeExternal) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+// A libsignal record kept for a remote address. For a trusted identity, Record is the serialized identity key.
+type StoredSignalRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceID uint32 `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Record   []byte `protobuf:"bytes,3,opt,name=Record,proto3" json:"Record,omitempty"`
+}
+
+func (x *StoredSignalRecord) Reset() {
+	*x = StoredSignalRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredSignalRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredSignalRecord) ProtoMessage() {}
+
+func (x *StoredSignalRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredSignalRecord.ProtoReflect.Descriptor instead.
+func (*StoredSignalRecord) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *StoredSignalRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoredSignalRecord) GetDeviceID() uint32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *StoredSignalRecord) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type StoredSenderKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID  string `protobuf:"bytes,1,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceID uint32 `protobuf:"varint,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Record   []byte `protobuf:"bytes,4,opt,name=Record,proto3" json:"Record,omitempty"`
+}
+
+func (x *StoredSenderKey) Reset() {
+	*x = StoredSenderKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredSenderKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredSenderKey) ProtoMessage() {}
+
+func (x *StoredSenderKey) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredSenderKey.ProtoReflect.Descriptor instead.
+func (*StoredSenderKey) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *StoredSenderKey) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredSenderKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoredSenderKey) GetDeviceID() uint32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *StoredSenderKey) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type StoredUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version               uint32                `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	UserID                string                `protobuf:"bytes,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	DeviceID              uint32                `protobuf:"varint,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	IdentityKeyPublic     []byte                `protobuf:"bytes,4,opt,name=IdentityKeyPublic,proto3" json:"IdentityKeyPublic,omitempty"`
+	IdentityKeyPrivate    []byte                `protobuf:"bytes,5,opt,name=IdentityKeyPrivate,proto3" json:"IdentityKeyPrivate,omitempty"`
+	RegistrationID        uint32                `protobuf:"varint,6,opt,name=RegistrationID,proto3" json:"RegistrationID,omitempty"`
+	PreKeys               [][]byte              `protobuf:"bytes,7,rep,name=PreKeys,proto3" json:"PreKeys,omitempty"`
+	SignedPreKeys         [][]byte              `protobuf:"bytes,8,rep,name=SignedPreKeys,proto3" json:"SignedPreKeys,omitempty"`
+	Sessions              []*StoredSignalRecord `protobuf:"bytes,9,rep,name=Sessions,proto3" json:"Sessions,omitempty"`
+	TrustedIdentities     []*StoredSignalRecord `protobuf:"bytes,10,rep,name=TrustedIdentities,proto3" json:"TrustedIdentities,omitempty"`
+	SenderKeys            []*StoredSenderKey    `protobuf:"bytes,11,rep,name=SenderKeys,proto3" json:"SenderKeys,omitempty"`
+	MlsIdentityPrivateKey []byte                `protobuf:"bytes,12,opt,name=MlsIdentityPrivateKey,proto3" json:"MlsIdentityPrivateKey,omitempty"`
+	MlsCredential         []byte                `protobuf:"bytes,13,opt,name=MlsCredential,proto3" json:"MlsCredential,omitempty"`
+	MlsInitialSecret      []byte                `protobuf:"bytes,14,opt,name=MlsInitialSecret,proto3" json:"MlsInitialSecret,omitempty"`
+	MlsKeyPackages        map[uint32][]byte     `protobuf:"bytes,15,rep,name=MlsKeyPackages,proto3" json:"MlsKeyPackages,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StoredUser) Reset() {
+	*x = StoredUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredUser) ProtoMessage() {}
+
+func (x *StoredUser) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredUser.ProtoReflect.Descriptor instead.
+func (*StoredUser) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *StoredUser) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredUser) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *StoredUser) GetDeviceID() uint32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *StoredUser) GetIdentityKeyPublic() []byte {
+	if x != nil {
+		return x.IdentityKeyPublic
+	}
+	return nil
+}
+
+func (x *StoredUser) GetIdentityKeyPrivate() []byte {
+	if x != nil {
+		return x.IdentityKeyPrivate
+	}
+	return nil
+}
+
+func (x *StoredUser) GetRegistrationID() uint32 {
+	if x != nil {
+		return x.RegistrationID
+	}
+	return 0
+}
+
+func (x *StoredUser) GetPreKeys() [][]byte {
+	if x != nil {
+		return x.PreKeys
+	}
+	return nil
+}
+
+func (x *StoredUser) GetSignedPreKeys() [][]byte {
+	if x != nil {
+		return x.SignedPreKeys
+	}
+	return nil
+}
+
+func (x *StoredUser) GetSessions() []*StoredSignalRecord {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *StoredUser) GetTrustedIdentities() []*StoredSignalRecord {
+	if x != nil {
+		return x.TrustedIdentities
+	}
+	return nil
+}
+
+func (x *StoredUser) GetSenderKeys() []*StoredSenderKey {
+	if x != nil {
+		return x.SenderKeys
+	}
+	return nil
+}
+
+func (x *StoredUser) GetMlsIdentityPrivateKey() []byte {
+	if x != nil {
+		return x.MlsIdentityPrivateKey
+	}
+	return nil
+}
+
+func (x *StoredUser) GetMlsCredential() []byte {
+	if x != nil {
+		return x.MlsCredential
+	}
+	return nil
+}
+
+func (x *StoredUser) GetMlsInitialSecret() []byte {
+	if x != nil {
+		return x.MlsInitialSecret
+	}
+	return nil
+}
+
+func (x *StoredUser) GetMlsKeyPackages() map[uint32][]byte {
+	if x != nil {
+		return x.MlsKeyPackages
+	}
+	return nil
+}
+
+// The group state a client keeps for a group. The TreeKEM, CMRT and MLS fields hold the serialized states and are
+// empty if the group has none.
+type StoredGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type            GroupType       `protobuf:"varint,1,opt,name=Type,proto3,enum=Services.GroupType" json:"Type,omitempty"`
+	GroupID         string          `protobuf:"bytes,2,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	Participants    []string        `protobuf:"bytes,3,rep,name=Participants,proto3" json:"Participants,omitempty"`
+	Chatbots        []string        `protobuf:"bytes,4,rep,name=Chatbots,proto3" json:"Chatbots,omitempty"`
+	ChatbotIsIGA    map[string]bool `protobuf:"bytes,5,rep,name=ChatbotIsIGA,proto3" json:"ChatbotIsIGA,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ChatbotIsPseudo map[string]bool `protobuf:"bytes,6,rep,name=ChatbotIsPseudo,proto3" json:"ChatbotIsPseudo,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	CipherSuite     uint32          `protobuf:"varint,7,opt,name=CipherSuite,proto3" json:"CipherSuite,omitempty"`
+	// Server-side groups
+	SenderKeyParticipants     []string         `protobuf:"bytes,8,rep,name=SenderKeyParticipants,proto3" json:"SenderKeyParticipants,omitempty"`
+	SentMessageCount          int64            `protobuf:"varint,9,opt,name=SentMessageCount,proto3" json:"SentMessageCount,omitempty"`
+	SenderKeyRotationInterval int64            `protobuf:"varint,10,opt,name=SenderKeyRotationInterval,proto3" json:"SenderKeyRotationInterval,omitempty"`
+	TreeKEMState              []byte           `protobuf:"bytes,11,opt,name=TreeKEMState,proto3" json:"TreeKEMState,omitempty"`
+	TreeKEMIndices            map[string]int64 `protobuf:"bytes,12,rep,name=TreeKEMIndices,proto3" json:"TreeKEMIndices,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	MultiTreeKEM              []byte           `protobuf:"bytes,13,opt,name=MultiTreeKEM,proto3" json:"MultiTreeKEM,omitempty"`
+	MultiTreeKEMExternal      []byte           `protobuf:"bytes,14,opt,name=MultiTreeKEMExternal,proto3" json:"MultiTreeKEMExternal,omitempty"`
+	// MLS groups
+	MlsStatePublic       []byte            `protobuf:"bytes,15,opt,name=MlsStatePublic,proto3" json:"MlsStatePublic,omitempty"`
+	MlsStateSecrets      []byte            `protobuf:"bytes,16,opt,name=MlsStateSecrets,proto3" json:"MlsStateSecrets,omitempty"`
+	MemberToLeafIndex    map[string]uint32 `protobuf:"bytes,17,rep,name=MemberToLeafIndex,proto3" json:"MemberToLeafIndex,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	MlsMultiTree         []byte            `protobuf:"bytes,18,opt,name=MlsMultiTree,proto3" json:"MlsMultiTree,omitempty"`
+	MlsMultiTreeExternal []byte            `protobuf:"bytes,19,opt,name=MlsMultiTreeExternal,proto3" json:"MlsMultiTreeExternal,omitempty"`
+}
+
+func (x *StoredGroup) Reset() {
+	*x = StoredGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredGroup) ProtoMessage() {}
+
+func (x *StoredGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredGroup.ProtoReflect.Descriptor instead.
+func (*StoredGroup) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *StoredGroup) GetType() GroupType {
+	if x != nil {
+		return x.Type
+	}
+	return GroupType_CLIENT_SIDE
+}
+
+func (x *StoredGroup) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredGroup) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetChatbots() []string {
+	if x != nil {
+		return x.Chatbots
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetChatbotIsIGA() map[string]bool {
+	if x != nil {
+		return x.ChatbotIsIGA
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetChatbotIsPseudo() map[string]bool {
+	if x != nil {
+		return x.ChatbotIsPseudo
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetCipherSuite() uint32 {
+	if x != nil {
+		return x.CipherSuite
+	}
+	return 0
+}
+
+func (x *StoredGroup) GetSenderKeyParticipants() []string {
+	if x != nil {
+		return x.SenderKeyParticipants
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetSentMessageCount() int64 {
+	if x != nil {
+		return x.SentMessageCount
+	}
+	return 0
+}
+
+func (x *StoredGroup) GetSenderKeyRotationInterval() int64 {
+	if x != nil {
+		return x.SenderKeyRotationInterval
+	}
+	return 0
+}
+
+func (x *StoredGroup) GetTreeKEMState() []byte {
+	if x != nil {
+		return x.TreeKEMState
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetTreeKEMIndices() map[string]int64 {
+	if x != nil {
+		return x.TreeKEMIndices
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMultiTreeKEM() []byte {
+	if x != nil {
+		return x.MultiTreeKEM
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMultiTreeKEMExternal() []byte {
+	if x != nil {
+		return x.MultiTreeKEMExternal
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMlsStatePublic() []byte {
+	if x != nil {
+		return x.MlsStatePublic
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMlsStateSecrets() []byte {
+	if x != nil {
+		return x.MlsStateSecrets
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMemberToLeafIndex() map[string]uint32 {
+	if x != nil {
+		return x.MemberToLeafIndex
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMlsMultiTree() []byte {
+	if x != nil {
+		return x.MlsMultiTree
+	}
+	return nil
+}
+
+func (x *StoredGroup) GetMlsMultiTreeExternal() []byte {
+	if x != nil {
+		return x.MlsMultiTreeExternal
+	}
+	return nil
+}
+
+type StoredPendingKeyUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID string          `protobuf:"bytes,1,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	RootID  string          `protobuf:"bytes,2,opt,name=RootID,proto3" json:"RootID,omitempty"`
+	Epoch   uint64          `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	Message *MessageWrapper `protobuf:"bytes,4,opt,name=Message,proto3" json:"Message,omitempty"`
+}
+
+func (x *StoredPendingKeyUpdate) Reset() {
+	*x = StoredPendingKeyUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredPendingKeyUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredPendingKeyUpdate) ProtoMessage() {}
+
+func (x *StoredPendingKeyUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredPendingKeyUpdate.ProtoReflect.Descriptor instead.
+func (*StoredPendingKeyUpdate) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *StoredPendingKeyUpdate) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredPendingKeyUpdate) GetRootID() string {
+	if x != nil {
+		return x.RootID
+	}
+	return ""
+}
+
+func (x *StoredPendingKeyUpdate) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *StoredPendingKeyUpdate) GetMessage() *MessageWrapper {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// A message the client received, remembered to reject the copies of it delivered again.
+type StoredReceivedMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SenderID    string `protobuf:"bytes,1,opt,name=SenderID,proto3" json:"SenderID,omitempty"`
+	RecipientID string `protobuf:"bytes,2,opt,name=RecipientID,proto3" json:"RecipientID,omitempty"`
+	MessageID   string `protobuf:"bytes,3,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
+}
+
+func (x *StoredReceivedMessage) Reset() {
+	*x = StoredReceivedMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[81]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredReceivedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredReceivedMessage) ProtoMessage() {}
+
+func (x *StoredReceivedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[81]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredReceivedMessage.ProtoReflect.Descriptor instead.
+func (*StoredReceivedMessage) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *StoredReceivedMessage) GetSenderID() string {
+	if x != nil {
+		return x.SenderID
+	}
+	return ""
+}
+
+func (x *StoredReceivedMessage) GetRecipientID() string {
+	if x != nil {
+		return x.RecipientID
+	}
+	return ""
+}
+
+func (x *StoredReceivedMessage) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
+type StoredClient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version           uint32                    `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	User              *StoredUser               `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	Sessions          []string                  `protobuf:"bytes,3,rep,name=Sessions,proto3" json:"Sessions,omitempty"`
+	Groups            []*StoredGroup            `protobuf:"bytes,4,rep,name=Groups,proto3" json:"Groups,omitempty"`
+	PendingKeyUpdates []*StoredPendingKeyUpdate `protobuf:"bytes,5,rep,name=PendingKeyUpdates,proto3" json:"PendingKeyUpdates,omitempty"`
+	KeyUpdateSeqs     map[string]uint64         `protobuf:"bytes,6,rep,name=KeyUpdateSeqs,proto3" json:"KeyUpdateSeqs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The messages the client received last, oldest first.
+	ReceivedMessages []*StoredReceivedMessage `protobuf:"bytes,7,rep,name=ReceivedMessages,proto3" json:"ReceivedMessages,omitempty"`
+}
+
+func (x *StoredClient) Reset() {
+	*x = StoredClient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[82]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredClient) ProtoMessage() {}
+
+func (x *StoredClient) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[82]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredClient.ProtoReflect.Descriptor instead.
+func (*StoredClient) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *StoredClient) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredClient) GetUser() *StoredUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *StoredClient) GetSessions() []string {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *StoredClient) GetGroups() []*StoredGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *StoredClient) GetPendingKeyUpdates() []*StoredPendingKeyUpdate {
+	if x != nil {
+		return x.PendingKeyUpdates
+	}
+	return nil
+}
+
+func (x *StoredClient) GetKeyUpdateSeqs() map[string]uint64 {
+	if x != nil {
+		return x.KeyUpdateSeqs
+	}
+	return nil
+}
+
+func (x *StoredClient) GetReceivedMessages() []*StoredReceivedMessage {
+	if x != nil {
+		return x.ReceivedMessages
+	}
+	return nil
+}
+
+type StoredPseudoUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID           string `protobuf:"bytes,1,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	ChatbotID         string `protobuf:"bytes,2,opt,name=ChatbotID,proto3" json:"ChatbotID,omitempty"`
+	PseudoUserID      string `protobuf:"bytes,3,opt,name=PseudoUserID,proto3" json:"PseudoUserID,omitempty"`
+	SigningPublicKey  []byte `protobuf:"bytes,4,opt,name=SigningPublicKey,proto3" json:"SigningPublicKey,omitempty"`
+	SigningPrivateKey []byte `protobuf:"bytes,5,opt,name=SigningPrivateKey,proto3" json:"SigningPrivateKey,omitempty"`
+	SignSecret        []byte `protobuf:"bytes,6,opt,name=SignSecret,proto3" json:"SignSecret,omitempty"`
+}
+
+func (x *StoredPseudoUser) Reset() {
+	*x = StoredPseudoUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[83]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredPseudoUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredPseudoUser) ProtoMessage() {}
+
+func (x *StoredPseudoUser) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[83]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredPseudoUser.ProtoReflect.Descriptor instead.
+func (*StoredPseudoUser) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *StoredPseudoUser) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredPseudoUser) GetChatbotID() string {
+	if x != nil {
+		return x.ChatbotID
+	}
+	return ""
+}
+
+func (x *StoredPseudoUser) GetPseudoUserID() string {
+	if x != nil {
+		return x.PseudoUserID
+	}
+	return ""
+}
+
+func (x *StoredPseudoUser) GetSigningPublicKey() []byte {
+	if x != nil {
+		return x.SigningPublicKey
+	}
+	return nil
+}
+
+func (x *StoredPseudoUser) GetSigningPrivateKey() []byte {
+	if x != nil {
+		return x.SigningPrivateKey
+	}
+	return nil
+}
+
+func (x *StoredPseudoUser) GetSignSecret() []byte {
+	if x != nil {
+		return x.SignSecret
+	}
+	return nil
+}
+
+type StoredChatbotSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID   string          `protobuf:"bytes,1,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	ChatbotID string          `protobuf:"bytes,2,opt,name=ChatbotID,proto3" json:"ChatbotID,omitempty"`
+	Routing   *ChatbotRouting `protobuf:"bytes,3,opt,name=Routing,proto3" json:"Routing,omitempty"`
+	Scopes    *ChatbotScopes  `protobuf:"bytes,4,opt,name=Scopes,proto3" json:"Scopes,omitempty"`
+	// The member who invited the chatbot and signed its routing.
+	InviterID     string `protobuf:"bytes,5,opt,name=InviterID,proto3" json:"InviterID,omitempty"`
+	ScopesVersion uint64 `protobuf:"varint,6,opt,name=ScopesVersion,proto3" json:"ScopesVersion,omitempty"`
+}
+
+func (x *StoredChatbotSettings) Reset() {
+	*x = StoredChatbotSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[84]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredChatbotSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredChatbotSettings) ProtoMessage() {}
+
+func (x *StoredChatbotSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[84]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredChatbotSettings.ProtoReflect.Descriptor instead.
+func (*StoredChatbotSettings) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *StoredChatbotSettings) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredChatbotSettings) GetChatbotID() string {
+	if x != nil {
+		return x.ChatbotID
+	}
+	return ""
+}
+
+func (x *StoredChatbotSettings) GetRouting() *ChatbotRouting {
+	if x != nil {
+		return x.Routing
+	}
+	return nil
+}
+
+func (x *StoredChatbotSettings) GetScopes() *ChatbotScopes {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *StoredChatbotSettings) GetInviterID() string {
+	if x != nil {
+		return x.InviterID
+	}
+	return ""
+}
+
+func (x *StoredChatbotSettings) GetScopesVersion() uint64 {
+	if x != nil {
+		return x.ScopesVersion
+	}
+	return 0
+}
+
+// A group message kept in the outbox until the server accepted or rejected it. Message is sent again exactly as it was
+// prepared, and RollbackGroup is the state of the group before it was issued, which is restored if the server rejects
+// it. The message is issued again from the remaining fields if its key update lost against a concurrent one.
+type StoredOutboxEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupID             string          `protobuf:"bytes,1,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	GroupType           GroupType       `protobuf:"varint,2,opt,name=GroupType,proto3,enum=Services.GroupType" json:"GroupType,omitempty"`
+	Message             *MessageWrapper `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
+	RollbackGroup       *StoredGroup    `protobuf:"bytes,4,opt,name=RollbackGroup,proto3" json:"RollbackGroup,omitempty"`
+	Plaintext           []byte          `protobuf:"bytes,5,opt,name=Plaintext,proto3" json:"Plaintext,omitempty"`
+	MessageType         MessageType     `protobuf:"varint,6,opt,name=MessageType,proto3,enum=Services.MessageType" json:"MessageType,omitempty"`
+	ReceivingChatbotIDs []string        `protobuf:"bytes,7,rep,name=ReceivingChatbotIDs,proto3" json:"ReceivingChatbotIDs,omitempty"`
+	HideTrigger         bool            `protobuf:"varint,8,opt,name=HideTrigger,proto3" json:"HideTrigger,omitempty"`
+}
+
+func (x *StoredOutboxEntry) Reset() {
+	*x = StoredOutboxEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[85]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredOutboxEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredOutboxEntry) ProtoMessage() {}
+
+func (x *StoredOutboxEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[85]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredOutboxEntry.ProtoReflect.Descriptor instead.
+func (*StoredOutboxEntry) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *StoredOutboxEntry) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *StoredOutboxEntry) GetGroupType() GroupType {
+	if x != nil {
+		return x.GroupType
+	}
+	return GroupType_CLIENT_SIDE
+}
+
+func (x *StoredOutboxEntry) GetMessage() *MessageWrapper {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *StoredOutboxEntry) GetRollbackGroup() *StoredGroup {
+	if x != nil {
+		return x.RollbackGroup
+	}
+	return nil
+}
+
+func (x *StoredOutboxEntry) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+func (x *StoredOutboxEntry) GetMessageType() MessageType {
+	if x != nil {
+		return x.MessageType
+	}
+	return MessageType_TEXT_MESSAGE
+}
+
+func (x *StoredOutboxEntry) GetReceivingChatbotIDs() []string {
+	if x != nil {
+		return x.ReceivingChatbotIDs
+	}
+	return nil
+}
+
+func (x *StoredOutboxEntry) GetHideTrigger() bool {
+	if x != nil {
+		return x.HideTrigger
+	}
+	return false
+}
+
+type StoredClientSideUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version          uint32                   `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	Client           *StoredClient            `protobuf:"bytes,2,opt,name=Client,proto3" json:"Client,omitempty"`
+	PseudoUsers      []*StoredPseudoUser      `protobuf:"bytes,3,rep,name=PseudoUsers,proto3" json:"PseudoUsers,omitempty"`
+	ChatbotSettings  []*StoredChatbotSettings `protobuf:"bytes,4,rep,name=ChatbotSettings,proto3" json:"ChatbotSettings,omitempty"`
+	GroupHideTrigger map[string]bool          `protobuf:"bytes,5,rep,name=GroupHideTrigger,proto3" json:"GroupHideTrigger,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Outbox           []*StoredOutboxEntry     `protobuf:"bytes,6,rep,name=Outbox,proto3" json:"Outbox,omitempty"`
+}
+
+func (x *StoredClientSideUser) Reset() {
+	*x = StoredClientSideUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[86]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredClientSideUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredClientSideUser) ProtoMessage() {}
+
+func (x *StoredClientSideUser) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[86]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredClientSideUser.ProtoReflect.Descriptor instead.
+func (*StoredClientSideUser) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *StoredClientSideUser) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredClientSideUser) GetClient() *StoredClient {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *StoredClientSideUser) GetPseudoUsers() []*StoredPseudoUser {
+	if x != nil {
+		return x.PseudoUsers
+	}
+	return nil
+}
+
+func (x *StoredClientSideUser) GetChatbotSettings() []*StoredChatbotSettings {
+	if x != nil {
+		return x.ChatbotSettings
+	}
+	return nil
+}
+
+func (x *StoredClientSideUser) GetGroupHideTrigger() map[string]bool {
+	if x != nil {
+		return x.GroupHideTrigger
+	}
+	return nil
+}
+
+func (x *StoredClientSideUser) GetOutbox() []*StoredOutboxEntry {
+	if x != nil {
+		return x.Outbox
+	}
+	return nil
+}
+
+// The pseudonyms of a chatbot are stored as StoredPseudoUser without ChatbotID and signing private key.
+type StoredClientSideChatbot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version         uint32                    `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	Client          *StoredClient             `protobuf:"bytes,2,opt,name=Client,proto3" json:"Client,omitempty"`
+	Pseudonyms      []*StoredPseudoUser       `protobuf:"bytes,3,rep,name=Pseudonyms,proto3" json:"Pseudonyms,omitempty"`
+	Routing         *ChatbotRouting           `protobuf:"bytes,4,opt,name=Routing,proto3" json:"Routing,omitempty"`
+	GroupScopes     map[string]*ChatbotScopes `protobuf:"bytes,5,rep,name=GroupScopes,proto3" json:"GroupScopes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ReadyKeyUpdates []*MessageWrapper         `protobuf:"bytes,6,rep,name=ReadyKeyUpdates,proto3" json:"ReadyKeyUpdates,omitempty"`
+	RootRecoveries  map[string]string         `protobuf:"bytes,7,rep,name=RootRecoveries,proto3" json:"RootRecoveries,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StoredClientSideChatbot) Reset() {
+	*x = StoredClientSideChatbot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[87]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StoredClientSideChatbot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredClientSideChatbot) ProtoMessage() {}
+
+func (x *StoredClientSideChatbot) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[87]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredClientSideChatbot.ProtoReflect.Descriptor instead.
+func (*StoredClientSideChatbot) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *StoredClientSideChatbot) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredClientSideChatbot) GetClient() *StoredClient {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+func (x *StoredClientSideChatbot) GetPseudonyms() []*StoredPseudoUser {
+	if x != nil {
+		return x.Pseudonyms
+	}
+	return nil
+}
+
+func (x *StoredClientSideChatbot) GetRouting() *ChatbotRouting {
+	if x != nil {
+		return x.Routing
+	}
+	return nil
+}
+
+func (x *StoredClientSideChatbot) GetGroupScopes() map[string]*ChatbotScopes {
+	if x != nil {
+		return x.GroupScopes
+	}
+	return nil
+}
+
+func (x *StoredClientSideChatbot) GetReadyKeyUpdates() []*MessageWrapper {
+	if x != nil {
+		return x.ReadyKeyUpdates
+	}
+	return nil
+}
+
+func (x *StoredClientSideChatbot) GetRootRecoveries() map[string]string {
+	if x != nil {
+		return x.RootRecoveries
+	}
+	return nil
+}
+
+// A message kept in the local history of a user. GroupID is the other user for individual messages, and Timestamp is
+// in Unix milliseconds.
+type HistoryEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MessageID   string      `protobuf:"bytes,1,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
+	GroupID     string      `protobuf:"bytes,2,opt,name=GroupID,proto3" json:"GroupID,omitempty"`
+	SenderID    string      `protobuf:"bytes,3,opt,name=SenderID,proto3" json:"SenderID,omitempty"`
+	Timestamp   int64       `protobuf:"varint,4,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	MessageType MessageType `protobuf:"varint,5,opt,name=MessageType,proto3,enum=Services.MessageType" json:"MessageType,omitempty"`
+	Message     []byte      `protobuf:"bytes,6,opt,name=Message,proto3" json:"Message,omitempty"`
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[88]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[88]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *HistoryEntry) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetGroupID() string {
+	if x != nil {
+		return x.GroupID
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetSenderID() string {
+	if x != nil {
+		return x.SenderID
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetMessageType() MessageType {
+	if x != nil {
+		return x.MessageType
+	}
+	return MessageType_TEXT_MESSAGE
+}
+
+func (x *HistoryEntry) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// A backup of a user, from which the user is restored on another device. History is empty unless the user chose to
+// back up the message history.
+type UserBackup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32                `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	User    *StoredClientSideUser `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	History []*HistoryEntry       `protobuf:"bytes,3,rep,name=History,proto3" json:"History,omitempty"`
+}
+
+func (x *UserBackup) Reset() {
+	*x = UserBackup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[89]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserBackup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserBackup) ProtoMessage() {}
+
+func (x *UserBackup) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[89]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserBackup.ProtoReflect.Descriptor instead.
+func (*UserBackup) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *UserBackup) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UserBackup) GetUser() *StoredClientSideUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserBackup) GetHistory() []*HistoryEntry {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type ECKEMCipherText struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Public     []byte `protobuf:"bytes,1,opt,name=Public,proto3" json:"Public,omitempty"`
+	IV         []byte `protobuf:"bytes,2,opt,name=IV,proto3" json:"IV,omitempty"`
+	CipherText []byte `protobuf:"bytes,3,opt,name=CipherText,proto3" json:"CipherText,omitempty"`
+	// Version 0 is the legacy raw ECDH ciphertext, version 1 is HPKE base mode, in which case IV is unused.
+	Version uint32 `protobuf:"varint,4,opt,name=Version,proto3" json:"Version,omitempty"`
+}
+
+func (x *ECKEMCipherText) Reset() {
+	*x = ECKEMCipherText{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[90]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ECKEMCipherText) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ECKEMCipherText) ProtoMessage() {}
+
+func (x *ECKEMCipherText) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[90]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ECKEMCipherText.ProtoReflect.Descriptor instead.
+func (*ECKEMCipherText) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ECKEMCipherText) GetPublic() []byte {
+	if x != nil {
+		return x.Public
+	}
+	return nil
+}
+
+func (x *ECKEMCipherText) GetIV() []byte {
+	if x != nil {
+		return x.IV
+	}
+	return nil
+}
+
+func (x *ECKEMCipherText) GetCipherText() []byte {
+	if x != nil {
+		return x.CipherText
+	}
+	return nil
+}
+
+func (x *ECKEMCipherText) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ECKEMCipherTextMap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ciphertexts map[uint32]*ECKEMCipherText `protobuf:"bytes,1,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ECKEMCipherTextMap) Reset() {
+	*x = ECKEMCipherTextMap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[91]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ECKEMCipherTextMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ECKEMCipherTextMap) ProtoMessage() {}
+
+func (x *ECKEMCipherTextMap) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[91]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ECKEMCipherTextMap.ProtoReflect.Descriptor instead.
+func (*ECKEMCipherTextMap) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ECKEMCipherTextMap) GetCiphertexts() map[uint32]*ECKEMCipherText {
+	if x != nil {
+		return x.Ciphertexts
+	}
+	return nil
+}
+
+type ECKEMCipherTextStringMap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ciphertexts map[string]*ECKEMCipherText `protobuf:"bytes,1,rep,name=Ciphertexts,proto3" json:"Ciphertexts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ECKEMCipherTextStringMap) Reset() {
+	*x = ECKEMCipherTextStringMap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[92]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ECKEMCipherTextStringMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ECKEMCipherTextStringMap) ProtoMessage() {}
+
+func (x *ECKEMCipherTextStringMap) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[92]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ECKEMCipherTextStringMap.ProtoReflect.Descriptor instead.
+func (*ECKEMCipherTextStringMap) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ECKEMCipherTextStringMap) GetCiphertexts() map[string]*ECKEMCipherText {
+	if x != nil {
+		return x.Ciphertexts
+	}
+	return nil
+}
+
+type TreeKEMNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Secret      []byte `protobuf:"bytes,1,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	Public      []byte `protobuf:"bytes,2,opt,name=Public,proto3" json:"Public,omitempty"`
+	Private     []byte `protobuf:"bytes,3,opt,name=Private,proto3" json:"Private,omitempty"`
+	SignPublic  []byte `protobuf:"bytes,4,opt,name=SignPublic,proto3" json:"SignPublic,omitempty"`
+	SignPrivate []byte `protobuf:"bytes,5,opt,name=SignPrivate,proto3" json:"SignPrivate,omitempty"`
+}
+
+func (x *TreeKEMNode) Reset() {
+	*x = TreeKEMNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_services_services_proto_msgTypes[93]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TreeKEMNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeKEMNode) ProtoMessage() {}
+
+func (x *TreeKEMNode) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_services_services_proto_msgTypes[93]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeKEMNode.ProtoReflect.Descriptor instead.
+func (*TreeKEMNode) Descriptor() ([]byte, []int) {
+	return file_protos_services_services_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *TreeKEMNode) GetSecret() []byte {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *TreeKEMNode) GetPublic() []byte {
+	if x != nil {
+		return x.Public
+	}
+	return nil
+}
+
+func (x *TreeKEMNode) GetPrivate() []byte {
+	if x != nil {
+		return x.Private
+	}
+	return nil
+}
+
+func (x *TreeKEMNode) GetSignPublic() []byte {
+	if x != nil {
+		return x.SignPublic
+	}
+	return nil
+}
+
+func (x *TreeKEMNode) GetSignPrivate() []byte {
+	if x != nil {
+		return x.SignPrivate
+	}
+	return nil
+}
+
+var File_protos_services_services_proto protoreflect.FileDescriptor
+
+var file_protos_services_services_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0x61, 0x0a, 0x13, 0x55, 0x70,
 	0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65,
@@ -4610,7 +7916,7 @@ var file_protos_services_services_proto_rawDesc = []byte{
 	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
 	0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65,
 	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x87, 0x01, 0x0a, 0x11, 0x53, 0x65,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xc9, 0x01, 0x0a, 0x11, 0x53, 0x65,
 	0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x2c, 0x0a,
@@ -4619,57 +7925,84 @@ var file_protos_services_services_proto_rawDesc = []byte{
 	0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x26, 0x0a, 0x0e, 0x72,
 	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0d, 0x52, 0x0e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x49, 0x44, 0x22, 0x52, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x31, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
-	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x22, 0xc6, 0x01, 0x0a, 0x12, 0x47,
-	0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12,
-	0x2c, 0x0a, 0x11, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x75,
-	0x62, 0x6c, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x26, 0x0a,
-	0x0e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12,
-	0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x69, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69,
-	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x22, 0x6d,
-	0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12,
-	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2b, 0x0a,
-	0x0f, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x22, 0xc5, 0x01, 0x0a, 0x10, 0x47,
-	0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72,
-	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44,
-	0x73, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22,
-	0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x22, 0xf8, 0x06, 0x0a, 0x13, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d,
+	0x6e, 0x49, 0x44, 0x12, 0x40, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x5e, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x6e, 0x74, 0x69,
+	0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x6d,
+	0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x50, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x14, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x52, 0x0a, 0x12,
+	0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x31, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x49, 0x44, 0x22, 0x88, 0x02, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x2c, 0x0a, 0x11, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x11, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x40, 0x0a, 0x0e,
+	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x0e,
+	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x8b,
+	0x01, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74,
+	0x6f, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x22, 0x6d, 0x0a, 0x13,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2b, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x22, 0xe7, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12,
+	0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69,
+	0x74, 0x65, 0x22, 0xd7, 0x09, 0x0a, 0x13, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d,
 	0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72,
 	0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f,
 	0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f,
@@ -4716,70 +8049,111 @@ var file_protos_services_services_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12,
 	0x22, 0x0a, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18,
 	0x0d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d,
-	0x6d, 0x69, 0x74, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75,
-	0x62, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
-	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x54, 0x0a,
-	0x14, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12,
-	0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0xb7, 0x01, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65,
-	0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74,
-	0x6f, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74,
-	0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x6f, 0x76,
-	0x65, 0x64, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x6f,
-	0x76, 0x65, 0x64, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f,
-	0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
-	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x6c,
-	0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x54, 0x0a,
-	0x14, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12,
-	0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x89, 0x04, 0x0a, 0x14, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
-	0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69,
-	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x76, 0x69,
-	0x74, 0x65, 0x64, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x76,
-	0x69, 0x74, 0x65, 0x64, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08,
-	0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
-	0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65,
-	0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62,
-	0x12, 0x2e, 0x0a, 0x12, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x53,
-	0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x74, 0x72,
+	0x6d, 0x69, 0x74, 0x12, 0x59, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e,
+	0x64, 0x69, 0x63, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e,
+	0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x12, 0x62,
+	0x0a, 0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54,
+	0x72, 0x65, 0x65, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54, 0x72,
+	0x65, 0x65, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62,
+	0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x5b, 0x0a, 0x16, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64,
+	0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x54, 0x0a, 0x14,
+	0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22,
+	0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x82, 0x02, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f,
+	0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x6c, 0x73,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x49, 0x0a, 0x11,
+	0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x52, 0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x54, 0x0a, 0x14, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xef, 0x05,
+	0x0a, 0x14, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72,
+	0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x64, 0x49, 0x44, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x64, 0x49, 0x44,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75,
+	0x64, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75,
+	0x64, 0x6f, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f,
+	0x74, 0x50, 0x75, 0x62, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x65, 0x65,
+	0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x12, 0x2e, 0x0a, 0x12, 0x74, 0x72,
 	0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62,
-	0x12, 0x28, 0x0a, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x4c,
-	0x65, 0x61, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x12, 0x47, 0x0a, 0x11, 0x63, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74,
-	0x52, 0x11, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54,
-	0x65, 0x78, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d,
-	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11,
-	0x6d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61,
-	0x67, 0x65, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x4b,
-	0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x6d,
-	0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d,
-	0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52,
+	0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x12, 0x28, 0x0a, 0x0f, 0x63, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74,
+	0x4c, 0x65, 0x61, 0x66, 0x12, 0x47, 0x0a, 0x11, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x43,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d,
+	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x11, 0x63, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x12, 0x2c, 0x0a,
+	0x11, 0x6d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x6d, 0x6c, 0x73, 0x57, 0x65, 0x6c,
+	0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d,
+	0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72,
+	0x41, 0x64, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73,
+	0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6d, 0x6c, 0x73,
+	0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68,
+	0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x12, 0x52, 0x0a, 0x14, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x14, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x41, 0x0a, 0x0c,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x22,
 	0x55, 0x0a, 0x15, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
 	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
@@ -4802,562 +8176,1379 @@ var file_protos_services_services_proto_rawDesc = []byte{
 	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63,
 	0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73,
 	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2b, 0x0a, 0x11, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x16, 0x0a,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x53, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
-	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x07, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61,
-	0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67,
-	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x74,
-	0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x40, 0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52, 0x0e, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x10, 0x75, 0x73,
-	0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x75, 0x73, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64,
-	0x53, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x68, 0x6f, 0x75,
-	0x6c, 0x64, 0x53, 0x65, 0x6e, 0x64, 0x22, 0x85, 0x01, 0x0a, 0x16, 0x43, 0x6c, 0x69, 0x65, 0x6e,
-	0x74, 0x53, 0x69, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x37, 0x0a, 0x0b, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9c,
-	0x01, 0x0a, 0x1c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73, 0x74,
-	0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x42, 0x0a, 0x1c, 0x73, 0x65, 0x6e,
-	0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x1c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69,
-	0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0a, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x22, 0x82, 0x01,
-	0x0a, 0x1c, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x6e, 0x79, 0x6d, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x73, 0x65, 0x75,
-	0x64, 0x6f, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x70, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d,
-	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x50,
-	0x75, 0x62, 0x22, 0xa0, 0x01, 0x0a, 0x11, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x49, 0x44, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x70, 0x72, 0x65,
-	0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x47, 0x0a, 0x13,
-	0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
-	0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x54, 0x79, 0x70, 0x65, 0x22, 0x81, 0x04, 0x0a, 0x0e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64,
-	0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64,
-	0x65, 0x72, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e,
-	0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x63, 0x69, 0x70,
-	0x69, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70,
-	0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x10, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x42, 0x0a, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x49, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x68, 0x61, 0x74,
-	0x62, 0x6f, 0x74, 0x49, 0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x50, 0x72, 0x65,
-	0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x73, 0x50, 0x72,
-	0x65, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73,
-	0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73,
-	0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x52, 0x0a, 0x14, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45,
-	0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x50, 0x61, 0x63, 0x6b, 0x52, 0x14, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x5f, 0x0a, 0x14, 0x63, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61,
-	0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
-	0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x50, 0x61, 0x63, 0x6b, 0x52, 0x14, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4b, 0x65,
-	0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x6d,
-	0x6c, 0x73, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
-	0x6d, 0x6c, 0x73, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x2f, 0x0a, 0x15, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xdb, 0x01, 0x0a, 0x0d, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6d, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10,
+	0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x79, 0x50, 0x6f, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x6d, 0x61, 0x79, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x61,
+	0x79, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x6d, 0x61, 0x79, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x13, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x22, 0xea, 0x01, 0x0a, 0x1a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x06,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x41, 0x0a,
+	0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73,
+	0x22, 0x5b, 0x0a, 0x1b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2b, 0x0a,
+	0x11, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e,
 	0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0xed, 0x08, 0x0a, 0x0f, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
-	0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
-	0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61,
-	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
-	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x12, 0x4f, 0x0a, 0x0c,
-	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0xa5, 0x01, 0x0a, 0x13, 0x53,
+	0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x71, 0x12, 0x2c, 0x0a, 0x11, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x11, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69,
+	0x63, 0x74, 0x22, 0x29, 0x0a, 0x07, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x1e, 0x0a,
+	0x0a, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0a, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x73, 0x22, 0xb8, 0x01,
+	0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x22, 0xbc, 0x01, 0x0a, 0x0e, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x40, 0x0a, 0x0e, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52, 0x0e, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x10, 0x75,
+	0x73, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x75, 0x73, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x68, 0x6f, 0x75, 0x6c,
+	0x64, 0x53, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x68, 0x6f,
+	0x75, 0x6c, 0x64, 0x53, 0x65, 0x6e, 0x64, 0x22, 0x85, 0x01, 0x0a, 0x16, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x53, 0x69, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x37, 0x0a, 0x0b,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x9c, 0x01, 0x0a, 0x1c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x42, 0x0a, 0x1c, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x1c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x62, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x22, 0x2e,
+	0x0a, 0x12, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x22, 0x78,
+	0x0a, 0x13, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12,
+	0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x22, 0x9d, 0x02, 0x0a, 0x0c, 0x52, 0x6f, 0x6f,
+	0x74, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x26, 0x0a, 0x0e,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x74, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x6f, 0x6f, 0x74, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52,
+	0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x34, 0x0a, 0x15, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x15, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69,
+	0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x82, 0x01, 0x0a, 0x1c, 0x50, 0x73, 0x65,
+	0x75, 0x64, 0x6f, 0x6e, 0x79, 0x6d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x73, 0x65, 0x75, 0x64,
+	0x6f, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69,
+	0x6e, 0x67, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d,
+	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x22, 0xa0, 0x01,
+	0x0a, 0x11, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x28, 0x0a,
+	0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x47, 0x0a, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x13, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x22, 0xad, 0x05, 0x0a, 0x0e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70,
+	0x70, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12,
+	0x20, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x49,
+	0x44, 0x12, 0x2a, 0x0a, 0x10, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x65, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x42, 0x0a,
+	0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x64,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x73, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64,
+	0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64,
+	0x6f, 0x12, 0x52, 0x0a, 0x14, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x52,
+	0x14, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x5f, 0x0a, 0x14, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b,
+	0x52, 0x14, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x43, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x54, 0x72,
+	0x69, 0x67, 0x67, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x68, 0x69, 0x64,
+	0x64, 0x65, 0x6e, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x6b, 0x65,
+	0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61, 0x73, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0d, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61, 0x73, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44,
+	0x22, 0x2f, 0x0a, 0x15, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x22, 0x2d, 0x0a, 0x13, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x22, 0x54, 0x0a, 0x14, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xe6, 0x11, 0x0a, 0x0f, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49,
+	0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x12, 0x4f, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x63, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x12, 0x58, 0x0a, 0x0f, 0x63, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
 	0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68,
-	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x0c, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x12, 0x58, 0x0a,
-	0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f,
-	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65,
+	0x75, 0x64, 0x6f, 0x12, 0x4f, 0x0a, 0x13, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52,
+	0x13, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69,
+	0x74, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49,
+	0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x74,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x12, 0x55,
+	0x0a, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64,
-	0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49,
-	0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x4f, 0x0a, 0x13, 0x74, 0x72, 0x65, 0x65, 0x4b,
-	0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74,
-	0x4b, 0x65, 0x79, 0x52, 0x13, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x74, 0x72, 0x65, 0x65,
-	0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0f, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65,
-	0x61, 0x66, 0x12, 0x55, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62,
-	0x4b, 0x65, 0x79, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62,
-	0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x61, 0x0a, 0x12, 0x63, 0x68, 0x61,
-	0x74, 0x62, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x18,
-	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b,
-	0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x62, 0x0a, 0x1a,
-	0x6c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x22, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45,
-	0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e,
-	0x67, 0x4d, 0x61, 0x70, 0x52, 0x1a, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x65,
-	0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73,
-	0x12, 0x2c, 0x0a, 0x11, 0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x4d, 0x6c, 0x73,
-	0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28,
-	0x0a, 0x0f, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49,
-	0x44, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50,
-	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x1a, 0x3f, 0x0a, 0x11, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14,
-	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x1a, 0x41, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65,
-	0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x69,
-	0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x61, 0x0a, 0x12, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x62, 0x0a, 0x1a, 0x6c, 0x61, 0x73, 0x74,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70,
+	0x52, 0x1a, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x65, 0x6d, 0x52, 0x6f, 0x6f,
+	0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x11,
+	0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63,
+	0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x6c,
+	0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0f, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x52, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x55, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x74, 0x72,
+	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x12, 0x5e, 0x0a, 0x11,
+	0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54, 0x72, 0x65,
+	0x65, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x74, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54, 0x72, 0x65, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x12, 0x5b,
+	0x0a, 0x10, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b,
+	0x45, 0x4d, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x48, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x4b, 0x45, 0x4d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x63, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x12, 0x64, 0x0a, 0x13, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65,
+	0x71, 0x18, 0x16, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x65, 0x71, 0x1a, 0x3f, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x49, 0x73, 0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
 	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc0, 0x02, 0x0a, 0x0d, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x49, 0x44, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e,
-	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
-	0x74, 0x49, 0x44, 0x73, 0x12, 0x40, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55,
-	0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55,
-	0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x52, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55,
-	0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65,
-	0x72, 0x41, 0x64, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x6c, 0x73, 0x55,
-	0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64,
-	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6d, 0x6c,
-	0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x22, 0x85, 0x02,
-	0x0a, 0x0c, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x12, 0x1a,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a,
+	0x17, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x62, 0x0a, 0x14, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x34,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x59, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e,
+	0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x5b, 0x0a, 0x16, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45,
+	0x4d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x43, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x48, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x65, 0x0a, 0x18, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63,
+	0x6f, 0x70, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xc0, 0x02, 0x0a, 0x0d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64,
+	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x65, 0x64, 0x49,
+	0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x40, 0x0a, 0x0e, 0x74, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x52, 0x0e, 0x74, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6d,
+	0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d,
+	0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12,
+	0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x22, 0xd0, 0x02, 0x0a, 0x0c, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12,
+	0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x28, 0x0a,
+	0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x49, 0x0a, 0x11, 0x74, 0x72,
+	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x52, 0x11, 0x74, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x94, 0x02, 0x0a, 0x17, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0c, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x0c,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x22, 0xa6, 0x04, 0x0a,
+	0x16, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x76,
+	0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a,
+	0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
+	0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47,
+	0x41, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a,
+	0x0a, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72,
+	0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50,
+	0x75, 0x62, 0x12, 0x2e, 0x0a, 0x12, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12,
+	0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50,
+	0x75, 0x62, 0x12, 0x28, 0x0a, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69,
+	0x74, 0x4c, 0x65, 0x61, 0x66, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x63, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x12, 0x2c, 0x0a, 0x11,
+	0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63,
+	0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x6c,
+	0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0f, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x06, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53,
+	0x75, 0x69, 0x74, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x4b, 0x45, 0x4d, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x79, 0x62, 0x72,
+	0x69, 0x64, 0x4b, 0x45, 0x4d, 0x22, 0xe0, 0x04, 0x0a, 0x14, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
 	0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72,
 	0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x49,
-	0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64,
-	0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
-	0x74, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74,
-	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c,
-	0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d,
-	0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d,
-	0x69, 0x74, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x54, 0x79, 0x70, 0x65, 0x22, 0xb5, 0x03, 0x0a, 0x16, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x61, 0x64, 0x64, 0x65, 0x64, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x64,
+	0x64, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x12, 0x31, 0x0a, 0x09,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64,
+	0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64,
+	0x6f, 0x12, 0x47, 0x0a, 0x11, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x11, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6c,
+	0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x6c,
+	0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x46,
+	0x0a, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x0e, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52,
+	0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x4b, 0x45, 0x4d, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x79, 0x62, 0x72,
+	0x69, 0x64, 0x4b, 0x45, 0x4d, 0x12, 0x41, 0x0a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x22, 0x92, 0x02, 0x0a, 0x13, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c,
 	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07,
 	0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
-	0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e,
-	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x44, 0x73, 0x12, 0x31,
-	0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65,
-	0x75, 0x64, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x50, 0x73, 0x65,
-	0x75, 0x64, 0x6f, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f,
-	0x6f, 0x74, 0x50, 0x75, 0x62, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x65,
-	0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x12, 0x2e, 0x0a, 0x12, 0x74,
-	0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75,
-	0x62, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x74, 0x72, 0x65, 0x65, 0x6b, 0x65, 0x6d,
-	0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x12, 0x28, 0x0a, 0x0f, 0x63,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x69,
-	0x74, 0x4c, 0x65, 0x61, 0x66, 0x12, 0x2c, 0x0a, 0x11, 0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63,
-	0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x11, 0x4d, 0x6c, 0x73, 0x57, 0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63,
-	0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x4d, 0x6c,
-	0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x44, 0x22, 0x86, 0x03,
-	0x0a, 0x14, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64,
-	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72,
-	0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e,
-	0x61, 0x64, 0x64, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x64, 0x64, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49,
-	0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x49, 0x44, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70,
-	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x49, 0x47, 0x41, 0x12, 0x1a, 0x0a,
-	0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x08, 0x69, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x47, 0x0a, 0x11, 0x63, 0x68, 0x61,
-	0x74, 0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52,
-	0x11, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65,
-	0x78, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x6c, 0x73, 0x55, 0x73, 0x65, 0x72, 0x41,
-	0x64, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d,
-	0x69, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6d, 0x6c, 0x73, 0x41, 0x64, 0x64,
-	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x92, 0x02, 0x0a, 0x13, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x12, 0x1a,
-	0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10,
-	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44,
-	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x04,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73,
-	0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x28,
-	0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
-	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f,
-	0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9e, 0x04, 0x0a, 0x0b,
-	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x09, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x45, 0x0a, 0x0f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76,
-	0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e,
-	0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0f, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0d, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x0c,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x0c, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x12, 0x5a, 0x0a, 0x16, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x16,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49,
+	0x44, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x6c, 0x73, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x09, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9b, 0x05,
+	0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a,
+	0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x45, 0x0a, 0x0f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a,
+	0x0d, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x0d, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3c,
+	0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x0c,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x12, 0x5a, 0x0a, 0x16,
 	0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x76, 0x69,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x54, 0x0a, 0x14, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x14, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61,
-	0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x13,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f,
-	0x76, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x13, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x42,
-	0x0b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0xf0, 0x01, 0x0a,
-	0x0e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53,
-	0x69, 0x7a, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x16, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x54, 0x0a, 0x14, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x14, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x51,
+	0x0a, 0x13, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x13, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61,
+	0x6c, 0x12, 0x5d, 0x0a, 0x17, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x17, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x0b,
+	0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0xf0, 0x01, 0x0a, 0x0e,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65,
+	0x78, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78,
+	0x74, 0x73, 0x12, 0x39, 0x0a, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x23, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x4f, 0x0a,
+	0x0a, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e,
+	0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xf6,
+	0x01, 0x0a, 0x11, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3e, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x0b, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x05, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x4f, 0x0a, 0x0a, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xfc, 0x01, 0x0a, 0x11, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x3e, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78,
 	0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54,
 	0x65, 0x78, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65,
-	0x78, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72,
-	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x41, 0x64, 0x64, 0x2e, 0x4e, 0x6f, 0x64,
-	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x4f,
-	0x0a, 0x0a, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
-	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0xf6, 0x01, 0x0a, 0x11, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3e, 0x0a, 0x0b, 0x43, 0x69, 0x70,
-	0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c,
+	0x78, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x06, 0x43, 0x6f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x2e, 0x43, 0x6f, 0x70, 0x61, 0x74, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x43, 0x6f,
+	0x70, 0x61, 0x74, 0x68, 0x1a, 0x50, 0x0a, 0x0b, 0x43, 0x6f, 0x70, 0x61, 0x74, 0x68, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc8, 0x06, 0x0a, 0x14, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12,
+	0x3b, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x5e, 0x0a, 0x18,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
 	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x0b, 0x43, 0x69,
-	0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x05, 0x4e, 0x6f, 0x64,
-	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x4f, 0x0a, 0x0a, 0x4e, 0x6f, 0x64, 0x65, 0x73,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x87, 0x02, 0x0a, 0x14, 0x54, 0x72, 0x65,
-	0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63,
-	0x6b, 0x12, 0x3b, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x52, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x5e,
-	0x0a, 0x18, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x22, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45,
-	0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e,
-	0x67, 0x4d, 0x61, 0x70, 0x52, 0x18, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x12, 0x24,
-	0x0a, 0x0d, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75,
-	0x62, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x53,
-	0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x11, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b,
-	0x65, 0x79, 0x22, 0xb0, 0x01, 0x0a, 0x21, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65,
-	0x4b, 0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x3f, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x74,
-	0x62, 0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d,
-	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x0d, 0x43, 0x68, 0x61, 0x74,
-	0x62, 0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4e, 0x65, 0x77,
-	0x43, 0x62, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
-	0x4e, 0x65, 0x77, 0x43, 0x62, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x4e,
-	0x65, 0x77, 0x43, 0x62, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x4e, 0x65, 0x77, 0x43, 0x62, 0x53, 0x69, 0x67, 0x6e, 0x50,
-	0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0xc6, 0x01, 0x0a, 0x13, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45,
-	0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a,
-	0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53, 0x69, 0x7a,
-	0x65, 0x12, 0x47, 0x0a, 0x08, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54,
-	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b,
-	0x65, 0x79, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x52, 0x08, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x1a, 0x52, 0x0a, 0x0d, 0x46, 0x72,
-	0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e,
-	0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x59,
-	0x0a, 0x0f, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x56, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x49, 0x56, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x69, 0x70,
-	0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x22, 0xc0, 0x01, 0x0a, 0x12, 0x45, 0x43,
-	0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x4d, 0x61, 0x70,
-	0x12, 0x4f, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74,
-	0x4d, 0x61, 0x70, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74,
-	0x73, 0x1a, 0x59, 0x0a, 0x10, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d,
+	0x61, 0x70, 0x52, 0x18, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0d,
+	0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x4e,
+	0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x12, 0x30, 0x0a, 0x13, 0x4e, 0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x4e,
+	0x65, 0x77, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x50, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x12, 0x51, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x41, 0x43, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x61, 0x63, 0x6b, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x41, 0x43,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d,
+	0x41, 0x43, 0x73, 0x12, 0x57, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x45, 0x70,
+	0x6f, 0x63, 0x68, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4b, 0x65, 0x79,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x12, 0x75, 0x0a, 0x17,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3b, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x2e, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x17, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73,
+	0x68, 0x65, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x4d, 0x41,
+	0x43, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x40, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x45, 0x70,
+	0x6f, 0x63, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4a, 0x0a, 0x1c, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73,
 	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78,
-	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xcc, 0x01, 0x0a,
-	0x18, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74,
-	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70, 0x12, 0x55, 0x0a, 0x0b, 0x43, 0x69, 0x70,
-	0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0xee, 0x01, 0x0a, 0x21, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x3f, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
 	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d,
-	0x61, 0x70, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73,
-	0x1a, 0x59, 0x0a, 0x10, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x99, 0x01, 0x0a, 0x0b,
-	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x18, 0x0a, 0x07, 0x50,
-	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x72,
-	0x69, 0x76, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62,
-	0x6c, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x20, 0x0a, 0x0b, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x72, 0x69,
-	0x76, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x53, 0x69, 0x67, 0x6e,
-	0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x2a, 0x36, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x53,
-	0x49, 0x44, 0x45, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f,
-	0x53, 0x49, 0x44, 0x45, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x4c, 0x53, 0x10, 0x02, 0x2a,
-	0xa9, 0x01, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x10, 0x0a, 0x0c, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10,
-	0x00, 0x12, 0x23, 0x0a, 0x1f, 0x53, 0x45, 0x4e, 0x44, 0x45, 0x52, 0x5f, 0x4b, 0x45, 0x59, 0x5f,
-	0x44, 0x49, 0x53, 0x54, 0x52, 0x49, 0x42, 0x55, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x45, 0x53,
-	0x53, 0x41, 0x47, 0x45, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54,
-	0x5f, 0x53, 0x49, 0x44, 0x45, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x4d, 0x45, 0x53, 0x53,
-	0x41, 0x47, 0x45, 0x10, 0x02, 0x12, 0x22, 0x0a, 0x1e, 0x50, 0x53, 0x45, 0x55, 0x44, 0x4f, 0x4e,
-	0x59, 0x4d, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
-	0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x56, 0x41, 0x4c,
-	0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10,
-	0x04, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x4b, 0x49, 0x50, 0x10, 0x05, 0x2a, 0xa3, 0x01, 0x0a, 0x0f,
-	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x14, 0x0a, 0x10, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x49, 0x4e, 0x56, 0x49, 0x54, 0x41, 0x54,
-	0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x41,
-	0x44, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x47, 0x52, 0x4f,
-	0x55, 0x50, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18,
-	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x49, 0x4e,
-	0x56, 0x49, 0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x47, 0x52,
-	0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x41, 0x44, 0x44, 0x49,
-	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f,
-	0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x41, 0x4c, 0x10,
-	0x05, 0x32, 0xf3, 0x0c, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x4f, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65,
-	0x79, 0x12, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x4b, 0x65,
-	0x79, 0x12, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74,
-	0x63, 0x68, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68,
-	0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x61, 0x0a, 0x12, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
-	0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x72,
-	0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x11, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x22, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50,
-	0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x64, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79,
-	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x24, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50,
-	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d,
-	0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x12, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d,
-	0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x23, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x4c, 0x53,
-	0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x24, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74,
-	0x63, 0x68, 0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x07, 0x47, 0x65, 0x74,
-	0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
-	0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65,
-	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x07, 0x53,
-	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x55,
-	0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a,
-	0x0a, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53,
-	0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x12, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x43, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x19, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0c, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65,
-	0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0c, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x0d, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4e, 0x65, 0x77, 0x43,
+	0x62, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x4e,
+	0x65, 0x77, 0x43, 0x62, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x4e, 0x65,
+	0x77, 0x43, 0x62, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0f, 0x4e, 0x65, 0x77, 0x43, 0x62, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x22, 0x98, 0x02, 0x0a, 0x13, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69,
+	0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x47,
+	0x0a, 0x08, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x2e,
+	0x46, 0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x46,
+	0x72, 0x6f, 0x6e, 0x74, 0x69, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x44, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x43, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x1a, 0x52, 0x0a, 0x0d, 0x46, 0x72, 0x6f,
+	0x6e, 0x74, 0x69, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f,
+	0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x4f, 0x0a,
+	0x0f, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0xba,
+	0x02, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x3d, 0x0a, 0x05, 0x4e, 0x6f, 0x64,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45,
+	0x4d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x43,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x1a, 0x4f, 0x0a, 0x0a, 0x4e, 0x6f,
+	0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa5, 0x07, 0x0a, 0x12,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x07,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x54,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x12, 0x55, 0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x45, 0x78,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x3d, 0x0a, 0x05, 0x52,
+	0x6f, 0x6f, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x05, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x12, 0x5e, 0x0a, 0x10, 0x4c, 0x61,
+	0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x2e, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x52, 0x6f,
+	0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72,
+	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x12, 0x40, 0x0a, 0x06, 0x45, 0x70,
+	0x6f, 0x63, 0x68, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x12, 0x49, 0x0a, 0x09,
+	0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x2e, 0x48, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x48, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x43, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x1a, 0x57, 0x0a, 0x12, 0x45, 0x78, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x4f, 0x0a, 0x0a, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x5a, 0x0a, 0x15, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x4b,
+	0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x54, 0x0a, 0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x52, 0x6f, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b,
+	0x45, 0x4d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xd8, 0x02, 0x0a, 0x1a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x0b,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65,
+	0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45,
+	0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x31, 0x0a, 0x08, 0x53, 0x65, 0x6c, 0x66, 0x4e, 0x6f, 0x64,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08,
+	0x53, 0x65, 0x6c, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x52,
+	0x6f, 0x6f, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x05, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1c,
+	0x0a, 0x09, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x48, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x45, 0x4d, 0x12, 0x20, 0x0a, 0x0b,
+	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x22, 0xb4,
+	0x06, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x54, 0x72, 0x65, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x55, 0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69,
+	0x54, 0x72, 0x65, 0x65, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x6f, 0x64,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x3d, 0x0a, 0x05, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54,
+	0x72, 0x65, 0x65, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05,
+	0x52, 0x6f, 0x6f, 0x74, 0x73, 0x12, 0x55, 0x0a, 0x0d, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x65,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x6c,
+	0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x2e, 0x4c, 0x61, 0x73, 0x74, 0x54,
+	0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x4c,
+	0x61, 0x73, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x12, 0x40, 0x0a, 0x06,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4d, 0x6c,
+	0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x20,
+	0x0a, 0x0b, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x72, 0x69, 0x76, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x72, 0x69, 0x76, 0x4b, 0x65, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x1a, 0x57, 0x0a, 0x12,
+	0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4f, 0x0a, 0x0a, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x57, 0x0a, 0x12, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72,
+	0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x54, 0x0a, 0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x52, 0x6f, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xba, 0x02, 0x0a, 0x1a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x45, 0x78, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x37,
+	0x0a, 0x0b, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x54,
+	0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x31, 0x0a, 0x08, 0x53, 0x65, 0x6c, 0x66, 0x4e,
+	0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65,
+	0x52, 0x08, 0x53, 0x65, 0x6c, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x52, 0x6f,
+	0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64, 0x65, 0x52,
+	0x04, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x52, 0x6f, 0x6f, 0x74, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x52,
+	0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x12, 0x20, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69,
+	0x74, 0x65, 0x22, 0x5c, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x22, 0x73, 0x0a, 0x0f, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x12, 0x0a,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0xfe, 0x05, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x49, 0x44, 0x12, 0x2c, 0x0a, 0x11, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65,
+	0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x12, 0x2e, 0x0a, 0x12, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50,
+	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65,
+	0x12, 0x26, 0x0a, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x65, 0x4b,
+	0x65, 0x79, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x72, 0x65, 0x4b, 0x65,
+	0x79, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b,
+	0x65, 0x79, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x38, 0x0a, 0x08, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x08, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x4a, 0x0a, 0x11, 0x54, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x11, 0x54, 0x72, 0x75,
+	0x73, 0x74, 0x65, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x39,
+	0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x52, 0x0a, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x34, 0x0a, 0x15, 0x4d, 0x6c, 0x73,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b,
+	0x65, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x15, 0x4d, 0x6c, 0x73, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x24, 0x0a, 0x0d, 0x4d, 0x6c, 0x73, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x4d, 0x6c, 0x73, 0x43, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x4d, 0x6c, 0x73, 0x49, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x10, 0x4d, 0x6c, 0x73, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x12, 0x50, 0x0a, 0x0e, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x2e,
+	0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0e, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x73, 0x1a, 0x41, 0x0a, 0x13, 0x4d, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd8, 0x09, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x27, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0c, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x73, 0x12, 0x4b, 0x0a, 0x0c, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73,
+	0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x49, 0x73, 0x49, 0x47, 0x41, 0x12, 0x54, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2a, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73,
+	0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x43, 0x68, 0x61,
+	0x74, 0x62, 0x6f, 0x74, 0x49, 0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x12, 0x20, 0x0a, 0x0b,
+	0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x12, 0x34,
+	0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x15, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x53, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x3c, 0x0a, 0x19, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x19, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x52, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x22,
+	0x0a, 0x0c, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x51, 0x0a, 0x0e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x2e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x49, 0x6e,
+	0x64, 0x69, 0x63, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72,
+	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x12, 0x32, 0x0a, 0x14, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72,
+	0x65, 0x65, 0x4b, 0x45, 0x4d, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x26, 0x0a,
+	0x0e, 0x4d, 0x6c, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x4d, 0x6c, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x6c, 0x73, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f,
+	0x4d, 0x6c, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x12,
+	0x5a, 0x0a, 0x11, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x54, 0x6f, 0x4c, 0x65, 0x61, 0x66, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x2e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x54, 0x6f, 0x4c, 0x65, 0x61, 0x66, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x54, 0x6f, 0x4c, 0x65, 0x61, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x22, 0x0a, 0x0c, 0x4d,
+	0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0c, 0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x12,
+	0x32, 0x0a, 0x14, 0x4d, 0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x45,
+	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x4d,
+	0x6c, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x54, 0x72, 0x65, 0x65, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x1a, 0x3f, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x73,
+	0x49, 0x47, 0x41, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49,
+	0x73, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x54, 0x72, 0x65, 0x65,
+	0x4b, 0x45, 0x4d, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x44, 0x0a, 0x16, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x54, 0x6f, 0x4c, 0x65, 0x61, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x94, 0x01, 0x0a, 0x16, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x6f, 0x6f, 0x74, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x6f, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x14,
+	0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x32, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52,
+	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x73, 0x0a, 0x15, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x20, 0x0a,
+	0x0b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12,
+	0x1c, 0x0a, 0x09, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x22, 0xcd, 0x03,
+	0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2d,
+	0x0a, 0x06, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x06, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x4e, 0x0a,
+	0x11, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x11, 0x50, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x4f, 0x0a,
+	0x0d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x4b, 0x65, 0x79,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0d, 0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71, 0x73, 0x12, 0x4b,
+	0x0a, 0x10, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x10, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x1a, 0x40, 0x0a, 0x12, 0x4b,
+	0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x71, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe8, 0x01,
+	0x0a, 0x10, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x73,
+	0x65, 0x75, 0x64, 0x6f, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2a,
+	0x0a, 0x10, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b,
+	0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e,
+	0x67, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x53, 0x69,
+	0x67, 0x6e, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x50, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x69,
+	0x67, 0x6e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0xf8, 0x01, 0x0a, 0x15, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x64, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x12, 0x32, 0x0a, 0x07, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2f,
+	0x0a, 0x06, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x06, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x24, 0x0a,
+	0x0d, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0xfc, 0x02, 0x0a, 0x11, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f, 0x75,
+	0x74, 0x62, 0x6f, 0x78, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x12, 0x31, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65,
+	0x72, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x52, 0x6f,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0d, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x6c, 0x61, 0x69, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x50, 0x6c, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x30,
+	0x0a, 0x13, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x49, 0x44, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x49, 0x44, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x48, 0x69, 0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x69, 0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67,
+	0x65, 0x72, 0x22, 0xc5, 0x03, 0x0a, 0x14, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x53, 0x69, 0x64, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0b, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55,
+	0x73, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x73, 0x65, 0x75,
+	0x64, 0x6f, 0x55, 0x73, 0x65, 0x72, 0x52, 0x0b, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73,
+	0x65, 0x72, 0x73, 0x12, 0x49, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x0f, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x60,
+	0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x48, 0x69, 0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67,
+	0x65, 0x72, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x53, 0x69, 0x64, 0x65, 0x55, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x48, 0x69,
+	0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x48, 0x69, 0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72,
+	0x12, 0x33, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x78, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4f,
+	0x75, 0x74, 0x62, 0x6f, 0x78, 0x1a, 0x43, 0x0a, 0x15, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x48, 0x69,
+	0x64, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe8, 0x04, 0x0a, 0x17, 0x53,
+	0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x64, 0x65, 0x43,
+	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x2e, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x12, 0x3a, 0x0a, 0x0a, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x6e, 0x79, 0x6d, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x0a, 0x50, 0x73, 0x65, 0x75, 0x64, 0x6f, 0x6e, 0x79, 0x6d, 0x73, 0x12, 0x32, 0x0a, 0x07,
+	0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74,
+	0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x12, 0x54, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x64,
+	0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x63,
+	0x6f, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x42, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x79, 0x4b,
+	0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x79,
+	0x4b, 0x65, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x0e, 0x52, 0x6f,
+	0x6f, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x35, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x64, 0x65, 0x43, 0x68,
+	0x61, 0x74, 0x62, 0x6f, 0x74, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x52, 0x6f, 0x6f, 0x74, 0x52,
+	0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x57, 0x0a, 0x10, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd3, 0x01, 0x0a, 0x0c, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x12, 0x1a,
+	0x0a, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x37, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x0a,
+	0x55, 0x73, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x64, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x30, 0x0a, 0x07, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x73, 0x0a, 0x0f, 0x45, 0x43,
+	0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x56, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x02, 0x49, 0x56, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54,
+	0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x54, 0x65, 0x78, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0xc0, 0x01, 0x0a, 0x12, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54,
+	0x65, 0x78, 0x74, 0x4d, 0x61, 0x70, 0x12, 0x4f, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x4d, 0x61, 0x70, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x74, 0x65, 0x78, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x1a, 0x59, 0x0a, 0x10, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xcc, 0x01, 0x0a, 0x18, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70, 0x12,
+	0x55, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x53,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74,
+	0x65, 0x78, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x74, 0x65, 0x78, 0x74, 0x73, 0x1a, 0x59, 0x0a, 0x10, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x74, 0x65, 0x78, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x45, 0x43, 0x4b, 0x45, 0x4d, 0x43, 0x69, 0x70, 0x68,
+	0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x99, 0x01, 0x0a, 0x0b, 0x54, 0x72, 0x65, 0x65, 0x4b, 0x45, 0x4d, 0x4e, 0x6f, 0x64,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x53,
+	0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x20, 0x0a, 0x0b, 0x53,
+	0x69, 0x67, 0x6e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x2a, 0x36, 0x0a,
+	0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4c,
+	0x49, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x49, 0x44, 0x45, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53,
+	0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x53, 0x49, 0x44, 0x45, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03,
+	0x4d, 0x4c, 0x53, 0x10, 0x02, 0x2a, 0x99, 0x02, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x4d, 0x45,
+	0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x53, 0x45, 0x4e, 0x44, 0x45,
+	0x52, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x44, 0x49, 0x53, 0x54, 0x52, 0x49, 0x42, 0x55, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19,
+	0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x49, 0x44, 0x45, 0x5f, 0x47, 0x52, 0x4f, 0x55,
+	0x50, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x02, 0x12, 0x22, 0x0a, 0x1e, 0x50,
+	0x53, 0x45, 0x55, 0x44, 0x4f, 0x4e, 0x59, 0x4d, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x03, 0x12,
+	0x16, 0x0a, 0x12, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x45,
+	0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x4b, 0x49, 0x50, 0x10,
+	0x05, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x4f, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x56, 0x45,
+	0x52, 0x59, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d,
+	0x52, 0x4f, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x59, 0x10, 0x07, 0x12,
+	0x18, 0x0a, 0x14, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x52, 0x45, 0x4a, 0x4f, 0x49, 0x4e, 0x5f,
+	0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x08, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x4c,
+	0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x50, 0x54, 0x10, 0x09, 0x12,
+	0x10, 0x0a, 0x0c, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x50, 0x54, 0x10,
+	0x0a, 0x2a, 0x59, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x0a, 0x0d,
+	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x00, 0x12,
+	0x16, 0x0a, 0x12, 0x49, 0x4e, 0x44, 0x49, 0x56, 0x49, 0x44, 0x55, 0x41, 0x4c, 0x5f, 0x43, 0x48,
+	0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x47, 0x41, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x53, 0x45, 0x55,
+	0x44, 0x4f, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x03, 0x2a, 0xc3, 0x01, 0x0a,
+	0x0f, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x14, 0x0a, 0x10, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x49, 0x4e, 0x56, 0x49, 0x54, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f,
+	0x41, 0x44, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x47, 0x52,
+	0x4f, 0x55, 0x50, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x1c, 0x0a,
+	0x18, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x49,
+	0x4e, 0x56, 0x49, 0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x47,
+	0x52, 0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x41, 0x44, 0x44,
+	0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x52, 0x4f, 0x55, 0x50,
+	0x5f, 0x43, 0x48, 0x41, 0x54, 0x42, 0x4f, 0x54, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x41, 0x4c,
+	0x10, 0x05, 0x12, 0x1e, 0x0a, 0x1a, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x43, 0x48, 0x41, 0x54,
+	0x42, 0x4f, 0x54, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45,
+	0x10, 0x06, 0x32, 0xaa, 0x0e, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b,
+	0x65, 0x79, 0x12, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x4b,
+	0x65, 0x79, 0x12, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63,
+	0x68, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x61, 0x0a, 0x12, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50,
+	0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x11, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x22, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
+	0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x50, 0x72, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x64, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x4c, 0x53, 0x4b, 0x65,
+	0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x24, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x12, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x23, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x4c,
+	0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x24, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x4d, 0x4c, 0x53, 0x4b, 0x65, 0x79, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x07, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x07,
+	0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0a, 0x53, 0x65, 0x74,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e,
+	0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x1c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x43, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x19,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0c, 0x49, 0x6e, 0x76, 0x69, 0x74,
 	0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0d, 0x49, 0x6e, 0x76, 0x69,
-	0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62,
-	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0d,
-	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1e, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43,
-	0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x4a, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x12, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x69, 0x74, 0x1a, 0x18,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x22, 0x00, 0x30, 0x01, 0x12, 0x48, 0x0a, 0x0b,
-	0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72,
-	0x61, 0x70, 0x70, 0x65, 0x72, 0x1a, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x11, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1f, 0x2e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x69, 0x74, 0x1a, 0x15, 0x2e, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x42, 0x11, 0x5a, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0c, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0d, 0x49, 0x6e, 0x76,
+	0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74,
+	0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a,
+	0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x12, 0x1e,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x64, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62,
+	0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x74, 0x62, 0x6f,
+	0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x43, 0x68, 0x61, 0x74, 0x62, 0x6f, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x49, 0x6e, 0x69, 0x74, 0x1a, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x48, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x18, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x1a, 0x1d, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a,
+	0x11, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x1f, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49,
+	0x6e, 0x69, 0x74, 0x1a, 0x15, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4f,
+	0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x12, 0x1d,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
+	0x11, 0x5a, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -5372,181 +9563,355 @@ func file_protos_services_services_proto_rawDescGZIP() []byte {
 	return file_protos_services_services_proto_rawDescData
 }
 
-var file_protos_services_services_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_protos_services_services_proto_msgTypes = make([]protoimpl.MessageInfo, 71)
+var file_protos_services_services_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_protos_services_services_proto_msgTypes = make([]protoimpl.MessageInfo, 136)
 var file_protos_services_services_proto_goTypes = []interface{}{
 	(GroupType)(0),                            // 0: Services.GroupType
 	(MessageType)(0),                          // 1: Services.MessageType
-	(ServerEventType)(0),                      // 2: Services.ServerEventType
-	(*UploadPreKeyRequest)(nil),               // 3: Services.UploadPreKeyRequest
-	(*UploadPreKeyResponse)(nil),              // 4: Services.UploadPreKeyResponse
-	(*FetchPreKeyRequest)(nil),                // 5: Services.FetchPreKeyRequest
-	(*FetchPreKeyResponse)(nil),               // 6: Services.FetchPreKeyResponse
-	(*UploadSignedPreKeyRequest)(nil),         // 7: Services.UploadSignedPreKeyRequest
-	(*UploadSignedPreKeyResponse)(nil),        // 8: Services.UploadSignedPreKeyResponse
-	(*FetchSignedPreKeyRequest)(nil),          // 9: Services.FetchSignedPreKeyRequest
-	(*FetchSignedPreKeyResponse)(nil),         // 10: Services.FetchSignedPreKeyResponse
-	(*FetchIdentityKeyRequest)(nil),           // 11: Services.FetchIdentityKeyRequest
-	(*FetchIdentityKeyResponse)(nil),          // 12: Services.FetchIdentityKeyResponse
-	(*UploadMLSKeyPackageRequest)(nil),        // 13: Services.UploadMLSKeyPackageRequest
-	(*UploadMLSKeyPackageResponse)(nil),       // 14: Services.UploadMLSKeyPackageResponse
-	(*FetchMLSKeyPackageRequest)(nil),         // 15: Services.FetchMLSKeyPackageRequest
-	(*FetchMLSKeyPackageResponse)(nil),        // 16: Services.FetchMLSKeyPackageResponse
-	(*SetUserRequest)(nil),                    // 17: Services.SetUserRequest
-	(*SetUserResponse)(nil),                   // 18: Services.SetUserResponse
-	(*GetUserRequest)(nil),                    // 19: Services.GetUserRequest
-	(*GetUserResponse)(nil),                   // 20: Services.GetUserResponse
-	(*SetChatbotRequest)(nil),                 // 21: Services.SetChatbotRequest
-	(*SetChatbotResponse)(nil),                // 22: Services.SetChatbotResponse
-	(*GetChatbotRequest)(nil),                 // 23: Services.GetChatbotRequest
-	(*GetChatbotResponse)(nil),                // 24: Services.GetChatbotResponse
-	(*CreateGroupRequest)(nil),                // 25: Services.CreateGroupRequest
-	(*CreateGroupResponse)(nil),               // 26: Services.CreateGroupResponse
-	(*GetGroupRequest)(nil),                   // 27: Services.GetGroupRequest
-	(*GetGroupResponse)(nil),                  // 28: Services.GetGroupResponse
-	(*InviteMemberRequest)(nil),               // 29: Services.InviteMemberRequest
-	(*InviteMemberResponse)(nil),              // 30: Services.InviteMemberResponse
-	(*RemoveMemberRequest)(nil),               // 31: Services.RemoveMemberRequest
-	(*RemoveMemberResponse)(nil),              // 32: Services.RemoveMemberResponse
-	(*InviteChatbotRequest)(nil),              // 33: Services.InviteChatbotRequest
-	(*InviteChatbotResponse)(nil),             // 34: Services.InviteChatbotResponse
-	(*RemoveChatbotRequest)(nil),              // 35: Services.RemoveChatbotRequest
-	(*RemoveChatbotResponse)(nil),             // 36: Services.RemoveChatbotResponse
-	(*MessageStreamInit)(nil),                 // 37: Services.MessageStreamInit
-	(*SendMessageResponse)(nil),               // 38: Services.SendMessageResponse
-	(*Message)(nil),                           // 39: Services.Message
-	(*ChatbotMessage)(nil),                    // 40: Services.ChatbotMessage
-	(*ClientSideGroupMessage)(nil),            // 41: Services.ClientSideGroupMessage
-	(*SenderKeyDistributionMessage)(nil),      // 42: Services.SenderKeyDistributionMessage
-	(*PseudonymRegistrationMessage)(nil),      // 43: Services.PseudonymRegistrationMessage
-	(*ValidationMessage)(nil),                 // 44: Services.ValidationMessage
-	(*MessageWrapper)(nil),                    // 45: Services.MessageWrapper
-	(*ServerEventStreamInit)(nil),             // 46: Services.ServerEventStreamInit
-	(*GroupInvitation)(nil),                   // 47: Services.GroupInvitation
-	(*GroupAddition)(nil),                     // 48: Services.GroupAddition
-	(*GroupRemoval)(nil),                      // 49: Services.GroupRemoval
-	(*GroupChatbotInvitation)(nil),            // 50: Services.GroupChatbotInvitation
-	(*GroupChatbotAddition)(nil),              // 51: Services.GroupChatbotAddition
-	(*GroupChatbotRemoval)(nil),               // 52: Services.GroupChatbotRemoval
-	(*ServerEvent)(nil),                       // 53: Services.ServerEvent
-	(*TreeKEMUserAdd)(nil),                    // 54: Services.TreeKEMUserAdd
-	(*TreeKEMUserUpdate)(nil),                 // 55: Services.TreeKEMUserUpdate
-	(*TreeKEMKeyUpdatePack)(nil),              // 56: Services.TreeKEMKeyUpdatePack
-	(*MultiTreeKEMExternalKeyUpdatePack)(nil), // 57: Services.MultiTreeKEMExternalKeyUpdatePack
-	(*TreeKEMGroupInitKey)(nil),               // 58: Services.TreeKEMGroupInitKey
-	(*ECKEMCipherText)(nil),                   // 59: Services.ECKEMCipherText
-	(*ECKEMCipherTextMap)(nil),                // 60: Services.ECKEMCipherTextMap
-	(*ECKEMCipherTextStringMap)(nil),          // 61: Services.ECKEMCipherTextStringMap
-	(*TreeKEMNode)(nil),                       // 62: Services.TreeKEMNode
-	nil,                                       // 63: Services.InviteMemberRequest.ChatbotPubKeysEntry
-	nil,                                       // 64: Services.InviteMemberRequest.ChatbotSignPubKeysEntry
-	nil,                                       // 65: Services.GroupInvitation.ChatbotIsIGAEntry
-	nil,                                       // 66: Services.GroupInvitation.ChatbotIsPseudoEntry
-	nil,                                       // 67: Services.GroupInvitation.ChatbotPubKeysEntry
-	nil,                                       // 68: Services.GroupInvitation.ChatbotSignPubKeysEntry
-	nil,                                       // 69: Services.TreeKEMUserAdd.NodesEntry
-	nil,                                       // 70: Services.TreeKEMUserUpdate.NodesEntry
-	nil,                                       // 71: Services.TreeKEMGroupInitKey.FrontierEntry
-	nil,                                       // 72: Services.ECKEMCipherTextMap.CiphertextsEntry
-	nil,                                       // 73: Services.ECKEMCipherTextStringMap.CiphertextsEntry
+	(Channel)(0),                              // 2: Services.Channel
+	(ServerEventType)(0),                      // 3: Services.ServerEventType
+	(*UploadPreKeyRequest)(nil),               // 4: Services.UploadPreKeyRequest
+	(*UploadPreKeyResponse)(nil),              // 5: Services.UploadPreKeyResponse
+	(*FetchPreKeyRequest)(nil),                // 6: Services.FetchPreKeyRequest
+	(*FetchPreKeyResponse)(nil),               // 7: Services.FetchPreKeyResponse
+	(*UploadSignedPreKeyRequest)(nil),         // 8: Services.UploadSignedPreKeyRequest
+	(*UploadSignedPreKeyResponse)(nil),        // 9: Services.UploadSignedPreKeyResponse
+	(*FetchSignedPreKeyRequest)(nil),          // 10: Services.FetchSignedPreKeyRequest
+	(*FetchSignedPreKeyResponse)(nil),         // 11: Services.FetchSignedPreKeyResponse
+	(*FetchIdentityKeyRequest)(nil),           // 12: Services.FetchIdentityKeyRequest
+	(*FetchIdentityKeyResponse)(nil),          // 13: Services.FetchIdentityKeyResponse
+	(*UploadMLSKeyPackageRequest)(nil),        // 14: Services.UploadMLSKeyPackageRequest
+	(*UploadMLSKeyPackageResponse)(nil),       // 15: Services.UploadMLSKeyPackageResponse
+	(*FetchMLSKeyPackageRequest)(nil),         // 16: Services.FetchMLSKeyPackageRequest
+	(*FetchMLSKeyPackageResponse)(nil),        // 17: Services.FetchMLSKeyPackageResponse
+	(*SetUserRequest)(nil),                    // 18: Services.SetUserRequest
+	(*SetUserResponse)(nil),                   // 19: Services.SetUserResponse
+	(*GetUserRequest)(nil),                    // 20: Services.GetUserRequest
+	(*GetUserResponse)(nil),                   // 21: Services.GetUserResponse
+	(*SetChatbotRequest)(nil),                 // 22: Services.SetChatbotRequest
+	(*ChatbotRouting)(nil),                    // 23: Services.ChatbotRouting
+	(*SignedChatbotRouting)(nil),              // 24: Services.SignedChatbotRouting
+	(*SetChatbotResponse)(nil),                // 25: Services.SetChatbotResponse
+	(*GetChatbotRequest)(nil),                 // 26: Services.GetChatbotRequest
+	(*GetChatbotResponse)(nil),                // 27: Services.GetChatbotResponse
+	(*CreateGroupRequest)(nil),                // 28: Services.CreateGroupRequest
+	(*CreateGroupResponse)(nil),               // 29: Services.CreateGroupResponse
+	(*GetGroupRequest)(nil),                   // 30: Services.GetGroupRequest
+	(*GetGroupResponse)(nil),                  // 31: Services.GetGroupResponse
+	(*InviteMemberRequest)(nil),               // 32: Services.InviteMemberRequest
+	(*InviteMemberResponse)(nil),              // 33: Services.InviteMemberResponse
+	(*RemoveMemberRequest)(nil),               // 34: Services.RemoveMemberRequest
+	(*RemoveMemberResponse)(nil),              // 35: Services.RemoveMemberResponse
+	(*InviteChatbotRequest)(nil),              // 36: Services.InviteChatbotRequest
+	(*InviteChatbotResponse)(nil),             // 37: Services.InviteChatbotResponse
+	(*RemoveChatbotRequest)(nil),              // 38: Services.RemoveChatbotRequest
+	(*RemoveChatbotResponse)(nil),             // 39: Services.RemoveChatbotResponse
+	(*ChatbotScopes)(nil),                     // 40: Services.ChatbotScopes
+	(*SignedChatbotScopes)(nil),               // 41: Services.SignedChatbotScopes
+	(*UpdateChatbotScopesRequest)(nil),        // 42: Services.UpdateChatbotScopesRequest
+	(*UpdateChatbotScopesResponse)(nil),       // 43: Services.UpdateChatbotScopesResponse
+	(*MessageStreamInit)(nil),                 // 44: Services.MessageStreamInit
+	(*SendMessageResponse)(nil),               // 45: Services.SendMessageResponse
+	(*Receipt)(nil),                           // 46: Services.Receipt
+	(*Message)(nil),                           // 47: Services.Message
+	(*ChatbotMessage)(nil),                    // 48: Services.ChatbotMessage
+	(*ClientSideGroupMessage)(nil),            // 49: Services.ClientSideGroupMessage
+	(*SenderKeyDistributionMessage)(nil),      // 50: Services.SenderKeyDistributionMessage
+	(*GroupRejoinRequest)(nil),                // 51: Services.GroupRejoinRequest
+	(*RootRecoveryRequest)(nil),               // 52: Services.RootRecoveryRequest
+	(*RootRecovery)(nil),                      // 53: Services.RootRecovery
+	(*PseudonymRegistrationMessage)(nil),      // 54: Services.PseudonymRegistrationMessage
+	(*ValidationMessage)(nil),                 // 55: Services.ValidationMessage
+	(*MessageWrapper)(nil),                    // 56: Services.MessageWrapper
+	(*ServerEventStreamInit)(nil),             // 57: Services.ServerEventStreamInit
+	(*CloseStreamsRequest)(nil),               // 58: Services.CloseStreamsRequest
+	(*CloseStreamsResponse)(nil),              // 59: Services.CloseStreamsResponse
+	(*GroupInvitation)(nil),                   // 60: Services.GroupInvitation
+	(*GroupAddition)(nil),                     // 61: Services.GroupAddition
+	(*GroupRemoval)(nil),                      // 62: Services.GroupRemoval
+	(*GroupChatbotScopeUpdate)(nil),           // 63: Services.GroupChatbotScopeUpdate
+	(*GroupChatbotInvitation)(nil),            // 64: Services.GroupChatbotInvitation
+	(*GroupChatbotAddition)(nil),              // 65: Services.GroupChatbotAddition
+	(*GroupChatbotRemoval)(nil),               // 66: Services.GroupChatbotRemoval
+	(*ServerEvent)(nil),                       // 67: Services.ServerEvent
+	(*TreeKEMUserAdd)(nil),                    // 68: Services.TreeKEMUserAdd
+	(*TreeKEMUserUpdate)(nil),                 // 69: Services.TreeKEMUserUpdate
+	(*TreeKEMUserRemove)(nil),                 // 70: Services.TreeKEMUserRemove
+	(*TreeKEMKeyUpdatePack)(nil),              // 71: Services.TreeKEMKeyUpdatePack
+	(*MultiTreeKEMExternalKeyUpdatePack)(nil), // 72: Services.MultiTreeKEMExternalKeyUpdatePack
+	(*TreeKEMGroupInitKey)(nil),               // 73: Services.TreeKEMGroupInitKey
+	(*StoredRootEpoch)(nil),                   // 74: Services.StoredRootEpoch
+	(*StoredTreeKEMState)(nil),                // 75: Services.StoredTreeKEMState
+	(*StoredMultiTreeKEM)(nil),                // 76: Services.StoredMultiTreeKEM
+	(*StoredMultiTreeKEMExternal)(nil),        // 77: Services.StoredMultiTreeKEMExternal
+	(*StoredMlsMultiTree)(nil),                // 78: Services.StoredMlsMultiTree
+	(*StoredMlsMultiTreeExternal)(nil),        // 79: Services.StoredMlsMultiTreeExternal
+	(*StoredSignalRecord)(nil),                // 80: Services.StoredSignalRecord
+	(*StoredSenderKey)(nil),                   // 81: Services.StoredSenderKey
+	(*StoredUser)(nil),                        // 82: Services.StoredUser
+	(*StoredGroup)(nil),                       // 83: Services.StoredGroup
+	(*StoredPendingKeyUpdate)(nil),            // 84: Services.StoredPendingKeyUpdate
+	(*StoredReceivedMessage)(nil),             // 85: Services.StoredReceivedMessage
+	(*StoredClient)(nil),                      // 86: Services.StoredClient
+	(*StoredPseudoUser)(nil),                  // 87: Services.StoredPseudoUser
+	(*StoredChatbotSettings)(nil),             // 88: Services.StoredChatbotSettings
+	(*StoredOutboxEntry)(nil),                 // 89: Services.StoredOutboxEntry
+	(*StoredClientSideUser)(nil),              // 90: Services.StoredClientSideUser
+	(*StoredClientSideChatbot)(nil),           // 91: Services.StoredClientSideChatbot
+	(*HistoryEntry)(nil),                      // 92: Services.HistoryEntry
+	(*UserBackup)(nil),                        // 93: Services.UserBackup
+	(*ECKEMCipherText)(nil),                   // 94: Services.ECKEMCipherText
+	(*ECKEMCipherTextMap)(nil),                // 95: Services.ECKEMCipherTextMap
+	(*ECKEMCipherTextStringMap)(nil),          // 96: Services.ECKEMCipherTextStringMap
+	(*TreeKEMNode)(nil),                       // 97: Services.TreeKEMNode
+	nil,                                       // 98: Services.InviteMemberRequest.ChatbotPubKeysEntry
+	nil,                                       // 99: Services.InviteMemberRequest.ChatbotSignPubKeysEntry
+	nil,                                       // 100: Services.InviteMemberRequest.TreeKEMIndicesEntry
+	nil,                                       // 101: Services.InviteMemberRequest.TreeKEMPublicTreeEntry
+	nil,                                       // 102: Services.GroupInvitation.ChatbotIsIGAEntry
+	nil,                                       // 103: Services.GroupInvitation.ChatbotIsPseudoEntry
+	nil,                                       // 104: Services.GroupInvitation.ChatbotPubKeysEntry
+	nil,                                       // 105: Services.GroupInvitation.ChatbotSignPubKeysEntry
+	nil,                                       // 106: Services.GroupInvitation.ChatbotRoutingsEntry
+	nil,                                       // 107: Services.GroupInvitation.ChatbotScopesEntry
+	nil,                                       // 108: Services.GroupInvitation.TreeKEMIndicesEntry
+	nil,                                       // 109: Services.GroupInvitation.TreeKEMPublicTreeEntry
+	nil,                                       // 110: Services.GroupInvitation.ChatbotHybridKEMEntry
+	nil,                                       // 111: Services.GroupInvitation.SignedChatbotScopesEntry
+	nil,                                       // 112: Services.TreeKEMUserAdd.NodesEntry
+	nil,                                       // 113: Services.TreeKEMUserUpdate.NodesEntry
+	nil,                                       // 114: Services.TreeKEMUserRemove.CopathEntry
+	nil,                                       // 115: Services.TreeKEMKeyUpdatePack.ChatbotMACsEntry
+	nil,                                       // 116: Services.TreeKEMKeyUpdatePack.ChatbotEpochsEntry
+	nil,                                       // 117: Services.TreeKEMKeyUpdatePack.ChatbotTranscriptHashesEntry
+	nil,                                       // 118: Services.TreeKEMGroupInitKey.FrontierEntry
+	nil,                                       // 119: Services.StoredTreeKEMState.NodesEntry
+	nil,                                       // 120: Services.StoredMultiTreeKEM.ExternalNodesEntry
+	nil,                                       // 121: Services.StoredMultiTreeKEM.RootsEntry
+	nil,                                       // 122: Services.StoredMultiTreeKEM.LastTreeKEMRootsEntry
+	nil,                                       // 123: Services.StoredMultiTreeKEM.EpochsEntry
+	nil,                                       // 124: Services.StoredMultiTreeKEM.HybridKEMEntry
+	nil,                                       // 125: Services.StoredMlsMultiTree.ExternalNodesEntry
+	nil,                                       // 126: Services.StoredMlsMultiTree.RootsEntry
+	nil,                                       // 127: Services.StoredMlsMultiTree.LastTreeRootsEntry
+	nil,                                       // 128: Services.StoredMlsMultiTree.EpochsEntry
+	nil,                                       // 129: Services.StoredUser.MlsKeyPackagesEntry
+	nil,                                       // 130: Services.StoredGroup.ChatbotIsIGAEntry
+	nil,                                       // 131: Services.StoredGroup.ChatbotIsPseudoEntry
+	nil,                                       // 132: Services.StoredGroup.TreeKEMIndicesEntry
+	nil,                                       // 133: Services.StoredGroup.MemberToLeafIndexEntry
+	nil,                                       // 134: Services.StoredClient.KeyUpdateSeqsEntry
+	nil,                                       // 135: Services.StoredClientSideUser.GroupHideTriggerEntry
+	nil,                                       // 136: Services.StoredClientSideChatbot.GroupScopesEntry
+	nil,                                       // 137: Services.StoredClientSideChatbot.RootRecoveriesEntry
+	nil,                                       // 138: Services.ECKEMCipherTextMap.CiphertextsEntry
+	nil,                                       // 139: Services.ECKEMCipherTextStringMap.CiphertextsEntry
 }
 var file_protos_services_services_proto_depIdxs = []int32{
-	0,  // 0: Services.CreateGroupRequest.groupType:type_name -> Services.GroupType
-	0,  // 1: Services.GetGroupResponse.groupType:type_name -> Services.GroupType
-	58, // 2: Services.InviteMemberRequest.treeKEMGroupInitKey:type_name -> Services.TreeKEMGroupInitKey
-	54, // 3: Services.InviteMemberRequest.treeKEMUserAdd:type_name -> Services.TreeKEMUserAdd
-	63, // 4: Services.InviteMemberRequest.chatbotPubKeys:type_name -> Services.InviteMemberRequest.ChatbotPubKeysEntry
-	64, // 5: Services.InviteMemberRequest.chatbotSignPubKeys:type_name -> Services.InviteMemberRequest.ChatbotSignPubKeysEntry
-	61, // 6: Services.InviteMemberRequest.lastTreeKemRootCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
-	59, // 7: Services.InviteChatbotRequest.chatbotCipherText:type_name -> Services.ECKEMCipherText
-	1,  // 8: Services.Message.messageType:type_name -> Services.MessageType
-	45, // 9: Services.ChatbotMessage.messageWrapper:type_name -> Services.MessageWrapper
-	1,  // 10: Services.ClientSideGroupMessage.messageType:type_name -> Services.MessageType
-	1,  // 11: Services.ValidationMessage.previousMessageType:type_name -> Services.MessageType
-	40, // 12: Services.MessageWrapper.chatbotMessages:type_name -> Services.ChatbotMessage
-	56, // 13: Services.MessageWrapper.treeKEMKeyUpdatePack:type_name -> Services.TreeKEMKeyUpdatePack
-	57, // 14: Services.MessageWrapper.chatbotKeyUpdatePack:type_name -> Services.MultiTreeKEMExternalKeyUpdatePack
-	65, // 15: Services.GroupInvitation.chatbotIsIGA:type_name -> Services.GroupInvitation.ChatbotIsIGAEntry
-	66, // 16: Services.GroupInvitation.chatbotIsPseudo:type_name -> Services.GroupInvitation.ChatbotIsPseudoEntry
-	58, // 17: Services.GroupInvitation.treeKEMGroupInitKey:type_name -> Services.TreeKEMGroupInitKey
-	67, // 18: Services.GroupInvitation.chatbotPubKeys:type_name -> Services.GroupInvitation.ChatbotPubKeysEntry
-	68, // 19: Services.GroupInvitation.chatbotSignPubKeys:type_name -> Services.GroupInvitation.ChatbotSignPubKeysEntry
-	61, // 20: Services.GroupInvitation.lastTreeKemRootCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
-	0,  // 21: Services.GroupInvitation.groupType:type_name -> Services.GroupType
-	54, // 22: Services.GroupAddition.treeKEMUserAdd:type_name -> Services.TreeKEMUserAdd
-	0,  // 23: Services.GroupAddition.groupType:type_name -> Services.GroupType
-	0,  // 24: Services.GroupRemoval.groupType:type_name -> Services.GroupType
-	0,  // 25: Services.GroupChatbotInvitation.groupType:type_name -> Services.GroupType
-	0,  // 26: Services.GroupChatbotAddition.groupType:type_name -> Services.GroupType
-	59, // 27: Services.GroupChatbotAddition.chatbotCipherText:type_name -> Services.ECKEMCipherText
-	0,  // 28: Services.GroupChatbotRemoval.groupType:type_name -> Services.GroupType
-	2,  // 29: Services.ServerEvent.eventType:type_name -> Services.ServerEventType
-	47, // 30: Services.ServerEvent.groupInvitation:type_name -> Services.GroupInvitation
-	48, // 31: Services.ServerEvent.groupAddition:type_name -> Services.GroupAddition
-	49, // 32: Services.ServerEvent.groupRemoval:type_name -> Services.GroupRemoval
-	50, // 33: Services.ServerEvent.groupChatbotInvitation:type_name -> Services.GroupChatbotInvitation
-	51, // 34: Services.ServerEvent.groupChatbotAddition:type_name -> Services.GroupChatbotAddition
-	52, // 35: Services.ServerEvent.groupChatbotRemoval:type_name -> Services.GroupChatbotRemoval
-	60, // 36: Services.TreeKEMUserAdd.Ciphertexts:type_name -> Services.ECKEMCipherTextMap
-	69, // 37: Services.TreeKEMUserAdd.Nodes:type_name -> Services.TreeKEMUserAdd.NodesEntry
-	60, // 38: Services.TreeKEMUserUpdate.Ciphertexts:type_name -> Services.ECKEMCipherTextMap
-	70, // 39: Services.TreeKEMUserUpdate.Nodes:type_name -> Services.TreeKEMUserUpdate.NodesEntry
-	55, // 40: Services.TreeKEMKeyUpdatePack.UserUpdate:type_name -> Services.TreeKEMUserUpdate
-	61, // 41: Services.TreeKEMKeyUpdatePack.ChatbotUpdateCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
-	59, // 42: Services.MultiTreeKEMExternalKeyUpdatePack.ChatbotUpdate:type_name -> Services.ECKEMCipherText
-	71, // 43: Services.TreeKEMGroupInitKey.Frontier:type_name -> Services.TreeKEMGroupInitKey.FrontierEntry
-	72, // 44: Services.ECKEMCipherTextMap.Ciphertexts:type_name -> Services.ECKEMCipherTextMap.CiphertextsEntry
-	73, // 45: Services.ECKEMCipherTextStringMap.Ciphertexts:type_name -> Services.ECKEMCipherTextStringMap.CiphertextsEntry
-	62, // 46: Services.TreeKEMUserAdd.NodesEntry.value:type_name -> Services.TreeKEMNode
-	62, // 47: Services.TreeKEMUserUpdate.NodesEntry.value:type_name -> Services.TreeKEMNode
-	62, // 48: Services.TreeKEMGroupInitKey.FrontierEntry.value:type_name -> Services.TreeKEMNode
-	59, // 49: Services.ECKEMCipherTextMap.CiphertextsEntry.value:type_name -> Services.ECKEMCipherText
-	59, // 50: Services.ECKEMCipherTextStringMap.CiphertextsEntry.value:type_name -> Services.ECKEMCipherText
-	3,  // 51: Services.ChatService.UploadPreKey:input_type -> Services.UploadPreKeyRequest
-	5,  // 52: Services.ChatService.FetchPreKey:input_type -> Services.FetchPreKeyRequest
-	7,  // 53: Services.ChatService.UploadSignedPreKey:input_type -> Services.UploadSignedPreKeyRequest
-	9,  // 54: Services.ChatService.FetchSignedPreKey:input_type -> Services.FetchSignedPreKeyRequest
-	11, // 55: Services.ChatService.FetchIdentityKey:input_type -> Services.FetchIdentityKeyRequest
-	13, // 56: Services.ChatService.UploadMLSKeyPackage:input_type -> Services.UploadMLSKeyPackageRequest
-	15, // 57: Services.ChatService.FetchMLSKeyPackage:input_type -> Services.FetchMLSKeyPackageRequest
-	19, // 58: Services.ChatService.GetUser:input_type -> Services.GetUserRequest
-	17, // 59: Services.ChatService.SetUser:input_type -> Services.SetUserRequest
-	23, // 60: Services.ChatService.GetChatbot:input_type -> Services.GetChatbotRequest
-	21, // 61: Services.ChatService.SetChatbot:input_type -> Services.SetChatbotRequest
-	25, // 62: Services.ChatService.CreateGroup:input_type -> Services.CreateGroupRequest
-	27, // 63: Services.ChatService.GetGroup:input_type -> Services.GetGroupRequest
-	29, // 64: Services.ChatService.InviteMember:input_type -> Services.InviteMemberRequest
-	31, // 65: Services.ChatService.RemoveMember:input_type -> Services.RemoveMemberRequest
-	33, // 66: Services.ChatService.InviteChatbot:input_type -> Services.InviteChatbotRequest
-	35, // 67: Services.ChatService.RemoveChatbot:input_type -> Services.RemoveChatbotRequest
-	37, // 68: Services.ChatService.MessageStream:input_type -> Services.MessageStreamInit
-	45, // 69: Services.ChatService.SendMessage:input_type -> Services.MessageWrapper
-	46, // 70: Services.ChatService.ServerEventStream:input_type -> Services.ServerEventStreamInit
-	4,  // 71: Services.ChatService.UploadPreKey:output_type -> Services.UploadPreKeyResponse
-	6,  // 72: Services.ChatService.FetchPreKey:output_type -> Services.FetchPreKeyResponse
-	8,  // 73: Services.ChatService.UploadSignedPreKey:output_type -> Services.UploadSignedPreKeyResponse
-	10, // 74: Services.ChatService.FetchSignedPreKey:output_type -> Services.FetchSignedPreKeyResponse
-	12, // 75: Services.ChatService.FetchIdentityKey:output_type -> Services.FetchIdentityKeyResponse
-	14, // 76: Services.ChatService.UploadMLSKeyPackage:output_type -> Services.UploadMLSKeyPackageResponse
-	16, // 77: Services.ChatService.FetchMLSKeyPackage:output_type -> Services.FetchMLSKeyPackageResponse
-	20, // 78: Services.ChatService.GetUser:output_type -> Services.GetUserResponse
-	18, // 79: Services.ChatService.SetUser:output_type -> Services.SetUserResponse
-	24, // 80: Services.ChatService.GetChatbot:output_type -> Services.GetChatbotResponse
-	22, // 81: Services.ChatService.SetChatbot:output_type -> Services.SetChatbotResponse
-	26, // 82: Services.ChatService.CreateGroup:output_type -> Services.CreateGroupResponse
-	28, // 83: Services.ChatService.GetGroup:output_type -> Services.GetGroupResponse
-	30, // 84: Services.ChatService.InviteMember:output_type -> Services.InviteMemberResponse
-	32, // 85: Services.ChatService.RemoveMember:output_type -> Services.RemoveMemberResponse
-	34, // 86: Services.ChatService.InviteChatbot:output_type -> Services.InviteChatbotResponse
-	36, // 87: Services.ChatService.RemoveChatbot:output_type -> Services.RemoveChatbotResponse
-	45, // 88: Services.ChatService.MessageStream:output_type -> Services.MessageWrapper
-	38, // 89: Services.ChatService.SendMessage:output_type -> Services.SendMessageResponse
-	53, // 90: Services.ChatService.ServerEventStream:output_type -> Services.ServerEvent
-	71, // [71:91] is the sub-list for method output_type
-	51, // [51:71] is the sub-list for method input_type
-	51, // [51:51] is the sub-list for extension type_name
-	51, // [51:51] is the sub-list for extension extendee
-	0,  // [0:51] is the sub-list for field type_name
+	23,  // 0: Services.SetChatbotRequest.chatbotRouting:type_name -> Services.ChatbotRouting
+	23,  // 1: Services.SignedChatbotRouting.routing:type_name -> Services.ChatbotRouting
+	23,  // 2: Services.GetChatbotResponse.chatbotRouting:type_name -> Services.ChatbotRouting
+	0,   // 3: Services.CreateGroupRequest.groupType:type_name -> Services.GroupType
+	0,   // 4: Services.GetGroupResponse.groupType:type_name -> Services.GroupType
+	73,  // 5: Services.InviteMemberRequest.treeKEMGroupInitKey:type_name -> Services.TreeKEMGroupInitKey
+	68,  // 6: Services.InviteMemberRequest.treeKEMUserAdd:type_name -> Services.TreeKEMUserAdd
+	98,  // 7: Services.InviteMemberRequest.chatbotPubKeys:type_name -> Services.InviteMemberRequest.ChatbotPubKeysEntry
+	99,  // 8: Services.InviteMemberRequest.chatbotSignPubKeys:type_name -> Services.InviteMemberRequest.ChatbotSignPubKeysEntry
+	96,  // 9: Services.InviteMemberRequest.lastTreeKemRootCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
+	100, // 10: Services.InviteMemberRequest.treeKEMIndices:type_name -> Services.InviteMemberRequest.TreeKEMIndicesEntry
+	101, // 11: Services.InviteMemberRequest.treeKEMPublicTree:type_name -> Services.InviteMemberRequest.TreeKEMPublicTreeEntry
+	70,  // 12: Services.RemoveMemberRequest.treeKEMUserRemove:type_name -> Services.TreeKEMUserRemove
+	94,  // 13: Services.InviteChatbotRequest.chatbotCipherText:type_name -> Services.ECKEMCipherText
+	40,  // 14: Services.InviteChatbotRequest.scopes:type_name -> Services.ChatbotScopes
+	24,  // 15: Services.InviteChatbotRequest.signedChatbotRouting:type_name -> Services.SignedChatbotRouting
+	41,  // 16: Services.InviteChatbotRequest.signedScopes:type_name -> Services.SignedChatbotScopes
+	40,  // 17: Services.SignedChatbotScopes.scopes:type_name -> Services.ChatbotScopes
+	40,  // 18: Services.UpdateChatbotScopesRequest.scopes:type_name -> Services.ChatbotScopes
+	41,  // 19: Services.UpdateChatbotScopesRequest.signedScopes:type_name -> Services.SignedChatbotScopes
+	1,   // 20: Services.Message.messageType:type_name -> Services.MessageType
+	56,  // 21: Services.ChatbotMessage.messageWrapper:type_name -> Services.MessageWrapper
+	1,   // 22: Services.ClientSideGroupMessage.messageType:type_name -> Services.MessageType
+	0,   // 23: Services.RootRecoveryRequest.groupType:type_name -> Services.GroupType
+	0,   // 24: Services.RootRecovery.groupType:type_name -> Services.GroupType
+	1,   // 25: Services.ValidationMessage.previousMessageType:type_name -> Services.MessageType
+	48,  // 26: Services.MessageWrapper.chatbotMessages:type_name -> Services.ChatbotMessage
+	71,  // 27: Services.MessageWrapper.treeKEMKeyUpdatePack:type_name -> Services.TreeKEMKeyUpdatePack
+	72,  // 28: Services.MessageWrapper.chatbotKeyUpdatePack:type_name -> Services.MultiTreeKEMExternalKeyUpdatePack
+	102, // 29: Services.GroupInvitation.chatbotIsIGA:type_name -> Services.GroupInvitation.ChatbotIsIGAEntry
+	103, // 30: Services.GroupInvitation.chatbotIsPseudo:type_name -> Services.GroupInvitation.ChatbotIsPseudoEntry
+	73,  // 31: Services.GroupInvitation.treeKEMGroupInitKey:type_name -> Services.TreeKEMGroupInitKey
+	104, // 32: Services.GroupInvitation.chatbotPubKeys:type_name -> Services.GroupInvitation.ChatbotPubKeysEntry
+	105, // 33: Services.GroupInvitation.chatbotSignPubKeys:type_name -> Services.GroupInvitation.ChatbotSignPubKeysEntry
+	96,  // 34: Services.GroupInvitation.lastTreeKemRootCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
+	0,   // 35: Services.GroupInvitation.groupType:type_name -> Services.GroupType
+	106, // 36: Services.GroupInvitation.chatbotRoutings:type_name -> Services.GroupInvitation.ChatbotRoutingsEntry
+	107, // 37: Services.GroupInvitation.chatbotScopes:type_name -> Services.GroupInvitation.ChatbotScopesEntry
+	108, // 38: Services.GroupInvitation.treeKEMIndices:type_name -> Services.GroupInvitation.TreeKEMIndicesEntry
+	109, // 39: Services.GroupInvitation.treeKEMPublicTree:type_name -> Services.GroupInvitation.TreeKEMPublicTreeEntry
+	110, // 40: Services.GroupInvitation.chatbotHybridKEM:type_name -> Services.GroupInvitation.ChatbotHybridKEMEntry
+	111, // 41: Services.GroupInvitation.signedChatbotScopes:type_name -> Services.GroupInvitation.SignedChatbotScopesEntry
+	68,  // 42: Services.GroupAddition.treeKEMUserAdd:type_name -> Services.TreeKEMUserAdd
+	0,   // 43: Services.GroupAddition.groupType:type_name -> Services.GroupType
+	0,   // 44: Services.GroupRemoval.groupType:type_name -> Services.GroupType
+	70,  // 45: Services.GroupRemoval.treeKEMUserRemove:type_name -> Services.TreeKEMUserRemove
+	0,   // 46: Services.GroupChatbotScopeUpdate.groupType:type_name -> Services.GroupType
+	40,  // 47: Services.GroupChatbotScopeUpdate.scopes:type_name -> Services.ChatbotScopes
+	41,  // 48: Services.GroupChatbotScopeUpdate.signedScopes:type_name -> Services.SignedChatbotScopes
+	0,   // 49: Services.GroupChatbotInvitation.groupType:type_name -> Services.GroupType
+	40,  // 50: Services.GroupChatbotInvitation.scopes:type_name -> Services.ChatbotScopes
+	0,   // 51: Services.GroupChatbotAddition.groupType:type_name -> Services.GroupType
+	94,  // 52: Services.GroupChatbotAddition.chatbotCipherText:type_name -> Services.ECKEMCipherText
+	24,  // 53: Services.GroupChatbotAddition.chatbotRouting:type_name -> Services.SignedChatbotRouting
+	40,  // 54: Services.GroupChatbotAddition.scopes:type_name -> Services.ChatbotScopes
+	41,  // 55: Services.GroupChatbotAddition.signedScopes:type_name -> Services.SignedChatbotScopes
+	0,   // 56: Services.GroupChatbotRemoval.groupType:type_name -> Services.GroupType
+	3,   // 57: Services.ServerEvent.eventType:type_name -> Services.ServerEventType
+	60,  // 58: Services.ServerEvent.groupInvitation:type_name -> Services.GroupInvitation
+	61,  // 59: Services.ServerEvent.groupAddition:type_name -> Services.GroupAddition
+	62,  // 60: Services.ServerEvent.groupRemoval:type_name -> Services.GroupRemoval
+	64,  // 61: Services.ServerEvent.groupChatbotInvitation:type_name -> Services.GroupChatbotInvitation
+	65,  // 62: Services.ServerEvent.groupChatbotAddition:type_name -> Services.GroupChatbotAddition
+	66,  // 63: Services.ServerEvent.groupChatbotRemoval:type_name -> Services.GroupChatbotRemoval
+	63,  // 64: Services.ServerEvent.groupChatbotScopeUpdate:type_name -> Services.GroupChatbotScopeUpdate
+	95,  // 65: Services.TreeKEMUserAdd.Ciphertexts:type_name -> Services.ECKEMCipherTextMap
+	112, // 66: Services.TreeKEMUserAdd.Nodes:type_name -> Services.TreeKEMUserAdd.NodesEntry
+	95,  // 67: Services.TreeKEMUserUpdate.Ciphertexts:type_name -> Services.ECKEMCipherTextMap
+	113, // 68: Services.TreeKEMUserUpdate.Nodes:type_name -> Services.TreeKEMUserUpdate.NodesEntry
+	95,  // 69: Services.TreeKEMUserRemove.Ciphertexts:type_name -> Services.ECKEMCipherTextMap
+	114, // 70: Services.TreeKEMUserRemove.Copath:type_name -> Services.TreeKEMUserRemove.CopathEntry
+	69,  // 71: Services.TreeKEMKeyUpdatePack.UserUpdate:type_name -> Services.TreeKEMUserUpdate
+	96,  // 72: Services.TreeKEMKeyUpdatePack.ChatbotUpdateCiphertexts:type_name -> Services.ECKEMCipherTextStringMap
+	115, // 73: Services.TreeKEMKeyUpdatePack.ChatbotMACs:type_name -> Services.TreeKEMKeyUpdatePack.ChatbotMACsEntry
+	116, // 74: Services.TreeKEMKeyUpdatePack.ChatbotEpochs:type_name -> Services.TreeKEMKeyUpdatePack.ChatbotEpochsEntry
+	117, // 75: Services.TreeKEMKeyUpdatePack.ChatbotTranscriptHashes:type_name -> Services.TreeKEMKeyUpdatePack.ChatbotTranscriptHashesEntry
+	94,  // 76: Services.MultiTreeKEMExternalKeyUpdatePack.ChatbotUpdate:type_name -> Services.ECKEMCipherText
+	118, // 77: Services.TreeKEMGroupInitKey.Frontier:type_name -> Services.TreeKEMGroupInitKey.FrontierEntry
+	119, // 78: Services.StoredTreeKEMState.Nodes:type_name -> Services.StoredTreeKEMState.NodesEntry
+	75,  // 79: Services.StoredMultiTreeKEM.TreeKEM:type_name -> Services.StoredTreeKEMState
+	120, // 80: Services.StoredMultiTreeKEM.ExternalNodes:type_name -> Services.StoredMultiTreeKEM.ExternalNodesEntry
+	121, // 81: Services.StoredMultiTreeKEM.Roots:type_name -> Services.StoredMultiTreeKEM.RootsEntry
+	122, // 82: Services.StoredMultiTreeKEM.LastTreeKEMRoots:type_name -> Services.StoredMultiTreeKEM.LastTreeKEMRootsEntry
+	123, // 83: Services.StoredMultiTreeKEM.Epochs:type_name -> Services.StoredMultiTreeKEM.EpochsEntry
+	124, // 84: Services.StoredMultiTreeKEM.HybridKEM:type_name -> Services.StoredMultiTreeKEM.HybridKEMEntry
+	97,  // 85: Services.StoredMultiTreeKEMExternal.TreeKEMRoot:type_name -> Services.TreeKEMNode
+	97,  // 86: Services.StoredMultiTreeKEMExternal.SelfNode:type_name -> Services.TreeKEMNode
+	97,  // 87: Services.StoredMultiTreeKEMExternal.Root:type_name -> Services.TreeKEMNode
+	74,  // 88: Services.StoredMultiTreeKEMExternal.Epoch:type_name -> Services.StoredRootEpoch
+	125, // 89: Services.StoredMlsMultiTree.ExternalNodes:type_name -> Services.StoredMlsMultiTree.ExternalNodesEntry
+	126, // 90: Services.StoredMlsMultiTree.Roots:type_name -> Services.StoredMlsMultiTree.RootsEntry
+	127, // 91: Services.StoredMlsMultiTree.LastTreeRoots:type_name -> Services.StoredMlsMultiTree.LastTreeRootsEntry
+	128, // 92: Services.StoredMlsMultiTree.Epochs:type_name -> Services.StoredMlsMultiTree.EpochsEntry
+	97,  // 93: Services.StoredMlsMultiTreeExternal.TreeKEMRoot:type_name -> Services.TreeKEMNode
+	97,  // 94: Services.StoredMlsMultiTreeExternal.SelfNode:type_name -> Services.TreeKEMNode
+	97,  // 95: Services.StoredMlsMultiTreeExternal.Root:type_name -> Services.TreeKEMNode
+	74,  // 96: Services.StoredMlsMultiTreeExternal.Epoch:type_name -> Services.StoredRootEpoch
+	80,  // 97: Services.StoredUser.Sessions:type_name -> Services.StoredSignalRecord
+	80,  // 98: Services.StoredUser.TrustedIdentities:type_name -> Services.StoredSignalRecord
+	81,  // 99: Services.StoredUser.SenderKeys:type_name -> Services.StoredSenderKey
+	129, // 100: Services.StoredUser.MlsKeyPackages:type_name -> Services.StoredUser.MlsKeyPackagesEntry
+	0,   // 101: Services.StoredGroup.Type:type_name -> Services.GroupType
+	130, // 102: Services.StoredGroup.ChatbotIsIGA:type_name -> Services.StoredGroup.ChatbotIsIGAEntry
+	131, // 103: Services.StoredGroup.ChatbotIsPseudo:type_name -> Services.StoredGroup.ChatbotIsPseudoEntry
+	132, // 104: Services.StoredGroup.TreeKEMIndices:type_name -> Services.StoredGroup.TreeKEMIndicesEntry
+	133, // 105: Services.StoredGroup.MemberToLeafIndex:type_name -> Services.StoredGroup.MemberToLeafIndexEntry
+	56,  // 106: Services.StoredPendingKeyUpdate.Message:type_name -> Services.MessageWrapper
+	82,  // 107: Services.StoredClient.User:type_name -> Services.StoredUser
+	83,  // 108: Services.StoredClient.Groups:type_name -> Services.StoredGroup
+	84,  // 109: Services.StoredClient.PendingKeyUpdates:type_name -> Services.StoredPendingKeyUpdate
+	134, // 110: Services.StoredClient.KeyUpdateSeqs:type_name -> Services.StoredClient.KeyUpdateSeqsEntry
+	85,  // 111: Services.StoredClient.ReceivedMessages:type_name -> Services.StoredReceivedMessage
+	23,  // 112: Services.StoredChatbotSettings.Routing:type_name -> Services.ChatbotRouting
+	40,  // 113: Services.StoredChatbotSettings.Scopes:type_name -> Services.ChatbotScopes
+	0,   // 114: Services.StoredOutboxEntry.GroupType:type_name -> Services.GroupType
+	56,  // 115: Services.StoredOutboxEntry.Message:type_name -> Services.MessageWrapper
+	83,  // 116: Services.StoredOutboxEntry.RollbackGroup:type_name -> Services.StoredGroup
+	1,   // 117: Services.StoredOutboxEntry.MessageType:type_name -> Services.MessageType
+	86,  // 118: Services.StoredClientSideUser.Client:type_name -> Services.StoredClient
+	87,  // 119: Services.StoredClientSideUser.PseudoUsers:type_name -> Services.StoredPseudoUser
+	88,  // 120: Services.StoredClientSideUser.ChatbotSettings:type_name -> Services.StoredChatbotSettings
+	135, // 121: Services.StoredClientSideUser.GroupHideTrigger:type_name -> Services.StoredClientSideUser.GroupHideTriggerEntry
+	89,  // 122: Services.StoredClientSideUser.Outbox:type_name -> Services.StoredOutboxEntry
+	86,  // 123: Services.StoredClientSideChatbot.Client:type_name -> Services.StoredClient
+	87,  // 124: Services.StoredClientSideChatbot.Pseudonyms:type_name -> Services.StoredPseudoUser
+	23,  // 125: Services.StoredClientSideChatbot.Routing:type_name -> Services.ChatbotRouting
+	136, // 126: Services.StoredClientSideChatbot.GroupScopes:type_name -> Services.StoredClientSideChatbot.GroupScopesEntry
+	56,  // 127: Services.StoredClientSideChatbot.ReadyKeyUpdates:type_name -> Services.MessageWrapper
+	137, // 128: Services.StoredClientSideChatbot.RootRecoveries:type_name -> Services.StoredClientSideChatbot.RootRecoveriesEntry
+	1,   // 129: Services.HistoryEntry.MessageType:type_name -> Services.MessageType
+	90,  // 130: Services.UserBackup.User:type_name -> Services.StoredClientSideUser
+	92,  // 131: Services.UserBackup.History:type_name -> Services.HistoryEntry
+	138, // 132: Services.ECKEMCipherTextMap.Ciphertexts:type_name -> Services.ECKEMCipherTextMap.CiphertextsEntry
+	139, // 133: Services.ECKEMCipherTextStringMap.Ciphertexts:type_name -> Services.ECKEMCipherTextStringMap.CiphertextsEntry
+	97,  // 134: Services.InviteMemberRequest.TreeKEMPublicTreeEntry.value:type_name -> Services.TreeKEMNode
+	24,  // 135: Services.GroupInvitation.ChatbotRoutingsEntry.value:type_name -> Services.SignedChatbotRouting
+	40,  // 136: Services.GroupInvitation.ChatbotScopesEntry.value:type_name -> Services.ChatbotScopes
+	97,  // 137: Services.GroupInvitation.TreeKEMPublicTreeEntry.value:type_name -> Services.TreeKEMNode
+	41,  // 138: Services.GroupInvitation.SignedChatbotScopesEntry.value:type_name -> Services.SignedChatbotScopes
+	97,  // 139: Services.TreeKEMUserAdd.NodesEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 140: Services.TreeKEMUserUpdate.NodesEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 141: Services.TreeKEMUserRemove.CopathEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 142: Services.TreeKEMGroupInitKey.FrontierEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 143: Services.StoredTreeKEMState.NodesEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 144: Services.StoredMultiTreeKEM.ExternalNodesEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 145: Services.StoredMultiTreeKEM.RootsEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 146: Services.StoredMultiTreeKEM.LastTreeKEMRootsEntry.value:type_name -> Services.TreeKEMNode
+	74,  // 147: Services.StoredMultiTreeKEM.EpochsEntry.value:type_name -> Services.StoredRootEpoch
+	97,  // 148: Services.StoredMlsMultiTree.ExternalNodesEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 149: Services.StoredMlsMultiTree.RootsEntry.value:type_name -> Services.TreeKEMNode
+	97,  // 150: Services.StoredMlsMultiTree.LastTreeRootsEntry.value:type_name -> Services.TreeKEMNode
+	74,  // 151: Services.StoredMlsMultiTree.EpochsEntry.value:type_name -> Services.StoredRootEpoch
+	40,  // 152: Services.StoredClientSideChatbot.GroupScopesEntry.value:type_name -> Services.ChatbotScopes
+	94,  // 153: Services.ECKEMCipherTextMap.CiphertextsEntry.value:type_name -> Services.ECKEMCipherText
+	94,  // 154: Services.ECKEMCipherTextStringMap.CiphertextsEntry.value:type_name -> Services.ECKEMCipherText
+	4,   // 155: Services.ChatService.UploadPreKey:input_type -> Services.UploadPreKeyRequest
+	6,   // 156: Services.ChatService.FetchPreKey:input_type -> Services.FetchPreKeyRequest
+	8,   // 157: Services.ChatService.UploadSignedPreKey:input_type -> Services.UploadSignedPreKeyRequest
+	10,  // 158: Services.ChatService.FetchSignedPreKey:input_type -> Services.FetchSignedPreKeyRequest
+	12,  // 159: Services.ChatService.FetchIdentityKey:input_type -> Services.FetchIdentityKeyRequest
+	14,  // 160: Services.ChatService.UploadMLSKeyPackage:input_type -> Services.UploadMLSKeyPackageRequest
+	16,  // 161: Services.ChatService.FetchMLSKeyPackage:input_type -> Services.FetchMLSKeyPackageRequest
+	20,  // 162: Services.ChatService.GetUser:input_type -> Services.GetUserRequest
+	18,  // 163: Services.ChatService.SetUser:input_type -> Services.SetUserRequest
+	26,  // 164: Services.ChatService.GetChatbot:input_type -> Services.GetChatbotRequest
+	22,  // 165: Services.ChatService.SetChatbot:input_type -> Services.SetChatbotRequest
+	28,  // 166: Services.ChatService.CreateGroup:input_type -> Services.CreateGroupRequest
+	30,  // 167: Services.ChatService.GetGroup:input_type -> Services.GetGroupRequest
+	32,  // 168: Services.ChatService.InviteMember:input_type -> Services.InviteMemberRequest
+	34,  // 169: Services.ChatService.RemoveMember:input_type -> Services.RemoveMemberRequest
+	36,  // 170: Services.ChatService.InviteChatbot:input_type -> Services.InviteChatbotRequest
+	38,  // 171: Services.ChatService.RemoveChatbot:input_type -> Services.RemoveChatbotRequest
+	42,  // 172: Services.ChatService.UpdateChatbotScopes:input_type -> Services.UpdateChatbotScopesRequest
+	44,  // 173: Services.ChatService.MessageStream:input_type -> Services.MessageStreamInit
+	56,  // 174: Services.ChatService.SendMessage:input_type -> Services.MessageWrapper
+	57,  // 175: Services.ChatService.ServerEventStream:input_type -> Services.ServerEventStreamInit
+	58,  // 176: Services.ChatService.CloseStreams:input_type -> Services.CloseStreamsRequest
+	5,   // 177: Services.ChatService.UploadPreKey:output_type -> Services.UploadPreKeyResponse
+	7,   // 178: Services.ChatService.FetchPreKey:output_type -> Services.FetchPreKeyResponse
+	9,   // 179: Services.ChatService.UploadSignedPreKey:output_type -> Services.UploadSignedPreKeyResponse
+	11,  // 180: Services.ChatService.FetchSignedPreKey:output_type -> Services.FetchSignedPreKeyResponse
+	13,  // 181: Services.ChatService.FetchIdentityKey:output_type -> Services.FetchIdentityKeyResponse
+	15,  // 182: Services.ChatService.UploadMLSKeyPackage:output_type -> Services.UploadMLSKeyPackageResponse
+	17,  // 183: Services.ChatService.FetchMLSKeyPackage:output_type -> Services.FetchMLSKeyPackageResponse
+	21,  // 184: Services.ChatService.GetUser:output_type -> Services.GetUserResponse
+	19,  // 185: Services.ChatService.SetUser:output_type -> Services.SetUserResponse
+	27,  // 186: Services.ChatService.GetChatbot:output_type -> Services.GetChatbotResponse
+	25,  // 187: Services.ChatService.SetChatbot:output_type -> Services.SetChatbotResponse
+	29,  // 188: Services.ChatService.CreateGroup:output_type -> Services.CreateGroupResponse
+	31,  // 189: Services.ChatService.GetGroup:output_type -> Services.GetGroupResponse
+	33,  // 190: Services.ChatService.InviteMember:output_type -> Services.InviteMemberResponse
+	35,  // 191: Services.ChatService.RemoveMember:output_type -> Services.RemoveMemberResponse
+	37,  // 192: Services.ChatService.InviteChatbot:output_type -> Services.InviteChatbotResponse
+	39,  // 193: Services.ChatService.RemoveChatbot:output_type -> Services.RemoveChatbotResponse
+	43,  // 194: Services.ChatService.UpdateChatbotScopes:output_type -> Services.UpdateChatbotScopesResponse
+	56,  // 195: Services.ChatService.MessageStream:output_type -> Services.MessageWrapper
+	45,  // 196: Services.ChatService.SendMessage:output_type -> Services.SendMessageResponse
+	67,  // 197: Services.ChatService.ServerEventStream:output_type -> Services.ServerEvent
+	59,  // 198: Services.ChatService.CloseStreams:output_type -> Services.CloseStreamsResponse
+	177, // [177:199] is the sub-list for method output_type
+	155, // [155:177] is the sub-list for method input_type
+	155, // [155:155] is the sub-list for extension type_name
+	155, // [155:155] is the sub-list for extension extendee
+	0,   // [0:155] is the sub-list for field type_name
 }
 
 func init() { file_protos_services_services_proto_init() }
@@ -5567,8 +9932,332 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadPreKeyResponse); i {
+		file_protos_services_services_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadPreKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchPreKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchPreKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadSignedPreKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadSignedPreKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchSignedPreKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchSignedPreKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchIdentityKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchIdentityKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMLSKeyPackageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMLSKeyPackageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchMLSKeyPackageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchMLSKeyPackageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetUserResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetChatbotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatbotRouting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignedChatbotRouting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetChatbotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetChatbotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetChatbotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateGroupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGroupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteMemberRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5579,8 +10268,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchPreKeyRequest); i {
+		file_protos_services_services_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteMemberResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5591,8 +10280,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchPreKeyResponse); i {
+		file_protos_services_services_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveMemberRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5603,8 +10292,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadSignedPreKeyRequest); i {
+		file_protos_services_services_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveMemberResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5615,8 +10304,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadSignedPreKeyResponse); i {
+		file_protos_services_services_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteChatbotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5627,8 +10316,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchSignedPreKeyRequest); i {
+		file_protos_services_services_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteChatbotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5639,8 +10328,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchSignedPreKeyResponse); i {
+		file_protos_services_services_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveChatbotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5651,8 +10340,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchIdentityKeyRequest); i {
+		file_protos_services_services_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveChatbotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5663,8 +10352,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchIdentityKeyResponse); i {
+		file_protos_services_services_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatbotScopes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5675,8 +10364,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadMLSKeyPackageRequest); i {
+		file_protos_services_services_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignedChatbotScopes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5687,8 +10376,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadMLSKeyPackageResponse); i {
+		file_protos_services_services_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChatbotScopesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5699,8 +10388,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchMLSKeyPackageRequest); i {
+		file_protos_services_services_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChatbotScopesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5711,8 +10400,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetchMLSKeyPackageResponse); i {
+		file_protos_services_services_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageStreamInit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5723,8 +10412,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetUserRequest); i {
+		file_protos_services_services_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendMessageResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5735,8 +10424,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetUserResponse); i {
+		file_protos_services_services_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Receipt); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5747,8 +10436,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUserRequest); i {
+		file_protos_services_services_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5759,8 +10448,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUserResponse); i {
+		file_protos_services_services_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatbotMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5771,8 +10460,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetChatbotRequest); i {
+		file_protos_services_services_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientSideGroupMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5783,8 +10472,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetChatbotResponse); i {
+		file_protos_services_services_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SenderKeyDistributionMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5795,8 +10484,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetChatbotRequest); i {
+		file_protos_services_services_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupRejoinRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5807,8 +10496,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetChatbotResponse); i {
+		file_protos_services_services_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RootRecoveryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5819,8 +10508,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateGroupRequest); i {
+		file_protos_services_services_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RootRecovery); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5831,8 +10520,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateGroupResponse); i {
+		file_protos_services_services_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PseudonymRegistrationMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5843,8 +10532,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetGroupRequest); i {
+		file_protos_services_services_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidationMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5855,8 +10544,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetGroupResponse); i {
+		file_protos_services_services_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageWrapper); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5867,8 +10556,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteMemberRequest); i {
+		file_protos_services_services_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerEventStreamInit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5879,8 +10568,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteMemberResponse); i {
+		file_protos_services_services_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseStreamsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5891,8 +10580,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveMemberRequest); i {
+		file_protos_services_services_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseStreamsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5903,8 +10592,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveMemberResponse); i {
+		file_protos_services_services_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupInvitation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5915,8 +10604,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteChatbotRequest); i {
+		file_protos_services_services_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupAddition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5927,8 +10616,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InviteChatbotResponse); i {
+		file_protos_services_services_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupRemoval); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5939,8 +10628,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveChatbotRequest); i {
+		file_protos_services_services_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChatbotScopeUpdate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5951,8 +10640,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveChatbotResponse); i {
+		file_protos_services_services_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChatbotInvitation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5963,8 +10652,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MessageStreamInit); i {
+		file_protos_services_services_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChatbotAddition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5975,8 +10664,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendMessageResponse); i {
+		file_protos_services_services_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChatbotRemoval); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5987,8 +10676,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message); i {
+		file_protos_services_services_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5999,8 +10688,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatbotMessage); i {
+		file_protos_services_services_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TreeKEMUserAdd); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6011,8 +10700,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ClientSideGroupMessage); i {
+		file_protos_services_services_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TreeKEMUserUpdate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6023,8 +10712,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SenderKeyDistributionMessage); i {
+		file_protos_services_services_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TreeKEMUserRemove); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6035,8 +10724,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PseudonymRegistrationMessage); i {
+		file_protos_services_services_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TreeKEMKeyUpdatePack); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6047,8 +10736,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ValidationMessage); i {
+		file_protos_services_services_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiTreeKEMExternalKeyUpdatePack); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6059,8 +10748,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MessageWrapper); i {
+		file_protos_services_services_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TreeKEMGroupInitKey); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6071,8 +10760,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServerEventStreamInit); i {
+		file_protos_services_services_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredRootEpoch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6083,8 +10772,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupInvitation); i {
+		file_protos_services_services_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredTreeKEMState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6095,8 +10784,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupAddition); i {
+		file_protos_services_services_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredMultiTreeKEM); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6107,8 +10796,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupRemoval); i {
+		file_protos_services_services_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredMultiTreeKEMExternal); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6119,8 +10808,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupChatbotInvitation); i {
+		file_protos_services_services_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredMlsMultiTree); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6131,8 +10820,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupChatbotAddition); i {
+		file_protos_services_services_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredMlsMultiTreeExternal); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6143,8 +10832,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupChatbotRemoval); i {
+		file_protos_services_services_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredSignalRecord); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6155,8 +10844,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServerEvent); i {
+		file_protos_services_services_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredSenderKey); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6167,8 +10856,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TreeKEMUserAdd); i {
+		file_protos_services_services_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredUser); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6179,8 +10868,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TreeKEMUserUpdate); i {
+		file_protos_services_services_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredGroup); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6191,8 +10880,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TreeKEMKeyUpdatePack); i {
+		file_protos_services_services_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredPendingKeyUpdate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6203,8 +10892,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MultiTreeKEMExternalKeyUpdatePack); i {
+		file_protos_services_services_proto_msgTypes[81].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredReceivedMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6215,8 +10904,8 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TreeKEMGroupInitKey); i {
+		file_protos_services_services_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredClient); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6227,7 +10916,91 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+		file_protos_services_services_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredPseudoUser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredChatbotSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredOutboxEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[86].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredClientSideUser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[87].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StoredClientSideChatbot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[88].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HistoryEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[89].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserBackup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_services_services_proto_msgTypes[90].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ECKEMCipherText); i {
 			case 0:
 				return &v.state
@@ -6239,7 +11012,7 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+		file_protos_services_services_proto_msgTypes[91].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ECKEMCipherTextMap); i {
 			case 0:
 				return &v.state
@@ -6251,7 +11024,7 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+		file_protos_services_services_proto_msgTypes[92].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ECKEMCipherTextStringMap); i {
 			case 0:
 				return &v.state
@@ -6263,7 +11036,7 @@ func file_protos_services_services_proto_init() {
 				return nil
 			}
 		}
-		file_protos_services_services_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+		file_protos_services_services_proto_msgTypes[93].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TreeKEMNode); i {
 			case 0:
 				return &v.state
@@ -6276,21 +11049,22 @@ func file_protos_services_services_proto_init() {
 			}
 		}
 	}
-	file_protos_services_services_proto_msgTypes[50].OneofWrappers = []interface{}{
+	file_protos_services_services_proto_msgTypes[63].OneofWrappers = []interface{}{
 		(*ServerEvent_GroupInvitation)(nil),
 		(*ServerEvent_GroupAddition)(nil),
 		(*ServerEvent_GroupRemoval)(nil),
 		(*ServerEvent_GroupChatbotInvitation)(nil),
 		(*ServerEvent_GroupChatbotAddition)(nil),
 		(*ServerEvent_GroupChatbotRemoval)(nil),
+		(*ServerEvent_GroupChatbotScopeUpdate)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protos_services_services_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   71,
+			NumEnums:      4,
+			NumMessages:   136,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -36,9 +36,11 @@ const (
 	ChatService_RemoveMember_FullMethodName        = "/Services.ChatService/RemoveMember"
 	ChatService_InviteChatbot_FullMethodName       = "/Services.ChatService/InviteChatbot"
 	ChatService_RemoveChatbot_FullMethodName       = "/Services.ChatService/RemoveChatbot"
+	ChatService_UpdateChatbotScopes_FullMethodName = "/Services.ChatService/UpdateChatbotScopes"
 	ChatService_MessageStream_FullMethodName       = "/Services.ChatService/MessageStream"
 	ChatService_SendMessage_FullMethodName         = "/Services.ChatService/SendMessage"
 	ChatService_ServerEventStream_FullMethodName   = "/Services.ChatService/ServerEventStream"
+	ChatService_CloseStreams_FullMethodName        = "/Services.ChatService/CloseStreams"
 )
 
 // ChatServiceClient is the client API for ChatService service.
@@ -62,9 +64,11 @@ type ChatServiceClient interface {
 	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*RemoveMemberResponse, error)
 	InviteChatbot(ctx context.Context, in *InviteChatbotRequest, opts ...grpc.CallOption) (*InviteChatbotResponse, error)
 	RemoveChatbot(ctx context.Context, in *RemoveChatbotRequest, opts ...grpc.CallOption) (*RemoveChatbotResponse, error)
+	UpdateChatbotScopes(ctx context.Context, in *UpdateChatbotScopesRequest, opts ...grpc.CallOption) (*UpdateChatbotScopesResponse, error)
 	MessageStream(ctx context.Context, in *MessageStreamInit, opts ...grpc.CallOption) (ChatService_MessageStreamClient, error)
 	SendMessage(ctx context.Context, in *MessageWrapper, opts ...grpc.CallOption) (*SendMessageResponse, error)
 	ServerEventStream(ctx context.Context, in *ServerEventStreamInit, opts ...grpc.CallOption) (ChatService_ServerEventStreamClient, error)
+	CloseStreams(ctx context.Context, in *CloseStreamsRequest, opts ...grpc.CallOption) (*CloseStreamsResponse, error)
 }
 
 type chatServiceClient struct {
@@ -228,6 +232,15 @@ func (c *chatServiceClient) RemoveChatbot(ctx context.Context, in *RemoveChatbot
 	return out, nil
 }
 
+func (c *chatServiceClient) UpdateChatbotScopes(ctx context.Context, in *UpdateChatbotScopesRequest, opts ...grpc.CallOption) (*UpdateChatbotScopesResponse, error) {
+	out := new(UpdateChatbotScopesResponse)
+	err := c.cc.Invoke(ctx, ChatService_UpdateChatbotScopes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatServiceClient) MessageStream(ctx context.Context, in *MessageStreamInit, opts ...grpc.CallOption) (ChatService_MessageStreamClient, error) {
 	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[0], ChatService_MessageStream_FullMethodName, opts...)
 	if err != nil {
@@ -301,6 +314,15 @@ func (x *chatServiceServerEventStreamClient) Recv() (*ServerEvent, error) {
 	return m, nil
 }
 
+func (c *chatServiceClient) CloseStreams(ctx context.Context, in *CloseStreamsRequest, opts ...grpc.CallOption) (*CloseStreamsResponse, error) {
+	out := new(CloseStreamsResponse)
+	err := c.cc.Invoke(ctx, ChatService_CloseStreams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility
@@ -322,9 +344,11 @@ type ChatServiceServer interface {
 	RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberResponse, error)
 	InviteChatbot(context.Context, *InviteChatbotRequest) (*InviteChatbotResponse, error)
 	RemoveChatbot(context.Context, *RemoveChatbotRequest) (*RemoveChatbotResponse, error)
+	UpdateChatbotScopes(context.Context, *UpdateChatbotScopesRequest) (*UpdateChatbotScopesResponse, error)
 	MessageStream(*MessageStreamInit, ChatService_MessageStreamServer) error
 	SendMessage(context.Context, *MessageWrapper) (*SendMessageResponse, error)
 	ServerEventStream(*ServerEventStreamInit, ChatService_ServerEventStreamServer) error
+	CloseStreams(context.Context, *CloseStreamsRequest) (*CloseStreamsResponse, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
@@ -383,6 +407,9 @@ func (UnimplementedChatServiceServer) InviteChatbot(context.Context, *InviteChat
 func (UnimplementedChatServiceServer) RemoveChatbot(context.Context, *RemoveChatbotRequest) (*RemoveChatbotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveChatbot not implemented")
 }
+func (UnimplementedChatServiceServer) UpdateChatbotScopes(context.Context, *UpdateChatbotScopesRequest) (*UpdateChatbotScopesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChatbotScopes not implemented")
+}
 func (UnimplementedChatServiceServer) MessageStream(*MessageStreamInit, ChatService_MessageStreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method MessageStream not implemented")
 }
@@ -392,6 +419,9 @@ func (UnimplementedChatServiceServer) SendMessage(context.Context, *MessageWrapp
 func (UnimplementedChatServiceServer) ServerEventStream(*ServerEventStreamInit, ChatService_ServerEventStreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method ServerEventStream not implemented")
 }
+func (UnimplementedChatServiceServer) CloseStreams(context.Context, *CloseStreamsRequest) (*CloseStreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseStreams not implemented")
+}
 func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
 
 // UnsafeChatServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -711,6 +741,24 @@ func _ChatService_RemoveChatbot_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatService_UpdateChatbotScopes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateChatbotScopesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UpdateChatbotScopes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UpdateChatbotScopes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UpdateChatbotScopes(ctx, req.(*UpdateChatbotScopesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChatService_MessageStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(MessageStreamInit)
 	if err := stream.RecvMsg(m); err != nil {
@@ -771,6 +819,24 @@ func (x *chatServiceServerEventStreamServer) Send(m *ServerEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ChatService_CloseStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CloseStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CloseStreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CloseStreams(ctx, req.(*CloseStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -846,10 +912,18 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveChatbot",
 			Handler:    _ChatService_RemoveChatbot_Handler,
 		},
+		{
+			MethodName: "UpdateChatbotScopes",
+			Handler:    _ChatService_UpdateChatbotScopes_Handler,
+		},
 		{
 			MethodName: "SendMessage",
 			Handler:    _ChatService_SendMessage_Handler,
 		},
+		{
+			MethodName: "CloseStreams",
+			Handler:    _ChatService_CloseStreams_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,185 @@
+package server
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// EventRecipient is the kind of recipient a server event is delivered to.
+type EventRecipient int
+
+const (
+	// MemberRecipient is a user of the group, including a user that is being invited or removed.
+	MemberRecipient EventRecipient = iota
+	// ChatbotRecipient is a chatbot of the group that is not IGA-enabled.
+	ChatbotRecipient
+	// IGAChatbotRecipient is an IGA-enabled chatbot of the group, including pseudonymous chatbots.
+	IGAChatbotRecipient
+)
+
+// RecipientOf returns the kind of the recipient in the group. Pseudonymity always comes with IGA, so pseudonymous
+// chatbots are IGA chatbots.
+func RecipientOf(group *ServerSideGroup, recipientID string) EventRecipient {
+	if !storage.ContainChatbot(recipientID) {
+		return MemberRecipient
+	}
+	if group.GetChatbotIsIGA()[recipientID] || group.GetChatbotIsPseudo()[recipientID] {
+		return IGAChatbotRecipient
+	}
+	return ChatbotRecipient
+}
+
+// ServerEventView decides what the recipient learns of the event in the group. It returns the event as the recipient
+// should receive it, or nil if the recipient must not receive it at all. The returned event is a redacted copy
+// whenever it differs from the given one, so the same event can be passed for every recipient.
+//
+// Members learn every event of the group. Non-IGA chatbots learn membership changes only if their scopes allow it
+// (or if they are MLS members and need the commits), but always learn the remaining participants of a server-side
+// group after a removal, as they rotate their sender keys. They learn other non-IGA chatbots only in MLS groups, and
+// never learn IGA chatbots. IGA chatbots learn nothing but their own invitation, removal and scopes, without the inviter, the
+// participants, the other chatbots, or any MLS state that would reveal the group size or epoch.
+func ServerEventView(group *ServerSideGroup, event *pb.ServerEvent, recipientID string) *pb.ServerEvent {
+	recipient := RecipientOf(group, recipientID)
+
+	switch event.GetEventType() {
+	case pb.ServerEventType_GROUP_INVITATION:
+		if recipient != MemberRecipient {
+			return nil
+		}
+		return event
+
+	case pb.ServerEventType_GROUP_ADDITION:
+		if event.GetGroupAddition().GetAddedID() == recipientID {
+			// The added user receives a GROUP_INVITATION instead.
+			return nil
+		}
+		return membershipEventView(group, event, recipientID, recipient)
+
+	case pb.ServerEventType_GROUP_REMOVAL:
+		return membershipEventView(group, event, recipientID, recipient)
+
+	case pb.ServerEventType_GROUP_CHATBOT_INVITATION:
+		switch recipient {
+		case ChatbotRecipient:
+			return event
+		case IGAChatbotRecipient:
+			view := proto.Clone(event).(*pb.ServerEvent)
+			invitation := view.GetGroupChatbotInvitation()
+			invitation.SenderID = ""
+			invitation.ParticipantIDs = nil
+			invitation.MlsWelcomeMessage = nil
+			invitation.MlsKeyPackageID = 0
+			return view
+		}
+		return nil
+
+	case pb.ServerEventType_GROUP_CHATBOT_ADDITION:
+		addition := event.GetGroupChatbotAddition()
+		switch recipient {
+		case MemberRecipient:
+			return event
+		case ChatbotRecipient:
+			// Only MLS members need to process the addition of another MLS member.
+			if addition.GetAddedChatbotID() == recipientID || group.GroupType != int(pb.GroupType_MLS) ||
+				RecipientOf(group, addition.GetAddedChatbotID()) != ChatbotRecipient {
+				return nil
+			}
+			view := proto.Clone(event).(*pb.ServerEvent)
+			view.GetGroupChatbotAddition().ChatbotIDs = nonIGAChatbotIDs(group, addition.GetChatbotIDs())
+			view.GetGroupChatbotAddition().ChatbotCipherText = nil
+			return view
+		}
+		return nil
+
+	case pb.ServerEventType_GROUP_CHATBOT_REMOVAL:
+		removal := event.GetGroupChatbotRemoval()
+		if recipient == MemberRecipient {
+			return event
+		}
+		if removal.GetRemovedChatbotID() != recipientID {
+			return nil
+		}
+		view := proto.Clone(event).(*pb.ServerEvent)
+		view.GetGroupChatbotRemoval().ChatbotIDs = nil
+		if recipient == IGAChatbotRecipient {
+			view.GetGroupChatbotRemoval().SenderID = ""
+		}
+		return view
+
+	case pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE:
+		if recipient == MemberRecipient {
+			return event
+		}
+		if event.GetGroupChatbotScopeUpdate().GetChatbotID() != recipientID {
+			return nil
+		}
+		if recipient == IGAChatbotRecipient {
+			view := proto.Clone(event).(*pb.ServerEvent)
+			view.GetGroupChatbotScopeUpdate().SenderID = ""
+			return view
+		}
+		return event
+	}
+
+	return nil
+}
+
+// membershipEventView decides what the recipient learns of a GROUP_ADDITION or GROUP_REMOVAL event.
+func membershipEventView(group *ServerSideGroup, event *pb.ServerEvent, recipientID string, recipient EventRecipient) *pb.ServerEvent {
+	switch recipient {
+	case MemberRecipient:
+		return event
+	case ChatbotRecipient:
+		if chatbotReceivesMembershipEvents(group, recipientID) {
+			return event
+		}
+		// The removed member knows the sender key of the chatbot, so the chatbot learns whom to send its next one to,
+		// but not who was removed or by whom.
+		if event.GetEventType() == pb.ServerEventType_GROUP_REMOVAL && group.GroupType == int(pb.GroupType_SERVER_SIDE) {
+			removal := event.GetGroupRemoval()
+			return &pb.ServerEvent{
+				EventType: pb.ServerEventType_GROUP_REMOVAL,
+				EventData: &pb.ServerEvent_GroupRemoval{GroupRemoval: &pb.GroupRemoval{
+					GroupID:        removal.GetGroupID(),
+					GroupType:      removal.GetGroupType(),
+					ParticipantIDs: removal.GetParticipantIDs(),
+				}},
+				Timestamp: event.GetTimestamp(),
+			}
+		}
+	}
+	return nil
+}
+
+// nonIGAChatbotIDs filters the IGA chatbots out of the given chatbot IDs.
+func nonIGAChatbotIDs(group *ServerSideGroup, chatbotIDs []string) []string {
+	filtered := make([]string, 0, len(chatbotIDs))
+	for _, cid := range chatbotIDs {
+		if RecipientOf(group, cid) == ChatbotRecipient {
+			filtered = append(filtered, cid)
+		}
+	}
+	return filtered
+}
+
+// pushServerEvent pushes the event to each of the recipients, as decided by ServerEventView. The event is stamped with
+// the time it is first pushed.
+func pushServerEvent(group *ServerSideGroup, event *pb.ServerEvent, recipientIDs ...string) {
+	if event.GetTimestamp() == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+	for _, rid := range recipientIDs {
+		if view := ServerEventView(group, event, rid); view != nil {
+			storage.GetUser(rid).PushServerEventToQueue(view)
+		}
+	}
+}
+
+// pushServerEventToGroup pushes the event to the participants and chatbots of the group, and then to the extra
+// recipients that are no longer in the group, as decided by ServerEventView.
+func pushServerEventToGroup(group *ServerSideGroup, event *pb.ServerEvent, extraRecipientIDs ...string) {
+	pushServerEvent(group, event, group.GetParticipantIDs()...)
+	pushServerEvent(group, event, group.GetChatbotIDs()...)
+	pushServerEvent(group, event, extraRecipientIDs...)
+}
@@ -2,9 +2,11 @@ package server
 
 import (
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
 	"context"
 	"log"
 	"math/rand"
+	"time"
 )
 
 var storage = NewStorage()
@@ -45,7 +47,7 @@ func (s *ServiceServer) GetChatbot(ctx context.Context, in *pb.GetChatbotRequest
 		return &pb.GetChatbotResponse{ChatbotID: "", IdentityKeyPublic: nil, Success: false, ErrorMessage: "chatbotID does not exist"}, nil
 	}
 	user := storage.GetChatbot(in.GetChatbotID())
-	return &pb.GetChatbotResponse{ChatbotID: in.GetChatbotID(), IdentityKeyPublic: user.identityKey, RegistrationID: user.registrationID, Success: true, ErrorMessage: ""}, nil
+	return &pb.GetChatbotResponse{ChatbotID: in.GetChatbotID(), IdentityKeyPublic: user.identityKey, RegistrationID: user.registrationID, ChatbotRouting: user.GetChatbotRouting(), Success: true, ErrorMessage: ""}, nil
 }
 
 // SetChatbot handles the set user requests.
@@ -58,6 +60,7 @@ func (s *ServiceServer) SetChatbot(ctx context.Context, in *pb.SetChatbotRequest
 	chatbot := storage.GetChatbot(in.GetChatbotID())
 	chatbot.identityKey = in.GetIdentityKeyPublic()
 	chatbot.registrationID = in.GetRegistrationID()
+	chatbot.SetChatbotRouting(in.GetChatbotRouting())
 
 	return &pb.SetChatbotResponse{Success: true, ErrorMessage: ""}, nil
 }
@@ -161,7 +164,7 @@ func (s *ServiceServer) FetchIdentityKey(ctx context.Context, in *pb.FetchIdenti
 
 // CreateGroup handles the create group requests.
 func (s *ServiceServer) CreateGroup(ctx context.Context, in *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
-	log.Printf("Received CreateGroup: group type=%v", in.GetGroupType())
+	log.Printf("Received CreateGroup: group type=%v cipher suite=%v", in.GetGroupType(), in.GetCipherSuite())
 
 	// Randomly choose an ID that does not exist.
 	var groupID string
@@ -179,8 +182,18 @@ func (s *ServiceServer) CreateGroup(ctx context.Context, in *pb.CreateGroupReque
 		return &pb.CreateGroupResponse{GroupID: "", Success: false, ErrorMessage: "initiatorID does not exist"}, nil
 	}
 
+	// Check if the cipher suite is supported. Zero selects the default suite.
+	cipherSuite := treekem.CipherSuiteID(in.GetCipherSuite())
+	if cipherSuite == 0 {
+		cipherSuite = treekem.DefaultCipherSuite
+	}
+	if _, err := treekem.CipherSuiteByID(cipherSuite); err != nil {
+		return &pb.CreateGroupResponse{GroupID: "", Success: false, ErrorMessage: err.Error()}, nil
+	}
+
 	// Create a new group.
 	storage.AddGroup(groupID, int(in.GetGroupType().Number()))
+	storage.GetGroup(groupID).CipherSuite = uint32(cipherSuite)
 	storage.GetGroup(groupID).AddParticipantByID(in.GetInitiatorID())
 
 	return &pb.CreateGroupResponse{GroupID: groupID, Success: true, ErrorMessage: ""}, nil
@@ -190,7 +203,7 @@ func (s *ServiceServer) CreateGroup(ctx context.Context, in *pb.CreateGroupReque
 func (s *ServiceServer) GetGroup(ctx context.Context, in *pb.GetGroupRequest) (*pb.GetGroupResponse, error) {
 	log.Printf("Received GetGroup: %v", in.GetGroupID())
 	// TODO: Check if the user or chatbot is a participant of the group and is allowed to read the participant list.
-	return &pb.GetGroupResponse{GroupID: in.GetGroupID(), ParticipantIDs: storage.GetGroup(in.GetGroupID()).GetParticipantIDs(), Success: true, ErrorMessage: ""}, nil
+	return &pb.GetGroupResponse{GroupID: in.GetGroupID(), ParticipantIDs: storage.GetGroup(in.GetGroupID()).GetParticipantIDs(), CipherSuite: storage.GetGroup(in.GetGroupID()).CipherSuite, Success: true, ErrorMessage: ""}, nil
 }
 
 // InviteMember handles the invite member requests.
@@ -210,6 +223,10 @@ func (s *ServiceServer) InviteMember(ctx context.Context, in *pb.InviteMemberReq
 	// Add the participant to the group.
 	storage.GetGroup(in.GetGroupID()).AddParticipantByID(in.GetInvitedID())
 
+	// The invited member joins at the current state of the group, so the key updates before it count as handled. A
+	// member re-added after restoring a backup would otherwise be held to the key updates its old device received.
+	keyUpdateSeq := storage.GetGroup(in.GetGroupID()).ResetDeliveredKeyUpdateSeq(in.GetInvitedID())
+
 	// Create a ServerEvent GROUP_INVITATION to the participant to notify it that it is added to a group.
 	eventMsg := &pb.ServerEvent{
 		EventType: pb.ServerEventType_GROUP_INVITATION,
@@ -221,18 +238,26 @@ func (s *ServiceServer) InviteMember(ctx context.Context, in *pb.InviteMemberReq
 				ChatbotIDs:                 storage.GetGroup(in.GetGroupID()).GetChatbotIDs(),
 				ChatbotIsIGA:               storage.GetGroup(in.GetGroupID()).GetChatbotIsIGA(),
 				ChatbotIsPseudo:            storage.GetGroup(in.GetGroupID()).GetChatbotIsPseudo(),
+				ChatbotRoutings:            storage.GetGroup(in.GetGroupID()).GetChatbotRoutings(),
+				ChatbotScopes:              storage.GetGroup(in.GetGroupID()).GetChatbotScopes(),
+				SignedChatbotScopes:        storage.GetGroup(in.GetGroupID()).GetChatbotSignedScopes(),
+				ChatbotHybridKEM:           storage.GetGroup(in.GetGroupID()).GetChatbotHybridKEM(),
 				TreeKEMGroupInitKey:        in.GetTreeKEMGroupInitKey(),
 				TreeKEMInitLeaf:            in.GetTreeKEMInitLeaf(),
 				ChatbotPubKeys:             in.GetChatbotPubKeys(),
 				LastTreeKemRootCiphertexts: in.GetLastTreeKemRootCiphertexts(),
 				MlsWelcomeMessage:          in.GetMlsWelcomeMessage(),
 				MlsKeyPackageID:            in.GetMlsKeyPackageID(),
+				KeyUpdateSeq:               keyUpdateSeq,
 				GroupType:                  pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
+				TreeKEMIndices:             in.GetTreeKEMIndices(),
+				TreeKEMPublicTree:          in.GetTreeKEMPublicTree(),
+				CipherSuite:                storage.GetGroup(in.GetGroupID()).CipherSuite,
 			},
 		},
 	}
 
-	storage.GetUser(in.GetInvitedID()).PushServerEventToQueue(eventMsg)
+	pushServerEvent(storage.GetGroup(in.GetGroupID()), eventMsg, in.GetInvitedID())
 
 	// Create a ServerEvent GROUP_ADDITION to other group members to notify them that a new user is added to a group.
 	eventMsg = &pb.ServerEvent{
@@ -251,17 +276,7 @@ func (s *ServiceServer) InviteMember(ctx context.Context, in *pb.InviteMemberReq
 		},
 	}
 
-	for _, pid := range storage.GetGroup(in.GetGroupID()).GetParticipantIDs() {
-		if pid != in.GetInvitedID() {
-			storage.GetUser(pid).PushServerEventToQueue(eventMsg)
-		}
-	}
-
-	for _, cid := range storage.GetGroup(in.GetGroupID()).GetChatbotIDs() {
-		if !storage.GetGroup(in.GetGroupID()).GetChatbotIsIGA()[cid] {
-			storage.GetChatbot(cid).PushServerEventToQueue(eventMsg)
-		}
-	}
+	pushServerEventToGroup(storage.GetGroup(in.GetGroupID()), eventMsg)
 
 	return &pb.InviteMemberResponse{Success: true, ErrorMessage: ""}, nil
 }
@@ -288,26 +303,19 @@ func (s *ServiceServer) RemoveMember(ctx context.Context, in *pb.RemoveMemberReq
 		EventType: pb.ServerEventType_GROUP_REMOVAL,
 		EventData: &pb.ServerEvent_GroupRemoval{
 			GroupRemoval: &pb.GroupRemoval{
-				SenderID:        in.GetInitiatorID(),
-				RemovedID:       in.GetRemovedID(),
-				GroupID:         in.GetGroupID(),
-				ParticipantIDs:  storage.GetGroup(in.GetGroupID()).GetParticipantIDs(),
-				MlsRemove:       in.GetMlsRemove(),
-				MlsRemoveCommit: in.GetMlsRemoveCommit(),
-				GroupType:       pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
+				SenderID:          in.GetInitiatorID(),
+				RemovedID:         in.GetRemovedID(),
+				GroupID:           in.GetGroupID(),
+				ParticipantIDs:    storage.GetGroup(in.GetGroupID()).GetParticipantIDs(),
+				MlsRemove:         in.GetMlsRemove(),
+				MlsRemoveCommit:   in.GetMlsRemoveCommit(),
+				GroupType:         pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
+				TreeKEMUserRemove: in.GetTreeKEMUserRemove(),
 			},
 		},
 	}
 
-	for _, pid := range storage.GetGroup(in.GetGroupID()).GetParticipantIDs() {
-		storage.GetUser(pid).PushServerEventToQueue(eventMsg)
-	}
-	for _, cid := range storage.GetGroup(in.GetGroupID()).GetChatbotIDs() {
-		if !storage.GetGroup(in.GetGroupID()).ChatbotIsIGA[cid] {
-			storage.GetChatbot(cid).PushServerEventToQueue(eventMsg)
-		}
-	}
-	storage.GetUser(in.GetRemovedID()).PushServerEventToQueue(eventMsg)
+	pushServerEventToGroup(storage.GetGroup(in.GetGroupID()), eventMsg, in.GetRemovedID())
 
 	return &pb.RemoveMemberResponse{Success: true, ErrorMessage: ""}, nil
 }
@@ -333,24 +341,43 @@ func (s *ServiceServer) InviteChatbot(ctx context.Context, in *pb.InviteChatbotR
 		return &pb.InviteChatbotResponse{Success: false, ErrorMessage: "pseudonimity must come with IGA"}, nil
 	}
 
+	// Reject the hybrid KEM in MLS groups, whose external nodes do not support it.
+	if in.GetHybridKEM() && storage.GetGroup(in.GetGroupID()).GroupType == int(pb.GroupType_MLS) {
+		return &pb.InviteChatbotResponse{Success: false, ErrorMessage: "hybrid KEM is not supported in MLS groups"}, nil
+	}
+
 	// Add the chatbot to the group.
 	storage.GetGroup(in.GetGroupID()).AddChatbotByID(in.GetInvitedID(), in.GetIsIGA(), in.GetIsPseudo())
 
-	// Create a ServerEvent GROUP_CHATBOT_INVITATION to the participant to notify it that it is added to a group.
-	var participantIDs []string
-	if in.GetIsIGA() || in.GetIsPseudo() {
-		participantIDs = nil
-	} else {
-		participantIDs = storage.GetGroup(in.GetGroupID()).GetParticipantIDs()
+	// Record the mention names and command prefixes the inviter signed for the chatbot, so that members can route
+	// messages to it. Members check the signature, so they are relayed as they are.
+	storage.GetGroup(in.GetGroupID()).SetChatbotRouting(in.GetInvitedID(), in.GetSignedChatbotRouting())
+
+	// Record the access scopes of the chatbot, preferring those the inviter signed. A chatbot invited without scopes has
+	// full access. Only the inviter may change them later.
+	scopes := in.GetScopes()
+	if in.GetSignedScopes() != nil {
+		scopes = in.GetSignedScopes().GetScopes()
 	}
+	if scopes == nil {
+		scopes = FullChatbotScopes()
+	}
+	storage.GetGroup(in.GetGroupID()).SetChatbotScopes(in.GetInvitedID(), scopes)
+	storage.GetGroup(in.GetGroupID()).SetChatbotSignedScopes(in.GetInvitedID(), in.GetSignedScopes())
+	storage.GetGroup(in.GetGroupID()).SetChatbotInviter(in.GetInvitedID(), in.GetInitiatorID())
+
+	// Record whether the chatbot uses the hybrid KEM, so that members joining later encrypt to it accordingly.
+	storage.GetGroup(in.GetGroupID()).SetChatbotHybridKEM(in.GetInvitedID(), in.GetHybridKEM())
 
+	// Create a ServerEvent GROUP_CHATBOT_INVITATION to the participant to notify it that it is added to a group.
+	// What an IGA chatbot may learn of the group is redacted by ServerEventView.
 	eventMsg := &pb.ServerEvent{
 		EventType: pb.ServerEventType_GROUP_CHATBOT_INVITATION,
 		EventData: &pb.ServerEvent_GroupChatbotInvitation{
 			GroupChatbotInvitation: &pb.GroupChatbotInvitation{
 				SenderID:           in.GetInitiatorID(),
 				GroupID:            in.GetGroupID(),
-				ParticipantIDs:     participantIDs,
+				ParticipantIDs:     storage.GetGroup(in.GetGroupID()).GetParticipantIDs(),
 				GroupType:          pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
 				IsIGA:              in.GetIsIGA(),
 				IsPseudo:           in.GetIsPseudo(),
@@ -359,11 +386,14 @@ func (s *ServiceServer) InviteChatbot(ctx context.Context, in *pb.InviteChatbotR
 				ChatbotInitLeaf:    in.GetChatbotInitLeaf(),
 				MlsKeyPackageID:    in.GetMlsKeyPackageID(),
 				MlsWelcomeMessage:  in.GetMlsWelcomeMessage(),
+				Scopes:             scopes,
+				CipherSuite:        storage.GetGroup(in.GetGroupID()).CipherSuite,
+				HybridKEM:          in.GetHybridKEM(),
 			},
 		},
 	}
 
-	storage.GetChatbot(in.GetInvitedID()).PushServerEventToQueue(eventMsg)
+	pushServerEvent(storage.GetGroup(in.GetGroupID()), eventMsg, in.GetInvitedID())
 
 	// Create a ServerEvent GROUP_CHATBOT_ADDITION to other group members to notify them that a new user is added to a group.
 	eventMsg = &pb.ServerEvent{
@@ -380,22 +410,16 @@ func (s *ServiceServer) InviteChatbot(ctx context.Context, in *pb.InviteChatbotR
 				ChatbotCipherText: in.GetChatbotCipherText(),
 				MlsUserAdd:        in.GetMlsUserAdd(),
 				MlsAddCommit:      in.GetMlsAddCommit(),
+				ChatbotRouting:    in.GetSignedChatbotRouting(),
+				Scopes:            scopes,
+				HybridKEM:         in.GetHybridKEM(),
+				SignedScopes:      in.GetSignedScopes(),
 			},
 		},
 	}
 
-	for _, pid := range storage.GetGroup(in.GetGroupID()).GetParticipantIDs() {
-		storage.GetUser(pid).PushServerEventToQueue(eventMsg)
-	}
-
-	// If is MLS group, send invitation to chatbot as well
-	if storage.GetGroup(in.GetGroupID()).GroupType == int(pb.GroupType_MLS) {
-		for _, cid := range storage.GetGroup(in.GetGroupID()).GetChatbotIDs() {
-			if cid != in.GetInvitedID() {
-				storage.GetChatbot(cid).PushServerEventToQueue(eventMsg)
-			}
-		}
-	}
+	// In MLS groups, the other non-IGA chatbots are MLS members and learn the addition as well.
+	pushServerEventToGroup(storage.GetGroup(in.GetGroupID()), eventMsg)
 
 	return &pb.InviteChatbotResponse{Success: true, ErrorMessage: ""}, nil
 }
@@ -427,35 +451,94 @@ func (s *ServiceServer) RemoveChatbot(ctx context.Context, in *pb.RemoveChatbotR
 				SenderID:         in.GetInitiatorID(),
 				RemovedChatbotID: in.GetRemovedID(),
 				GroupID:          in.GetGroupID(),
-				ChatbotIDs:       storage.GetGroup(in.GetGroupID()).GetParticipantIDs(),
+				ChatbotIDs:       storage.GetGroup(in.GetGroupID()).GetChatbotIDs(),
 				GroupType:        pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
 			},
 		},
 	}
 
-	for _, pid := range storage.GetGroup(in.GetGroupID()).GetParticipantIDs() {
-		storage.GetUser(pid).PushServerEventToQueue(eventMsg)
+	pushServerEventToGroup(storage.GetGroup(in.GetGroupID()), eventMsg, in.GetRemovedID())
+
+	return &pb.RemoveChatbotResponse{Success: true, ErrorMessage: ""}, nil
+}
+
+/*
+UpdateChatbotScopes handles the update chatbot scopes requests.
+*/
+func (s *ServiceServer) UpdateChatbotScopes(ctx context.Context, in *pb.UpdateChatbotScopesRequest) (*pb.UpdateChatbotScopesResponse, error) {
+	log.Printf("Received RequestUpdateChatbotScopes: %v %v", in.GetGroupID(), in.GetChatbotID())
+
+	// Check if the groupID exists.
+	if !storage.ContainGroup(in.GetGroupID()) {
+		return &pb.UpdateChatbotScopesResponse{Success: false, ErrorMessage: "groupID does not exist"}, nil
 	}
 
-	storage.GetChatbot(in.GetRemovedID()).PushServerEventToQueue(eventMsg)
+	// Check if the chatbot is in the group.
+	if !storage.GetGroup(in.GetGroupID()).ContainChatbot(in.GetChatbotID()) {
+		return &pb.UpdateChatbotScopesResponse{Success: false, ErrorMessage: "chatbotID is not in the group"}, nil
+	}
 
-	return &pb.RemoveChatbotResponse{Success: true, ErrorMessage: ""}, nil
+	// Only group members may change the scopes.
+	if !ContainString(in.GetInitiatorID(), storage.GetGroup(in.GetGroupID()).GetParticipantIDs()) {
+		return &pb.UpdateChatbotScopesResponse{Success: false, ErrorMessage: "initiatorID is not in the group"}, nil
+	}
+
+	// Of the members, only the one who invited the chatbot may change its scopes.
+	if in.GetInitiatorID() != storage.GetGroup(in.GetGroupID()).GetChatbotInviter(in.GetChatbotID()) {
+		return &pb.UpdateChatbotScopesResponse{Success: false, ErrorMessage: "initiatorID did not invite the chatbot"}, nil
+	}
+
+	scopes := in.GetScopes()
+	if in.GetSignedScopes() != nil {
+		scopes = in.GetSignedScopes().GetScopes()
+	}
+	if scopes == nil {
+		return &pb.UpdateChatbotScopesResponse{Success: false, ErrorMessage: "scopes must be given"}, nil
+	}
+
+	storage.GetGroup(in.GetGroupID()).SetChatbotScopes(in.GetChatbotID(), scopes)
+	storage.GetGroup(in.GetGroupID()).SetChatbotSignedScopes(in.GetChatbotID(), in.GetSignedScopes())
+
+	// Create a ServerEvent GROUP_CHATBOT_SCOPE_UPDATE to the members and the chatbot to notify them of the new scopes.
+	eventMsg := &pb.ServerEvent{
+		EventType: pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE,
+		EventData: &pb.ServerEvent_GroupChatbotScopeUpdate{
+			GroupChatbotScopeUpdate: &pb.GroupChatbotScopeUpdate{
+				SenderID:     in.GetInitiatorID(),
+				GroupID:      in.GetGroupID(),
+				ChatbotID:    in.GetChatbotID(),
+				GroupType:    pb.GroupType(storage.GetGroup(in.GetGroupID()).GroupType),
+				Scopes:       scopes,
+				SignedScopes: in.GetSignedScopes(),
+			},
+		},
+	}
+
+	pushServerEventToGroup(storage.GetGroup(in.GetGroupID()), eventMsg)
+
+	return &pb.UpdateChatbotScopesResponse{Success: true, ErrorMessage: ""}, nil
 }
 
 /*
 MessageStream handles the message stream requests, i.e., the messages from the server to the client.
 */
 func (s *ServiceServer) MessageStream(srv *pb.MessageStreamInit, stream pb.ChatService_MessageStreamServer) error {
-	var messageWrapper *pb.MessageWrapper
+	user := storage.GetUser(srv.GetUserID())
+	done := streamDone(stream.Context(), user.StreamsClosed())
 	for {
+		messageWrapper := user.PopMessageFromQueueUntil(done)
 		if messageWrapper == nil {
-			messageWrapper = storage.GetUser(srv.GetUserID()).PopMessageFromQueue()
+			// The client closed the stream, or asked to close it
+			return nil
 		}
 
 		if err := stream.Send(messageWrapper); err != nil {
 			log.Printf("send error %v", err)
-		} else {
-			messageWrapper = nil // message is sent successfully, so set it to nil
+			// Keep the message for the next attempt, or for the next stream if the client went away
+			user.ReturnUndeliveredMessage(messageWrapper)
+			if stream.Context().Err() != nil {
+				return nil
+			}
 		}
 	}
 }
@@ -469,6 +552,13 @@ func (s *ServiceServer) SendMessage(ctx context.Context, in *pb.MessageWrapper)
 
 	messageWrapper := &pb.MessageWrapper{}
 
+	// The MessageID is kept if the client chose one, so that the client can send the message again without it being
+	// fanned out twice.
+	messageID := in.GetMessageID()
+	if messageID == "" {
+		messageID = RandomString(16)
+	}
+
 	// Serialize the message
 	messageWrapper = &pb.MessageWrapper{
 		SenderID:             in.GetSenderID(),
@@ -478,54 +568,158 @@ func (s *ServiceServer) SendMessage(ctx context.Context, in *pb.MessageWrapper)
 		ChatbotIds:           in.GetChatbotIds(),
 		HasPreKey:            in.GetHasPreKey(),
 		IsIGA:                in.GetIsIGA(),
+		IsPseudo:             in.GetIsPseudo(),
 		TreeKEMKeyUpdatePack: in.GetTreeKEMKeyUpdatePack(),
 		ChatbotKeyUpdatePack: in.GetChatbotKeyUpdatePack(),
 		MlsCommit:            in.GetMlsCommit(),
+		Timestamp:            time.Now().UnixMilli(),
+		MessageID:            messageID,
 	}
 
+	var keyUpdateSeq uint64
 	if storage.ContainUser(in.GetRecipientID()) {
+		// A message sent again is not queued twice.
+		if in.GetMessageID() != "" && !storage.GetUser(in.GetRecipientID()).AcceptMessage(in.GetSenderID(), in.GetMessageID()) {
+			log.Printf("Dropped duplicate of message %v from %v to %v", in.GetMessageID(), in.GetSenderID(), in.GetRecipientID())
+			return &pb.SendMessageResponse{Success: true, ErrorMessage: ""}, nil
+		}
+
 		// Push message to the queue of the recipient
 		storage.GetUser(in.GetRecipientID()).PushMessageToQueue(messageWrapper)
 	} else if storage.ContainGroup(in.GetRecipientID()) {
+		group := storage.GetGroup(in.GetRecipientID())
+		group.sendMutex.Lock()
+		defer group.sendMutex.Unlock()
+
+		// A message sent again gets the response to the first one, as it was already fanned out.
+		if in.GetMessageID() != "" {
+			if response, accepted := group.GetAcceptedMessage(in.GetSenderID(), in.GetMessageID()); accepted {
+				log.Printf("Dropped duplicate of message %v from %v to %v", in.GetMessageID(), in.GetSenderID(), in.GetRecipientID())
+				return response, nil
+			}
+		}
+
+		recipientIDs := make([]string, 0, len(group.GetParticipantIDs())+len(in.GetChatbotMessages()))
+		for _, pid := range group.GetParticipantIDs() {
+			if pid != in.GetSenderID() {
+				recipientIDs = append(recipientIDs, pid)
+			}
+		}
+		for _, chatbotMessage := range in.GetChatbotMessages() {
+			if !storage.ContainChatbot(chatbotMessage.GetChatbotID()) || !ContainString(chatbotMessage.GetChatbotID(), group.GetChatbotIDs()) {
+				return &pb.SendMessageResponse{Success: false, ErrorMessage: "chatbotID does not exist"}, nil
+			}
+			recipientIDs = append(recipientIDs, chatbotMessage.GetChatbotID())
+		}
+
+		// Key updates are applied in the order they are fanned out. The first of two concurrent key updates wins, and the
+		// other one is rejected, as it does not build on the first one.
+		if carriesKeyUpdate(in) {
+			delivered, ok := group.CheckKeyUpdateBase(in.GetSenderID(), in.GetKeyUpdateBase())
+			if !ok {
+				log.Printf("Rejected key update from %v to %v: key update %v was not handled yet", in.GetSenderID(), in.GetRecipientID(), delivered)
+				return &pb.SendMessageResponse{Success: false, ErrorMessage: "key update conflict", KeyUpdateSeq: delivered, KeyUpdateConflict: true}, nil
+			}
+
+			keyUpdateSeq = group.AssignKeyUpdateSeq(recipientIDs)
+			messageWrapper.KeyUpdateSeq = keyUpdateSeq
+			for _, chatbotMessage := range in.GetChatbotMessages() {
+				chatbotMessage.GetMessageWrapper().KeyUpdateSeq = keyUpdateSeq
+			}
+		}
+
 		// Push message to the queues of all participants
-		for _, pid := range storage.GetGroup(in.GetRecipientID()).GetParticipantIDs() {
+		for _, pid := range group.GetParticipantIDs() {
 			if pid != in.GetSenderID() {
 				storage.GetUser(pid).PushMessageToQueue(messageWrapper)
 			}
 		}
 
 		for _, chatbotMessage := range in.GetChatbotMessages() {
-			if storage.ContainChatbot(chatbotMessage.GetChatbotID()) && ContainString(chatbotMessage.GetChatbotID(), storage.GetGroup(in.GetRecipientID()).GetChatbotIDs()) {
-				storage.GetChatbot(chatbotMessage.GetChatbotID()).PushMessageToQueue(chatbotMessage.GetMessageWrapper())
-			} else {
-				return &pb.SendMessageResponse{Success: false, ErrorMessage: "chatbotID does not exist"}, nil
-			}
+			chatbotMessage.GetMessageWrapper().Timestamp = messageWrapper.GetTimestamp()
+			chatbotMessage.GetMessageWrapper().MessageID = messageWrapper.GetMessageID()
+			storage.GetChatbot(chatbotMessage.GetChatbotID()).PushMessageToQueue(chatbotMessage.GetMessageWrapper())
 		}
+
+		response := &pb.SendMessageResponse{Success: true, ErrorMessage: "", KeyUpdateSeq: keyUpdateSeq}
+		if in.GetMessageID() != "" {
+			group.AddAcceptedMessage(in.GetSenderID(), in.GetMessageID(), response)
+		}
+		return response, nil
 	} else {
 		return &pb.SendMessageResponse{Success: false, ErrorMessage: "recipientID does not exist"}, nil
 	}
 
-	return &pb.SendMessageResponse{Success: true, ErrorMessage: ""}, nil
+	return &pb.SendMessageResponse{Success: true, ErrorMessage: "", KeyUpdateSeq: keyUpdateSeq}, nil
+}
+
+// carriesKeyUpdate checks whether a group message changes the TreeKEM, CMRT or MLS state of the group.
+func carriesKeyUpdate(messageWrapper *pb.MessageWrapper) bool {
+	if messageWrapper.GetTreeKEMKeyUpdatePack() != nil || messageWrapper.GetChatbotKeyUpdatePack() != nil || messageWrapper.GetMlsCommit() != nil {
+		return true
+	}
+	for _, chatbotMessage := range messageWrapper.GetChatbotMessages() {
+		if chatbotMessage.GetMessageWrapper().GetTreeKEMKeyUpdatePack() != nil {
+			return true
+		}
+	}
+	return false
 }
 
 /*
 ServerEventStream handles the server event stream requests.
 */
 func (s *ServiceServer) ServerEventStream(srv *pb.ServerEventStreamInit, stream pb.ChatService_ServerEventStreamServer) error {
-	var serverEvent *pb.ServerEvent
+	user := storage.GetUser(srv.GetUserID())
+	done := streamDone(stream.Context(), user.StreamsClosed())
 	for {
+		serverEvent := user.PopServerEventFromQueueUntil(done)
 		if serverEvent == nil {
-			serverEvent = storage.GetUser(srv.GetUserID()).PopServerEventFromQueue()
+			// The client closed the stream, or asked to close it
+			return nil
 		}
 
 		if err := stream.Send(serverEvent); err != nil {
 			log.Printf("send error %v", err)
-		} else {
-			serverEvent = nil // event is sent successfully, so set it to nil
+			// Keep the event for the next attempt, or for the next stream if the client went away
+			user.ReturnUndeliveredServerEvent(serverEvent)
+			if stream.Context().Err() != nil {
+				return nil
+			}
 		}
 	}
 }
 
+/*
+CloseStreams handles the close streams requests. The streams of the user end once they sent what they already took off
+the queues, so that the client receives everything up to the end of the streams and the rest waits for its next streams.
+*/
+func (s *ServiceServer) CloseStreams(ctx context.Context, in *pb.CloseStreamsRequest) (*pb.CloseStreamsResponse, error) {
+	log.Printf("Received CloseStreams: %v", in.GetUserID())
+
+	// Check if the user ID exists
+	if !storage.ContainUser(in.GetUserID()) {
+		return &pb.CloseStreamsResponse{Success: false, ErrorMessage: "userID does not exist"}, nil
+	}
+
+	storage.GetUser(in.GetUserID()).CloseStreams()
+
+	return &pb.CloseStreamsResponse{Success: true, ErrorMessage: ""}, nil
+}
+
+// streamDone returns a channel that is closed once the stream is cancelled or its streams are closed.
+func streamDone(ctx context.Context, streamsClosed <-chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-streamsClosed:
+		}
+		close(done)
+	}()
+	return done
+}
+
 // RandomString create a random string with the given length.
 func RandomString(length int) string {
 	const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
@@ -536,6 +730,12 @@ func RandomString(length int) string {
 	return string(b)
 }
 
+// chatbotReceivesMembershipEvents checks whether the chatbot's scopes let it learn membership changes of the group.
+// Chatbots in MLS groups are MLS members and always need the add and remove commits to stay in sync.
+func chatbotReceivesMembershipEvents(group *ServerSideGroup, chatbotID string) bool {
+	return group.GroupType == int(pb.GroupType_MLS) || group.GetChatbotScopes()[chatbotID].GetMembershipEvents()
+}
+
 // ContainString checks if the given string is in the given string slice.
 func ContainString(value string, slice []string) bool {
 	for _, v := range slice {
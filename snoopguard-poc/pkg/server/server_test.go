@@ -2,6 +2,7 @@ package server
 
 import (
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
 	"chatbot-poc-go/pkg/util"
 	"context"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"log"
 	"net"
 	"testing"
+	"time"
 )
 
 func server(ctx context.Context) (pb.ChatServiceClient, func()) {
@@ -224,6 +226,284 @@ func TestGroup(t *testing.T) {
 	assert.Equal(t, getGroupRes3.ParticipantIDs, []string{alice.UserID, carol.UserID}, "GetGroup response should have the same participant IDs")
 }
 
+func TestChatbotScopes(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	// New users and chatbots
+	for _, userID := range []string{"scope-alice", "scope-bob"} {
+		if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: userID}); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, chatbotID := range []string{"scope-chatbot1", "scope-chatbot2"} {
+		if _, err := client.SetChatbot(ctx, &pb.SetChatbotRequest{ChatbotID: chatbotID}); err != nil {
+			t.Error(err)
+		}
+	}
+
+	createGroupRes, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: "scope-alice", GroupType: pb.GroupType_SERVER_SIDE})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	groupID := createGroupRes.GroupID
+
+	// Chatbot1 is invited with commands only, chatbot2 without scopes
+	commandsOnly := &pb.ChatbotScopes{Commands: true}
+	inviteRes, err := client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "scope-alice", InvitedID: "scope-chatbot1", Scopes: commandsOnly})
+	assert.Nil(t, err, "InviteChatbot error should be nil")
+	assert.True(t, inviteRes.GetSuccess(), "InviteChatbot should be successful")
+	inviteRes, err = client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "scope-alice", InvitedID: "scope-chatbot2"})
+	assert.Nil(t, err, "InviteChatbot error should be nil")
+	assert.True(t, inviteRes.GetSuccess(), "InviteChatbot should be successful")
+
+	group := storage.GetGroup(groupID)
+	assert.True(t, group.GetChatbotScopes()["scope-chatbot1"].GetCommands(), "Chatbot1 should be allowed to read commands")
+	assert.False(t, group.GetChatbotScopes()["scope-chatbot1"].GetAllMessages(), "Chatbot1 should not be allowed to read all messages")
+	assert.True(t, group.GetChatbotScopes()["scope-chatbot2"].GetAllMessages(), "Chatbot2 should have full access")
+	assert.True(t, group.GetChatbotScopes()["scope-chatbot2"].GetMayPost(), "Chatbot2 should have full access")
+
+	invitation := storage.GetChatbot("scope-chatbot1").PopServerEventFromQueue()
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, invitation.GetEventType(), "Chatbot1 should be invited")
+	assert.True(t, invitation.GetGroupChatbotInvitation().GetScopes().GetCommands(), "Chatbot1 should learn its scopes")
+
+	// Chatbot1 does not learn membership changes, chatbot2 does
+	_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: groupID, InitiatorID: "scope-alice", InvitedID: "scope-bob"})
+	assert.Nil(t, err, "InviteMember error should be nil")
+	assert.Equal(t, 0, len(storage.GetChatbot("scope-chatbot1").eventQueue), "Chatbot1 should not learn membership changes")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, storage.GetUser("scope-bob").PopServerEventFromQueue().GetEventType(), "Bob should be invited")
+	assert.Equal(t, pb.ServerEventType_GROUP_CHATBOT_INVITATION, storage.GetChatbot("scope-chatbot2").PopServerEventFromQueue().GetEventType(), "Chatbot2 should be invited")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, storage.GetChatbot("scope-chatbot2").PopServerEventFromQueue().GetEventType(), "Chatbot2 should learn membership changes")
+
+	// Invalid scope updates are rejected
+	updateRes, err := client.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{GroupID: groupID, InitiatorID: "scope-carol", ChatbotID: "scope-chatbot1", Scopes: commandsOnly})
+	assert.Nil(t, err, "UpdateChatbotScopes error should be nil")
+	assert.False(t, updateRes.GetSuccess(), "Non-members should not update scopes")
+	updateRes, err = client.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{GroupID: groupID, InitiatorID: "scope-alice", ChatbotID: "scope-chatbot3", Scopes: commandsOnly})
+	assert.Nil(t, err, "UpdateChatbotScopes error should be nil")
+	assert.False(t, updateRes.GetSuccess(), "Scopes of chatbots not in the group should not be updated")
+
+	updateRes, err = client.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{GroupID: groupID, InitiatorID: "scope-bob", ChatbotID: "scope-chatbot1", Scopes: FullChatbotScopes()})
+	assert.Nil(t, err, "UpdateChatbotScopes error should be nil")
+	assert.False(t, updateRes.GetSuccess(), "Members who did not invite the chatbot should not update its scopes")
+	assert.False(t, group.GetChatbotScopes()["scope-chatbot1"].GetAllMessages(), "The scopes should not change")
+
+	// Alice, who invited chatbot1, grants it membership events
+	updateRes, err = client.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{GroupID: groupID, InitiatorID: "scope-alice", ChatbotID: "scope-chatbot1", Scopes: &pb.ChatbotScopes{Commands: true, MembershipEvents: true}})
+	assert.Nil(t, err, "UpdateChatbotScopes error should be nil")
+	assert.True(t, updateRes.GetSuccess(), "The inviter should update scopes")
+	assert.True(t, group.GetChatbotScopes()["scope-chatbot1"].GetMembershipEvents(), "The new scopes should be recorded")
+
+	for _, queue := range []*ServerSideUser{storage.GetUser("scope-alice"), storage.GetUser("scope-bob"), storage.GetChatbot("scope-chatbot1")} {
+		// Skip the earlier membership events
+		event := queue.PopServerEventFromQueue()
+		for event.GetEventType() != pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE {
+			event = queue.PopServerEventFromQueue()
+		}
+		assert.Equal(t, "scope-chatbot1", event.GetGroupChatbotScopeUpdate().GetChatbotID(), "The scope update should name the chatbot")
+		assert.True(t, event.GetGroupChatbotScopeUpdate().GetScopes().GetMembershipEvents(), "The scope update should carry the new scopes")
+	}
+}
+
+func TestGroupCipherSuite(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: "suite-alice"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: "suite-bob"}); err != nil {
+		t.Error(err)
+	}
+
+	// The default suite is selected when none is given
+	createGroupRes, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: "suite-alice", GroupType: pb.GroupType_SERVER_SIDE})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	assert.True(t, createGroupRes.Success, "CreateGroup response should be successful")
+	getGroupRes, err := client.GetGroup(ctx, &pb.GetGroupRequest{GroupID: createGroupRes.GroupID})
+	assert.Nil(t, err, "GetGroup error should be nil")
+	assert.Equal(t, uint32(treekem.DefaultCipherSuite), getGroupRes.CipherSuite, "group should use the default cipher suite")
+
+	// The negotiated suite is stored and sent along with the invitations
+	createGroupRes, err = client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: "suite-alice", GroupType: pb.GroupType_MLS, CipherSuite: uint32(treekem.X25519_CHACHA20POLY1305_SHA256_Ed25519)})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	assert.True(t, createGroupRes.Success, "CreateGroup response should be successful")
+	getGroupRes, err = client.GetGroup(ctx, &pb.GetGroupRequest{GroupID: createGroupRes.GroupID})
+	assert.Nil(t, err, "GetGroup error should be nil")
+	assert.Equal(t, uint32(treekem.X25519_CHACHA20POLY1305_SHA256_Ed25519), getGroupRes.CipherSuite, "group should use the negotiated cipher suite")
+
+	for len(storage.GetUser("suite-bob").eventQueue) > 0 {
+		storage.GetUser("suite-bob").PopServerEventFromQueue()
+	}
+	_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: createGroupRes.GroupID, InitiatorID: "suite-alice", InvitedID: "suite-bob"})
+	assert.Nil(t, err, "InviteMember error should be nil")
+	invitation := storage.GetUser("suite-bob").PopServerEventFromQueue().GetGroupInvitation()
+	assert.Equal(t, uint32(treekem.X25519_CHACHA20POLY1305_SHA256_Ed25519), invitation.GetCipherSuite(), "invitation should carry the cipher suite")
+
+	// Unsupported suites are rejected
+	createGroupRes, err = client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: "suite-alice", GroupType: pb.GroupType_SERVER_SIDE, CipherSuite: 0xffff})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	assert.False(t, createGroupRes.Success, "CreateGroup with an unsupported cipher suite should fail")
+}
+
+func TestServerEventPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	// drainEvents pops every event queued for the given user or chatbot.
+	drainEvents := func(id string) []*pb.ServerEvent {
+		var events []*pb.ServerEvent
+		for len(storage.GetUser(id).eventQueue) > 0 {
+			events = append(events, storage.GetUser(id).PopServerEventFromQueue())
+		}
+		return events
+	}
+
+	for _, userID := range []string{"policy-alice", "policy-bob", "policy-carol"} {
+		if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: userID}); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, chatbotID := range []string{"policy-chatbot", "policy-chatbot2", "policy-iga", "policy-pseudo"} {
+		if _, err := client.SetChatbot(ctx, &pb.SetChatbotRequest{ChatbotID: chatbotID}); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, groupType := range []pb.GroupType{pb.GroupType_SERVER_SIDE, pb.GroupType_MLS} {
+		createGroupRes, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: "policy-alice", GroupType: groupType})
+		assert.Nil(t, err, "CreateGroup error should be nil")
+		groupID := createGroupRes.GroupID
+		_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-bob"})
+		assert.Nil(t, err, "InviteMember error should be nil")
+
+		// Invite the IGA and pseudonymous chatbots first, then the non-IGA chatbots
+		_, err = client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-iga", IsIGA: true})
+		assert.Nil(t, err, "InviteChatbot error should be nil")
+		_, err = client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-pseudo", IsIGA: true, IsPseudo: true, MlsWelcomeMessage: []byte("welcome"), MlsKeyPackageID: 1})
+		assert.Nil(t, err, "InviteChatbot error should be nil")
+		_, err = client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-chatbot"})
+		assert.Nil(t, err, "InviteChatbot error should be nil")
+		_, err = client.InviteChatbot(ctx, &pb.InviteChatbotRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-chatbot2"})
+		assert.Nil(t, err, "InviteChatbot error should be nil")
+
+		// IGA chatbots learn only their own invitation, without the inviter, the participants or the MLS state
+		for _, cid := range []string{"policy-iga", "policy-pseudo"} {
+			events := drainEvents(cid)
+			assert.Equal(t, 1, len(events), "IGA chatbots should learn nothing but their invitation")
+			invitation := events[0].GetGroupChatbotInvitation()
+			assert.NotNil(t, invitation, "IGA chatbots should be invited")
+			assert.Nil(t, invitation.GetParticipantIDs(), "IGA chatbots should not learn the participants")
+			assert.Equal(t, "", invitation.GetSenderID(), "IGA chatbots should not learn the inviter")
+			assert.Nil(t, invitation.GetMlsWelcomeMessage(), "IGA chatbots should not learn the MLS welcome")
+			assert.Equal(t, uint32(0), invitation.GetMlsKeyPackageID(), "IGA chatbots should not learn the MLS key package")
+		}
+
+		// Non-IGA chatbots learn the participants, and in MLS groups the addition of the later non-IGA chatbot only
+		events := drainEvents("policy-chatbot")
+		assert.Equal(t, []string{"policy-alice", "policy-bob"}, events[0].GetGroupChatbotInvitation().GetParticipantIDs(), "Non-IGA chatbots should learn the participants")
+		if groupType == pb.GroupType_MLS {
+			assert.Equal(t, 2, len(events), "Non-IGA chatbots should learn the addition of other non-IGA chatbots in MLS groups")
+			addition := events[1].GetGroupChatbotAddition()
+			assert.Equal(t, "policy-chatbot2", addition.GetAddedChatbotID(), "Non-IGA chatbots should learn the addition of other non-IGA chatbots in MLS groups")
+			assert.Equal(t, []string{"policy-chatbot", "policy-chatbot2"}, addition.GetChatbotIDs(), "Non-IGA chatbots should not learn IGA chatbots")
+		} else {
+			assert.Equal(t, 1, len(events), "Non-IGA chatbots should not learn chatbot additions in server-side groups")
+		}
+		drainEvents("policy-chatbot2")
+
+		// Members learn every chatbot
+		events = drainEvents("policy-bob")
+		assert.Equal(t, []string{"policy-iga", "policy-pseudo", "policy-chatbot", "policy-chatbot2"}, events[len(events)-1].GetGroupChatbotAddition().GetChatbotIDs(), "Members should learn every chatbot")
+		drainEvents("policy-alice")
+
+		// Membership changes and scope updates of other chatbots do not reach IGA chatbots
+		_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: groupID, InitiatorID: "policy-alice", InvitedID: "policy-carol"})
+		assert.Nil(t, err, "InviteMember error should be nil")
+		_, err = client.RemoveMember(ctx, &pb.RemoveMemberRequest{GroupID: groupID, InitiatorID: "policy-alice", RemovedID: "policy-carol"})
+		assert.Nil(t, err, "RemoveMember error should be nil")
+		_, err = client.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{GroupID: groupID, InitiatorID: "policy-alice", ChatbotID: "policy-chatbot", Scopes: FullChatbotScopes()})
+		assert.Nil(t, err, "UpdateChatbotScopes error should be nil")
+		for _, cid := range []string{"policy-iga", "policy-pseudo"} {
+			assert.Equal(t, 0, len(drainEvents(cid)), "IGA chatbots should not learn membership changes")
+		}
+		events = drainEvents("policy-chatbot")
+		assert.Equal(t, 3, len(events), "Non-IGA chatbots should learn membership changes and their own scopes")
+		assert.Equal(t, 2, len(drainEvents("policy-chatbot2")), "Non-IGA chatbots should learn membership changes only")
+		assert.Equal(t, 2, len(drainEvents("policy-carol")), "Carol should learn her invitation and removal")
+
+		// The removed IGA chatbot learns neither the remover nor the remaining chatbots
+		_, err = client.RemoveChatbot(ctx, &pb.RemoveChatbotRequest{GroupID: groupID, InitiatorID: "policy-alice", RemovedID: "policy-iga"})
+		assert.Nil(t, err, "RemoveChatbot error should be nil")
+		events = drainEvents("policy-iga")
+		assert.Equal(t, 1, len(events), "The removed chatbot should learn its removal")
+		assert.Equal(t, groupID, events[0].GetGroupChatbotRemoval().GetGroupID(), "The removed chatbot should learn its removal")
+		assert.Nil(t, events[0].GetGroupChatbotRemoval().GetChatbotIDs(), "The removed chatbot should not learn the other chatbots")
+		assert.Equal(t, "", events[0].GetGroupChatbotRemoval().GetSenderID(), "The removed IGA chatbot should not learn the remover")
+		assert.Equal(t, 0, len(drainEvents("policy-pseudo")), "IGA chatbots should not learn the removal of other chatbots")
+		assert.Equal(t, 0, len(drainEvents("policy-chatbot")), "Non-IGA chatbots should not learn the removal of other chatbots")
+		events = drainEvents("policy-bob")
+		assert.Equal(t, []string{"policy-pseudo", "policy-chatbot", "policy-chatbot2"}, events[len(events)-1].GetGroupChatbotRemoval().GetChatbotIDs(), "Members should learn the remaining chatbots")
+		drainEvents("policy-alice")
+	}
+}
+
+func TestServerEventViewForIGAChatbot(t *testing.T) {
+	storage.AddUser("view-alice")
+	storage.AddChatbot("view-iga")
+	storage.AddChatbot("view-chatbot")
+	group := NewServerSideGroup("view-group", int(pb.GroupType_MLS))
+	group.AddParticipantByID("view-alice")
+	group.AddChatbotByID("view-iga", true, false)
+	group.AddChatbotByID("view-chatbot", false, false)
+
+	// No event about others reaches an IGA chatbot
+	events := []*pb.ServerEvent{
+		{EventType: pb.ServerEventType_GROUP_INVITATION, EventData: &pb.ServerEvent_GroupInvitation{GroupInvitation: &pb.GroupInvitation{GroupID: "view-group"}}},
+		{EventType: pb.ServerEventType_GROUP_ADDITION, EventData: &pb.ServerEvent_GroupAddition{GroupAddition: &pb.GroupAddition{GroupID: "view-group", AddedID: "view-bob"}}},
+		{EventType: pb.ServerEventType_GROUP_REMOVAL, EventData: &pb.ServerEvent_GroupRemoval{GroupRemoval: &pb.GroupRemoval{GroupID: "view-group", RemovedID: "view-bob"}}},
+		{EventType: pb.ServerEventType_GROUP_CHATBOT_ADDITION, EventData: &pb.ServerEvent_GroupChatbotAddition{GroupChatbotAddition: &pb.GroupChatbotAddition{GroupID: "view-group", AddedChatbotID: "view-chatbot"}}},
+		{EventType: pb.ServerEventType_GROUP_CHATBOT_REMOVAL, EventData: &pb.ServerEvent_GroupChatbotRemoval{GroupChatbotRemoval: &pb.GroupChatbotRemoval{GroupID: "view-group", RemovedChatbotID: "view-chatbot"}}},
+		{EventType: pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, EventData: &pb.ServerEvent_GroupChatbotScopeUpdate{GroupChatbotScopeUpdate: &pb.GroupChatbotScopeUpdate{GroupID: "view-group", ChatbotID: "view-chatbot"}}},
+	}
+	for _, event := range events {
+		assert.Nil(t, ServerEventView(group, event, "view-iga"), fmt.Sprintf("IGA chatbots should not learn %v", event.GetEventType()))
+		assert.Equal(t, event, ServerEventView(group, event, "view-alice"), fmt.Sprintf("Members should learn %v", event.GetEventType()))
+	}
+
+	// Redaction does not modify the event delivered to the others
+	event := &pb.ServerEvent{EventType: pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE, EventData: &pb.ServerEvent_GroupChatbotScopeUpdate{GroupChatbotScopeUpdate: &pb.GroupChatbotScopeUpdate{GroupID: "view-group", SenderID: "view-alice", ChatbotID: "view-iga"}}}
+	assert.Equal(t, "", ServerEventView(group, event, "view-iga").GetGroupChatbotScopeUpdate().GetSenderID(), "IGA chatbots should not learn who updated their scopes")
+	assert.Equal(t, "view-alice", event.GetGroupChatbotScopeUpdate().GetSenderID(), "The original event should not be redacted")
+}
+
+func TestServerEventViewForChatbotWithoutMembershipEvents(t *testing.T) {
+	storage.AddUser("view-dave")
+	storage.AddChatbot("view-scoped")
+	group := NewServerSideGroup("view-scoped-group", int(pb.GroupType_SERVER_SIDE))
+	group.AddParticipantByID("view-dave")
+	group.AddChatbotByID("view-scoped", false, false)
+	group.SetChatbotScopes("view-scoped", &pb.ChatbotScopes{Commands: true})
+
+	addition := &pb.ServerEvent{EventType: pb.ServerEventType_GROUP_ADDITION, EventData: &pb.ServerEvent_GroupAddition{GroupAddition: &pb.GroupAddition{GroupID: "view-scoped-group", AddedID: "view-erin"}}}
+	assert.Nil(t, ServerEventView(group, addition, "view-scoped"), "Chatbots without membership events should not learn additions")
+
+	// The chatbot learns the remaining participants after a removal, but not who was removed or by whom
+	removal := &pb.ServerEvent{EventType: pb.ServerEventType_GROUP_REMOVAL, EventData: &pb.ServerEvent_GroupRemoval{GroupRemoval: &pb.GroupRemoval{
+		GroupID: "view-scoped-group", GroupType: pb.GroupType_SERVER_SIDE, SenderID: "view-dave", RemovedID: "view-erin", ParticipantIDs: []string{"view-dave"},
+	}}}
+	view := ServerEventView(group, removal, "view-scoped")
+	assert.NotNil(t, view, "Chatbots without membership events should learn removals to rotate their sender keys")
+	assert.Equal(t, []string{"view-dave"}, view.GetGroupRemoval().GetParticipantIDs(), "The chatbot should learn the remaining participants")
+	assert.Equal(t, "", view.GetGroupRemoval().GetRemovedID(), "The chatbot should not learn who was removed")
+	assert.Equal(t, "", view.GetGroupRemoval().GetSenderID(), "The chatbot should not learn who removed the member")
+	assert.Equal(t, "view-erin", removal.GetGroupRemoval().GetRemovedID(), "The original event should not be redacted")
+}
+
 func TestMessageStream(t *testing.T) {
 	ctx := context.Background()
 
@@ -324,3 +604,194 @@ func TestMessageStream(t *testing.T) {
 		assert.Equal(t, bobRecv.EncryptedMessage, []byte(fmt.Sprintf("Test Alice to Bob %v", i)), "Bob should receive message from Alice")
 	}
 }
+
+func TestGroupKeyUpdateOrder(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	serializer := serialize.NewProtoBufSerializer()
+	alice := util.NewUser("alice", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: alice.UserID}); err != nil {
+		t.Error(err)
+	}
+	bob := util.NewUser("bob", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: bob.UserID}); err != nil {
+		t.Error(err)
+	}
+
+	createGroupRes, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: alice.UserID, GroupType: pb.GroupType_SERVER_SIDE})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	groupID := createGroupRes.GroupID
+	_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: groupID, InitiatorID: alice.UserID, InvitedID: bob.UserID})
+	assert.Nil(t, err, "InviteMember error should be nil")
+
+	keyUpdate := func(senderID string, keyUpdateBase uint64) *pb.SendMessageResponse {
+		res, err := client.SendMessage(ctx, &pb.MessageWrapper{
+			SenderID:             senderID,
+			RecipientID:          groupID,
+			EncryptedMessage:     []byte("Key update"), // not encrypted intentionally
+			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{},
+			KeyUpdateBase:        keyUpdateBase,
+		})
+		assert.Nil(t, err, "SendMessage error should be nil")
+		return res
+	}
+
+	// Alice and Bob both update from the initial state, Alice comes first
+	res := keyUpdate(alice.UserID, 0)
+	assert.True(t, res.GetSuccess(), "The first key update should be accepted")
+	assert.Equal(t, uint64(1), res.GetKeyUpdateSeq(), "The first key update should be ordered first")
+
+	res = keyUpdate(bob.UserID, 0)
+	assert.False(t, res.GetSuccess(), "The concurrent key update should be rejected")
+	assert.True(t, res.GetKeyUpdateConflict(), "The concurrent key update should be reported as a conflict")
+	assert.Equal(t, uint64(1), res.GetKeyUpdateSeq(), "Bob should be told to handle the key update of Alice first")
+
+	// Once Bob handled the update of Alice, his update is ordered after it
+	res = keyUpdate(bob.UserID, 1)
+	assert.True(t, res.GetSuccess(), "The key update on top of the accepted one should be accepted")
+	assert.Equal(t, uint64(2), res.GetKeyUpdateSeq(), "The key update of Bob should be ordered second")
+
+	// Alice did not handle the update of Bob yet
+	res = keyUpdate(alice.UserID, 1)
+	assert.True(t, res.GetKeyUpdateConflict(), "Alice should handle the key update of Bob first")
+	assert.Equal(t, uint64(2), res.GetKeyUpdateSeq(), "Alice should be told to handle the key update of Bob first")
+
+	// Messages without a key update are not ordered
+	res, err = client.SendMessage(ctx, &pb.MessageWrapper{SenderID: alice.UserID, RecipientID: groupID, EncryptedMessage: []byte("No key update")})
+	assert.Nil(t, err, "SendMessage error should be nil")
+	assert.True(t, res.GetSuccess(), "A message without a key update should be accepted")
+	assert.Equal(t, uint64(0), res.GetKeyUpdateSeq(), "A message without a key update should not be ordered")
+}
+
+func TestGroupMessageDedup(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	serializer := serialize.NewProtoBufSerializer()
+	erin := util.NewUser("erin", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: erin.UserID}); err != nil {
+		t.Error(err)
+	}
+	frank := util.NewUser("frank", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: frank.UserID}); err != nil {
+		t.Error(err)
+	}
+
+	createGroupRes, err := client.CreateGroup(ctx, &pb.CreateGroupRequest{InitiatorID: erin.UserID, GroupType: pb.GroupType_SERVER_SIDE})
+	assert.Nil(t, err, "CreateGroup error should be nil")
+	groupID := createGroupRes.GroupID
+	_, err = client.InviteMember(ctx, &pb.InviteMemberRequest{GroupID: groupID, InitiatorID: erin.UserID, InvitedID: frank.UserID})
+	assert.Nil(t, err, "InviteMember error should be nil")
+
+	frankConn, err := client.MessageStream(ctx, &pb.MessageStreamInit{UserID: frank.UserID})
+	assert.Nil(t, err, "MessageStream error should be nil")
+
+	keyUpdate := func(messageID string) *pb.SendMessageResponse {
+		res, err := client.SendMessage(ctx, &pb.MessageWrapper{
+			SenderID:             erin.UserID,
+			RecipientID:          groupID,
+			EncryptedMessage:     []byte("Key update " + messageID), // not encrypted intentionally
+			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{},
+			MessageID:            messageID,
+		})
+		assert.Nil(t, err, "SendMessage error should be nil")
+		return res
+	}
+
+	res := keyUpdate("first")
+	assert.True(t, res.GetSuccess(), "The key update should be accepted")
+	assert.Equal(t, uint64(1), res.GetKeyUpdateSeq(), "The key update should be ordered first")
+
+	// The message sent again gets the response to the first one
+	res = keyUpdate("first")
+	assert.True(t, res.GetSuccess(), "The message sent again should be accepted")
+	assert.False(t, res.GetKeyUpdateConflict(), "The message sent again should not conflict with itself")
+	assert.Equal(t, uint64(1), res.GetKeyUpdateSeq(), "The message sent again should keep its order")
+
+	// Frank receives the message once, with the MessageID Erin chose
+	frankRecv, err := frankConn.Recv()
+	assert.Nil(t, err, "Frank should receive the message")
+	assert.Equal(t, "first", frankRecv.GetMessageID(), "Frank should receive the message with its MessageID")
+	_, err = client.SendMessage(ctx, &pb.MessageWrapper{SenderID: erin.UserID, RecipientID: groupID, EncryptedMessage: []byte("Next"), MessageID: "next"})
+	assert.Nil(t, err, "SendMessage error should be nil")
+	frankRecv, err = frankConn.Recv()
+	assert.Nil(t, err, "Frank should receive the next message")
+	assert.Equal(t, "next", frankRecv.GetMessageID(), "Frank should not receive the message twice")
+}
+
+func TestAcceptedMessageLimit(t *testing.T) {
+	group := NewServerSideGroup("group", int(pb.GroupType_SERVER_SIDE))
+	for i := 0; i <= AcceptedMessageLimit; i++ {
+		group.AddAcceptedMessage("alice", fmt.Sprintf("message %v", i), &pb.SendMessageResponse{Success: true, KeyUpdateSeq: uint64(i)})
+	}
+
+	_, accepted := group.GetAcceptedMessage("alice", "message 0")
+	assert.False(t, accepted, "The oldest message should be forgotten")
+	response, accepted := group.GetAcceptedMessage("alice", fmt.Sprintf("message %v", AcceptedMessageLimit))
+	assert.True(t, accepted, "The latest message should be remembered")
+	assert.Equal(t, uint64(AcceptedMessageLimit), response.GetKeyUpdateSeq(), "The response to the latest message should be remembered")
+	_, accepted = group.GetAcceptedMessage("bob", "message 1")
+	assert.False(t, accepted, "The messages of another sender should not match")
+
+	// Messages older than the window are forgotten
+	group.acceptedMessages.order[0].acceptedAt = time.Now().Add(-AcceptedMessageWindow - time.Second)
+	_, accepted = group.GetAcceptedMessage("alice", "message 1")
+	assert.False(t, accepted, "A message older than the window should be forgotten")
+	_, accepted = group.GetAcceptedMessage("alice", "message 2")
+	assert.True(t, accepted, "A message within the window should be remembered")
+
+	// The last key update of a sender is remembered beyond the window
+	for _, message := range group.acceptedMessages.order {
+		message.acceptedAt = time.Now().Add(-AcceptedMessageWindow - time.Second)
+	}
+	_, accepted = group.GetAcceptedMessage("alice", "message 2")
+	assert.False(t, accepted, "A message older than the window should be forgotten")
+	response, accepted = group.GetAcceptedMessage("alice", fmt.Sprintf("message %v", AcceptedMessageLimit))
+	assert.True(t, accepted, "The last key update should be remembered beyond the window")
+	assert.Equal(t, uint64(AcceptedMessageLimit), response.GetKeyUpdateSeq(), "The response to the last key update should be remembered")
+}
+
+func TestIndividualMessageDedup(t *testing.T) {
+	ctx := context.Background()
+
+	client, closer := server(ctx)
+	defer closer()
+
+	serializer := serialize.NewProtoBufSerializer()
+	grace := util.NewUser("grace", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: grace.UserID}); err != nil {
+		t.Error(err)
+	}
+	heidi := util.NewUser("heidi", 1, serializer)
+	if _, err := client.SetUser(ctx, &pb.SetUserRequest{UserID: heidi.UserID}); err != nil {
+		t.Error(err)
+	}
+	heidiConn, err := client.MessageStream(ctx, &pb.MessageStreamInit{UserID: heidi.UserID})
+	assert.Nil(t, err, "MessageStream error should be nil")
+
+	send := func(messageID string) {
+		res, err := client.SendMessage(ctx, &pb.MessageWrapper{
+			SenderID:         grace.UserID,
+			RecipientID:      heidi.UserID,
+			EncryptedMessage: []byte("Test Grace to Heidi"), // not encrypted intentionally
+			MessageID:        messageID,
+		})
+		assert.Nil(t, err, "SendMessage error should be nil")
+		assert.True(t, res.GetSuccess(), "The message should be accepted")
+	}
+	send("first")
+	send("first")
+	send("second")
+
+	heidiRecv, err := heidiConn.Recv()
+	assert.Nil(t, err, "Heidi should receive the message")
+	assert.Equal(t, "first", heidiRecv.GetMessageID(), "Heidi should receive the message with its MessageID")
+	heidiRecv, err = heidiConn.Recv()
+	assert.Nil(t, err, "Heidi should receive the next message")
+	assert.Equal(t, "second", heidiRecv.GetMessageID(), "Heidi should not receive the message twice")
+}
@@ -4,6 +4,7 @@ import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"go.mau.fi/libsignal/keys/prekey"
 	"sync"
+	"time"
 )
 
 var mutexLock sync.Mutex
@@ -85,6 +86,19 @@ type ServerSideUser struct {
 	messageQueue               chan *pb.MessageWrapper
 	eventQueue                 chan *pb.ServerEvent
 	isChatbot                  bool
+	chatbotRouting             *pb.ChatbotRouting
+
+	// undeliveredMessage and undeliveredEvent hold what a stream popped but could not deliver before the client went
+	// away, so that the next stream of the client delivers it first.
+	undeliveredMessage *pb.MessageWrapper
+	undeliveredEvent   *pb.ServerEvent
+
+	// streamsClosed is closed when the client asks to end its current streams, and replaced for the streams after.
+	streamsClosed chan struct{}
+
+	// acceptedMessages remembers the individual messages recently queued for the user, so that a message sent again is
+	// not queued twice.
+	acceptedMessages *AcceptedMessages
 }
 
 // NewServerSideUser creates a new ServerSideUser.
@@ -98,6 +112,7 @@ func NewServerSideUser() *ServerSideUser {
 		messageQueue:               make(chan *pb.MessageWrapper, 10000),
 		eventQueue:                 make(chan *pb.ServerEvent, 10000),
 		isChatbot:                  false,
+		acceptedMessages:           NewAcceptedMessages(),
 	}
 }
 
@@ -112,6 +127,7 @@ func NewServerSideChatbot() *ServerSideUser {
 		messageQueue:               make(chan *pb.MessageWrapper, 10000),
 		eventQueue:                 make(chan *pb.ServerEvent, 10000),
 		isChatbot:                  true,
+		acceptedMessages:           NewAcceptedMessages(),
 	}
 }
 
@@ -182,6 +198,31 @@ func (s *ServerSideUser) GetSerializedMlsKeyPackage() ([]byte, uint32) {
 	return serializedMlsKeyPackageTemp, serializedMlsKeyPackageIdTemp
 }
 
+// SetChatbotRouting sets the mention names and command prefixes the chatbot registered.
+func (s *ServerSideUser) SetChatbotRouting(chatbotRouting *pb.ChatbotRouting) {
+	mutexLock.Lock()
+	s.chatbotRouting = chatbotRouting
+	mutexLock.Unlock()
+}
+
+// GetChatbotRouting gets the mention names and command prefixes the chatbot registered.
+func (s *ServerSideUser) GetChatbotRouting() *pb.ChatbotRouting {
+	return s.chatbotRouting
+}
+
+// AcceptMessage remembers an individual message queued for the ServerSideUser, and returns false if the sender already
+// sent it with the same MessageID.
+func (s *ServerSideUser) AcceptMessage(senderID string, messageID string) bool {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	if _, accepted := s.acceptedMessages.Get(senderID, messageID); accepted {
+		return false
+	}
+	s.acceptedMessages.Add(senderID, messageID, &pb.SendMessageResponse{Success: true})
+	return true
+}
+
 // PushMessageToQueue pushes a message to the ServerSideUser's message queue.
 func (s *ServerSideUser) PushMessageToQueue(message *pb.MessageWrapper) {
 	s.messageQueue <- message
@@ -192,6 +233,32 @@ func (s *ServerSideUser) PopMessageFromQueue() *pb.MessageWrapper {
 	return <-s.messageQueue
 }
 
+// PopMessageFromQueueUntil pops a message from the ServerSideUser's message queue, starting with the message an earlier
+// stream could not deliver. It returns nil if done is closed first.
+func (s *ServerSideUser) PopMessageFromQueueUntil(done <-chan struct{}) *pb.MessageWrapper {
+	mutexLock.Lock()
+	message := s.undeliveredMessage
+	s.undeliveredMessage = nil
+	mutexLock.Unlock()
+	if message != nil {
+		return message
+	}
+
+	select {
+	case message = <-s.messageQueue:
+		return message
+	case <-done:
+		return nil
+	}
+}
+
+// ReturnUndeliveredMessage keeps a popped message that could not be delivered, so that it is popped again first.
+func (s *ServerSideUser) ReturnUndeliveredMessage(message *pb.MessageWrapper) {
+	mutexLock.Lock()
+	s.undeliveredMessage = message
+	mutexLock.Unlock()
+}
+
 // PushServerEventToQueue pushes a server event to the ServerSideUser's event queue.
 func (s *ServerSideUser) PushServerEventToQueue(event *pb.ServerEvent) {
 	s.eventQueue <- event
@@ -202,22 +269,102 @@ func (s *ServerSideUser) PopServerEventFromQueue() *pb.ServerEvent {
 	return <-s.eventQueue
 }
 
+// PopServerEventFromQueueUntil pops a server event from the ServerSideUser's event queue, starting with the event an
+// earlier stream could not deliver. It returns nil if done is closed first.
+func (s *ServerSideUser) PopServerEventFromQueueUntil(done <-chan struct{}) *pb.ServerEvent {
+	mutexLock.Lock()
+	event := s.undeliveredEvent
+	s.undeliveredEvent = nil
+	mutexLock.Unlock()
+	if event != nil {
+		return event
+	}
+
+	select {
+	case event = <-s.eventQueue:
+		return event
+	case <-done:
+		return nil
+	}
+}
+
+// ReturnUndeliveredServerEvent keeps a popped server event that could not be delivered, so that it is popped again
+// first.
+func (s *ServerSideUser) ReturnUndeliveredServerEvent(event *pb.ServerEvent) {
+	mutexLock.Lock()
+	s.undeliveredEvent = event
+	mutexLock.Unlock()
+}
+
+// StreamsClosed returns a channel that is closed once the client asks to end its current streams.
+func (s *ServerSideUser) StreamsClosed() <-chan struct{} {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+	if s.streamsClosed == nil {
+		s.streamsClosed = make(chan struct{})
+	}
+	return s.streamsClosed
+}
+
+// CloseStreams ends the current streams of the ServerSideUser. Streams opened afterward are not affected.
+func (s *ServerSideUser) CloseStreams() {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+	if s.streamsClosed != nil {
+		close(s.streamsClosed)
+	}
+	s.streamsClosed = make(chan struct{})
+}
+
 type ServerSideGroup struct {
-	GroupID         string
-	ParticipantIDs  []string
-	ChatbotIDs      []string
-	ChatbotIsIGA    map[string]bool
-	ChatbotIsPseudo map[string]bool
-	GroupType       int
+	GroupID          string
+	ParticipantIDs   []string
+	ChatbotIDs       []string
+	ChatbotIsIGA     map[string]bool
+	ChatbotIsPseudo  map[string]bool
+	ChatbotRoutings  map[string]*pb.SignedChatbotRouting
+	ChatbotScopes    map[string]*pb.ChatbotScopes
+	ChatbotHybridKEM map[string]bool
+	GroupType        int
+	CipherSuite      uint32
+
+	// ChatbotInviters is the member who invited each chatbot, who alone may change its scopes, and
+	// ChatbotSignedScopes the scopes of each chatbot as signed by its inviter, which are relayed to the members.
+	ChatbotInviters     map[string]string
+	ChatbotSignedScopes map[string]*pb.SignedChatbotScopes
+
+	// KeyUpdateSeq is the sequence number of the last key update fanned out to the group, and DeliveredKeyUpdateSeqs
+	// the one of the last key update fanned out to each participant and chatbot.
+	KeyUpdateSeq           uint64
+	DeliveredKeyUpdateSeqs map[string]uint64
+
+	// acceptedMessages remembers the messages recently fanned out to the group, so that a message sent again is not
+	// fanned out twice, and lastKeyUpdates the last key update fanned out from each sender, which is remembered for
+	// good, as a sender rolls back a key update the server rejects when it is sent again.
+	acceptedMessages *AcceptedMessages
+	lastKeyUpdates   map[string]*acceptedMessage
+
+	// sendMutex makes the messages of the group fan out one at a time, so everyone receives them in the same order.
+	sendMutex sync.Mutex
 }
 
 // NewServerSideGroup creates a new ServerSideGroup.
 func NewServerSideGroup(groupID string, groupType int) *ServerSideGroup {
 	return &ServerSideGroup{
-		GroupID:         groupID,
-		GroupType:       groupType,
-		ChatbotIsIGA:    make(map[string]bool),
-		ChatbotIsPseudo: make(map[string]bool),
+		GroupID:          groupID,
+		GroupType:        groupType,
+		ChatbotIsIGA:     make(map[string]bool),
+		ChatbotIsPseudo:  make(map[string]bool),
+		ChatbotRoutings:  make(map[string]*pb.SignedChatbotRouting),
+		ChatbotScopes:    make(map[string]*pb.ChatbotScopes),
+		ChatbotHybridKEM: make(map[string]bool),
+
+		ChatbotInviters:     make(map[string]string),
+		ChatbotSignedScopes: make(map[string]*pb.SignedChatbotScopes),
+
+		DeliveredKeyUpdateSeqs: make(map[string]uint64),
+		acceptedMessages:       NewAcceptedMessages(),
+		lastKeyUpdates:         make(map[string]*acceptedMessage),
 	}
 }
 
@@ -261,6 +408,11 @@ func (s *ServerSideGroup) RemoveChatbotByID(chatbotID string) {
 	for i, v := range s.ChatbotIDs {
 		if v == chatbotID {
 			s.ChatbotIDs = append(s.ChatbotIDs[:i], s.ChatbotIDs[i+1:]...)
+			delete(s.ChatbotRoutings, chatbotID)
+			delete(s.ChatbotScopes, chatbotID)
+			delete(s.ChatbotHybridKEM, chatbotID)
+			delete(s.ChatbotInviters, chatbotID)
+			delete(s.ChatbotSignedScopes, chatbotID)
 			mutexLock.Unlock()
 			return
 		}
@@ -282,3 +434,202 @@ func (s *ServerSideGroup) GetChatbotIsIGA() map[string]bool {
 func (s *ServerSideGroup) GetChatbotIsPseudo() map[string]bool {
 	return s.ChatbotIsPseudo
 }
+
+// SetChatbotRouting records the mention names and command prefixes of a chatbot in the ServerSideGroup, as signed by the
+// member who invited it.
+func (s *ServerSideGroup) SetChatbotRouting(chatbotID string, chatbotRouting *pb.SignedChatbotRouting) {
+	mutexLock.Lock()
+	s.ChatbotRoutings[chatbotID] = chatbotRouting
+	mutexLock.Unlock()
+}
+
+// GetChatbotRoutings gets the chatbotRoutings from the ServerSideGroup.
+func (s *ServerSideGroup) GetChatbotRoutings() map[string]*pb.SignedChatbotRouting {
+	return s.ChatbotRoutings
+}
+
+// SetChatbotScopes records the access scopes of a chatbot in the ServerSideGroup.
+func (s *ServerSideGroup) SetChatbotScopes(chatbotID string, chatbotScopes *pb.ChatbotScopes) {
+	mutexLock.Lock()
+	s.ChatbotScopes[chatbotID] = chatbotScopes
+	mutexLock.Unlock()
+}
+
+// GetChatbotScopes gets the chatbotScopes from the ServerSideGroup.
+func (s *ServerSideGroup) GetChatbotScopes() map[string]*pb.ChatbotScopes {
+	return s.ChatbotScopes
+}
+
+// SetChatbotSignedScopes records the access scopes of a chatbot as signed by its inviter in the ServerSideGroup.
+func (s *ServerSideGroup) SetChatbotSignedScopes(chatbotID string, signedScopes *pb.SignedChatbotScopes) {
+	mutexLock.Lock()
+	s.ChatbotSignedScopes[chatbotID] = signedScopes
+	mutexLock.Unlock()
+}
+
+// GetChatbotSignedScopes gets the chatbotSignedScopes from the ServerSideGroup.
+func (s *ServerSideGroup) GetChatbotSignedScopes() map[string]*pb.SignedChatbotScopes {
+	return s.ChatbotSignedScopes
+}
+
+// SetChatbotInviter records the member who invited a chatbot to the ServerSideGroup.
+func (s *ServerSideGroup) SetChatbotInviter(chatbotID string, inviterID string) {
+	mutexLock.Lock()
+	s.ChatbotInviters[chatbotID] = inviterID
+	mutexLock.Unlock()
+}
+
+// GetChatbotInviter gets the member who invited a chatbot to the ServerSideGroup.
+func (s *ServerSideGroup) GetChatbotInviter(chatbotID string) string {
+	return s.ChatbotInviters[chatbotID]
+}
+
+// SetChatbotHybridKEM records whether the TreeKEM ciphertexts to and from a chatbot use the hybrid KEM.
+func (s *ServerSideGroup) SetChatbotHybridKEM(chatbotID string, hybridKEM bool) {
+	mutexLock.Lock()
+	s.ChatbotHybridKEM[chatbotID] = hybridKEM
+	mutexLock.Unlock()
+}
+
+// GetChatbotHybridKEM gets the chatbotHybridKEM from the ServerSideGroup.
+func (s *ServerSideGroup) GetChatbotHybridKEM() map[string]bool {
+	return s.ChatbotHybridKEM
+}
+
+// CheckKeyUpdateBase checks a key update the sender issued after handling the key update with the sequence number base.
+// It conflicts with the key updates delivered to the sender after that one, as it does not build on them. It returns
+// the sequence number of the last key update delivered to the sender, and whether the key update can be accepted.
+func (s *ServerSideGroup) CheckKeyUpdateBase(senderID string, base uint64) (uint64, bool) {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	delivered := s.DeliveredKeyUpdateSeqs[senderID]
+	return delivered, base >= delivered
+}
+
+// ResetDeliveredKeyUpdateSeq records that the last key update of the group was delivered to a member joining it, and
+// returns its sequence number.
+func (s *ServerSideGroup) ResetDeliveredKeyUpdateSeq(memberID string) uint64 {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	s.DeliveredKeyUpdateSeqs[memberID] = s.KeyUpdateSeq
+	return s.KeyUpdateSeq
+}
+
+// AssignKeyUpdateSeq assigns the next sequence number to a key update fanned out to the given recipients.
+func (s *ServerSideGroup) AssignKeyUpdateSeq(recipientIDs []string) uint64 {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	s.KeyUpdateSeq++
+	for _, recipientID := range recipientIDs {
+		s.DeliveredKeyUpdateSeqs[recipientID] = s.KeyUpdateSeq
+	}
+	return s.KeyUpdateSeq
+}
+
+// GetAcceptedMessage returns the response to the message the sender already sent to the group with the given
+// MessageID, if the group still remembers it. The last key update of the sender is always remembered.
+func (s *ServerSideGroup) GetAcceptedMessage(senderID string, messageID string) (*pb.SendMessageResponse, bool) {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	if keyUpdate, exists := s.lastKeyUpdates[senderID]; exists && keyUpdate.key[1] == messageID {
+		return keyUpdate.response, true
+	}
+	return s.acceptedMessages.Get(senderID, messageID)
+}
+
+// AddAcceptedMessage remembers the response to a message fanned out to the group. A key update, which the response
+// assigns a sequence number, is remembered until the sender's next one.
+func (s *ServerSideGroup) AddAcceptedMessage(senderID string, messageID string, response *pb.SendMessageResponse) {
+	mutexLock.Lock()
+	defer mutexLock.Unlock()
+
+	s.acceptedMessages.Add(senderID, messageID, response)
+	if response.GetKeyUpdateSeq() != 0 {
+		s.lastKeyUpdates[senderID] = &acceptedMessage{key: [2]string{senderID, messageID}, response: response, acceptedAt: time.Now()}
+	}
+}
+
+// ContainChatbot checks if the chatbot is in the ServerSideGroup.
+func (s *ServerSideGroup) ContainChatbot(chatbotID string) bool {
+	for _, v := range s.ChatbotIDs {
+		if v == chatbotID {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptedMessageLimit is how many accepted messages a group or user remembers to recognize the ones sent again.
+const AcceptedMessageLimit = 1024
+
+// AcceptedMessageWindow is how long an accepted message is remembered to recognize it when it is sent again.
+const AcceptedMessageWindow = 10 * time.Minute
+
+// acceptedMessage is the response to a message accepted from a sender, and when it was accepted.
+type acceptedMessage struct {
+	key        [2]string
+	response   *pb.SendMessageResponse
+	acceptedAt time.Time
+}
+
+// AcceptedMessages remembers the responses to the messages recently accepted from each sender by their MessageID, so
+// that a message sent again is recognized. It keeps at most AcceptedMessageLimit messages, none older than
+// AcceptedMessageWindow. The caller must hold the mutexLock.
+type AcceptedMessages struct {
+	messages map[[2]string]*acceptedMessage
+	// order holds the messages oldest first.
+	order []*acceptedMessage
+}
+
+// NewAcceptedMessages creates an AcceptedMessages that remembers no message yet.
+func NewAcceptedMessages() *AcceptedMessages {
+	return &AcceptedMessages{
+		messages: make(map[[2]string]*acceptedMessage),
+	}
+}
+
+// Get returns the response to the message the sender sent with the given MessageID, if it is still remembered.
+func (a *AcceptedMessages) Get(senderID string, messageID string) (*pb.SendMessageResponse, bool) {
+	a.expire(time.Now())
+	message, exists := a.messages[[2]string{senderID, messageID}]
+	if !exists {
+		return nil, false
+	}
+	return message.response, true
+}
+
+// Add remembers the response to a message accepted from the sender.
+func (a *AcceptedMessages) Add(senderID string, messageID string, response *pb.SendMessageResponse) {
+	now := time.Now()
+	message := &acceptedMessage{key: [2]string{senderID, messageID}, response: response, acceptedAt: now}
+	a.messages[message.key] = message
+	a.order = append(a.order, message)
+	a.expire(now)
+}
+
+// expire forgets the oldest messages beyond AcceptedMessageLimit, and the ones older than AcceptedMessageWindow.
+func (a *AcceptedMessages) expire(now time.Time) {
+	for len(a.order) > 0 && (len(a.order) > AcceptedMessageLimit || now.Sub(a.order[0].acceptedAt) > AcceptedMessageWindow) {
+		// A message added again replaced its entry, which is only forgotten once its latest copy is.
+		if a.messages[a.order[0].key] == a.order[0] {
+			delete(a.messages, a.order[0].key)
+		}
+		a.order = a.order[1:]
+	}
+}
+
+// FullChatbotScopes returns the scopes of a chatbot invited without restrictions.
+func FullChatbotScopes() *pb.ChatbotScopes {
+	return &pb.ChatbotScopes{
+		Commands:         true,
+		Mentions:         true,
+		AllMessages:      true,
+		MembershipEvents: true,
+		MayPost:          true,
+		MayDirectMessage: true,
+	}
+}
@@ -0,0 +1,71 @@
+package stores
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrWrongRecoveryCode is returned when a backup cannot be decrypted, either because the recovery code is wrong or
+// because the backup was tampered with.
+var ErrWrongRecoveryCode = errors.New("wrong recovery code or corrupted backup")
+
+const (
+	backupVersion = 1
+
+	// recoveryCodeBytes is the entropy of a recovery code, which is shown to the user in groups of recoveryCodeGroup
+	// characters.
+	recoveryCodeBytes = 20
+	recoveryCodeGroup = 4
+)
+
+var backupMagic = []byte("SGBK")
+
+// GenerateRecoveryCode returns a fresh random recovery code for SealBackup, e.g. "ABCD-EFGH-...". The code is all the
+// user needs to open the backup, so it should be written down rather than stored next to the backup.
+func GenerateRecoveryCode() (string, error) {
+	random := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random)
+
+	groups := make([]string, 0, len(encoded)/recoveryCodeGroup+1)
+	for i := 0; i < len(encoded); i += recoveryCodeGroup {
+		groups = append(groups, encoded[i:min(i+recoveryCodeGroup, len(encoded))])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeRecoveryCode drops the separators and the case of a recovery code, so that it can be typed back loosely.
+func normalizeRecoveryCode(recoveryCode string) []byte {
+	return []byte(strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(recoveryCode)))
+}
+
+// SealBackup encrypts a backup with AES-256-GCM under a key derived from the recovery code with Argon2id. Like the
+// EncryptedFileStore, the sealed backup starts with a header holding the format version and the salt of the key.
+func SealBackup(recoveryCode string, backup []byte) ([]byte, error) {
+	salt := make([]byte, encryptedFileStoreSalt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return seal(derivePassphraseKey(normalizeRecoveryCode(recoveryCode), salt), sealedHeader(backupMagic, backupVersion, salt), backup)
+}
+
+// OpenBackup decrypts a backup sealed by SealBackup.
+func OpenBackup(recoveryCode string, sealed []byte) ([]byte, error) {
+	salt, err := parseSealedHeader(backupMagic, backupVersion, sealed)
+	if errors.Is(err, errNotSealed) {
+		return nil, fmt.Errorf("%w: not a backup", ErrWrongRecoveryCode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	backup, err := open(derivePassphraseKey(normalizeRecoveryCode(recoveryCode), salt), len(sealedHeader(backupMagic, backupVersion, salt)), sealed)
+	if err != nil {
+		return nil, ErrWrongRecoveryCode
+	}
+	return backup, nil
+}
@@ -0,0 +1,212 @@
+package stores
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// StateStore durably keeps the serialized state of a client, so that the client can be restored after a restart.
+type StateStore interface {
+	// Save replaces the stored state.
+	Save(state []byte) error
+	// Load returns the stored state, or ErrStateNotFound if nothing was saved yet.
+	Load() ([]byte, error)
+}
+
+var (
+	// ErrStateNotFound is returned when loading from a store nothing was saved to yet.
+	ErrStateNotFound = errors.New("no stored state")
+	// ErrWrongPassphrase is returned when the stored state cannot be decrypted, either because the passphrase is wrong
+	// or because the file was tampered with.
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted state")
+)
+
+const (
+	encryptedFileStoreVersion = 1
+	encryptedFileStoreSalt    = 16
+
+	// Argon2id parameters used to derive the encryption key from the passphrase, the second recommended option of
+	// RFC 9106 for memory-constrained environments.
+	encryptedFileStoreKDFTime    = 3
+	encryptedFileStoreKDFMemory  = 64 * 1024
+	encryptedFileStoreKDFThreads = 4
+)
+
+var encryptedFileStoreMagic = []byte("SGST")
+
+// EncryptedFileStore is a StateStore that keeps the state in a file, sealed with AES-256-GCM under a key derived from a
+// passphrase with Argon2id. The file starts with a header holding the format version and the salt of the key, which is
+// authenticated together with the state. The file is replaced atomically, so a crash never leaves a torn state behind.
+type EncryptedFileStore struct {
+	path       string
+	passphrase []byte
+
+	// The key derived for salt, cached as the derivation is deliberately slow.
+	salt []byte
+	key  []byte
+
+	mutexLock sync.Mutex
+}
+
+// NewEncryptedFileStore returns an EncryptedFileStore keeping the state in the file at path, encrypted under passphrase.
+// The file is only created on the first Save.
+func NewEncryptedFileStore(path string, passphrase []byte) *EncryptedFileStore {
+	return &EncryptedFileStore{
+		path:       path,
+		passphrase: passphrase,
+	}
+}
+
+func (e *EncryptedFileStore) deriveKey(salt []byte) []byte {
+	if e.key != nil && bytes.Equal(e.salt, salt) {
+		return e.key
+	}
+	e.salt = salt
+	e.key = derivePassphraseKey(e.passphrase, salt)
+	return e.key
+}
+
+// derivePassphraseKey derives a 256-bit key from a passphrase with Argon2id.
+func derivePassphraseKey(passphrase []byte, salt []byte) []byte {
+	return argon2.IDKey(passphrase, salt, encryptedFileStoreKDFTime, encryptedFileStoreKDFMemory, encryptedFileStoreKDFThreads, 32)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Save encrypts state and atomically replaces the file with it. The salt of the key is chosen on the first Save and
+// kept afterward, so that the key is only derived once.
+func (e *EncryptedFileStore) Save(state []byte) error {
+	e.mutexLock.Lock()
+	defer e.mutexLock.Unlock()
+
+	salt := e.salt
+	if salt == nil {
+		salt = make([]byte, encryptedFileStoreSalt)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	sealed, err := seal(e.deriveKey(salt), sealedHeader(encryptedFileStoreMagic, encryptedFileStoreVersion, salt), state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(e.path, sealed)
+}
+
+func (e *EncryptedFileStore) Load() ([]byte, error) {
+	e.mutexLock.Lock()
+	defer e.mutexLock.Unlock()
+
+	sealed, err := os.ReadFile(e.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := parseSealedHeader(encryptedFileStoreMagic, encryptedFileStoreVersion, sealed)
+	if errors.Is(err, errNotSealed) {
+		return nil, fmt.Errorf("%w: not a state file", ErrWrongPassphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("state file: %w", err)
+	}
+	state, err := open(e.deriveKey(salt), len(sealedHeader(encryptedFileStoreMagic, encryptedFileStoreVersion, salt)), sealed)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return state, nil
+}
+
+// writeFileAtomically replaces the file at path with data. The data is written to a temporary file first, which is
+// moved over the old file once it is on disk.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// errNotSealed is returned when data does not start with the expected header.
+var errNotSealed = errors.New("missing header")
+
+// sealedHeader returns the header of sealed data: the magic, the format version and the salt of the key.
+func sealedHeader(magic []byte, version byte, salt []byte) []byte {
+	header := append([]byte{}, magic...)
+	header = append(header, version)
+	return append(header, salt...)
+}
+
+// parseSealedHeader checks the header of sealed data and returns the salt of the key.
+func parseSealedHeader(magic []byte, version byte, sealed []byte) ([]byte, error) {
+	headerSize := len(magic) + 1 + encryptedFileStoreSalt
+	if len(sealed) < headerSize || !bytes.Equal(sealed[:len(magic)], magic) {
+		return nil, errNotSealed
+	}
+	if sealed[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported version %v", sealed[len(magic)])
+	}
+	return append([]byte{}, sealed[headerSize-encryptedFileStoreSalt:headerSize]...), nil
+}
+
+// seal encrypts data with AES-256-GCM under key, and returns it after the header and the nonce. The header is
+// authenticated together with the data.
+func seal(key []byte, header []byte, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return append(append(header, nonce...), gcm.Seal(nil, nonce, data, header)...), nil
+}
+
+// open decrypts data sealed by seal, given the size of its header.
+func open(key []byte, headerSize int, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < headerSize+gcm.NonceSize() {
+		return nil, errors.New("truncated")
+	}
+	nonce := sealed[headerSize : headerSize+gcm.NonceSize()]
+	return gcm.Open(nil, nonce, sealed[headerSize+gcm.NonceSize():], sealed[:headerSize])
+}
@@ -8,14 +8,14 @@ import (
 
 func NewInMemoryIdentityKeyStore(identityKey *identity.KeyPair, localRegistrationID uint32) *InMemoryIdentityKeyStore {
 	return &InMemoryIdentityKeyStore{
-		trustedKeys:         make(map[*protocol.SignalAddress]*identity.Key),
+		trustedKeys:         make(map[protocol.SignalAddress]*identity.Key),
 		identityKeyPair:     identityKey,
 		localRegistrationID: localRegistrationID,
 	}
 }
 
 type InMemoryIdentityKeyStore struct {
-	trustedKeys         map[*protocol.SignalAddress]*identity.Key
+	trustedKeys         map[protocol.SignalAddress]*identity.Key
 	identityKeyPair     *identity.KeyPair
 	localRegistrationID uint32
 	mutexLock           sync.RWMutex
@@ -31,13 +31,25 @@ func (i *InMemoryIdentityKeyStore) GetLocalRegistrationId() uint32 {
 
 func (i *InMemoryIdentityKeyStore) SaveIdentity(address *protocol.SignalAddress, identityKey *identity.Key) {
 	i.mutexLock.Lock()
-	i.trustedKeys[address] = identityKey
+	i.trustedKeys[*address] = identityKey
 	i.mutexLock.Unlock()
 }
 
 func (i *InMemoryIdentityKeyStore) IsTrustedIdentity(address *protocol.SignalAddress, identityKey *identity.Key) bool {
 	i.mutexLock.RLock()
-	trusted := i.trustedKeys[address]
+	trusted := i.trustedKeys[*address]
 	i.mutexLock.RUnlock()
 	return (trusted == nil || trusted.Fingerprint() == identityKey.Fingerprint())
 }
+
+func (i *InMemoryIdentityKeyStore) LoadTrustedIdentities() map[*protocol.SignalAddress]*identity.Key {
+	i.mutexLock.RLock()
+	defer i.mutexLock.RUnlock()
+
+	trustedKeys := make(map[*protocol.SignalAddress]*identity.Key, len(i.trustedKeys))
+	for address, identityKey := range i.trustedKeys {
+		trustedKeys[protocol.NewSignalAddress(address.Name(), address.DeviceID())] = identityKey
+	}
+
+	return trustedKeys
+}
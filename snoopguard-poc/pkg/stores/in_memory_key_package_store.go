@@ -41,3 +41,15 @@ func (i *InMemoryKeyPackageStore) RemoveKeyPackage(keyPackageID uint32) {
 	delete(i.store, keyPackageID)
 	i.mutexLock.Unlock()
 }
+
+func (i *InMemoryKeyPackageStore) LoadKeyPackages() map[uint32]mls.KeyPackage {
+	i.mutexLock.RLock()
+	defer i.mutexLock.RUnlock()
+
+	keyPackages := make(map[uint32]mls.KeyPackage, len(i.store))
+	for id, keyPackage := range i.store {
+		keyPackages[id] = keyPackage
+	}
+
+	return keyPackages
+}
@@ -41,3 +41,15 @@ func (i *InMemoryPreKeyStore) RemovePreKey(preKeyID uint32) {
 	delete(i.store, preKeyID)
 	i.mutexLock.Unlock()
 }
+
+func (i *InMemoryPreKeyStore) LoadPreKeys() []*record.PreKey {
+	i.mutexLock.RLock()
+	defer i.mutexLock.RUnlock()
+
+	var preKeys []*record.PreKey
+	for _, record := range i.store {
+		preKeys = append(preKeys, record)
+	}
+
+	return preKeys
+}
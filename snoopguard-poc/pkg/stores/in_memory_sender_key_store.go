@@ -8,24 +8,48 @@ import (
 
 func NewInMemorySenderKeyStore() *InMemorySenderKeyStore {
 	return &InMemorySenderKeyStore{
-		store: make(map[*protocol.SenderKeyName]*groupRecord.SenderKey),
+		store: make(map[senderKeyNameKey]*groupRecord.SenderKey),
 	}
 }
 
+// senderKeyNameKey identifies a sender key by the group and the sender rather than by the pointer to its name, so that
+// the sender keys can be looked up again after they are persisted and restored.
+type senderKeyNameKey struct {
+	groupID string
+	sender  protocol.SignalAddress
+}
+
+func newSenderKeyNameKey(senderKeyName *protocol.SenderKeyName) senderKeyNameKey {
+	return senderKeyNameKey{groupID: senderKeyName.GroupID(), sender: *senderKeyName.Sender()}
+}
+
 type InMemorySenderKeyStore struct {
-	store     map[*protocol.SenderKeyName]*groupRecord.SenderKey
+	store     map[senderKeyNameKey]*groupRecord.SenderKey
 	mutexLock sync.RWMutex
 }
 
 func (i *InMemorySenderKeyStore) StoreSenderKey(senderKeyName *protocol.SenderKeyName, keyRecord *groupRecord.SenderKey) {
 	i.mutexLock.Lock()
-	i.store[senderKeyName] = keyRecord
+	i.store[newSenderKeyNameKey(senderKeyName)] = keyRecord
 	i.mutexLock.Unlock()
 }
 
 func (i *InMemorySenderKeyStore) LoadSenderKey(senderKeyName *protocol.SenderKeyName) *groupRecord.SenderKey {
 	i.mutexLock.RLock()
-	result := i.store[senderKeyName]
+	result := i.store[newSenderKeyNameKey(senderKeyName)]
 	i.mutexLock.RUnlock()
 	return result
 }
+
+func (i *InMemorySenderKeyStore) LoadSenderKeys() map[*protocol.SenderKeyName]*groupRecord.SenderKey {
+	i.mutexLock.RLock()
+	defer i.mutexLock.RUnlock()
+
+	senderKeys := make(map[*protocol.SenderKeyName]*groupRecord.SenderKey, len(i.store))
+	for key, keyRecord := range i.store {
+		sender := protocol.NewSignalAddress(key.sender.Name(), key.sender.DeviceID())
+		senderKeys[protocol.NewSenderKeyName(key.groupID, sender)] = keyRecord
+	}
+
+	return senderKeys
+}
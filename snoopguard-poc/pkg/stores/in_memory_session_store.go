@@ -9,13 +9,15 @@ import (
 
 func NewInMemorySessionStore(serializer *serialize.Serializer) *InMemorySessionStore {
 	return &InMemorySessionStore{
-		sessions:   make(map[*protocol.SignalAddress]*record.Session),
+		sessions:   make(map[protocol.SignalAddress]*record.Session),
 		serializer: serializer,
 	}
 }
 
+// InMemorySessionStore keys the sessions by the address rather than by the pointer to it, so that the sessions can be
+// looked up again after they are persisted and restored.
 type InMemorySessionStore struct {
-	sessions   map[*protocol.SignalAddress]*record.Session
+	sessions   map[protocol.SignalAddress]*record.Session
 	serializer *serialize.Serializer
 	mutexLock  sync.RWMutex
 }
@@ -23,13 +25,13 @@ type InMemorySessionStore struct {
 func (i *InMemorySessionStore) LoadSession(address *protocol.SignalAddress) *record.Session {
 	if i.ContainsSession(address) {
 		i.mutexLock.RLock()
-		result := i.sessions[address]
+		result := i.sessions[*address]
 		i.mutexLock.RUnlock()
 		return result
 	}
 	i.mutexLock.Lock()
 	sessionRecord := record.NewSession(i.serializer.Session, i.serializer.State)
-	i.sessions[address] = sessionRecord
+	i.sessions[*address] = sessionRecord
 	i.mutexLock.Unlock()
 
 	return sessionRecord
@@ -51,25 +53,37 @@ func (i *InMemorySessionStore) GetSubDeviceSessions(name string) []uint32 {
 
 func (i *InMemorySessionStore) StoreSession(remoteAddress *protocol.SignalAddress, record *record.Session) {
 	i.mutexLock.Lock()
-	i.sessions[remoteAddress] = record
+	i.sessions[*remoteAddress] = record
 	i.mutexLock.Unlock()
 }
 
 func (i *InMemorySessionStore) ContainsSession(remoteAddress *protocol.SignalAddress) bool {
 	i.mutexLock.RLock()
-	_, ok := i.sessions[remoteAddress]
+	_, ok := i.sessions[*remoteAddress]
 	i.mutexLock.RUnlock()
 	return ok
 }
 
 func (i *InMemorySessionStore) DeleteSession(remoteAddress *protocol.SignalAddress) {
 	i.mutexLock.Lock()
-	delete(i.sessions, remoteAddress)
+	delete(i.sessions, *remoteAddress)
 	i.mutexLock.Unlock()
 }
 
 func (i *InMemorySessionStore) DeleteAllSessions() {
 	i.mutexLock.Lock()
-	i.sessions = make(map[*protocol.SignalAddress]*record.Session)
+	i.sessions = make(map[protocol.SignalAddress]*record.Session)
 	i.mutexLock.Unlock()
 }
+
+func (i *InMemorySessionStore) LoadSessions() map[*protocol.SignalAddress]*record.Session {
+	i.mutexLock.RLock()
+	defer i.mutexLock.RUnlock()
+
+	sessions := make(map[*protocol.SignalAddress]*record.Session, len(i.sessions))
+	for address, sessionRecord := range i.sessions {
+		sessions[protocol.NewSignalAddress(address.Name(), address.DeviceID())] = sessionRecord
+	}
+
+	return sessions
+}
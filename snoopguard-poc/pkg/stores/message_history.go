@@ -0,0 +1,331 @@
+package stores
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	messageHistoryVersion = 1
+
+	// historyRecordLength is the size of the length prefix of every record of a history file.
+	historyRecordLength = 4
+)
+
+var messageHistoryMagic = []byte("SGMH")
+
+// HistoryQuery selects messages from a MessageHistory. Empty fields match every message.
+type HistoryQuery struct {
+	GroupID  string
+	SenderID string
+	// Text matches the messages containing every word of it, regardless of case.
+	Text string
+	// BeforeMessageID pages backwards: only the messages older than this message are returned.
+	BeforeMessageID string
+	// Limit caps the number of messages returned, from the newest. Zero returns all of them.
+	Limit int
+}
+
+// MessageHistory keeps the messages a user sent and received, in the order they were added. If a file is set, the
+// history is kept in it encrypted at rest: the file starts with the same header as an EncryptedFileStore, followed by
+// one sealed record per message, so that a message is appended without rewriting the history. The file is only
+// rewritten when messages are deleted.
+type MessageHistory struct {
+	entries []*pb.HistoryEntry
+	// Messages older than retention are deleted. Zero keeps them forever.
+	retention time.Duration
+
+	path   string
+	header []byte
+	key    []byte
+	// The number of records in the file, which numbers the next record.
+	records int
+
+	mutexLock sync.Mutex
+}
+
+// NewMessageHistory returns an empty MessageHistory, kept in memory until a file is set.
+func NewMessageHistory() *MessageHistory {
+	return &MessageHistory{}
+}
+
+// SetFile sets the file the history is kept in, encrypted under a key derived from passphrase with Argon2id. The
+// messages already in the file are loaded, ahead of the messages added before, and the history keeps appending to the
+// file. It returns ErrWrongPassphrase if the file cannot be decrypted with passphrase.
+func (h *MessageHistory) SetFile(path string, passphrase []byte) error {
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	var header []byte
+	var saved []*pb.HistoryEntry
+	file, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, encryptedFileStoreSalt)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		header = sealedHeader(messageHistoryMagic, messageHistoryVersion, salt)
+	} else if err != nil {
+		return err
+	} else {
+		salt, err := parseSealedHeader(messageHistoryMagic, messageHistoryVersion, file)
+		if errors.Is(err, errNotSealed) {
+			return fmt.Errorf("%w: not a message history", ErrWrongPassphrase)
+		}
+		if err != nil {
+			return fmt.Errorf("message history: %w", err)
+		}
+		header = sealedHeader(messageHistoryMagic, messageHistoryVersion, salt)
+	}
+	key := derivePassphraseKey(passphrase, header[len(header)-encryptedFileStoreSalt:])
+	if file != nil {
+		if saved, err = openHistoryRecords(key, header, file[len(header):]); err != nil {
+			return err
+		}
+	}
+
+	h.path = path
+	h.header = header
+	h.key = key
+	h.entries = mergeHistoryEntries(saved, h.entries)
+	h.prune()
+	return h.rewrite()
+}
+
+// SetRetention sets how long messages are kept, and deletes the messages already older than that.
+func (h *MessageHistory) SetRetention(retention time.Duration) error {
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	h.retention = retention
+	if h.prune() {
+		return h.rewrite()
+	}
+	return nil
+}
+
+// Append adds a message to the history, which must have a MessageID. A message without a timestamp is stamped with
+// the current time.
+func (h *MessageHistory) Append(entry *pb.HistoryEntry) error {
+	if entry.GetMessageID() == "" {
+		return errors.New("message history: message has no MessageID")
+	}
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().UnixMilli()
+	}
+
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	h.entries = append(h.entries, entry)
+	if h.prune() {
+		return h.rewrite()
+	}
+	return h.appendRecords([]*pb.HistoryEntry{entry})
+}
+
+// AppendAll adds the messages of another history, e.g. one restored from a backup, skipping those already kept.
+func (h *MessageHistory) AppendAll(entries []*pb.HistoryEntry) error {
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	kept := len(h.entries)
+	h.entries = mergeHistoryEntries(h.entries, entries)
+	added := append([]*pb.HistoryEntry{}, h.entries[kept:]...)
+	if h.prune() {
+		return h.rewrite()
+	}
+	return h.appendRecords(added)
+}
+
+// Query returns the messages matching the query, oldest first. To read the next page, query again with
+// BeforeMessageID set to the ID of the first message returned.
+func (h *MessageHistory) Query(query HistoryQuery) []*pb.HistoryEntry {
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	end := len(h.entries)
+	if query.BeforeMessageID != "" {
+		end = 0
+		for i, entry := range h.entries {
+			if entry.GetMessageID() == query.BeforeMessageID {
+				end = i
+				break
+			}
+		}
+	}
+	words := strings.Fields(strings.ToLower(query.Text))
+
+	var matches []*pb.HistoryEntry
+	for i := end - 1; i >= 0 && (query.Limit == 0 || len(matches) < query.Limit); i-- {
+		if historyEntryMatches(h.entries[i], query, words) {
+			matches = append(matches, h.entries[i])
+		}
+	}
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	return matches
+}
+
+// Entries returns every message of the history, oldest first.
+func (h *MessageHistory) Entries() []*pb.HistoryEntry {
+	h.mutexLock.Lock()
+	defer h.mutexLock.Unlock()
+
+	return append([]*pb.HistoryEntry{}, h.entries...)
+}
+
+// mergeHistoryEntries appends to entries the others that it does not hold yet.
+func mergeHistoryEntries(entries []*pb.HistoryEntry, others []*pb.HistoryEntry) []*pb.HistoryEntry {
+	known := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		known[entry.GetMessageID()] = true
+	}
+	for _, entry := range others {
+		if !known[entry.GetMessageID()] {
+			entries = append(entries, entry)
+			known[entry.GetMessageID()] = true
+		}
+	}
+	return entries
+}
+
+func historyEntryMatches(entry *pb.HistoryEntry, query HistoryQuery, words []string) bool {
+	if query.GroupID != "" && entry.GetGroupID() != query.GroupID {
+		return false
+	}
+	if query.SenderID != "" && entry.GetSenderID() != query.SenderID {
+		return false
+	}
+	text := strings.ToLower(string(entry.GetMessage()))
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// prune deletes the messages older than the retention, and reports whether any was deleted. The caller must hold the
+// lock.
+func (h *MessageHistory) prune() bool {
+	if h.retention == 0 {
+		return false
+	}
+	cutoff := time.Now().Add(-h.retention).UnixMilli()
+	kept := h.entries[:0]
+	for _, entry := range h.entries {
+		if entry.GetTimestamp() >= cutoff {
+			kept = append(kept, entry)
+		}
+	}
+	pruned := len(kept) != len(h.entries)
+	for i := len(kept); i < len(h.entries); i++ {
+		h.entries[i] = nil
+	}
+	h.entries = kept
+	return pruned
+}
+
+// historyRecordAAD returns the data authenticated together with the record numbered index of a history file: the header
+// of the file and the number of the record, so that records cannot be moved between files or reordered.
+func historyRecordAAD(header []byte, index int) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, header...), uint64(index))
+}
+
+// sealHistoryRecords seals entries as the records of a history file numbered from index, each after its length.
+func sealHistoryRecords(key []byte, header []byte, index int, entries []*pb.HistoryEntry) ([]byte, error) {
+	var records []byte
+	for i, entry := range entries {
+		serialized, err := proto.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		aad := historyRecordAAD(header, index+i)
+		sealed, err := seal(key, aad, serialized)
+		if err != nil {
+			return nil, err
+		}
+		records = binary.BigEndian.AppendUint32(records, uint32(len(sealed)-len(aad)))
+		records = append(records, sealed[len(aad):]...)
+	}
+	return records, nil
+}
+
+// openHistoryRecords opens the records of a history file. A record cut short at the end of the file, left by a crash
+// while appending it, is dropped.
+func openHistoryRecords(key []byte, header []byte, records []byte) ([]*pb.HistoryEntry, error) {
+	var entries []*pb.HistoryEntry
+	for index := 0; len(records) >= historyRecordLength; index++ {
+		length := int(binary.BigEndian.Uint32(records))
+		if len(records) < historyRecordLength+length {
+			break
+		}
+		aad := historyRecordAAD(header, index)
+		serialized, err := open(key, len(aad), append(aad, records[historyRecordLength:historyRecordLength+length]...))
+		if err != nil {
+			return nil, ErrWrongPassphrase
+		}
+		entry := &pb.HistoryEntry{}
+		if err := proto.Unmarshal(serialized, entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		records = records[historyRecordLength+length:]
+	}
+	return entries, nil
+}
+
+// appendRecords appends entries to the file of the history, if one is set. The caller must hold the lock.
+func (h *MessageHistory) appendRecords(entries []*pb.HistoryEntry) error {
+	if h.path == "" || len(entries) == 0 {
+		return nil
+	}
+	records, err := sealHistoryRecords(h.key, h.header, h.records, entries)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(records)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A failed append may leave part of a record behind, which the next record would follow
+		return h.rewrite()
+	}
+	h.records += len(entries)
+	return nil
+}
+
+// rewrite replaces the file of the history, if one is set, with the messages the history holds. The caller must hold
+// the lock.
+func (h *MessageHistory) rewrite() error {
+	if h.path == "" {
+		return nil
+	}
+	records, err := sealHistoryRecords(h.key, h.header, 0, h.entries)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomically(h.path, append(append([]byte{}, h.header...), records...)); err != nil {
+		return err
+	}
+	h.records = len(h.entries)
+	return nil
+}
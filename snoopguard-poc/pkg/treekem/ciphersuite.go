@@ -0,0 +1,173 @@
+package treekem
+
+import (
+	"chatbot-poc-go/pkg/util"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// CipherSuiteID identifies a CipherSuite. The values follow the MLS cipher suite registry. The zero value stands for
+// the default suite, so that groups created before cipher suites were negotiated keep working.
+type CipherSuiteID uint16
+
+const (
+	P256_AES128GCM_SHA256_P256             CipherSuiteID = 0x0002
+	X25519_CHACHA20POLY1305_SHA256_Ed25519 CipherSuiteID = 0x0003
+)
+
+// DefaultCipherSuite is the suite used when none is negotiated.
+const DefaultCipherSuite = P256_AES128GCM_SHA256_P256
+
+// CipherSuite is the set of primitives the TreeKEM, the MultiTreeKEM and the MlsMultiTree run with: the KEM and the
+// AEAD of ECKEM, the derivation of node key pairs from secrets, the hash, and the signature scheme of the node
+// signing keys. It also seals messages under the roots, as a util.MessageSuite.
+type CipherSuite interface {
+	ID() CipherSuiteID
+	Hash(data []byte) []byte
+	DeriveKeyPair(secret []byte) (*Keypair, error)
+	DeriveSigningKeyPair(secret []byte) (*SigningKeypair, error)
+	NewAEAD(key []byte) (cipher.AEAD, error)
+	Sign(message []byte, signPrivKey []byte) ([]byte, error)
+	Verify(message []byte, signature []byte, signPubKey []byte) bool
+
+	hpke() hpkeSuite
+}
+
+// SigningKeypair is a signing key pair of a CipherSuite, in the encoding of the suite.
+type SigningKeypair struct {
+	Public  []byte
+	Private []byte
+}
+
+// CipherSuiteByID returns the suite with the given ID, or an error if it is not supported.
+func CipherSuiteByID(id CipherSuiteID) (CipherSuite, error) {
+	switch id {
+	case 0, P256_AES128GCM_SHA256_P256:
+		return p256Suite{}, nil
+	case X25519_CHACHA20POLY1305_SHA256_Ed25519:
+		return x25519Suite{}, nil
+	}
+	return nil, fmt.Errorf("unsupported cipher suite 0x%04x", uint16(id))
+}
+
+// MustCipherSuite is CipherSuiteByID for suites known to be supported, such as those already negotiated.
+func MustCipherSuite(id CipherSuiteID) CipherSuite {
+	suite, err := CipherSuiteByID(id)
+	if err != nil {
+		panic(err)
+	}
+	return suite
+}
+
+// SupportedCipherSuites returns the IDs of the supported suites, in order of preference.
+func SupportedCipherSuites() []CipherSuiteID {
+	return []CipherSuiteID{P256_AES128GCM_SHA256_P256, X25519_CHACHA20POLY1305_SHA256_Ed25519}
+}
+
+// p256Suite is P-256 ECDH and ECDSA, SHA-256 and AES-GCM, which is what the CMRT originally hardcoded.
+type p256Suite struct{}
+
+func (p256Suite) ID() CipherSuiteID {
+	return P256_AES128GCM_SHA256_P256
+}
+
+func (p256Suite) Hash(data []byte) []byte {
+	return hash(data)
+}
+
+func (p256Suite) DeriveKeyPair(secret []byte) (*Keypair, error) {
+	return NewKeyPairFromSecret(secret)
+}
+
+func (p256Suite) DeriveSigningKeyPair(secret []byte) (*SigningKeypair, error) {
+	kp, err := NewSigningKeyPairFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKeypair{Public: kp.Public.Bytes(), Private: kp.Private.Bytes()}, nil
+}
+
+func (p256Suite) NewAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (p256Suite) Sign(message []byte, signPrivKey []byte) ([]byte, error) {
+	return util.Sign(message, signPrivKey)
+}
+
+func (p256Suite) Verify(message []byte, signature []byte, signPubKey []byte) bool {
+	return util.Verify(message, signature, signPubKey)
+}
+
+func (p256Suite) hpke() hpkeSuite {
+	return hpkeSuite{
+		kemID:   hpkeKEMP256,
+		aeadID:  hpkeAEADAES128GCM,
+		curve:   ecdh.P256(),
+		nk:      16,
+		newAEAD: p256Suite{}.NewAEAD,
+	}
+}
+
+// x25519Suite is X25519, Ed25519, SHA-256 and ChaCha20-Poly1305.
+type x25519Suite struct{}
+
+func (x25519Suite) ID() CipherSuiteID {
+	return X25519_CHACHA20POLY1305_SHA256_Ed25519
+}
+
+func (x25519Suite) Hash(data []byte) []byte {
+	return hash(data)
+}
+
+func (x25519Suite) DeriveKeyPair(secret []byte) (*Keypair, error) {
+	digest := sha256.Sum256(secret)
+	privateKey, err := ecdh.X25519().NewPrivateKey(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return &Keypair{Private: privateKey, Public: privateKey.PublicKey()}, nil
+}
+
+func (x25519Suite) DeriveSigningKeyPair(secret []byte) (*SigningKeypair, error) {
+	seed := sha256.Sum256(append([]byte("signing-"), secret...))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	return &SigningKeypair{Public: privateKey.Public().(ed25519.PublicKey), Private: privateKey}, nil
+}
+
+func (x25519Suite) NewAEAD(key []byte) (cipher.AEAD, error) {
+	return chacha20poly1305.New(key)
+}
+
+func (x25519Suite) Sign(message []byte, signPrivKey []byte) ([]byte, error) {
+	if len(signPrivKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key length %d", len(signPrivKey))
+	}
+	return ed25519.Sign(signPrivKey, message), nil
+}
+
+func (x25519Suite) Verify(message []byte, signature []byte, signPubKey []byte) bool {
+	if len(signPubKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(signPubKey, message, signature)
+}
+
+func (x25519Suite) hpke() hpkeSuite {
+	return hpkeSuite{
+		kemID:   hpkeKEMX25519,
+		aeadID:  hpkeAEADChaCha20Poly1305,
+		curve:   ecdh.X25519(),
+		nk:      chacha20poly1305.KeySize,
+		newAEAD: x25519Suite{}.NewAEAD,
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"crypto/ecdh"
 	"crypto/rand"
 	"crypto/sha256"
-	"io"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
 )
 
 type Keypair struct {
@@ -62,55 +65,129 @@ func SecretFromBytes(privateKey []byte, publicKey []byte) ([]byte, error) {
 	return sk.ECDH(pk)
 }
 
+const (
+	// ECKEMVersionLegacy is the original ECKEM, which uses the raw ECDH output as an AES-GCM key with no context.
+	// It is not bound to any context, so it is only decrypted with SetLegacyECKEMCompatibility and rejected with
+	// ErrLegacyECKEM otherwise.
+	ECKEMVersionLegacy uint32 = 0
+	// ECKEMVersionHPKE is HPKE base mode with the DHKEM, the KDF and the AEAD of the CipherSuite of the group, bound to
+	// an ECKEMContext.
+	ECKEMVersionHPKE uint32 = 1
+	// ECKEMVersionHybrid is HPKE base mode with the hybrid ML-KEM-768 + X25519 KEM, bound to an ECKEMContext. It is only
+	// used for the ciphertexts to and from the external nodes that opt in to it.
+	ECKEMVersionHybrid uint32 = 2
+)
+
+// ErrLegacyECKEM is returned when decrypting an ECKEMVersionLegacy ciphertext without SetLegacyECKEMCompatibility, as
+// it could be replayed in any group, epoch or node.
+var ErrLegacyECKEM = errors.New("legacy ECKEM ciphertexts are not accepted")
+
+var legacyECKEMCompatibility atomic.Bool
+
+// SetLegacyECKEMCompatibility sets whether ECKEMDecrypt decrypts ECKEMVersionLegacy ciphertexts, so that the state
+// stored and the messages sent by clients from before the switch to HPKE can still be read. It is off by default.
+func SetLegacyECKEMCompatibility(enabled bool) {
+	legacyECKEMCompatibility.Store(enabled)
+}
+
+// ExternalNodeIndex is the node index used in the ECKEMContext of ciphertexts to and from external nodes, which are
+// not part of the TreeKEM.
+const ExternalNodeIndex = -1
+
+// ECKEMCipherText is an ECKEM ciphertext. With ECKEMVersionHPKE, Public is the encapsulated key and IV is unused.
 type ECKEMCipherText struct {
+	Version    uint32
 	Public     []byte
 	IV         []byte
 	CipherText []byte
 }
 
-func ECKEMEncrypt(value []byte, publicKey []byte) (ECKEMCipherText, error) {
-	//return ECKEMCipherText{
-	//	Public:     nil,
-	//	IV:         nil,
-	//	CipherText: value,
-	//}, nil
+// ECKEMContext is what an ECKEM ciphertext is bound to, so that it cannot be replayed in another group, another
+// epoch, or to another node.
+type ECKEMContext struct {
+	GroupID   string
+	Epoch     uint64
+	NodeIndex int
+}
 
-	kpE, err := NewKeyPair()
-	if err != nil {
-		return ECKEMCipherText{}, err
-	}
-	ek, err := SecretFromBytes(kpE.Private.Bytes(), publicKey)
-	if err != nil {
-		return ECKEMCipherText{}, err
+// Bytes returns the encoding of the context used as both the HPKE info and the AEAD associated data.
+func (c ECKEMContext) Bytes() []byte {
+	b := []byte("snoopguard eckem")
+	b = binary.BigEndian.AppendUint32(b, uint32(len(c.GroupID)))
+	b = append(b, c.GroupID...)
+	b = binary.BigEndian.AppendUint64(b, c.Epoch)
+	b = binary.BigEndian.AppendUint32(b, uint32(int32(c.NodeIndex)))
+	return b
+}
+
+// ExternalNodeContext returns the context of ciphertexts to and from the external nodes of the group. External nodes
+// do not follow the epochs of the group, so the epoch is always zero.
+func ExternalNodeContext(groupID string) ECKEMContext {
+	return ECKEMContext{
+		GroupID:   groupID,
+		NodeIndex: ExternalNodeIndex,
 	}
+}
 
-	// AES GCM
-	block, err := aes.NewCipher(ek)
+// ECKEMEncrypt encrypts the value to the public key with HPKE base mode in the suite, bound to the context.
+func ECKEMEncrypt(suite CipherSuite, value []byte, publicKey []byte, context ECKEMContext) (ECKEMCipherText, error) {
+	info := context.Bytes()
+	enc, ctx, err := suite.hpke().setupBaseS(publicKey, info)
 	if err != nil {
 		return ECKEMCipherText{}, err
 	}
 
-	iv := make([]byte, 12)
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+	return ECKEMCipherText{
+		Version:    ECKEMVersionHPKE,
+		Public:     enc,
+		CipherText: ctx.Seal(info, value),
+	}, nil
+}
+
+// ECKEMEncryptDummy encrypts a random value of the given length to a freshly generated key pair whose private key
+// is discarded. The result is structurally identical to a real ECKEMEncrypt output but cannot be decrypted by anyone.
+func ECKEMEncryptDummy(suite CipherSuite, length int) (ECKEMCipherText, error) {
+	value, err := GenerateRandomBytes(length)
+	if err != nil {
 		return ECKEMCipherText{}, err
 	}
 
-	aesgcm, err := cipher.NewGCM(block)
+	kp, err := suite.hpke().curve.GenerateKey(rand.Reader)
 	if err != nil {
 		return ECKEMCipherText{}, err
 	}
 
-	cipherText := aesgcm.Seal(nil, iv, value, nil)
+	return ECKEMEncrypt(suite, value, kp.PublicKey().Bytes(), ECKEMContext{})
+}
 
-	return ECKEMCipherText{
-		Public:     kpE.Public.Bytes(),
-		IV:         iv,
-		CipherText: cipherText,
-	}, nil
+// ECKEMDecrypt decrypts the ciphertext with the private key in the suite. HPKE ciphertexts only decrypt under the
+// context they were encrypted with; legacy ciphertexts carry no context, only exist for P-256 and are only decrypted
+// with SetLegacyECKEMCompatibility.
+func ECKEMDecrypt(suite CipherSuite, ciphertext ECKEMCipherText, privateKey []byte, context ECKEMContext) ([]byte, error) {
+	switch ciphertext.Version {
+	case ECKEMVersionHPKE:
+		info := context.Bytes()
+		ctx, err := suite.hpke().setupBaseR(ciphertext.Public, privateKey, info)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.Open(info, ciphertext.CipherText)
+	case ECKEMVersionHybrid:
+		return hybridECKEMDecrypt(suite, ciphertext, privateKey, context)
+	case ECKEMVersionLegacy:
+		if !legacyECKEMCompatibility.Load() {
+			return nil, ErrLegacyECKEM
+		}
+		if suite.ID() != P256_AES128GCM_SHA256_P256 {
+			return nil, fmt.Errorf("legacy ECKEM is not defined for cipher suite 0x%04x", uint16(suite.ID()))
+		}
+		return eckemDecryptLegacy(ciphertext, privateKey)
+	}
+	return nil, fmt.Errorf("unknown ECKEM version %d", ciphertext.Version)
 }
 
-func ECKEMDecrypt(ciphertext ECKEMCipherText, privateKey []byte) ([]byte, error) {
-	//return ciphertext.CipherText, nil
+// eckemDecryptLegacy decrypts a ciphertext of ECKEMVersionLegacy.
+func eckemDecryptLegacy(ciphertext ECKEMCipherText, privateKey []byte) ([]byte, error) {
 	ek, err := SecretFromBytes(privateKey, ciphertext.Public)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,160 @@
+package treekem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// This file implements the epochs of the roots shared with the external nodes. Every update of a root, whether issued
+// by a member or by the external node, moves it to the next epoch and extends a running transcript hash over the roots
+// so far. Receivers use the epoch to tell a reordered update from a lost or forged one, and the transcript hash to
+// detect that they followed a different history than the sender.
+
+// ErrDesync is returned when a root update does not follow the local state of the root.
+var ErrDesync = errors.New("CMRT desync")
+
+// ErrStaleKeyUpdate is returned when a root update is for an epoch the root has already passed.
+var ErrStaleKeyUpdate = errors.New("stale key update")
+
+// ErrUndecryptableKeyUpdate is returned when the external node cannot decrypt the update of its root, e.g. because it
+// is a dummy sent to hide who a message triggers.
+var ErrUndecryptableKeyUpdate = errors.New("undecryptable key update")
+
+// DesyncError describes a root update that does not follow the local state of the root. If the update is ahead of the
+// local epoch, the updates before it are missing and it can be handled once they arrive. If the transcript hashes do
+// not match, the sender and the receiver followed different histories and the root has to be recovered.
+type DesyncError struct {
+	Epoch              uint64
+	ReceivedEpoch      uint64
+	TranscriptMismatch bool
+}
+
+// Error returns the description of the desync.
+func (e *DesyncError) Error() string {
+	if e.TranscriptMismatch {
+		return fmt.Sprintf("%v: transcript hash mismatch at epoch %v", ErrDesync, e.ReceivedEpoch)
+	}
+	return fmt.Sprintf("%v: received epoch %v at epoch %v", ErrDesync, e.ReceivedEpoch, e.Epoch)
+}
+
+// Is reports whether the target is ErrDesync.
+func (e *DesyncError) Is(target error) bool {
+	return target == ErrDesync
+}
+
+// RootEpoch is the epoch of a root shared with an external node and the transcript hash over its roots so far.
+type RootEpoch struct {
+	Epoch          uint64
+	TranscriptHash []byte
+}
+
+// initialRootEpoch returns the epoch of a root right after the external node joined.
+func initialRootEpoch(suite CipherSuite, rootPublic []byte) RootEpoch {
+	return RootEpoch{}.next(suite, rootPublic)
+}
+
+// next returns the epoch after the root is updated to the root with the given public key.
+func (e RootEpoch) next(suite CipherSuite, rootPublic []byte) RootEpoch {
+	epoch := e.Epoch + 1
+	if e.TranscriptHash == nil {
+		// The first root is epoch 0.
+		epoch = 0
+	}
+
+	transcript := []byte("snoopguard root transcript")
+	transcript = binary.BigEndian.AppendUint32(transcript, uint32(len(e.TranscriptHash)))
+	transcript = append(transcript, e.TranscriptHash...)
+	transcript = binary.BigEndian.AppendUint64(transcript, epoch)
+	transcript = append(transcript, rootPublic...)
+
+	return RootEpoch{Epoch: epoch, TranscriptHash: suite.Hash(transcript)}
+}
+
+// hiddenNext returns an epoch that looks the same as the one after an update of the root, for a root that was not
+// updated: the next epoch and a random transcript hash.
+func (e RootEpoch) hiddenNext() (RootEpoch, error) {
+	transcriptHash := make([]byte, len(e.TranscriptHash))
+	if _, err := rand.Read(transcriptHash); err != nil {
+		return RootEpoch{}, err
+	}
+	return RootEpoch{Epoch: e.Epoch + 1, TranscriptHash: transcriptHash}, nil
+}
+
+// hiddenEpochs returns the epochs and the transcript hashes of the roots of the given external nodes after a key update
+// of the roots of updatedIDs. The entries of the roots that were not updated are made with hiddenNext, so that the
+// entries do not tell which roots were updated.
+func hiddenEpochs(epochs map[string]RootEpoch, updatedIDs []string, ids []string) (map[string]uint64, map[string][]byte, error) {
+	epochMap := make(map[string]uint64)
+	transcriptHashes := make(map[string][]byte)
+	for _, id := range ids {
+		rootEpoch, ok := epochs[id]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(updatedIDs, id) {
+			var err error
+			if rootEpoch, err = rootEpoch.hiddenNext(); err != nil {
+				return nil, nil, err
+			}
+		}
+		epochMap[id] = rootEpoch.Epoch
+		transcriptHashes[id] = rootEpoch.TranscriptHash
+	}
+	return epochMap, transcriptHashes, nil
+}
+
+// check returns nil if an update to the given epoch is the next one, ErrStaleKeyUpdate if the root has already passed
+// it, or a DesyncError if updates before it are missing.
+func (e RootEpoch) check(epoch uint64) error {
+	switch {
+	case epoch == e.Epoch+1:
+		return nil
+	case epoch <= e.Epoch:
+		return fmt.Errorf("%w: received epoch %v at epoch %v", ErrStaleKeyUpdate, epoch, e.Epoch)
+	default:
+		return &DesyncError{Epoch: e.Epoch, ReceivedEpoch: epoch}
+	}
+}
+
+// advance returns the epoch after the root is updated to the root with the given public key, or a DesyncError if the
+// transcript hash of the sender does not match. A nil transcript hash is not checked.
+func (e RootEpoch) advance(suite CipherSuite, epoch uint64, transcriptHash []byte, rootPublic []byte) (RootEpoch, error) {
+	err := e.check(epoch)
+	if err != nil {
+		return RootEpoch{}, err
+	}
+
+	next := e.next(suite, rootPublic)
+	if transcriptHash != nil && !bytes.Equal(next.TranscriptHash, transcriptHash) {
+		return RootEpoch{}, &DesyncError{Epoch: e.Epoch, ReceivedEpoch: epoch, TranscriptMismatch: true}
+	}
+	return next, nil
+}
+
+// ExternalNodeRecovery is the state a member sends to an external node that lost track of its root, so that it can
+// resume at the current epoch.
+type ExternalNodeRecovery struct {
+	RootEpoch
+	RootSecret            []byte
+	TreeKEMRootPublic     []byte
+	TreeKEMRootSignPublic []byte
+}
+
+// recoveredRoot returns the root and its epoch from the recovery, checking that it does not go back in time.
+func (r ExternalNodeRecovery) recoveredRoot(suite CipherSuite, current RootEpoch) (Node, RootEpoch, error) {
+	if r.Epoch < current.Epoch {
+		return Node{}, RootEpoch{}, fmt.Errorf("%w: recovery to epoch %v at epoch %v", ErrStaleKeyUpdate, r.Epoch, current.Epoch)
+	}
+	root, err := rootNodeFromSecret(suite, r.RootSecret)
+	if err != nil {
+		return Node{}, RootEpoch{}, err
+	}
+	if len(root.Secret) == 0 || len(r.TranscriptHash) == 0 {
+		return Node{}, RootEpoch{}, errors.New("incomplete recovery")
+	}
+	return root, r.RootEpoch, nil
+}
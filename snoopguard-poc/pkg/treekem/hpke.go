@@ -0,0 +1,217 @@
+package treekem
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+// This file implements the base mode of HPKE (RFC 9180) with HKDF-SHA256, for the DHKEMs and AEADs of the supported
+// cipher suites.
+
+const (
+	hpkeKEMP256   uint16 = 0x0010
+	hpkeKEMX25519 uint16 = 0x0020
+	hpkeKDFID     uint16 = 0x0001
+
+	hpkeAEADAES128GCM        uint16 = 0x0001
+	hpkeAEADChaCha20Poly1305 uint16 = 0x0003
+
+	hpkeModeBase byte = 0x00
+
+	hpkeNsecret = 32
+	hpkeNn      = 12
+)
+
+var hpkeVersionLabel = []byte("HPKE-v1")
+
+// hpkeSuite is an HPKE suite with HKDF-SHA256 as the KDF.
+type hpkeSuite struct {
+	kemID   uint16
+	aeadID  uint16
+	curve   ecdh.Curve
+	nk      int
+	newAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+// hpkeContext is an HPKE encryption context, used to seal or open messages in order.
+type hpkeContext struct {
+	aead      cipher.AEAD
+	baseNonce []byte
+	seq       uint64
+}
+
+func (s hpkeSuite) kemSuiteID() []byte {
+	return binary.BigEndian.AppendUint16([]byte("KEM"), s.kemID)
+}
+
+func (s hpkeSuite) suiteID() []byte {
+	suiteID := binary.BigEndian.AppendUint16([]byte("HPKE"), s.kemID)
+	suiteID = binary.BigEndian.AppendUint16(suiteID, hpkeKDFID)
+	return binary.BigEndian.AppendUint16(suiteID, s.aeadID)
+}
+
+func hkdfExtract(salt, ikm []byte) []byte {
+	return hkdf.Extract(sha256.New, ikm, salt)
+}
+
+func hkdfExpand(prk, info []byte, length int) []byte {
+	out := make([]byte, length)
+	// HKDF only fails beyond 255*Nh bytes of output, far more than any HPKE label asks for.
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func labeledExtract(suiteID, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := append([]byte{}, hpkeVersionLabel...)
+	labeledIKM = append(labeledIKM, suiteID...)
+	labeledIKM = append(labeledIKM, label...)
+	labeledIKM = append(labeledIKM, ikm...)
+	return hkdfExtract(salt, labeledIKM)
+}
+
+func labeledExpand(suiteID, prk []byte, label string, info []byte, length int) []byte {
+	labeledInfo := binary.BigEndian.AppendUint16(nil, uint16(length))
+	labeledInfo = append(labeledInfo, hpkeVersionLabel...)
+	labeledInfo = append(labeledInfo, suiteID...)
+	labeledInfo = append(labeledInfo, label...)
+	labeledInfo = append(labeledInfo, info...)
+	return hkdfExpand(prk, labeledInfo, length)
+}
+
+// extractAndExpand derives the KEM shared secret from the DH output and the KEM context.
+func (s hpkeSuite) extractAndExpand(dh, kemContext []byte) []byte {
+	eaePRK := labeledExtract(s.kemSuiteID(), nil, "eae_prk", dh)
+	return labeledExpand(s.kemSuiteID(), eaePRK, "shared_secret", kemContext, hpkeNsecret)
+}
+
+// deriveKeyPair deterministically derives a KEM key pair from the input keying material.
+func (s hpkeSuite) deriveKeyPair(ikm []byte) (*ecdh.PrivateKey, error) {
+	dkpPRK := labeledExtract(s.kemSuiteID(), nil, "dkp_prk", ikm)
+	if s.curve == ecdh.X25519() {
+		return s.curve.NewPrivateKey(labeledExpand(s.kemSuiteID(), dkpPRK, "sk", nil, 32))
+	}
+	for counter := 0; counter < 256; counter++ {
+		bytes := labeledExpand(s.kemSuiteID(), dkpPRK, "candidate", []byte{byte(counter)}, 32)
+		if sk, err := s.curve.NewPrivateKey(bytes); err == nil {
+			return sk, nil
+		}
+	}
+	return nil, errors.New("hpke: cannot derive key pair")
+}
+
+// encap generates the shared secret and its encapsulation to pkR with the ephemeral key skE.
+func (s hpkeSuite) encap(pkR *ecdh.PublicKey, skE *ecdh.PrivateKey) ([]byte, []byte, error) {
+	dh, err := skE.ECDH(pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc := skE.PublicKey().Bytes()
+	kemContext := append(append([]byte{}, enc...), pkR.Bytes()...)
+	return s.extractAndExpand(dh, kemContext), enc, nil
+}
+
+// decap recovers the shared secret from its encapsulation with the recipient key skR.
+func (s hpkeSuite) decap(enc []byte, skR *ecdh.PrivateKey) ([]byte, error) {
+	pkE, err := s.curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := skR.ECDH(pkE)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(append([]byte{}, enc...), skR.PublicKey().Bytes()...)
+	return s.extractAndExpand(dh, kemContext), nil
+}
+
+// keySchedule derives the base mode encryption context from the shared secret and the info.
+func (s hpkeSuite) keySchedule(sharedSecret, info []byte) (*hpkeContext, error) {
+	suiteID := s.suiteID()
+	pskIDHash := labeledExtract(suiteID, nil, "psk_id_hash", nil)
+	infoHash := labeledExtract(suiteID, nil, "info_hash", info)
+	keyScheduleContext := append([]byte{hpkeModeBase}, pskIDHash...)
+	keyScheduleContext = append(keyScheduleContext, infoHash...)
+
+	secret := labeledExtract(suiteID, sharedSecret, "secret", nil)
+	key := labeledExpand(suiteID, secret, "key", keyScheduleContext, s.nk)
+	baseNonce := labeledExpand(suiteID, secret, "base_nonce", keyScheduleContext, hpkeNn)
+
+	aead, err := s.newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &hpkeContext{aead: aead, baseNonce: baseNonce}, nil
+}
+
+// setupBaseS sets up a base mode sender context to pkR, returning the encapsulated key and the context.
+func (s hpkeSuite) setupBaseS(pkR []byte, info []byte) ([]byte, *hpkeContext, error) {
+	skE, err := s.curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.setupBaseSWithEphemeral(pkR, info, skE)
+}
+
+// setupBaseSWithEphemeral is setupBaseS with a given ephemeral key, so that it can be checked against test vectors.
+func (s hpkeSuite) setupBaseSWithEphemeral(pkR []byte, info []byte, skE *ecdh.PrivateKey) ([]byte, *hpkeContext, error) {
+	pk, err := s.curve.NewPublicKey(pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedSecret, enc, err := s.encap(pk, skE)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := s.keySchedule(sharedSecret, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ctx, nil
+}
+
+// setupBaseR sets up a base mode recipient context from the encapsulated key and the recipient private key.
+func (s hpkeSuite) setupBaseR(enc []byte, skR []byte, info []byte) (*hpkeContext, error) {
+	sk, err := s.curve.NewPrivateKey(skR)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := s.decap(enc, sk)
+	if err != nil {
+		return nil, err
+	}
+	return s.keySchedule(sharedSecret, info)
+}
+
+func (c *hpkeContext) nonce() []byte {
+	nonce := make([]byte, hpkeNn)
+	binary.BigEndian.PutUint64(nonce[hpkeNn-8:], c.seq)
+	for i := range nonce {
+		nonce[i] ^= c.baseNonce[i]
+	}
+	return nonce
+}
+
+// Seal encrypts and authenticates the plaintext with the associated data, using the next sequence number.
+func (c *hpkeContext) Seal(aad, plaintext []byte) []byte {
+	ciphertext := c.aead.Seal(nil, c.nonce(), plaintext, aad)
+	c.seq++
+	return ciphertext
+}
+
+// Open decrypts and authenticates the ciphertext with the associated data, using the next sequence number.
+func (c *hpkeContext) Open(aad, ciphertext []byte) ([]byte, error) {
+	plaintext, err := c.aead.Open(nil, c.nonce(), ciphertext, aad)
+	if err != nil {
+		return nil, err
+	}
+	c.seq++
+	return plaintext, nil
+}
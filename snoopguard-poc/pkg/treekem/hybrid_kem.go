@@ -0,0 +1,205 @@
+package treekem
+
+import (
+	"crypto/ecdh"
+	"crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha3"
+	"errors"
+)
+
+// This file implements the hybrid KEM used for the ciphertexts to and from the external nodes that opt in to it. It
+// combines ML-KEM-768 (FIPS 203) with X25519 in the way of X-Wing, so that a ciphertext stays confidential as long as
+// either of the two holds, and plugs the combined shared secret into the HPKE key schedule with the AEAD of the suite.
+
+const (
+	// hpkeKEMXWing is the KEM ID of X-Wing, used in the suite ID of the HPKE key schedule of the hybrid KEM.
+	hpkeKEMXWing uint16 = 0x647a
+
+	hybridX25519Size = 32
+	// HybridPublicKeySize is the size of a hybrid public key: an ML-KEM-768 encapsulation key and an X25519 key.
+	HybridPublicKeySize = mlkem.EncapsulationKeySize768 + hybridX25519Size
+	// HybridPrivateKeySize is the size of a hybrid private key: an ML-KEM-768 seed and an X25519 key.
+	HybridPrivateKeySize = mlkem.SeedSize + hybridX25519Size
+	// HybridEncapsulationSize is the size of a hybrid encapsulation: an ML-KEM-768 ciphertext and an X25519 key.
+	HybridEncapsulationSize = mlkem.CiphertextSize768 + hybridX25519Size
+)
+
+// hybridLabel is the domain separator of the X-Wing combiner.
+var hybridLabel = []byte(`\.//^\`)
+
+// HybridKeypair is a hybrid ML-KEM-768 + X25519 key pair. Public is the ML-KEM encapsulation key followed by the
+// X25519 public key, and Private is the ML-KEM seed followed by the X25519 private key.
+type HybridKeypair struct {
+	Public  []byte
+	Private []byte
+}
+
+// hybridKEMSuiteID is the suite ID used to derive hybrid key pairs.
+func hybridKEMSuiteID() []byte {
+	return hpkeSuite{kemID: hpkeKEMXWing}.kemSuiteID()
+}
+
+// DeriveHybridKeyPair deterministically derives a hybrid key pair from the secret.
+func DeriveHybridKeyPair(secret []byte) (*HybridKeypair, error) {
+	prk := labeledExtract(hybridKEMSuiteID(), nil, "dkp_prk", secret)
+	private := labeledExpand(hybridKEMSuiteID(), prk, "sk", nil, HybridPrivateKeySize)
+
+	dk, skX, err := parseHybridPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	public := append(dk.EncapsulationKey().Bytes(), skX.PublicKey().Bytes()...)
+	return &HybridKeypair{Public: public, Private: private}, nil
+}
+
+// GenerateHybridKeyPair generates a random hybrid key pair.
+func GenerateHybridKeyPair() (*HybridKeypair, error) {
+	secret, err := GenerateRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	return DeriveHybridKeyPair(secret)
+}
+
+func parseHybridPrivateKey(private []byte) (*mlkem.DecapsulationKey768, *ecdh.PrivateKey, error) {
+	if len(private) != HybridPrivateKeySize {
+		return nil, nil, errors.New("hybrid kem: invalid private key size")
+	}
+	dk, err := mlkem.NewDecapsulationKey768(private[:mlkem.SeedSize])
+	if err != nil {
+		return nil, nil, err
+	}
+	skX, err := ecdh.X25519().NewPrivateKey(private[mlkem.SeedSize:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return dk, skX, nil
+}
+
+// hybridCombine derives the shared secret of the hybrid KEM from the shared secrets of both KEMs.
+func hybridCombine(ssM, ssX, ctX, pkX []byte) []byte {
+	input := append([]byte{}, ssM...)
+	input = append(input, ssX...)
+	input = append(input, ctX...)
+	input = append(input, pkX...)
+	input = append(input, hybridLabel...)
+	ss := sha3.Sum256(input)
+	return ss[:]
+}
+
+// hybridEncap generates the shared secret and its encapsulation to the hybrid public key.
+func hybridEncap(public []byte) ([]byte, []byte, error) {
+	if len(public) != HybridPublicKeySize {
+		return nil, nil, errors.New("hybrid kem: invalid public key size")
+	}
+	ek, err := mlkem.NewEncapsulationKey768(public[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, nil, err
+	}
+	pkX, err := ecdh.X25519().NewPublicKey(public[mlkem.EncapsulationKeySize768:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ssM, ctM := ek.Encapsulate()
+
+	skE, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	ssX, err := skE.ECDH(pkX)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctX := skE.PublicKey().Bytes()
+
+	return hybridCombine(ssM, ssX, ctX, pkX.Bytes()), append(ctM, ctX...), nil
+}
+
+// hybridDecap recovers the shared secret from its encapsulation with the hybrid private key.
+func hybridDecap(enc []byte, private []byte) ([]byte, error) {
+	if len(enc) != HybridEncapsulationSize {
+		return nil, errors.New("hybrid kem: invalid encapsulation size")
+	}
+	dk, skX, err := parseHybridPrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	ssM, err := dk.Decapsulate(enc[:mlkem.CiphertextSize768])
+	if err != nil {
+		return nil, err
+	}
+
+	ctX := enc[mlkem.CiphertextSize768:]
+	pkE, err := ecdh.X25519().NewPublicKey(ctX)
+	if err != nil {
+		return nil, err
+	}
+	ssX, err := skX.ECDH(pkE)
+	if err != nil {
+		return nil, err
+	}
+
+	return hybridCombine(ssM, ssX, ctX, skX.PublicKey().Bytes()), nil
+}
+
+// hybridHPKE returns the HPKE suite of the hybrid KEM with the AEAD of the cipher suite.
+func hybridHPKE(suite CipherSuite) hpkeSuite {
+	s := suite.hpke()
+	s.kemID = hpkeKEMXWing
+	return s
+}
+
+// HybridECKEMEncrypt encrypts the value to the hybrid public key with the hybrid KEM and the AEAD of the suite, bound to
+// the context.
+func HybridECKEMEncrypt(suite CipherSuite, value []byte, publicKey []byte, context ECKEMContext) (ECKEMCipherText, error) {
+	sharedSecret, enc, err := hybridEncap(publicKey)
+	if err != nil {
+		return ECKEMCipherText{}, err
+	}
+
+	info := context.Bytes()
+	ctx, err := hybridHPKE(suite).keySchedule(sharedSecret, info)
+	if err != nil {
+		return ECKEMCipherText{}, err
+	}
+
+	return ECKEMCipherText{
+		Version:    ECKEMVersionHybrid,
+		Public:     enc,
+		CipherText: ctx.Seal(info, value),
+	}, nil
+}
+
+// HybridECKEMEncryptDummy is ECKEMEncryptDummy for the hybrid KEM.
+func HybridECKEMEncryptDummy(suite CipherSuite, length int) (ECKEMCipherText, error) {
+	value, err := GenerateRandomBytes(length)
+	if err != nil {
+		return ECKEMCipherText{}, err
+	}
+
+	kp, err := GenerateHybridKeyPair()
+	if err != nil {
+		return ECKEMCipherText{}, err
+	}
+
+	return HybridECKEMEncrypt(suite, value, kp.Public, ECKEMContext{})
+}
+
+// hybridECKEMDecrypt decrypts the hybrid ciphertext with the hybrid private key.
+func hybridECKEMDecrypt(suite CipherSuite, ciphertext ECKEMCipherText, privateKey []byte, context ECKEMContext) ([]byte, error) {
+	sharedSecret, err := hybridDecap(ciphertext.Public, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	info := context.Bytes()
+	ctx, err := hybridHPKE(suite).keySchedule(sharedSecret, info)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Open(info, ciphertext.CipherText)
+}
@@ -0,0 +1,163 @@
+package treekem
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// This file implements the authentication of the TreeKEM key update packs. The sender signs a pack with its identity
+// key for the members and the chatbots without IGA, who know who sent it anyway. Chatbots with IGA must not learn the
+// sender, so each of them gets a MAC keyed by the root it shares with the group before the update instead, which any
+// member could have computed but the server cannot.
+
+// keyUpdateMACSize is the size of the MAC keys and the MACs over key update packs.
+const keyUpdateMACSize = sha256.Size
+
+// ErrUnauthenticatedKeyUpdate is returned when a key update pack has no valid signature or MAC.
+var ErrUnauthenticatedKeyUpdate = errors.New("unauthenticated key update")
+
+// KeyUpdatePackTBS returns the bytes the signature and the MACs over the key update pack are computed on: the group ID
+// and the pack without its signature and MACs.
+func KeyUpdatePackTBS(groupID string, pack *pb.TreeKEMKeyUpdatePack) ([]byte, error) {
+	unsigned := proto.Clone(pack).(*pb.TreeKEMKeyUpdatePack)
+	unsigned.Signature = nil
+	unsigned.ChatbotMACs = nil
+
+	packBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs := []byte("snoopguard key update")
+	tbs = binary.BigEndian.AppendUint32(tbs, uint32(len(groupID)))
+	tbs = append(tbs, groupID...)
+	return append(tbs, packBytes...), nil
+}
+
+// KeyUpdateMACKey derives the key of the MAC over key update packs from the root secret an external node shares with
+// the group.
+func KeyUpdateMACKey(suite CipherSuite, rootSecret []byte) []byte {
+	suiteID := suite.hpke().suiteID()
+	prk := labeledExtract(suiteID, nil, "update_mac_prk", rootSecret)
+	return labeledExpand(suiteID, prk, "update_mac", nil, keyUpdateMACSize)
+}
+
+// KeyUpdateMAC computes the MAC over the to-be-signed bytes of a key update pack.
+func KeyUpdateMAC(macKey []byte, tbs []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(tbs)
+	return mac.Sum(nil)
+}
+
+// VerifyKeyUpdateMAC checks the MAC over the to-be-signed bytes of a key update pack.
+func VerifyKeyUpdateMAC(macKey []byte, tbs []byte, mac []byte) error {
+	if len(macKey) == 0 || !hmac.Equal(KeyUpdateMAC(macKey, tbs), mac) {
+		return ErrUnauthenticatedKeyUpdate
+	}
+	return nil
+}
+
+// GetUpdateMACKeys returns the keys of the MACs over the next key update pack for the given external nodes, derived
+// from the roots they currently share with the group. It has to be called before the update is generated.
+func (m *MultiTreeKEM) GetUpdateMACKeys(ids []string) map[string][]byte {
+	macKeys := make(map[string][]byte)
+	for _, id := range ids {
+		if root, ok := m.roots[id]; ok && root.Secret != nil {
+			macKeys[id] = KeyUpdateMACKey(m.suite, root.Secret)
+		}
+	}
+	return macKeys
+}
+
+// GetUpdateMACKey returns the key of the MAC over the next key update pack, derived from the current root, or nil
+// without a root. It has to be called before the update is handled.
+func (m *MultiTreeKEMExternal) GetUpdateMACKey() []byte {
+	if m == nil || m.root.Secret == nil {
+		return nil
+	}
+	return KeyUpdateMACKey(m.suite, m.root.Secret)
+}
+
+// GetUpdateMACKeys returns the keys of the MACs over the next key update pack for the given external nodes, derived
+// from the roots they currently share with the group. It has to be called before the update is generated.
+func (m *MlsMultiTree) GetUpdateMACKeys(ids []string) map[string][]byte {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	macKeys := make(map[string][]byte)
+	for _, id := range ids {
+		if root, ok := m.roots[id]; ok && root.Secret != nil {
+			macKeys[id] = KeyUpdateMACKey(m.suite, root.Secret)
+		}
+	}
+	return macKeys
+}
+
+// GetUpdateMACKey returns the key of the MAC over the next key update pack, derived from the current root, or nil
+// without a root. It has to be called before the update is handled.
+func (m *MlsMultiTreeExternal) GetUpdateMACKey() []byte {
+	if m == nil || m.root.Secret == nil {
+		return nil
+	}
+	return KeyUpdateMACKey(m.suite, m.root.Secret)
+}
+
+// encodeExternalNodeJoinSecrets encodes the secrets a joining member needs for an external node: the treekem root the
+// external node encrypts its updates to, and the root shared with the external node with its epoch, which keys the
+// MACs over key update packs and orders them.
+func encodeExternalNodeJoinSecrets(lastTreeKemRootSecret []byte, rootSecret []byte, rootEpoch RootEpoch) []byte {
+	encoded := binary.BigEndian.AppendUint32(nil, uint32(len(lastTreeKemRootSecret)))
+	encoded = append(encoded, lastTreeKemRootSecret...)
+	encoded = binary.BigEndian.AppendUint32(encoded, uint32(len(rootSecret)))
+	encoded = append(encoded, rootSecret...)
+	encoded = binary.BigEndian.AppendUint64(encoded, rootEpoch.Epoch)
+	return append(encoded, rootEpoch.TranscriptHash...)
+}
+
+// decodeExternalNodeJoinSecrets decodes the secrets encoded by encodeExternalNodeJoinSecrets.
+func decodeExternalNodeJoinSecrets(encoded []byte) ([]byte, []byte, RootEpoch, error) {
+	malformed := errors.New("malformed external node join secrets")
+	var secrets [2][]byte
+	for i := range secrets {
+		if len(encoded) < 4 || uint64(len(encoded)-4) < uint64(binary.BigEndian.Uint32(encoded)) {
+			return nil, nil, RootEpoch{}, malformed
+		}
+		n := 4 + int(binary.BigEndian.Uint32(encoded))
+		secrets[i], encoded = encoded[4:n], encoded[n:]
+	}
+	if len(encoded) < 8 {
+		return nil, nil, RootEpoch{}, malformed
+	}
+	rootEpoch := RootEpoch{Epoch: binary.BigEndian.Uint64(encoded)}
+	if len(encoded) > 8 {
+		rootEpoch.TranscriptHash = encoded[8:]
+	}
+	return secrets[0], secrets[1], rootEpoch, nil
+}
+
+// rootNodeFromSecret derives the root shared with an external node from its secret, or returns an empty node if the
+// secret is unknown.
+func rootNodeFromSecret(suite CipherSuite, secret []byte) (Node, error) {
+	if len(secret) == 0 {
+		return Node{}, nil
+	}
+	kp, err := suite.DeriveKeyPair(secret)
+	if err != nil {
+		return Node{}, err
+	}
+	kpSign, err := suite.DeriveSigningKeyPair(secret)
+	if err != nil {
+		return Node{}, err
+	}
+	return Node{
+		Secret:      secret,
+		Public:      kp.Public.Bytes(),
+		Private:     kp.Private.Bytes(),
+		SignPublic:  kpSign.Public,
+		SignPrivate: kpSign.Private,
+	}, nil
+}
@@ -2,7 +2,9 @@ package treekem
 
 import (
 	"errors"
+	"fmt"
 	"github.com/s3131212/go-mls"
+	"sort"
 	"sync"
 )
 
@@ -12,23 +14,34 @@ type MlsMultiTree struct {
 	externalNodes map[string]Node
 	roots         map[string]Node
 	lastTreeRoots map[string]Node
+	epochs        map[string]RootEpoch
 	selfPubKey    []byte
 	selfPrivKey   []byte
+	groupID       string
+	suite         CipherSuite
 	mutexLock     sync.RWMutex
 }
 
-// NewMlsMultiTree creates a new MlsMultiTree object.
+// NewMlsMultiTree creates a new MlsMultiTree object with the default cipher suite.
 func NewMlsMultiTree(mlsState **mls.State) *MlsMultiTree {
+	return NewMlsMultiTreeWithCipherSuite(p256Suite{}, mlsState)
+}
+
+// NewMlsMultiTreeWithCipherSuite creates a new MlsMultiTree object with the given cipher suite. The suite only applies
+// to the external nodes; the MLS tree itself uses the cipher suite of the MLS group.
+func NewMlsMultiTreeWithCipherSuite(suite CipherSuite, mlsState **mls.State) *MlsMultiTree {
 	selfSecret := (*mlsState).GetSecrets().Keys.InitSecret
-	initKp, _ := NewKeyPairFromSecret(selfSecret)
+	initKp, _ := suite.DeriveKeyPair(selfSecret)
 
 	return &MlsMultiTree{
 		MlsState:      mlsState,
 		externalNodes: make(map[string]Node),
 		roots:         make(map[string]Node),
 		lastTreeRoots: make(map[string]Node),
+		epochs:        make(map[string]RootEpoch),
 		selfPubKey:    initKp.Public.Bytes(),
 		selfPrivKey:   initKp.Private.Bytes(),
+		suite:         suite,
 	}
 
 }
@@ -44,22 +57,22 @@ func (m *MlsMultiTree) AddExternalNode(id string, ct ECKEMCipherText) error {
 		return err
 	}
 
-	initLeaf, err := ECKEMDecrypt(ct, currentRoot.Private)
+	initLeaf, err := ECKEMDecrypt(m.suite, ct, currentRoot.Private, m.eckemContext())
 	if err != nil {
 		return err
 	}
-	initKp, _ := NewKeyPairFromSecret(initLeaf)
-	initKpSign, _ := NewSigningKeyPairFromSecret(initLeaf)
+	initKp, _ := m.suite.DeriveKeyPair(initLeaf)
+	initKpSign, _ := m.suite.DeriveSigningKeyPair(initLeaf)
 
 	m.mutexLock.Lock()
 	m.externalNodes[id] = Node{
 		Public:     initKp.Public.Bytes(),
-		SignPublic: initKpSign.Public.Bytes(),
+		SignPublic: initKpSign.Public,
 	}
 
-	rootSecret := hash(initLeaf)
-	rootKp, err := NewKeyPairFromSecret(rootSecret)
-	rootKpSign, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := m.suite.Hash(initLeaf)
+	rootKp, err := m.suite.DeriveKeyPair(rootSecret)
+	rootKpSign, err := m.suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		m.mutexLock.Unlock()
 		return err
@@ -69,9 +82,10 @@ func (m *MlsMultiTree) AddExternalNode(id string, ct ECKEMCipherText) error {
 		Secret:      rootSecret,
 		Public:      rootKp.Public.Bytes(),
 		Private:     rootKp.Private.Bytes(),
-		SignPublic:  rootKpSign.Public.Bytes(),
-		SignPrivate: rootKpSign.Private.Bytes(),
+		SignPublic:  rootKpSign.Public,
+		SignPrivate: rootKpSign.Private,
 	}
+	m.epochs[id] = initialRootEpoch(m.suite, m.roots[id].Public)
 
 	// Copy the current treekem root
 	m.lastTreeRoots[id] = currentRoot
@@ -80,6 +94,37 @@ func (m *MlsMultiTree) AddExternalNode(id string, ct ECKEMCipherText) error {
 	return nil
 }
 
+// RemoveExternalNode removes an external node from the multi-tree. The remaining external nodes may still share
+// their root secret with the removed one, so a UpdateTreeKEM covering all of them should follow a new MLS epoch.
+func (m *MlsMultiTree) RemoveExternalNode(id string) error {
+	m.mutexLock.Lock()
+	defer m.mutexLock.Unlock()
+
+	if _, ok := m.externalNodes[id]; !ok {
+		return errors.New("id does not exist")
+	}
+
+	delete(m.externalNodes, id)
+	delete(m.roots, id)
+	delete(m.lastTreeRoots, id)
+	delete(m.epochs, id)
+
+	return nil
+}
+
+// GetExternalNodeIDs returns the sorted IDs of all external nodes.
+func (m *MlsMultiTree) GetExternalNodeIDs() []string {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	ids := make([]string, 0, len(m.externalNodes))
+	for id := range m.externalNodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // GetExternalNodeJoin generates the external node join message.
 func (m *MlsMultiTree) GetExternalNodeJoin(id string) (ECKEMCipherText, []byte, error) {
 	currentRoot, err := m.GetTreeKEMRoot()
@@ -92,18 +137,18 @@ func (m *MlsMultiTree) GetExternalNodeJoin(id string) (ECKEMCipherText, []byte,
 		return ECKEMCipherText{}, nil, err
 	}
 
-	initKp, err := NewKeyPairFromSecret(initLeaf)
+	initKp, err := m.suite.DeriveKeyPair(initLeaf)
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
-	ct, err := ECKEMEncrypt(initLeaf, currentRoot.Public)
+	ct, err := ECKEMEncrypt(m.suite, initLeaf, currentRoot.Public, m.eckemContext())
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
 
-	rootSecret := hash(initLeaf)
-	rootKp, err := NewKeyPairFromSecret(rootSecret)
-	rootSignKp, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := m.suite.Hash(initLeaf)
+	rootKp, err := m.suite.DeriveKeyPair(rootSecret)
+	rootSignKp, err := m.suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
@@ -114,9 +159,10 @@ func (m *MlsMultiTree) GetExternalNodeJoin(id string) (ECKEMCipherText, []byte,
 		Secret:      rootSecret,
 		Public:      rootKp.Public.Bytes(),
 		Private:     rootKp.Private.Bytes(),
-		SignPublic:  rootSignKp.Public.Bytes(),
-		SignPrivate: rootSignKp.Private.Bytes(),
+		SignPublic:  rootSignKp.Public,
+		SignPrivate: rootSignKp.Private,
 	}
+	m.epochs[id] = initialRootEpoch(m.suite, m.roots[id].Public)
 	m.lastTreeRoots[id] = currentRoot
 	m.mutexLock.Unlock()
 
@@ -133,7 +179,8 @@ func (m *MlsMultiTree) GetExternalNodeJoinsWithoutUpdate(pubKey []byte) (map[str
 		chatbotPubKeys[id] = m.externalNodes[id].Public
 		chatbotSignPubKeys[id] = m.externalNodes[id].SignPublic
 
-		ct, err := ECKEMEncrypt(m.lastTreeRoots[id].Secret, pubKey)
+		// The root is sent along so that the joining member can authenticate its key updates to the external node.
+		ct, err := ECKEMEncrypt(m.suite, encodeExternalNodeJoinSecrets(m.lastTreeRoots[id].Secret, m.roots[id].Secret, m.epochs[id]), pubKey, m.eckemContext())
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -152,15 +199,25 @@ func (m *MlsMultiTree) SetExternalNodeJoinsWithoutUpdate(chatbotPubKeys map[stri
 			Public:     chatbotPubKey,
 			SignPublic: chatbotSignPubKeys[id],
 		}
-		m.roots[id] = Node{}
 
-		lastTreeKemRootSecret, err := ECKEMDecrypt(lastTreeKemRootCiphertexts[id], m.selfPrivKey)
+		joinSecrets, err := ECKEMDecrypt(m.suite, lastTreeKemRootCiphertexts[id], m.selfPrivKey, m.eckemContext())
+		if err != nil {
+			m.mutexLock.Unlock()
+			return err
+		}
+		lastTreeKemRootSecret, rootSecret, rootEpoch, err := decodeExternalNodeJoinSecrets(joinSecrets)
 		if err != nil {
 			m.mutexLock.Unlock()
 			return err
 		}
-		lastTreeKemRootKey, err := NewKeyPairFromSecret(lastTreeKemRootSecret)
-		lastTreeKemRootSignKey, err := NewSigningKeyPairFromSecret(lastTreeKemRootSecret)
+		m.roots[id], err = rootNodeFromSecret(m.suite, rootSecret)
+		if err != nil {
+			m.mutexLock.Unlock()
+			return err
+		}
+		m.epochs[id] = rootEpoch
+		lastTreeKemRootKey, err := m.suite.DeriveKeyPair(lastTreeKemRootSecret)
+		lastTreeKemRootSignKey, err := m.suite.DeriveSigningKeyPair(lastTreeKemRootSecret)
 		if err != nil {
 			m.mutexLock.Unlock()
 			return err
@@ -168,8 +225,8 @@ func (m *MlsMultiTree) SetExternalNodeJoinsWithoutUpdate(chatbotPubKeys map[stri
 		m.lastTreeRoots[id] = Node{
 			Public:      lastTreeKemRootKey.Public.Bytes(),
 			Private:     lastTreeKemRootKey.Private.Bytes(),
-			SignPublic:  lastTreeKemRootSignKey.Public.Bytes(),
-			SignPrivate: lastTreeKemRootSignKey.Private.Bytes(),
+			SignPublic:  lastTreeKemRootSignKey.Public,
+			SignPrivate: lastTreeKemRootSignKey.Private,
 			Secret:      lastTreeKemRootSecret,
 		}
 	}
@@ -181,8 +238,8 @@ func (m *MlsMultiTree) SetExternalNodeJoinsWithoutUpdate(chatbotPubKeys map[stri
 // GetTreeKEMRoot calculates the treekem root from the hash.
 func (m *MlsMultiTree) GetTreeKEMRoot() (Node, error) {
 	rootHash := (*m.MlsState).Tree.RootHash()
-	kp, err := NewKeyPairFromSecret(rootHash)
-	kpSign, err := NewSigningKeyPairFromSecret(rootHash)
+	kp, err := m.suite.DeriveKeyPair(rootHash)
+	kpSign, err := m.suite.DeriveSigningKeyPair(rootHash)
 	if err != nil {
 		return Node{}, err
 	}
@@ -190,8 +247,8 @@ func (m *MlsMultiTree) GetTreeKEMRoot() (Node, error) {
 		Secret:      rootHash,
 		Public:      kp.Public.Bytes(),
 		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+		SignPublic:  kpSign.Public,
+		SignPrivate: kpSign.Private,
 	}, nil
 }
 
@@ -204,7 +261,7 @@ func (m *MlsMultiTree) UpdateTreeKEM(externalNodeIds []string) (map[string]ECKEM
 	}
 
 	// Hash again for the multi-treekem
-	h := hash(currentRoot.Secret)
+	h := m.suite.Hash(currentRoot.Secret)
 	encs := make(map[string]ECKEMCipherText)
 	m.mutexLock.Lock()
 	//for id, node := range m.externalNodes {
@@ -214,15 +271,15 @@ func (m *MlsMultiTree) UpdateTreeKEM(externalNodeIds []string) (map[string]ECKEM
 			continue
 		}
 
-		enc, err := ECKEMEncrypt(h, node.Public)
+		enc, err := ECKEMEncrypt(m.suite, h, node.Public, m.eckemContext())
 		if err != nil {
 			m.mutexLock.Unlock()
 			return nil, nil, nil, err
 		}
 		encs[id] = enc
 
-		kp, err := NewKeyPairFromSecret(h)
-		kpSign, err := NewSigningKeyPairFromSecret(h)
+		kp, err := m.suite.DeriveKeyPair(h)
+		kpSign, err := m.suite.DeriveSigningKeyPair(h)
 		if err != nil {
 			m.mutexLock.Unlock()
 			return nil, nil, nil, err
@@ -231,9 +288,10 @@ func (m *MlsMultiTree) UpdateTreeKEM(externalNodeIds []string) (map[string]ECKEM
 			Secret:      h,
 			Public:      kp.Public.Bytes(),
 			Private:     kp.Private.Bytes(),
-			SignPublic:  kpSign.Public.Bytes(),
-			SignPrivate: kpSign.Private.Bytes(),
+			SignPublic:  kpSign.Public,
+			SignPrivate: kpSign.Private,
 		}
+		m.epochs[id] = m.epochs[id].next(m.suite, m.roots[id].Public)
 		m.lastTreeRoots[id] = currentRoot
 	}
 	m.mutexLock.Unlock()
@@ -249,21 +307,25 @@ func (m *MlsMultiTree) HandleTreeKEMUpdate(externalNodeIds []string) error {
 	}
 
 	// Update the roots
-	h := hash(currentRoot.Secret)
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	h := m.suite.Hash(currentRoot.Secret)
+	kp, err := m.suite.DeriveKeyPair(h)
+	kpSign, err := m.suite.DeriveSigningKeyPair(h)
 	if err != nil {
 		return err
 	}
 	m.mutexLock.Lock()
 	for _, externalNodeId := range externalNodeIds {
+		if _, exist := m.externalNodes[externalNodeId]; !exist {
+			continue
+		}
 		m.roots[externalNodeId] = Node{
 			Secret:      h,
 			Public:      kp.Public.Bytes(),
 			Private:     kp.Private.Bytes(),
-			SignPublic:  kpSign.Public.Bytes(),
-			SignPrivate: kpSign.Private.Bytes(),
+			SignPublic:  kpSign.Public,
+			SignPrivate: kpSign.Private,
 		}
+		m.epochs[externalNodeId] = m.epochs[externalNodeId].next(m.suite, m.roots[externalNodeId].Public)
 		m.lastTreeRoots[externalNodeId] = currentRoot
 	}
 	m.mutexLock.Unlock()
@@ -273,37 +335,114 @@ func (m *MlsMultiTree) HandleTreeKEMUpdate(externalNodeIds []string) error {
 
 // HandleExternalNodeUpdate handles the update message from the external node.
 func (m *MlsMultiTree) HandleExternalNodeUpdate(id string, updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
+	return m.HandleExternalNodeUpdateAtEpoch(id, updateMessage, newPubKey, newSignPubKey, m.GetEpoch(id)+1, nil)
+}
+
+// HandleExternalNodeUpdateAtEpoch handles the update message from the external node, which moves its root to the given
+// epoch with the given transcript hash. A nil transcript hash is not checked.
+func (m *MlsMultiTree) HandleExternalNodeUpdateAtEpoch(id string, updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	m.mutexLock.Lock()
+	defer m.mutexLock.Unlock()
+
 	if _, ok := m.externalNodes[id]; !ok {
 		return errors.New("id does not exist")
 	}
+	err := m.epochs[id].check(epoch)
+	if err != nil {
+		return err
+	}
 
-	h, err := ECKEMDecrypt(updateMessage, m.lastTreeRoots[id].Private)
+	h, err := ECKEMDecrypt(m.suite, updateMessage, m.lastTreeRoots[id].Private, m.eckemContext())
 	if err != nil {
 		return err
 	}
 
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	root, err := rootNodeFromSecret(m.suite, h)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	m.mutexLock.Lock()
-	m.roots[id] = Node{
-		Secret:      h,
-		Public:      kp.Public.Bytes(),
-		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+	rootEpoch, err := m.epochs[id].advance(m.suite, epoch, transcriptHash, root.Public)
+	if err != nil {
+		return err
 	}
+	m.roots[id] = root
+	m.epochs[id] = rootEpoch
 	m.externalNodes[id] = Node{
 		Public:     newPubKey,
 		SignPublic: newSignPubKey,
 	}
-	m.mutexLock.Unlock()
 
 	return nil
 }
 
+// GetEpoch returns the epoch of the root shared with the external node.
+func (m *MlsMultiTree) GetEpoch(id string) uint64 {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+	return m.epochs[id].Epoch
+}
+
+// GetTranscriptHash returns the transcript hash over the roots shared with the external node so far.
+func (m *MlsMultiTree) GetTranscriptHash(id string) []byte {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+	return m.epochs[id].TranscriptHash
+}
+
+// GetEpochs returns the epochs and the transcript hashes of the roots shared with the given external nodes.
+func (m *MlsMultiTree) GetEpochs(ids []string) (map[string]uint64, map[string][]byte) {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	epochs := make(map[string]uint64)
+	transcriptHashes := make(map[string][]byte)
+	for _, id := range ids {
+		if rootEpoch, ok := m.epochs[id]; ok {
+			epochs[id] = rootEpoch.Epoch
+			transcriptHashes[id] = rootEpoch.TranscriptHash
+		}
+	}
+	return epochs, transcriptHashes
+}
+
+// GetHiddenEpochs returns the epochs and the transcript hashes of the roots shared with the given external nodes after a
+// key update of the roots of updatedIDs. The other roots get an entry that looks the same as the one of an updated
+// root, so that a key update pack sent to hide who a message triggers does not tell it.
+func (m *MlsMultiTree) GetHiddenEpochs(updatedIDs []string, ids []string) (map[string]uint64, map[string][]byte, error) {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+	return hiddenEpochs(m.epochs, updatedIDs, ids)
+}
+
+// CheckEpoch returns nil if an update of the root shared with the external node to the given epoch is the next one,
+// ErrStaleKeyUpdate if the root has already passed it, or a DesyncError if updates before it are missing.
+func (m *MlsMultiTree) CheckEpoch(id string, epoch uint64) error {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+	return m.epochs[id].check(epoch)
+}
+
+// GetExternalNodeRecovery returns the current state of the root shared with the external node, for the external node
+// to resume from after it lost track of it.
+func (m *MlsMultiTree) GetExternalNodeRecovery(id string) (ExternalNodeRecovery, error) {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	if _, ok := m.externalNodes[id]; !ok {
+		return ExternalNodeRecovery{}, errors.New("id does not exist")
+	}
+	if len(m.roots[id].Secret) == 0 {
+		return ExternalNodeRecovery{}, errors.New("root is unknown")
+	}
+
+	return ExternalNodeRecovery{
+		RootEpoch:             m.epochs[id],
+		RootSecret:            m.roots[id].Secret,
+		TreeKEMRootPublic:     m.lastTreeRoots[id].Public,
+		TreeKEMRootSignPublic: m.lastTreeRoots[id].SignPublic,
+	}, nil
+}
+
 // GetRootSecret returns the secret of the root node.
 func (m *MlsMultiTree) GetRootSecret(id string) []byte {
 	m.mutexLock.RLock()
@@ -337,19 +476,37 @@ func (m *MlsMultiTree) GetExternalNode(id string) Node {
 	return m.externalNodes[id]
 }
 
+// SetGroupID sets the group ID the ciphertexts to and from the external nodes are bound to.
+func (m *MlsMultiTree) SetGroupID(groupID string) {
+	m.groupID = groupID
+}
+
+// eckemContext returns the context of ciphertexts to and from the external nodes.
+func (m *MlsMultiTree) eckemContext() ECKEMContext {
+	return ExternalNodeContext(m.groupID)
+}
+
 // MlsMultiTreeExternal is a multi TreeKEM for the external node.
 type MlsMultiTreeExternal struct {
 	treekemRoot Node
 	selfNode    Node
 	root        Node
+	epoch       RootEpoch
+	groupID     string
+	suite       CipherSuite
 }
 
-// NewMlsMultiTreeExternal creates a new MlsMultiTreeExternal object.
+// NewMlsMultiTreeExternal creates a new MlsMultiTreeExternal object with the default cipher suite.
 func NewMlsMultiTreeExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte) *MlsMultiTreeExternal {
+	return NewMlsMultiTreeExternalWithCipherSuite(p256Suite{}, treekemRootPub, treekemRootSignPub, initLeaf)
+}
+
+// NewMlsMultiTreeExternalWithCipherSuite creates a new MlsMultiTreeExternal object with the given cipher suite.
+func NewMlsMultiTreeExternalWithCipherSuite(suite CipherSuite, treekemRootPub, treekemRootSignPub, initLeaf []byte) *MlsMultiTreeExternal {
 	treekemRoot := Node{Public: treekemRootPub, SignPublic: treekemRootSignPub}
 
-	kpSelf, err := NewKeyPairFromSecret(initLeaf)
-	kpSelfSign, err := NewSigningKeyPairFromSecret(initLeaf)
+	kpSelf, err := suite.DeriveKeyPair(initLeaf)
+	kpSelfSign, err := suite.DeriveSigningKeyPair(initLeaf)
 	if err != nil {
 		panic(err)
 	}
@@ -357,13 +514,13 @@ func NewMlsMultiTreeExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte
 		Secret:      initLeaf,
 		Public:      kpSelf.Public.Bytes(),
 		Private:     kpSelf.Private.Bytes(),
-		SignPublic:  kpSelfSign.Public.Bytes(),
-		SignPrivate: kpSelfSign.Private.Bytes(),
+		SignPublic:  kpSelfSign.Public,
+		SignPrivate: kpSelfSign.Private,
 	}
 
-	rootSecret := hash(initLeaf)
-	kpRoot, err := NewKeyPairFromSecret(rootSecret)
-	kpRootSign, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := suite.Hash(initLeaf)
+	kpRoot, err := suite.DeriveKeyPair(rootSecret)
+	kpRootSign, err := suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		panic(err)
 	}
@@ -371,17 +528,29 @@ func NewMlsMultiTreeExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte
 		Secret:      rootSecret,
 		Public:      kpRoot.Public.Bytes(),
 		Private:     kpRoot.Private.Bytes(),
-		SignPublic:  kpRootSign.Public.Bytes(),
-		SignPrivate: kpRootSign.Private.Bytes(),
+		SignPublic:  kpRootSign.Public,
+		SignPrivate: kpRootSign.Private,
 	}
 
 	return &MlsMultiTreeExternal{
 		treekemRoot: treekemRoot,
 		selfNode:    selfNode,
 		root:        rootNode,
+		epoch:       initialRootEpoch(suite, rootNode.Public),
+		suite:       suite,
 	}
 }
 
+// SetGroupID sets the group ID the ciphertexts to and from the tree are bound to.
+func (m *MlsMultiTreeExternal) SetGroupID(groupID string) {
+	m.groupID = groupID
+}
+
+// eckemContext returns the context of ciphertexts to and from the tree.
+func (m *MlsMultiTreeExternal) eckemContext() ECKEMContext {
+	return ExternalNodeContext(m.groupID)
+}
+
 // UpdateExternalNode issue an external node update.
 func (m *MlsMultiTreeExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []byte, error) {
 	newLeaf, err := GenerateRandomBytes(32)
@@ -389,8 +558,8 @@ func (m *MlsMultiTreeExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []
 		return ECKEMCipherText{}, nil, nil, err
 	}
 
-	kpSelf, err := NewKeyPairFromSecret(newLeaf)
-	kpSelfSign, err := NewSigningKeyPairFromSecret(newLeaf)
+	kpSelf, err := m.suite.DeriveKeyPair(newLeaf)
+	kpSelfSign, err := m.suite.DeriveSigningKeyPair(newLeaf)
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
@@ -398,24 +567,25 @@ func (m *MlsMultiTreeExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []
 		Secret:      newLeaf,
 		Public:      kpSelf.Public.Bytes(),
 		Private:     kpSelf.Private.Bytes(),
-		SignPublic:  kpSelfSign.Public.Bytes(),
-		SignPrivate: kpSelfSign.Private.Bytes(),
+		SignPublic:  kpSelfSign.Public,
+		SignPrivate: kpSelfSign.Private,
 	}
 
-	kpRoot, err := NewKeyPairFromSecret(hash(newLeaf))
-	kpRootSign, err := NewSigningKeyPairFromSecret(hash(newLeaf))
+	kpRoot, err := m.suite.DeriveKeyPair(m.suite.Hash(newLeaf))
+	kpRootSign, err := m.suite.DeriveSigningKeyPair(m.suite.Hash(newLeaf))
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
 	m.root = Node{
-		Secret:      hash(newLeaf),
+		Secret:      m.suite.Hash(newLeaf),
 		Public:      kpRoot.Public.Bytes(),
 		Private:     kpRoot.Private.Bytes(),
-		SignPublic:  kpRootSign.Public.Bytes(),
-		SignPrivate: kpRootSign.Private.Bytes(),
+		SignPublic:  kpRootSign.Public,
+		SignPrivate: kpRootSign.Private,
 	}
+	m.epoch = m.epoch.next(m.suite, m.root.Public)
 
-	enc, err := ECKEMEncrypt(hash(newLeaf), m.treekemRoot.Public)
+	enc, err := ECKEMEncrypt(m.suite, m.suite.Hash(newLeaf), m.treekemRoot.Public, m.eckemContext())
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
@@ -425,32 +595,70 @@ func (m *MlsMultiTreeExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []
 
 // HandleTreeKEMUpdate handles the treekem user update.
 func (m *MlsMultiTreeExternal) HandleTreeKEMUpdate(updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
-	h, err := ECKEMDecrypt(updateMessage, m.selfNode.Private)
+	return m.HandleTreeKEMUpdateAtEpoch(updateMessage, newPubKey, newSignPubKey, m.epoch.Epoch+1, nil)
+}
+
+// HandleTreeKEMUpdateAtEpoch handles the treekem user update, which moves the root to the given epoch with the given
+// transcript hash. A nil transcript hash is not checked.
+func (m *MlsMultiTreeExternal) HandleTreeKEMUpdateAtEpoch(updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	err := m.epoch.check(epoch)
 	if err != nil {
 		return err
 	}
 
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	h, err := ECKEMDecrypt(m.suite, updateMessage, m.selfNode.Private, m.eckemContext())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUndecryptableKeyUpdate, err)
+	}
+
+	root, err := rootNodeFromSecret(m.suite, h)
 	if err != nil {
 		return err
 	}
-	m.root = Node{
-		Secret:      h,
-		Public:      kp.Public.Bytes(),
-		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+	rootEpoch, err := m.epoch.advance(m.suite, epoch, transcriptHash, root.Public)
+	if err != nil {
+		return err
 	}
+	m.root = root
+	m.epoch = rootEpoch
 	m.treekemRoot = Node{Public: newPubKey, SignPublic: newSignPubKey}
 
 	return nil
 }
 
+// CheckEpoch returns nil if an update to the given epoch is the next one, ErrStaleKeyUpdate if the root has already
+// passed it, or a DesyncError if updates before it are missing.
+func (m *MlsMultiTreeExternal) CheckEpoch(epoch uint64) error {
+	return m.epoch.check(epoch)
+}
+
+// GetEpoch returns the epoch of the root.
+func (m *MlsMultiTreeExternal) GetEpoch() uint64 {
+	return m.epoch.Epoch
+}
+
+// GetTranscriptHash returns the transcript hash over the roots so far.
+func (m *MlsMultiTreeExternal) GetTranscriptHash() []byte {
+	return m.epoch.TranscriptHash
+}
+
+// Recover resumes the root at the state sent by a member after the external node lost track of it.
+func (m *MlsMultiTreeExternal) Recover(recovery ExternalNodeRecovery) error {
+	root, rootEpoch, err := recovery.recoveredRoot(m.suite, m.epoch)
+	if err != nil {
+		return err
+	}
+	m.root = root
+	m.epoch = rootEpoch
+	m.treekemRoot = Node{Public: recovery.TreeKEMRootPublic, SignPublic: recovery.TreeKEMRootSignPublic}
+
+	return nil
+}
+
 // UpdateRootFromHash calculates the treekem root from the hash.
 func (m *MlsMultiTreeExternal) UpdateRootFromHash(hash []byte) error {
-	kp, err := NewKeyPairFromSecret(hash)
-	kpSign, err := NewSigningKeyPairFromSecret(hash)
+	kp, err := m.suite.DeriveKeyPair(hash)
+	kpSign, err := m.suite.DeriveSigningKeyPair(hash)
 	if err != nil {
 		return err
 	}
@@ -458,8 +666,8 @@ func (m *MlsMultiTreeExternal) UpdateRootFromHash(hash []byte) error {
 		Secret:      hash,
 		Public:      kp.Public.Bytes(),
 		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+		SignPublic:  kpSign.Public,
+		SignPrivate: kpSign.Private,
 	}
 	return nil
 }
@@ -1,11 +1,13 @@
 package treekem
 
 import (
+	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/util"
 	"fmt"
 	"github.com/s3131212/go-mls"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"testing"
 )
 
@@ -15,7 +17,7 @@ type StateTest struct {
 	credentials   []mls.Credential
 	initPrivs     []mls.HPKEPrivateKey
 	keyPackages   []mls.KeyPackage
-	states        []mls.State
+	states        []*mls.State
 }
 
 func TestMlsMultiTree(t *testing.T) {
@@ -24,7 +26,7 @@ func TestMlsMultiTree(t *testing.T) {
 	// start with the group creator
 	s0, err := mls.NewEmptyState([]byte("test"), stateTest.initSecrets[0], stateTest.identityPrivs[0], stateTest.keyPackages[0])
 	require.Nil(t, err)
-	stateTest.states = append(stateTest.states, *s0)
+	stateTest.states = append(stateTest.states, s0)
 
 	// add proposals for rest of the participants
 	for i := 1; i < groupSize; i++ {
@@ -38,18 +40,18 @@ func TestMlsMultiTree(t *testing.T) {
 	secret := util.RandomBytes(32)
 	_, welcome, next, err := stateTest.states[0].Commit(secret)
 	require.Nil(t, err)
-	stateTest.states[0] = *next
+	stateTest.states[0] = next
 	// initialize the new joiners from the welcome
 	for i := 1; i < groupSize; i++ {
 		s, err := mls.NewJoinedState(stateTest.initSecrets[i], stateTest.identityPrivs[i:i+1], stateTest.keyPackages[i:i+1], *welcome)
 		require.Nil(t, err)
-		stateTest.states = append(stateTest.states, *s)
+		stateTest.states = append(stateTest.states, s)
 	}
 
 	// Verify that the states are all equivalent
 	for _, lhs := range stateTest.states {
 		for _, rhs := range stateTest.states {
-			require.True(t, lhs.Equals(rhs))
+			require.True(t, lhs.Equals(*rhs))
 		}
 	}
 
@@ -110,7 +112,7 @@ func TestMlsMultiTree(t *testing.T) {
 	// Have each member update and verify that others are consistent
 	for c, chatbot := range chatbots {
 		for i, mt1 := range mlsMultiTrees {
-			ct, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err := mt1.UpdateTreeKEM([]string{fmt.Sprintf("cb-%d", c)}, stateTest.states[i].Tree.RootHash())
+			ct, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err := mt1.UpdateTreeKEM([]string{fmt.Sprintf("cb-%d", c)})
 			assert.Nilf(t, err, "error creating user update: %s", err)
 
 			err = chatbot.HandleTreeKEMUpdate(ct[fmt.Sprintf("cb-%d", c)], newTreeKemRootPubKey, newTreeKemRootSignPubKey)
@@ -174,7 +176,7 @@ func TestMlsMultiTree(t *testing.T) {
 	secret = util.RandomBytes(32)
 	addCommit, welcome, next, err := stateTest.states[1].Commit(secret)
 	require.Nil(t, err)
-	stateTest.states[1] = *next
+	stateTest.states[1] = next
 
 	// original members handle the add commit
 	for i := 0; i < groupSize; i++ {
@@ -183,30 +185,30 @@ func TestMlsMultiTree(t *testing.T) {
 		}
 		next, err = stateTest.states[i].Handle(addCommit)
 		require.Nil(t, err)
-		stateTest.states[i] = *next
+		stateTest.states[i] = next
 	}
 
 	// initialize the new joiners from the welcome
 	for i := 0; i < groupSize2; i++ {
 		s, err := mls.NewJoinedState(stateTest2.initSecrets[i], stateTest2.identityPrivs[i:i+1], stateTest2.keyPackages[i:i+1], *welcome)
 		require.Nil(t, err)
-		stateTest2.states = append(stateTest2.states, *s)
+		stateTest2.states = append(stateTest2.states, s)
 	}
 
 	// Verify that the states are all equivalent
 	for _, lhs := range stateTest.states {
 		for _, rhs := range stateTest.states {
-			require.True(t, lhs.Equals(rhs))
+			require.True(t, lhs.Equals(*rhs))
 		}
 		for _, rhs := range stateTest2.states {
-			require.True(t, lhs.Equals(rhs))
+			require.True(t, lhs.Equals(*rhs))
 		}
 	}
 
 	// Set up another MlsMultiTree
 	mlsMultiTrees2 := make([]*MlsMultiTree, groupSize2)
 	for i := 0; i < groupSize2; i++ {
-		mlsMultiTrees2[i] = NewMlsMultiTree(stateTest2.states[i])
+		mlsMultiTrees2[i] = NewMlsMultiTree(&stateTest2.states[i])
 	}
 
 	// member 1 should also send the info of chatbots to the new joiners
@@ -250,6 +252,203 @@ func TestMlsMultiTree(t *testing.T) {
 	}
 }
 
+func TestMlsMultiTreeRemoveExternalNode(t *testing.T) {
+	groupSize := 3
+	stateTest := setup(t, groupSize)
+	s0, err := mls.NewEmptyState([]byte("test"), stateTest.initSecrets[0], stateTest.identityPrivs[0], stateTest.keyPackages[0])
+	require.Nil(t, err)
+	stateTest.states = append(stateTest.states, s0)
+	for i := 1; i < groupSize; i++ {
+		add, err := stateTest.states[0].Add(stateTest.keyPackages[i])
+		require.Nil(t, err)
+		_, err = stateTest.states[0].Handle(add)
+		require.Nil(t, err)
+	}
+	_, welcome, next, err := stateTest.states[0].Commit(util.RandomBytes(32))
+	require.Nil(t, err)
+	stateTest.states[0] = next
+	for i := 1; i < groupSize; i++ {
+		s, err := mls.NewJoinedState(stateTest.initSecrets[i], stateTest.identityPrivs[i:i+1], stateTest.keyPackages[i:i+1], *welcome)
+		require.Nil(t, err)
+		stateTest.states = append(stateTest.states, s)
+	}
+
+	mlsMultiTrees := make([]*MlsMultiTree, groupSize)
+	for i := 0; i < groupSize; i++ {
+		mlsMultiTrees[i] = NewMlsMultiTree(&stateTest.states[i])
+	}
+
+	// Member 0 adds two chatbots
+	chatbots := make([]*MlsMultiTreeExternal, 2)
+	for c := range chatbots {
+		cbct, initLeaf, err := mlsMultiTrees[0].GetExternalNodeJoin(fmt.Sprintf("cb-%d", c))
+		assert.Nilf(t, err, "error creating chatbot add: %s", err)
+		treekemRoot, _ := mlsMultiTrees[0].GetTreeKEMRoot()
+		chatbots[c] = NewMlsMultiTreeExternal(treekemRoot.Public, treekemRoot.SignPublic, initLeaf)
+		for _, mt := range mlsMultiTrees[1:] {
+			err := mt.AddExternalNode(fmt.Sprintf("cb-%d", c), cbct)
+			assert.Nilf(t, err, "error handling chatbot add: %s", err)
+		}
+	}
+
+	// An update covering both chatbots gives them the same root
+	ct, newRootPub, newRootSignPub, err := mlsMultiTrees[0].UpdateTreeKEM([]string{"cb-0", "cb-1"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	for c, chatbot := range chatbots {
+		err = chatbot.HandleTreeKEMUpdate(ct[fmt.Sprintf("cb-%d", c)], newRootPub, newRootSignPub)
+		assert.Nilf(t, err, "error updating chatbot: %s", err)
+	}
+	for _, mt := range mlsMultiTrees[1:] {
+		err = mt.HandleTreeKEMUpdate([]string{"cb-0", "cb-1"})
+		assert.Nilf(t, err, "error handling user update: %s", err)
+	}
+	assert.Equal(t, chatbots[0].GetRootSecret(), chatbots[1].GetRootSecret(), "chatbots updated together should share the root")
+
+	// Every member removes cb-1
+	for _, mt := range mlsMultiTrees {
+		err = mt.RemoveExternalNode("cb-1")
+		assert.Nilf(t, err, "error removing chatbot: %s", err)
+		assert.Equal(t, []string{"cb-0"}, mt.GetExternalNodeIDs(), "removed chatbot should not be an external node")
+		assert.Nil(t, mt.GetRootSecret("cb-1"), "removed chatbot should not have a root")
+	}
+	assert.NotNil(t, mlsMultiTrees[0].RemoveExternalNode("cb-1"), "removing a removed chatbot should fail")
+
+	// Member 0 commits a new epoch and forces an update of the remaining chatbots
+	commit, _, next, err := stateTest.states[0].Commit(util.RandomBytes(32))
+	require.Nil(t, err)
+	stateTest.states[0] = next
+	for i := 1; i < groupSize; i++ {
+		next, err = stateTest.states[i].Handle(commit)
+		require.Nil(t, err)
+		stateTest.states[i] = next
+	}
+	ct, newRootPub, newRootSignPub, err = mlsMultiTrees[0].UpdateTreeKEM(mlsMultiTrees[0].GetExternalNodeIDs())
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	assert.Equal(t, 1, len(ct), "the forced update should not be encrypted to the removed chatbot")
+	for _, mt := range mlsMultiTrees[1:] {
+		err = mt.HandleTreeKEMUpdate(mt.GetExternalNodeIDs())
+		assert.Nilf(t, err, "error handling user update: %s", err)
+	}
+
+	err = chatbots[0].HandleTreeKEMUpdate(ct["cb-0"], newRootPub, newRootSignPub)
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	for _, mt := range mlsMultiTrees {
+		assert.Equal(t, chatbots[0].GetRootSecret(), mt.GetRootSecret("cb-0"), "remaining chatbot is not consistent with member")
+		assert.NotEqual(t, chatbots[1].GetRootSecret(), mt.GetRootSecret("cb-0"), "removed chatbot should not know the new root")
+	}
+	assert.NotNil(t, chatbots[1].HandleTreeKEMUpdate(ct["cb-0"], newRootPub, newRootSignPub), "removed chatbot should not decrypt the forced update")
+
+	// Updates from the removed chatbot are rejected
+	chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbots[1].UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	assert.NotNil(t, mlsMultiTrees[1].HandleExternalNodeUpdate("cb-1", chatbotUpdate, newCbPubKey, newCbSignPubKey), "update from the removed chatbot should be rejected")
+}
+
+func TestMlsMultiTreeSerialization(t *testing.T) {
+	groupSize := 3
+	stateTest := setup(t, groupSize)
+	s0, err := mls.NewEmptyState([]byte("test"), stateTest.initSecrets[0], stateTest.identityPrivs[0], stateTest.keyPackages[0])
+	require.Nil(t, err)
+	stateTest.states = append(stateTest.states, s0)
+	for i := 1; i < groupSize; i++ {
+		add, err := stateTest.states[0].Add(stateTest.keyPackages[i])
+		require.Nil(t, err)
+		_, err = stateTest.states[0].Handle(add)
+		require.Nil(t, err)
+	}
+	_, welcome, next, err := stateTest.states[0].Commit(util.RandomBytes(32))
+	require.Nil(t, err)
+	stateTest.states[0] = next
+	for i := 1; i < groupSize; i++ {
+		s, err := mls.NewJoinedState(stateTest.initSecrets[i], stateTest.identityPrivs[i:i+1], stateTest.keyPackages[i:i+1], *welcome)
+		require.Nil(t, err)
+		stateTest.states = append(stateTest.states, s)
+	}
+
+	mlsMultiTrees := make([]*MlsMultiTree, groupSize)
+	for i := 0; i < groupSize; i++ {
+		mlsMultiTrees[i] = NewMlsMultiTree(&stateTest.states[i])
+		mlsMultiTrees[i].SetGroupID("group")
+	}
+
+	cbct, initLeaf, err := mlsMultiTrees[0].GetExternalNodeJoin("cb")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	treekemRoot, _ := mlsMultiTrees[0].GetTreeKEMRoot()
+	chatbot := NewMlsMultiTreeExternal(treekemRoot.Public, treekemRoot.SignPublic, initLeaf)
+	chatbot.SetGroupID("group")
+	for _, mt := range mlsMultiTrees[1:] {
+		err := mt.AddExternalNode("cb", cbct)
+		assert.Nilf(t, err, "error handling chatbot add: %s", err)
+	}
+
+	ct, newRootPub, newRootSignPub, err := mlsMultiTrees[1].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs, transcriptHashes := mlsMultiTrees[1].GetEpochs([]string{"cb"})
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(ct["cb"], newRootPub, newRootSignPub, epochs["cb"], transcriptHashes["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	for i, mt := range mlsMultiTrees {
+		if i != 1 {
+			err = mt.HandleTreeKEMUpdate([]string{"cb"})
+			assert.Nilf(t, err, "error handling user update: %s", err)
+		}
+	}
+
+	// Restore every member on top of its MLS state, and the chatbot on its own
+	for i, mt := range mlsMultiTrees {
+		data, err := mt.Marshal()
+		assert.Nilf(t, err, "error marshaling state: %s", err)
+		restored, err := UnmarshalMlsMultiTree(data, &stateTest.states[i])
+		assert.Nilf(t, err, "error unmarshaling state: %s", err)
+
+		root, restoredRoot := mt.GetRoots()["cb"], restored.GetRoots()["cb"]
+		externalNode, restoredExternalNode := mt.GetExternalNode("cb"), restored.GetExternalNode("cb")
+		assert.True(t, nodeEqual(&root, &restoredRoot), "restored root of member %d is not equal", i)
+		assert.True(t, nodeEqual(&externalNode, &restoredExternalNode), "restored chatbot of member %d is not equal", i)
+		assert.Equal(t, mt.GetRootSecret("cb"), restored.GetRootSecret("cb"), "restored root of member %d has another secret", i)
+		assert.Equal(t, mt.GetEpoch("cb"), restored.GetEpoch("cb"), "restored root of member %d has another epoch", i)
+		assert.Equal(t, mt.GetTranscriptHash("cb"), restored.GetTranscriptHash("cb"), "restored root of member %d has another transcript", i)
+		mlsMultiTrees[i] = restored
+	}
+	data, err := chatbot.Marshal()
+	assert.Nilf(t, err, "error marshaling state: %s", err)
+	restoredChatbot, err := UnmarshalMlsMultiTreeExternal(data)
+	assert.Nilf(t, err, "error unmarshaling state: %s", err)
+	selfNode, restoredSelfNode := chatbot.GetSelfNode(), restoredChatbot.GetSelfNode()
+	assert.True(t, nodeEqual(&selfNode, &restoredSelfNode), "restored chatbot has another key")
+	assert.Equal(t, chatbot.GetRootSecret(), restoredChatbot.GetRootSecret(), "restored chatbot has another root")
+	assert.Equal(t, chatbot.GetEpoch(), restoredChatbot.GetEpoch(), "restored chatbot has another epoch")
+	assert.Equal(t, chatbot.GetTranscriptHash(), restoredChatbot.GetTranscriptHash(), "restored chatbot has another transcript")
+	chatbot = restoredChatbot
+
+	// The restored states keep working, in both directions
+	ct, newRootPub, newRootSignPub, err = mlsMultiTrees[2].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs, transcriptHashes = mlsMultiTrees[2].GetEpochs([]string{"cb"})
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(ct["cb"], newRootPub, newRootSignPub, epochs["cb"], transcriptHashes["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	for i, mt := range mlsMultiTrees[:2] {
+		err = mt.HandleTreeKEMUpdate([]string{"cb"})
+		assert.Nilf(t, err, "error handling user update: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret("cb"), "chatbot is not consistent with restored member %d", i)
+	}
+
+	chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbot.UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	for i, mt := range mlsMultiTrees {
+		err = mt.HandleExternalNodeUpdateAtEpoch("cb", chatbotUpdate, newCbPubKey, newCbSignPubKey, chatbot.GetEpoch(), chatbot.GetTranscriptHash())
+		assert.Nilf(t, err, "error handling chatbot update: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret("cb"), "restored member %d is not consistent with chatbot", i)
+	}
+
+	// States written with an unknown version are rejected
+	data, _ = proto.Marshal(&pb.StoredMlsMultiTree{Version: StateSerializationVersion + 1})
+	_, err = UnmarshalMlsMultiTree(data, &stateTest.states[0])
+	assert.ErrorIs(t, err, ErrUnsupportedStateVersion, "unknown version should be rejected")
+	data, _ = proto.Marshal(&pb.StoredMlsMultiTreeExternal{Version: StateSerializationVersion + 1})
+	_, err = UnmarshalMlsMultiTreeExternal(data)
+	assert.ErrorIs(t, err, ErrUnsupportedStateVersion, "unknown version should be rejected")
+}
+
 func setup(t *testing.T, groupSize int) StateTest {
 	stateTest := StateTest{}
 	stateTest.keyPackages = make([]mls.KeyPackage, groupSize)
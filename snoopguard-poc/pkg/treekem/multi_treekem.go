@@ -2,6 +2,8 @@ package treekem
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 )
 
 // MultiTreeKEM is a struct that contains a treeKEM, the additional node, and waited-to-be-sent update message.
@@ -10,39 +12,113 @@ type MultiTreeKEM struct {
 	externalNodes    map[string]Node
 	roots            map[string]Node
 	lastTreeKemRoots map[string]Node
+	epochs           map[string]RootEpoch
+	hybridKEM        map[string]bool
+	suite            CipherSuite
 }
 
-// NewMultiTreeKEM creates a new MultiTreeKEM object.
+// NewMultiTreeKEM creates a new MultiTreeKEM object, which runs with the cipher suite of the treekem.
 func NewMultiTreeKEM(tk *TreeKEMState) *MultiTreeKEM {
 	return &MultiTreeKEM{
 		treekem:          tk,
+		suite:            tk.Suite(),
 		externalNodes:    make(map[string]Node),
 		roots:            make(map[string]Node),
 		lastTreeKemRoots: make(map[string]Node),
+		epochs:           make(map[string]RootEpoch),
+		hybridKEM:        make(map[string]bool),
 	}
 }
 
+// SetExternalNodeHybridKEM sets whether the ciphertexts to and from the external node use the hybrid ML-KEM-768 +
+// X25519 KEM. It has to be set before the external node joins.
+func (m *MultiTreeKEM) SetExternalNodeHybridKEM(id string, hybridKEM bool) {
+	m.hybridKEM[id] = hybridKEM
+}
+
+// IsExternalNodeHybridKEM returns whether the ciphertexts to and from the external node use the hybrid KEM.
+func (m *MultiTreeKEM) IsExternalNodeHybridKEM(id string) bool {
+	return m.hybridKEM[id]
+}
+
+// HasHybridKEMExternalNode returns whether any external node uses the hybrid KEM.
+func (m *MultiTreeKEM) HasHybridKEMExternalNode() bool {
+	for id := range m.externalNodes {
+		if m.hybridKEM[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTreeKEMRootHybridPublic returns the hybrid public key of the current treekem root, which the external nodes
+// using the hybrid KEM encrypt their updates to.
+func (m *MultiTreeKEM) GetTreeKEMRootHybridPublic() ([]byte, error) {
+	kp, err := DeriveHybridKeyPair(m.treekem.Nodes()[root(m.treekem.Size())].Secret)
+	if err != nil {
+		return nil, err
+	}
+	return kp.Public, nil
+}
+
+// externalNodePublic returns the public key of the external node with the given leaf secret.
+func (m *MultiTreeKEM) externalNodePublic(id string, leaf []byte) ([]byte, error) {
+	if m.hybridKEM[id] {
+		kp, err := DeriveHybridKeyPair(leaf)
+		if err != nil {
+			return nil, err
+		}
+		return kp.Public, nil
+	}
+
+	kp, err := m.suite.DeriveKeyPair(leaf)
+	if err != nil {
+		return nil, err
+	}
+	return kp.Public.Bytes(), nil
+}
+
+// encryptToExternalNode encrypts the value to the public key of the external node.
+func (m *MultiTreeKEM) encryptToExternalNode(id string, value []byte, publicKey []byte) (ECKEMCipherText, error) {
+	if m.hybridKEM[id] {
+		return HybridECKEMEncrypt(m.suite, value, publicKey, m.eckemContext())
+	}
+	return ECKEMEncrypt(m.suite, value, publicKey, m.eckemContext())
+}
+
+// EncryptDummyToExternalNode generates a ciphertext that cannot be told apart from a real ciphertext to the external
+// node but cannot be decrypted by anyone.
+func (m *MultiTreeKEM) EncryptDummyToExternalNode(id string, length int) (ECKEMCipherText, error) {
+	if m.hybridKEM[id] {
+		return HybridECKEMEncryptDummy(m.suite, length)
+	}
+	return ECKEMEncryptDummy(m.suite, length)
+}
+
 // AddExternalNode adds an external node to the multi-treekem.
 func (m *MultiTreeKEM) AddExternalNode(id string, ct ECKEMCipherText) error {
 	if _, ok := m.externalNodes[id]; ok {
 		return errors.New("id already exist")
 	}
 
-	initLeaf, err := ECKEMDecrypt(ct, m.treekem.Nodes()[root(m.treekem.Size())].Private)
+	initLeaf, err := ECKEMDecrypt(m.suite, ct, m.treekem.Nodes()[root(m.treekem.Size())].Private, m.eckemContext())
+	if err != nil {
+		return err
+	}
+	initPublic, err := m.externalNodePublic(id, initLeaf)
 	if err != nil {
 		return err
 	}
-	initKp, _ := NewKeyPairFromSecret(initLeaf)
-	initKpSign, _ := NewSigningKeyPairFromSecret(initLeaf)
+	initKpSign, _ := m.suite.DeriveSigningKeyPair(initLeaf)
 
 	m.externalNodes[id] = Node{
-		Public:     initKp.Public.Bytes(),
-		SignPublic: initKpSign.Public.Bytes(),
+		Public:     initPublic,
+		SignPublic: initKpSign.Public,
 	}
 
-	rootSecret := hash(initLeaf)
-	rootKp, err := NewKeyPairFromSecret(rootSecret)
-	rootKpSign, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := m.suite.Hash(initLeaf)
+	rootKp, err := m.suite.DeriveKeyPair(rootSecret)
+	rootKpSign, err := m.suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		return err
 	}
@@ -50,9 +126,10 @@ func (m *MultiTreeKEM) AddExternalNode(id string, ct ECKEMCipherText) error {
 		Secret:      rootSecret,
 		Public:      rootKp.Public.Bytes(),
 		Private:     rootKp.Private.Bytes(),
-		SignPublic:  rootKpSign.Public.Bytes(),
-		SignPrivate: rootKpSign.Private.Bytes(),
+		SignPublic:  rootKpSign.Public,
+		SignPrivate: rootKpSign.Private,
 	}
+	m.epochs[id] = initialRootEpoch(m.suite, m.roots[id].Public)
 
 	// Copy the current treekem root
 	m.lastTreeKemRoots[id] = Node{
@@ -66,6 +143,32 @@ func (m *MultiTreeKEM) AddExternalNode(id string, ct ECKEMCipherText) error {
 	return nil
 }
 
+// RemoveExternalNode removes an external node from the multi-treekem. The remaining external nodes may still share
+// their root secret with the removed one, so a UpdateTreeKEM covering all of them should follow.
+func (m *MultiTreeKEM) RemoveExternalNode(id string) error {
+	if _, ok := m.externalNodes[id]; !ok {
+		return errors.New("id does not exist")
+	}
+
+	delete(m.externalNodes, id)
+	delete(m.roots, id)
+	delete(m.lastTreeKemRoots, id)
+	delete(m.epochs, id)
+	delete(m.hybridKEM, id)
+
+	return nil
+}
+
+// GetExternalNodeIDs returns the sorted IDs of all external nodes.
+func (m *MultiTreeKEM) GetExternalNodeIDs() []string {
+	ids := make([]string, 0, len(m.externalNodes))
+	for id := range m.externalNodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // GetExternalNodeJoin generates the external node join message.
 func (m *MultiTreeKEM) GetExternalNodeJoin(id string) (ECKEMCipherText, []byte, error) {
 	initLeaf, err := GenerateRandomBytes(32)
@@ -73,30 +176,31 @@ func (m *MultiTreeKEM) GetExternalNodeJoin(id string) (ECKEMCipherText, []byte,
 		return ECKEMCipherText{}, nil, err
 	}
 
-	initKp, err := NewKeyPairFromSecret(initLeaf)
+	initPublic, err := m.externalNodePublic(id, initLeaf)
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
-	ct, err := ECKEMEncrypt(initLeaf, m.treekem.Nodes()[root(m.treekem.Size())].Public)
+	ct, err := ECKEMEncrypt(m.suite, initLeaf, m.treekem.Nodes()[root(m.treekem.Size())].Public, m.eckemContext())
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
 
-	rootSecret := hash(initLeaf)
-	rootKp, err := NewKeyPairFromSecret(rootSecret)
-	rootSignKp, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := m.suite.Hash(initLeaf)
+	rootKp, err := m.suite.DeriveKeyPair(rootSecret)
+	rootSignKp, err := m.suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		return ECKEMCipherText{}, nil, err
 	}
 
-	m.externalNodes[id] = Node{Public: initKp.Public.Bytes()}
+	m.externalNodes[id] = Node{Public: initPublic}
 	m.roots[id] = Node{
 		Secret:      rootSecret,
 		Public:      rootKp.Public.Bytes(),
 		Private:     rootKp.Private.Bytes(),
-		SignPublic:  rootSignKp.Public.Bytes(),
-		SignPrivate: rootSignKp.Private.Bytes(),
+		SignPublic:  rootSignKp.Public,
+		SignPrivate: rootSignKp.Private,
 	}
+	m.epochs[id] = initialRootEpoch(m.suite, m.roots[id].Public)
 	m.lastTreeKemRoots[id] = Node{
 		Public:      m.treekem.Nodes()[root(m.treekem.Size())].Public,
 		Private:     m.treekem.Nodes()[root(m.treekem.Size())].Private,
@@ -117,7 +221,8 @@ func (m *MultiTreeKEM) GetExternalNodeJoinsWithoutUpdate(pubKey []byte) (map[str
 		chatbotPubKeys[id] = m.externalNodes[id].Public
 		chatbotSignPubKeys[id] = m.externalNodes[id].SignPublic
 
-		ct, err := ECKEMEncrypt(m.lastTreeKemRoots[id].Secret, pubKey)
+		// The root is sent along so that the joining member can authenticate its key updates to the external node.
+		ct, err := ECKEMEncrypt(m.suite, encodeExternalNodeJoinSecrets(m.lastTreeKemRoots[id].Secret, m.roots[id].Secret, m.epochs[id]), pubKey, m.eckemContext())
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -134,22 +239,30 @@ func (m *MultiTreeKEM) SetExternalNodeJoinsWithoutUpdate(chatbotPubKeys map[stri
 			Public:     chatbotPubKey,
 			SignPublic: chatbotSignPubKeys[id],
 		}
-		m.roots[id] = Node{}
 
-		lastTreeKemRootSecret, err := ECKEMDecrypt(lastTreeKemRootCiphertexts[id], m.treekem.Self().Private)
+		joinSecrets, err := ECKEMDecrypt(m.suite, lastTreeKemRootCiphertexts[id], m.treekem.Self().Private, m.eckemContext())
+		if err != nil {
+			return err
+		}
+		lastTreeKemRootSecret, rootSecret, rootEpoch, err := decodeExternalNodeJoinSecrets(joinSecrets)
 		if err != nil {
 			return err
 		}
-		lastTreeKemRootKey, err := NewKeyPairFromSecret(lastTreeKemRootSecret)
-		lastTreeKemRootSignKey, err := NewSigningKeyPairFromSecret(lastTreeKemRootSecret)
+		m.roots[id], err = rootNodeFromSecret(m.suite, rootSecret)
+		if err != nil {
+			return err
+		}
+		m.epochs[id] = rootEpoch
+		lastTreeKemRootKey, err := m.suite.DeriveKeyPair(lastTreeKemRootSecret)
+		lastTreeKemRootSignKey, err := m.suite.DeriveSigningKeyPair(lastTreeKemRootSecret)
 		if err != nil {
 			return err
 		}
 		m.lastTreeKemRoots[id] = Node{
 			Public:      lastTreeKemRootKey.Public.Bytes(),
 			Private:     lastTreeKemRootKey.Private.Bytes(),
-			SignPublic:  lastTreeKemRootSignKey.Public.Bytes(),
-			SignPrivate: lastTreeKemRootSignKey.Private.Bytes(),
+			SignPublic:  lastTreeKemRootSignKey.Public,
+			SignPrivate: lastTreeKemRootSignKey.Private,
 			Secret:      lastTreeKemRootSecret,
 		}
 	}
@@ -170,20 +283,23 @@ func (m *MultiTreeKEM) UpdateTreeKEM(externalNodeIds []string) (*UserUpdate, map
 	rootNode := m.treekem.Nodes()[root(m.treekem.Size())]
 
 	// Hash again for the multi-treekem
-	h := hash(rootNode.Secret)
+	h := m.suite.Hash(rootNode.Secret)
 	encs := make(map[string]ECKEMCipherText)
 	//for id, node := range m.externalNodes {
 	for _, id := range externalNodeIds {
-		node := m.externalNodes[id]
+		node, exist := m.externalNodes[id]
+		if !exist {
+			continue
+		}
 
-		enc, err := ECKEMEncrypt(h, node.Public)
+		enc, err := m.encryptToExternalNode(id, h, node.Public)
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
 		encs[id] = enc
 
-		kp, err := NewKeyPairFromSecret(h)
-		kpSign, err := NewSigningKeyPairFromSecret(h)
+		kp, err := m.suite.DeriveKeyPair(h)
+		kpSign, err := m.suite.DeriveSigningKeyPair(h)
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
@@ -191,9 +307,10 @@ func (m *MultiTreeKEM) UpdateTreeKEM(externalNodeIds []string) (*UserUpdate, map
 			Secret:      h,
 			Public:      kp.Public.Bytes(),
 			Private:     kp.Private.Bytes(),
-			SignPublic:  kpSign.Public.Bytes(),
-			SignPrivate: kpSign.Private.Bytes(),
+			SignPublic:  kpSign.Public,
+			SignPrivate: kpSign.Private,
 		}
+		m.epochs[id] = m.epochs[id].next(m.suite, m.roots[id].Public)
 		m.lastTreeKemRoots[id] = Node{
 			Public:      m.treekem.Nodes()[root(m.treekem.Size())].Public,
 			Private:     m.treekem.Nodes()[root(m.treekem.Size())].Private,
@@ -211,20 +328,24 @@ func (m *MultiTreeKEM) HandleTreeKEMUpdate(userUpdate *UserUpdate, externalNodeI
 	m.treekem.HandleUpdate(*userUpdate)
 
 	// Update the roots
-	h := hash(m.treekem.Nodes()[root(m.treekem.Size())].Secret)
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	h := m.suite.Hash(m.treekem.Nodes()[root(m.treekem.Size())].Secret)
+	kp, err := m.suite.DeriveKeyPair(h)
+	kpSign, err := m.suite.DeriveSigningKeyPair(h)
 	if err != nil {
 		return err
 	}
 	for _, externalNodeId := range externalNodeIds {
+		if _, exist := m.externalNodes[externalNodeId]; !exist {
+			continue
+		}
 		m.roots[externalNodeId] = Node{
 			Secret:      h,
 			Public:      kp.Public.Bytes(),
 			Private:     kp.Private.Bytes(),
-			SignPublic:  kpSign.Public.Bytes(),
-			SignPrivate: kpSign.Private.Bytes(),
+			SignPublic:  kpSign.Public,
+			SignPrivate: kpSign.Private,
 		}
+		m.epochs[externalNodeId] = m.epochs[externalNodeId].next(m.suite, m.roots[externalNodeId].Public)
 		m.lastTreeKemRoots[externalNodeId] = Node{
 			Public:      m.treekem.Nodes()[root(m.treekem.Size())].Public,
 			Private:     m.treekem.Nodes()[root(m.treekem.Size())].Private,
@@ -237,29 +358,85 @@ func (m *MultiTreeKEM) HandleTreeKEMUpdate(userUpdate *UserUpdate, externalNodeI
 	return nil
 }
 
+// RemoveTreeKEMUser issues a treekem user remove, which blanks the direct path of the removed user and replaces the
+// root with a fresh one the removed user cannot derive. The remover applies it with HandleTreeKEMRemove like every other
+// member. The roots of the external nodes are derived from the old treekem root, so a UpdateTreeKEM covering all
+// external nodes should follow.
+func (m *MultiTreeKEM) RemoveTreeKEMUser(index int) (*UserRemove, error) {
+	if index < 0 || index >= m.treekem.Size() || index == m.treekem.Index() {
+		return nil, errors.New("invalid index")
+	}
+
+	newLeaf, err := GenerateRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	userRemove := m.treekem.Remove(newLeaf, index, map[int]*Node{})
+
+	// The copath is sent to the other members, so only the public part is kept.
+	copath := make(map[int]*Node)
+	for n, node := range userRemove.Copath {
+		copath[n] = &Node{
+			Public:     node.Public,
+			SignPublic: node.SignPublic,
+		}
+	}
+	userRemove.Copath = copath
+
+	return &userRemove, nil
+}
+
+// HandleTreeKEMRemove handles the UserRemove request from a member, including the remover itself.
+func (m *MultiTreeKEM) HandleTreeKEMRemove(userRemove *UserRemove) error {
+	if userRemove.Index < 0 || userRemove.Index >= m.treekem.Size() || userRemove.Index == m.treekem.Index() {
+		return errors.New("invalid index")
+	}
+
+	m.treekem.HandleRemove(*userRemove)
+	return nil
+}
+
 // HandleExternalNodeUpdate handles the update message from the external node.
 func (m *MultiTreeKEM) HandleExternalNodeUpdate(id string, updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
+	return m.HandleExternalNodeUpdateAtEpoch(id, updateMessage, newPubKey, newSignPubKey, m.epochs[id].Epoch+1, nil)
+}
+
+// HandleExternalNodeUpdateAtEpoch handles the update message from the external node, which moves its root to the given
+// epoch with the given transcript hash. A nil transcript hash is not checked.
+func (m *MultiTreeKEM) HandleExternalNodeUpdateAtEpoch(id string, updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
 	if _, ok := m.externalNodes[id]; !ok {
 		return errors.New("id does not exist")
 	}
+	err := m.epochs[id].check(epoch)
+	if err != nil {
+		return err
+	}
 
-	h, err := ECKEMDecrypt(updateMessage, m.lastTreeKemRoots[id].Private)
+	// The external node encrypts to the treekem root it learned last, or to its hybrid key if it uses the hybrid KEM.
+	privateKey := m.lastTreeKemRoots[id].Private
+	if m.hybridKEM[id] {
+		kp, err := DeriveHybridKeyPair(m.lastTreeKemRoots[id].Secret)
+		if err != nil {
+			return err
+		}
+		privateKey = kp.Private
+	}
+
+	h, err := ECKEMDecrypt(m.suite, updateMessage, privateKey, m.eckemContext())
 	if err != nil {
 		return err
 	}
 
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	root, err := rootNodeFromSecret(m.suite, h)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	m.roots[id] = Node{
-		Secret:      h,
-		Public:      kp.Public.Bytes(),
-		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+	rootEpoch, err := m.epochs[id].advance(m.suite, epoch, transcriptHash, root.Public)
+	if err != nil {
+		return err
 	}
+	m.roots[id] = root
+	m.epochs[id] = rootEpoch
 	m.externalNodes[id] = Node{
 		Public:     newPubKey,
 		SignPublic: newSignPubKey,
@@ -268,6 +445,70 @@ func (m *MultiTreeKEM) HandleExternalNodeUpdate(id string, updateMessage ECKEMCi
 	return nil
 }
 
+// GetEpoch returns the epoch of the root shared with the external node.
+func (m *MultiTreeKEM) GetEpoch(id string) uint64 {
+	return m.epochs[id].Epoch
+}
+
+// GetTranscriptHash returns the transcript hash over the roots shared with the external node so far.
+func (m *MultiTreeKEM) GetTranscriptHash(id string) []byte {
+	return m.epochs[id].TranscriptHash
+}
+
+// GetEpochs returns the epochs and the transcript hashes of the roots shared with the given external nodes.
+func (m *MultiTreeKEM) GetEpochs(ids []string) (map[string]uint64, map[string][]byte) {
+	epochs := make(map[string]uint64)
+	transcriptHashes := make(map[string][]byte)
+	for _, id := range ids {
+		if rootEpoch, ok := m.epochs[id]; ok {
+			epochs[id] = rootEpoch.Epoch
+			transcriptHashes[id] = rootEpoch.TranscriptHash
+		}
+	}
+	return epochs, transcriptHashes
+}
+
+// GetHiddenEpochs returns the epochs and the transcript hashes of the roots shared with the given external nodes after a
+// key update of the roots of updatedIDs. The other roots get an entry that looks the same as the one of an updated
+// root, so that a key update pack sent to hide who a message triggers does not tell it.
+func (m *MultiTreeKEM) GetHiddenEpochs(updatedIDs []string, ids []string) (map[string]uint64, map[string][]byte, error) {
+	return hiddenEpochs(m.epochs, updatedIDs, ids)
+}
+
+// CheckEpoch returns nil if an update of the root shared with the external node to the given epoch is the next one,
+// ErrStaleKeyUpdate if the root has already passed it, or a DesyncError if updates before it are missing.
+func (m *MultiTreeKEM) CheckEpoch(id string, epoch uint64) error {
+	return m.epochs[id].check(epoch)
+}
+
+// GetExternalNodeRecovery returns the current state of the root shared with the external node, for the external node
+// to resume from after it lost track of it.
+func (m *MultiTreeKEM) GetExternalNodeRecovery(id string) (ExternalNodeRecovery, error) {
+	if _, ok := m.externalNodes[id]; !ok {
+		return ExternalNodeRecovery{}, errors.New("id does not exist")
+	}
+	if len(m.roots[id].Secret) == 0 {
+		return ExternalNodeRecovery{}, errors.New("root is unknown")
+	}
+
+	// The external node encrypts its updates to the treekem root it learned last, or to its hybrid key.
+	treekemRootPublic := m.lastTreeKemRoots[id].Public
+	if m.hybridKEM[id] {
+		kp, err := DeriveHybridKeyPair(m.lastTreeKemRoots[id].Secret)
+		if err != nil {
+			return ExternalNodeRecovery{}, err
+		}
+		treekemRootPublic = kp.Public
+	}
+
+	return ExternalNodeRecovery{
+		RootEpoch:             m.epochs[id],
+		RootSecret:            m.roots[id].Secret,
+		TreeKEMRootPublic:     treekemRootPublic,
+		TreeKEMRootSignPublic: m.lastTreeKemRoots[id].SignPublic,
+	}, nil
+}
+
 // GetRootSecret returns the secret of the root node.
 func (m *MultiTreeKEM) GetRootSecret(id string) []byte {
 	return m.roots[id].Secret
@@ -298,19 +539,33 @@ func (m *MultiTreeKEM) GetExternalNode(id string) Node {
 	return m.externalNodes[id]
 }
 
+// eckemContext returns the context of ciphertexts to and from the external nodes.
+func (m *MultiTreeKEM) eckemContext() ECKEMContext {
+	return ExternalNodeContext(m.treekem.GroupID())
+}
+
 // MultiTreeKEMExternal is a multi TreeKEM for the external node.
 type MultiTreeKEMExternal struct {
 	treekemRoot Node
 	selfNode    Node
 	root        Node
+	epoch       RootEpoch
+	groupID     string
+	hybridKEM   bool
+	suite       CipherSuite
 }
 
-// NewMultiTreeKEMExternal creates a new MultiTreeKEMExternal object.
+// NewMultiTreeKEMExternal creates a new MultiTreeKEMExternal object with the default cipher suite.
 func NewMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte) *MultiTreeKEMExternal {
+	return NewMultiTreeKEMExternalWithCipherSuite(p256Suite{}, treekemRootPub, treekemRootSignPub, initLeaf)
+}
+
+// NewMultiTreeKEMExternalWithCipherSuite creates a new MultiTreeKEMExternal object with the given cipher suite.
+func NewMultiTreeKEMExternalWithCipherSuite(suite CipherSuite, treekemRootPub, treekemRootSignPub, initLeaf []byte) *MultiTreeKEMExternal {
 	treekemRoot := Node{Public: treekemRootPub, SignPublic: treekemRootSignPub}
 
-	kpSelf, err := NewKeyPairFromSecret(initLeaf)
-	kpSelfSign, err := NewSigningKeyPairFromSecret(initLeaf)
+	kpSelf, err := suite.DeriveKeyPair(initLeaf)
+	kpSelfSign, err := suite.DeriveSigningKeyPair(initLeaf)
 	if err != nil {
 		panic(err)
 	}
@@ -318,13 +573,13 @@ func NewMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte
 		Secret:      initLeaf,
 		Public:      kpSelf.Public.Bytes(),
 		Private:     kpSelf.Private.Bytes(),
-		SignPublic:  kpSelfSign.Public.Bytes(),
-		SignPrivate: kpSelfSign.Private.Bytes(),
+		SignPublic:  kpSelfSign.Public,
+		SignPrivate: kpSelfSign.Private,
 	}
 
-	rootSecret := hash(initLeaf)
-	kpRoot, err := NewKeyPairFromSecret(rootSecret)
-	kpRootSign, err := NewSigningKeyPairFromSecret(rootSecret)
+	rootSecret := suite.Hash(initLeaf)
+	kpRoot, err := suite.DeriveKeyPair(rootSecret)
+	kpRootSign, err := suite.DeriveSigningKeyPair(rootSecret)
 	if err != nil {
 		panic(err)
 	}
@@ -332,15 +587,63 @@ func NewMultiTreeKEMExternal(treekemRootPub, treekemRootSignPub, initLeaf []byte
 		Secret:      rootSecret,
 		Public:      kpRoot.Public.Bytes(),
 		Private:     kpRoot.Private.Bytes(),
-		SignPublic:  kpRootSign.Public.Bytes(),
-		SignPrivate: kpRootSign.Private.Bytes(),
+		SignPublic:  kpRootSign.Public,
+		SignPrivate: kpRootSign.Private,
 	}
 
 	return &MultiTreeKEMExternal{
 		treekemRoot: treekemRoot,
 		selfNode:    selfNode,
 		root:        rootNode,
+		epoch:       initialRootEpoch(suite, rootNode.Public),
+		suite:       suite,
+	}
+}
+
+// SetGroupID sets the group ID the ciphertexts to and from the tree are bound to.
+func (m *MultiTreeKEMExternal) SetGroupID(groupID string) {
+	m.groupID = groupID
+}
+
+// eckemContext returns the context of ciphertexts to and from the tree.
+func (m *MultiTreeKEMExternal) eckemContext() ECKEMContext {
+	return ExternalNodeContext(m.groupID)
+}
+
+// SetHybridKEM sets whether the ciphertexts to and from the tree use the hybrid ML-KEM-768 + X25519 KEM, in which case
+// the public key of the treekem root has to be its hybrid public key. The key pair of the self node is derived again
+// for the KEM.
+func (m *MultiTreeKEMExternal) SetHybridKEM(hybridKEM bool) error {
+	m.hybridKEM = hybridKEM
+	public, private, err := m.selfKeyPair(m.selfNode.Secret)
+	if err != nil {
+		return err
 	}
+	m.selfNode.Public = public
+	m.selfNode.Private = private
+	return nil
+}
+
+// IsHybridKEM returns whether the ciphertexts to and from the tree use the hybrid KEM.
+func (m *MultiTreeKEMExternal) IsHybridKEM() bool {
+	return m.hybridKEM
+}
+
+// selfKeyPair derives the key pair of the self node from its leaf secret.
+func (m *MultiTreeKEMExternal) selfKeyPair(leaf []byte) ([]byte, []byte, error) {
+	if m.hybridKEM {
+		kp, err := DeriveHybridKeyPair(leaf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return kp.Public, kp.Private, nil
+	}
+
+	kp, err := m.suite.DeriveKeyPair(leaf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kp.Public.Bytes(), kp.Private.Bytes(), nil
 }
 
 // UpdateExternalNode issue an external node update.
@@ -350,33 +653,42 @@ func (m *MultiTreeKEMExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []
 		return ECKEMCipherText{}, nil, nil, err
 	}
 
-	kpSelf, err := NewKeyPairFromSecret(newLeaf)
-	kpSelfSign, err := NewSigningKeyPairFromSecret(newLeaf)
+	selfPublic, selfPrivate, err := m.selfKeyPair(newLeaf)
+	if err != nil {
+		return ECKEMCipherText{}, nil, nil, err
+	}
+	kpSelfSign, err := m.suite.DeriveSigningKeyPair(newLeaf)
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
 	m.selfNode = Node{
 		Secret:      newLeaf,
-		Public:      kpSelf.Public.Bytes(),
-		Private:     kpSelf.Private.Bytes(),
-		SignPublic:  kpSelfSign.Public.Bytes(),
-		SignPrivate: kpSelfSign.Private.Bytes(),
+		Public:      selfPublic,
+		Private:     selfPrivate,
+		SignPublic:  kpSelfSign.Public,
+		SignPrivate: kpSelfSign.Private,
 	}
 
-	kpRoot, err := NewKeyPairFromSecret(hash(newLeaf))
-	kpRootSign, err := NewSigningKeyPairFromSecret(hash(newLeaf))
+	kpRoot, err := m.suite.DeriveKeyPair(m.suite.Hash(newLeaf))
+	kpRootSign, err := m.suite.DeriveSigningKeyPair(m.suite.Hash(newLeaf))
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
 	m.root = Node{
-		Secret:      hash(newLeaf),
+		Secret:      m.suite.Hash(newLeaf),
 		Public:      kpRoot.Public.Bytes(),
 		Private:     kpRoot.Private.Bytes(),
-		SignPublic:  kpRootSign.Public.Bytes(),
-		SignPrivate: kpRootSign.Private.Bytes(),
+		SignPublic:  kpRootSign.Public,
+		SignPrivate: kpRootSign.Private,
 	}
+	m.epoch = m.epoch.next(m.suite, m.root.Public)
 
-	enc, err := ECKEMEncrypt(hash(newLeaf), m.treekemRoot.Public)
+	var enc ECKEMCipherText
+	if m.hybridKEM {
+		enc, err = HybridECKEMEncrypt(m.suite, m.suite.Hash(newLeaf), m.treekemRoot.Public, m.eckemContext())
+	} else {
+		enc, err = ECKEMEncrypt(m.suite, m.suite.Hash(newLeaf), m.treekemRoot.Public, m.eckemContext())
+	}
 	if err != nil {
 		return ECKEMCipherText{}, nil, nil, err
 	}
@@ -386,28 +698,66 @@ func (m *MultiTreeKEMExternal) UpdateExternalNode() (ECKEMCipherText, []byte, []
 
 // HandleTreeKEMUpdate handles the treekem user update.
 func (m *MultiTreeKEMExternal) HandleTreeKEMUpdate(updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte) error {
-	h, err := ECKEMDecrypt(updateMessage, m.selfNode.Private)
+	return m.HandleTreeKEMUpdateAtEpoch(updateMessage, newPubKey, newSignPubKey, m.epoch.Epoch+1, nil)
+}
+
+// HandleTreeKEMUpdateAtEpoch handles the treekem user update, which moves the root to the given epoch with the given
+// transcript hash. A nil transcript hash is not checked.
+func (m *MultiTreeKEMExternal) HandleTreeKEMUpdateAtEpoch(updateMessage ECKEMCipherText, newPubKey []byte, newSignPubKey []byte, epoch uint64, transcriptHash []byte) error {
+	err := m.epoch.check(epoch)
 	if err != nil {
 		return err
 	}
 
-	kp, err := NewKeyPairFromSecret(h)
-	kpSign, err := NewSigningKeyPairFromSecret(h)
+	h, err := ECKEMDecrypt(m.suite, updateMessage, m.selfNode.Private, m.eckemContext())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUndecryptableKeyUpdate, err)
+	}
+
+	root, err := rootNodeFromSecret(m.suite, h)
 	if err != nil {
 		return err
 	}
-	m.root = Node{
-		Secret:      h,
-		Public:      kp.Public.Bytes(),
-		Private:     kp.Private.Bytes(),
-		SignPublic:  kpSign.Public.Bytes(),
-		SignPrivate: kpSign.Private.Bytes(),
+	rootEpoch, err := m.epoch.advance(m.suite, epoch, transcriptHash, root.Public)
+	if err != nil {
+		return err
 	}
+	m.root = root
+	m.epoch = rootEpoch
 	m.treekemRoot = Node{Public: newPubKey, SignPublic: newSignPubKey}
 
 	return nil
 }
 
+// CheckEpoch returns nil if an update to the given epoch is the next one, ErrStaleKeyUpdate if the root has already
+// passed it, or a DesyncError if updates before it are missing.
+func (m *MultiTreeKEMExternal) CheckEpoch(epoch uint64) error {
+	return m.epoch.check(epoch)
+}
+
+// GetEpoch returns the epoch of the root.
+func (m *MultiTreeKEMExternal) GetEpoch() uint64 {
+	return m.epoch.Epoch
+}
+
+// GetTranscriptHash returns the transcript hash over the roots so far.
+func (m *MultiTreeKEMExternal) GetTranscriptHash() []byte {
+	return m.epoch.TranscriptHash
+}
+
+// Recover resumes the root at the state sent by a member after the external node lost track of it.
+func (m *MultiTreeKEMExternal) Recover(recovery ExternalNodeRecovery) error {
+	root, rootEpoch, err := recovery.recoveredRoot(m.suite, m.epoch)
+	if err != nil {
+		return err
+	}
+	m.root = root
+	m.epoch = rootEpoch
+	m.treekemRoot = Node{Public: recovery.TreeKEMRootPublic, SignPublic: recovery.TreeKEMRootSignPublic}
+
+	return nil
+}
+
 // GetRootSecret returns the secret of the root node.
 func (m *MultiTreeKEMExternal) GetRootSecret() []byte {
 	return m.root.Secret
@@ -1,8 +1,12 @@
 package treekem
 
 import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/util"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	"testing"
 )
 
@@ -132,11 +136,11 @@ func TestMultiTreeKEM(t *testing.T) {
 		// The final joiner build the external nodes for the three chatbots using the information given by the first user
 		for i, chatbot := range chatbots {
 			// member 0 send the new root to chatbot
-			ctToChatbot, err := ECKEMEncrypt(hash(members[0].Nodes()[root(members[0].Size())].Secret), multiTreeKEMs[0].externalNodes[fmt.Sprintf("cb-%d", i)].Public)
+			ctToChatbot, err := ECKEMEncrypt(members[0].Suite(), hash(members[0].Nodes()[root(members[0].Size())].Secret), multiTreeKEMs[0].externalNodes[fmt.Sprintf("cb-%d", i)].Public, ExternalNodeContext(""))
 			assert.Nil(t, err)
 
 			// chatbot receive the new root
-			cbNewRootSecret, err := ECKEMDecrypt(ctToChatbot, chatbot.selfNode.Private)
+			cbNewRootSecret, err := ECKEMDecrypt(members[0].Suite(), ctToChatbot, chatbot.selfNode.Private, ExternalNodeContext(""))
 
 			assert.Equal(t, cbNewRootSecret, hash(members[0].Nodes()[root(members[0].Size())].Secret), "chatbot fails to decrypt new secret")
 
@@ -154,6 +158,191 @@ func TestMultiTreeKEM(t *testing.T) {
 	}
 }
 
+func TestMultiTreeKEMRemoveExternalNode(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	for i := 1; i < 3; i++ {
+		leaf, _ = generateRandomBytes(32)
+		initKP, _ := NewKeyPairFromSecret(leaf)
+		gaGroup, gaJoiner, _ := members[len(members)-1].Add(initKP.Public.Bytes())
+		joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+		for _, m := range members {
+			m.HandleGroupAdd(gaGroup)
+		}
+		members = append(members, joiner)
+	}
+
+	multiTreeKEMs := make([]*MultiTreeKEM, len(members))
+	for i, m := range members {
+		multiTreeKEMs[i] = NewMultiTreeKEM(m)
+	}
+
+	// Member 0 adds two chatbots
+	chatbots := make([]*MultiTreeKEMExternal, 2)
+	for c := range chatbots {
+		cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin(fmt.Sprintf("cb-%d", c))
+		assert.Nilf(t, err, "error creating chatbot add: %s", err)
+		chatbots[c] = NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].Nodes()[root(members[0].Size())].SignPublic, initLeaf)
+		for _, mt := range multiTreeKEMs[1:] {
+			err := mt.AddExternalNode(fmt.Sprintf("cb-%d", c), cbct)
+			assert.Nilf(t, err, "error handling chatbot add: %s", err)
+		}
+	}
+
+	// An update covering both chatbots gives them the same root
+	userUpdate, ct, newRootPub, newRootSignPub, err := multiTreeKEMs[0].UpdateTreeKEM([]string{"cb-0", "cb-1"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	for c, chatbot := range chatbots {
+		err = chatbot.HandleTreeKEMUpdate(ct[fmt.Sprintf("cb-%d", c)], newRootPub, newRootSignPub)
+		assert.Nilf(t, err, "error updating chatbot: %s", err)
+	}
+	for _, mt := range multiTreeKEMs[1:] {
+		err = mt.HandleTreeKEMUpdate(userUpdate, []string{"cb-0", "cb-1"})
+		assert.Nilf(t, err, "error handling user update: %s", err)
+	}
+	assert.Equal(t, chatbots[0].GetRootSecret(), chatbots[1].GetRootSecret(), "chatbots updated together should share the root")
+
+	// Every member removes cb-1
+	for _, mt := range multiTreeKEMs {
+		err = mt.RemoveExternalNode("cb-1")
+		assert.Nilf(t, err, "error removing chatbot: %s", err)
+		assert.Equal(t, []string{"cb-0"}, mt.GetExternalNodeIDs(), "removed chatbot should not be an external node")
+		assert.Nil(t, mt.GetRootSecret("cb-1"), "removed chatbot should not have a root")
+	}
+	assert.NotNil(t, multiTreeKEMs[0].RemoveExternalNode("cb-1"), "removing a removed chatbot should fail")
+
+	// Member 1 forces an update of the remaining chatbots; stale IDs are ignored
+	userUpdate, ct, newRootPub, newRootSignPub, err = multiTreeKEMs[1].UpdateTreeKEM(append(multiTreeKEMs[1].GetExternalNodeIDs(), "cb-1"))
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	assert.Equal(t, 1, len(ct), "the forced update should not be encrypted to the removed chatbot")
+	for j, mt := range multiTreeKEMs {
+		if j == 1 {
+			continue
+		}
+		err = mt.HandleTreeKEMUpdate(userUpdate, []string{"cb-0", "cb-1"})
+		assert.Nilf(t, err, "error handling user update: %s", err)
+		assert.True(t, groupEqual(members[1], members[j]), "members 1 -> %d are not equal", j)
+		assert.Nil(t, mt.GetRootSecret("cb-1"), "handling an update should not restore the removed chatbot")
+	}
+
+	err = chatbots[0].HandleTreeKEMUpdate(ct["cb-0"], newRootPub, newRootSignPub)
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	for j, mt := range multiTreeKEMs {
+		assert.Equal(t, chatbots[0].GetRootSecret(), mt.GetRootSecret("cb-0"), "remaining chatbot is not consistent with member %d", j)
+		assert.NotEqual(t, chatbots[1].GetRootSecret(), mt.GetRootSecret("cb-0"), "removed chatbot should not know the new root")
+	}
+	assert.NotNil(t, chatbots[1].HandleTreeKEMUpdate(ct["cb-0"], newRootPub, newRootSignPub), "removed chatbot should not decrypt the forced update")
+
+	// Updates from the removed chatbot are rejected
+	chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbots[1].UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	assert.NotNil(t, multiTreeKEMs[0].HandleExternalNodeUpdate("cb-1", chatbotUpdate, newCbPubKey, newCbSignPubKey), "update from the removed chatbot should be rejected")
+}
+
+func TestMultiTreeKEMRemoveUser(t *testing.T) {
+	for testGroupSize := 3; testGroupSize <= 9; testGroupSize++ {
+		// A later joiner removing an earlier member relies on the public tree received with the invitation
+		for _, pair := range [][2]int{{0, 1}, {testGroupSize - 1, 0}, {testGroupSize - 1, testGroupSize / 2}} {
+			testMultiTreeKEMRemoveUser(t, testGroupSize, pair[0], pair[1])
+		}
+	}
+}
+
+func testMultiTreeKEMRemoveUser(t *testing.T, testGroupSize int, removerIndex int, removedIndex int) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	for i := 1; i < testGroupSize; i++ {
+		leaf, _ = generateRandomBytes(32)
+		gik := members[0].GroupInitKey()
+		ua, _ := TreeKEMStateJoin(leaf, gik)
+		joiner, _ := TreeKEMStateFromUserAdd(leaf, gik)
+		joiner.HandlePublicTree(members[0].PublicTree())
+		for _, m := range members {
+			m.HandleUserAdd(ua)
+		}
+		members = append(members, joiner)
+	}
+
+	multiTreeKEMs := make([]*MultiTreeKEM, len(members))
+	for i, m := range members {
+		multiTreeKEMs[i] = NewMultiTreeKEM(m)
+	}
+
+	// Member 0 adds a chatbot
+	cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin("cb-0")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	chatbot := NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	for _, mt := range multiTreeKEMs[1:] {
+		err := mt.AddExternalNode("cb-0", cbct)
+		assert.Nilf(t, err, "error handling chatbot add: %s", err)
+	}
+
+	// The remover removes the member, and the remaining members (including the remover) handle the remove
+	removed := multiTreeKEMs[removedIndex]
+	oldRootSecret := members[removerIndex].Nodes()[root(members[removerIndex].Size())].Secret
+	_, err = multiTreeKEMs[removerIndex].RemoveTreeKEMUser(removerIndex)
+	assert.NotNil(t, err, "removing self should fail")
+
+	userRemove, err := multiTreeKEMs[removerIndex].RemoveTreeKEMUser(removedIndex)
+	assert.Nilf(t, err, "error creating user remove: %s", err)
+	for _, node := range userRemove.Copath {
+		assert.Nil(t, node.Private, "the copath of a remove should not contain private keys")
+		assert.Nil(t, node.Secret, "the copath of a remove should not contain secrets")
+	}
+	*userRemove = PbTreeKEMUserRemoveConvert(TreeKEMUserRemovePbConvert(userRemove))
+
+	err = multiTreeKEMs[removerIndex].HandleTreeKEMRemove(userRemove)
+	assert.Nilf(t, err, "error handling own user remove: %s", err)
+	for j, mt := range multiTreeKEMs {
+		if j == removedIndex || j == removerIndex {
+			continue
+		}
+		err = mt.HandleTreeKEMRemove(userRemove)
+		assert.Nilf(t, err, "error handling user remove: %s", err)
+		assert.True(t, groupEqual(members[removerIndex], members[j]), "members %d -> %d are not equal", removerIndex, j)
+		assert.Equal(t, members[removerIndex].Nodes()[root(members[removerIndex].Size())].Secret, members[j].Nodes()[root(members[j].Size())].Secret, "members %d -> %d do not share the root", removerIndex, j)
+	}
+	assert.NotNil(t, removed.HandleTreeKEMRemove(userRemove), "the removed member should not handle its own remove")
+	assert.NotEqual(t, oldRootSecret, members[removerIndex].Nodes()[root(members[removerIndex].Size())].Secret, "the remove should replace the root")
+	assert.Nil(t, members[removerIndex].Nodes()[2*removedIndex], "the leaf of the removed member should be blank")
+
+	// Each remaining member updates the chatbot in turn, and the removed member cannot follow
+	for i, mt1 := range multiTreeKEMs {
+		if i == removedIndex {
+			continue
+		}
+		userUpdate, ct, newRootPub, newRootSignPub, err := mt1.UpdateTreeKEM([]string{"cb-0"})
+		assert.Nilf(t, err, "error creating user update: %s", err)
+		for j, mt2 := range multiTreeKEMs {
+			if j == i || j == removedIndex {
+				continue
+			}
+			err = mt2.HandleTreeKEMUpdate(userUpdate, []string{"cb-0"})
+			assert.Nilf(t, err, "error handling user update: %s", err)
+			assert.True(t, groupEqual(members[i], members[j]), "members %d -> %d are not equal", i, j)
+		}
+		assert.Panics(t, func() { _ = removed.HandleTreeKEMUpdate(userUpdate, []string{"cb-0"}) }, "the removed member should not decrypt the update")
+
+		err = chatbot.HandleTreeKEMUpdate(ct["cb-0"], newRootPub, newRootSignPub)
+		assert.Nilf(t, err, "error updating chatbot: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt1.GetRootSecret("cb-0"), "chatbot is not consistent with member %d", i)
+		assert.NotEqual(t, chatbot.GetRootSecret(), removed.GetRootSecret("cb-0"), "the removed member should not know the chatbot root")
+	}
+
+	// The chatbot still updates its own key with the remaining members
+	chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbot.UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	for j, mt := range multiTreeKEMs {
+		if j == removedIndex {
+			continue
+		}
+		err = mt.HandleExternalNodeUpdate("cb-0", chatbotUpdate, newCbPubKey, newCbSignPubKey)
+		assert.Nilf(t, err, "error handling chatbot update: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret("cb-0"), "chatbot is not consistent with member %d", j)
+	}
+	assert.NotNil(t, removed.HandleExternalNodeUpdate("cb-0", chatbotUpdate, newCbPubKey, newCbSignPubKey), "the removed member should not decrypt the chatbot update")
+}
+
 func TestMultiTreeKEMAsyncUpdate(t *testing.T) {
 	for testGroupSize := 3; testGroupSize <= 32; testGroupSize++ {
 		leaf, _ := generateRandomBytes(32)
@@ -503,3 +692,484 @@ func TestMultiTreeKEMAsyncUpdate(t *testing.T) {
 
 	}
 }
+
+func TestMultiTreeKEMCipherSuite(t *testing.T) {
+	suite := MustCipherSuite(X25519_CHACHA20POLY1305_SHA256_Ed25519)
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroupWithCipherSuite(suite, leaf)}
+	for i := 1; i < 3; i++ {
+		leaf, _ = generateRandomBytes(32)
+		initKP, _ := suite.DeriveKeyPair(leaf)
+		gaGroup, gaJoiner, _ := members[len(members)-1].Add(initKP.Public.Bytes())
+		joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+		for _, m := range members {
+			m.HandleGroupAdd(gaGroup)
+		}
+		members = append(members, joiner)
+	}
+
+	multiTreeKEMs := make([]*MultiTreeKEM, len(members))
+	for i, m := range members {
+		multiTreeKEMs[i] = NewMultiTreeKEM(m)
+	}
+
+	cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin("cb")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	chatbot := NewMultiTreeKEMExternalWithCipherSuite(suite, members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	for _, mt := range multiTreeKEMs[1:] {
+		err := mt.AddExternalNode("cb", cbct)
+		assert.Nilf(t, err, "error handling chatbot add: %s", err)
+	}
+	assert.Len(t, chatbot.GetRootSignPublic(), 32, "root signing key should be an Ed25519 key")
+
+	// Messages sealed under the root by a member are opened by the chatbot
+	ct, err := util.EncryptWithSuite(suite, []byte("hello"), multiTreeKEMs[1].GetRootSecret("cb"), multiTreeKEMs[1].GetRoots()["cb"].SignPrivate)
+	assert.Nilf(t, err, "error encrypting: %s", err)
+	pt, err := util.DecryptWithSuite(suite, ct, chatbot.GetRootSecret(), chatbot.GetRootSignPublic())
+	assert.Nilf(t, err, "error decrypting: %s", err)
+	assert.Equal(t, []byte("hello"), pt, "chatbot should decrypt the message")
+
+	userUpdate, cts, newRootPub, newRootSignPub, err := multiTreeKEMs[2].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	err = chatbot.HandleTreeKEMUpdate(cts["cb"], newRootPub, newRootSignPub)
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	for _, mt := range multiTreeKEMs[:2] {
+		err = mt.HandleTreeKEMUpdate(userUpdate, []string{"cb"})
+		assert.Nilf(t, err, "error handling user update: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret("cb"), "chatbot is not consistent with member")
+	}
+
+	chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbot.UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	for _, mt := range multiTreeKEMs {
+		err = mt.HandleExternalNodeUpdate("cb", chatbotUpdate, newCbPubKey, newCbSignPubKey)
+		assert.Nilf(t, err, "error handling chatbot update: %s", err)
+		assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret("cb"), "chatbot is not consistent with member")
+	}
+}
+
+func TestMultiTreeKEMKeyUpdateMAC(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	leaf, _ = generateRandomBytes(32)
+	initKP, _ := NewKeyPairFromSecret(leaf)
+	gaGroup, gaJoiner, _ := members[0].Add(initKP.Public.Bytes())
+	joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+	members[0].HandleGroupAdd(gaGroup)
+	members = append(members, joiner)
+	multiTreeKEMs := []*MultiTreeKEM{NewMultiTreeKEM(members[0]), NewMultiTreeKEM(members[1])}
+
+	cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin("cb")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	chatbot := NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	err = multiTreeKEMs[1].AddExternalNode("cb", cbct)
+	assert.Nilf(t, err, "error handling chatbot add: %s", err)
+
+	// A member MACs its update with the root it shares with the chatbot before the update
+	macKeys := multiTreeKEMs[1].GetUpdateMACKeys([]string{"cb"})
+	assert.Equal(t, chatbot.GetUpdateMACKey(), macKeys["cb"], "member and chatbot should derive the same MAC key")
+	userUpdate, cts, newRootPub, newRootSignPub, err := multiTreeKEMs[1].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	pack := &pb.TreeKEMKeyUpdatePack{
+		ChatbotUpdateCiphertexts: ECKEMCipherTextStringMapPbConvert(cts),
+		NewRootPubKey:            newRootPub,
+		NewRootSignPubKey:        newRootSignPub,
+	}
+	tbs, err := KeyUpdatePackTBS("group", pack)
+	assert.Nilf(t, err, "error encoding pack: %s", err)
+	pack.ChatbotMACs = map[string][]byte{"cb": KeyUpdateMAC(macKeys["cb"], tbs)}
+
+	// The MACs and the signature are not covered by themselves
+	tbsWithMACs, err := KeyUpdatePackTBS("group", pack)
+	assert.Nilf(t, err, "error encoding pack: %s", err)
+	assert.Equal(t, tbs, tbsWithMACs, "TBS should not depend on the MACs")
+
+	// The chatbot accepts the genuine pack and rejects a substituted root or a different group
+	assert.Nil(t, VerifyKeyUpdateMAC(chatbot.GetUpdateMACKey(), tbs, pack.ChatbotMACs["cb"]), "chatbot should accept the genuine pack")
+	forged := &pb.TreeKEMKeyUpdatePack{
+		ChatbotUpdateCiphertexts: pack.ChatbotUpdateCiphertexts,
+		NewRootPubKey:            members[0].RootPublic(),
+		NewRootSignPubKey:        newRootSignPub,
+	}
+	forgedTBS, err := KeyUpdatePackTBS("group", forged)
+	assert.Nilf(t, err, "error encoding pack: %s", err)
+	assert.ErrorIs(t, VerifyKeyUpdateMAC(chatbot.GetUpdateMACKey(), forgedTBS, pack.ChatbotMACs["cb"]), ErrUnauthenticatedKeyUpdate, "chatbot should reject a substituted root")
+	otherGroupTBS, err := KeyUpdatePackTBS("other-group", pack)
+	assert.Nilf(t, err, "error encoding pack: %s", err)
+	assert.ErrorIs(t, VerifyKeyUpdateMAC(chatbot.GetUpdateMACKey(), otherGroupTBS, pack.ChatbotMACs["cb"]), ErrUnauthenticatedKeyUpdate, "chatbot should reject a pack for another group")
+	assert.ErrorIs(t, VerifyKeyUpdateMAC(nil, tbs, pack.ChatbotMACs["cb"]), ErrUnauthenticatedKeyUpdate, "an unknown root should not authenticate anything")
+
+	err = chatbot.HandleTreeKEMUpdate(cts["cb"], newRootPub, newRootSignPub)
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	err = multiTreeKEMs[0].HandleTreeKEMUpdate(userUpdate, []string{"cb"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+
+	// A new member learns the root with the chatbot on join, so it can MAC its first update
+	leaf, _ = generateRandomBytes(32)
+	initKP, _ = NewKeyPairFromSecret(leaf)
+	gaGroup, gaJoiner, _ = members[0].Add(initKP.Public.Bytes())
+	joiner, _ = TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+	for _, m := range members {
+		m.HandleGroupAdd(gaGroup)
+	}
+	joinerMT := NewMultiTreeKEM(joiner)
+	chatbotPubKeys, chatbotSignPubKeys, lastTreeKemRootCiphertexts, err := multiTreeKEMs[0].GetExternalNodeJoinsWithoutUpdate(joiner.Self().Public)
+	assert.Nilf(t, err, "error creating chatbot keys: %s", err)
+	err = joinerMT.SetExternalNodeJoinsWithoutUpdate(chatbotPubKeys, chatbotSignPubKeys, lastTreeKemRootCiphertexts)
+	assert.Nilf(t, err, "error handling chatbot add: %s", err)
+	assert.Equal(t, chatbot.GetRootSecret(), joinerMT.GetRootSecret("cb"), "new member should learn the root with the chatbot")
+	assert.Equal(t, chatbot.GetUpdateMACKey(), joinerMT.GetUpdateMACKeys([]string{"cb"})["cb"], "new member and chatbot should derive the same MAC key")
+}
+
+func TestMultiTreeKEMEpochs(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	leaf, _ = generateRandomBytes(32)
+	initKP, _ := NewKeyPairFromSecret(leaf)
+	gaGroup, gaJoiner, _ := members[0].Add(initKP.Public.Bytes())
+	joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+	members[0].HandleGroupAdd(gaGroup)
+	members = append(members, joiner)
+	multiTreeKEMs := []*MultiTreeKEM{NewMultiTreeKEM(members[0]), NewMultiTreeKEM(members[1])}
+
+	cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin("cb")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	chatbot := NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	err = multiTreeKEMs[1].AddExternalNode("cb", cbct)
+	assert.Nilf(t, err, "error handling chatbot add: %s", err)
+
+	// Everyone starts at epoch 0 with the same transcript
+	for _, m := range multiTreeKEMs {
+		assert.Equal(t, uint64(0), m.GetEpoch("cb"), "members should start at epoch 0")
+		assert.Equal(t, chatbot.GetTranscriptHash(), m.GetTranscriptHash("cb"), "members and chatbot should start with the same transcript")
+	}
+	assert.Equal(t, uint64(0), chatbot.GetEpoch(), "chatbot should start at epoch 0")
+
+	// A member sends two updates in a row
+	userUpdate1, cts1, newRootPub1, newRootSignPub1, err := multiTreeKEMs[1].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs1, transcriptHashes1 := multiTreeKEMs[1].GetEpochs([]string{"cb"})
+	userUpdate2, cts2, newRootPub2, newRootSignPub2, err := multiTreeKEMs[1].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs2, transcriptHashes2 := multiTreeKEMs[1].GetEpochs([]string{"cb"})
+	assert.Equal(t, uint64(1), epochs1["cb"], "first update should move the root to epoch 1")
+	assert.Equal(t, uint64(2), epochs2["cb"], "second update should move the root to epoch 2")
+
+	// The second update arrives first and is reported as a desync without touching the root
+	rootBefore := chatbot.GetRootSecret()
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts2["cb"], newRootPub2, newRootSignPub2, epochs2["cb"], transcriptHashes2["cb"])
+	assert.ErrorIs(t, err, ErrDesync, "update ahead of the root should be a desync")
+	var desyncErr *DesyncError
+	assert.True(t, errors.As(err, &desyncErr), "desync should be a DesyncError")
+	assert.Equal(t, uint64(0), desyncErr.Epoch, "desync should report the local epoch")
+	assert.Equal(t, uint64(2), desyncErr.ReceivedEpoch, "desync should report the received epoch")
+	assert.False(t, desyncErr.TranscriptMismatch, "missing updates are not a transcript mismatch")
+	assert.Equal(t, rootBefore, chatbot.GetRootSecret(), "desync should not touch the root")
+
+	// A transcript that does not match the local history is a desync too
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts1["cb"], newRootPub1, newRootSignPub1, epochs1["cb"], transcriptHashes2["cb"])
+	assert.True(t, errors.As(err, &desyncErr), "transcript mismatch should be a DesyncError")
+	assert.True(t, desyncErr.TranscriptMismatch, "transcript mismatch should be reported")
+	assert.Equal(t, rootBefore, chatbot.GetRootSecret(), "transcript mismatch should not touch the root")
+
+	// Handled in order, both updates apply, and replaying one is rejected as stale
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts1["cb"], newRootPub1, newRootSignPub1, epochs1["cb"], transcriptHashes1["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts1["cb"], newRootPub1, newRootSignPub1, epochs1["cb"], transcriptHashes1["cb"])
+	assert.ErrorIs(t, err, ErrStaleKeyUpdate, "replayed update should be stale")
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts2["cb"], newRootPub2, newRootSignPub2, epochs2["cb"], transcriptHashes2["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	assert.Equal(t, multiTreeKEMs[1].GetRootSecret("cb"), chatbot.GetRootSecret(), "chatbot should follow the member")
+	assert.Equal(t, multiTreeKEMs[1].GetTranscriptHash("cb"), chatbot.GetTranscriptHash(), "chatbot should have the member's transcript")
+
+	// The other member follows the same history
+	err = multiTreeKEMs[0].HandleTreeKEMUpdate(userUpdate1, []string{"cb"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+	err = multiTreeKEMs[0].HandleTreeKEMUpdate(userUpdate2, []string{"cb"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+	assert.Equal(t, uint64(2), multiTreeKEMs[0].GetEpoch("cb"), "member should be at epoch 2")
+	assert.Equal(t, chatbot.GetTranscriptHash(), multiTreeKEMs[0].GetTranscriptHash("cb"), "members and chatbot should have the same transcript")
+
+	// A chatbot that missed both updates recovers the root from a member and can update it again
+	lostChatbot := NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	recovery, err := multiTreeKEMs[0].GetExternalNodeRecovery("cb")
+	assert.Nilf(t, err, "error creating recovery: %s", err)
+	err = lostChatbot.Recover(recovery)
+	assert.Nilf(t, err, "error recovering root: %s", err)
+	assert.Equal(t, chatbot.GetRootSecret(), lostChatbot.GetRootSecret(), "recovered chatbot should have the current root")
+	assert.Equal(t, uint64(2), lostChatbot.GetEpoch(), "recovered chatbot should be at the current epoch")
+
+	cbUpdate, cbPub, cbSignPub, err := lostChatbot.UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	for _, m := range multiTreeKEMs {
+		err = m.HandleExternalNodeUpdateAtEpoch("cb", cbUpdate, cbPub, cbSignPub, lostChatbot.GetEpoch(), lostChatbot.GetTranscriptHash())
+		assert.Nilf(t, err, "error handling chatbot update: %s", err)
+		assert.Equal(t, lostChatbot.GetRootSecret(), m.GetRootSecret("cb"), "members should follow the recovered chatbot")
+	}
+
+	// A recovery cannot move the root back in time
+	recovery.Epoch = 0
+	assert.ErrorIs(t, lostChatbot.Recover(recovery), ErrStaleKeyUpdate, "recovery to an older epoch should be rejected")
+}
+
+func TestMultiTreeKEMSnapshot(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	leaf, _ = generateRandomBytes(32)
+	initKP, _ := NewKeyPairFromSecret(leaf)
+	gaGroup, gaJoiner, _ := members[0].Add(initKP.Public.Bytes())
+	joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+	members[0].HandleGroupAdd(gaGroup)
+	members = append(members, joiner)
+	multiTreeKEMs := []*MultiTreeKEM{NewMultiTreeKEM(members[0]), NewMultiTreeKEM(members[1])}
+
+	cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin("cb")
+	assert.Nilf(t, err, "error creating chatbot add: %s", err)
+	chatbot := NewMultiTreeKEMExternal(members[0].RootPublic(), members[0].RootSignPublic(), initLeaf)
+	err = multiTreeKEMs[1].AddExternalNode("cb", cbct)
+	assert.Nilf(t, err, "error handling chatbot add: %s", err)
+
+	// Both members issue an update from the same state, but only the update of the first one is accepted
+	snapshot := multiTreeKEMs[1].Snapshot()
+	rootBefore, treeEpochBefore := multiTreeKEMs[1].GetRootSecret("cb"), members[1].Epoch()
+	_, _, _, _, err = multiTreeKEMs[1].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	userUpdate, cts, newRootPub, newRootSignPub, err := multiTreeKEMs[0].UpdateTreeKEM([]string{"cb"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs, transcriptHashes := multiTreeKEMs[0].GetEpochs([]string{"cb"})
+
+	// The second member rolls its update back, including the treekem it shares with the session
+	multiTreeKEMs[1].Restore(snapshot)
+	assert.Equal(t, rootBefore, multiTreeKEMs[1].GetRootSecret("cb"), "restore should roll the root back")
+	assert.Equal(t, uint64(0), multiTreeKEMs[1].GetEpoch("cb"), "restore should roll the epoch back")
+	assert.Equal(t, treeEpochBefore, members[1].Epoch(), "restore should roll the treekem back in place")
+
+	// Everyone then follows the accepted update
+	err = multiTreeKEMs[1].HandleTreeKEMUpdate(userUpdate, []string{"cb"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+	err = chatbot.HandleTreeKEMUpdateAtEpoch(cts["cb"], newRootPub, newRootSignPub, epochs["cb"], transcriptHashes["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	assert.True(t, members[0].Equal(members[1]), "members should have the same tree")
+	assert.Equal(t, multiTreeKEMs[0].GetRootSecret("cb"), multiTreeKEMs[1].GetRootSecret("cb"), "members should have the same root")
+	assert.Equal(t, multiTreeKEMs[0].GetRootSecret("cb"), chatbot.GetRootSecret(), "chatbot should follow the members")
+
+	// A chatbot rolls its own update back the same way
+	chatbotSnapshot := chatbot.Snapshot()
+	_, _, _, err = chatbot.UpdateExternalNode()
+	assert.Nilf(t, err, "error creating chatbot update: %s", err)
+	chatbot.Restore(chatbotSnapshot)
+	assert.Equal(t, multiTreeKEMs[0].GetRootSecret("cb"), chatbot.GetRootSecret(), "restore should roll the chatbot root back")
+	assert.Equal(t, multiTreeKEMs[0].GetTranscriptHash("cb"), chatbot.GetTranscriptHash(), "restore should roll the chatbot transcript back")
+}
+
+func TestMultiTreeKEMHybridKEM(t *testing.T) {
+	members := make([]*TreeKEMState, 0)
+	leaf, _ := generateRandomBytes(32)
+	members = append(members, TreeKEMStateOneMemberGroup(leaf))
+	for i := 1; i < 3; i++ {
+		leaf, _ = generateRandomBytes(32)
+		initKP, _ := NewKeyPairFromSecret(leaf)
+		gaGroup, gaJoiner, _ := members[len(members)-1].Add(initKP.Public.Bytes())
+		joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+		for _, m := range members {
+			m.HandleGroupAdd(gaGroup)
+		}
+		members = append(members, joiner)
+	}
+
+	multiTreeKEMs := make([]*MultiTreeKEM, len(members))
+	for i, m := range members {
+		multiTreeKEMs[i] = NewMultiTreeKEM(m)
+		multiTreeKEMs[i].SetExternalNodeHybridKEM("pq", true)
+	}
+
+	// "pq" opts in to the hybrid KEM while "cb" does not
+	chatbots := make(map[string]*MultiTreeKEMExternal)
+	for _, id := range []string{"pq", "cb"} {
+		rootPub := members[0].RootPublic()
+		if multiTreeKEMs[0].IsExternalNodeHybridKEM(id) {
+			var err error
+			rootPub, err = multiTreeKEMs[0].GetTreeKEMRootHybridPublic()
+			assert.Nilf(t, err, "error deriving hybrid root key: %s", err)
+		}
+
+		cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin(id)
+		assert.Nilf(t, err, "error creating chatbot add: %s", err)
+		chatbots[id] = NewMultiTreeKEMExternal(rootPub, members[0].RootSignPublic(), initLeaf)
+		err = chatbots[id].SetHybridKEM(multiTreeKEMs[0].IsExternalNodeHybridKEM(id))
+		assert.Nilf(t, err, "error setting hybrid KEM: %s", err)
+		for _, mt := range multiTreeKEMs[1:] {
+			err := mt.AddExternalNode(id, cbct)
+			assert.Nilf(t, err, "error handling chatbot add: %s", err)
+		}
+	}
+	assert.True(t, multiTreeKEMs[1].HasHybridKEMExternalNode(), "group should have a hybrid chatbot")
+	assert.Len(t, multiTreeKEMs[1].GetExternalNode("pq").Public, HybridPublicKeySize, "hybrid chatbot should have a hybrid key")
+	assert.Equal(t, chatbots["pq"].GetSelfNode().Public, multiTreeKEMs[1].GetExternalNode("pq").Public, "chatbot key is not consistent with member")
+
+	for i := 0; i < 3; i++ {
+		// Member update: the hybrid chatbot receives a hybrid ciphertext and the hybrid root key
+		userUpdate, cts, newRootPub, newRootSignPub, err := multiTreeKEMs[i].UpdateTreeKEM([]string{"pq", "cb"})
+		assert.Nilf(t, err, "error creating user update: %s", err)
+		assert.Equal(t, ECKEMVersionHybrid, cts["pq"].Version, "hybrid chatbot should receive a hybrid ciphertext")
+		assert.Equal(t, ECKEMVersionHPKE, cts["cb"].Version, "classical chatbot should receive a classical ciphertext")
+		newRootHybridPub, err := multiTreeKEMs[i].GetTreeKEMRootHybridPublic()
+		assert.Nilf(t, err, "error deriving hybrid root key: %s", err)
+
+		err = chatbots["pq"].HandleTreeKEMUpdate(cts["pq"], newRootHybridPub, newRootSignPub)
+		assert.Nilf(t, err, "error updating hybrid chatbot: %s", err)
+		err = chatbots["cb"].HandleTreeKEMUpdate(cts["cb"], newRootPub, newRootSignPub)
+		assert.Nilf(t, err, "error updating classical chatbot: %s", err)
+
+		// A hybrid chatbot does not accept a classical ciphertext
+		downgraded, _ := ECKEMEncrypt(multiTreeKEMs[i].suite, multiTreeKEMs[i].GetRootSecret("pq"), multiTreeKEMs[i].GetExternalNode("cb").Public, multiTreeKEMs[i].eckemContext())
+		err = chatbots["pq"].HandleTreeKEMUpdate(downgraded, newRootHybridPub, newRootSignPub)
+		assert.NotNil(t, err, "hybrid chatbot should reject a classical ciphertext")
+
+		for j, mt := range multiTreeKEMs {
+			if j != i {
+				err = mt.HandleTreeKEMUpdate(userUpdate, []string{"pq", "cb"})
+				assert.Nilf(t, err, "error handling user update: %s", err)
+			}
+			for id, chatbot := range chatbots {
+				assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret(id), "chatbot %s is not consistent with member %d", id, j)
+			}
+		}
+
+		// Chatbot update: the hybrid chatbot encrypts to the hybrid root key
+		for id, chatbot := range chatbots {
+			chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbot.UpdateExternalNode()
+			assert.Nilf(t, err, "error creating chatbot update: %s", err)
+			if id == "pq" {
+				assert.Equal(t, ECKEMVersionHybrid, chatbotUpdate.Version, "hybrid chatbot should send a hybrid ciphertext")
+				assert.Len(t, newCbPubKey, HybridPublicKeySize, "hybrid chatbot should send a hybrid key")
+			}
+			for _, mt := range multiTreeKEMs {
+				err = mt.HandleExternalNodeUpdate(id, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+				assert.Nilf(t, err, "error handling chatbot update: %s", err)
+				assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret(id), "chatbot %s is not consistent with member", id)
+			}
+		}
+	}
+
+	// Dummies look like the real ciphertexts of each chatbot
+	dummy, err := multiTreeKEMs[0].EncryptDummyToExternalNode("pq", 32)
+	assert.Nilf(t, err, "error creating dummy: %s", err)
+	assert.Equal(t, ECKEMVersionHybrid, dummy.Version, "dummy to a hybrid chatbot should be hybrid")
+	dummy, err = multiTreeKEMs[0].EncryptDummyToExternalNode("cb", 32)
+	assert.Nilf(t, err, "error creating dummy: %s", err)
+	assert.Equal(t, ECKEMVersionHPKE, dummy.Version, "dummy to a classical chatbot should be classical")
+
+	err = multiTreeKEMs[0].RemoveExternalNode("pq")
+	assert.Nilf(t, err, "error removing chatbot: %s", err)
+	assert.False(t, multiTreeKEMs[0].HasHybridKEMExternalNode(), "group should have no hybrid chatbot left")
+}
+
+func TestMultiTreeKEMSerialization(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	members := []*TreeKEMState{TreeKEMStateOneMemberGroup(leaf)}
+	leaf, _ = generateRandomBytes(32)
+	initKP, _ := NewKeyPairFromSecret(leaf)
+	gaGroup, gaJoiner, _ := members[0].Add(initKP.Public.Bytes())
+	joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+	members[0].HandleGroupAdd(gaGroup)
+	members = append(members, joiner)
+	multiTreeKEMs := []*MultiTreeKEM{NewMultiTreeKEM(members[0]), NewMultiTreeKEM(members[1])}
+	for _, mt := range multiTreeKEMs {
+		mt.SetExternalNodeHybridKEM("pq", true)
+	}
+
+	chatbots := make(map[string]*MultiTreeKEMExternal)
+	for _, id := range []string{"cb", "pq"} {
+		rootPub := members[0].RootPublic()
+		if id == "pq" {
+			rootPub, _ = multiTreeKEMs[0].GetTreeKEMRootHybridPublic()
+		}
+		cbct, initLeaf, err := multiTreeKEMs[0].GetExternalNodeJoin(id)
+		assert.Nilf(t, err, "error creating chatbot add: %s", err)
+		chatbots[id] = NewMultiTreeKEMExternal(rootPub, members[0].RootSignPublic(), initLeaf)
+		err = chatbots[id].SetHybridKEM(id == "pq")
+		assert.Nilf(t, err, "error setting hybrid KEM: %s", err)
+		err = multiTreeKEMs[1].AddExternalNode(id, cbct)
+		assert.Nilf(t, err, "error handling chatbot add: %s", err)
+	}
+
+	// Move the roots past epoch 0 so the transcripts are not trivial
+	userUpdate, cts, newRootPub, newRootSignPub, err := multiTreeKEMs[1].UpdateTreeKEM([]string{"cb", "pq"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs, transcriptHashes := multiTreeKEMs[1].GetEpochs([]string{"cb", "pq"})
+	newRootHybridPub, _ := multiTreeKEMs[1].GetTreeKEMRootHybridPublic()
+	err = multiTreeKEMs[0].HandleTreeKEMUpdate(userUpdate, []string{"cb", "pq"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+	err = chatbots["cb"].HandleTreeKEMUpdateAtEpoch(cts["cb"], newRootPub, newRootSignPub, epochs["cb"], transcriptHashes["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	err = chatbots["pq"].HandleTreeKEMUpdateAtEpoch(cts["pq"], newRootHybridPub, newRootSignPub, epochs["pq"], transcriptHashes["pq"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+
+	// Restore every member and chatbot from its serialized state
+	for i, mt := range multiTreeKEMs {
+		data, err := mt.Marshal()
+		assert.Nilf(t, err, "error marshaling state: %s", err)
+		restored, err := UnmarshalMultiTreeKEM(data)
+		assert.Nilf(t, err, "error unmarshaling state: %s", err)
+
+		assert.True(t, groupEqual(mt.GetTreeKEM(), restored.GetTreeKEM()), "restored treekem of member %d is not equal", i)
+		assert.Equal(t, mt.GetExternalNodeIDs(), restored.GetExternalNodeIDs(), "restored member %d has other chatbots", i)
+		for _, id := range []string{"cb", "pq"} {
+			root, restoredRoot := mt.GetRoots()[id], restored.GetRoots()[id]
+			externalNode, restoredExternalNode := mt.GetExternalNode(id), restored.GetExternalNode(id)
+			assert.True(t, nodeEqual(&root, &restoredRoot), "restored root %s of member %d is not equal", id, i)
+			assert.True(t, nodeEqual(&externalNode, &restoredExternalNode), "restored chatbot %s of member %d is not equal", id, i)
+			assert.Equal(t, mt.GetRootSecret(id), restored.GetRootSecret(id), "restored root %s of member %d has another secret", id, i)
+			assert.Equal(t, mt.GetEpoch(id), restored.GetEpoch(id), "restored root %s of member %d has another epoch", id, i)
+			assert.Equal(t, mt.GetTranscriptHash(id), restored.GetTranscriptHash(id), "restored root %s of member %d has another transcript", id, i)
+			assert.Equal(t, mt.IsExternalNodeHybridKEM(id), restored.IsExternalNodeHybridKEM(id), "restored member %d lost the KEM of %s", i, id)
+		}
+		multiTreeKEMs[i] = restored
+	}
+	for id, chatbot := range chatbots {
+		data, err := chatbot.Marshal()
+		assert.Nilf(t, err, "error marshaling state: %s", err)
+		restored, err := UnmarshalMultiTreeKEMExternal(data)
+		assert.Nilf(t, err, "error unmarshaling state: %s", err)
+
+		selfNode, restoredSelfNode := chatbot.GetSelfNode(), restored.GetSelfNode()
+		assert.True(t, nodeEqual(&selfNode, &restoredSelfNode), "restored chatbot %s has another key", id)
+		assert.Equal(t, chatbot.GetRootSecret(), restored.GetRootSecret(), "restored chatbot %s has another root", id)
+		assert.Equal(t, chatbot.GetRootSignPublic(), restored.GetRootSignPublic(), "restored chatbot %s has another root", id)
+		assert.Equal(t, chatbot.GetEpoch(), restored.GetEpoch(), "restored chatbot %s has another epoch", id)
+		assert.Equal(t, chatbot.GetTranscriptHash(), restored.GetTranscriptHash(), "restored chatbot %s has another transcript", id)
+		chatbots[id] = restored
+	}
+
+	// The restored states keep working, in both directions
+	userUpdate, cts, newRootPub, newRootSignPub, err = multiTreeKEMs[0].UpdateTreeKEM([]string{"cb", "pq"})
+	assert.Nilf(t, err, "error creating user update: %s", err)
+	epochs, transcriptHashes = multiTreeKEMs[0].GetEpochs([]string{"cb", "pq"})
+	newRootHybridPub, _ = multiTreeKEMs[0].GetTreeKEMRootHybridPublic()
+	err = multiTreeKEMs[1].HandleTreeKEMUpdate(userUpdate, []string{"cb", "pq"})
+	assert.Nilf(t, err, "error handling user update: %s", err)
+	err = chatbots["cb"].HandleTreeKEMUpdateAtEpoch(cts["cb"], newRootPub, newRootSignPub, epochs["cb"], transcriptHashes["cb"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	err = chatbots["pq"].HandleTreeKEMUpdateAtEpoch(cts["pq"], newRootHybridPub, newRootSignPub, epochs["pq"], transcriptHashes["pq"])
+	assert.Nilf(t, err, "error updating chatbot: %s", err)
+	assert.True(t, groupEqual(multiTreeKEMs[0].GetTreeKEM(), multiTreeKEMs[1].GetTreeKEM()), "restored members should have the same tree")
+
+	for id, chatbot := range chatbots {
+		chatbotUpdate, newCbPubKey, newCbSignPubKey, err := chatbot.UpdateExternalNode()
+		assert.Nilf(t, err, "error creating chatbot update: %s", err)
+		for i, mt := range multiTreeKEMs {
+			err = mt.HandleExternalNodeUpdateAtEpoch(id, chatbotUpdate, newCbPubKey, newCbSignPubKey, chatbot.GetEpoch(), chatbot.GetTranscriptHash())
+			assert.Nilf(t, err, "error handling chatbot update: %s", err)
+			assert.Equal(t, chatbot.GetRootSecret(), mt.GetRootSecret(id), "chatbot %s is not consistent with restored member %d", id, i)
+		}
+	}
+
+	// States written with an unknown version are rejected
+	data, _ := proto.Marshal(&pb.StoredMultiTreeKEM{Version: StateSerializationVersion + 1})
+	_, err = UnmarshalMultiTreeKEM(data)
+	assert.ErrorIs(t, err, ErrUnsupportedStateVersion, "unknown version should be rejected")
+	data, _ = proto.Marshal(&pb.StoredMultiTreeKEMExternal{Version: StateSerializationVersion + 1})
+	_, err = UnmarshalMultiTreeKEMExternal(data)
+	assert.ErrorIs(t, err, ErrUnsupportedStateVersion, "unknown version should be rejected")
+}
@@ -0,0 +1,305 @@
+package treekem
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"errors"
+	"fmt"
+	"github.com/s3131212/go-mls"
+	"google.golang.org/protobuf/proto"
+)
+
+// This file implements the binary serialization of the TreeKEM and CMRT states, so that a client can persist its
+// groups and load them again after a restart. The states are stored as the Stored* protobuf messages, which carry the
+// version of the format they were written with.
+
+// StateSerializationVersion is the version of the format the states are serialized with.
+const StateSerializationVersion = 1
+
+// ErrUnsupportedStateVersion is returned when a serialized state was written with a format version this client does
+// not know.
+var ErrUnsupportedStateVersion = errors.New("unsupported state serialization version")
+
+// checkStateVersion checks that a state was serialized with a known format version.
+func checkStateVersion(version uint32) error {
+	if version != StateSerializationVersion {
+		return fmt.Errorf("%w: %v", ErrUnsupportedStateVersion, version)
+	}
+	return nil
+}
+
+// nodeStringMapPbConvert converts a map of nodes keyed by ID to TreeKEMNode in protobuf.
+func nodeStringMapPbConvert(nodes map[string]Node) map[string]*pb.TreeKEMNode {
+	pbNodes := make(map[string]*pb.TreeKEMNode, len(nodes))
+	for id, node := range nodes {
+		pbNodes[id] = TreeKEMNodePbConvert(&node)
+	}
+	return pbNodes
+}
+
+// pbNodeStringMapConvert converts a map of TreeKEMNode in protobuf keyed by ID to nodes.
+func pbNodeStringMapConvert(pbNodes map[string]*pb.TreeKEMNode) map[string]Node {
+	nodes := make(map[string]Node, len(pbNodes))
+	for id, pbNode := range pbNodes {
+		nodes[id] = *PbTreeKEMNodeConvert(pbNode)
+	}
+	return nodes
+}
+
+// rootEpochPbConvert converts a RootEpoch to StoredRootEpoch in protobuf.
+func rootEpochPbConvert(epoch RootEpoch) *pb.StoredRootEpoch {
+	return &pb.StoredRootEpoch{
+		Epoch:          epoch.Epoch,
+		TranscriptHash: epoch.TranscriptHash,
+	}
+}
+
+// pbRootEpochConvert converts a StoredRootEpoch in protobuf to a RootEpoch.
+func pbRootEpochConvert(pbEpoch *pb.StoredRootEpoch) RootEpoch {
+	return RootEpoch{
+		Epoch:          pbEpoch.GetEpoch(),
+		TranscriptHash: pbEpoch.GetTranscriptHash(),
+	}
+}
+
+// rootEpochMapPbConvert converts a map of RootEpoch keyed by ID to StoredRootEpoch in protobuf.
+func rootEpochMapPbConvert(epochs map[string]RootEpoch) map[string]*pb.StoredRootEpoch {
+	pbEpochs := make(map[string]*pb.StoredRootEpoch, len(epochs))
+	for id, epoch := range epochs {
+		pbEpochs[id] = rootEpochPbConvert(epoch)
+	}
+	return pbEpochs
+}
+
+// pbRootEpochMapConvert converts a map of StoredRootEpoch in protobuf keyed by ID to RootEpoch.
+func pbRootEpochMapConvert(pbEpochs map[string]*pb.StoredRootEpoch) map[string]RootEpoch {
+	epochs := make(map[string]RootEpoch, len(pbEpochs))
+	for id, pbEpoch := range pbEpochs {
+		epochs[id] = pbRootEpochConvert(pbEpoch)
+	}
+	return epochs
+}
+
+// treeKEMStatePbConvert converts a TreeKEMState to StoredTreeKEMState in protobuf. Blank nodes are left out.
+func treeKEMStatePbConvert(t *TreeKEMState) *pb.StoredTreeKEMState {
+	nodes := make(map[uint32]*pb.TreeKEMNode, len(t.tkem.Nodes))
+	for index, node := range t.tkem.Nodes {
+		if node != nil {
+			nodes[uint32(index)] = TreeKEMNodePbConvert(node)
+		}
+	}
+
+	return &pb.StoredTreeKEMState{
+		Version:     StateSerializationVersion,
+		Size:        uint32(t.tkem.Size),
+		Index:       uint32(t.tkem.Index),
+		Nodes:       nodes,
+		GroupID:     t.tkem.GroupID,
+		Epoch:       t.tkem.Epoch,
+		CipherSuite: uint32(t.tkem.Suite.ID()),
+	}
+}
+
+// pbTreeKEMStateConvert converts a StoredTreeKEMState in protobuf to a TreeKEMState.
+func pbTreeKEMStateConvert(stored *pb.StoredTreeKEMState) (*TreeKEMState, error) {
+	if err := checkStateVersion(stored.GetVersion()); err != nil {
+		return nil, err
+	}
+	suite, err := CipherSuiteByID(CipherSuiteID(stored.GetCipherSuite()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TreeKEMState{
+		tkem: &TreeKEM{
+			Size:    int(stored.GetSize()),
+			Index:   int(stored.GetIndex()),
+			Nodes:   PbTreeKEMNodeMapConvert(stored.GetNodes()),
+			GroupID: stored.GetGroupID(),
+			Epoch:   stored.GetEpoch(),
+			Suite:   suite,
+		},
+	}, nil
+}
+
+// Marshal serializes the TreeKEMState, including the private keys of the nodes the member knows.
+func (t *TreeKEMState) Marshal() ([]byte, error) {
+	return proto.Marshal(treeKEMStatePbConvert(t))
+}
+
+// UnmarshalTreeKEMState loads a TreeKEMState serialized by Marshal.
+func UnmarshalTreeKEMState(data []byte) (*TreeKEMState, error) {
+	stored := &pb.StoredTreeKEMState{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	return pbTreeKEMStateConvert(stored)
+}
+
+// Marshal serializes the MultiTreeKEM together with its treekem.
+func (m *MultiTreeKEM) Marshal() ([]byte, error) {
+	return proto.Marshal(&pb.StoredMultiTreeKEM{
+		Version:          StateSerializationVersion,
+		TreeKEM:          treeKEMStatePbConvert(m.treekem),
+		ExternalNodes:    nodeStringMapPbConvert(m.externalNodes),
+		Roots:            nodeStringMapPbConvert(m.roots),
+		LastTreeKEMRoots: nodeStringMapPbConvert(m.lastTreeKemRoots),
+		Epochs:           rootEpochMapPbConvert(m.epochs),
+		HybridKEM:        m.hybridKEM,
+		CipherSuite:      uint32(m.suite.ID()),
+	})
+}
+
+// UnmarshalMultiTreeKEM loads a MultiTreeKEM serialized by Marshal. Its treekem is available through GetTreeKEM.
+func UnmarshalMultiTreeKEM(data []byte) (*MultiTreeKEM, error) {
+	stored := &pb.StoredMultiTreeKEM{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	if err := checkStateVersion(stored.GetVersion()); err != nil {
+		return nil, err
+	}
+	suite, err := CipherSuiteByID(CipherSuiteID(stored.GetCipherSuite()))
+	if err != nil {
+		return nil, err
+	}
+	tk, err := pbTreeKEMStateConvert(stored.GetTreeKEM())
+	if err != nil {
+		return nil, err
+	}
+
+	hybridKEM := make(map[string]bool, len(stored.GetHybridKEM()))
+	for id, hybrid := range stored.GetHybridKEM() {
+		hybridKEM[id] = hybrid
+	}
+
+	return &MultiTreeKEM{
+		treekem:          tk,
+		externalNodes:    pbNodeStringMapConvert(stored.GetExternalNodes()),
+		roots:            pbNodeStringMapConvert(stored.GetRoots()),
+		lastTreeKemRoots: pbNodeStringMapConvert(stored.GetLastTreeKEMRoots()),
+		epochs:           pbRootEpochMapConvert(stored.GetEpochs()),
+		hybridKEM:        hybridKEM,
+		suite:            suite,
+	}, nil
+}
+
+// Marshal serializes the MultiTreeKEMExternal.
+func (m *MultiTreeKEMExternal) Marshal() ([]byte, error) {
+	return proto.Marshal(&pb.StoredMultiTreeKEMExternal{
+		Version:     StateSerializationVersion,
+		TreeKEMRoot: TreeKEMNodePbConvert(&m.treekemRoot),
+		SelfNode:    TreeKEMNodePbConvert(&m.selfNode),
+		Root:        TreeKEMNodePbConvert(&m.root),
+		Epoch:       rootEpochPbConvert(m.epoch),
+		GroupID:     m.groupID,
+		HybridKEM:   m.hybridKEM,
+		CipherSuite: uint32(m.suite.ID()),
+	})
+}
+
+// UnmarshalMultiTreeKEMExternal loads a MultiTreeKEMExternal serialized by Marshal.
+func UnmarshalMultiTreeKEMExternal(data []byte) (*MultiTreeKEMExternal, error) {
+	stored := &pb.StoredMultiTreeKEMExternal{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	if err := checkStateVersion(stored.GetVersion()); err != nil {
+		return nil, err
+	}
+	suite, err := CipherSuiteByID(CipherSuiteID(stored.GetCipherSuite()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiTreeKEMExternal{
+		treekemRoot: *PbTreeKEMNodeConvert(stored.GetTreeKEMRoot()),
+		selfNode:    *PbTreeKEMNodeConvert(stored.GetSelfNode()),
+		root:        *PbTreeKEMNodeConvert(stored.GetRoot()),
+		epoch:       pbRootEpochConvert(stored.GetEpoch()),
+		groupID:     stored.GetGroupID(),
+		hybridKEM:   stored.GetHybridKEM(),
+		suite:       suite,
+	}, nil
+}
+
+// Marshal serializes the MlsMultiTree. The MLS state is not included, as it is kept by the MLS session.
+func (m *MlsMultiTree) Marshal() ([]byte, error) {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	return proto.Marshal(&pb.StoredMlsMultiTree{
+		Version:       StateSerializationVersion,
+		ExternalNodes: nodeStringMapPbConvert(m.externalNodes),
+		Roots:         nodeStringMapPbConvert(m.roots),
+		LastTreeRoots: nodeStringMapPbConvert(m.lastTreeRoots),
+		Epochs:        rootEpochMapPbConvert(m.epochs),
+		SelfPubKey:    m.selfPubKey,
+		SelfPrivKey:   m.selfPrivKey,
+		GroupID:       m.groupID,
+		CipherSuite:   uint32(m.suite.ID()),
+	})
+}
+
+// UnmarshalMlsMultiTree loads a MlsMultiTree serialized by Marshal on top of the given MLS state.
+func UnmarshalMlsMultiTree(data []byte, mlsState **mls.State) (*MlsMultiTree, error) {
+	stored := &pb.StoredMlsMultiTree{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	if err := checkStateVersion(stored.GetVersion()); err != nil {
+		return nil, err
+	}
+	suite, err := CipherSuiteByID(CipherSuiteID(stored.GetCipherSuite()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MlsMultiTree{
+		MlsState:      mlsState,
+		externalNodes: pbNodeStringMapConvert(stored.GetExternalNodes()),
+		roots:         pbNodeStringMapConvert(stored.GetRoots()),
+		lastTreeRoots: pbNodeStringMapConvert(stored.GetLastTreeRoots()),
+		epochs:        pbRootEpochMapConvert(stored.GetEpochs()),
+		selfPubKey:    stored.GetSelfPubKey(),
+		selfPrivKey:   stored.GetSelfPrivKey(),
+		groupID:       stored.GetGroupID(),
+		suite:         suite,
+	}, nil
+}
+
+// Marshal serializes the MlsMultiTreeExternal.
+func (m *MlsMultiTreeExternal) Marshal() ([]byte, error) {
+	return proto.Marshal(&pb.StoredMlsMultiTreeExternal{
+		Version:     StateSerializationVersion,
+		TreeKEMRoot: TreeKEMNodePbConvert(&m.treekemRoot),
+		SelfNode:    TreeKEMNodePbConvert(&m.selfNode),
+		Root:        TreeKEMNodePbConvert(&m.root),
+		Epoch:       rootEpochPbConvert(m.epoch),
+		GroupID:     m.groupID,
+		CipherSuite: uint32(m.suite.ID()),
+	})
+}
+
+// UnmarshalMlsMultiTreeExternal loads a MlsMultiTreeExternal serialized by Marshal.
+func UnmarshalMlsMultiTreeExternal(data []byte) (*MlsMultiTreeExternal, error) {
+	stored := &pb.StoredMlsMultiTreeExternal{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return nil, err
+	}
+	if err := checkStateVersion(stored.GetVersion()); err != nil {
+		return nil, err
+	}
+	suite, err := CipherSuiteByID(CipherSuiteID(stored.GetCipherSuite()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MlsMultiTreeExternal{
+		treekemRoot: *PbTreeKEMNodeConvert(stored.GetTreeKEMRoot()),
+		selfNode:    *PbTreeKEMNodeConvert(stored.GetSelfNode()),
+		root:        *PbTreeKEMNodeConvert(stored.GetRoot()),
+		epoch:       pbRootEpochConvert(stored.GetEpoch()),
+		groupID:     stored.GetGroupID(),
+		suite:       suite,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package treekem
+
+// This file implements snapshots of the CMRT state. A sender takes a snapshot before it issues a key update, so that it
+// can roll the update back if the server orders a concurrent key update of the group before it.
+
+// clone returns a copy of the tree that shares no nodes with it.
+func (tkem *TreeKEM) clone() *TreeKEM {
+	clone := *tkem
+	clone.Nodes = make(map[int]*Node, len(tkem.Nodes))
+	for n, node := range tkem.Nodes {
+		if node == nil {
+			clone.Nodes[n] = nil
+			continue
+		}
+		nodeCopy := *node
+		clone.Nodes[n] = &nodeCopy
+	}
+	return &clone
+}
+
+// copyNodes returns a copy of the given nodes.
+func copyNodes(nodes map[string]Node) map[string]Node {
+	nodesCopy := make(map[string]Node, len(nodes))
+	for id, node := range nodes {
+		nodesCopy[id] = node
+	}
+	return nodesCopy
+}
+
+// copyRootEpochs returns a copy of the given root epochs.
+func copyRootEpochs(epochs map[string]RootEpoch) map[string]RootEpoch {
+	epochsCopy := make(map[string]RootEpoch, len(epochs))
+	for id, epoch := range epochs {
+		epochsCopy[id] = epoch
+	}
+	return epochsCopy
+}
+
+// MultiTreeKEMSnapshot is the state of a MultiTreeKEM, including its treekem, at the time the snapshot was taken.
+type MultiTreeKEMSnapshot struct {
+	tkem             *TreeKEM
+	externalNodes    map[string]Node
+	roots            map[string]Node
+	lastTreeKemRoots map[string]Node
+	epochs           map[string]RootEpoch
+}
+
+// Snapshot returns the current state of the MultiTreeKEM.
+func (m *MultiTreeKEM) Snapshot() *MultiTreeKEMSnapshot {
+	return &MultiTreeKEMSnapshot{
+		tkem:             m.treekem.tkem.clone(),
+		externalNodes:    copyNodes(m.externalNodes),
+		roots:            copyNodes(m.roots),
+		lastTreeKemRoots: copyNodes(m.lastTreeKemRoots),
+		epochs:           copyRootEpochs(m.epochs),
+	}
+}
+
+// Restore rolls the MultiTreeKEM and its treekem back to the given snapshot. The treekem is restored in place, so the
+// holders of the TreeKEMState see the restored tree as well.
+func (m *MultiTreeKEM) Restore(snapshot *MultiTreeKEMSnapshot) {
+	m.treekem.tkem = snapshot.tkem.clone()
+	m.externalNodes = copyNodes(snapshot.externalNodes)
+	m.roots = copyNodes(snapshot.roots)
+	m.lastTreeKemRoots = copyNodes(snapshot.lastTreeKemRoots)
+	m.epochs = copyRootEpochs(snapshot.epochs)
+}
+
+// MultiTreeKEMExternalSnapshot is the state of a MultiTreeKEMExternal at the time the snapshot was taken.
+type MultiTreeKEMExternalSnapshot struct {
+	treekemRoot Node
+	selfNode    Node
+	root        Node
+	epoch       RootEpoch
+}
+
+// Snapshot returns the current state of the MultiTreeKEMExternal.
+func (m *MultiTreeKEMExternal) Snapshot() *MultiTreeKEMExternalSnapshot {
+	return &MultiTreeKEMExternalSnapshot{
+		treekemRoot: m.treekemRoot,
+		selfNode:    m.selfNode,
+		root:        m.root,
+		epoch:       m.epoch,
+	}
+}
+
+// Restore rolls the MultiTreeKEMExternal back to the given snapshot.
+func (m *MultiTreeKEMExternal) Restore(snapshot *MultiTreeKEMExternalSnapshot) {
+	m.treekemRoot = snapshot.treekemRoot
+	m.selfNode = snapshot.selfNode
+	m.root = snapshot.root
+	m.epoch = snapshot.epoch
+}
+
+// MlsMultiTreeSnapshot is the state of a MlsMultiTree at the time the snapshot was taken. The MLS state is not part of
+// it, as MLS states are never changed in place; the holder of the MLS state keeps the previous one instead.
+type MlsMultiTreeSnapshot struct {
+	externalNodes map[string]Node
+	roots         map[string]Node
+	lastTreeRoots map[string]Node
+	epochs        map[string]RootEpoch
+}
+
+// Snapshot returns the current state of the MlsMultiTree.
+func (m *MlsMultiTree) Snapshot() *MlsMultiTreeSnapshot {
+	m.mutexLock.RLock()
+	defer m.mutexLock.RUnlock()
+
+	return &MlsMultiTreeSnapshot{
+		externalNodes: copyNodes(m.externalNodes),
+		roots:         copyNodes(m.roots),
+		lastTreeRoots: copyNodes(m.lastTreeRoots),
+		epochs:        copyRootEpochs(m.epochs),
+	}
+}
+
+// Restore rolls the MlsMultiTree back to the given snapshot.
+func (m *MlsMultiTree) Restore(snapshot *MlsMultiTreeSnapshot) {
+	m.mutexLock.Lock()
+	defer m.mutexLock.Unlock()
+
+	m.externalNodes = copyNodes(snapshot.externalNodes)
+	m.roots = copyNodes(snapshot.roots)
+	m.lastTreeRoots = copyNodes(snapshot.lastTreeRoots)
+	m.epochs = copyRootEpochs(snapshot.epochs)
+}
+
+// MlsMultiTreeExternalSnapshot is the state of a MlsMultiTreeExternal at the time the snapshot was taken.
+type MlsMultiTreeExternalSnapshot struct {
+	treekemRoot Node
+	selfNode    Node
+	root        Node
+	epoch       RootEpoch
+}
+
+// Snapshot returns the current state of the MlsMultiTreeExternal.
+func (m *MlsMultiTreeExternal) Snapshot() *MlsMultiTreeExternalSnapshot {
+	return &MlsMultiTreeExternalSnapshot{
+		treekemRoot: m.treekemRoot,
+		selfNode:    m.selfNode,
+		root:        m.root,
+		epoch:       m.epoch,
+	}
+}
+
+// Restore rolls the MlsMultiTreeExternal back to the given snapshot.
+func (m *MlsMultiTreeExternal) Restore(snapshot *MlsMultiTreeExternalSnapshot) {
+	m.treekemRoot = snapshot.treekemRoot
+	m.selfNode = snapshot.selfNode
+	m.root = snapshot.root
+	m.epoch = snapshot.epoch
+}
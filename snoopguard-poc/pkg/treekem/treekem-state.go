@@ -9,8 +9,11 @@ type TreeKEMState struct {
 }
 
 type GroupInitKey struct {
-	Size     int
-	Frontier map[int]*Node
+	Size        int
+	Frontier    map[int]*Node
+	GroupID     string
+	Epoch       uint64
+	CipherSuite CipherSuiteID
 }
 
 type GroupAddForJoiner struct {
@@ -18,6 +21,9 @@ type GroupAddForJoiner struct {
 	EncryptedLeaf ECKEMCipherText
 	Frontier      map[int]*Node
 	Path          map[int]*Node
+	GroupID       string
+	Epoch         uint64
+	CipherSuite   CipherSuiteID
 }
 
 type GroupAddForGroup struct {
@@ -78,41 +84,85 @@ func (t *TreeKEMState) Copath() map[int]*Node {
 	return t.tkem.copath(t.tkem.Index)
 }
 
+// GroupID returns the group ID the ciphertexts of the tree are bound to.
+func (t *TreeKEMState) GroupID() string {
+	return t.tkem.GroupID
+}
+
+// SetGroupID sets the group ID the ciphertexts of the tree are bound to. Joiners learn it from the GroupInitKey, so
+// only the creator of the group needs to set it.
+func (t *TreeKEMState) SetGroupID(groupID string) {
+	t.tkem.GroupID = groupID
+}
+
+// Suite returns the cipher suite the tree runs with.
+func (t *TreeKEMState) Suite() CipherSuite {
+	return t.tkem.Suite
+}
+
+// Epoch returns the epoch of the tree, which is advanced by every handled add, update, remove and move.
+func (t *TreeKEMState) Epoch() uint64 {
+	return t.tkem.Epoch
+}
+
 func (t *TreeKEMState) Equal(other *TreeKEMState) bool {
 	return t.tkem.equal(other.tkem)
 }
 
 func TreeKEMStateOneMemberGroup(leaf []byte) *TreeKEMState {
+	return TreeKEMStateOneMemberGroupWithCipherSuite(p256Suite{}, leaf)
+}
+
+// TreeKEMStateOneMemberGroupWithCipherSuite creates a group with a single member that runs with the given cipher suite.
+func TreeKEMStateOneMemberGroupWithCipherSuite(suite CipherSuite, leaf []byte) *TreeKEMState {
 	state := NewTreeKEMState()
-	tkem := oneMemberGroup(leaf)
+	tkem := oneMemberGroup(suite, leaf)
 	state.tkem = tkem
 	return state
 }
 
 func TreeKEMStateFromGroupAdd(initLeaf []byte, groupAdd GroupAddForJoiner) (*TreeKEMState, error) {
-	kp, err := NewKeyPairFromSecret(initLeaf)
+	suite, err := CipherSuiteByID(groupAdd.CipherSuite)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := suite.DeriveKeyPair(initLeaf)
 	if err != nil {
 		return nil, err
 	}
 
-	leaf, err := ECKEMDecrypt(groupAdd.EncryptedLeaf, kp.Private.Bytes())
+	leaf, err := ECKEMDecrypt(suite, groupAdd.EncryptedLeaf, kp.Private.Bytes(), joinerContext(groupAdd.GroupID, groupAdd.Epoch, groupAdd.Size))
 	if err != nil {
 		return nil, err
 	}
 
 	state := NewTreeKEMState()
-	state.tkem = FromFrontier(groupAdd.Size, groupAdd.Frontier, leaf)
+	state.tkem = FromFrontier(suite, groupAdd.Size, groupAdd.Frontier, leaf)
+	state.tkem.GroupID = groupAdd.GroupID
+	state.tkem.Epoch = groupAdd.Epoch + 1
 	return state, nil
 }
 
 func TreeKEMStateFromUserAdd(leaf []byte, groupInitKey GroupInitKey) (*TreeKEMState, error) {
+	suite, err := CipherSuiteByID(groupInitKey.CipherSuite)
+	if err != nil {
+		return nil, err
+	}
 	state := NewTreeKEMState()
-	state.tkem = FromFrontier(groupInitKey.Size, groupInitKey.Frontier, leaf)
+	state.tkem = FromFrontier(suite, groupInitKey.Size, groupInitKey.Frontier, leaf)
+	state.tkem.GroupID = groupInitKey.GroupID
+	state.tkem.Epoch = groupInitKey.Epoch + 1
 	return state, nil
 }
 
 func TreeKEMStateJoin(leaf []byte, groupInitKey GroupInitKey) (UserAdd, error) {
-	tkem := FromFrontier(groupInitKey.Size, groupInitKey.Frontier, leaf)
+	suite, err := CipherSuiteByID(groupInitKey.CipherSuite)
+	if err != nil {
+		return UserAdd{}, err
+	}
+	tkem := FromFrontier(suite, groupInitKey.Size, groupInitKey.Frontier, leaf)
+	tkem.GroupID = groupInitKey.GroupID
+	tkem.Epoch = groupInitKey.Epoch
 	ct := tkem.Encrypt(leaf, tkem.Index)
 	ua := UserAdd{
 		Size:        tkem.Size,
@@ -129,7 +179,7 @@ func (t *TreeKEMState) Add(userInitPub []byte) (GroupAddForGroup, GroupAddForJoi
 		return GroupAddForGroup{}, GroupAddForJoiner{}, err
 	}
 
-	encryptedLeaf, err := ECKEMEncrypt(leaf, userInitPub)
+	encryptedLeaf, err := ECKEMEncrypt(t.Suite(), leaf, userInitPub, joinerContext(t.GroupID(), t.Epoch(), t.Size()))
 	if err != nil {
 		return GroupAddForGroup{}, GroupAddForJoiner{}, err
 	}
@@ -150,6 +200,9 @@ func (t *TreeKEMState) Add(userInitPub []byte) (GroupAddForGroup, GroupAddForJoi
 		Size:          t.Size(),
 		EncryptedLeaf: encryptedLeaf,
 		Frontier:      t.tkem.frontier(),
+		GroupID:       t.GroupID(),
+		Epoch:         t.Epoch(),
+		CipherSuite:   t.Suite().ID(),
 	}
 
 	return groupAddForGroup, groupAddForJoiner, nil
@@ -188,8 +241,11 @@ func (t *TreeKEMState) Move(leaf []byte, index int, copath map[int]*Node) UserMo
 
 func (t *TreeKEMState) GroupInitKey() GroupInitKey {
 	return GroupInitKey{
-		Size:     t.Size(),
-		Frontier: t.tkem.frontier(),
+		Size:        t.Size(),
+		Frontier:    t.tkem.frontier(),
+		GroupID:     t.GroupID(),
+		Epoch:       t.Epoch(),
+		CipherSuite: t.Suite().ID(),
 	}
 }
 
@@ -213,11 +269,30 @@ func (t *TreeKEMState) Self() *Node {
 	return t.tkem.Nodes[t.tkem.Index*2]
 }
 
+// PublicTree returns the public part of every node in the tree. The frontier given to a joiner is enough to join and
+// update, but removing a member requires the public keys along its copath, so joiners also receive the public tree.
+func (t *TreeKEMState) PublicTree() map[int]*Node {
+	nodes := make(map[int]*Node)
+	for n, node := range t.tkem.Nodes {
+		nodes[n] = &Node{
+			Public:     node.Public,
+			SignPublic: node.SignPublic,
+		}
+	}
+	return nodes
+}
+
+// HandlePublicTree merges the public tree of another member, leaving the known nodes alone.
+func (t *TreeKEMState) HandlePublicTree(nodes map[int]*Node) {
+	t.tkem.merge(nodes, true)
+}
+
 func (t *TreeKEMState) HandleUserAdd(ua UserAdd) {
 	pt := t.tkem.Decrypt(t.tkem.Size, ua.Ciphertexts)
 	t.tkem.merge(ua.Nodes, false)
 	t.tkem.merge(pt.Nodes, false)
 	t.tkem.Size += 1
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleGroupAdd(ga GroupAddForGroup) {
@@ -225,17 +300,20 @@ func (t *TreeKEMState) HandleGroupAdd(ga GroupAddForGroup) {
 	t.tkem.merge(ga.Nodes, false)
 	t.tkem.merge(pt.Nodes, false)
 	t.tkem.Size += 1
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleSelfUpdate(update UserUpdate, leaf []byte) {
-	privateNodes := hashUp(2*t.tkem.Index, t.tkem.Size, leaf)
+	privateNodes := hashUp(t.tkem.Suite, 2*t.tkem.Index, t.tkem.Size, leaf)
 	t.tkem.merge(privateNodes, false)
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleUpdate(update UserUpdate) {
 	pt := t.tkem.Decrypt(update.From, update.Ciphertexts)
 	t.tkem.merge(update.Nodes, false)
 	t.tkem.merge(pt.Nodes, false)
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleRemove(remove UserRemove) {
@@ -243,14 +321,16 @@ func (t *TreeKEMState) HandleRemove(remove UserRemove) {
 	t.tkem.remove(remove.Index)
 	t.tkem.merge(pt.Root, false)
 	t.tkem.merge(remove.Copath, true)
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleSelfMove(move UserMove, leaf []byte) {
-	privateNodes := hashUp(2*move.To, t.tkem.Size, leaf)
+	privateNodes := hashUp(t.tkem.Suite, 2*move.To, t.tkem.Size, leaf)
 	t.tkem.remove(move.From)
 	t.tkem.merge(privateNodes, false)
 	t.tkem.merge(move.Copath, true)
 	t.tkem.Index = move.To
+	t.tkem.Epoch++
 }
 
 func (t *TreeKEMState) HandleMove(move UserMove) {
@@ -259,4 +339,14 @@ func (t *TreeKEMState) HandleMove(move UserMove) {
 	t.tkem.merge(move.Nodes, false)
 	t.tkem.merge(move.Copath, true)
 	t.tkem.merge(pt.Nodes, false)
+	t.tkem.Epoch++
+}
+
+// joinerContext returns the context that binds the encrypted leaf of a GroupAddForJoiner to the leaf the joiner takes.
+func joinerContext(groupID string, epoch uint64, size int) ECKEMContext {
+	return ECKEMContext{
+		GroupID:   groupID,
+		Epoch:     epoch,
+		NodeIndex: 2 * size,
+	}
 }
@@ -6,9 +6,12 @@ import (
 
 // TreeKEM is a struct representing a TreeKEM object.
 type TreeKEM struct {
-	Size  int
-	Index int
-	Nodes map[int]*Node
+	Size    int
+	Index   int
+	Nodes   map[int]*Node
+	GroupID string
+	Epoch   uint64
+	Suite   CipherSuite
 }
 
 // Node is a struct representing a TreeKEM node.
@@ -25,6 +28,7 @@ func NewTreeKEM() *TreeKEM {
 		Size:  0,
 		Index: 0,
 		Nodes: make(map[int]*Node),
+		Suite: p256Suite{},
 	}
 }
 
@@ -36,15 +40,15 @@ func hash(x64 []byte) []byte {
 }
 
 // hashUp function
-func hashUp(index, size int, h []byte) map[int]*Node {
+func hashUp(suite CipherSuite, index, size int, h []byte) map[int]*Node {
 	nodes := make(map[int]*Node)
 	n := index
 	root := root(size)
 	path := []int{n}
 
 	for {
-		kp, err := NewKeyPairFromSecret(h)
-		kpSign, err := NewSigningKeyPairFromSecret(h)
+		kp, err := suite.DeriveKeyPair(h)
+		kpSign, err := suite.DeriveSigningKeyPair(h)
 		if err != nil {
 			panic(err)
 		}
@@ -52,8 +56,8 @@ func hashUp(index, size int, h []byte) map[int]*Node {
 			Secret:      h,
 			Public:      kp.Public.Bytes(),
 			Private:     kp.Private.Bytes(),
-			SignPublic:  kpSign.Public.Bytes(),
-			SignPrivate: kpSign.Private.Bytes(),
+			SignPublic:  kpSign.Public,
+			SignPrivate: kpSign.Private,
 		}
 
 		if n == root {
@@ -62,7 +66,7 @@ func hashUp(index, size int, h []byte) map[int]*Node {
 
 		n = parent(n, size)
 		path = append(path, n)
-		h = hash(h)
+		h = suite.Hash(h)
 	}
 
 	return nodes
@@ -71,36 +75,47 @@ func hashUp(index, size int, h []byte) map[int]*Node {
 /*
  * oneMemberGroup constructs a TreeKEM representing a group with a single member, with the given leaf secret.
  */
-func oneMemberGroup(leaf []byte) *TreeKEM {
+func oneMemberGroup(suite CipherSuite, leaf []byte) *TreeKEM {
 	tkem := &TreeKEM{
 		Size:  1,
 		Index: 0,
+		Suite: suite,
 	}
 	tkem.Nodes = make(map[int]*Node)
-	tkem.merge(hashUp(0, 1, leaf), false)
+	tkem.merge(hashUp(suite, 0, 1, leaf), false)
 	return tkem
 }
 
 // FromFrontier constructs a tree that extends a tree with the given size and frontier by adding a member with the given leaf secret.
-func FromFrontier(size int, frontier map[int]*Node, leaf []byte) *TreeKEM {
+func FromFrontier(suite CipherSuite, size int, frontier map[int]*Node, leaf []byte) *TreeKEM {
 	tkem := &TreeKEM{
 		Size:  size + 1,
 		Index: size,
+		Suite: suite,
 	}
 	tkem.Nodes = make(map[int]*Node)
 	tkem.merge(frontier, false)
 
-	nodes := hashUp(2*tkem.Index, tkem.Size, leaf)
+	nodes := hashUp(suite, 2*tkem.Index, tkem.Size, leaf)
 	tkem.merge(nodes, false)
 	return tkem
 }
 
+// eckemContext returns the context that binds a ciphertext to the given node of the tree in the current epoch.
+func (tkem *TreeKEM) eckemContext(node int) ECKEMContext {
+	return ECKEMContext{
+		GroupID:   tkem.GroupID,
+		Epoch:     tkem.Epoch,
+		NodeIndex: node,
+	}
+}
+
 // EncryptToSubtree encrypts a value so that it can be decrypted by all nodes in the subtree with the indicated head.
 func (tkem *TreeKEM) EncryptToSubtree(head int, value []byte) map[int]ECKEMCipherText {
 	encryptions := make(map[int]ECKEMCipherText)
 
 	if node, ok := tkem.Nodes[head]; ok {
-		enc, err := ECKEMEncrypt(value, node.Public)
+		enc, err := ECKEMEncrypt(tkem.Suite, value, node.Public, tkem.eckemContext(head))
 		if err != nil {
 			panic(err)
 		}
@@ -168,7 +183,7 @@ func (tkem *TreeKEM) Encrypt(leaf []byte, except int) *TreeKEMCiphertext {
 	copath := trCopath(2*except, tkem.Size)
 
 	// Generate hashes up the tree
-	privateNodes := hashUp(2*except, tkem.Size, leaf)
+	privateNodes := hashUp(tkem.Suite, 2*except, tkem.Size, leaf)
 	nodes := make(map[int]*Node)
 	for n, node := range privateNodes {
 		nodes[n] = &Node{
@@ -242,14 +257,14 @@ func (tkem *TreeKEM) Decrypt(index int, ciphertexts []map[int]ECKEMCipherText) *
 		panic("Decrypt fail")
 	}
 
-	h, err := ECKEMDecrypt(encryptions[decNode], tkem.Nodes[decNode].Private)
+	h, err := ECKEMDecrypt(tkem.Suite, encryptions[decNode], tkem.Nodes[decNode].Private, tkem.eckemContext(decNode))
 	if err != nil {
 		panic(err)
 	}
 
 	rootNode := root(senderSize)
 	newDirpath := append(trDirpath(2*tkem.Index, senderSize), rootNode)
-	nodes := hashUp(newDirpath[dirIndex+1], senderSize, h)
+	nodes := hashUp(tkem.Suite, newDirpath[dirIndex+1], senderSize, h)
 
 	root := make(map[int]*Node)
 	root[rootNode] = nodes[rootNode]
@@ -303,7 +318,7 @@ func (tkem *TreeKEM) DecryptRaw(index int, ciphertexts []map[int]ECKEMCipherText
 		panic("Decrypt fail")
 	}
 
-	h, err := ECKEMDecrypt(encryptions[decNode], tkem.Nodes[decNode].Private)
+	h, err := ECKEMDecrypt(tkem.Suite, encryptions[decNode], tkem.Nodes[decNode].Private, tkem.eckemContext(decNode))
 	if err != nil {
 		panic(err)
 	}
@@ -1,8 +1,15 @@
 package treekem
 
 import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
 	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	"testing"
 )
 
@@ -59,17 +66,240 @@ func testMembers(size int) []*TreeKEM {
 }
 
 func TestECKEM(t *testing.T) {
+	for _, id := range SupportedCipherSuites() {
+		suite := MustCipherSuite(id)
+		original := []byte{0, 1, 2, 3}
+		kp, err := suite.DeriveKeyPair([]byte("eckem test"))
+		assert.Nilf(t, err, "error generating key pair: %s", err)
+
+		context := ECKEMContext{GroupID: "group", Epoch: 3, NodeIndex: 5}
+		encrypted, err := ECKEMEncrypt(suite, original, kp.Public.Bytes(), context)
+		assert.Nilf(t, err, "error encrypting: %s", err)
+		assert.Equal(t, ECKEMVersionHPKE, encrypted.Version, "new ciphertexts should use HPKE")
+
+		decrypted, err := ECKEMDecrypt(suite, encrypted, kp.Private.Bytes(), context)
+		assert.Nilf(t, err, "error decrypting: %s", err)
+
+		assert.Equal(t, original, decrypted, "decrypted value should equal original")
+
+		// The ciphertext is bound to the group, the epoch and the node
+		for _, other := range []ECKEMContext{
+			{GroupID: "other group", Epoch: 3, NodeIndex: 5},
+			{GroupID: "group", Epoch: 4, NodeIndex: 5},
+			{GroupID: "group", Epoch: 3, NodeIndex: 6},
+		} {
+			_, err = ECKEMDecrypt(suite, encrypted, kp.Private.Bytes(), other)
+			assert.NotNilf(t, err, "ciphertext should not decrypt under context %v", other)
+		}
+
+		// Unknown versions are rejected
+		encrypted.Version = 99
+		_, err = ECKEMDecrypt(suite, encrypted, kp.Private.Bytes(), context)
+		assert.NotNil(t, err, "unknown version should be rejected")
+	}
+}
+
+func TestHybridECKEM(t *testing.T) {
+	for _, id := range SupportedCipherSuites() {
+		suite := MustCipherSuite(id)
+		original := []byte{0, 1, 2, 3}
+		kp, err := DeriveHybridKeyPair([]byte("hybrid eckem test"))
+		assert.Nilf(t, err, "error generating key pair: %s", err)
+		assert.Len(t, kp.Public, HybridPublicKeySize, "hybrid public key size")
+
+		again, _ := DeriveHybridKeyPair([]byte("hybrid eckem test"))
+		assert.Equal(t, kp, again, "hybrid key pairs should be derived deterministically")
+
+		context := ECKEMContext{GroupID: "group", NodeIndex: ExternalNodeIndex}
+		encrypted, err := HybridECKEMEncrypt(suite, original, kp.Public, context)
+		assert.Nilf(t, err, "error encrypting: %s", err)
+		assert.Equal(t, ECKEMVersionHybrid, encrypted.Version, "ciphertext should use the hybrid KEM")
+		assert.Len(t, encrypted.Public, HybridEncapsulationSize, "hybrid encapsulation size")
+
+		decrypted, err := ECKEMDecrypt(suite, encrypted, kp.Private, context)
+		assert.Nilf(t, err, "error decrypting: %s", err)
+		assert.Equal(t, original, decrypted, "decrypted value should equal original")
+
+		_, err = ECKEMDecrypt(suite, encrypted, kp.Private, ECKEMContext{GroupID: "other group", NodeIndex: ExternalNodeIndex})
+		assert.NotNil(t, err, "ciphertext should not decrypt under another context")
+
+		other, _ := GenerateHybridKeyPair()
+		_, err = ECKEMDecrypt(suite, encrypted, other.Private, context)
+		assert.NotNil(t, err, "ciphertext should not decrypt with another key")
+
+		// A classical ciphertext cannot be passed off to a hybrid key, nor the other way round
+		classicalKp, _ := suite.DeriveKeyPair([]byte("eckem test"))
+		classical, _ := ECKEMEncrypt(suite, original, classicalKp.Public.Bytes(), context)
+		_, err = ECKEMDecrypt(suite, classical, kp.Private, context)
+		assert.NotNil(t, err, "classical ciphertext should not decrypt with a hybrid key")
+		_, err = ECKEMDecrypt(suite, encrypted, classicalKp.Private.Bytes(), context)
+		assert.NotNil(t, err, "hybrid ciphertext should not decrypt with a classical key")
+
+		dummy, err := HybridECKEMEncryptDummy(suite, len(original))
+		assert.Nilf(t, err, "error encrypting dummy: %s", err)
+		assert.Equal(t, encrypted.Version, dummy.Version, "dummy should look like a real ciphertext")
+		assert.Equal(t, len(encrypted.Public), len(dummy.Public), "dummy should look like a real ciphertext")
+		assert.Equal(t, len(encrypted.CipherText), len(dummy.CipherText), "dummy should look like a real ciphertext")
+	}
+}
+
+func TestECKEMLegacy(t *testing.T) {
 	original := []byte{0, 1, 2, 3}
 	kp, err := NewKeyPair()
 	assert.Nilf(t, err, "error generating key pair: %s", err)
 
-	encrypted, err := ECKEMEncrypt(original, kp.Public.Bytes())
-	assert.Nilf(t, err, "error encrypting: %s", err)
+	// Encrypt as the original ECKEM did: the raw ECDH output is the AES-GCM key
+	kpE, err := NewKeyPair()
+	assert.Nilf(t, err, "error generating key pair: %s", err)
+	ek, err := SecretFromBytes(kpE.Private.Bytes(), kp.Public.Bytes())
+	assert.Nilf(t, err, "error computing secret: %s", err)
+	block, _ := aes.NewCipher(ek)
+	aesgcm, _ := cipher.NewGCM(block)
+	iv, _ := generateRandomBytes(12)
+	legacy := ECKEMCipherText{
+		Public:     kpE.Public.Bytes(),
+		IV:         iv,
+		CipherText: aesgcm.Seal(nil, iv, original, nil),
+	}
+	assert.Equal(t, ECKEMVersionLegacy, legacy.Version, "the zero version should be the legacy ECKEM")
 
-	decrypted, err := ECKEMDecrypt(encrypted, kp.Private.Bytes())
-	assert.Nilf(t, err, "error decrypting: %s", err)
+	_, err = ECKEMDecrypt(p256Suite{}, legacy, kp.Private.Bytes(), ECKEMContext{GroupID: "group"})
+	assert.ErrorIs(t, err, ErrLegacyECKEM, "legacy ciphertexts are not bound to a context and should be rejected by default")
 
+	// Old ciphertexts stay readable with the compatibility option
+	SetLegacyECKEMCompatibility(true)
+	defer SetLegacyECKEMCompatibility(false)
+	decrypted, err := ECKEMDecrypt(p256Suite{}, legacy, kp.Private.Bytes(), ECKEMContext{GroupID: "group"})
+	assert.Nilf(t, err, "error decrypting legacy ciphertext: %s", err)
 	assert.Equal(t, original, decrypted, "decrypted value should equal original")
+	_, err = ECKEMDecrypt(x25519Suite{}, legacy, kp.Private.Bytes(), ECKEMContext{GroupID: "group"})
+	assert.NotNil(t, err, "legacy ciphertexts only exist for P-256")
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.Nilf(t, err, "error decoding hex: %s", err)
+	return b
+}
+
+// TestHPKEVectors checks the HPKE implementation against the base mode test vectors of RFC 9180 for the KEMs and AEADs
+// of the supported cipher suites: DHKEM(P-256, HKDF-SHA256) with AES-128-GCM (Appendix A.3.1) and
+// DHKEM(X25519, HKDF-SHA256) with ChaCha20-Poly1305 (Appendix A.2.1).
+func TestHPKEVectors(t *testing.T) {
+	vectors := []struct {
+		suite        CipherSuite
+		ikmE         string
+		ikmR         string
+		skRm         string
+		pkRm         string
+		enc          string
+		sharedSecret string
+		baseNonce    string
+		ciphertexts  []string
+	}{
+		{
+			suite:        p256Suite{},
+			ikmE:         "4270e54ffd08d79d5928020af4686d8f6b7d35dbe470265f1f5aa22816ce860e",
+			ikmR:         "668b37171f1072f3cf12ea8a236a45df23fc13b82af3609ad1e354f6ef817550",
+			skRm:         "f3ce7fdae57e1a310d87f1ebbde6f328be0a99cdbcadf4d6589cf29de4b8ffd2",
+			pkRm:         "04fe8c19ce0905191ebc298a9245792531f26f0cece2460639e8bc39cb7f706a826a779b4cf969b8a0e539c7f62fb3d30ad6aa8f80e30f1d128aafd68a2ce72ea0",
+			enc:          "04a92719c6195d5085104f469a8b9814d5838ff72b60501e2c4466e5e67b325ac98536d7b61a1af4b78e5b7f951c0900be863c403ce65c9bfcb9382657222d18c4",
+			sharedSecret: "c0d26aeab536609a572b07695d933b589dcf363ff9d93c93adea537aeabb8cb8",
+			baseNonce:    "4e0bc5018beba4bf004cca59",
+			ciphertexts: []string{
+				"5ad590bb8baa577f8619db35a36311226a896e7342a6d836d8b7bcd2f20b6c7f9076ac232e3ab2523f39513434",
+				"fa6f037b47fc21826b610172ca9637e82d6e5801eb31cbd3748271affd4ecb06646e0329cbdf3c3cd655b28e82",
+			},
+		},
+		{
+			suite:        x25519Suite{},
+			ikmE:         "909a9b35d3dc4713a5e72a4da274b55d3d3821a37e5d099e74a647db583a904b",
+			ikmR:         "1ac01f181fdf9f352797655161c58b75c656a6cc2716dcb66372da835542e1df",
+			skRm:         "8057991eef8f1f1af18f4a9491d16a1ce333f695d4db8e38da75975c4478e0fb",
+			pkRm:         "4310ee97d88cc1f088a5576c77ab0cf5c3ac797f3d95139c6c84b5429c59662a",
+			enc:          "1afa08d3dec047a643885163f1180476fa7ddb54c6a8029ea33f95796bf2ac4a",
+			sharedSecret: "0bbe78490412b4bbea4812666f7916932b828bba79942424abb65244930d69a7",
+			baseNonce:    "5c4d98150661b848853b547f",
+			ciphertexts: []string{
+				"1c5250d8034ec2b784ba2cfd69dbdb8af406cfe3ff938e131f0def8c8b60b4db21993c62ce81883d2dd1b51a28",
+				"6b53c051e4199c518de79594e1c4ab18b96f081549d45ce015be002090bb119e85285337cc95ba5f59992dc98c",
+			},
+		},
+	}
+	info := mustDecodeHex(t, "4f6465206f6e2061204772656369616e2055726e")
+	plaintext := mustDecodeHex(t, "4265617574792069732074727574682c20747275746820626561757479")
+
+	for _, v := range vectors {
+		s := v.suite.hpke()
+		skRm := mustDecodeHex(t, v.skRm)
+		pkRm := mustDecodeHex(t, v.pkRm)
+		enc := mustDecodeHex(t, v.enc)
+		sharedSecret := mustDecodeHex(t, v.sharedSecret)
+
+		skE, err := s.deriveKeyPair(mustDecodeHex(t, v.ikmE))
+		assert.Nilf(t, err, "error deriving ephemeral key pair: %s", err)
+		skR, err := s.deriveKeyPair(mustDecodeHex(t, v.ikmR))
+		assert.Nilf(t, err, "error deriving recipient key pair: %s", err)
+		assert.Equal(t, skRm, skR.Bytes(), "derived skRm does not match")
+		assert.Equal(t, pkRm, skR.PublicKey().Bytes(), "derived pkRm does not match")
+
+		ss, encapsulated, err := s.encap(skR.PublicKey(), skE)
+		assert.Nilf(t, err, "error encapsulating: %s", err)
+		assert.Equal(t, enc, encapsulated, "enc does not match")
+		assert.Equal(t, sharedSecret, ss, "shared secret does not match")
+
+		ss, err = s.decap(enc, skR)
+		assert.Nilf(t, err, "error decapsulating: %s", err)
+		assert.Equal(t, sharedSecret, ss, "decapsulated shared secret does not match")
+
+		_, sender, err := s.setupBaseSWithEphemeral(pkRm, info, skE)
+		assert.Nilf(t, err, "error setting up sender: %s", err)
+		assert.Equal(t, mustDecodeHex(t, v.baseNonce), sender.baseNonce, "base nonce does not match")
+
+		recipient, err := s.setupBaseR(enc, skRm, info)
+		assert.Nilf(t, err, "error setting up recipient: %s", err)
+
+		for i, c := range v.ciphertexts {
+			aad := []byte(fmt.Sprintf("Count-%d", i))
+			ciphertext := mustDecodeHex(t, c)
+			assert.Equalf(t, ciphertext, sender.Seal(aad, plaintext), "ciphertext %d does not match", i)
+
+			opened, err := recipient.Open(aad, ciphertext)
+			assert.Nilf(t, err, "error opening ciphertext %d: %s", i, err)
+			assert.Equalf(t, plaintext, opened, "plaintext %d does not match", i)
+		}
+	}
+}
+
+func TestECKEMDummyIndistinguishable(t *testing.T) {
+	kp, err := NewKeyPair()
+	assert.Nilf(t, err, "error generating key pair: %s", err)
+
+	secret, _ := generateRandomBytes(32)
+	genuine, err := ECKEMEncrypt(p256Suite{}, secret, kp.Public.Bytes(), ECKEMContext{})
+	assert.Nilf(t, err, "error encrypting: %s", err)
+
+	publics := make(map[string]bool)
+	for i := 0; i < 64; i++ {
+		dummy, err := ECKEMEncryptDummy(p256Suite{}, len(secret))
+		assert.Nilf(t, err, "error generating dummy: %s", err)
+
+		// Every field should have the same shape as a genuine ciphertext
+		assert.Equal(t, len(genuine.Public), len(dummy.Public), "dummy public key length differs from real")
+		assert.Equal(t, len(genuine.IV), len(dummy.IV), "dummy IV length differs from real")
+		assert.Equal(t, len(genuine.CipherText), len(dummy.CipherText), "dummy ciphertext length differs from real")
+
+		// The public key should be a valid curve point
+		_, err = ecdh.P256().NewPublicKey(dummy.Public)
+		assert.Nilf(t, err, "dummy public key is not a valid P-256 point: %s", err)
+
+		// Nobody, including the intended recipient of the real ciphertext, can decrypt it
+		_, err = ECKEMDecrypt(p256Suite{}, dummy, kp.Private.Bytes(), ECKEMContext{})
+		assert.NotNil(t, err, "dummy ciphertext should not be decryptable")
+
+		assert.False(t, publics[string(dummy.Public)], "dummy public key should be fresh")
+		publics[string(dummy.Public)] = true
+	}
 }
 
 func TestEncryptDecrypt(t *testing.T) {
@@ -82,7 +312,7 @@ func TestEncryptDecrypt(t *testing.T) {
 		// Have each member send and be received by all members
 		for _, m := range members {
 			ct := m.Encrypt(seed, m.Index)
-			privateNodes := hashUp(2*m.Index, m.Size, seed)
+			privateNodes := hashUp(p256Suite{}, 2*m.Index, m.Size, seed)
 
 			m.merge(ct.Nodes, false)
 			m.merge(privateNodes, false)
@@ -198,6 +428,39 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateBoundToGroupAndEpoch(t *testing.T) {
+	leaf, _ := generateRandomBytes(32)
+	creator := TreeKEMStateOneMemberGroup(leaf)
+	creator.SetGroupID("group")
+
+	joinerLeaf, _ := generateRandomBytes(32)
+	gik := creator.GroupInitKey()
+	ua, err := TreeKEMStateJoin(joinerLeaf, gik)
+	assert.Nilf(t, err, "error joining group: %s", err)
+	joiner, err := TreeKEMStateFromUserAdd(joinerLeaf, gik)
+	assert.Nilf(t, err, "error creating user add: %s", err)
+	creator.HandleUserAdd(ua)
+
+	assert.Equal(t, "group", joiner.GroupID(), "joiner should learn the group ID")
+	assert.Equal(t, creator.Epoch(), joiner.Epoch(), "members should agree on the epoch")
+
+	leaf, _ = generateRandomBytes(32)
+	userUpdate := creator.Update(leaf)
+	creator.HandleSelfUpdate(userUpdate, leaf)
+
+	// The update cannot be processed in another group
+	other, _ := TreeKEMStateFromUserAdd(joinerLeaf, gik)
+	other.SetGroupID("other group")
+	assert.Panics(t, func() { other.HandleUpdate(userUpdate) }, "update should not decrypt in another group")
+
+	joiner.HandleUpdate(userUpdate)
+	assert.True(t, groupEqual(creator, joiner), "members should be equal after the update")
+	assert.Equal(t, creator.Epoch(), joiner.Epoch(), "members should agree on the epoch")
+
+	// Replaying the update in a later epoch fails
+	assert.Panics(t, func() { joiner.HandleUpdate(userUpdate) }, "replayed update should not decrypt")
+}
+
 func TestRemove(t *testing.T) {
 	for testGroupSize := 4; testGroupSize <= 32; testGroupSize++ {
 		leaf, _ := generateRandomBytes(32)
@@ -259,3 +522,130 @@ func TestRemove(t *testing.T) {
 		}
 	}
 }
+
+func TestCipherSuites(t *testing.T) {
+	for _, id := range SupportedCipherSuites() {
+		suite, err := CipherSuiteByID(id)
+		assert.Nilf(t, err, "error getting cipher suite: %s", err)
+
+		leaf, _ := generateRandomBytes(32)
+		creator := TreeKEMStateOneMemberGroupWithCipherSuite(suite, leaf)
+		members := []*TreeKEMState{creator}
+
+		for i := 1; i < 6; i++ {
+			leaf, _ = generateRandomBytes(32)
+			var joiner *TreeKEMState
+			if i%2 == 0 {
+				// The group init key tells the joiner which suite to run with
+				gik := members[0].GroupInitKey()
+				assert.Equal(t, id, gik.CipherSuite, "group init key should carry the cipher suite")
+				ua, err := TreeKEMStateJoin(leaf, PbTreeKEMGroupInitKeyConvert(TreeKEMGroupInitKeyPbConvert(gik)))
+				assert.Nilf(t, err, "error joining group: %s", err)
+				joiner, err = TreeKEMStateFromUserAdd(leaf, gik)
+				assert.Nilf(t, err, "error creating user add: %s", err)
+				for _, m := range members {
+					m.HandleUserAdd(ua)
+				}
+			} else {
+				initKP, err := suite.DeriveKeyPair(leaf)
+				assert.Nilf(t, err, "error creating key pair: %s", err)
+				gaGroup, gaJoiner, err := members[len(members)-1].Add(initKP.Public.Bytes())
+				assert.Nilf(t, err, "error creating group add: %s", err)
+				joiner, err = TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+				assert.Nilf(t, err, "error handling group add: %s", err)
+				for _, m := range members {
+					m.HandleGroupAdd(gaGroup)
+				}
+			}
+			assert.Equal(t, id, joiner.Suite().ID(), "joiner should run with the suite of the group")
+			members = append(members, joiner)
+		}
+
+		for _, m1 := range members {
+			leaf, _ = generateRandomBytes(32)
+			userUpdate := m1.Update(leaf)
+			m1.HandleSelfUpdate(userUpdate, leaf)
+			for _, m2 := range members {
+				if m2.Index() == m1.Index() {
+					continue
+				}
+				m2.HandleUpdate(userUpdate)
+				assert.Truef(t, groupEqual(m1, m2), "members %d -> %d are not equal with suite 0x%04x", m1.Index(), m2.Index(), uint16(id))
+			}
+		}
+	}
+
+	// Unsupported suites are rejected
+	_, err := CipherSuiteByID(0xffff)
+	assert.NotNil(t, err, "unsupported cipher suite should be rejected")
+	leaf, _ := generateRandomBytes(32)
+	gik := TreeKEMStateOneMemberGroup(leaf).GroupInitKey()
+	gik.CipherSuite = 0xffff
+	_, err = TreeKEMStateJoin(leaf, gik)
+	assert.NotNil(t, err, "joining with an unsupported cipher suite should fail")
+}
+
+func TestTreeKEMStateSerialization(t *testing.T) {
+	for _, id := range SupportedCipherSuites() {
+		suite, _ := CipherSuiteByID(id)
+		leaf, _ := generateRandomBytes(32)
+		creator := TreeKEMStateOneMemberGroupWithCipherSuite(suite, leaf)
+		creator.SetGroupID("group")
+		members := []*TreeKEMState{creator}
+
+		for i := 1; i < 5; i++ {
+			leaf, _ = generateRandomBytes(32)
+			initKP, _ := suite.DeriveKeyPair(leaf)
+			gaGroup, gaJoiner, _ := members[len(members)-1].Add(initKP.Public.Bytes())
+			joiner, _ := TreeKEMStateFromGroupAdd(leaf, gaJoiner)
+			for _, m := range members {
+				m.HandleGroupAdd(gaGroup)
+			}
+			members = append(members, joiner)
+		}
+
+		// Every member restores to an identical state, private keys included
+		for i, m := range members {
+			data, err := m.Marshal()
+			assert.Nilf(t, err, "error marshaling state: %s", err)
+			restored, err := UnmarshalTreeKEMState(data)
+			assert.Nilf(t, err, "error unmarshaling state: %s", err)
+
+			assert.True(t, groupEqual(m, restored), "restored member %d is not equal", i)
+			assert.True(t, m.Equal(restored), "restored member %d does not have the same tree", i)
+			assert.Equal(t, m.Index(), restored.Index(), "restored member %d has another index", i)
+			assert.Equal(t, m.Epoch(), restored.Epoch(), "restored member %d has another epoch", i)
+			assert.Equal(t, m.GroupID(), restored.GroupID(), "restored member %d has another group ID", i)
+			assert.Equal(t, id, restored.Suite().ID(), "restored member %d has another cipher suite", i)
+			for n, node := range m.Nodes() {
+				if node == nil {
+					continue
+				}
+				assert.Truef(t, nodeEqual(node, restored.Nodes()[n]), "node %d of member %d is not equal", n, i)
+				assert.Equal(t, node.Private, restored.Nodes()[n].Private, "node %d of member %d lost its private key", n, i)
+			}
+			members[i] = restored
+		}
+
+		// The restored members keep working
+		for _, m1 := range members {
+			leaf, _ = generateRandomBytes(32)
+			userUpdate := m1.Update(leaf)
+			m1.HandleSelfUpdate(userUpdate, leaf)
+			for _, m2 := range members {
+				if m2.Index() == m1.Index() {
+					continue
+				}
+				m2.HandleUpdate(userUpdate)
+				assert.Truef(t, groupEqual(m1, m2), "members %d -> %d are not equal after restoring", m1.Index(), m2.Index())
+			}
+		}
+	}
+
+	// States written with an unknown version are rejected
+	data, _ := proto.Marshal(&pb.StoredTreeKEMState{Version: StateSerializationVersion + 1})
+	_, err := UnmarshalTreeKEMState(data)
+	assert.ErrorIs(t, err, ErrUnsupportedStateVersion, "unknown version should be rejected")
+	_, err = UnmarshalTreeKEMState([]byte{0xff})
+	assert.NotNil(t, err, "garbage should be rejected")
+}
@@ -44,8 +44,11 @@ PbTreeKEMGroupInitKeyConvert converts the TreeKEMGroupInitKey in protobuf to Gro
 */
 func PbTreeKEMGroupInitKeyConvert(pbTreeKEMGroupInitKey *pb.TreeKEMGroupInitKey) GroupInitKey {
 	return GroupInitKey{
-		Size:     int(pbTreeKEMGroupInitKey.GetSize()),
-		Frontier: PbTreeKEMNodeMapConvert(pbTreeKEMGroupInitKey.GetFrontier()),
+		Size:        int(pbTreeKEMGroupInitKey.GetSize()),
+		Frontier:    PbTreeKEMNodeMapConvert(pbTreeKEMGroupInitKey.GetFrontier()),
+		GroupID:     pbTreeKEMGroupInitKey.GetGroupID(),
+		Epoch:       pbTreeKEMGroupInitKey.GetEpoch(),
+		CipherSuite: CipherSuiteID(pbTreeKEMGroupInitKey.GetCipherSuite()),
 	}
 }
 
@@ -60,11 +63,23 @@ func PbTreeKEMUserUpdateConvert(pbTreeKEMUserUpdate *pb.TreeKEMUserUpdate) UserU
 	}
 }
 
+/*
+PbTreeKEMUserRemoveConvert converts the TreeKEMUserRemove in protobuf to UserRemove object.
+*/
+func PbTreeKEMUserRemoveConvert(pbTreeKEMUserRemove *pb.TreeKEMUserRemove) UserRemove {
+	return UserRemove{
+		Index:       int(pbTreeKEMUserRemove.GetIndex()),
+		Ciphertexts: PbECKEMCipherTextMapSliceConvert(pbTreeKEMUserRemove.GetCiphertexts()),
+		Copath:      PbTreeKEMNodeMapConvert(pbTreeKEMUserRemove.GetCopath()),
+	}
+}
+
 /*
 PbECKEMCipherTextConvert converts the ECKEMCipherText in protobuf to ECKEMCipherText object.
 */
 func PbECKEMCipherTextConvert(pbECKEMCipherText *pb.ECKEMCipherText) ECKEMCipherText {
 	return ECKEMCipherText{
+		Version:    pbECKEMCipherText.GetVersion(),
 		Public:     pbECKEMCipherText.GetPublic(),
 		IV:         pbECKEMCipherText.GetIV(),
 		CipherText: pbECKEMCipherText.GetCipherText(),
@@ -144,8 +159,11 @@ TreeKEMGroupInitKeyPbConvert converts a GroupInitKey object to TreeKEMGroupInitK
 */
 func TreeKEMGroupInitKeyPbConvert(groupInitKey GroupInitKey) *pb.TreeKEMGroupInitKey {
 	return &pb.TreeKEMGroupInitKey{
-		Size:     uint32(groupInitKey.Size),
-		Frontier: TreeKEMNodeMapPbConvert(groupInitKey.Frontier),
+		Size:        uint32(groupInitKey.Size),
+		Frontier:    TreeKEMNodeMapPbConvert(groupInitKey.Frontier),
+		GroupID:     groupInitKey.GroupID,
+		Epoch:       groupInitKey.Epoch,
+		CipherSuite: uint32(groupInitKey.CipherSuite),
 	}
 }
 
@@ -194,6 +212,7 @@ func ECKEMCipherTextPbConvert(cipherText *ECKEMCipherText) *pb.ECKEMCipherText {
 		return nil
 	}
 	return &pb.ECKEMCipherText{
+		Version:    cipherText.Version,
 		Public:     cipherText.Public,
 		IV:         cipherText.IV,
 		CipherText: cipherText.CipherText,
@@ -237,3 +256,17 @@ func TreeKEMUserUpdatePbConvert(userUpdate *UserUpdate) *pb.TreeKEMUserUpdate {
 		Nodes:       TreeKEMNodeMapPbConvert(userUpdate.Nodes),
 	}
 }
+
+/*
+TreeKEMUserRemovePbConvert converts a UserRemove object to TreeKEMUserRemove in protobuf.
+*/
+func TreeKEMUserRemovePbConvert(userRemove *UserRemove) *pb.TreeKEMUserRemove {
+	if userRemove == nil {
+		return nil
+	}
+	return &pb.TreeKEMUserRemove{
+		Index:       uint32(userRemove.Index),
+		Ciphertexts: ECKEMCipherTextMapSlicePbConvert(userRemove.Ciphertexts),
+		Copath:      TreeKEMNodeMapPbConvert(userRemove.Copath),
+	}
+}
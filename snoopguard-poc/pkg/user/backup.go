@@ -0,0 +1,224 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"go.mau.fi/libsignal/protocol"
+	"google.golang.org/protobuf/proto"
+	"net"
+)
+
+// UserBackupVersion is the version of the backups ExportBackup writes.
+const UserBackupVersion = 1
+
+/*
+ExportBackup returns a backup of the user sealed with the recovery code (see stores.GenerateRecoveryCode), from which
+the user moves to another device with ImportBackup. The backup covers the identity, the groups and the pseudonyms of
+the user, and the message history if includeHistory is set.
+*/
+func (csu *ClientSideUser) ExportBackup(recoveryCode string, includeHistory bool) ([]byte, error) {
+	csu.mutex.Lock()
+	stored, err := csu.export()
+	csu.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// The outbox stays on this device, as its messages were issued on group states RejoinGroups replaces.
+	stored.Outbox = nil
+
+	userBackup := &pb.UserBackup{
+		Version: UserBackupVersion,
+		User:    stored,
+	}
+	if includeHistory {
+		userBackup.History = csu.history.Entries()
+	}
+	backup, err := proto.Marshal(userBackup)
+	if err != nil {
+		return nil, err
+	}
+	return stores.SealBackup(recoveryCode, backup)
+}
+
+/*
+openBackup recreates the user from a backup sealed with the recovery code, without connecting it to the server.
+*/
+func openBackup(sealed []byte, recoveryCode string) (*ClientSideUser, error) {
+	serialized, err := stores.OpenBackup(recoveryCode, sealed)
+	if err != nil {
+		return nil, err
+	}
+	backup := &pb.UserBackup{}
+	if err := proto.Unmarshal(serialized, backup); err != nil {
+		return nil, err
+	}
+	if backup.GetVersion() != UserBackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %v", backup.GetVersion())
+	}
+	csu, err := restoreClientSideUser(backup.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	if err := csu.history.AppendAll(backup.GetHistory()); err != nil {
+		return nil, err
+	}
+	return csu, nil
+}
+
+/*
+ImportBackup restores the user from a backup sealed with the recovery code, connects it to the server at
+chatServiceAddress and rejoins its groups. The close() of the connection is returned as well.
+*/
+func ImportBackup(ctx context.Context, sealed []byte, recoveryCode string, chatServiceAddress string) (*ClientSideUser, func() error, error) {
+	csu, err := openBackup(sealed, recoveryCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeChatServiceClient, err := csu.connect(ctx, chatServiceAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := csu.RejoinGroups(ctx); err != nil {
+		logger.Error("Failed to rejoin groups: ", err)
+	}
+	return csu, closeChatServiceClient, nil
+}
+
+/*
+ImportBackupBufconn restores the user from a backup sealed with the recovery code, connects it to the server through
+the given dialer and rejoins its groups.
+*/
+func ImportBackupBufconn(ctx context.Context, sealed []byte, recoveryCode string, dialer func(context.Context, string) (net.Conn, error)) (*ClientSideUser, error) {
+	csu, err := openBackup(sealed, recoveryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := csu.connectBufconn(ctx, dialer); err != nil {
+		return nil, err
+	}
+	if err := csu.RejoinGroups(ctx); err != nil {
+		logger.Error("Failed to rejoin groups: ", err)
+	}
+	return csu, nil
+}
+
+/*
+RejoinGroups brings the groups of a user restored from a backup up to date, as the group states in the backup may be
+outdated. In Sender Keys groups, the user distributes a fresh sender key and asks the others to send theirs back. In
+MLS groups, the user asks another member to remove and re-add it, so that it joins the current epoch through a Welcome.
+*/
+func (csu *ClientSideUser) RejoinGroups(ctx context.Context) error {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+
+	var errs []error
+	for _, groupID := range csu.Client.GetGroupIDs(pb.GroupType_SERVER_SIDE) {
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info("Rejoining server-side group ", groupID)
+		sessionDriver.RotateSelfSenderKey()
+		if err := csu.distributeSelfSenderKeyToAll(ctx, groupID, true); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rejoin group %v: %w", groupID, err))
+		}
+	}
+
+	for _, groupID := range csu.Client.GetGroupIDs(pb.GroupType_MLS) {
+		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := csu.requestGroupRejoin(ctx, groupID, sessionDriver.GetGroupParticipants()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rejoin group %v: %w", groupID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+requestGroupRejoin asks the first other member of an MLS group to remove and re-add the user.
+*/
+func (csu *ClientSideUser) requestGroupRejoin(ctx context.Context, groupID string, participantIDs []string) error {
+	for _, participantID := range participantIDs {
+		if participantID == csu.userID {
+			continue
+		}
+
+		groupRejoinRequest, err := proto.Marshal(&pb.GroupRejoinRequest{GroupID: groupID})
+		if err != nil {
+			logger.Error("Error marshalling group rejoin request: ", err)
+			return err
+		}
+		logger.Info("Asking ", participantID, " to re-add us to MLS group ", groupID)
+		return csu.SendIndividualMessage(ctx, protocol.NewSignalAddress(participantID, 1), groupRejoinRequest, pb.MessageType_GROUP_REJOIN_REQUEST)
+	}
+	return fmt.Errorf("no other member in group %v", groupID)
+}
+
+/*
+HandleGroupRejoinRequest removes a member restored from a backup from an MLS group, and re-adds it once the removal is
+handled, so that the member joins the current epoch.
+*/
+func (csu *ClientSideUser) HandleGroupRejoinRequest(ctx context.Context, message []byte, senderID string) error {
+	groupRejoinRequest := &pb.GroupRejoinRequest{}
+	err := proto.Unmarshal(message, groupRejoinRequest)
+	if err != nil {
+		logger.Error("Error unmarshalling group rejoin request: ", err)
+		return err
+	}
+
+	groupID := groupRejoinRequest.GetGroupID()
+	sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+	if err != nil {
+		logger.Error("Received group rejoin request for unknown group ", groupID)
+		return err
+	}
+
+	// Only a member may ask to be re-added.
+	if !util.ContainString(senderID, sessionDriver.GetGroupParticipants()) {
+		err = fmt.Errorf("%v is not a member of group %v", senderID, groupID)
+		logger.Error("Rejected group rejoin request: ", err)
+		return err
+	}
+
+	// A request sent again before the member is re-added is answered by the removal already requested.
+	if csu.pendingRejoins[groupID][senderID] {
+		logger.Info("Ignoring repeated group rejoin request from ", senderID, " in group ", groupID)
+		return nil
+	}
+
+	if _, exists := csu.pendingRejoins[groupID]; !exists {
+		csu.pendingRejoins[groupID] = make(map[string]bool)
+	}
+	csu.pendingRejoins[groupID][senderID] = true
+	err = csu.RequestRemoveUserFromGroup(ctx, groupID, senderID)
+	if err != nil {
+		delete(csu.pendingRejoins[groupID], senderID)
+		return err
+	}
+	return nil
+}
+
+/*
+finishGroupRejoin re-adds a member that asked to rejoin an MLS group, once its removal is handled.
+*/
+func (csu *ClientSideUser) finishGroupRejoin(ctx context.Context, groupID string, removedID string) error {
+	if !csu.pendingRejoins[groupID][removedID] {
+		return nil
+	}
+	delete(csu.pendingRejoins[groupID], removedID)
+
+	logger.Info("Re-adding ", removedID, " to MLS group ", groupID)
+	return csu.RequestInviteUserToGroup(ctx, groupID, pb.GroupType_MLS, removedID)
+}
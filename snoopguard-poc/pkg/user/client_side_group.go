@@ -4,6 +4,8 @@ import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
+	"context"
+	"errors"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -50,27 +52,66 @@ func (csu *ClientSideUser) PostCreateClientSideGroup(groupID string, chatbotIsIG
 /*
 SendClientSideGroupMessage sends a client-side group message to the group.
 */
-func (csu *ClientSideUser) SendClientSideGroupMessage(groupID string, message []byte, messageType pb.MessageType, receivingChatbotIDs []string) error {
-	messages, err := csu.GenerateClientSideGroupMessageCipherText(groupID, message, messageType, receivingChatbotIDs)
+func (csu *ClientSideUser) SendClientSideGroupMessage(ctx context.Context, groupID string, message []byte, messageType pb.MessageType, receivingChatbotIDs []string) error {
+	// Every copy carries the same MessageID, by which the receipts of the members refer to the message.
+	messageID := client.NewMessageID()
+	messages, err := csu.GenerateClientSideGroupMessageCipherText(ctx, groupID, message, messageType, messageID, receivingChatbotIDs)
 	if err != nil {
 		logger.Error("Failed to generate client side group message: ", err)
 		return err
 	}
-	return csu.Client.SendClientSideGroupMessage(groupID, messages)
+	err = csu.Client.SendClientSideGroupMessage(ctx, groupID, messages)
+	if err != nil {
+		return err
+	}
+	csu.recordSentMessage(groupID, messageID, message, messageType)
+	return nil
 }
 
 /*
-GenerateClientSideGroupMessageCipherText generates the client-side group message ciphertexts for all group participants.
+GenerateClientSideGroupMessageCipherText generates the client-side group message ciphertexts for all group participants,
+which all carry the given MessageID.
 */
-func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID string, message []byte, messageType pb.MessageType, receivingChatbotIDs []string) (map[string]*pb.MessageWrapper, error) {
+func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(ctx context.Context, groupID string, message []byte, messageType pb.MessageType, messageID string, receivingChatbotIDs []string) (map[string]*pb.MessageWrapper, error) {
 	groupDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
-	// Update TreeKEM
+	// Only chatbots whose scopes allow them to read the message are encrypted for.
+	receivingChatbotIDs = csu.FilterChatbotsByScope(groupID, receivingChatbotIDs, message, messageType)
+
+	// Update TreeKEM. The chatbots authenticate the update with the roots they share with the group before it.
+	updateMACKeys := groupDriver.GetMultiTreeKEM().GetUpdateMACKeys(receivingChatbotIDs)
 	userUpdate, chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err := groupDriver.GenerateMultiTreeKEMKeyUpdate(receivingChatbotIDs)
+	if err != nil {
+		logger.Error("Failed to generate MultiTreeKEM key update: ", err)
+		return nil, err
+	}
+	newTreeKemRootHybridPubKey, err := groupDriver.GetTreeKEMRootHybridPublicKey()
+	if err != nil {
+		logger.Error("Failed to derive hybrid TreeKEM root key: ", err)
+		return nil, err
+	}
+
+	// Everyone in a client-side group knows the sender, so the pack is signed for all of them.
+	treeKEMKeyUpdatePack := &pb.TreeKEMKeyUpdatePack{
+		UserUpdate:               treekem.TreeKEMUserUpdatePbConvert(userUpdate),
+		ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(chatbotUpdateCiphertexts),
+		NewRootPubKey:            newTreeKemRootPubKey,
+		NewRootSignPubKey:        newTreeKemRootSignPubKey,
+		NewRootHybridPubKey:      newTreeKemRootHybridPubKey,
+	}
+	treeKEMKeyUpdatePack.ChatbotEpochs, treeKEMKeyUpdatePack.ChatbotTranscriptHashes = groupDriver.GetMultiTreeKEM().GetEpochs(receivingChatbotIDs)
+	err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack, updateMACKeys)
+	if err != nil {
+		return nil, err
+	}
+	treeKEMKeyUpdatePack, err = csu.signTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create ClientSideGroupMessage
 	packedCSGMsg := &pb.ClientSideGroupMessage{
@@ -88,6 +129,7 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 	packedMessage := &pb.Message{
 		Message:     packedCSGMsgMarshalled,
 		MessageType: pb.MessageType_CLIENT_SIDE_GROUP_MESSAGE,
+		MessageID:   messageID,
 	}
 
 	packedMessageMarshal, err := proto.Marshal(packedMessage)
@@ -103,7 +145,7 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 		sessionDriver, err := csu.Client.GetSessionDriver(protocol.NewSignalAddress(participantID, 1))
 		if err != nil {
 			logger.Info("Sending message to user without session ", participantID)
-			sessionDriver, err = csu.CreateIndividualSession(protocol.NewSignalAddress(participantID, 1))
+			sessionDriver, err = csu.CreateIndividualSession(ctx, protocol.NewSignalAddress(participantID, 1))
 			if err != nil {
 				logger.Error("Failed to create session with ", participantID, ": ", err)
 				return nil, err
@@ -112,18 +154,14 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 
 		encryptedMsg := sessionDriver.EncryptMessage(packedMessageMarshal)
 		messageWrapper := &pb.MessageWrapper{
-			SenderID:         csu.userID,
-			RecipientID:      participantID,
-			EncryptedMessage: encryptedMsg.Serialize(),
-			HasPreKey:        encryptedMsg.Type() == protocol.PREKEY_TYPE,
-			ChatbotIds:       receivingChatbotIDs,
-			IsIGA:            false,
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				UserUpdate:               treekem.TreeKEMUserUpdatePbConvert(userUpdate),
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(chatbotUpdateCiphertexts),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
+			SenderID:             csu.userID,
+			RecipientID:          participantID,
+			EncryptedMessage:     encryptedMsg.Serialize(),
+			HasPreKey:            encryptedMsg.Type() == protocol.PREKEY_TYPE,
+			ChatbotIds:           receivingChatbotIDs,
+			IsIGA:                false,
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+			MessageID:            messageID,
 		}
 
 		messages[participantID] = messageWrapper
@@ -148,7 +186,7 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 		sessionDriver, err := csu.Client.GetSessionDriver(protocol.NewSignalAddress(chatbotID, 1))
 		if err != nil {
 			logger.Info("Sending message to user without session ", chatbotID)
-			sessionDriver, err = csu.CreateIndividualSession(protocol.NewSignalAddress(chatbotID, 1))
+			sessionDriver, err = csu.CreateIndividualSession(ctx, protocol.NewSignalAddress(chatbotID, 1))
 			if err != nil {
 				logger.Error("Failed to create session with ", chatbotID, ": ", err)
 				return nil, err
@@ -161,18 +199,14 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 		//}
 
 		messageWrapper := &pb.MessageWrapper{
-			SenderID:         senderID,
-			RecipientID:      chatbotID,
-			EncryptedMessage: encryptedMsg,
-			HasPreKey:        hasPreKey,
-			ChatbotIds:       receivingChatbotIDs,
-			IsIGA:            false,
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				UserUpdate:               treekem.TreeKEMUserUpdatePbConvert(userUpdate),
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(chatbotUpdateCiphertexts),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
+			SenderID:             senderID,
+			RecipientID:          chatbotID,
+			EncryptedMessage:     encryptedMsg,
+			HasPreKey:            hasPreKey,
+			ChatbotIds:           receivingChatbotIDs,
+			IsIGA:                false,
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+			MessageID:            messageID,
 		}
 
 		messages[chatbotID] = messageWrapper
@@ -181,36 +215,53 @@ func (csu *ClientSideUser) GenerateClientSideGroupMessageCipherText(groupID stri
 	return messages, nil
 }
 
-func (csu *ClientSideUser) HandleClientSideGroupMessage(message []byte, senderID string, chatbotIds []string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, chatbotKeyUpdatePack *pb.MultiTreeKEMExternalKeyUpdatePack) ([]byte, pb.MessageType) {
-	groupId, groupMessage, groupMessageType := csu.Client.ParseClientSideGroupMessage(message, senderID)
+/*
+HandleClientSideGroupMessage handles the incoming client-side group message, and returns the group it belongs to. The
+message is returned along with the error if only its key update failed.
+*/
+func (csu *ClientSideUser) HandleClientSideGroupMessage(message []byte, senderID string, chatbotIds []string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, chatbotKeyUpdatePack *pb.MultiTreeKEMExternalKeyUpdatePack) (string, []byte, pb.MessageType, error) {
+	groupId, groupMessage, groupMessageType, err := csu.Client.ParseClientSideGroupMessage(message, senderID)
+	if err != nil {
+		return groupId, nil, -1, err
+	}
 
 	// Handle treekem key update
 	sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupId)
-	if err != nil {
+	if err != nil && (treeKEMKeyUpdatePack != nil || chatbotKeyUpdatePack != nil) {
 		logger.Error("Not in the group: ", groupId)
+		return groupId, nil, -1, err
 	}
 
+	var keyUpdateErr error
 	if treeKEMKeyUpdatePack != nil {
 		logger.Info("Received TreeKEM update from ", senderID, " for client-side group ", groupId)
 
+		if err := csu.verifyTreeKEMKeyUpdatePack(groupId, senderID, treeKEMKeyUpdatePack); err != nil {
+			return groupId, nil, -1, err
+		}
+
 		userUpdate := treekem.PbTreeKEMUserUpdateConvert(treeKEMKeyUpdatePack.GetUserUpdate())
 		err = sessionDriver.UpdateTreeKEMUserKey(&userUpdate, chatbotIds)
 		if err != nil {
 			logger.Error("UpdateTreeKEMUserKey failed: ", groupId)
+			keyUpdateErr = err
 		}
 	}
 
 	if chatbotKeyUpdatePack != nil {
 		logger.Info("Received MultiTreeKEM update from ", senderID, " for client-side group ", groupId)
 
-		chatbotUpdate := treekem.PbECKEMCipherTextConvert(chatbotKeyUpdatePack.GetChatbotUpdate())
-		newCbPubKey := chatbotKeyUpdatePack.GetNewCbPubKey()
-		newCbSignPubKey := chatbotKeyUpdatePack.GetNewCbSignPubKey()
-		err = sessionDriver.HandleMultiTreeKEMExternalKeyUpdate(senderID, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+		err = csu.handleChatbotKeyUpdatePack(groupId, senderID, chatbotKeyUpdatePack, sessionDriver.HandleMultiTreeKEMExternalKeyUpdate)
 		if err != nil {
 			logger.Error("UpdateMultiTreeKEMExternalKey failed: ", groupId, err)
+			keyUpdateErr = errors.Join(keyUpdateErr, err)
 		}
 	}
 
-	return groupMessage, groupMessageType
+	if !csu.chatbotMayPost(groupId, senderID) {
+		logger.Warning("Dropped message from chatbot ", senderID, " without the scope to post in group ", groupId)
+		return groupId, nil, -1, keyUpdateErr
+	}
+
+	return groupId, groupMessage, groupMessageType, keyUpdateErr
 }
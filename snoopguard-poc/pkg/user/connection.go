@@ -1,9 +1,11 @@
 package user
 
 import (
+	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"go.mau.fi/libsignal/logger"
@@ -11,10 +13,11 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"io"
+	"time"
 )
 
 /*
-SetupChatServiceClient set up the chatServiceClient and chatServiceClientCtx into the ClientSideUser.
+SetupChatServiceClient set up the chatServiceClient into the ClientSideUser.
 Note that this function is not called when the ClientSideUser is created. It needs to be called explicitly.
 The close() will be returned. One should defer the close() after calling this function.
 */
@@ -26,35 +29,37 @@ func (csu *ClientSideUser) SetupChatServiceClient(addr string) func() error {
 	}
 
 	csu.chatServiceClient = pb.NewChatServiceClient(conn)
-	csu.chatServiceClientCtx = context.Background()
+	csu.chatServiceConn = conn
 
 	return conn.Close
 }
 
 /*
 SetupMessageStreamService set up the message stream service and return two channels, one for receiving messages, and one for receiving done signal.
+The stream is closed once the given context is done.
 */
-func (csu *ClientSideUser) SetupMessageStreamService() (chan *pb.MessageWrapper, chan bool) {
+func (csu *ClientSideUser) SetupMessageStreamService(ctx context.Context) (chan *pb.MessageWrapper, chan bool) {
 	// Set up a stream to the server.
-	messageStream, err := csu.chatServiceClient.MessageStream(csu.chatServiceClientCtx, &pb.MessageStreamInit{UserID: csu.userID})
+	messageStream, err := csu.chatServiceClient.MessageStream(ctx, &pb.MessageStreamInit{UserID: csu.userID})
 
+	messageStreamDone := make(chan bool, 1)
+	messageStreamChan := make(chan *pb.MessageWrapper)
 	if err != nil {
 		logger.Error("MessageStream failed: ", err)
+		messageStreamDone <- true
+		return messageStreamChan, messageStreamDone
 	}
-
-	messageStreamDone := make(chan bool)
-	messageStreamChan := make(chan *pb.MessageWrapper)
 	go func() {
+		// Everything received is handed over before the done signal, so that nothing is lost when the stream is closed
+		defer func() { messageStreamDone <- true }()
 		for {
 			resp, err := messageStream.Recv()
 			if err == io.EOF {
 				logger.Info("MessageStream EOF received")
-				messageStreamDone <- true
 				return
 			}
 			if err != nil {
 				//logger.Error("MessageStream cannot receive ", err)
-				messageStreamDone <- true
 				return
 			}
 			logger.Info("MessageStream received: ", resp)
@@ -67,28 +72,30 @@ func (csu *ClientSideUser) SetupMessageStreamService() (chan *pb.MessageWrapper,
 
 /*
 SetupServerEventStreamService set up the server event stream service and return two channels, one for receiving messages, and one for receiving done signal.
+The stream is closed once the given context is done.
 */
-func (csu *ClientSideUser) SetupServerEventStreamService() (chan *pb.ServerEvent, chan bool) {
+func (csu *ClientSideUser) SetupServerEventStreamService(ctx context.Context) (chan *pb.ServerEvent, chan bool) {
 	// Set up a stream to the server.
-	serverEventStream, err := csu.chatServiceClient.ServerEventStream(csu.chatServiceClientCtx, &pb.ServerEventStreamInit{UserID: csu.userID})
+	serverEventStream, err := csu.chatServiceClient.ServerEventStream(ctx, &pb.ServerEventStreamInit{UserID: csu.userID})
 
+	serverEventStreamDone := make(chan bool, 1)
+	serverEventStreamChan := make(chan *pb.ServerEvent)
 	if err != nil {
 		logger.Error("ServerEventStream failed: ", err)
+		serverEventStreamDone <- true
+		return serverEventStreamChan, serverEventStreamDone
 	}
-
-	serverEventStreamDone := make(chan bool)
-	serverEventStreamChan := make(chan *pb.ServerEvent)
 	go func() {
+		// Everything received is handed over before the done signal, so that nothing is lost when the stream is closed
+		defer func() { serverEventStreamDone <- true }()
 		for {
 			resp, err := serverEventStream.Recv()
 			if err == io.EOF {
 				logger.Info("ServerEventStream EOF received")
-				serverEventStreamDone <- true
 				return
 			}
 			if err != nil {
 				//logger.Error("ServerEventStream cannot receive ", err)
-				serverEventStreamDone <- true
 				return
 			}
 			logger.Info("ServerEventStream received: ", resp)
@@ -99,55 +106,225 @@ func (csu *ClientSideUser) SetupServerEventStreamService() (chan *pb.ServerEvent
 	return serverEventStreamChan, serverEventStreamDone
 }
 
+// streamCloseTimeout is how long deactivation waits for the server to end the streams before cancelling them.
+const streamCloseTimeout = 5 * time.Second
+
+// handleTimeout bounds the requests sent to the server while handling a single message or server event.
+const handleTimeout = 30 * time.Second
+
+/*
+closeStreams asks the server to end the streams once it sent what it already took off the queues.
+*/
+func (csu *ClientSideUser) closeStreams(ctx context.Context) bool {
+	resp, err := csu.chatServiceClient.CloseStreams(ctx, &pb.CloseStreamsRequest{UserID: csu.userID})
+	if err != nil {
+		logger.Error("Failed to close streams: ", err)
+		return false
+	}
+	if !resp.GetSuccess() {
+		logger.Error("Failed to close streams: ", resp.GetErrorMessage())
+		return false
+	}
+	return true
+}
+
+/*
+startListening listens to the streams in a goroutine until the user is deactivated.
+*/
+func (csu *ClientSideUser) startListening() {
+	csu.lifecycle, csu.cancelLifecycle = context.WithCancel(context.Background())
+	csu.listenerDone = make(chan struct{})
+	go func() {
+		defer close(csu.listenerDone)
+		csu.ListenToStreams(csu.lifecycle)
+	}()
+}
+
+/*
+goBackground runs f in a goroutine, for work that outlives the handling of the current message or event, e.g. because
+it waits for the next ones to be handled. Its context is bounded by handleTimeout and cancelled once the user is
+deactivated, which waits for it to return.
+*/
+func (csu *ClientSideUser) goBackground(f func(ctx context.Context)) {
+	lifecycle := csu.lifecycle
+	if lifecycle == nil {
+		// The user is not listening to the streams, so it is never deactivated.
+		lifecycle = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(lifecycle, handleTimeout)
+	csu.background.Add(1)
+	go func() {
+		defer csu.background.Done()
+		defer cancel()
+		f(ctx)
+	}()
+}
+
 /*
-ListenToStreams for messages and server events. This should be executed in a goroutine.
+ListenToStreams for messages and server events until the user is deactivated or ctx is done. This should be executed
+in a goroutine.
 */
-func (csu *ClientSideUser) ListenToStreams() {
-	messageStreamChan, messageStreamDone := csu.SetupMessageStreamService()
-	serverEventStreamChan, serverEventStreamDone := csu.SetupServerEventStreamService()
+func (csu *ClientSideUser) ListenToStreams(ctx context.Context) {
+	// The streams are closed on deactivation, so that the server keeps the messages for the next session
+	streamCtx, cancelStreams := context.WithCancel(ctx)
+	defer cancelStreams()
+	messageStreamChan, messageStreamDone := csu.SetupMessageStreamService(streamCtx)
+	serverEventStreamChan, serverEventStreamDone := csu.SetupServerEventStreamService(streamCtx)
+
+	// drainStreams handles what the streams deliver until both end. They are cancelled once closeCtx is done or the
+	// server does not end them in time.
+	drainStreams := func(closeCtx context.Context) {
+		closeTimeout := time.After(streamCloseTimeout)
+		closeDone := closeCtx.Done()
+		for messageStreamDone != nil || serverEventStreamDone != nil {
+			select {
+			case <-closeTimeout:
+				cancelStreams()
+			case <-closeDone:
+				cancelStreams()
+				closeDone = nil
+			case messageData := <-messageStreamChan:
+				csu.handleStreamMessage(ctx, messageData)
+			case eventData := <-serverEventStreamChan:
+				csu.handleStreamEvent(ctx, eventData)
+			case <-messageStreamDone:
+				messageStreamChan, messageStreamDone = nil, nil
+			case <-serverEventStreamDone:
+				serverEventStreamChan, serverEventStreamDone = nil, nil
+			}
+		}
+		if err := csu.Persist(); err != nil {
+			logger.Error("Failed to persist state: ", err)
+		}
+	}
 
 	for {
 		select {
 		case messageData := <-messageStreamChan:
-			output, messageType := csu.ParseMessageWrapper(messageData)
-			if output != nil {
-				csu.messageChan <- OutputMessage{Message: output, MessageType: messageType}
-			}
+			csu.handleStreamMessage(ctx, messageData)
 		case eventData := <-serverEventStreamChan:
-			output, eventType := csu.ParseServerEvent(eventData)
-			if output != nil {
-				csu.messageChan <- OutputMessage{Message: output, EventType: eventType}
-			}
+			csu.handleStreamEvent(ctx, eventData)
 		case <-messageStreamDone:
 			logger.Info("messageStreamDone received")
+			messageStreamChan, messageStreamDone = nil, nil
 		case <-serverEventStreamDone:
 			logger.Info("eventStreamDone received")
-		case <-csu.deactivateChan:
+			serverEventStreamChan, serverEventStreamDone = nil, nil
+		case <-ctx.Done():
+			logger.Info("Stopped listening to streams: ", ctx.Err())
+			drainStreams(ctx)
+			return
+		case deactivateCtx := <-csu.deactivateChan:
 			logger.Info("Deactivate received")
-			csu.messageChan <- OutputMessage{Message: []byte("Deactivate")}
+			// Ask the server to end the streams and handle what it sent up to their end, as the server considers it
+			// delivered.
+			if !csu.closeStreams(deactivateCtx) {
+				cancelStreams()
+			}
+			drainStreams(deactivateCtx)
+			// The user stops listening even if nobody reads the full message channel
+			select {
+			case csu.messageChan <- OutputMessage{Message: []byte("Deactivate")}:
+			default:
+				logger.Warning("Dropped the deactivate message as the message channel is full")
+			}
 			return
 		}
 	}
 }
 
 /*
-ParseMessageWrapper parses the given messageWrapper and handles it.
+handleStreamMessage handles a message received from the message stream and persists the resulting state.
+*/
+func (csu *ClientSideUser) handleStreamMessage(ctx context.Context, messageData *pb.MessageWrapper) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	csu.mutex.Lock()
+	csu.resendOutboxBeforeHandling(ctx, messageData.GetRecipientID())
+	output, err := csu.ParseMessageWrapper(ctx, messageData)
+	if messageData.GetKeyUpdateSeq() > 0 {
+		csu.Client.GetKeyUpdateSequence().Advance(messageData.GetRecipientID(), messageData.GetKeyUpdateSeq())
+	}
+	if err := csu.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	csu.mutex.Unlock()
+	if err != nil {
+		client.ReportError(csu.errorChan, &HandlingError{
+			GroupID:   messageData.GetRecipientID(),
+			SenderID:  messageData.GetSenderID(),
+			MessageID: messageData.GetMessageID(),
+			Err:       err,
+		})
+	}
+	if output != nil {
+		csu.recordReceivedMessage(output)
+		csu.messageChan <- *output
+	}
+}
+
+/*
+handleStreamEvent handles a server event received from the server event stream and persists the resulting state.
+*/
+func (csu *ClientSideUser) handleStreamEvent(ctx context.Context, eventData *pb.ServerEvent) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	csu.mutex.Lock()
+	csu.resendOutboxBeforeHandling(ctx)
+	output, err := csu.ParseServerEvent(ctx, eventData)
+	if err := csu.persistLocked(); err != nil {
+		logger.Error("Failed to persist state: ", err)
+	}
+	csu.mutex.Unlock()
+	if err != nil {
+		handlingError := &HandlingError{Event: eventData, Err: err}
+		if output != nil {
+			handlingError.GroupID, handlingError.SenderID = output.GroupID, output.SenderID
+		}
+		client.ReportError(csu.errorChan, handlingError)
+	}
+	if output != nil {
+		csu.messageChan <- *output
+	}
+}
+
+/*
+ParseMessageWrapper parses the given messageWrapper and handles it. The output is returned along with the error if only
+the key update of the message failed. A message that was already received is rejected with ErrReplayedMessage, and one
+not carrying the MessageID it was encrypted with is rejected with ErrInvalidMessageID. The receipts of the members are applied and not
+output.
 */
-func (csu *ClientSideUser) ParseMessageWrapper(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csu *ClientSideUser) ParseMessageWrapper(ctx context.Context, messageWrapper *pb.MessageWrapper) (*OutputMessage, error) {
+	if err := csu.Client.GetReplayWindow().Check(messageWrapper); err != nil {
+		logger.Warning("Dropped message: ", err)
+		return nil, err
+	}
+	output, err := csu.parseMessageWrapper(ctx, messageWrapper)
+	return csu.handleReceipts(output), err
+}
+
+/*
+parseMessageWrapper parses the given messageWrapper and handles it, without checking whether it was already received.
+*/
+func (csu *ClientSideUser) parseMessageWrapper(ctx context.Context, messageWrapper *pb.MessageWrapper) (*OutputMessage, error) {
 	// If recipient ID is not user ID, it should be the server-side group ID or MLS group ID
 	if messageWrapper.RecipientID != csu.userID {
 		// check if this is a server-side group
 		if _, err := csu.Client.GetServerSideGroupSessionDriver(messageWrapper.RecipientID); err == nil {
-			return csu.HandleServerSideGroupMessage(messageWrapper)
+			message, messageType, err := csu.HandleServerSideGroupMessage(messageWrapper)
+			return client.NewMessageOutput(messageWrapper, messageWrapper.RecipientID, message, messageType), err
 		}
 
 		// check if this is a MLS group
 		if _, err := csu.Client.GetMlsGroupSessionDriver(messageWrapper.RecipientID); err == nil {
-			return csu.HandleMlsGroupMessage(messageWrapper)
+			message, messageType, err := csu.HandleMlsGroupMessage(messageWrapper)
+			return client.NewMessageOutput(messageWrapper, messageWrapper.RecipientID, message, messageType), err
 		}
 
 		logger.Error("Received message with unknown recipient ID: ", messageWrapper.RecipientID)
-		return nil, -1
+		return nil, fmt.Errorf("%w: unknown recipient %v", ErrNotInGroup, messageWrapper.RecipientID)
 	}
 
 	// If is individual message
@@ -155,85 +332,110 @@ func (csu *ClientSideUser) ParseMessageWrapper(messageWrapper *pb.MessageWrapper
 		sessionDriver, err := csu.Client.GetSessionDriver(protocol.NewSignalAddress(messageWrapper.SenderID, 1))
 		if err != nil {
 			logger.Debug("Received message from user without session ", messageWrapper.SenderID)
-			prekeyBundle, err := csu.Client.GetOthersPreKeyBundle(messageWrapper.SenderID)
+			prekeyBundle, err := csu.Client.GetOthersPreKeyBundle(ctx, messageWrapper.SenderID)
 			if err != nil {
-				panic("")
+				return nil, fmt.Errorf("%w with %v: %w", ErrNoSession, messageWrapper.SenderID, err)
 			}
 			sessionDriver, err = csu.Client.CreateSessionAndDriver(protocol.NewSignalAddress(messageWrapper.SenderID, 1), prekeyBundle)
 			if err != nil {
-				panic("")
+				return nil, fmt.Errorf("%w with %v: %w", ErrNoSession, messageWrapper.SenderID, err)
 			}
 		}
 
-		message, messageType, err := sessionDriver.DecryptMessage(messageWrapper.SenderID, messageWrapper.RecipientID, messageWrapper.EncryptedMessage, messageWrapper.HasPreKey)
+		message, messageType, err := sessionDriver.DecryptMessage(messageWrapper.SenderID, messageWrapper.RecipientID, messageWrapper.GetMessageID(), messageWrapper.EncryptedMessage, messageWrapper.HasPreKey)
 		if err != nil {
 			logger.Error("Error decrypting Message: ", err)
-			return nil, -1
+			return nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 		}
 
 		switch messageType {
 		case pb.MessageType_TEXT_MESSAGE:
+			if !csu.chatbotMayDirectMessage(messageWrapper.SenderID) {
+				logger.Warning("Dropped direct message from chatbot ", messageWrapper.SenderID, " without the scope to direct message members")
+				return nil, nil
+			}
 			logger.Info(fmt.Sprintf("Received text message from %v: %v", messageWrapper.SenderID, string(message)))
-			return message, messageType
+			return client.NewMessageOutput(messageWrapper, "", message, messageType), nil
 
 		case pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE:
 			groupID, bounceBack, err := csu.Client.ParseSenderKeyDistributionMessage(message, messageWrapper.SenderID)
 			if err != nil {
 				logger.Error("Failed to parse sender key message: ", err)
+				return nil, fmt.Errorf("%w: %w", ErrUndecryptable, err)
 			}
 
 			if bounceBack {
-				err = csu.DistributeSelfSenderKeyToUserID(messageWrapper.SenderID, groupID, false)
+				err = csu.DistributeSelfSenderKeyToUserID(ctx, messageWrapper.SenderID, groupID, false)
 				if err != nil {
 					logger.Error("Failed to distribute self sender key to user: ", err)
 				}
 			}
-			return message, messageType
+			return client.NewMessageOutput(messageWrapper, "", message, messageType), err
 
 		case pb.MessageType_CLIENT_SIDE_GROUP_MESSAGE:
-			return csu.HandleClientSideGroupMessage(
+			groupID, groupMessage, groupMessageType, err := csu.HandleClientSideGroupMessage(
 				message,
 				messageWrapper.SenderID,
 				messageWrapper.GetChatbotIds(),
 				messageWrapper.GetTreeKEMKeyUpdatePack(),
 				messageWrapper.GetChatbotKeyUpdatePack())
+			return client.NewMessageOutput(messageWrapper, groupID, groupMessage, groupMessageType), err
 		case pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE:
 			logger.Info("Received pseudonym registration message without IGA from ", messageWrapper.SenderID, "as a group member.")
-			return message, messageType
+			return client.NewMessageOutput(messageWrapper, "", message, messageType), nil
+		case pb.MessageType_ROOT_RECOVERY_REQUEST:
+			err = csu.HandleRootRecoveryRequest(ctx, message, messageWrapper.SenderID)
+			if err != nil {
+				logger.Error("Failed to answer root recovery request from ", messageWrapper.SenderID, ": ", err)
+			}
+			return nil, err
+		case pb.MessageType_GROUP_REJOIN_REQUEST:
+			err = csu.HandleGroupRejoinRequest(ctx, message, messageWrapper.SenderID)
+			if err != nil {
+				logger.Error("Failed to answer group rejoin request from ", messageWrapper.SenderID, ": ", err)
+			}
+			return nil, err
 		}
 	}
-	return nil, -1
+	return nil, nil
 }
 
 /*
 ParseServerEventRaw parses the given serverEventRaw and handles it.
 */
-func (csu *ClientSideUser) ParseServerEventRaw(serverEventRaw []byte) {
+func (csu *ClientSideUser) ParseServerEventRaw(ctx context.Context, serverEventRaw []byte) error {
 	serverEvent := &pb.ServerEvent{}
 	err := proto.Unmarshal(serverEventRaw, serverEvent)
 	if err != nil {
 		logger.Error("ParseMessage failed: ", err)
-		return
+		return err
 	}
-	csu.ParseServerEvent(serverEvent)
+	_, err = csu.ParseServerEvent(ctx, serverEvent)
+	return err
 }
 
 /*
-ParseServerEvent parse the incoming server events.
+ParseServerEvent parse the incoming server events. The output is returned along with the error if the user failed to
+apply the event.
 */
-func (csu *ClientSideUser) ParseServerEvent(serverEvent *pb.ServerEvent) ([]byte, pb.ServerEventType) {
+func (csu *ClientSideUser) ParseServerEvent(ctx context.Context, serverEvent *pb.ServerEvent) (*OutputMessage, error) {
 	logger.Info("Received server event: ", serverEvent.GetEventType())
 
 	switch serverEvent.GetEventType() {
 	case pb.ServerEventType_GROUP_INVITATION:
-		csu.JoinGroup(
+		// MLS groups carry no group init key, so the suite comes with the invitation.
+		treekemGroupInitKey := treekem.PbTreeKEMGroupInitKeyConvert(serverEvent.GetGroupInvitation().GetTreeKEMGroupInitKey())
+		if treekemGroupInitKey.CipherSuite == 0 {
+			treekemGroupInitKey.CipherSuite = treekem.CipherSuiteID(serverEvent.GetGroupInvitation().GetCipherSuite())
+		}
+		err := csu.JoinGroup(
 			serverEvent.GetGroupInvitation().GetGroupID(),
 			serverEvent.GetGroupInvitation().GetGroupType(),
 			serverEvent.GetGroupInvitation().GetParticipantIDs(),
 			serverEvent.GetGroupInvitation().GetChatbotIDs(),
 			serverEvent.GetGroupInvitation().GetChatbotIsIGA(),
 			serverEvent.GetGroupInvitation().GetChatbotIsPseudo(),
-			treekem.PbTreeKEMGroupInitKeyConvert(serverEvent.GetGroupInvitation().GetTreeKEMGroupInitKey()),
+			treekemGroupInitKey,
 			serverEvent.GetGroupInvitation().GetTreeKEMInitLeaf(),
 			serverEvent.GetGroupInvitation().GetChatbotPubKeys(),
 			serverEvent.GetGroupInvitation().GetChatbotSignPubKeys(),
@@ -241,9 +443,46 @@ func (csu *ClientSideUser) ParseServerEvent(serverEvent *pb.ServerEvent) ([]byte
 			serverEvent.GetGroupInvitation().GetMlsWelcomeMessage(),
 			serverEvent.GetGroupInvitation().GetMlsKeyPackageID(),
 		)
-		return []byte(serverEvent.GetGroupInvitation().GetGroupID()), pb.ServerEventType_GROUP_INVITATION
+		// The state the user joins with covers the key updates of the group so far, even those an old device of the
+		// user handled before a backup was restored.
+		csu.Client.GetKeyUpdateSequence().Advance(serverEvent.GetGroupInvitation().GetGroupID(), serverEvent.GetGroupInvitation().GetKeyUpdateSeq())
+		// The chatbots may have been invited by any member.
+		for chatbotID, signedRouting := range serverEvent.GetGroupInvitation().GetChatbotRoutings() {
+			routingErr := csu.SetSignedChatbotRouting(ctx, serverEvent.GetGroupInvitation().GetGroupID(), chatbotID, signedRouting, serverEvent.GetGroupInvitation().GetParticipantIDs())
+			if routingErr != nil {
+				logger.Error("Rejected routing of chatbot ", chatbotID, ": ", routingErr)
+				err = errors.Join(err, routingErr)
+			}
+		}
+		// A chatbot whose scopes are not signed by its inviter is granted none.
+		for chatbotID := range serverEvent.GetGroupInvitation().GetChatbotScopes() {
+			scopesErr := csu.SetSignedChatbotScopes(ctx, serverEvent.GetGroupInvitation().GetGroupID(), chatbotID, serverEvent.GetGroupInvitation().GetSignedChatbotScopes()[chatbotID])
+			if scopesErr != nil {
+				logger.Error("Rejected scopes of chatbot ", chatbotID, ": ", scopesErr)
+				csu.SetChatbotScopes(serverEvent.GetGroupInvitation().GetGroupID(), chatbotID, &pb.ChatbotScopes{})
+				err = errors.Join(err, scopesErr)
+			}
+		}
+		for chatbotID, hybridKEM := range serverEvent.GetGroupInvitation().GetChatbotHybridKEM() {
+			if !hybridKEM {
+				continue
+			}
+			err := csu.SetChatbotHybridKEM(serverEvent.GetGroupInvitation().GetGroupID(), serverEvent.GetGroupInvitation().GetGroupType(), chatbotID, hybridKEM)
+			if err != nil {
+				logger.Error("Failed to set hybrid KEM of chatbot: ", err)
+			}
+		}
+		csu.setTreeKEMIndices(
+			serverEvent.GetGroupInvitation().GetGroupID(),
+			serverEvent.GetGroupInvitation().GetGroupType(),
+			serverEvent.GetGroupInvitation().GetTreeKEMIndices())
+		csu.setTreeKEMPublicTree(
+			serverEvent.GetGroupInvitation().GetGroupID(),
+			serverEvent.GetGroupInvitation().GetGroupType(),
+			serverEvent.GetGroupInvitation().GetTreeKEMPublicTree())
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_ADDITION:
-		csu.AddUserToGroup(
+		err := csu.AddUserToGroup(
 			serverEvent.GetGroupAddition().GetGroupID(),
 			serverEvent.GetGroupAddition().GetGroupType(),
 			serverEvent.GetGroupAddition().GetSenderID(),
@@ -253,19 +492,54 @@ func (csu *ClientSideUser) ParseServerEvent(serverEvent *pb.ServerEvent) ([]byte
 			serverEvent.GetGroupAddition().GetMlsUserAdd(),
 			serverEvent.GetGroupAddition().GetMlsAddCommit(),
 		)
-		return []byte(serverEvent.GetGroupAddition().GetGroupID()), pb.ServerEventType_GROUP_ADDITION
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_REMOVAL:
-		csu.RemoveUserFromGroup(
+		err := csu.RemoveUserFromGroup(
 			serverEvent.GetGroupRemoval().GetGroupID(),
 			serverEvent.GetGroupRemoval().GetGroupType(),
+			serverEvent.GetGroupRemoval().GetSenderID(),
 			serverEvent.GetGroupRemoval().GetRemovedID(),
 			serverEvent.GetGroupRemoval().GetParticipantIDs(),
 			serverEvent.GetGroupRemoval().GetMlsRemove(),
 			serverEvent.GetGroupRemoval().GetMlsRemoveCommit(),
+			treekem.PbTreeKEMUserRemoveConvert(serverEvent.GetGroupRemoval().GetTreeKEMUserRemove()),
 		)
-		return []byte(serverEvent.GetGroupRemoval().GetGroupID()), pb.ServerEventType_GROUP_REMOVAL
+		// Every remaining member rotates its sender key, as the removed member knows the current ones.
+		if serverEvent.GetGroupRemoval().GetRemovedID() != csu.userID && serverEvent.GetGroupRemoval().GetGroupType() == pb.GroupType_SERVER_SIDE {
+			err := csu.RotateSelfSenderKey(ctx, serverEvent.GetGroupRemoval().GetGroupID())
+			if err != nil {
+				logger.Error("Failed to rotate sender key after removal: ", err)
+			}
+		}
+		// The user who removed the member rekeys the chatbots, as the removed member knows their current roots.
+		if serverEvent.GetGroupRemoval().GetSenderID() == csu.userID && serverEvent.GetGroupRemoval().GetGroupType() == pb.GroupType_SERVER_SIDE {
+			// The rekey is sent once the event is handled, as it waits for the incoming messages to be handled.
+			groupID, groupType := serverEvent.GetGroupRemoval().GetGroupID(), serverEvent.GetGroupRemoval().GetGroupType()
+			csu.goBackground(func(ctx context.Context) {
+				err := csu.RekeyGroupChatbots(ctx, groupID, groupType)
+				if err != nil {
+					logger.Error("Failed to rekey chatbots after removal: ", err)
+				}
+			})
+		}
+		// A member that asked to rejoin is re-added once its removal is applied.
+		if serverEvent.GetGroupRemoval().GetSenderID() == csu.userID && serverEvent.GetGroupRemoval().GetGroupType() == pb.GroupType_MLS {
+			err = errors.Join(err, csu.finishGroupRejoin(ctx, serverEvent.GetGroupRemoval().GetGroupID(), serverEvent.GetGroupRemoval().GetRemovedID()))
+		}
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_CHATBOT_ADDITION:
-		csu.AddChatbotToGroup(
+		// The chatbot's external node key is derived for the KEM it uses, so the KEM is set before it is added.
+		if serverEvent.GetGroupChatbotAddition().GetHybridKEM() {
+			err := csu.SetChatbotHybridKEM(
+				serverEvent.GetGroupChatbotAddition().GetGroupID(),
+				serverEvent.GetGroupChatbotAddition().GetGroupType(),
+				serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(),
+				true)
+			if err != nil {
+				logger.Error("Failed to set hybrid KEM of chatbot: ", err)
+			}
+		}
+		err := csu.AddChatbotToGroup(
 			serverEvent.GetGroupChatbotAddition().GetGroupID(),
 			serverEvent.GetGroupChatbotAddition().GetGroupType(),
 			serverEvent.GetGroupChatbotAddition().GetSenderID(),
@@ -276,14 +550,63 @@ func (csu *ClientSideUser) ParseServerEvent(serverEvent *pb.ServerEvent) ([]byte
 			treekem.PbECKEMCipherTextConvert(serverEvent.GetGroupChatbotAddition().GetChatbotCipherText()),
 			serverEvent.GetGroupChatbotAddition().GetMlsUserAdd(),
 			serverEvent.GetGroupChatbotAddition().GetMlsAddCommit())
-		return []byte(serverEvent.GetGroupChatbotAddition().GetGroupID()), pb.ServerEventType_GROUP_CHATBOT_ADDITION
+		// Only the member who invited the chatbot signs its routing.
+		routingErr := csu.SetSignedChatbotRouting(
+			ctx,
+			serverEvent.GetGroupChatbotAddition().GetGroupID(),
+			serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(),
+			serverEvent.GetGroupChatbotAddition().GetChatbotRouting(),
+			[]string{serverEvent.GetGroupChatbotAddition().GetSenderID()})
+		if routingErr != nil {
+			logger.Error("Rejected routing of chatbot ", serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(), ": ", routingErr)
+			err = errors.Join(err, routingErr)
+		}
+		scopesErr := csu.SetSignedChatbotScopes(
+			ctx,
+			serverEvent.GetGroupChatbotAddition().GetGroupID(),
+			serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(),
+			serverEvent.GetGroupChatbotAddition().GetSignedScopes())
+		if scopesErr != nil {
+			logger.Error("Rejected scopes of chatbot ", serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(), ": ", scopesErr)
+			csu.SetChatbotScopes(serverEvent.GetGroupChatbotAddition().GetGroupID(), serverEvent.GetGroupChatbotAddition().GetAddedChatbotID(), &pb.ChatbotScopes{})
+			err = errors.Join(err, scopesErr)
+		}
+		return client.NewEventOutput(serverEvent), err
 	case pb.ServerEventType_GROUP_CHATBOT_REMOVAL:
-		csu.RemoveChatbotFromGroup(
+		err := csu.RemoveChatbotFromGroup(
 			serverEvent.GetGroupChatbotRemoval().GetGroupID(),
 			serverEvent.GetGroupChatbotRemoval().GetGroupType(),
 			serverEvent.GetGroupChatbotRemoval().GetRemovedChatbotID(),
 			serverEvent.GetGroupChatbotRemoval().GetChatbotIDs())
-		return []byte(serverEvent.GetGroupChatbotRemoval().GetGroupID()), pb.ServerEventType_GROUP_CHATBOT_REMOVAL
+		csu.RemoveChatbotRouting(
+			serverEvent.GetGroupChatbotRemoval().GetGroupID(),
+			serverEvent.GetGroupChatbotRemoval().GetRemovedChatbotID())
+		csu.RemoveChatbotScopes(
+			serverEvent.GetGroupChatbotRemoval().GetGroupID(),
+			serverEvent.GetGroupChatbotRemoval().GetRemovedChatbotID())
+		// The user who removed the chatbot rekeys the remaining chatbots.
+		if serverEvent.GetGroupChatbotRemoval().GetSenderID() == csu.userID {
+			// The rekey is sent once the event is handled, as it waits for the incoming messages to be handled.
+			groupID, groupType := serverEvent.GetGroupChatbotRemoval().GetGroupID(), serverEvent.GetGroupChatbotRemoval().GetGroupType()
+			csu.goBackground(func(ctx context.Context) {
+				err := csu.RekeyGroupChatbots(ctx, groupID, groupType)
+				if err != nil {
+					logger.Error("Failed to rekey chatbots after removal: ", err)
+				}
+			})
+		}
+		return client.NewEventOutput(serverEvent), err
+	case pb.ServerEventType_GROUP_CHATBOT_SCOPE_UPDATE:
+		// Scopes not signed by the inviter, or older than the current ones, are ignored.
+		err := csu.SetSignedChatbotScopes(
+			ctx,
+			serverEvent.GetGroupChatbotScopeUpdate().GetGroupID(),
+			serverEvent.GetGroupChatbotScopeUpdate().GetChatbotID(),
+			serverEvent.GetGroupChatbotScopeUpdate().GetSignedScopes())
+		if err != nil {
+			logger.Error("Rejected scopes of chatbot ", serverEvent.GetGroupChatbotScopeUpdate().GetChatbotID(), ": ", err)
+		}
+		return client.NewEventOutput(serverEvent), err
 	}
-	return nil, -1
+	return nil, nil
 }
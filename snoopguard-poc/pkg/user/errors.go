@@ -0,0 +1,30 @@
+package user
+
+import (
+	"chatbot-poc-go/pkg/client"
+	"errors"
+)
+
+// The errors the user returns and reports on the error channel. See the client package for their meaning.
+var (
+	ErrNotInGroup       = client.ErrNotInGroup
+	ErrNoSession        = client.ErrNoSession
+	ErrDesync           = client.ErrDesync
+	ErrBadSignature     = client.ErrBadSignature
+	ErrUnknownPseudonym = client.ErrUnknownPseudonym
+	ErrUndecryptable    = client.ErrUndecryptable
+	ErrReplayedMessage  = client.ErrReplayedMessage
+	ErrInvalidMessageID = client.ErrInvalidMessageID
+	ErrRequestRejected  = client.ErrRequestRejected
+)
+
+var (
+	// ErrMessageQueued is returned when it is not known whether the server got a group message. The message is kept in
+	// the outbox, and sent again before the next message to the group or with FlushOutbox.
+	ErrMessageQueued = errors.New("message queued in the outbox")
+	// ErrOutboxPending is returned when a group message is not sent, as an earlier one is still in the outbox.
+	ErrOutboxPending = errors.New("earlier message still in the outbox")
+)
+
+// HandlingError reports an incoming message or server event the user failed to handle.
+type HandlingError = client.HandlingError
@@ -3,7 +3,9 @@ package user
 import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
 	"context"
+	"errors"
 	"fmt"
 	syntax "github.com/cisco/go-tls-syntax"
 	"github.com/s3131212/go-mls"
@@ -14,11 +16,25 @@ import (
 /*
 CreateGroup initializes a new group, either server side or client side, registers the group to the server, and return the group id.
 */
-func (csu *ClientSideUser) CreateGroup(groupType pb.GroupType) (string, error) {
+func (csu *ClientSideUser) CreateGroup(ctx context.Context, groupType pb.GroupType) (string, error) {
+	return csu.CreateGroupWithCipherSuite(ctx, groupType, treekem.DefaultCipherSuite)
+}
+
+/*
+CreateGroupWithCipherSuite is CreateGroup with the given cipher suite for the CMRT of the group. The suite is registered
+to the server along with the group, and every member and chatbot that joins later runs with it.
+*/
+func (csu *ClientSideUser) CreateGroupWithCipherSuite(ctx context.Context, groupType pb.GroupType, cipherSuite treekem.CipherSuiteID) (string, error) {
+	if _, err := treekem.CipherSuiteByID(cipherSuite); err != nil {
+		logger.Error("Failed to create group: ", err)
+		return "", err
+	}
+
 	// Send group creation request to server
-	res, err := csu.chatServiceClient.CreateGroup(context.Background(), &pb.CreateGroupRequest{
+	res, err := csu.chatServiceClient.CreateGroup(ctx, &pb.CreateGroupRequest{
 		InitiatorID: csu.userID,
 		GroupType:   groupType,
+		CipherSuite: uint32(cipherSuite),
 	})
 	if err != nil {
 		logger.Error("Failed to create group: ", err)
@@ -27,7 +43,7 @@ func (csu *ClientSideUser) CreateGroup(groupType pb.GroupType) (string, error) {
 
 	if !res.GetSuccess() || res.GetErrorMessage() != "" {
 		logger.Error("Failed to create group: ", res.GetErrorMessage())
-		return "", fmt.Errorf(res.GetErrorMessage())
+		return "", errors.New(res.GetErrorMessage())
 
 	}
 
@@ -45,22 +61,25 @@ func (csu *ClientSideUser) CreateGroup(groupType pb.GroupType) (string, error) {
 	keyPackageId := rand.Uint32()
 	csu.Client.GenerateMLSKeyPackage(keyPackageId + 10000)
 
-	csu.JoinGroup(res.GetGroupID(), groupType, []string{csu.userID}, []string{}, nil, nil, treekem.GroupInitKey{}, initLeaf, nil, nil, nil, nil, keyPackageId+10000)
+	err = csu.JoinGroup(res.GetGroupID(), groupType, []string{csu.userID}, []string{}, nil, nil, treekem.GroupInitKey{CipherSuite: cipherSuite}, initLeaf, nil, nil, nil, nil, keyPackageId+10000)
+	if err != nil {
+		return "", err
+	}
 
 	return res.GetGroupID(), nil
 }
 
 /*
-JoinGroup joins a group, either server side or client side, and return the group id.
+JoinGroup joins a group, either server side or client side.
 */
-func (csu *ClientSideUser) JoinGroup(groupID string, groupType pb.GroupType, participantIDs []string, chatbotIDs []string, chatbotIsIGA map[string]bool, chatbotIsPseudo map[string]bool, treekemGroupInitKey treekem.GroupInitKey, treekemInitLeaf []byte, chatbotPubKeys map[string][]byte, chatbotSignPubKeys map[string][]byte, lastTreeKemRootCiphertexts map[string]treekem.ECKEMCipherText, welcomeMessageSerialized []byte, keyPackageId uint32) {
+func (csu *ClientSideUser) JoinGroup(groupID string, groupType pb.GroupType, participantIDs []string, chatbotIDs []string, chatbotIsIGA map[string]bool, chatbotIsPseudo map[string]bool, treekemGroupInitKey treekem.GroupInitKey, treekemInitLeaf []byte, chatbotPubKeys map[string][]byte, chatbotSignPubKeys map[string][]byte, lastTreeKemRootCiphertexts map[string]treekem.ECKEMCipherText, welcomeMessageSerialized []byte, keyPackageId uint32) error {
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
 		// Check if already in the group
 		_, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		csu.Client.JoinGroup(groupID, groupType, participantIDs, chatbotIDs)
@@ -68,14 +87,14 @@ func (csu *ClientSideUser) JoinGroup(groupID string, groupType pb.GroupType, par
 		_, err = csu.PostCreateServerSideGroup(groupID, chatbotIsIGA, chatbotIsPseudo, treekemGroupInitKey, treekemInitLeaf, chatbotPubKeys, chatbotSignPubKeys, lastTreeKemRootCiphertexts)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	case pb.GroupType_CLIENT_SIDE:
 		// Check if already in the group
 		_, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		csu.Client.JoinGroup(groupID, groupType, participantIDs, chatbotIDs)
@@ -83,14 +102,14 @@ func (csu *ClientSideUser) JoinGroup(groupID string, groupType pb.GroupType, par
 		_, err = csu.PostCreateClientSideGroup(groupID, chatbotIsIGA, treekemGroupInitKey, treekemInitLeaf, chatbotPubKeys, chatbotSignPubKeys, lastTreeKemRootCiphertexts)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	case pb.GroupType_MLS:
 		// Check if already in the group
 		_, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err == nil {
 			logger.Info("Already in the group: ", groupID)
-			return
+			return nil
 		}
 
 		// Deserialize Welcome
@@ -101,19 +120,19 @@ func (csu *ClientSideUser) JoinGroup(groupID string, groupType pb.GroupType, par
 
 		csu.Client.JoinGroup(groupID, groupType, participantIDs, chatbotIDs)
 
-		_, err = csu.PostCreateMlsGroup(groupID, chatbotIsIGA, chatbotIsPseudo, welcome, keyPackageId)
+		_, err = csu.PostCreateMlsGroup(groupID, chatbotIsIGA, chatbotIsPseudo, welcome, keyPackageId, treekemGroupInitKey.CipherSuite)
 		if err != nil {
 			logger.Error("Failed to listen to group: ", err)
-			return
+			return err
 		}
 	}
-
+	return nil
 }
 
 /*
 AddUserToGroup adds a user to the group.
 */
-func (csu *ClientSideUser) AddUserToGroup(groupID string, groupType pb.GroupType, senderID string, addedID string, participantIDs []string, treekemUserAdd treekem.UserAdd, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) {
+func (csu *ClientSideUser) AddUserToGroup(groupID string, groupType pb.GroupType, senderID string, addedID string, participantIDs []string, treekemUserAdd treekem.UserAdd, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) error {
 	logger.Info("Adding user: ", addedID, " to group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -121,19 +140,21 @@ func (csu *ClientSideUser) AddUserToGroup(groupID string, groupType pb.GroupType
 		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
 
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
 		sessionDriver.AddUserToTreeKEM(&treekemUserAdd)
+		// The added user takes the last leaf of the TreeKEM.
+		sessionDriver.SetTreeKEMIndex(addedID, treekemUserAdd.Size-1)
 	case pb.GroupType_CLIENT_SIDE:
 		// Check if already in the group
 		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -146,7 +167,7 @@ func (csu *ClientSideUser) AddUserToGroup(groupID string, groupType pb.GroupType
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -165,17 +186,18 @@ func (csu *ClientSideUser) AddUserToGroup(groupID string, groupType pb.GroupType
 			err = sessionDriver.AddUser(&mlsUserAdd, &mlsCommit)
 			if err != nil {
 				logger.Error("Failed to add user to MLS group: ", err)
-				return
+				return err
 			}
 		}
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
 	}
+	return nil
 }
 
 /*
 RemoveUserFromGroup removes self from the group if id matches, otherwise user from the group's participant list.
 */
-func (csu *ClientSideUser) RemoveUserFromGroup(groupID string, groupType pb.GroupType, removedID string, participantIDs []string, mlsRemoveSerialized []byte, mlsRemoveCommitSerialized []byte) {
+func (csu *ClientSideUser) RemoveUserFromGroup(groupID string, groupType pb.GroupType, senderID string, removedID string, participantIDs []string, mlsRemoveSerialized []byte, mlsRemoveCommitSerialized []byte, treekemUserRemove treekem.UserRemove) error {
 	logger.Info("Removing user: ", removedID, " from group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -183,29 +205,40 @@ func (csu *ClientSideUser) RemoveUserFromGroup(groupID string, groupType pb.Grou
 		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 		if removedID == csu.userID {
 			logger.Info("Leaving group: ", groupID)
 			csu.Client.LeaveGroup(groupID, groupType)
-			return
+			return nil
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
 
 		sessionDriver.RemoveUserSession(removedID)
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
+
+		// Blank the leaf of the removed user in the TreeKEM
+		if len(treekemUserRemove.Ciphertexts) > 0 {
+			err = sessionDriver.HandleTreeKEMUserRemove(&treekemUserRemove)
+			if err != nil {
+				logger.Error("Failed to remove user from TreeKEM: ", err)
+				return err
+			}
+		} else {
+			logger.Warning("No TreeKEM remove for user ", removedID, " in group ", groupID)
+		}
 	case pb.GroupType_CLIENT_SIDE:
 		// Check if already in the group
 		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 		if removedID == csu.userID {
 			logger.Info("Leaving group: ", groupID)
 			csu.Client.LeaveGroup(groupID, groupType)
-			return
+			return nil
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
@@ -218,7 +251,7 @@ func (csu *ClientSideUser) RemoveUserFromGroup(groupID string, groupType pb.Grou
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Deserialize MLSRemove and MLSRemoveCommit
@@ -234,22 +267,26 @@ func (csu *ClientSideUser) RemoveUserFromGroup(groupID string, groupType pb.Grou
 		if removedID == csu.userID {
 			logger.Info("Leaving group: ", groupID)
 			csu.Client.LeaveGroup(groupID, groupType)
-			return
+			return nil
 		}
 
-		err = sessionDriver.RemoveUser(&mlsRemove, &mlsRemoveCommit)
-		if err != nil {
-			logger.Error("Failed to remove user from MLS group: ", err)
-			return
+		// The remover already applied its commit when creating it.
+		if senderID != csu.userID {
+			err = sessionDriver.RemoveUser(&mlsRemove, &mlsRemoveCommit)
+			if err != nil {
+				logger.Error("Failed to remove user from MLS group: ", err)
+				return err
+			}
 		}
 		sessionDriver.UpdateGroupParticipantIDs(participantIDs)
 	}
+	return nil
 }
 
 /*
 AddChatbotToGroup adds a user to the group.
 */
-func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupType, senderID string, addedChatbotID string, chatbotIDs []string, isIGA bool, isPseudo bool, chatbotCipherText treekem.ECKEMCipherText, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) {
+func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupType, senderID string, addedChatbotID string, chatbotIDs []string, isIGA bool, isPseudo bool, chatbotCipherText treekem.ECKEMCipherText, mlsUserAddSerialized []byte, mlsCommitSerialized []byte) error {
 	logger.Info("Adding chatbot: ", addedChatbotID, " to group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -257,7 +294,7 @@ func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -278,7 +315,7 @@ func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -297,7 +334,7 @@ func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupT
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs - addedID
@@ -325,7 +362,7 @@ func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupT
 				err = sessionDriver.AddUser(&mlsUserAdd, &mlsCommit)
 				if err != nil {
 					logger.Error("Failed to add user to MLS group: ", err)
-					return
+					return err
 				}
 			}
 		}
@@ -334,12 +371,13 @@ func (csu *ClientSideUser) AddChatbotToGroup(groupID string, groupType pb.GroupT
 		sessionDriver.SetChatbotIsIGA(addedChatbotID, isIGA)
 		sessionDriver.SetChatbotIsPseudo(addedChatbotID, isPseudo)
 	}
+	return nil
 }
 
 /*
 RemoveChatbotFromGroup removes chatbot from the group's chatbot list.
 */
-func (csu *ClientSideUser) RemoveChatbotFromGroup(groupID string, groupType pb.GroupType, removedChatbotID string, chatbotIDs []string) {
+func (csu *ClientSideUser) RemoveChatbotFromGroup(groupID string, groupType pb.GroupType, removedChatbotID string, chatbotIDs []string) error {
 	logger.Info("Removing chatbot: ", removedChatbotID, " from group: ", groupID)
 	switch groupType {
 	case pb.GroupType_SERVER_SIDE:
@@ -347,43 +385,118 @@ func (csu *ClientSideUser) RemoveChatbotFromGroup(groupID string, groupType pb.G
 		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
 
 		sessionDriver.RemoveUserSession(removedChatbotID)
 		sessionDriver.UpdateGroupChatbotIDs(chatbotIDs)
+
+		// Remove from MultiTreeKEM's external node, so that no further update is encrypted to the chatbot
+		err = sessionDriver.RemoveExternalNodeFromMultiTreeKEM(removedChatbotID)
+		if err != nil {
+			logger.Info("Chatbot ", removedChatbotID, " has no external node in MultiTreeKEM: ", err)
+		}
 	case pb.GroupType_CLIENT_SIDE:
 		// Check if already in the group
 		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
 
 		sessionDriver.RemoveUserSession(removedChatbotID)
 		sessionDriver.UpdateGroupChatbotIDs(chatbotIDs)
+
+		// Remove from MultiTreeKEM's external node, so that no further update is encrypted to the chatbot
+		err = sessionDriver.RemoveExternalNodeFromMultiTreeKEM(removedChatbotID)
+		if err != nil {
+			logger.Info("Chatbot ", removedChatbotID, " has no external node in MultiTreeKEM: ", err)
+		}
 	case pb.GroupType_MLS:
 		// Check if already in the group
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Info("Not in the group: ", groupID)
-			return
+			return err
 		}
 
 		// Todo: Assert that current sessionDriver.participantIDs = participantIDs + removedID
 
 		sessionDriver.UpdateGroupChatbotIDs(chatbotIDs)
+
+		// Remove from MlsMultiTree's external node, so that no further update is encrypted to the chatbot
+		err = sessionDriver.RemoveExternalNodeFromMlsMultiTree(removedChatbotID)
+		if err != nil {
+			logger.Info("Chatbot ", removedChatbotID, " has no external node in MlsMultiTree: ", err)
+		}
+	}
+	return nil
+}
+
+/*
+RekeyGroupChatbots forces a key update to every remaining chatbot of the group after a chatbot is removed. Until then,
+the remaining chatbots share a root with the removed one, so the removed chatbot could still read IGA messages. The
+update is carried by a SKIP message with random content.
+Pseudonymous chatbots without a registered pseudonym are left out; they receive a fresh root when the pseudonym is registered.
+*/
+func (csu *ClientSideUser) RekeyGroupChatbots(ctx context.Context, groupID string, groupType pb.GroupType) error {
+	var groupChatbotIDs []string
+	var isPseudo func(string) bool
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return err
+		}
+		groupChatbotIDs, isPseudo = sessionDriver.GetGroupChatbots(), sessionDriver.GetChatbotIsPseudo
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			return err
+		}
+		groupChatbotIDs, isPseudo = sessionDriver.GetGroupChatbots(), sessionDriver.GetChatbotIsPseudo
+	case pb.GroupType_MLS:
+		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			return err
+		}
+		groupChatbotIDs, isPseudo = sessionDriver.GetGroupChatbots(), sessionDriver.GetChatbotIsPseudo
+	default:
+		return fmt.Errorf("unknown group type %v", groupType)
+	}
+
+	rekeyedChatbotIDs := make([]string, 0, len(groupChatbotIDs))
+	for _, chatbotID := range groupChatbotIDs {
+		if isPseudo(chatbotID) && csu.GetPseudoUser(groupID, chatbotID) == nil {
+			logger.Info("Skipping rekey of pseudonymous chatbot ", chatbotID, " without a registered pseudonym")
+			continue
+		}
+		rekeyedChatbotIDs = append(rekeyedChatbotIDs, chatbotID)
+	}
+	if len(rekeyedChatbotIDs) == 0 {
+		return nil
+	}
+
+	logger.Info("Rekeying chatbots ", rekeyedChatbotIDs, " in group ", groupID)
+	padding := []byte(util.RandomString(32))
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		return csu.SendServerSideGroupMessage(ctx, groupID, padding, pb.MessageType_SKIP, rekeyedChatbotIDs, false)
+	case pb.GroupType_CLIENT_SIDE:
+		return csu.SendClientSideGroupMessage(ctx, groupID, padding, pb.MessageType_SKIP, rekeyedChatbotIDs)
+	default:
+		return csu.SendMlsGroupMessage(ctx, groupID, padding, pb.MessageType_SKIP, rekeyedChatbotIDs, false)
 	}
 }
 
 /*
 RequestInviteUserToGroup invites a group member to the group. This function both send request and add the member.
 */
-func (csu *ClientSideUser) RequestInviteUserToGroup(groupID string, groupType pb.GroupType, invitedID string) {
+func (csu *ClientSideUser) RequestInviteUserToGroup(ctx context.Context, groupID string, groupType pb.GroupType, invitedID string) error {
 	// For TreeKEM's UserAdd, GroupInitKey, and chatbots external join messages.
 	var ua treekem.UserAdd
 	var gik treekem.GroupInitKey
@@ -406,27 +519,36 @@ func (csu *ClientSideUser) RequestInviteUserToGroup(groupID string, groupType pb
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Error("Error getting MLS group session driver: ", err)
-			panic("")
+			return err
 		}
 
 		// Get key package
-		keyPackage, keyPackageID, err = csu.Client.GetOthersMLSKeyPackage(invitedID)
+		keyPackage, keyPackageID, err = csu.Client.GetOthersMLSKeyPackage(ctx, invitedID)
 		if err != nil {
 			logger.Error("Error getting MLS key package: ", err)
-			panic("")
+			return err
 		}
 		welcome, add, addCommit, err := sessionDriver.GetWelcomeMessage(keyPackage)
 		if err != nil {
 			logger.Error("Error getting welcome message: ", err)
-			panic("")
+			return err
 		}
 		welcomeSerialized, err = syntax.Marshal(welcome)
+		if err != nil {
+			return err
+		}
 		addSerialized, err = syntax.Marshal(add)
+		if err != nil {
+			return err
+		}
 		addCommitSerialized, err = syntax.Marshal(addCommit)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Send group invitation request to server
-	res, err := csu.chatServiceClient.InviteMember(csu.chatServiceClientCtx, &pb.InviteMemberRequest{
+	res, err := csu.chatServiceClient.InviteMember(ctx, &pb.InviteMemberRequest{
 		GroupID:                    groupID,
 		InitiatorID:                csu.userID,
 		InvitedID:                  invitedID,
@@ -440,59 +562,195 @@ func (csu *ClientSideUser) RequestInviteUserToGroup(groupID string, groupType pb
 		MlsUserAdd:                 addSerialized,
 		MlsAddCommit:               addCommitSerialized,
 		MlsKeyPackageID:            keyPackageID,
+		TreeKEMIndices:             csu.getTreeKEMIndices(groupID, groupType),
+		TreeKEMPublicTree:          csu.getTreeKEMPublicTree(groupID, groupType),
 	})
 
-	if err != nil || res.ErrorMessage != "" {
+	if err != nil {
 		logger.Error("Error inviting member to group: ", err)
-		panic("")
+		return err
+	}
+	if res.GetErrorMessage() != "" {
+		logger.Error("Error inviting member to group: ", res.GetErrorMessage())
+		return fmt.Errorf("%w: %v", ErrRequestRejected, res.GetErrorMessage())
 	}
 
 	logger.Info("Received response for group invitation: ", res.String())
+	return nil
+}
+
+/*
+getCipherSuite returns the cipher suite the CMRT of the group runs with, or the default suite if not in the group.
+*/
+func (csu *ClientSideUser) getCipherSuite(groupID string, groupType pb.GroupType) treekem.CipherSuite {
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		if sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID); err == nil {
+			return sessionDriver.GetCipherSuite()
+		}
+	case pb.GroupType_CLIENT_SIDE:
+		if sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID); err == nil {
+			return sessionDriver.GetCipherSuite()
+		}
+	case pb.GroupType_MLS:
+		if sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID); err == nil {
+			return sessionDriver.GetCipherSuite()
+		}
+	}
+	return treekem.MustCipherSuite(treekem.DefaultCipherSuite)
+}
+
+/*
+getTreeKEMIndices returns the TreeKEM leaf indices of the group members, so that an invited user is able to remove them.
+Only server-side groups remove members from the TreeKEM.
+*/
+func (csu *ClientSideUser) getTreeKEMIndices(groupID string, groupType pb.GroupType) map[string]uint32 {
+	if groupType != pb.GroupType_SERVER_SIDE {
+		return nil
+	}
+	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return nil
+	}
+
+	treekemIndices := make(map[string]uint32)
+	for userID, index := range sessionDriver.GetTreeKEMIndices() {
+		treekemIndices[userID] = uint32(index)
+	}
+	return treekemIndices
+}
+
+/*
+setTreeKEMIndices sets the TreeKEM leaf indices of the group members received with the group invitation.
+*/
+func (csu *ClientSideUser) setTreeKEMIndices(groupID string, groupType pb.GroupType, treekemIndices map[string]uint32) {
+	if groupType != pb.GroupType_SERVER_SIDE {
+		return
+	}
+	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return
+	}
+
+	for userID, index := range treekemIndices {
+		sessionDriver.SetTreeKEMIndex(userID, int(index))
+	}
+}
+
+/*
+getTreeKEMPublicTree returns the public part of the TreeKEM. The group init key only carries the frontier, which is
+not enough for an invited user to remove the members that joined before it.
+*/
+func (csu *ClientSideUser) getTreeKEMPublicTree(groupID string, groupType pb.GroupType) map[uint32]*pb.TreeKEMNode {
+	if groupType != pb.GroupType_SERVER_SIDE {
+		return nil
+	}
+	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return nil
+	}
+	return treekem.TreeKEMNodeMapPbConvert(sessionDriver.GetTreeKEMPublicTree())
+}
+
+/*
+setTreeKEMPublicTree merges the public part of the TreeKEM received with the group invitation.
+*/
+func (csu *ClientSideUser) setTreeKEMPublicTree(groupID string, groupType pb.GroupType, publicTree map[uint32]*pb.TreeKEMNode) {
+	if groupType != pb.GroupType_SERVER_SIDE {
+		return
+	}
+	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return
+	}
+	sessionDriver.HandleTreeKEMPublicTree(treekem.PbTreeKEMNodeMapConvert(publicTree))
 }
 
 /*
 RequestRemoveUserFromGroup requests to remove a group member from the group. This function only send request but does not remove the member.
 */
-func (csu *ClientSideUser) RequestRemoveUserFromGroup(groupID string, removedID string) {
+func (csu *ClientSideUser) RequestRemoveUserFromGroup(ctx context.Context, groupID string, removedID string) error {
 	// Generate Remove for MLS group if needed
 	var removeSerialized []byte
 	var removeCommitSerialized []byte
+	var treekemUserRemove *treekem.UserRemove
+	if sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID); err == nil {
+		// Generate a TreeKEM remove that blanks the leaf of the removed user
+		treekemUserRemove, err = sessionDriver.RemoveUserFromTreeKEM(removedID)
+		if err != nil {
+			logger.Error("Error getting TreeKEM remove: ", err)
+		}
+	}
 	if sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID); err == nil {
 		remove, removeCommit, err := sessionDriver.GetRemoveMessage(removedID)
 		if err != nil {
 			logger.Error("Error getting remove message: ", err)
-			panic("")
+			return err
 		}
 		removeSerialized, err = syntax.Marshal(remove)
+		if err != nil {
+			return err
+		}
 		removeCommitSerialized, err = syntax.Marshal(removeCommit)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Send group removal request to server
-	res, err := csu.chatServiceClient.RemoveMember(csu.chatServiceClientCtx, &pb.RemoveMemberRequest{
-		GroupID:         groupID,
-		InitiatorID:     csu.userID,
-		RemovedID:       removedID,
-		MlsRemove:       removeSerialized,
-		MlsRemoveCommit: removeCommitSerialized,
+	res, err := csu.chatServiceClient.RemoveMember(ctx, &pb.RemoveMemberRequest{
+		GroupID:           groupID,
+		InitiatorID:       csu.userID,
+		RemovedID:         removedID,
+		MlsRemove:         removeSerialized,
+		MlsRemoveCommit:   removeCommitSerialized,
+		TreeKEMUserRemove: treekem.TreeKEMUserRemovePbConvert(treekemUserRemove),
 	})
 
-	if err != nil || res.ErrorMessage != "" {
+	if err != nil {
 		logger.Error("Error removing member from group: ", err)
-		panic("")
+		return err
+	}
+	if res.GetErrorMessage() != "" {
+		logger.Error("Error removing member from group: ", res.GetErrorMessage())
+		return fmt.Errorf("%w: %v", ErrRequestRejected, res.GetErrorMessage())
 	}
 
 	logger.Info("Received response for group removal: ", res.String())
+	return nil
+}
+
+/*
+RequestInviteChatbotToGroup invites a chatbot with full access to the group. This function only send request but does not add the chatbot.
+*/
+func (csu *ClientSideUser) RequestInviteChatbotToGroup(ctx context.Context, groupID string, groupType pb.GroupType, invitedID string, isIGA bool, isPseudo bool) error {
+	return csu.RequestInviteChatbotToGroupWithScopes(ctx, groupID, groupType, invitedID, isIGA, isPseudo, nil)
+}
+
+/*
+RequestInviteChatbotToGroupWithScopes invites a chatbot to the group with the given access scopes. A nil scopes grants full access.
+This function only send request but does not add the chatbot.
+*/
+func (csu *ClientSideUser) RequestInviteChatbotToGroupWithScopes(ctx context.Context, groupID string, groupType pb.GroupType, invitedID string, isIGA bool, isPseudo bool, scopes *pb.ChatbotScopes) error {
+	return csu.RequestInviteChatbotToGroupWithHybridKEM(ctx, groupID, groupType, invitedID, isIGA, isPseudo, scopes, false)
 }
 
 /*
-RequestInviteChatbotToGroup invites a chatbot to the group. This function only send request but does not add the chatbot.
+RequestInviteChatbotToGroupWithHybridKEM invites a chatbot to the group with the given access scopes, and lets the
+TreeKEM ciphertexts to and from the chatbot use the hybrid ML-KEM-768 + X25519 KEM if hybridKEM is set. MLS groups do
+not support the hybrid KEM. This function only send request but does not add the chatbot.
 */
-func (csu *ClientSideUser) RequestInviteChatbotToGroup(groupID string, groupType pb.GroupType, invitedID string, isIGA bool, isPseudo bool) {
+func (csu *ClientSideUser) RequestInviteChatbotToGroupWithHybridKEM(ctx context.Context, groupID string, groupType pb.GroupType, invitedID string, isIGA bool, isPseudo bool, scopes *pb.ChatbotScopes, hybridKEM bool) error {
+	// The chatbot's external node key is derived for the KEM it uses, so the KEM is set before it joins.
+	err := csu.SetChatbotHybridKEM(groupID, groupType, invitedID, hybridKEM)
+	if err != nil {
+		logger.Error("Error setting hybrid KEM of chatbot: ", err)
+		return err
+	}
 
 	// For TreeKEM
 	var chatbotCipherText treekem.ECKEMCipherText
 	var initLeaf []byte
-	var err error
 
 	// For MLS's welcome message
 	var welcomeSerialized []byte
@@ -501,80 +759,125 @@ func (csu *ClientSideUser) RequestInviteChatbotToGroup(groupID string, groupType
 	var keyPackageID uint32
 	var keyPackage mls.KeyPackage
 
-	if groupType != pb.GroupType_MLS || isIGA {
-		chatbotCipherText, initLeaf, err = csu.TreeKEMChatbotAdd(groupID, groupType, invitedID)
-		if err != nil {
-			logger.Error("Error adding chatbot to group: ", err)
-			panic("")
-		}
+	// Non-IGA chatbots in MLS groups also join the MlsMultiTree, so that their external node key can be used to hide triggers.
+	chatbotCipherText, initLeaf, err = csu.TreeKEMChatbotAdd(groupID, groupType, invitedID)
+	if err != nil {
+		logger.Error("Error adding chatbot to group: ", err)
+		return err
 	}
 	if groupType == pb.GroupType_MLS && !isIGA {
 		// Get MLS Welcome message
 		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 		if err != nil {
 			logger.Error("Error getting MLS group session driver: ", err)
-			panic("")
+			return err
 		}
 
 		// Get key package
-		keyPackage, keyPackageID, err = csu.Client.GetOthersMLSKeyPackage(invitedID)
+		keyPackage, keyPackageID, err = csu.Client.GetOthersMLSKeyPackage(ctx, invitedID)
 		if err != nil {
 			logger.Error("Error getting MLS key package: ", err)
-			panic("")
+			return err
 		}
 		welcome, add, addCommit, err := sessionDriver.GetWelcomeMessage(keyPackage)
 		if err != nil {
 			logger.Error("Error getting welcome message: ", err)
-			panic("")
+			return err
 		}
 		welcomeSerialized, err = syntax.Marshal(welcome)
+		if err != nil {
+			return err
+		}
 		addSerialized, err = syntax.Marshal(add)
+		if err != nil {
+			return err
+		}
 		addCommitSerialized, err = syntax.Marshal(addCommit)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Send group invitation request to server
 	rootPub, rootSignPub := csu.GetTreeKEMRootPublicKey(groupID, groupType)
-	res, err := csu.chatServiceClient.InviteChatbot(csu.chatServiceClientCtx, &pb.InviteChatbotRequest{
-		GroupID:            groupID,
-		InitiatorID:        csu.userID,
-		InvitedID:          invitedID,
-		IsIGA:              isIGA,
-		IsPseudo:           isPseudo,
-		TreekemRootPub:     rootPub,
-		TreekemRootSignPub: rootSignPub,
-		ChatbotInitLeaf:    initLeaf,
-		ChatbotCipherText:  treekem.ECKEMCipherTextPbConvert(&chatbotCipherText),
-		MlsWelcomeMessage:  welcomeSerialized,
-		MlsUserAdd:         addSerialized,
-		MlsAddCommit:       addCommitSerialized,
-		MlsKeyPackageID:    keyPackageID,
+	if hybridKEM {
+		rootPub, err = csu.GetTreeKEMRootHybridPublicKey(groupID, groupType)
+		if err != nil {
+			logger.Error("Error getting hybrid TreeKEM root key: ", err)
+			return err
+		}
+	}
+	// The members route messages to the chatbot by the routing the inviter signs, so that the server cannot change it.
+	chatbotRouting, err := csu.Client.GetChatbotRouting(ctx, invitedID)
+	if err != nil {
+		logger.Error("Error getting chatbot routing: ", err)
+		return err
+	}
+	signedChatbotRouting, err := csu.Client.SignChatbotRouting(groupID, invitedID, chatbotRouting)
+	if err != nil {
+		logger.Error("Error signing chatbot routing: ", err)
+		return err
+	}
+	signedScopes, err := csu.Client.SignChatbotScopes(groupID, invitedID, scopes, 1)
+	if err != nil {
+		logger.Error("Error signing chatbot scopes: ", err)
+		return err
+	}
+	res, err := csu.chatServiceClient.InviteChatbot(ctx, &pb.InviteChatbotRequest{
+		GroupID:              groupID,
+		InitiatorID:          csu.userID,
+		InvitedID:            invitedID,
+		IsIGA:                isIGA,
+		IsPseudo:             isPseudo,
+		TreekemRootPub:       rootPub,
+		TreekemRootSignPub:   rootSignPub,
+		ChatbotInitLeaf:      initLeaf,
+		ChatbotCipherText:    treekem.ECKEMCipherTextPbConvert(&chatbotCipherText),
+		MlsWelcomeMessage:    welcomeSerialized,
+		MlsUserAdd:           addSerialized,
+		MlsAddCommit:         addCommitSerialized,
+		MlsKeyPackageID:      keyPackageID,
+		Scopes:               scopes,
+		HybridKEM:            hybridKEM,
+		SignedChatbotRouting: signedChatbotRouting,
+		SignedScopes:         signedScopes,
 	})
 
-	if err != nil || res.ErrorMessage != "" {
+	if err != nil {
 		logger.Error("Error inviting chatbot to group: ", err)
-		panic("")
+		return err
+	}
+	if res.GetErrorMessage() != "" {
+		logger.Error("Error inviting chatbot to group: ", res.GetErrorMessage())
+		return fmt.Errorf("%w: %v", ErrRequestRejected, res.GetErrorMessage())
 	}
 
 	logger.Info("Received response for group invitation: ", res.String())
+	return nil
 }
 
 /*
 RequestRemoveChatbotFromGroup requests to remove a chatbot from the group. This function only send request but does not remove the chatbot.
 */
-func (csu *ClientSideUser) RequestRemoveChatbotFromGroup(groupID string, removedID string) {
+func (csu *ClientSideUser) RequestRemoveChatbotFromGroup(ctx context.Context, groupID string, removedID string) error {
 	// Send group removal request to server
-	res, err := csu.chatServiceClient.RemoveChatbot(csu.chatServiceClientCtx, &pb.RemoveChatbotRequest{
+	res, err := csu.chatServiceClient.RemoveChatbot(ctx, &pb.RemoveChatbotRequest{
 		GroupID:     groupID,
 		InitiatorID: csu.userID,
 		RemovedID:   removedID,
 	})
 
-	if err != nil || res.ErrorMessage != "" {
+	if err != nil {
 		logger.Error("Error removing chatbot from group: ", err)
-		panic("")
+		return err
+	}
+	if res.GetErrorMessage() != "" {
+		logger.Error("Error removing chatbot from group: ", res.GetErrorMessage())
+		return fmt.Errorf("%w: %v", ErrRequestRejected, res.GetErrorMessage())
 	}
 
 	logger.Info("Received response for group removal: ", res.String())
+	return nil
 }
 
 /*
@@ -735,7 +1038,11 @@ func (csu *ClientSideUser) CreatePseudoUser(groupId string, groupType pb.GroupTy
 		logger.Error("Failed to generate random bytes: ", err)
 		return "", nil, err
 	}
-	pseudoSigningKp, err := treekem.NewSigningKeyPairFromSecret(signSecret)
+	pseudoSigningKp, err := csu.getCipherSuite(groupId, groupType).DeriveSigningKeyPair(signSecret)
+	if err != nil {
+		logger.Error("Failed to derive signing key pair: ", err)
+		return "", nil, err
+	}
 	pseudoUser := &PseudoUser{
 		PseudoUserID:   pseudonymId,
 		SigningKeyPair: *pseudoSigningKp,
@@ -747,7 +1054,7 @@ func (csu *ClientSideUser) CreatePseudoUser(groupId string, groupType pb.GroupTy
 	}
 	csu.pseudoUsers[groupId][chatbotId] = pseudoUser
 
-	return pseudonymId, pseudoSigningKp.Public.Bytes(), nil
+	return pseudonymId, pseudoSigningKp.Public, nil
 
 }
 
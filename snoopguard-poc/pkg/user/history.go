@@ -0,0 +1,94 @@
+package user
+
+import (
+	"chatbot-poc-go/pkg/client"
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
+	"go.mau.fi/libsignal/logger"
+	"time"
+)
+
+/*
+SetMessageHistoryFile sets the file the message history of the user is kept in, encrypted at rest under passphrase. The
+history already in the file is loaded, and the messages are appended to it from then on.
+*/
+func (csu *ClientSideUser) SetMessageHistoryFile(path string, passphrase []byte) error {
+	return csu.history.SetFile(path, passphrase)
+}
+
+/*
+SetMessageHistoryRetention deletes the messages of the history once they are older than retention. Zero keeps them
+forever, which is the default.
+*/
+func (csu *ClientSideUser) SetMessageHistoryRetention(retention time.Duration) error {
+	return csu.history.SetRetention(retention)
+}
+
+/*
+GetMessageHistory returns a page of the text messages the user sent and received, oldest first. The group of an
+individual message is the other user.
+*/
+func (csu *ClientSideUser) GetMessageHistory(query stores.HistoryQuery) []*pb.HistoryEntry {
+	return csu.history.Query(query)
+}
+
+/*
+SearchMessageHistory returns the latest text messages, up to limit, that contain every word of text.
+*/
+func (csu *ClientSideUser) SearchMessageHistory(text string, limit int) []*pb.HistoryEntry {
+	return csu.history.Query(stores.HistoryQuery{Text: text, Limit: limit})
+}
+
+/*
+recordSentMessage adds a text message the user sent to the history under its MessageID, and tracks its receipts if it
+was sent to a group. Other message types are not kept.
+*/
+func (csu *ClientSideUser) recordSentMessage(groupID string, messageID string, message []byte, messageType pb.MessageType) {
+	if messageType != pb.MessageType_TEXT_MESSAGE || message == nil {
+		return
+	}
+	csu.trackSentMessage(groupID, messageID, messageType)
+	err := csu.history.Append(&pb.HistoryEntry{
+		MessageID:   messageID,
+		GroupID:     groupID,
+		SenderID:    csu.userID,
+		MessageType: messageType,
+		Message:     message,
+	})
+	if err != nil {
+		logger.Error("Failed to record message history: ", err)
+	}
+}
+
+/*
+recordReceivedMessage adds a text message the user received to the history, under its MessageID and the time the server
+gave it. A message the server delivered without a MessageID is given a new one.
+*/
+func (csu *ClientSideUser) recordReceivedMessage(output *OutputMessage) {
+	if output.Event != nil || output.MessageType != pb.MessageType_TEXT_MESSAGE || output.Message == nil {
+		return
+	}
+	var timestamp int64
+	if !output.Timestamp.IsZero() {
+		timestamp = output.Timestamp.UnixMilli()
+	}
+	messageID := output.MessageID
+	if messageID == "" {
+		messageID = client.NewMessageID()
+	}
+	groupID := output.GroupID
+	if output.Channel == pb.Channel_INDIVIDUAL_CHANNEL {
+		groupID = output.SenderID
+	}
+	err := csu.history.Append(&pb.HistoryEntry{
+		MessageID:   messageID,
+		GroupID:     groupID,
+		SenderID:    output.SenderID,
+		Timestamp:   timestamp,
+		MessageType: output.MessageType,
+		Message:     output.Message,
+	})
+	if err != nil {
+		logger.Error("Failed to record message history: ", err)
+	}
+}
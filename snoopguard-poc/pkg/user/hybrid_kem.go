@@ -0,0 +1,92 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+)
+
+/*
+SetChatbotHybridKEM sets whether the TreeKEM ciphertexts to and from a chatbot in the given group use the hybrid
+ML-KEM-768 + X25519 KEM. It has to be set before the chatbot is added to the MultiTreeKEM. MLS groups do not support
+the hybrid KEM.
+*/
+func (csu *ClientSideUser) SetChatbotHybridKEM(groupID string, groupType pb.GroupType, chatbotID string, hybridKEM bool) error {
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Info("Not in the group: ", groupID)
+			return err
+		}
+		sessionDriver.SetChatbotHybridKEM(chatbotID, hybridKEM)
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Info("Not in the group: ", groupID)
+			return err
+		}
+		sessionDriver.SetChatbotHybridKEM(chatbotID, hybridKEM)
+	case pb.GroupType_MLS:
+		if hybridKEM {
+			return fmt.Errorf("hybrid KEM is not supported in MLS groups")
+		}
+	}
+	return nil
+}
+
+/*
+GetChatbotHybridKEM returns whether the TreeKEM ciphertexts to and from a chatbot in the given group use the hybrid KEM.
+*/
+func (csu *ClientSideUser) GetChatbotHybridKEM(groupID string, groupType pb.GroupType, chatbotID string) bool {
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return false
+		}
+		return sessionDriver.GetChatbotHybridKEM(chatbotID)
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			return false
+		}
+		return sessionDriver.GetChatbotHybridKEM(chatbotID)
+	}
+	return false
+}
+
+/*
+GetTreeKEMRootHybridPublicKey returns the hybrid public key of the root of the treekem of the group, which chatbots
+using the hybrid KEM encrypt their updates to.
+*/
+func (csu *ClientSideUser) GetTreeKEMRootHybridPublicKey(groupID string, groupType pb.GroupType) ([]byte, error) {
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Info("Not in the group: ", groupID)
+			return nil, err
+		}
+		return sessionDriver.GetMultiTreeKEM().GetTreeKEMRootHybridPublic()
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Info("Not in the group: ", groupID)
+			return nil, err
+		}
+		return sessionDriver.GetMultiTreeKEM().GetTreeKEMRootHybridPublic()
+	}
+	return nil, fmt.Errorf("hybrid KEM is not supported in group type %v", groupType)
+}
+
+/*
+rootHybridPubKeyFor returns the hybrid root public key if the chatbot uses the hybrid KEM, so that chatbots not using it
+are not sent the key.
+*/
+func rootHybridPubKeyFor(hybridKEM bool, rootHybridPubKey []byte) []byte {
+	if !hybridKEM {
+		return nil
+	}
+	return rootHybridPubKey
+}
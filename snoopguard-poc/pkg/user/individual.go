@@ -3,6 +3,7 @@ package user
 import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/protobuf/proto"
@@ -11,8 +12,8 @@ import (
 /*
 CreateIndividualSession creates a session with a recipient.
 */
-func (csu *ClientSideUser) CreateIndividualSession(recipientAddress *protocol.SignalAddress) (*client.ClientSessionDriver, error) {
-	preKeyBundle, err := csu.Client.GetOthersPreKeyBundle(recipientAddress.Name())
+func (csu *ClientSideUser) CreateIndividualSession(ctx context.Context, recipientAddress *protocol.SignalAddress) (*client.ClientSessionDriver, error) {
+	preKeyBundle, err := csu.Client.GetOthersPreKeyBundle(ctx, recipientAddress.Name())
 	if err != nil {
 		return nil, err
 	}
@@ -23,11 +24,13 @@ func (csu *ClientSideUser) CreateIndividualSession(recipientAddress *protocol.Si
 /*
 SendIndividualMessage send a message to a recipient given that the client session is already established.
 */
-func (csu *ClientSideUser) SendIndividualMessage(recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) error {
+func (csu *ClientSideUser) SendIndividualMessage(ctx context.Context, recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) error {
 	logger.Debug("Sending individual message: ", string(message))
+	messageID := client.NewMessageID()
 	packedMessage := &pb.Message{
 		Message:     message,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 
 	packedMessageMarshal, err := proto.Marshal(packedMessage)
@@ -37,7 +40,7 @@ func (csu *ClientSideUser) SendIndividualMessage(recipientAddress *protocol.Sign
 	}
 	sessionDriver, err := csu.Client.GetSessionDriver(recipientAddress)
 	if err != nil {
-		sessionDriver, err = csu.CreateIndividualSession(recipientAddress)
+		sessionDriver, err = csu.CreateIndividualSession(ctx, recipientAddress)
 		if err != nil {
 			logger.Error("Error creating session with ", recipientAddress.Name(), ": ", err)
 			return err
@@ -49,21 +52,29 @@ func (csu *ClientSideUser) SendIndividualMessage(recipientAddress *protocol.Sign
 		RecipientID:      recipientAddress.Name(),
 		EncryptedMessage: encryptedMessage.Serialize(),
 		HasPreKey:        encryptedMessage.Type() == protocol.PREKEY_TYPE,
+		MessageID:        messageID,
 	}
 
 	logger.Debug("Sending message to server: ", packedMessageWrapper.String())
 
-	return csu.Client.SendIndividualMessage(recipientAddress, packedMessageWrapper)
+	err = csu.Client.SendIndividualMessage(ctx, recipientAddress, packedMessageWrapper)
+	if err != nil {
+		return err
+	}
+	csu.recordSentMessage(recipientAddress.Name(), messageID, message, messageType)
+	return nil
 }
 
 /*
 SendIndividualMessageBenchmark send a message to a recipient given that the client session is already established.
 */
-func (csu *ClientSideUser) SendIndividualMessageBenchmark(recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) (*pb.MessageWrapper, error) {
+func (csu *ClientSideUser) SendIndividualMessageBenchmark(ctx context.Context, recipientAddress *protocol.SignalAddress, message []byte, messageType pb.MessageType) (*pb.MessageWrapper, error) {
 	logger.Debug("Sending individual message: ", string(message))
+	messageID := client.NewMessageID()
 	packedMessage := &pb.Message{
 		Message:     message,
 		MessageType: messageType,
+		MessageID:   messageID,
 	}
 
 	packedMessageMarshal, err := proto.Marshal(packedMessage)
@@ -73,7 +84,7 @@ func (csu *ClientSideUser) SendIndividualMessageBenchmark(recipientAddress *prot
 	}
 	sessionDriver, err := csu.Client.GetSessionDriver(recipientAddress)
 	if err != nil {
-		sessionDriver, err = csu.CreateIndividualSession(recipientAddress)
+		sessionDriver, err = csu.CreateIndividualSession(ctx, recipientAddress)
 		if err != nil {
 			logger.Error("Error creating session with ", recipientAddress.Name(), ": ", err)
 			return nil, err
@@ -85,6 +96,7 @@ func (csu *ClientSideUser) SendIndividualMessageBenchmark(recipientAddress *prot
 		RecipientID:      recipientAddress.Name(),
 		EncryptedMessage: encryptedMessage.Serialize(),
 		HasPreKey:        encryptedMessage.Type() == protocol.PREKEY_TYPE,
+		MessageID:        messageID,
 	}
 
 	logger.Debug("Sending message to server: ", packedMessageWrapper.String())
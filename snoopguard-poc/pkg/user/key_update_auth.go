@@ -0,0 +1,56 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"go.mau.fi/libsignal/logger"
+)
+
+/*
+macTreeKEMKeyUpdatePack adds the MAC of every chatbot the key update pack carries a ciphertext for, so that chatbots
+with IGA can authenticate the update without learning who sent it. The MAC keys have to be taken from the roots before
+the update is generated.
+*/
+func macTreeKEMKeyUpdatePack(groupID string, pack *pb.TreeKEMKeyUpdatePack, updateMACKeys map[string][]byte) error {
+	tbs, err := treekem.KeyUpdatePackTBS(groupID, pack)
+	if err != nil {
+		logger.Error("Failed to encode key update pack: ", err)
+		return err
+	}
+
+	pack.ChatbotMACs = make(map[string][]byte)
+	for chatbotID := range pack.GetChatbotUpdateCiphertexts().GetCiphertexts() {
+		if macKey, ok := updateMACKeys[chatbotID]; ok {
+			pack.ChatbotMACs[chatbotID] = treekem.KeyUpdateMAC(macKey, tbs)
+		}
+	}
+	return nil
+}
+
+/*
+signTreeKEMKeyUpdatePack returns a copy of the key update pack signed with the identity key of the user, for the
+recipients who know the sender anyway.
+*/
+func (csu *ClientSideUser) signTreeKEMKeyUpdatePack(groupID string, pack *pb.TreeKEMKeyUpdatePack) (*pb.TreeKEMKeyUpdatePack, error) {
+	if pack == nil {
+		return nil, nil
+	}
+	signed, err := csu.Client.SignTreeKEMKeyUpdatePack(groupID, pack)
+	if err != nil {
+		logger.Error("Failed to sign key update pack: ", err)
+		return nil, err
+	}
+	return signed, nil
+}
+
+/*
+verifyTreeKEMKeyUpdatePack checks that a key update pack from another member is signed by the identity key of the
+sender. Unauthenticated updates must not be applied.
+*/
+func (csu *ClientSideUser) verifyTreeKEMKeyUpdatePack(groupID string, senderID string, pack *pb.TreeKEMKeyUpdatePack) error {
+	err := csu.Client.VerifyTreeKEMKeyUpdatePackSignature(groupID, senderID, pack)
+	if err != nil {
+		logger.Error("Rejected TreeKEM update from ", senderID, " in group ", groupID, ": ", err)
+	}
+	return err
+}
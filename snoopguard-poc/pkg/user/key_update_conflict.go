@@ -0,0 +1,78 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+sendGroupMessageWithKeyUpdate issues and sends a group message that carries a key update, issuing it again after every
+key update conflict as client.KeyUpdateSequence.RetryConflicts describes.
+
+A message left in the outbox of the group is sent first, as the new one is issued on top of it. If it still cannot be
+sent, the new message is not issued and ErrOutboxPending is returned.
+*/
+func (csu *ClientSideUser) sendGroupMessageWithKeyUpdate(ctx context.Context, entry *pb.StoredOutboxEntry, issue func() (*pb.MessageWrapper, error)) error {
+	groupID := entry.GetGroupID()
+	if err := csu.flushOutbox(ctx, groupID); errors.Is(err, ErrMessageQueued) {
+		return fmt.Errorf("%w: %v", ErrOutboxPending, err)
+	}
+
+	return csu.Client.GetKeyUpdateSequence().RetryConflicts(ctx, groupID, func() error {
+		return csu.sendGroupMessageOrRollback(ctx, entry, issue)
+	})
+}
+
+/*
+sendGroupMessageOrRollback issues and sends a group message while no incoming message is handled. The group state is
+rolled back if the server rejected the message. If it is not known whether the server got it, the
+message is kept in the outbox together with the state to roll back to, and an error wrapping ErrMessageQueued is
+returned.
+*/
+func (csu *ClientSideUser) sendGroupMessageOrRollback(ctx context.Context, entry *pb.StoredOutboxEntry, issue func() (*pb.MessageWrapper, error)) error {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+
+	groupID := entry.GetGroupID()
+	rollback, err := csu.snapshotGroup(groupID, entry.GetGroupType())
+	if err != nil {
+		return err
+	}
+	var rollbackGroup *pb.StoredGroup
+	if csu.stateStore != nil {
+		rollbackGroup, err = csu.Client.ExportGroup(groupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	messageWrapper, err := issue()
+	if err != nil {
+		rollback()
+		return err
+	}
+
+	err = csu.sendIssuedGroupMessage(ctx, groupID, entry.GetGroupType(), messageWrapper)
+	if err == nil {
+		csu.recordSentMessage(groupID, messageWrapper.GetMessageID(), entry.GetPlaintext(), entry.GetMessageType())
+		return nil
+	}
+	if !deliveryUnknown(err) {
+		rollback()
+		return err
+	}
+
+	queued := proto.Clone(entry).(*pb.StoredOutboxEntry)
+	queued.Message = messageWrapper
+	queued.RollbackGroup = rollbackGroup
+	csu.outbox[groupID] = &outboxEntry{stored: queued, rollback: rollback}
+	if persistErr := csu.persistLocked(); persistErr != nil {
+		logger.Error("Failed to persist state: ", persistErr)
+	}
+	logger.Warning("Kept message ", messageWrapper.GetMessageID(), " to group ", groupID, " in the outbox: ", err)
+	return fmt.Errorf("%w: %w", ErrMessageQueued, err)
+}
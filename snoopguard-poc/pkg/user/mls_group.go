@@ -5,6 +5,9 @@ import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
 	"chatbot-poc-go/pkg/util"
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	syntax "github.com/cisco/go-tls-syntax"
 	"github.com/s3131212/go-mls"
@@ -15,7 +18,7 @@ import (
 /*
 PostCreateMlsGroup is called when a new MLS group is created.
 */
-func (csu *ClientSideUser) PostCreateMlsGroup(groupID string, chatbotIsIGA map[string]bool, chatbotIsPseudo map[string]bool, welcome mls.Welcome, keyPackageId uint32) (*client.MlsGroupSessionDriver, error) {
+func (csu *ClientSideUser) PostCreateMlsGroup(groupID string, chatbotIsIGA map[string]bool, chatbotIsPseudo map[string]bool, welcome mls.Welcome, keyPackageId uint32, cipherSuite treekem.CipherSuiteID) (*client.MlsGroupSessionDriver, error) {
 	sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, err
@@ -33,6 +36,11 @@ func (csu *ClientSideUser) PostCreateMlsGroup(groupID string, chatbotIsIGA map[s
 	}
 
 	// Setup MlsMultiTree
+	err = sessionDriver.SetCipherSuite(cipherSuite)
+	if err != nil {
+		logger.Error("Failed to set cipher suite: ", err)
+		return nil, err
+	}
 	err = sessionDriver.InitiateMlsMultiTree()
 
 	// Set Chatbot IGA status.
@@ -49,39 +57,47 @@ func (csu *ClientSideUser) PostCreateMlsGroup(groupID string, chatbotIsIGA map[s
 }
 
 /*
-HandleMlsGroupMessage handles the incoming MLS group message.
+HandleMlsGroupMessage handles the incoming MLS group message. The message is returned along with the error if only its
+key update failed.
 */
-func (csu *ClientSideUser) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csu *ClientSideUser) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType, error) {
 	groupId, senderId := messageWrapper.RecipientID, messageWrapper.SenderID
 
 	sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupId)
 	if err != nil {
 		logger.Error("Received message from Mls fanout group without session ", groupId)
-		return nil, -1
+		return nil, -1, err
 	}
 
 	// Todo: Update CMRT
 
-	err = sessionDriver.UpdateTreeKEMUserKey(messageWrapper.GetChatbotIds())
-	if err != nil {
-		logger.Error("UpdateTreeKEMUserKey failed: ", groupId)
+	// Messages with a commit update the CMRT once the commit is handled, so that each update moves the roots to the
+	// next epoch exactly once.
+	var keyUpdateErr error
+	if messageWrapper.GetMlsCommit() == nil {
+		err = sessionDriver.UpdateTreeKEMUserKey(messageWrapper.GetChatbotIds())
+		if err != nil {
+			logger.Error("UpdateTreeKEMUserKey failed: ", groupId)
+			keyUpdateErr = err
+		}
 	}
 
 	chatbotKeyUpdatePack := messageWrapper.GetChatbotKeyUpdatePack()
 	if chatbotKeyUpdatePack != nil {
 		logger.Info("Received MultiTreeKEM update from ", senderId, " for MLS group ", groupId)
 
-		chatbotUpdate := treekem.PbECKEMCipherTextConvert(chatbotKeyUpdatePack.GetChatbotUpdate())
-		newCbPubKey := chatbotKeyUpdatePack.GetNewCbPubKey()
-		newCbSignPubKey := chatbotKeyUpdatePack.GetNewCbSignPubKey()
-		err = sessionDriver.HandleMlsMultiTreeExternalKeyUpdate(senderId, chatbotUpdate, newCbPubKey, newCbSignPubKey)
+		err = csu.handleChatbotKeyUpdatePack(groupId, senderId, chatbotKeyUpdatePack, sessionDriver.HandleMlsMultiTreeExternalKeyUpdate)
 		if err != nil {
 			logger.Error("UpdateMultiTreeKEMExternalKey failed: ", groupId, err)
+			keyUpdateErr = errors.Join(keyUpdateErr, err)
 		}
 	}
 
 	if messageWrapper.GetIsIGA() {
-		message, messageType := sessionDriver.ParseEncryptedExternalIGAMessage(messageWrapper.EncryptedMessage, messageWrapper.SenderID)
+		message, messageType, err := sessionDriver.ParseEncryptedExternalIGAMessage(messageWrapper.EncryptedMessage, messageWrapper.SenderID, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, errors.Join(err, keyUpdateErr)
+		}
 		logger.Info(fmt.Sprintf("Received IGA message in MLS group %v: %v", messageWrapper.RecipientID, string(message)))
 
 		// Validation message always comes from IGA channel.
@@ -89,15 +105,23 @@ func (csu *ClientSideUser) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapp
 			logger.Info("Received validation message from ", messageWrapper.SenderID, " in MLS group ", messageWrapper.RecipientID, " with content: ", string(message))
 		}
 
-		return message, messageType
+		if !csu.chatbotMayPost(groupId, senderId) {
+			logger.Warning("Dropped message from chatbot ", senderId, " without the scope to post in group ", groupId)
+			return nil, -1, keyUpdateErr
+		}
+
+		return message, messageType, keyUpdateErr
 	} else {
 		deserializedCiphertext, err := util.DeserializeMLSCiphertext(messageWrapper.EncryptedMessage)
 		if err != nil {
-			return nil, -1
+			return nil, -1, errors.Join(fmt.Errorf("%w: %w", ErrUndecryptable, err), keyUpdateErr)
 		}
 
 		// Forward the message to the MLS group handler.
-		message, messageType, receivingChatbotIDs := sessionDriver.ParseEncryptedMessage(senderId, deserializedCiphertext)
+		message, messageType, receivingChatbotIDs, err := sessionDriver.ParseEncryptedMessage(senderId, deserializedCiphertext, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, errors.Join(err, keyUpdateErr)
+		}
 		logger.Info(fmt.Sprintf("Received message from %v in MLS group %v with type %v: %v", messageWrapper.SenderID, messageWrapper.RecipientID, messageType.String(), string(message)))
 
 		// Handle the commit
@@ -105,20 +129,26 @@ func (csu *ClientSideUser) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapp
 		_, err = syntax.Unmarshal(messageWrapper.GetMlsCommit(), commit)
 		if err != nil {
 			logger.Error(err)
-			return nil, -1
+			return nil, -1, errors.Join(fmt.Errorf("%w: %w", ErrUndecryptable, err), keyUpdateErr)
 		}
 		err = sessionDriver.HandleCommit(commit, messageWrapper.SenderID)
 		if err != nil {
 			logger.Error(err)
-			return nil, -1
+			return nil, -1, errors.Join(fmt.Errorf("%w: %w", ErrDesync, err), keyUpdateErr)
 		}
 
 		err = sessionDriver.UpdateTreeKEMUserKey(receivingChatbotIDs)
 		if err != nil {
 			logger.Error("UpdateTreeKEMUserKey failed: ", groupId)
+			keyUpdateErr = errors.Join(keyUpdateErr, err)
 		}
 
-		return message, messageType
+		if !csu.chatbotMayPost(groupId, senderId) {
+			logger.Warning("Dropped message from chatbot ", senderId, " without the scope to post in group ", groupId)
+			return nil, -1, keyUpdateErr
+		}
+
+		return message, messageType, keyUpdateErr
 	}
 
 }
@@ -126,14 +156,23 @@ func (csu *ClientSideUser) HandleMlsGroupMessage(messageWrapper *pb.MessageWrapp
 /*
 SendMlsGroupMessage sends a message to an MLS group.
 */
-func (csu *ClientSideUser) SendMlsGroupMessage(groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) error {
-	messageWrapper, err := csu.GenerateMlsGroupMessageCipherText(groupID, messageRaw, messageType, receivingChatbotIDs, hideTrigger)
-	if err != nil {
+func (csu *ClientSideUser) SendMlsGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) error {
+	if _, err := csu.Client.GetMlsGroupSessionDriver(groupID); err != nil {
 		logger.Error(err)
 		return err
 	}
 
-	return csu.Client.SendMlsGroupMessage(groupID, messageWrapper)
+	entry := &pb.StoredOutboxEntry{
+		GroupID:             groupID,
+		GroupType:           pb.GroupType_MLS,
+		Plaintext:           messageRaw,
+		MessageType:         messageType,
+		ReceivingChatbotIDs: receivingChatbotIDs,
+		HideTrigger:         hideTrigger,
+	}
+	return csu.sendGroupMessageWithKeyUpdate(ctx, entry, func() (*pb.MessageWrapper, error) {
+		return csu.GenerateMlsGroupMessageCipherText(groupID, messageRaw, messageType, receivingChatbotIDs, hideTrigger)
+	})
 }
 
 /*
@@ -190,8 +229,14 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 		return nil, err
 	}
 
+	// Only chatbots whose scopes allow them to read the message are encrypted for.
+	receivingChatbotIDs = csu.FilterChatbotsByScope(groupID, receivingChatbotIDs, messageRaw, messageType)
+
+	// Every ciphertext of the message is bound to its MessageID.
+	messageID := client.NewMessageID()
+
 	// Encrypt message for user (note the updated signature to include receivingChatbotIDs)
-	ct, commit, err := sessionDriver.EncryptMessage(messageRaw, messageType, receivingChatbotIDs)
+	ct, commit, err := sessionDriver.EncryptMessage(messageRaw, messageType, messageID, receivingChatbotIDs)
 	if err != nil {
 		logger.Error("Failed to encrypt message: ", err)
 		return nil, err
@@ -253,27 +298,33 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 		var newTreeKemRootPubKey []byte
 		var newTreeKemRootSignPubKey []byte
 
+		// The chatbots authenticate the update with the roots they share with the group before it.
+		updateMACKeys := sessionDriver.GetMlsMultiTree().GetUpdateMACKeys(sessionDriver.GetGroupChatbots())
+
 		chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err = sessionDriver.GenerateMlsMultiTreeKeyUpdate(receivingChatbotIDs)
 		if err != nil {
 			logger.Error("Failed to generate MlsMultiTree key update: ", err)
 			return nil, err
 		}
 
-		// If hiding triggers, add fake update ciphertexts for IGA/pseudonymous chatbots not in the original list.
+		// If hiding triggers, add fake update ciphertexts for chatbots not in the original list.
 		if hideTrigger {
 			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if (sessionDriver.GetChatbotIsIGA(chatbotID) || sessionDriver.GetChatbotIsPseudo(chatbotID)) && !util.ContainString(chatbotID, receivingChatbotIDs) {
-					chatbotUpdateCiphertexts[chatbotID] = treekem.ECKEMCipherText{
-						CipherText: util.RandomBytes(32),
-						IV:         util.RandomBytes(16),
-						Public:     util.RandomBytes(32),
+				if !util.ContainString(chatbotID, receivingChatbotIDs) {
+					chatbotUpdateCiphertexts[chatbotID], err = treekem.ECKEMEncryptDummy(sessionDriver.GetCipherSuite(), sha256.Size)
+					if err != nil {
+						logger.Error("Failed to generate dummy chatbot update ciphertext: ", err)
+						return nil, err
 					}
 				}
 			}
 		}
 
 		// Encrypt the message (using MLS MultiTree) for IGA/pseudonymous chatbots.
-		cipherTextForIGAChatbot := sessionDriver.EncryptMessageByMlsMultiTreeRoot(messageRaw, messageType, exampleChatbotID, nil)
+		cipherTextForIGAChatbot, err := sessionDriver.EncryptMessageByMlsMultiTreeRoot(messageRaw, messageType, messageID, exampleChatbotID, nil)
+		if err != nil {
+			return nil, err
+		}
 
 		// Prepare the TreeKEMKeyUpdatePack
 		treeKEMKeyUpdatePackChatbot = &pb.TreeKEMKeyUpdatePack{
@@ -281,6 +332,16 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 			NewRootPubKey:            newTreeKemRootPubKey,
 			NewRootSignPubKey:        newTreeKemRootSignPubKey,
 		}
+		// Every chatbot the pack is sent to gets an entry that looks the same, so that the entries do not tell who the
+		// message triggers.
+		treeKEMKeyUpdatePackChatbot.ChatbotEpochs, treeKEMKeyUpdatePackChatbot.ChatbotTranscriptHashes, err = sessionDriver.GetMlsMultiTree().GetHiddenEpochs(receivingChatbotIDs, actuallySentChatbotIDs)
+		if err != nil {
+			return nil, err
+		}
+		err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePackChatbot, updateMACKeys)
+		if err != nil {
+			return nil, err
+		}
 
 		// Create chatbot messages for all chatbots in actuallySentChatbotIDs
 		for _, chatbotID := range actuallySentChatbotIDs {
@@ -296,7 +357,7 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 					}
 					// For a pseudonymous user, sign the ciphertext.
 					senderID = pseudoUser.PseudoUserID
-					sig, err := util.Sign(cipherTextForIGAChatbot.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+					sig, err := sessionDriver.GetCipherSuite().Sign(cipherTextForIGAChatbot.CipherText, pseudoUser.SigningKeyPair.Private)
 					if err != nil {
 						logger.Error("Failed to sign message: ", err)
 						return nil, err
@@ -324,6 +385,7 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 					IsPseudo:             sessionDriver.GetChatbotIsPseudo(chatbotID),
 					TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
 					MlsCommit:            serializedCommit,
+					MessageID:            messageID,
 				}
 				chatbotMessage := &pb.ChatbotMessage{
 					ChatbotID:        chatbotID,
@@ -334,71 +396,36 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 			}
 		}
 
-		// Handle non-IGA/Pseudonymous chatbots if present in the original list.
-		var nonIGAchatbots []string
-		for _, chatbotID := range receivingChatbotIDs {
-			if !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
-				nonIGAchatbots = append(nonIGAchatbots, chatbotID)
-			}
-		}
-		if len(nonIGAchatbots) > 0 {
-			// For non-IGA chatbots, we create a separate message wrapper using the user ciphertext.
-			chatbotMessageWrapper := &pb.MessageWrapper{
-				SenderID:             csu.userID,
-				RecipientID:          groupID,
-				EncryptedMessage:     serializedCipherText,
-				HasPreKey:            false,
-				ChatbotIds:           receivingChatbotIDs,
-				IsIGA:                false,
-				IsPseudo:             false,
-				TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
-				MlsCommit:            serializedCommit,
-			}
-			for _, chatbotID := range nonIGAchatbots {
-				chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-					ChatbotID:        chatbotID,
-					MessageWrapper:   chatbotMessageWrapper,
-					UseNormalMessage: false,
-				})
-			}
-		}
-
-		// Handle non-IGA/Pseudonymous chatbots that did not receive the real message (when hideTrigger is true).
 		if hideTrigger {
-			var nonIGAnonReceivingChatbots []string
-			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if !util.ContainString(chatbotID, receivingChatbotIDs) && !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
-					nonIGAnonReceivingChatbots = append(nonIGAnonReceivingChatbots, chatbotID)
-				}
+			// Every non-IGA/Pseudonymous chatbot receives a sealed message along with the commit, whether it is triggered or not.
+			hiddenTriggerChatbotMessages, err := csu.generateMlsHiddenTriggerChatbotMessages(groupID, sessionDriver, messageRaw, messageType, messageID, receivingChatbotIDs, treeKEMKeyUpdatePackChatbot, serializedCommit)
+			if err != nil {
+				return nil, err
 			}
-			if len(nonIGAnonReceivingChatbots) > 0 {
-				// Create a dummy ciphertext for these chatbots.
-				dummyMessage := []byte(util.RandomString(len(messageRaw)))
-				dummyCT, dummyCommit, err := sessionDriver.EncryptMessage(dummyMessage, pb.MessageType_SKIP, receivingChatbotIDs)
-				if err != nil {
-					logger.Error("Failed to encrypt dummy message for hiding trigger: ", err)
-					return nil, err
-				}
-				serializedDummyCT, err := util.SerializeMLSCiphertext(*dummyCT)
-				if err != nil {
-					return nil, err
-				}
-				dummyCommitSerialized, err := syntax.Marshal(dummyCommit)
-				if err != nil {
-					return nil, err
+			chatbotMessages = append(chatbotMessages, hiddenTriggerChatbotMessages...)
+		} else {
+			// Handle non-IGA/Pseudonymous chatbots if present in the original list.
+			var nonIGAchatbots []string
+			for _, chatbotID := range receivingChatbotIDs {
+				if !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
+					nonIGAchatbots = append(nonIGAchatbots, chatbotID)
 				}
+			}
+			if len(nonIGAchatbots) > 0 {
+				// For non-IGA chatbots, we create a separate message wrapper using the user ciphertext.
 				chatbotMessageWrapper := &pb.MessageWrapper{
-					SenderID:         csu.userID,
-					RecipientID:      groupID,
-					EncryptedMessage: serializedDummyCT,
-					HasPreKey:        false,
-					ChatbotIds:       receivingChatbotIDs,
-					IsIGA:            false,
-					IsPseudo:         false,
-					// No TreeKEM update needed for non-IGA dummy message.
-					MlsCommit: dummyCommitSerialized,
+					SenderID:             csu.userID,
+					RecipientID:          groupID,
+					EncryptedMessage:     serializedCipherText,
+					HasPreKey:            false,
+					ChatbotIds:           receivingChatbotIDs,
+					IsIGA:                false,
+					IsPseudo:             false,
+					TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
+					MlsCommit:            serializedCommit,
+					MessageID:            messageID,
 				}
-				for _, chatbotID := range nonIGAnonReceivingChatbots {
+				for _, chatbotID := range nonIGAchatbots {
 					chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
 						ChatbotID:        chatbotID,
 						MessageWrapper:   chatbotMessageWrapper,
@@ -407,56 +434,31 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 				}
 			}
 		}
-
 	} else {
-		// If there is no IGA/Pseudonymous chatbot, simply prepare chatbot messages for each receiving chatbot.
-		chatbotMessages = make([]*pb.ChatbotMessage, 0)
-		for _, chatbotID := range receivingChatbotIDs {
-			chatbotMessageWrapper := &pb.MessageWrapper{
-				SenderID:         csu.userID,
-				RecipientID:      groupID,
-				EncryptedMessage: serializedCipherText,
-				ChatbotIds:       []string{chatbotID},
-				// No MultiTree update for non-IGA chatbots.
-				MlsCommit: serializedCommit,
-			}
-			chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-				ChatbotID:        chatbotID,
-				MessageWrapper:   chatbotMessageWrapper,
-				UseNormalMessage: false,
-			})
-		}
-		// For non-IGA/Pseudonymous chatbots, if hideTrigger is true, send a dummy message for those not in receivingChatbotIDs.
 		if hideTrigger {
-			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if !util.ContainString(chatbotID, receivingChatbotIDs) {
-					dummyMessage := []byte(util.RandomString(len(messageRaw)))
-					dummyCT, dummyCommit, err := sessionDriver.EncryptMessage(dummyMessage, pb.MessageType_SKIP, receivingChatbotIDs)
-					if err != nil {
-						logger.Error("Failed to encrypt dummy message for hiding trigger: ", err)
-						return nil, err
-					}
-					serializedDummyCT, err := util.SerializeMLSCiphertext(*dummyCT)
-					if err != nil {
-						return nil, err
-					}
-					dummyCommitSerialized, err := syntax.Marshal(dummyCommit)
-					if err != nil {
-						return nil, err
-					}
-					chatbotMessageWrapper := &pb.MessageWrapper{
-						SenderID:         csu.userID,
-						RecipientID:      groupID,
-						EncryptedMessage: serializedDummyCT,
-						ChatbotIds:       []string{chatbotID},
-						MlsCommit:        dummyCommitSerialized,
-					}
-					chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-						ChatbotID:        chatbotID,
-						MessageWrapper:   chatbotMessageWrapper,
-						UseNormalMessage: false,
-					})
+			// When hiding triggers, every chatbot receives a sealed message along with the commit, whether it is triggered or not.
+			chatbotMessages, err = csu.generateMlsHiddenTriggerChatbotMessages(groupID, sessionDriver, messageRaw, messageType, messageID, receivingChatbotIDs, nil, serializedCommit)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// If there is no IGA/Pseudonymous chatbot, simply prepare chatbot messages for each receiving chatbot.
+			chatbotMessages = make([]*pb.ChatbotMessage, 0)
+			for _, chatbotID := range receivingChatbotIDs {
+				chatbotMessageWrapper := &pb.MessageWrapper{
+					SenderID:         csu.userID,
+					RecipientID:      groupID,
+					EncryptedMessage: serializedCipherText,
+					ChatbotIds:       []string{chatbotID},
+					// No MultiTree update for non-IGA chatbots.
+					MlsCommit: serializedCommit,
+					MessageID: messageID,
 				}
+				chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
+					ChatbotID:        chatbotID,
+					MessageWrapper:   chatbotMessageWrapper,
+					UseNormalMessage: false,
+				})
 			}
 		}
 	}
@@ -471,21 +473,63 @@ func (csu *ClientSideUser) GenerateMlsGroupMessageCipherText(groupID string, mes
 		ChatbotIds:       actuallySentChatbotIDs,
 		// No user-level MultiTree update exists here so we only attach the MLS commit.
 		MlsCommit: serializedCommit,
+		MessageID: messageID,
 	}
 
 	logger.Info("MLs messageWrapper: ", messageWrapper)
 	return messageWrapper, nil
 }
 
+/*
+generateMlsHiddenTriggerChatbotMessages generates a sealed message for every non-IGA, non-pseudonymous chatbot in the
+group. Each of them receives the commit so that its MLS state stays in sync, but only the chatbots in
+receivingChatbotIDs are able to open the message; the server cannot tell which is which.
+*/
+func (csu *ClientSideUser) generateMlsHiddenTriggerChatbotMessages(groupID string, sessionDriver *client.MlsGroupSessionDriver, messageRaw []byte, messageType pb.MessageType, messageID string, receivingChatbotIDs []string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack, serializedCommit []byte) ([]*pb.ChatbotMessage, error) {
+	chatbotMessages := make([]*pb.ChatbotMessage, 0)
+	for _, chatbotID := range sessionDriver.GetGroupChatbots() {
+		if sessionDriver.GetChatbotIsIGA(chatbotID) || sessionDriver.GetChatbotIsPseudo(chatbotID) {
+			continue
+		}
+
+		chatbotCipherText, err := sessionDriver.EncryptMessageForHiddenTrigger(messageRaw, messageType, messageID, chatbotID, util.ContainString(chatbotID, receivingChatbotIDs), csu.Client.GetIdentityKey())
+		if err != nil {
+			logger.Error("Failed to encrypt hidden trigger message for chatbot ", chatbotID, ": ", err)
+			return nil, err
+		}
+
+		chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
+			ChatbotID: chatbotID,
+			MessageWrapper: &pb.MessageWrapper{
+				SenderID:             csu.userID,
+				RecipientID:          groupID,
+				EncryptedMessage:     chatbotCipherText,
+				HasPreKey:            false,
+				ChatbotIds:           sessionDriver.GetGroupChatbots(),
+				HiddenTrigger:        true,
+				TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+				MlsCommit:            serializedCommit,
+				MessageID:            messageID,
+			},
+			UseNormalMessage: false,
+		})
+	}
+
+	return chatbotMessages, nil
+}
+
 /*
 GetMlsChatbotEncryptedMessage returns the encrypted message for the given chatbotID.
 */
-func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool, originalCipherText []byte, originalCommit []byte) ([]*pb.ChatbotMessage, []string, error) {
+func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message []byte, messageType pb.MessageType, messageID string, receivingChatbotIDs []string, hideTrigger bool, originalCipherText []byte, originalCommit []byte) ([]*pb.ChatbotMessage, []string, error) {
 	sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Only chatbots whose scopes allow them to read the message are encrypted for.
+	receivingChatbotIDs = csu.FilterChatbotsByScope(groupID, receivingChatbotIDs, message, messageType)
+
 	// If hiding triggers is true, for chatbots not in the receivingChatbotIDs, they will still receive the message.
 	receivingChatbotIDsReal := receivingChatbotIDs
 	if hideTrigger {
@@ -500,6 +544,8 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 	var chatbotUpdateCiphertexts map[string]treekem.ECKEMCipherText
 	var newTreeKemRootPubKey []byte
 	var newTreeKemRootSignPubKey []byte
+	// The chatbots authenticate the update with the roots they share with the group before it.
+	updateMACKeys := sessionDriver.GetMlsMultiTree().GetUpdateMACKeys(receivingChatbotIDs)
 	for _, chatbotID := range receivingChatbotIDs {
 		if sessionDriver.GetChatbotIsIGA(chatbotID) {
 			chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err = sessionDriver.GenerateMlsMultiTreeKeyUpdate(receivingChatbotIDs)
@@ -533,9 +579,12 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 
 			if !sendSkip {
 				if encryptedMessageCache.CipherText == nil {
-					encryptedMessageCache = sessionDriver.EncryptMessageByMlsMultiTreeRoot(message, messageType, chatbotID, nil)
+					encryptedMessageCache, err = sessionDriver.EncryptMessageByMlsMultiTreeRoot(message, messageType, messageID, chatbotID, nil)
+					if err != nil {
+						return nil, nil, err
+					}
 				}
-				sig, err := util.Sign(encryptedMessageCache.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+				sig, err := sessionDriver.GetCipherSuite().Sign(encryptedMessageCache.CipherText, pseudoUser.SigningKeyPair.Private)
 				if err != nil {
 					logger.Error("Failed to sign message: ", err)
 					return nil, nil, err
@@ -547,9 +596,12 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 				}.Serialize()
 			} else {
 				if encryptedSkipMessageCache.CipherText == nil {
-					encryptedSkipMessageCache = sessionDriver.EncryptMessageByMlsMultiTreeRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, chatbotID, nil)
+					encryptedSkipMessageCache, err = sessionDriver.EncryptMessageByMlsMultiTreeRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, messageID, chatbotID, nil)
+					if err != nil {
+						return nil, nil, err
+					}
 				}
-				sig, err := util.Sign(encryptedSkipMessageCache.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+				sig, err := sessionDriver.GetCipherSuite().Sign(encryptedSkipMessageCache.CipherText, pseudoUser.SigningKeyPair.Private)
 				if err != nil {
 					logger.Error("Failed to sign message: ", err)
 					return nil, nil, err
@@ -564,12 +616,18 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 		} else if sessionDriver.GetChatbotIsIGA(chatbotID) {
 			if !sendSkip {
 				if encryptedMessageCache.CipherText == nil {
-					encryptedMessageCache = sessionDriver.EncryptMessageByMlsMultiTreeRoot(message, messageType, chatbotID, nil)
+					encryptedMessageCache, err = sessionDriver.EncryptMessageByMlsMultiTreeRoot(message, messageType, messageID, chatbotID, nil)
+					if err != nil {
+						return nil, nil, err
+					}
 				}
 				cipherText = encryptedMessageCache.Serialize()
 			} else {
 				if encryptedSkipMessageCache.CipherText == nil {
-					encryptedSkipMessageCache = sessionDriver.EncryptMessageByMlsMultiTreeRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, chatbotID, nil)
+					encryptedSkipMessageCache, err = sessionDriver.EncryptMessageByMlsMultiTreeRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, messageID, chatbotID, nil)
+					if err != nil {
+						return nil, nil, err
+					}
 				}
 				cipherText = encryptedSkipMessageCache.Serialize()
 			}
@@ -580,20 +638,28 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 			senderID = csu.userID
 		}
 
+		treeKEMKeyUpdatePack := &pb.TreeKEMKeyUpdatePack{
+			ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
+			NewRootPubKey:            newTreeKemRootPubKey,
+			NewRootSignPubKey:        newTreeKemRootSignPubKey,
+		}
+		treeKEMKeyUpdatePack.ChatbotEpochs, treeKEMKeyUpdatePack.ChatbotTranscriptHashes = sessionDriver.GetMlsMultiTree().GetEpochs([]string{chatbotID})
+		err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack, updateMACKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		messageWrapper := &pb.MessageWrapper{
-			SenderID:         senderID,
-			RecipientID:      groupID,
-			EncryptedMessage: cipherText,
-			HasPreKey:        false,
-			ChatbotIds:       receivingChatbotIDs,
-			IsIGA:            sessionDriver.GetChatbotIsIGA(chatbotID),
-			IsPseudo:         sessionDriver.GetChatbotIsPseudo(chatbotID),
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
-			MlsCommit: originalCommit,
+			SenderID:             senderID,
+			RecipientID:          groupID,
+			EncryptedMessage:     cipherText,
+			HasPreKey:            false,
+			ChatbotIds:           receivingChatbotIDs,
+			IsIGA:                sessionDriver.GetChatbotIsIGA(chatbotID),
+			IsPseudo:             sessionDriver.GetChatbotIsPseudo(chatbotID),
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+			MlsCommit:            originalCommit,
+			MessageID:            messageID,
 		}
 		chatbotMessage := &pb.ChatbotMessage{
 			ChatbotID:        chatbotID,
@@ -613,7 +679,7 @@ func (csu *ClientSideUser) GetMlsChatbotEncryptedMessage(groupID string, message
 /*
 CreateAndRegisterMlsPseudonym creates a pseudonym for the given chatbotID and send it to the chatbot through the IGA channel.
 */
-func (csu *ClientSideUser) CreateAndRegisterMlsPseudonym(groupID string, chatbotID string) error {
+func (csu *ClientSideUser) CreateAndRegisterMlsPseudonym(ctx context.Context, groupID string, chatbotID string) error {
 	//logger.Error("CreateAndRegisterMlsPseudonym is not implemented yet.")
 	pseudoUserID, signingKeyPub, err := csu.CreatePseudoUser(groupID, pb.GroupType_MLS, chatbotID)
 	if err != nil {
@@ -643,26 +709,43 @@ func (csu *ClientSideUser) CreateAndRegisterMlsPseudonym(groupID string, chatbot
 		return err
 	}
 
-	// Update TreeKEM
+	// Update TreeKEM. The chatbot authenticates the update with the root it shares with the group before it.
+	updateMACKeys := sessionDriver.GetMlsMultiTree().GetUpdateMACKeys([]string{chatbotID})
 	chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err := sessionDriver.GenerateMlsMultiTreeKeyUpdate([]string{chatbotID})
+	if err != nil {
+		logger.Error("Failed to generate MlsMultiTree key update: ", err)
+		return err
+	}
+	treeKEMKeyUpdatePack := &pb.TreeKEMKeyUpdatePack{
+		ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
+		NewRootPubKey:            newTreeKemRootPubKey,
+		NewRootSignPubKey:        newTreeKemRootSignPubKey,
+	}
+	treeKEMKeyUpdatePack.ChatbotEpochs, treeKEMKeyUpdatePack.ChatbotTranscriptHashes = sessionDriver.GetMlsMultiTree().GetEpochs([]string{chatbotID})
+	err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack, updateMACKeys)
+	if err != nil {
+		return err
+	}
 
-	// Encrypt the message.
-	encryptedMessage := sessionDriver.EncryptMessageByMlsMultiTreeRoot(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, chatbotID, nil).Serialize()
+	// Encrypt the message, bound to its MessageID.
+	messageID := client.NewMessageID()
+	cipherText, err := sessionDriver.EncryptMessageByMlsMultiTreeRoot(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, messageID, chatbotID, nil)
+	if err != nil {
+		return err
+	}
+	encryptedMessage := cipherText.Serialize()
 
 	chatbotMessage := &pb.ChatbotMessage{
 		ChatbotID: chatbotID,
 		MessageWrapper: &pb.MessageWrapper{
-			SenderID:         "",
-			RecipientID:      groupID,
-			EncryptedMessage: encryptedMessage,
-			HasPreKey:        false,
-			ChatbotIds:       []string{chatbotID},
-			IsIGA:            true,
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
+			SenderID:             "",
+			RecipientID:          groupID,
+			EncryptedMessage:     encryptedMessage,
+			HasPreKey:            false,
+			ChatbotIds:           []string{chatbotID},
+			IsIGA:                true,
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+			MessageID:            messageID,
 		},
 		UseNormalMessage: false,
 	}
@@ -676,7 +759,8 @@ func (csu *ClientSideUser) CreateAndRegisterMlsPseudonym(groupID string, chatbot
 		ChatbotMessages:  []*pb.ChatbotMessage{chatbotMessage},
 		HasPreKey:        false,
 		ChatbotIds:       []string{chatbotID},
+		MessageID:        messageID,
 	}
 
-	return csu.Client.SendMlsGroupMessage(groupID, messageWrapper)
+	return csu.Client.SendMlsGroupMessage(ctx, groupID, messageWrapper)
 }
@@ -0,0 +1,237 @@
+package user
+
+import (
+	"chatbot-poc-go/pkg/client"
+	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// groupSendAttempts is how many times a group message is sent before it is left in the outbox.
+const groupSendAttempts = 3
+
+// groupSendRetryBackoff is how long a sender waits before sending a group message the first time again. The wait
+// doubles with every attempt.
+const groupSendRetryBackoff = 100 * time.Millisecond
+
+/*
+outboxEntry is a group message that may not have reached the server. rollback undoes the message in memory, and is nil
+for the entries restored from the state store, which are rolled back to their stored group state instead.
+*/
+type outboxEntry struct {
+	stored   *pb.StoredOutboxEntry
+	rollback func()
+}
+
+/*
+deliveryUnknown checks whether sending a message failed in a way that leaves open whether the server got it: the
+connection broke or the call timed out or was canceled before the response arrived. Any other status is an answer of
+the server.
+*/
+func deliveryUnknown(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	}
+	return false
+}
+
+/*
+snapshotGroup returns a function that rolls the key update state of the group back to its current state. The session
+driver is looked up when the snapshot is taken, as rolling back an entry restored from the state store replaces it.
+*/
+func (csu *ClientSideUser) snapshotGroup(groupID string, groupType pb.GroupType) (func(), error) {
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, err
+		}
+		return sessionDriver.SnapshotKeyUpdateState(), nil
+	case pb.GroupType_MLS:
+		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, err
+		}
+		return sessionDriver.SnapshotKeyUpdateState(), nil
+	}
+	return nil, fmt.Errorf("cannot snapshot a %v group", groupType)
+}
+
+/*
+sendIssuedGroupMessage sends an issued group message, and sends it again a few times if it is not known whether the
+server got it. The caller must hold the mutex, so that no incoming message is handled in between.
+*/
+func (csu *ClientSideUser) sendIssuedGroupMessage(ctx context.Context, groupID string, groupType pb.GroupType, messageWrapper *pb.MessageWrapper) error {
+	var err error
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		err = csu.Client.SendServerSideGroupMessage(ctx, groupID, messageWrapper)
+	case pb.GroupType_MLS:
+		err = csu.Client.SendMlsGroupMessage(ctx, groupID, messageWrapper)
+	default:
+		return fmt.Errorf("cannot send a message to a %v group", groupType)
+	}
+
+	for attempt := 1; attempt < groupSendAttempts && deliveryUnknown(err); attempt++ {
+		logger.Warning("Failed to send message ", messageWrapper.GetMessageID(), " to group ", groupID, ", sending it again: ", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(groupSendRetryBackoff << (attempt - 1)):
+		}
+		err = csu.Client.ResendGroupMessage(ctx, groupID, groupType, messageWrapper)
+	}
+	return err
+}
+
+/*
+FlushOutbox sends the group messages left in the outbox again. The server does not deliver a message twice if it got
+it before. A message the server rejects is rolled back, and one that lost against a concurrent key update is issued
+again on top of it. An error wrapping ErrMessageQueued is returned if a message still cannot be sent.
+*/
+func (csu *ClientSideUser) FlushOutbox(ctx context.Context) error {
+	csu.mutex.Lock()
+	groupIDs := make([]string, 0, len(csu.outbox))
+	for groupID := range csu.outbox {
+		groupIDs = append(groupIDs, groupID)
+	}
+	csu.mutex.Unlock()
+
+	var errs []error
+	for _, groupID := range groupIDs {
+		errs = append(errs, csu.flushOutbox(ctx, groupID))
+	}
+	return errors.Join(errs...)
+}
+
+/*
+flushOutbox sends the message left in the outbox of the group again, and issues it again if it lost against a
+concurrent key update.
+*/
+func (csu *ClientSideUser) flushOutbox(ctx context.Context, groupID string) error {
+	csu.mutex.Lock()
+	reissue, err := csu.resendOutboxLocked(ctx, groupID)
+	csu.mutex.Unlock()
+	if reissue == nil {
+		return err
+	}
+	return csu.reissueOutboxEntry(ctx, reissue)
+}
+
+/*
+resendOutboxLocked sends the message left in the outbox of the group again. The message is removed from the outbox
+unless it is still not known whether the server got it. It is rolled back if the server rejected it, and returned to be
+issued again if it lost against a concurrent key update. The caller must hold the mutex.
+*/
+func (csu *ClientSideUser) resendOutboxLocked(ctx context.Context, groupID string) (*pb.StoredOutboxEntry, error) {
+	entry, exists := csu.outbox[groupID]
+	if !exists {
+		return nil, nil
+	}
+
+	err := csu.Client.ResendGroupMessage(ctx, groupID, entry.stored.GetGroupType(), entry.stored.GetMessage())
+	if deliveryUnknown(err) {
+		return nil, fmt.Errorf("%w: %w", ErrMessageQueued, err)
+	}
+
+	delete(csu.outbox, groupID)
+	defer func() {
+		if err := csu.persistLocked(); err != nil {
+			logger.Error("Failed to persist state: ", err)
+		}
+	}()
+
+	switch {
+	case err == nil:
+		csu.recordSentMessage(groupID, entry.stored.GetMessage().GetMessageID(), entry.stored.GetPlaintext(), entry.stored.GetMessageType())
+		return nil, nil
+	case errors.Is(err, ErrNotInGroup):
+		// The user left the group, so there is nothing to roll back
+		logger.Warning("Dropped message ", entry.stored.GetMessage().GetMessageID(), " in the outbox of group ", groupID, ": ", err)
+		return nil, err
+	}
+
+	csu.rollbackOutboxEntry(entry)
+	if errors.Is(err, client.ErrKeyUpdateConflict) {
+		logger.Warning("Message ", entry.stored.GetMessage().GetMessageID(), " in the outbox of group ", groupID, " lost against a concurrent key update, issuing it again")
+		return entry.stored, nil
+	}
+	logger.Error("Message ", entry.stored.GetMessage().GetMessageID(), " in the outbox of group ", groupID, " was rejected: ", err)
+	return nil, err
+}
+
+/*
+resendOutboxBeforeHandling sends the messages in the outbox of the given groups again, or of every group if none is
+given, so that an incoming message or event is handled on top of the state the server has. A message that lost against
+a concurrent key update is issued again once the incoming message is handled, as it waits for it. The caller must hold
+the mutex.
+*/
+func (csu *ClientSideUser) resendOutboxBeforeHandling(ctx context.Context, groupIDs ...string) {
+	if len(csu.outbox) == 0 {
+		return
+	}
+	if len(groupIDs) == 0 {
+		for groupID := range csu.outbox {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+
+	for _, groupID := range groupIDs {
+		reissue, err := csu.resendOutboxLocked(ctx, groupID)
+		if err != nil {
+			logger.Warning("Failed to send the message in the outbox of group ", groupID, " again: ", err)
+		}
+		if reissue == nil {
+			continue
+		}
+
+		csu.goBackground(func(ctx context.Context) {
+			if err := csu.reissueOutboxEntry(ctx, reissue); err != nil {
+				logger.Error("Failed to issue the message in the outbox of group ", reissue.GetGroupID(), " again: ", err)
+			}
+		})
+	}
+}
+
+/*
+reissueOutboxEntry issues a message that was rolled back again, on top of the current state of the group.
+*/
+func (csu *ClientSideUser) reissueOutboxEntry(ctx context.Context, stored *pb.StoredOutboxEntry) error {
+	switch stored.GetGroupType() {
+	case pb.GroupType_SERVER_SIDE:
+		return csu.SendServerSideGroupMessage(ctx, stored.GetGroupID(), stored.GetPlaintext(), stored.GetMessageType(), stored.GetReceivingChatbotIDs(), stored.GetHideTrigger())
+	case pb.GroupType_MLS:
+		return csu.SendMlsGroupMessage(ctx, stored.GetGroupID(), stored.GetPlaintext(), stored.GetMessageType(), stored.GetReceivingChatbotIDs(), stored.GetHideTrigger())
+	}
+	return fmt.Errorf("cannot issue a message to a %v group", stored.GetGroupType())
+}
+
+/*
+rollbackOutboxEntry rolls the group back to its state before the message was issued.
+*/
+func (csu *ClientSideUser) rollbackOutboxEntry(entry *outboxEntry) {
+	if entry.rollback != nil {
+		entry.rollback()
+		return
+	}
+	if entry.stored.GetRollbackGroup() == nil {
+		logger.Error("No state to roll group ", entry.stored.GetGroupID(), " back to")
+		return
+	}
+	if err := csu.Client.RestoreGroup(entry.stored.GetRollbackGroup()); err != nil {
+		logger.Error("Failed to roll group ", entry.stored.GetGroupID(), " back: ", err)
+	}
+}
@@ -0,0 +1,281 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+// ReceiptLimit is how many sent and received messages the user keeps the receipts of.
+const ReceiptLimit = 1024
+
+// ReceiptStatus is how far a message the user sent got with a member of its group.
+type ReceiptStatus int
+
+const (
+	// ReceiptSent means the member did not acknowledge the message yet.
+	ReceiptSent ReceiptStatus = iota
+	// ReceiptDelivered means the member received the message.
+	ReceiptDelivered
+	// ReceiptRead means the member read the message.
+	ReceiptRead
+)
+
+func (s ReceiptStatus) String() string {
+	switch s {
+	case ReceiptSent:
+		return "sent"
+	case ReceiptDelivered:
+		return "delivered"
+	case ReceiptRead:
+		return "read"
+	}
+	return fmt.Sprintf("ReceiptStatus(%d)", int(s))
+}
+
+/*
+sentReceipts is the receipt status of a message the user sent, per member of its group.
+*/
+type sentReceipts struct {
+	groupID  string
+	statuses map[string]ReceiptStatus
+}
+
+/*
+receiptTracker keeps the receipt status of the text messages the user sent to its groups, and the group of the text
+messages it received from members, which it may acknowledge. Only messages on the normal channel of a group are
+tracked, so that a receipt never points at a message of an IGA or pseudonymous sender.
+*/
+type receiptTracker struct {
+	// sent is the receipt status of the messages the user sent by their MessageID, oldest first in sentOrder.
+	sent      map[string]*sentReceipts
+	sentOrder []string
+	// received is the group of the messages the user received by their MessageID, oldest first in receivedOrder.
+	received      map[string]string
+	receivedOrder []string
+
+	// sendDeliveryReceipts tells whether the user acknowledges the messages it receives.
+	sendDeliveryReceipts bool
+	mutex                sync.Mutex
+}
+
+/*
+newReceiptTracker creates a receiptTracker that tracks no message yet and sends no delivery receipts.
+*/
+func newReceiptTracker() *receiptTracker {
+	return &receiptTracker{
+		sent:     make(map[string]*sentReceipts),
+		received: make(map[string]string),
+	}
+}
+
+/*
+SetDeliveryReceipts sets whether the user sends a delivery receipt to the other members of a group for every text
+message it receives from a member on the normal channel of the group. They are off by default.
+*/
+func (csu *ClientSideUser) SetDeliveryReceipts(enabled bool) {
+	csu.receipts.mutex.Lock()
+	defer csu.receipts.mutex.Unlock()
+
+	csu.receipts.sendDeliveryReceipts = enabled
+}
+
+/*
+GetMessageReceipts returns the receipt status of a text message the user sent to a group, per other member of the
+group when the message was sent. It returns false if the message is not tracked, e.g. because it was sent to a chatbot
+only or is older than the last ReceiptLimit messages.
+*/
+func (csu *ClientSideUser) GetMessageReceipts(messageID string) (map[string]ReceiptStatus, bool) {
+	csu.receipts.mutex.Lock()
+	defer csu.receipts.mutex.Unlock()
+
+	receipts, exists := csu.receipts.sent[messageID]
+	if !exists {
+		return nil, false
+	}
+	statuses := make(map[string]ReceiptStatus, len(receipts.statuses))
+	for memberID, status := range receipts.statuses {
+		statuses[memberID] = status
+	}
+	return statuses, true
+}
+
+/*
+SendReadReceipts tells the other members of the group that the user read the given messages. Only the messages the user
+received from members on the normal channel of the group are acknowledged, and the others are skipped.
+*/
+func (csu *ClientSideUser) SendReadReceipts(ctx context.Context, groupID string, messageIDs ...string) error {
+	csu.receipts.mutex.Lock()
+	acknowledged := make([]string, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		if csu.receipts.received[messageID] != groupID {
+			logger.Warning("Skipped read receipt for message ", messageID, " not received from a member of group ", groupID)
+			continue
+		}
+		acknowledged = append(acknowledged, messageID)
+	}
+	csu.receipts.mutex.Unlock()
+
+	if len(acknowledged) == 0 {
+		return nil
+	}
+	return csu.sendReceipt(ctx, groupID, pb.MessageType_READ_RECEIPT, acknowledged)
+}
+
+/*
+sendReceipt sends a receipt for the messages to the other members of the group, on its normal channel. It is sent to
+no chatbot.
+*/
+func (csu *ClientSideUser) sendReceipt(ctx context.Context, groupID string, receiptType pb.MessageType, messageIDs []string) error {
+	receipt, err := proto.Marshal(&pb.Receipt{MessageIDs: messageIDs})
+	if err != nil {
+		return err
+	}
+
+	groupType, _, err := csu.groupParticipants(groupID)
+	if err != nil {
+		return err
+	}
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		return csu.SendServerSideGroupMessage(ctx, groupID, receipt, receiptType, nil, false)
+	case pb.GroupType_CLIENT_SIDE:
+		return csu.SendClientSideGroupMessage(ctx, groupID, receipt, receiptType, nil)
+	default:
+		return csu.SendMlsGroupMessage(ctx, groupID, receipt, receiptType, nil, false)
+	}
+}
+
+/*
+groupParticipants returns the type and the participants of a group the user is in.
+*/
+func (csu *ClientSideUser) groupParticipants(groupID string) (pb.GroupType, []string, error) {
+	if sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID); err == nil {
+		return pb.GroupType_SERVER_SIDE, sessionDriver.GetGroupParticipants(), nil
+	}
+	if sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID); err == nil {
+		return pb.GroupType_CLIENT_SIDE, sessionDriver.GetGroupParticipants(), nil
+	}
+	if sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID); err == nil {
+		return pb.GroupType_MLS, sessionDriver.GetGroupParticipants(), nil
+	}
+	return 0, nil, fmt.Errorf("%w: no group session for %v", ErrNotInGroup, groupID)
+}
+
+/*
+trackSentMessage starts tracking the receipts of a text message the user sent to the group, for its other members.
+*/
+func (csu *ClientSideUser) trackSentMessage(groupID string, messageID string, messageType pb.MessageType) {
+	if messageType != pb.MessageType_TEXT_MESSAGE || messageID == "" {
+		return
+	}
+	_, participantIDs, err := csu.groupParticipants(groupID)
+	if err != nil {
+		return
+	}
+
+	receipts := &sentReceipts{groupID: groupID, statuses: make(map[string]ReceiptStatus)}
+	for _, participantID := range participantIDs {
+		if participantID != csu.userID {
+			receipts.statuses[participantID] = ReceiptSent
+		}
+	}
+
+	csu.receipts.mutex.Lock()
+	defer csu.receipts.mutex.Unlock()
+
+	csu.receipts.sent[messageID] = receipts
+	csu.receipts.sentOrder = append(csu.receipts.sentOrder, messageID)
+	if len(csu.receipts.sentOrder) > ReceiptLimit {
+		delete(csu.receipts.sent, csu.receipts.sentOrder[0])
+		csu.receipts.sentOrder = csu.receipts.sentOrder[1:]
+	}
+}
+
+/*
+handleReceipts applies the receipts among the received messages, and tracks the text messages received from members
+on the normal channel of a group, sending a delivery receipt for them if the user does so. Receipts are not output.
+*/
+func (csu *ClientSideUser) handleReceipts(output *OutputMessage) *OutputMessage {
+	if output == nil {
+		return nil
+	}
+	isReceipt := output.MessageType == pb.MessageType_DELIVERY_RECEIPT || output.MessageType == pb.MessageType_READ_RECEIPT
+	if !isReceipt && output.MessageType != pb.MessageType_TEXT_MESSAGE {
+		return output
+	}
+
+	// Only members acknowledge messages, and only the ones of the normal channel of the group.
+	fromMember := false
+	if output.Channel == pb.Channel_GROUP_CHANNEL && output.SenderID != csu.userID {
+		if _, participantIDs, err := csu.groupParticipants(output.GroupID); err == nil {
+			fromMember = util.ContainString(output.SenderID, participantIDs)
+		}
+	}
+
+	if isReceipt {
+		if !fromMember {
+			logger.Warning("Dropped receipt from ", output.SenderID, " outside the normal channel of group ", output.GroupID)
+			return nil
+		}
+		csu.applyReceipt(output)
+		return nil
+	}
+	if !fromMember {
+		return output
+	}
+
+	csu.receipts.mutex.Lock()
+	csu.receipts.received[output.MessageID] = output.GroupID
+	csu.receipts.receivedOrder = append(csu.receipts.receivedOrder, output.MessageID)
+	if len(csu.receipts.receivedOrder) > ReceiptLimit {
+		delete(csu.receipts.received, csu.receipts.receivedOrder[0])
+		csu.receipts.receivedOrder = csu.receipts.receivedOrder[1:]
+	}
+	sendDeliveryReceipts := csu.receipts.sendDeliveryReceipts
+	csu.receipts.mutex.Unlock()
+
+	if sendDeliveryReceipts {
+		// The receipt is sent once the message is handled, as it waits for the incoming messages to be handled.
+		groupID, messageID := output.GroupID, output.MessageID
+		csu.goBackground(func(ctx context.Context) {
+			if err := csu.sendReceipt(ctx, groupID, pb.MessageType_DELIVERY_RECEIPT, []string{messageID}); err != nil {
+				logger.Error("Failed to send delivery receipt for message ", messageID, ": ", err)
+			}
+		})
+	}
+	return output
+}
+
+/*
+applyReceipt raises the receipt status of the acknowledged messages the user sent to the group. A status never goes
+back, e.g. a delivery receipt arriving after the read receipt.
+*/
+func (csu *ClientSideUser) applyReceipt(output *OutputMessage) {
+	receipt := &pb.Receipt{}
+	if err := proto.Unmarshal(output.Message, receipt); err != nil {
+		logger.Warning("Dropped malformed receipt from ", output.SenderID, ": ", err)
+		return
+	}
+	status := ReceiptDelivered
+	if output.MessageType == pb.MessageType_READ_RECEIPT {
+		status = ReceiptRead
+	}
+
+	csu.receipts.mutex.Lock()
+	defer csu.receipts.mutex.Unlock()
+
+	for _, messageID := range receipt.GetMessageIDs() {
+		receipts, exists := csu.receipts.sent[messageID]
+		if !exists || receipts.groupID != output.GroupID {
+			continue
+		}
+		if current, isMember := receipts.statuses[output.SenderID]; isMember && current < status {
+			receipts.statuses[output.SenderID] = status
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/treekem"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"errors"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"go.mau.fi/libsignal/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+chatbotKeyUpdateHandler applies the key update of the root a chatbot shares with a group, moving it to the given epoch.
+*/
+type chatbotKeyUpdateHandler func(chatbotID string, chatbotUpdate treekem.ECKEMCipherText, newCbPubKey []byte, newCbSignPubKey []byte, epoch uint64, transcriptHash []byte) error
+
+/*
+handleChatbotKeyUpdatePack applies the key update of the root a chatbot shares with the group. An update ahead of the
+root is buffered until the updates before it arrive, and the buffered updates following it are applied right after it.
+A buffered update that fails to apply is discarded, as another one buffered for the same epoch may be the genuine one.
+*/
+func (csu *ClientSideUser) handleChatbotKeyUpdatePack(groupID string, chatbotID string, chatbotKeyUpdatePack *pb.MultiTreeKEMExternalKeyUpdatePack, handle chatbotKeyUpdateHandler) error {
+	pendingKeyUpdates := csu.Client.GetPendingKeyUpdates()
+	candidates := []*pb.MultiTreeKEMExternalKeyUpdatePack{chatbotKeyUpdatePack}
+	buffered := false
+	for len(candidates) > 0 {
+		chatbotKeyUpdatePack = candidates[0]
+		candidates = candidates[1:]
+
+		chatbotUpdate := treekem.PbECKEMCipherTextConvert(chatbotKeyUpdatePack.GetChatbotUpdate())
+		err := handle(chatbotID, chatbotUpdate, chatbotKeyUpdatePack.GetNewCbPubKey(), chatbotKeyUpdatePack.GetNewCbSignPubKey(), chatbotKeyUpdatePack.GetEpoch(), chatbotKeyUpdatePack.GetTranscriptHash())
+
+		var desyncErr *treekem.DesyncError
+		if errors.As(err, &desyncErr) && !desyncErr.TranscriptMismatch {
+			pendingMessageWrapper := &pb.MessageWrapper{
+				SenderID:             chatbotID,
+				RecipientID:          groupID,
+				ChatbotKeyUpdatePack: chatbotKeyUpdatePack,
+			}
+			if !pendingKeyUpdates.Add(groupID, chatbotID, desyncErr.Epoch, chatbotKeyUpdatePack.GetEpoch(), pendingMessageWrapper) {
+				return fmt.Errorf("too many key updates of chatbot %v missing: %w", chatbotID, err)
+			}
+			logger.Warning("Buffered MultiTreeKEM update from ", chatbotID, " for group ", groupID, ": ", err)
+			return nil
+		}
+		if err != nil && buffered {
+			logger.Warning("Discarded buffered MultiTreeKEM update from ", chatbotID, " for group ", groupID, ": ", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		candidates = nil
+		for _, messageWrapper := range pendingKeyUpdates.Take(groupID, chatbotID, chatbotKeyUpdatePack.GetEpoch()+1) {
+			candidates = append(candidates, messageWrapper.GetChatbotKeyUpdatePack())
+		}
+		buffered = true
+	}
+	return nil
+}
+
+/*
+HandleRootRecoveryRequest answers a chatbot that lost track of the root it shares with a group with the current root.
+The chatbot learns that this member answered, but nothing it could not have derived from the root anyway.
+*/
+func (csu *ClientSideUser) HandleRootRecoveryRequest(ctx context.Context, message []byte, chatbotID string) error {
+	rootRecoveryRequest := &pb.RootRecoveryRequest{}
+	err := proto.Unmarshal(message, rootRecoveryRequest)
+	if err != nil {
+		logger.Error("Error unmarshalling root recovery request: ", err)
+		return err
+	}
+
+	groupID := rootRecoveryRequest.GetGroupID()
+	var chatbotIDs []string
+	var getExternalNodeRecovery func(id string) (treekem.ExternalNodeRecovery, error)
+	switch rootRecoveryRequest.GetGroupType() {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Error("Received root recovery request for unknown group ", groupID)
+			return err
+		}
+		chatbotIDs, getExternalNodeRecovery = sessionDriver.GetGroupChatbots(), sessionDriver.GetMultiTreeKEM().GetExternalNodeRecovery
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Error("Received root recovery request for unknown group ", groupID)
+			return err
+		}
+		chatbotIDs, getExternalNodeRecovery = sessionDriver.GetGroupChatbots(), sessionDriver.GetMultiTreeKEM().GetExternalNodeRecovery
+	case pb.GroupType_MLS:
+		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			logger.Error("Received root recovery request for unknown group ", groupID)
+			return err
+		}
+		chatbotIDs, getExternalNodeRecovery = sessionDriver.GetGroupChatbots(), sessionDriver.GetMlsMultiTree().GetExternalNodeRecovery
+	default:
+		return fmt.Errorf("unknown group type %v", rootRecoveryRequest.GetGroupType())
+	}
+
+	// Only the chatbot itself may learn its root.
+	if !util.ContainString(chatbotID, chatbotIDs) {
+		err = fmt.Errorf("%v is not a chatbot of group %v", chatbotID, groupID)
+		logger.Error("Rejected root recovery request: ", err)
+		return err
+	}
+
+	recovery, err := getExternalNodeRecovery(chatbotID)
+	if err != nil {
+		logger.Error("Failed to get the root of chatbot ", chatbotID, " in group ", groupID, ": ", err)
+		return err
+	}
+
+	rootRecovery, err := proto.Marshal(&pb.RootRecovery{
+		GroupID:               groupID,
+		GroupType:             rootRecoveryRequest.GetGroupType(),
+		Epoch:                 recovery.Epoch,
+		TranscriptHash:        recovery.TranscriptHash,
+		RootSecret:            recovery.RootSecret,
+		TreeKemRootPubKey:     recovery.TreeKEMRootPublic,
+		TreeKemRootSignPubKey: recovery.TreeKEMRootSignPublic,
+	})
+	if err != nil {
+		logger.Error("Error marshalling root recovery: ", err)
+		return err
+	}
+
+	logger.Info("Sending root recovery of group ", groupID, " at epoch ", recovery.Epoch, " to ", chatbotID)
+	return csu.SendIndividualMessage(ctx, protocol.NewSignalAddress(chatbotID, 1), rootRecovery, pb.MessageType_ROOT_RECOVERY)
+}
@@ -0,0 +1,230 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/util"
+	"context"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"strings"
+	"unicode"
+)
+
+/*
+ParseChatbotTriggers extracts the "@name" mentions and the leading "/command" from an outgoing text message.
+Mentions are returned without the "@" and lowercased; the command keeps its "/" and is lowercased, and is empty if the
+message does not start with one. A command in the form "/command@name" also mentions name.
+*/
+func ParseChatbotTriggers(text string) ([]string, string) {
+	var mentions []string
+	var command string
+
+	for i, token := range strings.Fields(text) {
+		if i == 0 && strings.HasPrefix(token, "/") {
+			command = token
+			if at := strings.Index(token, "@"); at > 0 {
+				command = token[:at]
+				token = token[at:]
+			} else {
+				continue
+			}
+		}
+		if !strings.HasPrefix(token, "@") {
+			continue
+		}
+		name := strings.TrimRightFunc(token[1:], func(r rune) bool {
+			return unicode.IsPunct(r) && r != '_' && r != '-'
+		})
+		if name != "" {
+			mentions = append(mentions, strings.ToLower(name))
+		}
+	}
+
+	return mentions, strings.ToLower(command)
+}
+
+/*
+SetChatbotRouting records the mention names and command prefixes a chatbot registered in the given group.
+*/
+func (csu *ClientSideUser) SetChatbotRouting(groupID string, chatbotID string, chatbotRouting *pb.ChatbotRouting) {
+	if _, exists := csu.chatbotRoutings[groupID]; !exists {
+		csu.chatbotRoutings[groupID] = make(map[string]*pb.ChatbotRouting)
+	}
+	if chatbotRouting == nil {
+		chatbotRouting = &pb.ChatbotRouting{}
+	}
+	csu.chatbotRoutings[groupID][chatbotID] = chatbotRouting
+}
+
+/*
+GetChatbotRouting returns the mention names and command prefixes a chatbot registered in the given group.
+*/
+func (csu *ClientSideUser) GetChatbotRouting(groupID string, chatbotID string) *pb.ChatbotRouting {
+	if _, exists := csu.chatbotRoutings[groupID]; !exists {
+		return nil
+	}
+	return csu.chatbotRoutings[groupID][chatbotID]
+}
+
+/*
+SetSignedChatbotRouting records the routing of a chatbot in the given group once it checks that it is signed by the
+member who invited the chatbot. inviterIDs are the members who may have invited it. A routing that fails the check is
+not used, so that the chatbot is only triggered by mentions of its ID, and the error is returned.
+*/
+func (csu *ClientSideUser) SetSignedChatbotRouting(ctx context.Context, groupID string, chatbotID string, signedRouting *pb.SignedChatbotRouting, inviterIDs []string) error {
+	csu.SetChatbotRouting(groupID, chatbotID, nil)
+
+	if !util.ContainString(signedRouting.GetSignerID(), inviterIDs) {
+		return fmt.Errorf("%w: routing of %v is signed by %q, who did not invite it", ErrBadSignature, chatbotID, signedRouting.GetSignerID())
+	}
+	err := csu.Client.VerifyChatbotRouting(ctx, groupID, chatbotID, signedRouting)
+	if err != nil {
+		return err
+	}
+
+	csu.SetChatbotRouting(groupID, chatbotID, signedRouting.GetRouting())
+	csu.setChatbotInviter(groupID, chatbotID, signedRouting.GetSignerID())
+	return nil
+}
+
+/*
+RemoveChatbotRouting removes the routing metadata of a chatbot in the given group.
+*/
+func (csu *ClientSideUser) RemoveChatbotRouting(groupID string, chatbotID string) {
+	if _, exists := csu.chatbotRoutings[groupID]; !exists {
+		return
+	}
+	delete(csu.chatbotRoutings[groupID], chatbotID)
+	delete(csu.chatbotInviters[groupID], chatbotID)
+}
+
+/*
+setChatbotInviter records the member who invited a chatbot to the given group and signed its routing.
+*/
+func (csu *ClientSideUser) setChatbotInviter(groupID string, chatbotID string, inviterID string) {
+	if _, exists := csu.chatbotInviters[groupID]; !exists {
+		csu.chatbotInviters[groupID] = make(map[string]string)
+	}
+	csu.chatbotInviters[groupID][chatbotID] = inviterID
+}
+
+/*
+GetChatbotInviter returns the member who invited a chatbot to the given group, or an empty string if unknown.
+*/
+func (csu *ClientSideUser) GetChatbotInviter(groupID string, chatbotID string) string {
+	return csu.chatbotInviters[groupID][chatbotID]
+}
+
+/*
+SetGroupHideTrigger sets whether routed messages in the given group hide which chatbots are triggered.
+*/
+func (csu *ClientSideUser) SetGroupHideTrigger(groupID string, hideTrigger bool) {
+	csu.groupHideTrigger[groupID] = hideTrigger
+}
+
+/*
+GetGroupHideTrigger returns whether routed messages in the given group hide which chatbots are triggered.
+*/
+func (csu *ClientSideUser) GetGroupHideTrigger(groupID string) bool {
+	return csu.groupHideTrigger[groupID]
+}
+
+/*
+RouteGroupMessage determines the chatbots triggered by the given text message in the group. A chatbot is triggered if it
+is mentioned by one of its mention names or its chatbot ID, or if the message starts with one of its command prefixes.
+The returned chatbot IDs follow the order of the group's chatbot list. The hideTrigger setting of the group is returned as well.
+*/
+func (csu *ClientSideUser) RouteGroupMessage(groupID string, groupType pb.GroupType, messageRaw []byte) ([]string, bool, error) {
+	var chatbotIDs []string
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, false, err
+		}
+		chatbotIDs = sessionDriver.GetGroupChatbots()
+	case pb.GroupType_CLIENT_SIDE:
+		sessionDriver, err := csu.Client.GetClientSideGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, false, err
+		}
+		chatbotIDs = sessionDriver.GetGroupChatbots()
+	case pb.GroupType_MLS:
+		sessionDriver, err := csu.Client.GetMlsGroupSessionDriver(groupID)
+		if err != nil {
+			return nil, false, err
+		}
+		chatbotIDs = sessionDriver.GetGroupChatbots()
+	default:
+		return nil, false, fmt.Errorf("unknown group type %v", groupType)
+	}
+
+	mentions, command := ParseChatbotTriggers(string(messageRaw))
+
+	receivingChatbotIDs := make([]string, 0)
+	for _, chatbotID := range chatbotIDs {
+		if isChatbotTriggered(chatbotID, csu.GetChatbotRouting(groupID, chatbotID), mentions, command) {
+			receivingChatbotIDs = append(receivingChatbotIDs, chatbotID)
+		}
+	}
+
+	return receivingChatbotIDs, csu.GetGroupHideTrigger(groupID), nil
+}
+
+/*
+SendRoutedGroupMessage sends a text message to the group, delivering it to the chatbots it mentions or commands.
+Client-side groups do not support hiding the trigger, so the hideTrigger setting is ignored for them.
+*/
+func (csu *ClientSideUser) SendRoutedGroupMessage(ctx context.Context, groupID string, groupType pb.GroupType, messageRaw []byte) error {
+	receivingChatbotIDs, hideTrigger, err := csu.RouteGroupMessage(groupID, groupType, messageRaw)
+	if err != nil {
+		logger.Error("Error routing group message: ", err)
+		return err
+	}
+
+	switch groupType {
+	case pb.GroupType_SERVER_SIDE:
+		return csu.SendServerSideGroupMessage(ctx, groupID, messageRaw, pb.MessageType_TEXT_MESSAGE, receivingChatbotIDs, hideTrigger)
+	case pb.GroupType_CLIENT_SIDE:
+		return csu.SendClientSideGroupMessage(ctx, groupID, messageRaw, pb.MessageType_TEXT_MESSAGE, receivingChatbotIDs)
+	default:
+		return csu.SendMlsGroupMessage(ctx, groupID, messageRaw, pb.MessageType_TEXT_MESSAGE, receivingChatbotIDs, hideTrigger)
+	}
+}
+
+/*
+isChatbotTriggered checks whether the parsed mentions and command trigger the chatbot.
+*/
+func isChatbotTriggered(chatbotID string, chatbotRouting *pb.ChatbotRouting, mentions []string, command string) bool {
+	return isChatbotMentioned(chatbotID, chatbotRouting, mentions) || isChatbotCommanded(chatbotRouting, command)
+}
+
+/*
+isChatbotMentioned checks whether one of the parsed mentions is the chatbot ID or one of the chatbot's mention names.
+*/
+func isChatbotMentioned(chatbotID string, chatbotRouting *pb.ChatbotRouting, mentions []string) bool {
+	names := append([]string{chatbotID}, chatbotRouting.GetMentionNames()...)
+	for _, mention := range mentions {
+		for _, name := range names {
+			if mention == strings.ToLower(strings.TrimPrefix(name, "@")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+isChatbotCommanded checks whether the parsed command is one of the chatbot's command prefixes.
+*/
+func isChatbotCommanded(chatbotRouting *pb.ChatbotRouting, command string) bool {
+	if command == "" {
+		return false
+	}
+	for _, prefix := range chatbotRouting.GetCommandPrefixes() {
+		if command == strings.ToLower("/"+strings.TrimPrefix(prefix, "/")) {
+			return true
+		}
+	}
+	return false
+}
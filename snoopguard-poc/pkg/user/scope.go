@@ -0,0 +1,176 @@
+package user
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"context"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+)
+
+/*
+SetChatbotScopes records the access scopes of a chatbot in the given group.
+*/
+func (csu *ClientSideUser) SetChatbotScopes(groupID string, chatbotID string, chatbotScopes *pb.ChatbotScopes) {
+	if _, exists := csu.chatbotScopes[groupID]; !exists {
+		csu.chatbotScopes[groupID] = make(map[string]*pb.ChatbotScopes)
+	}
+	csu.chatbotScopes[groupID][chatbotID] = chatbotScopes
+}
+
+/*
+GetChatbotScopes returns the access scopes of a chatbot in the given group. A chatbot whose scopes are unknown is
+treated as having full access, which is what a chatbot invited without scopes is granted.
+*/
+func (csu *ClientSideUser) GetChatbotScopes(groupID string, chatbotID string) *pb.ChatbotScopes {
+	if chatbotScopes, exists := csu.chatbotScopes[groupID][chatbotID]; exists && chatbotScopes != nil {
+		return chatbotScopes
+	}
+	return fullChatbotScopes()
+}
+
+/*
+fullChatbotScopes returns the scopes of a chatbot invited without restrictions.
+*/
+func fullChatbotScopes() *pb.ChatbotScopes {
+	return &pb.ChatbotScopes{
+		Commands:         true,
+		Mentions:         true,
+		AllMessages:      true,
+		MembershipEvents: true,
+		MayPost:          true,
+		MayDirectMessage: true,
+	}
+}
+
+/*
+SetSignedChatbotScopes records the access scopes of a chatbot in the given group once it checks that they are signed by
+the member who invited the chatbot, and that their version is newer than the last one taken. Scopes that fail the check
+are not used, and the error is returned.
+*/
+func (csu *ClientSideUser) SetSignedChatbotScopes(ctx context.Context, groupID string, chatbotID string, signedScopes *pb.SignedChatbotScopes) error {
+	inviterID := csu.GetChatbotInviter(groupID, chatbotID)
+	if inviterID == "" || signedScopes.GetSignerID() != inviterID {
+		return fmt.Errorf("%w: scopes of %v are signed by %q, who did not invite it", ErrBadSignature, chatbotID, signedScopes.GetSignerID())
+	}
+	if version := csu.chatbotScopesVersions[groupID][chatbotID]; signedScopes.GetVersion() <= version {
+		return fmt.Errorf("%w: scopes version %v of %v is not newer than %v", ErrReplayedMessage, signedScopes.GetVersion(), chatbotID, version)
+	}
+	err := csu.Client.VerifyChatbotScopes(ctx, groupID, chatbotID, signedScopes)
+	if err != nil {
+		return err
+	}
+
+	scopes := signedScopes.GetScopes()
+	if scopes == nil {
+		scopes = fullChatbotScopes()
+	}
+	csu.SetChatbotScopes(groupID, chatbotID, scopes)
+	csu.setChatbotScopesVersion(groupID, chatbotID, signedScopes.GetVersion())
+	return nil
+}
+
+/*
+setChatbotScopesVersion records the version of the signed scopes of a chatbot in the given group.
+*/
+func (csu *ClientSideUser) setChatbotScopesVersion(groupID string, chatbotID string, version uint64) {
+	if _, exists := csu.chatbotScopesVersions[groupID]; !exists {
+		csu.chatbotScopesVersions[groupID] = make(map[string]uint64)
+	}
+	csu.chatbotScopesVersions[groupID][chatbotID] = version
+}
+
+/*
+RemoveChatbotScopes removes the access scopes of a chatbot in the given group.
+*/
+func (csu *ClientSideUser) RemoveChatbotScopes(groupID string, chatbotID string) {
+	if _, exists := csu.chatbotScopes[groupID]; !exists {
+		return
+	}
+	delete(csu.chatbotScopes[groupID], chatbotID)
+	delete(csu.chatbotScopesVersions[groupID], chatbotID)
+}
+
+/*
+FilterChatbotsByScope removes the chatbots whose scopes do not allow them to read the given message from receivingChatbotIDs.
+Only text messages are filtered; control messages such as pseudonym registrations are always delivered.
+*/
+func (csu *ClientSideUser) FilterChatbotsByScope(groupID string, receivingChatbotIDs []string, messageRaw []byte, messageType pb.MessageType) []string {
+	if messageType != pb.MessageType_TEXT_MESSAGE || len(receivingChatbotIDs) == 0 {
+		return receivingChatbotIDs
+	}
+
+	mentions, command := ParseChatbotTriggers(string(messageRaw))
+
+	allowedChatbotIDs := make([]string, 0, len(receivingChatbotIDs))
+	for _, chatbotID := range receivingChatbotIDs {
+		chatbotScopes := csu.GetChatbotScopes(groupID, chatbotID)
+		chatbotRouting := csu.GetChatbotRouting(groupID, chatbotID)
+		if chatbotScopes.GetAllMessages() ||
+			(chatbotScopes.GetMentions() && isChatbotMentioned(chatbotID, chatbotRouting, mentions)) ||
+			(chatbotScopes.GetCommands() && isChatbotCommanded(chatbotRouting, command)) {
+			allowedChatbotIDs = append(allowedChatbotIDs, chatbotID)
+		} else {
+			logger.Info("Chatbot ", chatbotID, " is not allowed to read the message in group ", groupID, " by its scopes")
+		}
+	}
+
+	return allowedChatbotIDs
+}
+
+/*
+chatbotMayPost checks whether the sender may post to the group. Senders that are not chatbots of the group may always post.
+*/
+func (csu *ClientSideUser) chatbotMayPost(groupID string, senderID string) bool {
+	return csu.GetChatbotScopes(groupID, senderID).GetMayPost()
+}
+
+/*
+chatbotMayDirectMessage checks whether the sender may send direct messages to the user. A sender that is a chatbot in
+any of the user's groups must be granted the scope by at least one of those groups.
+*/
+func (csu *ClientSideUser) chatbotMayDirectMessage(senderID string) bool {
+	isChatbot := false
+	for _, groupChatbotScopes := range csu.chatbotScopes {
+		chatbotScopes, exists := groupChatbotScopes[senderID]
+		if !exists {
+			continue
+		}
+		isChatbot = true
+		if chatbotScopes.GetMayDirectMessage() {
+			return true
+		}
+	}
+	return !isChatbot
+}
+
+/*
+RequestUpdateChatbotScopes requests to change the access scopes of a chatbot in the group. Only the member who invited
+the chatbot may change them, and signs the next version of them.
+*/
+func (csu *ClientSideUser) RequestUpdateChatbotScopes(ctx context.Context, groupID string, chatbotID string, chatbotScopes *pb.ChatbotScopes) error {
+	signedScopes, err := csu.Client.SignChatbotScopes(groupID, chatbotID, chatbotScopes, csu.chatbotScopesVersions[groupID][chatbotID]+1)
+	if err != nil {
+		logger.Error("Error signing chatbot scopes: ", err)
+		return err
+	}
+
+	res, err := csu.chatServiceClient.UpdateChatbotScopes(ctx, &pb.UpdateChatbotScopesRequest{
+		GroupID:      groupID,
+		InitiatorID:  csu.userID,
+		ChatbotID:    chatbotID,
+		Scopes:       chatbotScopes,
+		SignedScopes: signedScopes,
+	})
+	if err != nil {
+		logger.Error("Error updating chatbot scopes: ", err)
+		return err
+	}
+
+	if !res.GetSuccess() || res.GetErrorMessage() != "" {
+		logger.Error("Error updating chatbot scopes: ", res.GetErrorMessage())
+		return fmt.Errorf("%w: %v", ErrRequestRejected, res.GetErrorMessage())
+	}
+
+	logger.Info("Received response for chatbot scopes update: ", res.String())
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/treekem"
 	"chatbot-poc-go/pkg/util"
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
@@ -88,10 +91,11 @@ func (csu *ClientSideUser) PostCreateServerSideGroup(groupID string, chatbotIsIG
 }
 
 /*
-HandleServerSideGroupMessage handles the incoming server-side group message.
+HandleServerSideGroupMessage handles the incoming server-side group message. The message is returned along with the
+error if only its key update failed.
 Todo: modularize this function. It's too big now.
 */
-func (csu *ClientSideUser) HandleServerSideGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType) {
+func (csu *ClientSideUser) HandleServerSideGroupMessage(messageWrapper *pb.MessageWrapper) ([]byte, pb.MessageType, error) {
 	groupId, senderId := messageWrapper.RecipientID, messageWrapper.SenderID
 
 	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupId)
@@ -104,43 +108,57 @@ func (csu *ClientSideUser) HandleServerSideGroupMessage(messageWrapper *pb.Messa
 		msg, err := csu.Client.ParseSenderKeyMessage(messageWrapper.EncryptedMessage) // Convert to SenderKeyMessage
 		if err != nil {
 			logger.Error("Failed to decode sender key message ", err)
-			panic("")
+			return nil, -1, fmt.Errorf("%w: %w", ErrUndecryptable, err)
+		}
+		message, messageType, receivingChatbotIDs, err := sessionDriver.ParseEncryptedMessage(senderId, msg, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, err
 		}
-		message, messageType, receivingChatbotIDs := sessionDriver.ParseEncryptedMessage(senderId, msg)
 
 		logger.Info(fmt.Sprintf("Received message from %v in server-side group %v with type %v: %v", messageWrapper.SenderID, messageWrapper.RecipientID, messageType.String(), string(message)))
 
 		// Handle treekem key update. This only happens when the message comes from other users and is also intended for other chatbots.
+		var keyUpdateErr error
 		treeKEMKeyUpdatePack := messageWrapper.GetTreeKEMKeyUpdatePack()
 		if treeKEMKeyUpdatePack != nil {
 			logger.Info("Received TreeKEM update from ", senderId, " for server-side group ", groupId)
 
+			if err := csu.verifyTreeKEMKeyUpdatePack(groupId, senderId, treeKEMKeyUpdatePack); err != nil {
+				return nil, -1, err
+			}
+
 			userUpdate := treekem.PbTreeKEMUserUpdateConvert(treeKEMKeyUpdatePack.GetUserUpdate())
-			err = sessionDriver.UpdateTreeKEMUserKey(&userUpdate, receivingChatbotIDs)
-			if err != nil {
+			keyUpdateErr = sessionDriver.UpdateTreeKEMUserKey(&userUpdate, receivingChatbotIDs)
+			if keyUpdateErr != nil {
 				logger.Error("UpdateTreeKEMUserKey failed: ", groupId)
 			}
 		}
 
-		return message, messageType
+		if !csu.chatbotMayPost(groupId, senderId) {
+			logger.Warning("Dropped message from chatbot ", senderId, " without the scope to post in group ", groupId)
+			return nil, -1, keyUpdateErr
+		}
+
+		return message, messageType, keyUpdateErr
 	}
 
 	// Handle chatbot key update. This only happens when the message comes from IGA chatbot.
+	var keyUpdateErr error
 	chatbotKeyUpdatePack := messageWrapper.GetChatbotKeyUpdatePack()
 	if chatbotKeyUpdatePack != nil {
 		logger.Info("Received MultiTreeKEM update from ", senderId, " for server-side group ", groupId)
 
-		chatbotUpdate := treekem.PbECKEMCipherTextConvert(chatbotKeyUpdatePack.GetChatbotUpdate())
-		newCbPubKey := chatbotKeyUpdatePack.GetNewCbPubKey()
-		newCbSignPubKey := chatbotKeyUpdatePack.GetNewCbSignPubKey()
-		err = sessionDriver.HandleMultiTreeKEMExternalKeyUpdate(senderId, chatbotUpdate, newCbPubKey, newCbSignPubKey)
-		if err != nil {
-			logger.Error("UpdateMultiTreeKEMExternalKey failed: ", groupId, err)
+		keyUpdateErr = csu.handleChatbotKeyUpdatePack(groupId, senderId, chatbotKeyUpdatePack, sessionDriver.HandleMultiTreeKEMExternalKeyUpdate)
+		if keyUpdateErr != nil {
+			logger.Error("UpdateMultiTreeKEMExternalKey failed: ", groupId, keyUpdateErr)
 		}
 	}
 
 	if messageWrapper.GetIsIGA() {
-		message, messageType := sessionDriver.ParseEncryptedExternalIGAMessage(messageWrapper.EncryptedMessage, messageWrapper.SenderID)
+		message, messageType, err := sessionDriver.ParseEncryptedExternalIGAMessage(messageWrapper.EncryptedMessage, messageWrapper.SenderID, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, errors.Join(err, keyUpdateErr)
+		}
 		logger.Info(fmt.Sprintf("%v Received IGA message in server-side group %v from %v: %v", csu.userID, messageWrapper.RecipientID, messageWrapper.SenderID, string(message)))
 
 		// Validation message always comes from IGA channel.
@@ -148,18 +166,26 @@ func (csu *ClientSideUser) HandleServerSideGroupMessage(messageWrapper *pb.Messa
 			logger.Info("Received validation message from ", messageWrapper.SenderID, " in server-side group ", messageWrapper.RecipientID, " with content: ", string(message))
 		}
 
-		return message, messageType
+		if !csu.chatbotMayPost(groupId, senderId) {
+			logger.Warning("Dropped message from chatbot ", senderId, " without the scope to post in group ", groupId)
+			return nil, -1, keyUpdateErr
+		}
+
+		return message, messageType, keyUpdateErr
 	} else {
 		// Forward the message to the server-side group handler.
 		msg, err := csu.Client.ParseSenderKeyMessage(messageWrapper.EncryptedMessage) // Convert to SenderKeyMessage
 		if err != nil {
 			logger.Error("Failed to decode sender key message ", err)
-			panic("")
+			return nil, -1, errors.Join(fmt.Errorf("%w: %w", ErrUndecryptable, err), keyUpdateErr)
+		}
+		message, messageType, _, err := sessionDriver.ParseEncryptedMessage(senderId, msg, messageWrapper.GetMessageID())
+		if err != nil {
+			return nil, -1, errors.Join(err, keyUpdateErr)
 		}
-		message, messageType, _ := sessionDriver.ParseEncryptedMessage(senderId, msg)
 
 		logger.Info(fmt.Sprintf("Received message from %v in server-side group %v with type %v: %v", messageWrapper.SenderID, messageWrapper.RecipientID, messageType.String(), string(message)))
-		return message, messageType
+		return message, messageType, keyUpdateErr
 	}
 
 }
@@ -167,13 +193,23 @@ func (csu *ClientSideUser) HandleServerSideGroupMessage(messageWrapper *pb.Messa
 /*
 SendServerSideGroupMessage sends a message to a server-side group.
 */
-func (csu *ClientSideUser) SendServerSideGroupMessage(groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) error {
-	messageWrapper, err := csu.GenerateServerSideGroupMessageCipherText(groupID, messageRaw, messageType, receivingChatbotIDs, hideTrigger)
-	if err != nil {
+func (csu *ClientSideUser) SendServerSideGroupMessage(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) error {
+	if _, err := csu.Client.GetServerSideGroupSessionDriver(groupID); err != nil {
+		logger.Error(err)
 		return err
 	}
 
-	return csu.Client.SendServerSideGroupMessage(groupID, messageWrapper)
+	entry := &pb.StoredOutboxEntry{
+		GroupID:             groupID,
+		GroupType:           pb.GroupType_SERVER_SIDE,
+		Plaintext:           messageRaw,
+		MessageType:         messageType,
+		ReceivingChatbotIDs: receivingChatbotIDs,
+		HideTrigger:         hideTrigger,
+	}
+	return csu.sendGroupMessageWithKeyUpdate(ctx, entry, func() (*pb.MessageWrapper, error) {
+		return csu.GenerateServerSideGroupMessageCipherText(ctx, groupID, messageRaw, messageType, receivingChatbotIDs, hideTrigger)
+	})
 }
 
 /*
@@ -218,7 +254,7 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 /*
 GenerateServerSideGroupMessageCipherText generate the ciphertext for server-side group without actually sending it.
 */
-func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) (*pb.MessageWrapper, error) {
+func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(ctx context.Context, groupID string, messageRaw []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool) (*pb.MessageWrapper, error) {
 	logger.Info("Sending message to server-side group ", groupID, ": ", string(messageRaw))
 	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
@@ -226,7 +262,26 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 		return nil, err
 	}
 
-	cipherTextForUser := sessionDriver.EncryptMessageBySendingSession(messageRaw, messageType, receivingChatbotIDs).SignedSerialize()
+	// Rotate the sender key once it has been used for long enough.
+	if sessionDriver.SenderKeyRotationDue() {
+		err = csu.RotateSelfSenderKey(ctx, groupID)
+		if err != nil {
+			logger.Error("Failed to rotate sender key: ", err)
+			return nil, err
+		}
+	}
+
+	// Only chatbots whose scopes allow them to read the message are encrypted for.
+	receivingChatbotIDs = csu.FilterChatbotsByScope(groupID, receivingChatbotIDs, messageRaw, messageType)
+
+	// Every ciphertext of the message is bound to its MessageID.
+	messageID := client.NewMessageID()
+
+	groupCipherText, err := sessionDriver.EncryptMessageBySendingSession(messageRaw, messageType, messageID, receivingChatbotIDs)
+	if err != nil {
+		return nil, err
+	}
+	cipherTextForUser := groupCipherText.SignedSerialize()
 
 	var chatbotMessages []*pb.ChatbotMessage
 	var treeKEMKeyUpdatePackChatbot *pb.TreeKEMKeyUpdatePack
@@ -274,34 +329,65 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 		var newTreeKemRootPubKey []byte
 		var newTreeKemRootSignPubKey []byte
 
+		// The chatbots authenticate the update with the roots they share with the group before it.
+		updateMACKeys := sessionDriver.GetMultiTreeKEM().GetUpdateMACKeys(sessionDriver.GetGroupChatbots())
+
 		userUpdate, chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err = sessionDriver.GenerateMultiTreeKEMKeyUpdate(receivingChatbotIDs)
 		if err != nil {
 			logger.Error("Failed to generate MultiTreeKEM key update: ", err)
 			return nil, err
 		}
 
-		// When hide triggers, add fake chatbot update ciphertexts for IGA chatbots not receiving the message
+		// When hide triggers, add fake chatbot update ciphertexts for chatbots not receiving the message
 		if hideTrigger {
 			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if (sessionDriver.GetChatbotIsIGA(chatbotID) || sessionDriver.GetChatbotIsPseudo(chatbotID)) && !util.ContainString(chatbotID, receivingChatbotIDs) {
-					// Create a fake treekem.ECKEMCipherText filled with random bytes
-					chatbotUpdateCiphertexts[chatbotID] = treekem.ECKEMCipherText{
-						CipherText: util.RandomBytes(32),
-						IV:         util.RandomBytes(16),
-						Public:     util.RandomBytes(32),
+				if !util.ContainString(chatbotID, receivingChatbotIDs) {
+					// Create a fake treekem.ECKEMCipherText that encrypts a random secret to a throwaway key,
+					// so it is indistinguishable from a real update
+					chatbotUpdateCiphertexts[chatbotID], err = sessionDriver.GetMultiTreeKEM().EncryptDummyToExternalNode(chatbotID, sha256.Size)
+					if err != nil {
+						logger.Error("Failed to generate dummy chatbot update ciphertext: ", err)
+						return nil, err
 					}
 				}
 			}
 		}
 
+		// Chatbots using the hybrid KEM encrypt their next update to the hybrid key of the new root.
+		newTreeKemRootHybridPubKey, err := sessionDriver.GetTreeKEMRootHybridPublicKey()
+		if err != nil {
+			logger.Error("Failed to derive hybrid TreeKEM root key: ", err)
+			return nil, err
+		}
+
 		// Encrypt the message using MultiTreeKEM
-		cipherTextForIGAChatbot := sessionDriver.EncryptMessageByMultiTreeKEMRoot(messageRaw, messageType, nil, exampleChatbotId, nil)
+		cipherTextForIGAChatbot, err := sessionDriver.EncryptMessageByMultiTreeKEMRoot(messageRaw, messageType, messageID, nil, exampleChatbotId, nil)
+		if err != nil {
+			return nil, err
+		}
 
 		// Prepare TreeKEMKeyUpdatePack
 		treeKEMKeyUpdatePackChatbot = &pb.TreeKEMKeyUpdatePack{
 			ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(chatbotUpdateCiphertexts),
 			NewRootPubKey:            newTreeKemRootPubKey,
 			NewRootSignPubKey:        newTreeKemRootSignPubKey,
+			NewRootHybridPubKey:      newTreeKemRootHybridPubKey,
+		}
+		// Every chatbot the pack is sent to gets an entry that looks the same, so that the entries do not tell who the
+		// message triggers.
+		treeKEMKeyUpdatePackChatbot.ChatbotEpochs, treeKEMKeyUpdatePackChatbot.ChatbotTranscriptHashes, err = sessionDriver.GetMultiTreeKEM().GetHiddenEpochs(receivingChatbotIDs, actuallySentChatbotIDs)
+		if err != nil {
+			return nil, err
+		}
+		err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePackChatbot, updateMACKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		// Chatbots without IGA know the sender, so they get the pack signed as well.
+		signedTreeKEMKeyUpdatePackChatbot, err := csu.signTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePackChatbot)
+		if err != nil {
+			return nil, err
 		}
 
 		// Create ChatbotMessages with the same ciphertext
@@ -318,7 +404,7 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 					}
 
 					senderID = pseudoUser.PseudoUserID
-					sig, err := util.Sign(cipherTextForIGAChatbot.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+					sig, err := sessionDriver.GetCipherSuite().Sign(cipherTextForIGAChatbot.CipherText, pseudoUser.SigningKeyPair.Private)
 
 					if err != nil {
 						logger.Error("Failed to sign message: ", err)
@@ -346,6 +432,7 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 					IsIGA:                sessionDriver.GetChatbotIsIGA(chatbotID),
 					IsPseudo:             sessionDriver.GetChatbotIsPseudo(chatbotID),
 					TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
+					MessageID:            messageID,
 				}
 				chatbotMessage := &pb.ChatbotMessage{
 					ChatbotID:        chatbotID,
@@ -356,67 +443,38 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 			}
 		}
 
-		// Handle non-IGA/Pseudonymous chatbots if any
-		nonIGAChatbots := []string{}
-		for _, chatbotID := range receivingChatbotIDs {
-			if !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) && util.ContainString(chatbotID, receivingChatbotIDs) {
-				nonIGAChatbots = append(nonIGAChatbots, chatbotID)
-			}
-		}
-
-		if len(nonIGAChatbots) > 0 {
-			// Create a MessageWrapper for non-IGA/Pseudonymous chatbots
-			chatbotMessageWrapper := &pb.MessageWrapper{
-				SenderID:             csu.userID,
-				RecipientID:          groupID,
-				EncryptedMessage:     cipherTextForUser,
-				HasPreKey:            false,
-				ChatbotIds:           receivingChatbotIDs,
-				IsIGA:                false,
-				IsPseudo:             false,
-				TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
-			}
-
-			// Add to chatbotMessages
-			for _, chatbotID := range nonIGAChatbots {
-				chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-					ChatbotID:        chatbotID,
-					MessageWrapper:   chatbotMessageWrapper,
-					UseNormalMessage: false,
-				})
-			}
-		}
-
-		// Handle non-IGA/Pseudonymous skipped chatbots if any
-		// Non-IGA/Pseudonymous chatbots cannot hide triggers, according to our paper.
-		// For compatibility, we send a dummy message instead of the real message. This is not intended to be a secure solution.
 		if hideTrigger {
-			nonIGAnonReceivingChatbots := []string{}
-			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if !util.ContainString(chatbotID, receivingChatbotIDs) && !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
-					nonIGAnonReceivingChatbots = append(nonIGAnonReceivingChatbots, chatbotID)
+			// Every non-IGA/Pseudonymous chatbot receives a sealed message, whether it is triggered or not
+			hiddenTriggerChatbotMessages, err := csu.generateServerSideHiddenTriggerChatbotMessages(groupID, sessionDriver, messageRaw, messageType, messageID, receivingChatbotIDs, signedTreeKEMKeyUpdatePackChatbot)
+			if err != nil {
+				return nil, err
+			}
+			chatbotMessages = append(chatbotMessages, hiddenTriggerChatbotMessages...)
+		} else {
+			// Handle non-IGA/Pseudonymous chatbots if any
+			nonIGAChatbots := []string{}
+			for _, chatbotID := range receivingChatbotIDs {
+				if !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
+					nonIGAChatbots = append(nonIGAChatbots, chatbotID)
 				}
 			}
 
-			if len(nonIGAnonReceivingChatbots) > 0 {
-				// Encrypt message for users
-				dummyMessage := []byte(util.RandomString(len(messageRaw)))
-				chatbotCipherText := sessionDriver.EncryptMessageBySendingSession(dummyMessage, pb.MessageType_SKIP, nil).SignedSerialize()
-
+			if len(nonIGAChatbots) > 0 {
 				// Create a MessageWrapper for non-IGA/Pseudonymous chatbots
 				chatbotMessageWrapper := &pb.MessageWrapper{
 					SenderID:             csu.userID,
 					RecipientID:          groupID,
-					EncryptedMessage:     chatbotCipherText,
+					EncryptedMessage:     cipherTextForUser,
 					HasPreKey:            false,
 					ChatbotIds:           receivingChatbotIDs,
 					IsIGA:                false,
 					IsPseudo:             false,
-					TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
+					TreeKEMKeyUpdatePack: signedTreeKEMKeyUpdatePackChatbot,
+					MessageID:            messageID,
 				}
 
 				// Add to chatbotMessages
-				for _, chatbotID := range nonIGAnonReceivingChatbots {
+				for _, chatbotID := range nonIGAChatbots {
 					chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
 						ChatbotID:        chatbotID,
 						MessageWrapper:   chatbotMessageWrapper,
@@ -426,52 +484,41 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 			}
 		}
 	} else {
-		// Encrypt for chatbots without IGA or pseudonymity
-		chatbotMessages = make([]*pb.ChatbotMessage, 0)
-		for _, chatbotID := range receivingChatbotIDs {
-			chatbotMessageWrapper := &pb.MessageWrapper{
-				SenderID:         csu.userID,
-				RecipientID:      groupID,
-				EncryptedMessage: cipherTextForUser,
-				ChatbotIds:       []string{chatbotID},
-				// No TreeKEM update for non-IGA chatbots
-			}
-
-			chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-				ChatbotID:        chatbotID,
-				MessageWrapper:   chatbotMessageWrapper,
-				UseNormalMessage: false,
-			})
-		}
-
-		// Again, chatbot without IGA/Pseudonymity cannot hide triggers.
-		// For compatibility, we send a dummy message instead of the real message.
 		if hideTrigger {
-			for _, chatbotID := range sessionDriver.GetGroupChatbots() {
-				if !util.ContainString(chatbotID, receivingChatbotIDs) {
-					dummyMessage := []byte(util.RandomString(len(messageRaw)))
-					chatbotCipherText := sessionDriver.EncryptMessageBySendingSession(dummyMessage, pb.MessageType_SKIP, nil).SignedSerialize()
-					chatbotMessageWrapper := &pb.MessageWrapper{
-						SenderID:         csu.userID,
-						RecipientID:      groupID,
-						EncryptedMessage: chatbotCipherText,
-						ChatbotIds:       []string{chatbotID},
-						// No TreeKEM update for non-IGA chatbots
-					}
-					chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
-						ChatbotID:        chatbotID,
-						MessageWrapper:   chatbotMessageWrapper,
-						UseNormalMessage: false,
-					})
+			// When hiding triggers, every chatbot receives a sealed message, whether it is triggered or not.
+			chatbotMessages, err = csu.generateServerSideHiddenTriggerChatbotMessages(groupID, sessionDriver, messageRaw, messageType, messageID, receivingChatbotIDs, nil)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Encrypt for chatbots without IGA or pseudonymity
+			chatbotMessages = make([]*pb.ChatbotMessage, 0)
+			for _, chatbotID := range receivingChatbotIDs {
+				chatbotMessageWrapper := &pb.MessageWrapper{
+					SenderID:         csu.userID,
+					RecipientID:      groupID,
+					EncryptedMessage: cipherTextForUser,
+					ChatbotIds:       []string{chatbotID},
+					// No TreeKEM update for non-IGA chatbots
+					MessageID: messageID,
 				}
+
+				chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
+					ChatbotID:        chatbotID,
+					MessageWrapper:   chatbotMessageWrapper,
+					UseNormalMessage: false,
+				})
 			}
 		}
 	}
 
 	var treeKEMKeyUpdatePackUser *pb.TreeKEMKeyUpdatePack
 	if userUpdate != nil {
-		treeKEMKeyUpdatePackUser = &pb.TreeKEMKeyUpdatePack{
+		treeKEMKeyUpdatePackUser, err = csu.signTreeKEMKeyUpdatePack(groupID, &pb.TreeKEMKeyUpdatePack{
 			UserUpdate: treekem.TreeKEMUserUpdatePbConvert(userUpdate),
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -484,6 +531,7 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 		HasPreKey:            false,
 		ChatbotIds:           actuallySentChatbotIDs,
 		TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackUser,
+		MessageID:            messageID,
 	}
 
 	logger.Info("messageWrapper: ", messageWrapper)
@@ -491,15 +539,55 @@ func (csu *ClientSideUser) GenerateServerSideGroupMessageCipherText(groupID stri
 	return messageWrapper, nil
 }
 
+/*
+generateServerSideHiddenTriggerChatbotMessages generates a sealed message for every non-IGA, non-pseudonymous chatbot
+in the group. Only the chatbots in receivingChatbotIDs are able to open theirs; the others learn that the message was
+not for them, and the server cannot tell which is which.
+*/
+func (csu *ClientSideUser) generateServerSideHiddenTriggerChatbotMessages(groupID string, sessionDriver *client.ServerSideGroupSessionDriver, messageRaw []byte, messageType pb.MessageType, messageID string, receivingChatbotIDs []string, treeKEMKeyUpdatePack *pb.TreeKEMKeyUpdatePack) ([]*pb.ChatbotMessage, error) {
+	chatbotMessages := make([]*pb.ChatbotMessage, 0)
+	for _, chatbotID := range sessionDriver.GetGroupChatbots() {
+		if sessionDriver.GetChatbotIsIGA(chatbotID) || sessionDriver.GetChatbotIsPseudo(chatbotID) {
+			continue
+		}
+
+		chatbotCipherText, err := sessionDriver.EncryptMessageForHiddenTrigger(messageRaw, messageType, messageID, chatbotID, util.ContainString(chatbotID, receivingChatbotIDs), csu.Client.GetIdentityKey())
+		if err != nil {
+			logger.Error("Failed to encrypt hidden trigger message for chatbot ", chatbotID, ": ", err)
+			return nil, err
+		}
+
+		chatbotMessages = append(chatbotMessages, &pb.ChatbotMessage{
+			ChatbotID: chatbotID,
+			MessageWrapper: &pb.MessageWrapper{
+				SenderID:             csu.userID,
+				RecipientID:          groupID,
+				EncryptedMessage:     chatbotCipherText,
+				HasPreKey:            false,
+				ChatbotIds:           sessionDriver.GetGroupChatbots(),
+				HiddenTrigger:        true,
+				TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+				MessageID:            messageID,
+			},
+			UseNormalMessage: false,
+		})
+	}
+
+	return chatbotMessages, nil
+}
+
 /*
 GetServerSideChatbotEncryptedMessage returns the encrypted message for the given chatbotID.
 */
-func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string, message []byte, messageType pb.MessageType, receivingChatbotIDs []string, hideTrigger bool, originalCipherText []byte) ([]*pb.ChatbotMessage, *treekem.UserUpdate, []string, error) {
+func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string, message []byte, messageType pb.MessageType, messageID string, receivingChatbotIDs []string, hideTrigger bool, originalCipherText []byte) ([]*pb.ChatbotMessage, *treekem.UserUpdate, []string, error) {
 	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	// Only chatbots whose scopes allow them to read the message are encrypted for.
+	receivingChatbotIDs = csu.FilterChatbotsByScope(groupID, receivingChatbotIDs, message, messageType)
+
 	// If hiding triggers is true, for chatbots not in the receivingChatbotIDs, they will still receive the message.
 	receivingChatbotIDsReal := receivingChatbotIDs
 	if hideTrigger {
@@ -516,6 +604,9 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 	var newTreeKemRootPubKey []byte
 	var newTreeKemRootSignPubKey []byte
 
+	// The chatbots authenticate the update with the roots they share with the group before it.
+	updateMACKeys := sessionDriver.GetMultiTreeKEM().GetUpdateMACKeys(receivingChatbotIDs)
+
 	for _, chatbotID := range receivingChatbotIDs {
 		if sessionDriver.GetChatbotIsIGA(chatbotID) {
 			userUpdate, chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err = sessionDriver.GenerateMultiTreeKEMKeyUpdate(receivingChatbotIDs)
@@ -525,6 +616,10 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 			break
 		}
 	}
+	newTreeKemRootHybridPubKey, err := sessionDriver.GetTreeKEMRootHybridPublicKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	chatbotMessages := make([]*pb.ChatbotMessage, 0)
 	var encryptedMessageCache util.CipherText
@@ -549,9 +644,12 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 
 			if !sendSkip {
 				if encryptedMessageCache.CipherText == nil {
-					encryptedMessageCache = sessionDriver.EncryptMessageByMultiTreeKEMRoot(message, messageType, nil, chatbotID, nil)
+					encryptedMessageCache, err = sessionDriver.EncryptMessageByMultiTreeKEMRoot(message, messageType, messageID, nil, chatbotID, nil)
+					if err != nil {
+						return nil, nil, nil, err
+					}
 				}
-				sig, err := util.Sign(encryptedMessageCache.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+				sig, err := sessionDriver.GetCipherSuite().Sign(encryptedMessageCache.CipherText, pseudoUser.SigningKeyPair.Private)
 				if err != nil {
 					logger.Error("Failed to sign message: ", err)
 					return nil, nil, nil, err
@@ -563,9 +661,12 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 				}.Serialize()
 			} else {
 				if encryptedSkipMessageCache.CipherText == nil {
-					encryptedSkipMessageCache = sessionDriver.EncryptMessageByMultiTreeKEMRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, nil, chatbotID, nil)
+					encryptedSkipMessageCache, err = sessionDriver.EncryptMessageByMultiTreeKEMRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, messageID, nil, chatbotID, nil)
+					if err != nil {
+						return nil, nil, nil, err
+					}
 				}
-				sig, err := util.Sign(encryptedSkipMessageCache.CipherText, pseudoUser.SigningKeyPair.Private.Bytes())
+				sig, err := sessionDriver.GetCipherSuite().Sign(encryptedSkipMessageCache.CipherText, pseudoUser.SigningKeyPair.Private)
 				if err != nil {
 					logger.Error("Failed to sign message: ", err)
 					return nil, nil, nil, err
@@ -581,12 +682,18 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 		} else if sessionDriver.GetChatbotIsIGA(chatbotID) {
 			if !sendSkip {
 				if encryptedMessageCache.CipherText == nil {
-					encryptedMessageCache = sessionDriver.EncryptMessageByMultiTreeKEMRoot(message, messageType, nil, chatbotID, nil)
+					encryptedMessageCache, err = sessionDriver.EncryptMessageByMultiTreeKEMRoot(message, messageType, messageID, nil, chatbotID, nil)
+					if err != nil {
+						return nil, nil, nil, err
+					}
 				}
 				ct = encryptedMessageCache.Serialize()
 			} else {
 				if encryptedSkipMessageCache.CipherText == nil {
-					encryptedSkipMessageCache = sessionDriver.EncryptMessageByMultiTreeKEMRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, nil, chatbotID, nil)
+					encryptedSkipMessageCache, err = sessionDriver.EncryptMessageByMultiTreeKEMRoot([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, messageID, nil, chatbotID, nil)
+					if err != nil {
+						return nil, nil, nil, err
+					}
 				}
 				ct = encryptedSkipMessageCache.Serialize()
 			}
@@ -596,25 +703,44 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 			if !sendSkip {
 				ct = originalCipherText
 			} else {
-				ct = sessionDriver.EncryptMessageBySendingSession([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, nil).SignedSerialize()
+				skipCipherText, err := sessionDriver.EncryptMessageBySendingSession([]byte(util.RandomString(len(message))), pb.MessageType_SKIP, messageID, nil)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				ct = skipCipherText.SignedSerialize()
 			}
 			senderID = csu.userID
 			hasPreKey = false
 		}
 
+		treeKEMKeyUpdatePack := &pb.TreeKEMKeyUpdatePack{
+			ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
+			NewRootPubKey:            newTreeKemRootPubKey,
+			NewRootSignPubKey:        newTreeKemRootSignPubKey,
+			NewRootHybridPubKey:      rootHybridPubKeyFor(sessionDriver.GetChatbotHybridKEM(chatbotID), newTreeKemRootHybridPubKey),
+		}
+		treeKEMKeyUpdatePack.ChatbotEpochs, treeKEMKeyUpdatePack.ChatbotTranscriptHashes = sessionDriver.GetMultiTreeKEM().GetEpochs([]string{chatbotID})
+		err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack, updateMACKeys)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !sessionDriver.GetChatbotIsIGA(chatbotID) && !sessionDriver.GetChatbotIsPseudo(chatbotID) {
+			treeKEMKeyUpdatePack, err = csu.signTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePack)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+
 		messageWrapper := &pb.MessageWrapper{
-			SenderID:         senderID,
-			RecipientID:      groupID,
-			EncryptedMessage: ct,
-			HasPreKey:        hasPreKey,
-			ChatbotIds:       receivingChatbotIDs,
-			IsIGA:            sessionDriver.GetChatbotIsIGA(chatbotID),
-			IsPseudo:         sessionDriver.GetChatbotIsPseudo(chatbotID),
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
+			SenderID:             senderID,
+			RecipientID:          groupID,
+			EncryptedMessage:     ct,
+			HasPreKey:            hasPreKey,
+			ChatbotIds:           receivingChatbotIDs,
+			IsIGA:                sessionDriver.GetChatbotIsIGA(chatbotID),
+			IsPseudo:             sessionDriver.GetChatbotIsPseudo(chatbotID),
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePack,
+			MessageID:            messageID,
 		}
 		chatbotMessage := &pb.ChatbotMessage{
 			ChatbotID:        chatbotID,
@@ -634,7 +760,7 @@ func (csu *ClientSideUser) GetServerSideChatbotEncryptedMessage(groupID string,
 /*
 DistributeSelfSenderKeyToUserID sends the own sender key to the given userID.
 */
-func (csu *ClientSideUser) DistributeSelfSenderKeyToUserID(userID string, groupID string, bounceBack bool) error {
+func (csu *ClientSideUser) DistributeSelfSenderKeyToUserID(ctx context.Context, userID string, groupID string, bounceBack bool) error {
 	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return err
@@ -651,13 +777,37 @@ func (csu *ClientSideUser) DistributeSelfSenderKeyToUserID(userID string, groupI
 		return err
 	}
 
-	return csu.SendIndividualMessage(protocol.NewSignalAddress(userID, 1), msgMarshal, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE)
+	return csu.SendIndividualMessage(ctx, protocol.NewSignalAddress(userID, 1), msgMarshal, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE)
 }
 
 /*
 DistributeSelfSenderKeyToAll sends the own sender key to all group participants.
 */
-func (csu *ClientSideUser) DistributeSelfSenderKeyToAll(groupID string) error {
+func (csu *ClientSideUser) DistributeSelfSenderKeyToAll(ctx context.Context, groupID string) error {
+	return csu.distributeSelfSenderKeyToAll(ctx, groupID, true)
+}
+
+/*
+RotateSelfSenderKey replaces the own sender key with a fresh one and distributes it to the remaining group participants,
+so that a removed participant cannot decrypt the later messages.
+*/
+func (csu *ClientSideUser) RotateSelfSenderKey(ctx context.Context, groupID string) error {
+	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Rotating sender key in group ", groupID)
+	sessionDriver.RotateSelfSenderKey()
+
+	// The others already have our sender key, so they do not need to bounce back theirs.
+	return csu.distributeSelfSenderKeyToAll(ctx, groupID, false)
+}
+
+/*
+distributeSelfSenderKeyToAll sends the own sender key to all group participants and non-IGA chatbots.
+*/
+func (csu *ClientSideUser) distributeSelfSenderKeyToAll(ctx context.Context, groupID string, bounceBack bool) error {
 	sessionDriver, err := csu.Client.GetServerSideGroupSessionDriver(groupID)
 	if err != nil {
 		return err
@@ -667,7 +817,7 @@ func (csu *ClientSideUser) DistributeSelfSenderKeyToAll(groupID string) error {
 		if userID == csu.userID {
 			continue
 		}
-		err = csu.DistributeSelfSenderKeyToUserID(userID, groupID, true)
+		err = csu.DistributeSelfSenderKeyToUserID(ctx, userID, groupID, bounceBack)
 		if err != nil {
 			logger.Error("Error sending sender key distribution message to ", userID, ": ", err)
 			return err
@@ -676,7 +826,7 @@ func (csu *ClientSideUser) DistributeSelfSenderKeyToAll(groupID string) error {
 
 	for _, chatbotID := range sessionDriver.GetGroupChatbots() {
 		if !sessionDriver.GetChatbotIsIGA(chatbotID) {
-			err = csu.DistributeSelfSenderKeyToUserID(chatbotID, groupID, true)
+			err = csu.DistributeSelfSenderKeyToUserID(ctx, chatbotID, groupID, bounceBack)
 			if err != nil {
 				logger.Error("Error sending sender key distribution message to ", chatbotID, ": ", err)
 				return err
@@ -690,7 +840,7 @@ func (csu *ClientSideUser) DistributeSelfSenderKeyToAll(groupID string) error {
 /*
 CreateAndRegisterServerSidePseudonym creates a pseudonym for the given chatbotID and send it to the chatbot through the IGA channel.
 */
-func (csu *ClientSideUser) CreateAndRegisterServerSidePseudonym(groupID string, chatbotID string) error {
+func (csu *ClientSideUser) CreateAndRegisterServerSidePseudonym(ctx context.Context, groupID string, chatbotID string) error {
 	pseudoUserID, signingKeyPub, err := csu.CreatePseudoUser(groupID, pb.GroupType_SERVER_SIDE, chatbotID)
 	if err != nil {
 		logger.Error("Failed to create pseudo user: ", err)
@@ -719,41 +869,74 @@ func (csu *ClientSideUser) CreateAndRegisterServerSidePseudonym(groupID string,
 		return err
 	}
 
-	// Update TreeKEM
+	// Update TreeKEM. The chatbot authenticates the update with the root it shares with the group before it.
+	updateMACKeys := sessionDriver.GetMultiTreeKEM().GetUpdateMACKeys([]string{chatbotID})
 	userUpdate, chatbotUpdateCiphertexts, newTreeKemRootPubKey, newTreeKemRootSignPubKey, err := sessionDriver.GenerateMultiTreeKEMKeyUpdate([]string{chatbotID})
+	if err != nil {
+		logger.Error("Failed to generate MultiTreeKEM key update: ", err)
+		return err
+	}
+	newTreeKemRootHybridPubKey, err := sessionDriver.GetTreeKEMRootHybridPublicKey()
+	if err != nil {
+		logger.Error("Failed to derive hybrid TreeKEM root key: ", err)
+		return err
+	}
 
-	// Encrypt the message.
-	encryptedMessage := sessionDriver.EncryptMessageByMultiTreeKEMRoot(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, []string{chatbotID}, chatbotID, nil).Serialize()
+	treeKEMKeyUpdatePackChatbot := &pb.TreeKEMKeyUpdatePack{
+		ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
+		NewRootPubKey:            newTreeKemRootPubKey,
+		NewRootSignPubKey:        newTreeKemRootSignPubKey,
+		NewRootHybridPubKey:      rootHybridPubKeyFor(sessionDriver.GetChatbotHybridKEM(chatbotID), newTreeKemRootHybridPubKey),
+	}
+	treeKEMKeyUpdatePackChatbot.ChatbotEpochs, treeKEMKeyUpdatePackChatbot.ChatbotTranscriptHashes = sessionDriver.GetMultiTreeKEM().GetEpochs([]string{chatbotID})
+	err = macTreeKEMKeyUpdatePack(groupID, treeKEMKeyUpdatePackChatbot, updateMACKeys)
+	if err != nil {
+		return err
+	}
+	treeKEMKeyUpdatePackUser, err := csu.signTreeKEMKeyUpdatePack(groupID, &pb.TreeKEMKeyUpdatePack{
+		UserUpdate: treekem.TreeKEMUserUpdatePbConvert(userUpdate),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Encrypt the message, bound to its MessageID.
+	messageID := client.NewMessageID()
+	cipherText, err := sessionDriver.EncryptMessageByMultiTreeKEMRoot(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, messageID, []string{chatbotID}, chatbotID, nil)
+	if err != nil {
+		return err
+	}
+	encryptedMessage := cipherText.Serialize()
+	groupCipherText, err := sessionDriver.EncryptMessageBySendingSession(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, messageID, []string{chatbotID})
+	if err != nil {
+		return err
+	}
 
 	chatbotMessage := &pb.ChatbotMessage{
 		ChatbotID: chatbotID,
 		MessageWrapper: &pb.MessageWrapper{
-			SenderID:         "",
-			RecipientID:      groupID,
-			EncryptedMessage: encryptedMessage,
-			HasPreKey:        false,
-			ChatbotIds:       []string{chatbotID},
-			IsIGA:            true,
-			TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-				ChatbotUpdateCiphertexts: treekem.ECKEMCipherTextStringMapPbConvert(map[string]treekem.ECKEMCipherText{chatbotID: chatbotUpdateCiphertexts[chatbotID]}),
-				NewRootPubKey:            newTreeKemRootPubKey,
-				NewRootSignPubKey:        newTreeKemRootSignPubKey,
-			},
+			SenderID:             "",
+			RecipientID:          groupID,
+			EncryptedMessage:     encryptedMessage,
+			HasPreKey:            false,
+			ChatbotIds:           []string{chatbotID},
+			IsIGA:                true,
+			TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackChatbot,
+			MessageID:            messageID,
 		},
 		UseNormalMessage: false,
 	}
 
 	messageWrapper := &pb.MessageWrapper{
-		SenderID:         csu.userID,
-		RecipientID:      groupID,
-		EncryptedMessage: sessionDriver.EncryptMessageBySendingSession(pseudonymRegistrationMessageMarshalled, pb.MessageType_PSEUDONYM_REGISTRATION_MESSAGE, []string{chatbotID}).SignedSerialize(),
-		ChatbotMessages:  []*pb.ChatbotMessage{chatbotMessage},
-		HasPreKey:        false,
-		ChatbotIds:       []string{chatbotID},
-		TreeKEMKeyUpdatePack: &pb.TreeKEMKeyUpdatePack{
-			UserUpdate: treekem.TreeKEMUserUpdatePbConvert(userUpdate),
-		},
+		SenderID:             csu.userID,
+		RecipientID:          groupID,
+		EncryptedMessage:     groupCipherText.SignedSerialize(),
+		ChatbotMessages:      []*pb.ChatbotMessage{chatbotMessage},
+		HasPreKey:            false,
+		ChatbotIds:           []string{chatbotID},
+		TreeKEMKeyUpdatePack: treeKEMKeyUpdatePackUser,
+		MessageID:            messageID,
 	}
 
-	return csu.Client.SendServerSideGroupMessage(groupID, messageWrapper)
+	return csu.Client.SendServerSideGroupMessage(ctx, groupID, messageWrapper)
 }
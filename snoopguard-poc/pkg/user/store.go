@@ -0,0 +1,295 @@
+package user
+
+import (
+	"chatbot-poc-go/pkg/client"
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
+	"chatbot-poc-go/pkg/treekem"
+	"context"
+	"fmt"
+	"go.mau.fi/libsignal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"net"
+)
+
+// StoredClientSideUserVersion is the version of the format Persist writes.
+const StoredClientSideUserVersion = 1
+
+/*
+SetStateStore sets the store the user persists its state to. Once set, the state is persisted after every message and
+server event the user handles, and when the user is deactivated. Messages the user sends are persisted on the next
+Persist.
+*/
+func (csu *ClientSideUser) SetStateStore(stateStore stores.StateStore) {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+
+	csu.stateStore = stateStore
+}
+
+/*
+Persist saves the state of the user to its state store, from which it is restored by RestoreClientSideUser.
+*/
+func (csu *ClientSideUser) Persist() error {
+	csu.mutex.Lock()
+	defer csu.mutex.Unlock()
+
+	return csu.persistLocked()
+}
+
+/*
+persistLocked saves the state of the user to its state store, if one is set. The caller must hold the mutex.
+*/
+func (csu *ClientSideUser) persistLocked() error {
+	if csu.stateStore == nil {
+		return nil
+	}
+
+	stored, err := csu.export()
+	if err != nil {
+		return err
+	}
+	serialized, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return csu.stateStore.Save(serialized)
+}
+
+/*
+export returns the state of the user: its client, pseudonyms, outbox and the routing and scopes of the chatbots of its
+groups.
+*/
+func (csu *ClientSideUser) export() (*pb.StoredClientSideUser, error) {
+	storedClient, err := csu.Client.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &pb.StoredClientSideUser{
+		Version:          StoredClientSideUserVersion,
+		Client:           storedClient,
+		GroupHideTrigger: csu.groupHideTrigger,
+	}
+	for groupID, pseudoUsers := range csu.pseudoUsers {
+		for chatbotID, pseudoUser := range pseudoUsers {
+			stored.PseudoUsers = append(stored.PseudoUsers, &pb.StoredPseudoUser{
+				GroupID:           groupID,
+				ChatbotID:         chatbotID,
+				PseudoUserID:      pseudoUser.PseudoUserID,
+				SigningPublicKey:  pseudoUser.SigningKeyPair.Public,
+				SigningPrivateKey: pseudoUser.SigningKeyPair.Private,
+				SignSecret:        pseudoUser.SignSecret,
+			})
+		}
+	}
+
+	settings := make(map[[2]string]*pb.StoredChatbotSettings)
+	chatbotSettings := func(groupID string, chatbotID string) *pb.StoredChatbotSettings {
+		if _, exists := settings[[2]string{groupID, chatbotID}]; !exists {
+			settings[[2]string{groupID, chatbotID}] = &pb.StoredChatbotSettings{GroupID: groupID, ChatbotID: chatbotID}
+			stored.ChatbotSettings = append(stored.ChatbotSettings, settings[[2]string{groupID, chatbotID}])
+		}
+		return settings[[2]string{groupID, chatbotID}]
+	}
+	for groupID, chatbotRoutings := range csu.chatbotRoutings {
+		for chatbotID, chatbotRouting := range chatbotRoutings {
+			chatbotSettings(groupID, chatbotID).Routing = chatbotRouting
+		}
+	}
+	for groupID, chatbotInviters := range csu.chatbotInviters {
+		for chatbotID, inviterID := range chatbotInviters {
+			chatbotSettings(groupID, chatbotID).InviterID = inviterID
+		}
+	}
+	for groupID, chatbotScopes := range csu.chatbotScopes {
+		for chatbotID, scopes := range chatbotScopes {
+			chatbotSettings(groupID, chatbotID).Scopes = scopes
+		}
+	}
+	for groupID, chatbotScopesVersions := range csu.chatbotScopesVersions {
+		for chatbotID, version := range chatbotScopesVersions {
+			chatbotSettings(groupID, chatbotID).ScopesVersion = version
+		}
+	}
+	for _, entry := range csu.outbox {
+		stored.Outbox = append(stored.Outbox, entry.stored)
+	}
+
+	return stored, nil
+}
+
+/*
+loadClientSideUser recreates a user from its state store, without connecting it to the server.
+*/
+func loadClientSideUser(stateStore stores.StateStore) (*ClientSideUser, error) {
+	serialized, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	stored := &pb.StoredClientSideUser{}
+	if err := proto.Unmarshal(serialized, stored); err != nil {
+		return nil, err
+	}
+
+	csu, err := restoreClientSideUser(stored)
+	if err != nil {
+		return nil, err
+	}
+	csu.stateStore = stateStore
+	return csu, nil
+}
+
+/*
+restoreClientSideUser recreates a user from its exported state, without connecting it to the server.
+*/
+func restoreClientSideUser(stored *pb.StoredClientSideUser) (*ClientSideUser, error) {
+	if stored.GetVersion() != StoredClientSideUserVersion {
+		return nil, fmt.Errorf("unsupported stored user version %v", stored.GetVersion())
+	}
+
+	clientObj, err := client.RestoreClient(stored.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	csu := &ClientSideUser{
+		Client:                clientObj,
+		userID:                clientObj.GetUserID(),
+		messageChan:           make(chan OutputMessage, 100),
+		errorChan:             make(chan error, 100),
+		deactivateChan:        make(chan context.Context, 1),
+		pseudoUsers:           make(map[string]map[string]*PseudoUser),
+		chatbotRoutings:       make(map[string]map[string]*pb.ChatbotRouting),
+		chatbotInviters:       make(map[string]map[string]string),
+		chatbotScopes:         make(map[string]map[string]*pb.ChatbotScopes),
+		chatbotScopesVersions: make(map[string]map[string]uint64),
+		groupHideTrigger:      make(map[string]bool),
+		pendingRejoins:        make(map[string]map[string]bool),
+		outbox:                make(map[string]*outboxEntry),
+		receipts:              newReceiptTracker(),
+		history:               stores.NewMessageHistory(),
+	}
+
+	for _, storedPseudoUser := range stored.GetPseudoUsers() {
+		if _, exists := csu.pseudoUsers[storedPseudoUser.GetGroupID()]; !exists {
+			csu.pseudoUsers[storedPseudoUser.GetGroupID()] = make(map[string]*PseudoUser)
+		}
+		csu.pseudoUsers[storedPseudoUser.GetGroupID()][storedPseudoUser.GetChatbotID()] = &PseudoUser{
+			PseudoUserID: storedPseudoUser.GetPseudoUserID(),
+			SigningKeyPair: treekem.SigningKeypair{
+				Public:  storedPseudoUser.GetSigningPublicKey(),
+				Private: storedPseudoUser.GetSigningPrivateKey(),
+			},
+			SignSecret: storedPseudoUser.GetSignSecret(),
+		}
+	}
+	for _, settings := range stored.GetChatbotSettings() {
+		if settings.GetRouting() != nil {
+			csu.SetChatbotRouting(settings.GetGroupID(), settings.GetChatbotID(), settings.GetRouting())
+		}
+		if settings.GetInviterID() != "" {
+			csu.setChatbotInviter(settings.GetGroupID(), settings.GetChatbotID(), settings.GetInviterID())
+		}
+		if settings.GetScopes() != nil {
+			csu.SetChatbotScopes(settings.GetGroupID(), settings.GetChatbotID(), settings.GetScopes())
+		}
+		if settings.GetScopesVersion() != 0 {
+			csu.setChatbotScopesVersion(settings.GetGroupID(), settings.GetChatbotID(), settings.GetScopesVersion())
+		}
+	}
+	for groupID, hideTrigger := range stored.GetGroupHideTrigger() {
+		csu.groupHideTrigger[groupID] = hideTrigger
+	}
+	for _, storedOutboxEntry := range stored.GetOutbox() {
+		csu.outbox[storedOutboxEntry.GetGroupID()] = &outboxEntry{stored: storedOutboxEntry}
+	}
+
+	return csu, nil
+}
+
+/*
+RestoreClientSideUser recreates the user persisted to the state store and connects it to the server at
+chatServiceAddress. The user keeps persisting to the same store. The close() of the connection is returned as well.
+*/
+func RestoreClientSideUser(ctx context.Context, stateStore stores.StateStore, chatServiceAddress string) (*ClientSideUser, func() error, error) {
+	csu, err := loadClientSideUser(stateStore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeChatServiceClient, err := csu.connect(ctx, chatServiceAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return csu, closeChatServiceClient, nil
+}
+
+/*
+RestoreClientSideUserBufconn recreates the user persisted to the state store and connects it to the server through the
+given dialer. The user keeps persisting to the same store.
+*/
+func RestoreClientSideUserBufconn(ctx context.Context, stateStore stores.StateStore, dialer func(context.Context, string) (net.Conn, error)) (*ClientSideUser, error) {
+	csu, err := loadClientSideUser(stateStore)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := csu.connectBufconn(ctx, dialer); err != nil {
+		return nil, err
+	}
+	return csu, nil
+}
+
+/*
+connect connects a restored user to the server at chatServiceAddress, registers it and starts listening to the
+streams. The close() of the connection is returned.
+*/
+func (csu *ClientSideUser) connect(ctx context.Context, chatServiceAddress string) (func() error, error) {
+	csu.chatServiceAddress = chatServiceAddress
+
+	closeChatServiceClient := csu.SetupChatServiceClient(chatServiceAddress)
+	csu.Client.SetChatServiceClient(&csu.chatServiceClient)
+
+	if !csu.RegisterUserToServer(ctx) {
+		closeChatServiceClient()
+		return nil, fmt.Errorf("user registration failed")
+	}
+
+	csu.startListening()
+	if err := csu.FlushOutbox(ctx); err != nil {
+		logger.Warning("Failed to flush the outbox: ", err)
+	}
+
+	return closeChatServiceClient, nil
+}
+
+/*
+connectBufconn connects a restored user to the server through the given dialer, registers it and starts listening to
+the streams.
+*/
+func (csu *ClientSideUser) connectBufconn(ctx context.Context, dialer func(context.Context, string) (net.Conn, error)) error {
+	conn, err := grpc.DialContext(ctx, "", grpc.WithInsecure(), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return err
+	}
+
+	serviceClient := pb.NewChatServiceClient(conn)
+	csu.Client.SetChatServiceClient(&serviceClient)
+	csu.chatServiceClient = serviceClient
+	csu.chatServiceConn = conn
+
+	if !csu.RegisterUserToServer(ctx) {
+		logger.Error("User registration failed")
+		conn.Close()
+		return fmt.Errorf("user registration failed")
+	}
+
+	csu.startListening()
+	if err := csu.FlushOutbox(ctx); err != nil {
+		logger.Warning("Failed to flush the outbox: ", err)
+	}
+
+	return nil
+}
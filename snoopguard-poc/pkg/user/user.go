@@ -3,62 +3,116 @@ package user
 import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
 	"chatbot-poc-go/pkg/treekem"
 	"context"
 	"go.mau.fi/libsignal/logger"
 	"google.golang.org/grpc"
 	"net"
+	"sync"
 )
 
 type ClientSideUser struct {
 	Client         *client.Client
 	userID         string
 	messageChan    chan OutputMessage
-	deactivateChan chan bool
+	errorChan      chan error
+	deactivateChan chan context.Context
+	// listenerDone is closed once the user stopped listening to the streams.
+	listenerDone chan struct{}
+	// lifecycle is cancelled once the user is deactivated, which stops the listener and the work started in the
+	// background while handling a message or event. background tracks that work.
+	lifecycle       context.Context
+	cancelLifecycle context.CancelFunc
+	background      sync.WaitGroup
+
+	// mutex keeps the group messages the user sends from interleaving with the handling of incoming messages and
+	// events, as both change the group state.
+	mutex sync.Mutex
 
 	pseudoUsers map[string]map[string]*PseudoUser
 
-	chatServiceClient    pb.ChatServiceClient
-	chatServiceClientCtx context.Context
-	chatServiceAddress   string
+	chatbotRoutings map[string]map[string]*pb.ChatbotRouting
+	chatbotInviters map[string]map[string]string
+	chatbotScopes   map[string]map[string]*pb.ChatbotScopes
+	// chatbotScopesVersions is the version of the signed scopes of each chatbot, which only grows.
+	chatbotScopesVersions map[string]map[string]uint64
+	groupHideTrigger      map[string]bool
+
+	// The users restored from a backup that asked to be re-added to each MLS group, who are re-added once their removal
+	// is handled.
+	pendingRejoins map[string]map[string]bool
+
+	// outbox keeps the group message of each group that may not have reached the server, to be sent again.
+	outbox map[string]*outboxEntry
+
+	// receipts keeps the receipt status of the group messages the user sent, and the ones it may acknowledge.
+	receipts *receiptTracker
+
+	// stateStore is where the state of the user is persisted to, if set.
+	stateStore stores.StateStore
+
+	// history keeps the text messages the user sent and received.
+	history *stores.MessageHistory
+
+	chatServiceClient  pb.ChatServiceClient
+	chatServiceConn    *grpc.ClientConn
+	closeConnOnce      sync.Once
+	chatServiceAddress string
 }
 
-func NewClientSideUser(userID string, chatServiceAddress string, setup bool) (*ClientSideUser, func() error) {
+/*
+NewClientSideUser creates a user connected to the server at chatServiceAddress, and uploads its prekeys and MLS key
+packages if setup is set. ctx bounds the registration and the uploads, but not the streams. The close() of the
+connection is returned as well, though Deactivate closes it too.
+*/
+func NewClientSideUser(ctx context.Context, userID string, chatServiceAddress string, setup bool) (*ClientSideUser, func() error) {
 	clientObj := client.NewClient(userID)
 	csu := &ClientSideUser{
-		Client:             clientObj,
-		userID:             userID,
-		messageChan:        make(chan OutputMessage, 100),
-		deactivateChan:     make(chan bool),
-		pseudoUsers:        make(map[string]map[string]*PseudoUser),
-		chatServiceAddress: chatServiceAddress,
+		Client:                clientObj,
+		userID:                userID,
+		messageChan:           make(chan OutputMessage, 100),
+		errorChan:             make(chan error, 100),
+		deactivateChan:        make(chan context.Context, 1),
+		pseudoUsers:           make(map[string]map[string]*PseudoUser),
+		chatbotRoutings:       make(map[string]map[string]*pb.ChatbotRouting),
+		chatbotInviters:       make(map[string]map[string]string),
+		chatbotScopes:         make(map[string]map[string]*pb.ChatbotScopes),
+		chatbotScopesVersions: make(map[string]map[string]uint64),
+		groupHideTrigger:      make(map[string]bool),
+		pendingRejoins:        make(map[string]map[string]bool),
+		outbox:                make(map[string]*outboxEntry),
+		receipts:              newReceiptTracker(),
+		history:               stores.NewMessageHistory(),
+		chatServiceAddress:    chatServiceAddress,
 	}
 
 	closeChatServiceClient := csu.SetupChatServiceClient(chatServiceAddress)
-	clientObj.SetChatServiceClient(&csu.chatServiceClient, &csu.chatServiceClientCtx)
+	clientObj.SetChatServiceClient(&csu.chatServiceClient)
 
-	if !csu.RegisterUserToServer() {
+	if !csu.RegisterUserToServer(ctx) {
 		logger.Error("User registration failed")
+		closeChatServiceClient()
 		return nil, nil
 	}
 
-	go csu.ListenToStreams()
+	csu.startListening()
 
 	if setup {
 		// Generate and upload prekeys
 		for i := 1; i <= 300; i = i + 1 {
 			preKeyID := clientObj.GeneratePreKey(i)
-			clientObj.UploadPreKeyByID(preKeyID)
+			clientObj.UploadPreKeyByID(ctx, preKeyID)
 		}
 
 		// Generate and upload signed prekey
 		signedPreKeyID := clientObj.GenerateSignedPreKey()
-		clientObj.UploadSignedPreKeyByID(signedPreKeyID)
+		clientObj.UploadSignedPreKeyByID(ctx, signedPreKeyID)
 
 		// Generate and upload MLS key packages
 		for i := 1; i <= 200; i = i + 1 {
 			clientObj.GenerateMLSKeyPackage(uint32(i))
-			clientObj.UploadMLSKeyPackage(uint32(i))
+			clientObj.UploadMLSKeyPackage(ctx, uint32(i))
 		}
 		logger.Info("Finish setup.")
 	}
@@ -66,50 +120,63 @@ func NewClientSideUser(userID string, chatServiceAddress string, setup bool) (*C
 	return csu, closeChatServiceClient
 }
 
-func NewClientSideUserBufconn(userID string, dialer func(context.Context, string) (net.Conn, error), setup bool) *ClientSideUser {
+/*
+NewClientSideUserBufconn creates a user connected to the server through the given dialer, like NewClientSideUser.
+*/
+func NewClientSideUserBufconn(ctx context.Context, userID string, dialer func(context.Context, string) (net.Conn, error), setup bool) *ClientSideUser {
 	clientObj := client.NewClient(userID)
 	csu := &ClientSideUser{
-		Client:             clientObj,
-		userID:             userID,
-		messageChan:        make(chan OutputMessage, 100),
-		deactivateChan:     make(chan bool),
-		pseudoUsers:        make(map[string]map[string]*PseudoUser),
-		chatServiceAddress: "",
+		Client:                clientObj,
+		userID:                userID,
+		messageChan:           make(chan OutputMessage, 100),
+		errorChan:             make(chan error, 100),
+		deactivateChan:        make(chan context.Context, 1),
+		pseudoUsers:           make(map[string]map[string]*PseudoUser),
+		chatbotRoutings:       make(map[string]map[string]*pb.ChatbotRouting),
+		chatbotInviters:       make(map[string]map[string]string),
+		chatbotScopes:         make(map[string]map[string]*pb.ChatbotScopes),
+		chatbotScopesVersions: make(map[string]map[string]uint64),
+		groupHideTrigger:      make(map[string]bool),
+		pendingRejoins:        make(map[string]map[string]bool),
+		outbox:                make(map[string]*outboxEntry),
+		receipts:              newReceiptTracker(),
+		history:               stores.NewMessageHistory(),
+		chatServiceAddress:    "",
 	}
 
-	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "", grpc.WithInsecure(), grpc.WithContextDialer(dialer))
 	if err != nil {
 		panic(err)
 	}
 
 	serviceClient := pb.NewChatServiceClient(conn)
-	clientObj.SetChatServiceClient(&serviceClient, &ctx)
+	clientObj.SetChatServiceClient(&serviceClient)
 	csu.chatServiceClient = serviceClient
-	csu.chatServiceClientCtx = ctx
+	csu.chatServiceConn = conn
 
-	if !csu.RegisterUserToServer() {
+	if !csu.RegisterUserToServer(ctx) {
 		logger.Error("User registration failed")
+		conn.Close()
 		return nil
 	}
 
-	go csu.ListenToStreams()
+	csu.startListening()
 
 	if setup {
 		// Generate and upload prekeys
 		for i := 1; i <= 300; i = i + 1 {
 			preKeyID := clientObj.GeneratePreKey(i)
-			clientObj.UploadPreKeyByID(preKeyID)
+			clientObj.UploadPreKeyByID(ctx, preKeyID)
 		}
 
 		// Generate and upload signed prekey
 		signedPreKeyID := clientObj.GenerateSignedPreKey()
-		clientObj.UploadSignedPreKeyByID(signedPreKeyID)
+		clientObj.UploadSignedPreKeyByID(ctx, signedPreKeyID)
 
 		// Generate and upload MLS key packages
 		for i := 1; i <= 200; i = i + 1 {
 			clientObj.GenerateMLSKeyPackage(uint32(i))
-			clientObj.UploadMLSKeyPackage(uint32(i))
+			clientObj.UploadMLSKeyPackage(ctx, uint32(i))
 		}
 		logger.Info("Finish setup.")
 	}
@@ -117,11 +184,11 @@ func NewClientSideUserBufconn(userID string, dialer func(context.Context, string
 	return csu
 }
 
-func (csu *ClientSideUser) RegisterUserToServer() bool {
+func (csu *ClientSideUser) RegisterUserToServer(ctx context.Context) bool {
 	logger.Info("Registering User: ", csu.userID)
 
 	// Register user
-	res, err := csu.chatServiceClient.SetUser(csu.chatServiceClientCtx, &pb.SetUserRequest{
+	res, err := csu.chatServiceClient.SetUser(ctx, &pb.SetUserRequest{
 		UserID:            csu.userID,
 		IdentityKeyPublic: csu.Client.GetIdentityKey().PublicKey().Serialize(),
 		RegistrationID:    csu.Client.GetRegistrationID(),
@@ -155,26 +222,68 @@ func (csu *ClientSideUser) GetMessageChan() <-chan OutputMessage {
 }
 
 /*
-Deactivate deactivates the user.
+GetErrorChan returns the error channel, which receives a *HandlingError for every incoming message or server event the
+user failed to handle.
 */
-func (csu *ClientSideUser) Deactivate() {
-	csu.deactivateChan <- true
+func (csu *ClientSideUser) GetErrorChan() <-chan error {
+	return csu.errorChan
 }
 
 /*
-OutputMessage is used in the message channel to denote the message receive by the user.
+Deactivate deactivates the user. The server ends the streams once it sent what it already took off the queues, and the
+user handles it before the connection to the server is closed. The streams are cancelled if ctx is done first.
 */
-type OutputMessage struct {
-	Message     []byte
-	MessageType pb.MessageType
-	EventType   pb.ServerEventType
+func (csu *ClientSideUser) Deactivate(ctx context.Context) error {
+	if csu.listenerDone == nil {
+		return nil
+	}
+
+	select {
+	case csu.deactivateChan <- ctx:
+	default:
+		// The user is already being deactivated
+	}
+
+	select {
+	case <-csu.listenerDone:
+	case <-ctx.Done():
+		csu.stopBackground()
+		csu.closeConnection()
+		return ctx.Err()
+	}
+	csu.stopBackground()
+	return csu.closeConnection()
+}
+
+/*
+stopBackground cancels the work started in the background while handling messages and events, and waits for it to stop.
+*/
+func (csu *ClientSideUser) stopBackground() {
+	csu.cancelLifecycle()
+	csu.background.Wait()
+}
+
+/*
+closeConnection closes the connection to the server once, so that the user can be deactivated again.
+*/
+func (csu *ClientSideUser) closeConnection() error {
+	var err error
+	csu.closeConnOnce.Do(func() {
+		err = csu.chatServiceConn.Close()
+	})
+	return err
 }
 
+/*
+OutputMessage is used in the message channel to denote the message or the server event received by the user.
+*/
+type OutputMessage = client.OutputMessage
+
 /*
 PseudoUser is used to denote a pseudo user.
 */
 type PseudoUser struct {
 	PseudoUserID   string
-	SigningKeyPair treekem.Keypair
+	SigningKeyPair treekem.SigningKeypair
 	SignSecret     []byte
 }
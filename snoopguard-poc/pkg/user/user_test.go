@@ -5,16 +5,25 @@ import (
 	"chatbot-poc-go/pkg/client"
 	pb "chatbot-poc-go/pkg/protos/services"
 	"chatbot-poc-go/pkg/server"
+	"chatbot-poc-go/pkg/stores"
 	"chatbot-poc-go/pkg/treekem"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"go.mau.fi/libsignal/protocol"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 	"log"
 	"math/rand"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -33,27 +42,28 @@ func TestCreateUserAndSetupConnection(t *testing.T) {
 }
 
 func TestUserPreKeyAndSignedPreKey(t *testing.T) {
+	ctx := context.Background()
 	// Generate prekey and signed prekey
 	// Note that this is only for test purpose, when setup=true, the prekeys are generated and uploaded by the time the ClientSideUser is initialized.
 	alicePreKeyID := alice.Client.GeneratePreKey(0)
 	aliceSignedPreKeyID := alice.Client.GenerateSignedPreKey()
-	assert.True(t, alice.Client.UploadPreKeyByID(alicePreKeyID), "Alice upload prekey should be successful")
-	assert.True(t, alice.Client.UploadSignedPreKeyByID(aliceSignedPreKeyID), "Alice upload signed prekey should be successful")
+	assert.True(t, alice.Client.UploadPreKeyByID(ctx, alicePreKeyID), "Alice upload prekey should be successful")
+	assert.True(t, alice.Client.UploadSignedPreKeyByID(ctx, aliceSignedPreKeyID), "Alice upload signed prekey should be successful")
 
 	bobPreKeyID := bob.Client.GeneratePreKey(0)
 	bobSignedPreKeyID := bob.Client.GenerateSignedPreKey()
-	assert.True(t, bob.Client.UploadPreKeyByID(bobPreKeyID), "Bob upload prekey should be successful")
-	assert.True(t, bob.Client.UploadSignedPreKeyByID(bobSignedPreKeyID), "Bob upload signed prekey should be successful")
+	assert.True(t, bob.Client.UploadPreKeyByID(ctx, bobPreKeyID), "Bob upload prekey should be successful")
+	assert.True(t, bob.Client.UploadSignedPreKeyByID(ctx, bobSignedPreKeyID), "Bob upload signed prekey should be successful")
 
 	carolPreKeyID := carol.Client.GeneratePreKey(0)
 	carolSignedPreKeyID := carol.Client.GenerateSignedPreKey()
-	assert.True(t, carol.Client.UploadPreKeyByID(carolPreKeyID), "Carol upload prekey should be successful")
-	assert.True(t, carol.Client.UploadSignedPreKeyByID(carolSignedPreKeyID), "Carol upload signed prekey should be successful")
+	assert.True(t, carol.Client.UploadPreKeyByID(ctx, carolPreKeyID), "Carol upload prekey should be successful")
+	assert.True(t, carol.Client.UploadSignedPreKeyByID(ctx, carolSignedPreKeyID), "Carol upload signed prekey should be successful")
 
 	// Test PreKeyBundle
-	aliceGetBobsPreKeyBundle, err := alice.Client.GetOthersPreKeyBundle(bob.GetUserID())
+	aliceGetBobsPreKeyBundle, err := alice.Client.GetOthersPreKeyBundle(ctx, bob.GetUserID())
 	assert.Nil(t, err, "Alice should be able to get Bob's prekey bundle")
-	bobGetAlicesPreKeyBundle, err := bob.Client.GetOthersPreKeyBundle(alice.GetUserID())
+	bobGetAlicesPreKeyBundle, err := bob.Client.GetOthersPreKeyBundle(ctx, alice.GetUserID())
 	assert.Nil(t, err, "Bob should be able to get Alice's prekey bundle")
 
 	// Get real PreKeyBundle
@@ -86,23 +96,29 @@ func TestUserPreKeyAndSignedPreKey(t *testing.T) {
 }
 
 func TestSendMessage(t *testing.T) {
+	ctx := context.Background()
 	// Alice to Bob
-	_, err := alice.CreateIndividualSession(protocol.NewSignalAddress(bob.GetUserID(), 1))
+	_, err := alice.CreateIndividualSession(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1))
 	assert.Nil(t, err, "Alice should be able to create a session with Bob")
 	for i := 0; i < 10; i++ {
-		err := alice.SendIndividualMessage(protocol.NewSignalAddress(bob.GetUserID(), 1), []byte(fmt.Sprintf("Hello Bob! %v", i)), pb.MessageType_TEXT_MESSAGE)
+		err := alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1), []byte(fmt.Sprintf("Hello Bob! %v", i)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "Alice should be able to send message to Bob")
 		msg, success := timeOutReadFromMessageChannel(bob.messageChan)
 		assert.True(t, success, "Bob should receive a message from Alice")
 		assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Bob should receive a text message from Alice")
 		assert.Equal(t, fmt.Sprintf("Hello Bob! %v", i), string(msg.Message), "Bob should receive the same message from Alice")
+		assert.Equal(t, alice.GetUserID(), msg.SenderID, "Bob should learn the message is from Alice")
+		assert.Equal(t, pb.Channel_INDIVIDUAL_CHANNEL, msg.Channel, "Bob should receive an individual message")
+		assert.Empty(t, msg.GroupID, "An individual message should not belong to a group")
+		assert.NotEmpty(t, msg.MessageID, "The server should assign a message ID")
+		assert.False(t, msg.Timestamp.IsZero(), "The server should timestamp the message")
 	}
 
 	// Bob to Alice
-	_, err = bob.CreateIndividualSession(protocol.NewSignalAddress(alice.GetUserID(), 1))
+	_, err = bob.CreateIndividualSession(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1))
 	assert.Nil(t, err, "Bob should be able to create a session with Alice")
 	for i := 0; i < 10; i++ {
-		err := bob.SendIndividualMessage(protocol.NewSignalAddress(alice.GetUserID(), 1), []byte(fmt.Sprintf("Hello Alice! %v", i)), pb.MessageType_TEXT_MESSAGE)
+		err := bob.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte(fmt.Sprintf("Hello Alice! %v", i)), pb.MessageType_TEXT_MESSAGE)
 		assert.Nil(t, err, "Bob should be able to send message to Alice")
 		msg, success := timeOutReadFromMessageChannel(alice.messageChan)
 		assert.True(t, success, "Alice should receive a message from Bob")
@@ -111,10 +127,46 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+// TestHandlingErrors tests that the failures of handling incoming messages and membership changes are reported.
+func TestHandlingErrors(t *testing.T) {
+	ctx := context.Background()
+	// A message Bob cannot decrypt is reported on the error channel instead of being dropped silently
+	err := alice.Client.SendIndividualMessage(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1), &pb.MessageWrapper{
+		SenderID:         alice.GetUserID(),
+		RecipientID:      bob.GetUserID(),
+		EncryptedMessage: []byte("Not a Signal message."),
+		MessageID:        "malformed",
+	})
+	assert.Nil(t, err, "Alice should be able to send a malformed message to Bob")
+	handlingErr, success := timeOutReadFromErrorChannel(bob.GetErrorChan())
+	assert.True(t, success, "Bob should report the malformed message")
+	assert.ErrorIs(t, handlingErr, ErrUndecryptable, "Bob should not decrypt the malformed message")
+	var bobHandlingErr *HandlingError
+	assert.True(t, errors.As(handlingErr, &bobHandlingErr), "Bob should report a HandlingError")
+	assert.Equal(t, alice.GetUserID(), bobHandlingErr.SenderID, "Bob should report who sent the malformed message")
+	assert.NotEmpty(t, bobHandlingErr.MessageID, "Bob should report which message is malformed")
+
+	// Bob keeps receiving the messages of Alice
+	err = alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1), []byte("Still here."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Alice should be able to send message to Bob")
+	msg, success := timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive a message from Alice")
+	assert.Equal(t, "Still here.", string(msg.Message), "Bob should receive the message after the malformed one")
+
+	// Messages and membership changes of a group Bob is not in are rejected
+	_, err = bob.ParseMessageWrapper(ctx, &pb.MessageWrapper{SenderID: alice.GetUserID(), RecipientID: "unknown-group", MessageID: "unknown-group"})
+	assert.ErrorIs(t, err, ErrNotInGroup, "Bob should not handle a message of a group he is not in")
+	err = bob.AddUserToGroup("unknown-group", pb.GroupType_SERVER_SIDE, alice.GetUserID(), carol.GetUserID(), nil, treekem.UserAdd{}, nil, nil)
+	assert.ErrorIs(t, err, ErrNotInGroup, "Bob should not add a user to a group he is not in")
+	err = bob.RemoveChatbotFromGroup("unknown-group", pb.GroupType_MLS, "chatbot", nil)
+	assert.ErrorIs(t, err, ErrNotInGroup, "Bob should not remove a chatbot from a group he is not in")
+}
+
 // TestClientSideGroupMessage test the client side group Message.
 func TestClientSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_CLIENT_SIDE)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_CLIENT_SIDE)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
 	// Alice should have the group session
@@ -122,10 +174,16 @@ func TestClientSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Alice should have the group session")
 
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, bob.GetUserID())
 	msg, success := timeOutReadFromMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, groupId, msg.GroupID, "The invitation should be for the group")
+	assert.Equal(t, alice.GetUserID(), msg.SenderID, "The invitation should be from Alice")
+	assert.Equal(t, groupId, msg.Event.GetGroupInvitation().GetGroupID(), "The invitation should carry its event")
+	assert.Equal(t, pb.GroupType_CLIENT_SIDE, msg.GroupType, "The invitation should be for a client-side group")
+	assert.ElementsMatch(t, []string{alice.GetUserID(), bob.GetUserID()}, msg.ParticipantIDs, "The invitation should list the members")
+	assert.False(t, msg.Timestamp.IsZero(), "The server should timestamp the event")
 
 	// Bob should have the group session
 	bobSessionDriver, err := bob.Client.GetClientSideGroupSessionDriver(groupId)
@@ -135,13 +193,15 @@ func TestClientSideGroupMessage(t *testing.T) {
 	msg, success = timeOutReadFromMessageChannel(alice.messageChan)
 	assert.True(t, success, "Alice should receive a group addition event")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
+	assert.Equal(t, bob.GetUserID(), msg.MemberID, "Alice should learn that Bob is added")
+	assert.Equal(t, groupId, msg.GroupID, "The addition should be for the group")
 
 	// TreeKEM should be the same.
 	assert.True(t, treekemGroupEqual(aliceSessionDriver.GetTreeKEMState(), bobSessionDriver.GetTreeKEMState()), "Alice and Bob should have the same TreeKEM state")
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Alice send a message to the group
-	err = alice.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = alice.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob should receive the Message
@@ -149,13 +209,16 @@ func TestClientSideGroupMessage(t *testing.T) {
 	assert.True(t, success, "Bob should receive a Message from Alice")
 	assert.Equal(t, msg.MessageType, pb.MessageType_TEXT_MESSAGE, "Bob should receive a group text Message from Alice")
 	assert.Equal(t, "Hello everyone! I'm Alice.", string(msg.Message), "Bob should receive a group text Message from Alice")
+	assert.Equal(t, groupId, msg.GroupID, "Bob should learn the group of the message")
+	assert.Equal(t, alice.GetUserID(), msg.SenderID, "Bob should learn the message is from Alice")
+	assert.Equal(t, pb.Channel_GROUP_CHANNEL, msg.Channel, "Bob should receive a group message")
 
 	// TreeKEM should be the same.
 	assert.True(t, treekemGroupEqual(aliceSessionDriver.GetTreeKEMState(), bobSessionDriver.GetTreeKEMState()), "Alice and Bob should have the same TreeKEM state")
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Invite Carol to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, carol.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, carol.GetUserID())
 
 	// Carol should receive a group invitation from Alice
 	msg, success = timeOutReadFromMessageChannel(carol.GetMessageChan())
@@ -183,7 +246,7 @@ func TestClientSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Bob send a message to the group
-	err = bob.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = bob.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Bob should be able to send a message to the group")
 
 	// Alice should receive the message
@@ -206,7 +269,7 @@ func TestClientSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Carol invite David to the group.
-	carol.RequestInviteUserToGroup(groupId, pb.GroupType_CLIENT_SIDE, david.GetUserID())
+	carol.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_CLIENT_SIDE, david.GetUserID())
 	msg, success = timeOutReadFromMessageChannel(david.GetMessageChan())
 	assert.True(t, success, "David should receive a group invitation from Carol")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "David should receive a group invitation from Carol")
@@ -231,7 +294,7 @@ func TestClientSideGroupMessage(t *testing.T) {
 	}
 
 	// Carol send a message to the group
-	err = carol.SendClientSideGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil)
+	err = carol.SendClientSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil)
 	assert.Nil(t, err, "Carol should be able to send a message to the group")
 
 	// Alice, Bob, and David should receive the message
@@ -254,8 +317,9 @@ func TestClientSideGroupMessage(t *testing.T) {
 
 // TestServerSideGroupMessage test the server side group Message.
 func TestServerSideGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_SERVER_SIDE)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
 	// Alice should have the group session
@@ -263,7 +327,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Alice should have the group session")
 
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
 	msg, success := timeOutReadFromMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
@@ -278,7 +342,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_ADDITION, "Alice should receive a group addition event")
 
 	// Bob distribute his sender key to all
-	err = bob.DistributeSelfSenderKeyToAll(groupId)
+	err = bob.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "Bob should be able to distribute his sender key to all")
 
 	// Alice and Bob should receive sender key distribution messages from others
@@ -296,7 +360,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, treekemGroupEqual(aliceSessionDriver.GetTreeKEMState(), bobSessionDriver.GetTreeKEMState()), "Alice and Bob should have the same TreeKEM state")
 
 	// Alice send a message to the group
-	err = alice.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob should receive the message
@@ -310,7 +374,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Invite Carol to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
 
 	// Carol should receive a group invitation from Alice
 	msg, success = timeOutReadFromMessageChannel(carol.GetMessageChan())
@@ -328,7 +392,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	carolSessionDriver, err := carol.Client.GetServerSideGroupSessionDriver(groupId)
 	assert.Nil(t, err, "Carol should have the group session")
 
-	err = carol.DistributeSelfSenderKeyToAll(groupId)
+	err = carol.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "Carol should be able to distribute her sender key to all")
 
 	// Alice, Bob should receive sender key distribution messages from Carol
@@ -362,7 +426,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Bob send a message to the group
-	err = bob.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = bob.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Bob should be able to send a message to the group")
 
 	// Alice should receive the message
@@ -384,7 +448,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	assert.True(t, multiTreeKemEqual(aliceSessionDriver.GetMultiTreeKEM(), bobSessionDriver.GetMultiTreeKEM()), "Alice and Bob should have the same MultiTreeKEM state")
 
 	// Carol invite David to the group.
-	carol.RequestInviteUserToGroup(groupId, pb.GroupType_SERVER_SIDE, david.GetUserID())
+	carol.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, david.GetUserID())
 	msg, success = timeOutReadFromMessageChannel(david.GetMessageChan())
 	assert.True(t, success, "David should receive a group invitation from Carol")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "David should receive a group invitation from Carol")
@@ -401,7 +465,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// David distribute his sender key to all
-	err = david.DistributeSelfSenderKeyToAll(groupId)
+	err = david.DistributeSelfSenderKeyToAll(ctx, groupId)
 	assert.Nil(t, err, "David should be able to distribute his sender key to all")
 
 	// Alice, Bob, Carol should receive sender key distribution messages from David
@@ -427,7 +491,7 @@ func TestServerSideGroupMessage(t *testing.T) {
 	}
 
 	// Carol send a message to the group
-	err = carol.SendServerSideGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = carol.SendServerSideGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Carol should be able to send a message to the group")
 
 	// Alice, Bob, and David should receive the message
@@ -448,10 +512,128 @@ func TestServerSideGroupMessage(t *testing.T) {
 
 }
 
+// TestServerSideGroupSenderKeyRotation test that the sender keys are rotated on member removal and after N messages.
+func TestServerSideGroupSenderKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Alice should be able to create a group")
+	aliceSessionDriver, err := alice.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Alice should have the group session")
+
+	// Invite Bob and Carol to the group.
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, bob.GetUserID())
+	msg, success := timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Bob should receive a group invitation from Alice")
+	msg, success = timeOutReadFromMessageChannel(alice.messageChan)
+	assert.True(t, success, "Alice should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice should receive a group addition event")
+
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_SERVER_SIDE, carol.GetUserID())
+	msg, success = timeOutReadFromMessageChannel(carol.messageChan)
+	assert.True(t, success, "Carol should receive a group invitation from Alice")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Carol should receive a group invitation from Alice")
+	for _, c := range []<-chan OutputMessage{alice.messageChan, bob.messageChan} {
+		msg, success = timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Alice and Bob should receive a group addition event")
+		assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Alice and Bob should receive a group addition event")
+	}
+
+	bobSessionDriver, err := bob.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Bob should have the group session")
+	carolSessionDriver, err := carol.Client.GetServerSideGroupSessionDriver(groupId)
+	assert.Nil(t, err, "Carol should have the group session")
+
+	// Bob and Carol distribute their sender keys, and everyone bounces back theirs.
+	for _, distributor := range []*ClientSideUser{bob, carol} {
+		err = distributor.DistributeSelfSenderKeyToAll(ctx, groupId)
+		assert.Nil(t, err, "Should be able to distribute the sender key to all")
+		for _, c := range []<-chan OutputMessage{alice.messageChan, bob.messageChan, carol.messageChan} {
+			expected := 1
+			if c == distributor.messageChan {
+				expected = 2
+			}
+			for i := 0; i < expected; i++ {
+				msg, success = timeOutReadFromMessageChannel(c)
+				assert.True(t, success, "Should receive a sender key distribution message from the group")
+				assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+			}
+		}
+	}
+
+	// Carol can decrypt the messages of Alice before the removal.
+	cipherText, err := aliceSessionDriver.EncryptMessageBySendingSession([]byte("Before the removal."), pb.MessageType_TEXT_MESSAGE, "before", nil)
+	assert.Nil(t, err, "Alice should encrypt the message")
+	message, _, _, _ := carolSessionDriver.ParseEncryptedMessage(alice.GetUserID(), cipherText, "before")
+	assert.Equal(t, "Before the removal.", string(message), "Carol should decrypt the message of Alice before the removal")
+	aliceSenderKeyID := aliceSessionDriver.GetSelfSenderKey().ID()
+	bobSenderKeyID := bobSessionDriver.GetSelfSenderKey().ID()
+
+	// Alice removes Carol
+	alice.RequestRemoveUserFromGroup(ctx, groupId, carol.GetUserID())
+	msg, success = timeOutReadFromMessageChannel(carol.messageChan)
+	assert.True(t, success, "Carol should receive a GROUP_REMOVAL event")
+	assert.Equal(t, pb.ServerEventType_GROUP_REMOVAL, msg.EventType, "Carol should receive a GROUP_REMOVAL event")
+	assert.Equal(t, carol.GetUserID(), msg.MemberID, "Carol should learn that she is removed")
+	assert.NotContains(t, msg.ParticipantIDs, carol.GetUserID(), "Carol should not be listed as a member")
+
+	// Alice and Bob should receive GROUP_REMOVAL event and the rotated sender key of each other, in either order
+	for _, c := range []<-chan OutputMessage{alice.messageChan, bob.messageChan} {
+		receivedRemoval, receivedSenderKey := false, false
+		for i := 0; i < 2; i++ {
+			msg, success = timeOutReadFromMessageChannel(c)
+			assert.True(t, success, "Should receive the removal and the rotated sender key")
+			receivedRemoval = receivedRemoval || msg.EventType == pb.ServerEventType_GROUP_REMOVAL
+			receivedSenderKey = receivedSenderKey || msg.MessageType == pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE
+		}
+		assert.True(t, receivedRemoval, "Should receive a GROUP_REMOVAL event")
+		assert.True(t, receivedSenderKey, "Should receive the rotated sender key")
+	}
+	assert.NotEqual(t, aliceSenderKeyID, aliceSessionDriver.GetSelfSenderKey().ID(), "Alice should rotate her sender key")
+	assert.NotEqual(t, bobSenderKeyID, bobSessionDriver.GetSelfSenderKey().ID(), "Bob should rotate his sender key")
+
+	// Carol keeps her old state, as a compromised member would, but cannot decrypt the later messages.
+	for _, sender := range []*ClientSideUser{alice, bob} {
+		senderSessionDriver, err := sender.Client.GetServerSideGroupSessionDriver(groupId)
+		assert.Nil(t, err, "Should have the group session")
+		cipherText, err = senderSessionDriver.EncryptMessageBySendingSession([]byte("After the removal."), pb.MessageType_TEXT_MESSAGE, "after", nil)
+		assert.Nil(t, err, "Should encrypt the message")
+		assert.Panics(t, func() { carolSessionDriver.ParseEncryptedMessage(sender.GetUserID(), cipherText, "after") }, "Carol should not decrypt the messages after the removal")
+	}
+
+	// Alice and Bob still talk to each other
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Carol is gone."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Alice should be able to send a message to the group")
+	msg, success = timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive a message from Alice")
+	assert.Equal(t, "Carol is gone.", string(msg.Message), "Bob should receive a group text message from Alice")
+
+	// Alice has used her sender key for two messages since the rotation, so the next message after one more rotates it.
+	aliceSessionDriver.SetSenderKeyRotationInterval(3)
+	aliceSenderKeyID = aliceSessionDriver.GetSelfSenderKey().ID()
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Third message."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Alice should be able to send a message to the group")
+	msg, success = timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive a message from Alice")
+	assert.Equal(t, "Third message.", string(msg.Message), "Bob should receive a group text message from Alice")
+	assert.Equal(t, aliceSenderKeyID, aliceSessionDriver.GetSelfSenderKey().ID(), "Alice should not rotate her sender key yet")
+
+	err = alice.SendServerSideGroupMessage(ctx, groupId, []byte("Fourth message."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Alice should be able to send a message to the group")
+	msg, success = timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive the rotated sender key of Alice")
+	assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Bob should receive the rotated sender key of Alice")
+	msg, success = timeOutReadFromMessageChannel(bob.messageChan)
+	assert.True(t, success, "Bob should receive a message from Alice")
+	assert.Equal(t, "Fourth message.", string(msg.Message), "Bob should receive a group text message from Alice")
+	assert.NotEqual(t, aliceSenderKeyID, aliceSessionDriver.GetSelfSenderKey().ID(), "Alice should rotate her sender key after three messages")
+}
+
 // TestMlsGroupMessage test the MLS group Message.
 func TestMlsGroupMessage(t *testing.T) {
+	ctx := context.Background()
 	// Alice is the initiator of the group
-	groupId, err := alice.CreateGroup(pb.GroupType_MLS)
+	groupId, err := alice.CreateGroup(ctx, pb.GroupType_MLS)
 	assert.Nil(t, err, "Alice should be able to create a group")
 
 	// Alice should have the group session
@@ -459,7 +641,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.Nil(t, err, "Alice should have the group session")
 
 	// Invite Bob to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_MLS, bob.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, bob.GetUserID())
 	msg, success := timeOutReadFromMessageChannel(bob.GetMessageChan())
 	assert.True(t, success, "Bob should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Bob should receive a group invitation from Alice")
@@ -477,7 +659,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
 
 	// Alice send a message to the group
-	err = alice.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = alice.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Alice."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Alice should be able to send a message to the group")
 
 	// Bob should receive the message
@@ -490,7 +672,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
 
 	// Bob send a message to the group
-	err = bob.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = bob.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Bob should be able to send a message to the group")
 
 	// Alice should receive the message
@@ -503,7 +685,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, aliceSessionDriver.GetGroupState().Equals(*bobSessionDriver.GetGroupState()), "Alice and Bob should have the same group state")
 
 	// Invite Carol to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_MLS, carol.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, carol.GetUserID())
 	msg, success = timeOutReadFromMessageChannel(carol.GetMessageChan())
 	assert.True(t, success, "Carol should receive a group invitation from Alice")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "Carol should receive a group invitation from Alice")
@@ -525,7 +707,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, bobSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Bob and Carol should have the same group state")
 
 	// Carol send a message to the group
-	err = carol.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = carol.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Carol."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Carol should be able to send a message to the group")
 
 	// Alice and Bob should receive the message
@@ -542,7 +724,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, bobSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Bob and Carol should have the same group state")
 
 	// Bob send a message to the group
-	err = bob.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = bob.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm Bob."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "Bob should be able to send a message to the group")
 
 	// Alice and Carol should receive the message
@@ -559,7 +741,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	assert.True(t, bobSessionDriver.GetGroupState().Equals(*carolSessionDriver.GetGroupState()), "Bob and Carol should have the same group state")
 
 	// Carol invite David to the group.
-	alice.RequestInviteUserToGroup(groupId, pb.GroupType_MLS, david.GetUserID())
+	alice.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, david.GetUserID())
 	msg, success = timeOutReadFromMessageChannel(david.GetMessageChan())
 	assert.True(t, success, "David should receive a group invitation from Carol")
 	assert.Equal(t, msg.EventType, pb.ServerEventType_GROUP_INVITATION, "David should receive a group invitation from Carol")
@@ -583,7 +765,7 @@ func TestMlsGroupMessage(t *testing.T) {
 	}
 
 	// David send a message to the group
-	err = david.SendMlsGroupMessage(groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	err = david.SendMlsGroupMessage(ctx, groupId, []byte("Hello everyone! I'm David."), pb.MessageType_TEXT_MESSAGE, nil, false)
 	assert.Nil(t, err, "David should be able to send a message to the group")
 
 	// Alice, Bob, Carol should receive the message
@@ -602,12 +784,39 @@ func TestMlsGroupMessage(t *testing.T) {
 	}
 }
 
+func TestParseChatbotTriggers(t *testing.T) {
+	mentions, command := ParseChatbotTriggers("Hey @Weather, what about tomorrow? cc @translator")
+	assert.Equal(t, []string{"weather", "translator"}, mentions, "Mentions should be extracted without punctuation and lowercased")
+	assert.Equal(t, "", command, "A message not starting with a slash has no command")
+
+	mentions, command = ParseChatbotTriggers("/Forecast tomorrow in Taipei")
+	assert.Empty(t, mentions, "A command without mentions has no mentions")
+	assert.Equal(t, "/forecast", command, "The leading command should be extracted and lowercased")
+
+	mentions, command = ParseChatbotTriggers("/forecast@weather tomorrow")
+	assert.Equal(t, []string{"weather"}, mentions, "A command addressed to a chatbot should mention it")
+	assert.Equal(t, "/forecast", command, "The command should not include the addressed chatbot")
+
+	mentions, command = ParseChatbotTriggers("email me at alice@example.com, not /forecast")
+	assert.Empty(t, mentions, "An @ inside a word is not a mention")
+	assert.Equal(t, "", command, "A slash in the middle of the message is not a command")
+
+	// Routing follows the chatbot's registered names and prefixes, and the chatbot ID always works as a mention.
+	routing := &pb.ChatbotRouting{MentionNames: []string{"Weather"}, CommandPrefixes: []string{"forecast"}}
+	assert.True(t, isChatbotTriggered("weatherbot-1", routing, []string{"weather"}, ""), "Mention names should trigger the chatbot")
+	assert.True(t, isChatbotTriggered("weatherbot-1", routing, []string{"weatherbot-1"}, ""), "The chatbot ID should trigger the chatbot")
+	assert.True(t, isChatbotTriggered("weatherbot-1", routing, nil, "/forecast"), "Command prefixes should trigger the chatbot")
+	assert.False(t, isChatbotTriggered("weatherbot-1", routing, []string{"translator"}, "/translate"), "Other mentions and commands should not trigger the chatbot")
+	assert.False(t, isChatbotTriggered("weatherbot-1", nil, nil, "/forecast"), "Commands should not trigger a chatbot without routing metadata")
+}
+
 func TestDeactivate(t *testing.T) {
+	ctx := context.Background()
 	// Alice send a message to Bob.
-	_, err := alice.CreateIndividualSession(protocol.NewSignalAddress(bob.GetUserID(), 1))
+	_, err := alice.CreateIndividualSession(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1))
 	assert.Nil(t, err, "Alice should be able to create a session with Bob")
 
-	err = alice.SendIndividualMessage(protocol.NewSignalAddress(bob.GetUserID(), 1), []byte("Hello Bob!"), pb.MessageType_TEXT_MESSAGE)
+	err = alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1), []byte("Hello Bob!"), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Alice should be able to send message to Bob")
 	msg, success := timeOutReadFromMessageChannel(bob.messageChan)
 	assert.True(t, success, "Bob should receive a message from Alice")
@@ -615,13 +824,13 @@ func TestDeactivate(t *testing.T) {
 	assert.Equal(t, "Hello Bob!", string(msg.Message), "Bob should receive the same message from Alice")
 
 	// Deactivate Bob
-	bob.Deactivate()
+	assert.Nil(t, bob.Deactivate(ctx), "Bob should be deactivated")
 	msg, success = timeOutReadFromMessageChannel(bob.messageChan)
 	assert.True(t, success, "Bob should receive the deactivate message")
 	assert.Equal(t, msg.Message, []byte("Deactivate"), "Bob should receive the deactivate message")
 
 	// Alice send a message to Bob
-	err = alice.SendIndividualMessage(protocol.NewSignalAddress(bob.GetUserID(), 1), []byte("Hello Bob again!"), pb.MessageType_TEXT_MESSAGE)
+	err = alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(bob.GetUserID(), 1), []byte("Hello Bob again!"), pb.MessageType_TEXT_MESSAGE)
 	assert.Nil(t, err, "Alice should be able to send message to Bob")
 
 	// Bob should not receive the message
@@ -629,6 +838,585 @@ func TestDeactivate(t *testing.T) {
 	assert.False(t, success, "Bob should not receive the message from Alice")
 }
 
+func TestDeactivateLeaksNoGoroutines(t *testing.T) {
+	dial := dialer()
+	before := goroutines()
+
+	// Kate is set up, talks to Alice and is torn down within a deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	kate := NewClientSideUserBufconn(ctx, "kate"+randomString(8), dial, true)
+	assert.NotNil(t, kate, "Kate should be created")
+	err := alice.SendIndividualMessage(ctx, protocol.NewSignalAddress(kate.GetUserID(), 1), []byte("Hello Kate!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Alice should be able to send message to Kate")
+	msg, success := timeOutReadFromMessageChannel(kate.GetMessageChan())
+	assert.True(t, success, "Kate should receive a message from Alice")
+	assert.Equal(t, "Hello Kate!", string(msg.Message), "Kate should receive the same message from Alice")
+	err = kate.SendIndividualMessage(ctx, protocol.NewSignalAddress(alice.GetUserID(), 1), []byte("Hello Alice!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Kate should be able to send message to Alice")
+	msg, success = timeOutReadFromMessageChannel(alice.GetMessageChan())
+	assert.True(t, success, "Alice should receive a message from Kate")
+	assert.Equal(t, "Hello Alice!", string(msg.Message), "Alice should receive the same message from Kate")
+
+	// Work Kate left running in the background is cancelled and waited for on deactivation.
+	backgroundStopped := make(chan struct{})
+	kate.goBackground(func(ctx context.Context) {
+		defer close(backgroundStopped)
+		<-ctx.Done()
+	})
+	assert.Nil(t, kate.Deactivate(ctx), "Kate should be deactivated")
+	select {
+	case <-backgroundStopped:
+	default:
+		t.Error("Kate should stop the work in the background before the deactivation returns")
+	}
+	assert.Nil(t, kate.Deactivate(ctx), "Deactivating Kate again should do nothing")
+
+	// Requests fail with a context that is already done.
+	cancelledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = alice.CreateGroup(cancelledCtx, pb.GroupType_SERVER_SIDE)
+	assert.Equal(t, codes.Canceled, status.Code(err), "Alice should not create a group with a cancelled context")
+
+	// Leo is torn down even if the deactivation is cancelled, and nothing is left running.
+	leo := NewClientSideUserBufconn(ctx, "leo"+randomString(8), dial, false)
+	assert.NotNil(t, leo, "Leo should be created")
+	assert.ErrorIs(t, leo.Deactivate(cancelledCtx), context.Canceled, "Deactivating Leo should be cancelled")
+	assertNoLeakedGoroutines(t, before)
+}
+
+func TestRestoreClientSideUser(t *testing.T) {
+	ctx := context.Background()
+	erin := createClientSideUserWithRandomUserID("erin")
+	frank := createClientSideUserWithRandomUserID("frank")
+	statePath := filepath.Join(t.TempDir(), "frank.state")
+	frank.SetStateStore(stores.NewEncryptedFileStore(statePath, []byte("frank's passphrase")))
+
+	// Erin and Frank talk individually, in a server-side group and in an MLS group.
+	_, err := erin.CreateIndividualSession(ctx, protocol.NewSignalAddress(frank.GetUserID(), 1))
+	assert.Nil(t, err, "Erin should be able to create a session with Frank")
+	err = erin.SendIndividualMessage(ctx, protocol.NewSignalAddress(frank.GetUserID(), 1), []byte("Hello Frank!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Erin should be able to send message to Frank")
+	msg, success := timeOutReadFromMessageChannel(frank.GetMessageChan())
+	assert.True(t, success, "Frank should receive a message from Erin")
+	assert.Equal(t, "Hello Frank!", string(msg.Message), "Frank should receive the same message from Erin")
+
+	serverSideGroupId, err := erin.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Erin should be able to create a server-side group")
+	erin.RequestInviteUserToGroup(ctx, serverSideGroupId, pb.GroupType_SERVER_SIDE, frank.GetUserID())
+	msg, success = timeOutReadFromMessageChannel(frank.GetMessageChan())
+	assert.True(t, success, "Frank should receive a group invitation from Erin")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Frank should receive a group invitation from Erin")
+	msg, success = timeOutReadFromMessageChannel(erin.GetMessageChan())
+	assert.True(t, success, "Erin should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Erin should receive a group addition event")
+	err = frank.DistributeSelfSenderKeyToAll(ctx, serverSideGroupId)
+	assert.Nil(t, err, "Frank should be able to distribute his sender key to all")
+	for _, c := range []<-chan OutputMessage{erin.GetMessageChan(), frank.GetMessageChan()} {
+		msg, success = timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	mlsGroupId, err := erin.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Erin should be able to create an MLS group")
+	erin.RequestInviteUserToGroup(ctx, mlsGroupId, pb.GroupType_MLS, frank.GetUserID())
+	msg, success = timeOutReadFromMessageChannel(frank.GetMessageChan())
+	assert.True(t, success, "Frank should receive a group invitation from Erin")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Frank should receive a group invitation from Erin")
+	msg, success = timeOutReadFromMessageChannel(erin.GetMessageChan())
+	assert.True(t, success, "Erin should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Erin should receive a group addition event")
+
+	// Frank shuts down, and Erin keeps writing to him.
+	assert.Nil(t, frank.Deactivate(ctx), "Frank should be deactivated")
+	msg, success = timeOutReadFromMessageChannel(frank.GetMessageChan())
+	assert.True(t, success, "Frank should receive the deactivate message")
+	assert.Equal(t, []byte("Deactivate"), msg.Message, "Frank should receive the deactivate message")
+
+	err = erin.SendIndividualMessage(ctx, protocol.NewSignalAddress(frank.GetUserID(), 1), []byte("Are you there, Frank?"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Erin should be able to send message to Frank")
+	err = erin.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Hello server-side group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Erin should be able to send a message to the server-side group")
+	err = erin.SendMlsGroupMessage(ctx, mlsGroupId, []byte("Hello MLS group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Erin should be able to send a message to the MLS group")
+
+	// The state cannot be restored without the passphrase.
+	_, err = stores.NewEncryptedFileStore(statePath, []byte("wrong passphrase")).Load()
+	assert.ErrorIs(t, err, stores.ErrWrongPassphrase, "The state should not be loaded with a wrong passphrase")
+
+	// Frank restarts from his stored state.
+	restoredFrank, err := RestoreClientSideUserBufconn(ctx, stores.NewEncryptedFileStore(statePath, []byte("frank's passphrase")), dialer())
+	assert.Nil(t, err, "Frank should be restored from his stored state")
+	assert.Equal(t, frank.GetUserID(), restoredFrank.GetUserID(), "The restored Frank should have the same user ID")
+	assert.Equal(t, frank.Client.GetIdentityKey().PublicKey().Serialize(), restoredFrank.Client.GetIdentityKey().PublicKey().Serialize(), "The restored Frank should have the same identity key")
+
+	// The restored Frank receives what Erin sent while he was away.
+	for _, expected := range []string{"Are you there, Frank?", "Hello server-side group!", "Hello MLS group!"} {
+		msg, success = timeOutReadFromMessageChannel(restoredFrank.GetMessageChan())
+		assert.True(t, success, "The restored Frank should receive the message sent while he was away")
+		assert.Equal(t, pb.MessageType_TEXT_MESSAGE, msg.MessageType, "The restored Frank should receive a text message")
+		assert.Equal(t, expected, string(msg.Message), "The restored Frank should receive the message sent while he was away")
+	}
+
+	// And the restored Frank writes back through every channel.
+	err = restoredFrank.SendIndividualMessage(ctx, protocol.NewSignalAddress(erin.GetUserID(), 1), []byte("I'm back, Erin!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "The restored Frank should be able to send message to Erin")
+	err = restoredFrank.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("I'm back, server-side group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "The restored Frank should be able to send a message to the server-side group")
+	err = restoredFrank.SendMlsGroupMessage(ctx, mlsGroupId, []byte("I'm back, MLS group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "The restored Frank should be able to send a message to the MLS group")
+	for _, expected := range []string{"I'm back, Erin!", "I'm back, server-side group!", "I'm back, MLS group!"} {
+		msg, success = timeOutReadFromMessageChannel(erin.GetMessageChan())
+		assert.True(t, success, "Erin should receive the message from the restored Frank")
+		assert.Equal(t, expected, string(msg.Message), "Erin should receive the message from the restored Frank")
+	}
+
+	erinMlsSessionDriver, _ := erin.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	frankMlsSessionDriver, _ := restoredFrank.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	assert.True(t, erinMlsSessionDriver.GetGroupState().Equals(*frankMlsSessionDriver.GetGroupState()), "Erin and the restored Frank should have the same MLS group state")
+	erinServerSideSessionDriver, _ := erin.Client.GetServerSideGroupSessionDriver(serverSideGroupId)
+	frankServerSideSessionDriver, _ := restoredFrank.Client.GetServerSideGroupSessionDriver(serverSideGroupId)
+	assert.True(t, multiTreeKemEqual(erinServerSideSessionDriver.GetMultiTreeKEM(), frankServerSideSessionDriver.GetMultiTreeKEM()), "Erin and the restored Frank should have the same MultiTreeKEM state")
+}
+
+func TestUserBackup(t *testing.T) {
+	ctx := context.Background()
+	gina := createClientSideUserWithRandomUserID("gina")
+	hank := createClientSideUserWithRandomUserID("hank")
+
+	// Gina and Hank share a server-side group and an MLS group.
+	serverSideGroupId, err := gina.CreateGroup(ctx, pb.GroupType_SERVER_SIDE)
+	assert.Nil(t, err, "Gina should be able to create a server-side group")
+	gina.RequestInviteUserToGroup(ctx, serverSideGroupId, pb.GroupType_SERVER_SIDE, hank.GetUserID())
+	msg, success := timeOutReadFromMessageChannel(hank.GetMessageChan())
+	assert.True(t, success, "Hank should receive a group invitation from Gina")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Hank should receive a group invitation from Gina")
+	msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+	assert.True(t, success, "Gina should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Gina should receive a group addition event")
+	err = hank.DistributeSelfSenderKeyToAll(ctx, serverSideGroupId)
+	assert.Nil(t, err, "Hank should be able to distribute his sender key to all")
+	for _, c := range []<-chan OutputMessage{gina.GetMessageChan(), hank.GetMessageChan()} {
+		msg, success = timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Should receive a sender key distribution message from the group")
+		assert.Equal(t, pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE, msg.MessageType, "Should receive a sender key distribution message from the group")
+	}
+
+	mlsGroupId, err := gina.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Gina should be able to create an MLS group")
+	gina.RequestInviteUserToGroup(ctx, mlsGroupId, pb.GroupType_MLS, hank.GetUserID())
+	msg, success = timeOutReadFromMessageChannel(hank.GetMessageChan())
+	assert.True(t, success, "Hank should receive a group invitation from Gina")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Hank should receive a group invitation from Gina")
+	msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+	assert.True(t, success, "Gina should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Gina should receive a group addition event")
+
+	// Hank backs up his account, keeps talking in the MLS group on his old device for a while, and loses it.
+	recoveryCode, err := stores.GenerateRecoveryCode()
+	assert.Nil(t, err, "Should be able to generate a recovery code")
+	backup, err := hank.ExportBackup(recoveryCode, false)
+	assert.Nil(t, err, "Hank should be able to export a backup")
+	for i := 1; i <= 4; i++ {
+		sender, recipient := gina, hank
+		if i%2 == 0 {
+			sender, recipient = hank, gina
+		}
+		err = sender.SendMlsGroupMessage(ctx, mlsGroupId, []byte(fmt.Sprintf("After the backup %v", i)), pb.MessageType_TEXT_MESSAGE, nil, false)
+		assert.Nil(t, err, "Should be able to send a message to the MLS group after the backup")
+		msg, success = timeOutReadFromMessageChannel(recipient.GetMessageChan())
+		assert.True(t, success, "Should receive the message sent after the backup")
+		assert.Equal(t, fmt.Sprintf("After the backup %v", i), string(msg.Message), "Should receive the message sent after the backup")
+	}
+	assert.Nil(t, hank.Deactivate(ctx), "Hank should be deactivated")
+	msg, success = timeOutReadFromMessageChannel(hank.GetMessageChan())
+	assert.True(t, success, "Hank should receive the deactivate message")
+	assert.Equal(t, []byte("Deactivate"), msg.Message, "Hank should receive the deactivate message")
+
+	// The backup cannot be imported without the recovery code.
+	_, err = ImportBackupBufconn(ctx, backup, "AAAA-AAAA-AAAA-AAAA-AAAA-AAAA-AAAA-AAAA", dialer())
+	assert.ErrorIs(t, err, stores.ErrWrongRecoveryCode, "The backup should not be imported with a wrong recovery code")
+
+	// Hank imports his backup on a new device, written in lowercase this time.
+	importedHank, err := ImportBackupBufconn(ctx, backup, strings.ToLower(recoveryCode), dialer())
+	assert.Nil(t, err, "Hank should be able to import his backup")
+	assert.Equal(t, hank.GetUserID(), importedHank.GetUserID(), "The imported Hank should have the same user ID")
+	assert.Equal(t, hank.Client.GetIdentityKey().PublicKey().Serialize(), importedHank.Client.GetIdentityKey().PublicKey().Serialize(), "The imported Hank should have the same identity key")
+
+	// Gina receives the new sender key of Hank, and removes and re-adds him to the MLS group.
+	// Hank receives the sender key Gina bounces back, his removal and the invitation back, in the server's order.
+	for _, c := range []<-chan OutputMessage{gina.GetMessageChan(), importedHank.GetMessageChan()} {
+		receivedSenderKey, receivedRemoval, receivedReAddition := false, false, false
+		for i := 0; i < 3; i++ {
+			msg, success = timeOutReadFromMessageChannel(c)
+			assert.True(t, success, "Should receive the sender key, the removal and the re-addition")
+			if msg.MessageType == pb.MessageType_SENDER_KEY_DISTRIBUTION_MESSAGE {
+				receivedSenderKey = true
+				continue
+			}
+			receivedRemoval = receivedRemoval || msg.EventType == pb.ServerEventType_GROUP_REMOVAL
+			receivedReAddition = receivedReAddition || msg.EventType == pb.ServerEventType_GROUP_ADDITION || msg.EventType == pb.ServerEventType_GROUP_INVITATION
+		}
+		assert.True(t, receivedSenderKey, "Should receive a sender key distribution message")
+		assert.True(t, receivedRemoval, "Should receive a GROUP_REMOVAL event")
+		assert.True(t, receivedReAddition, "Should receive the re-addition to the MLS group")
+	}
+
+	// Gina and the imported Hank talk again in both groups.
+	err = importedHank.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Back from my backup, server-side group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "The imported Hank should be able to send a message to the server-side group")
+	err = importedHank.SendMlsGroupMessage(ctx, mlsGroupId, []byte("Back from my backup, MLS group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "The imported Hank should be able to send a message to the MLS group")
+	for _, expected := range []string{"Back from my backup, server-side group!", "Back from my backup, MLS group!"} {
+		msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+		assert.True(t, success, "Gina should receive the message from the imported Hank")
+		assert.Equal(t, expected, string(msg.Message), "Gina should receive the message from the imported Hank")
+	}
+
+	err = gina.SendServerSideGroupMessage(ctx, serverSideGroupId, []byte("Welcome back, server-side group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Gina should be able to send a message to the server-side group")
+	err = gina.SendMlsGroupMessage(ctx, mlsGroupId, []byte("Welcome back, MLS group!"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Gina should be able to send a message to the MLS group")
+	for _, expected := range []string{"Welcome back, server-side group!", "Welcome back, MLS group!"} {
+		msg, success = timeOutReadFromMessageChannel(importedHank.GetMessageChan())
+		assert.True(t, success, "The imported Hank should receive the message from Gina")
+		assert.Equal(t, expected, string(msg.Message), "The imported Hank should receive the message from Gina")
+	}
+
+	ginaMlsSessionDriver, _ := gina.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	hankMlsSessionDriver, _ := importedHank.Client.GetMlsGroupSessionDriver(mlsGroupId)
+	assert.True(t, ginaMlsSessionDriver.GetGroupState().Equals(*hankMlsSessionDriver.GetGroupState()), "Gina and the imported Hank should have the same MLS group state")
+}
+
+func TestRepeatedGroupRejoinRequest(t *testing.T) {
+	ctx := context.Background()
+	gina := createClientSideUserWithRandomUserID("gina")
+	hank := createClientSideUserWithRandomUserID("hank")
+
+	groupId, err := gina.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Gina should be able to create an MLS group")
+	gina.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, hank.GetUserID())
+	msg, success := timeOutReadFromMessageChannel(hank.GetMessageChan())
+	assert.True(t, success, "Hank should receive a group invitation from Gina")
+	assert.Equal(t, pb.ServerEventType_GROUP_INVITATION, msg.EventType, "Hank should receive a group invitation from Gina")
+	msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+	assert.True(t, success, "Gina should receive a group addition event")
+	assert.Equal(t, pb.ServerEventType_GROUP_ADDITION, msg.EventType, "Gina should receive a group addition event")
+
+	// Hank asks twice to rejoin before Gina handles his removal; Gina removes and re-adds him once.
+	groupRejoinRequest, err := proto.Marshal(&pb.GroupRejoinRequest{GroupID: groupId})
+	assert.Nil(t, err, "The group rejoin request should be marshalled")
+	gina.mutex.Lock()
+	for i := 0; i < 2; i++ {
+		err = gina.HandleGroupRejoinRequest(ctx, groupRejoinRequest, hank.GetUserID())
+		assert.Nil(t, err, "Gina should answer the group rejoin request")
+	}
+	gina.mutex.Unlock()
+
+	for _, expected := range []pb.ServerEventType{pb.ServerEventType_GROUP_REMOVAL, pb.ServerEventType_GROUP_INVITATION} {
+		msg, success = timeOutReadFromMessageChannel(hank.GetMessageChan())
+		assert.True(t, success, "Hank should be removed and re-added")
+		assert.Equal(t, expected, msg.EventType, "Hank should be removed and re-added")
+	}
+	for _, expected := range []pb.ServerEventType{pb.ServerEventType_GROUP_REMOVAL, pb.ServerEventType_GROUP_ADDITION} {
+		msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+		assert.True(t, success, "Gina should remove and re-add Hank")
+		assert.Equal(t, expected, msg.EventType, "Gina should remove and re-add Hank")
+	}
+	_, success = timeOutReadFromMessageChannel(hank.GetMessageChan())
+	assert.False(t, success, "Hank should not be removed again")
+	select {
+	case err = <-gina.GetErrorChan():
+		assert.Fail(t, "Gina should handle the repeated request without errors", err)
+	default:
+	}
+
+	err = hank.SendMlsGroupMessage(ctx, groupId, []byte("Back again."), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Hank should be able to send a message to the group")
+	msg, success = timeOutReadFromMessageChannel(gina.GetMessageChan())
+	assert.True(t, success, "Gina should receive the message from Hank")
+	assert.Equal(t, "Back again.", string(msg.Message), "Gina should receive the message from Hank")
+}
+
+func TestMessageHistory(t *testing.T) {
+	ctx := context.Background()
+	ivy := createClientSideUserWithRandomUserID("ivy")
+	jack := createClientSideUserWithRandomUserID("jack")
+	historyPath := filepath.Join(t.TempDir(), "jack.history")
+	err := jack.SetMessageHistoryFile(historyPath, []byte("jack's passphrase"))
+	assert.Nil(t, err, "Jack should be able to set his history file")
+
+	// Ivy and Jack talk individually and in an MLS group.
+	err = ivy.SendIndividualMessage(ctx, protocol.NewSignalAddress(jack.GetUserID(), 1), []byte("The weather is nice today."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Ivy should be able to send message to Jack")
+	_, success := timeOutReadFromMessageChannel(jack.GetMessageChan())
+	assert.True(t, success, "Jack should receive a message from Ivy")
+	err = jack.SendIndividualMessage(ctx, protocol.NewSignalAddress(ivy.GetUserID(), 1), []byte("Indeed, sunny WEATHER."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Jack should be able to send message to Ivy")
+	_, success = timeOutReadFromMessageChannel(ivy.GetMessageChan())
+	assert.True(t, success, "Ivy should receive a message from Jack")
+
+	groupId, err := ivy.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Ivy should be able to create an MLS group")
+	ivy.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, jack.GetUserID())
+	_, success = timeOutReadFromMessageChannel(jack.GetMessageChan())
+	assert.True(t, success, "Jack should receive a group invitation from Ivy")
+	_, success = timeOutReadFromMessageChannel(ivy.GetMessageChan())
+	assert.True(t, success, "Ivy should receive a group addition event")
+	for i := 1; i <= 3; i++ {
+		err = ivy.SendMlsGroupMessage(ctx, groupId, []byte(fmt.Sprintf("Group message %v", i)), pb.MessageType_TEXT_MESSAGE, nil, false)
+		assert.Nil(t, err, "Ivy should be able to send a message to the group")
+		_, success = timeOutReadFromMessageChannel(jack.GetMessageChan())
+		assert.True(t, success, "Jack should receive a message from Ivy")
+	}
+
+	// The individual conversation holds the messages of both, in order.
+	entries := jack.GetMessageHistory(stores.HistoryQuery{GroupID: ivy.GetUserID()})
+	assert.Equal(t, 2, len(entries), "Jack should keep both individual messages")
+	assert.Equal(t, ivy.GetUserID(), entries[0].GetSenderID(), "The first message should come from Ivy")
+	assert.Equal(t, jack.GetUserID(), entries[1].GetSenderID(), "The second message should come from Jack")
+	assert.NotEqual(t, entries[0].GetMessageID(), entries[1].GetMessageID(), "The messages should have distinct IDs")
+	assert.LessOrEqual(t, entries[0].GetTimestamp(), entries[1].GetTimestamp(), "The messages should be in order")
+
+	// The group history is read backwards page by page.
+	page := jack.GetMessageHistory(stores.HistoryQuery{GroupID: groupId, Limit: 2})
+	assert.Equal(t, 2, len(page), "The first page should hold two messages")
+	assert.Equal(t, "Group message 2", string(page[0].GetMessage()), "The first page should hold the latest messages")
+	assert.Equal(t, "Group message 3", string(page[1].GetMessage()), "The first page should hold the latest messages")
+	page = jack.GetMessageHistory(stores.HistoryQuery{GroupID: groupId, Limit: 2, BeforeMessageID: page[0].GetMessageID()})
+	assert.Equal(t, 1, len(page), "The second page should hold the oldest message")
+	assert.Equal(t, "Group message 1", string(page[0].GetMessage()), "The second page should hold the oldest message")
+	assert.Equal(t, 3, len(jack.GetMessageHistory(stores.HistoryQuery{SenderID: ivy.GetUserID(), GroupID: groupId})), "Ivy should have sent three group messages")
+
+	// Search ignores case and needs every word.
+	assert.Equal(t, 2, len(jack.SearchMessageHistory("weather", 0)), "Both individual messages mention the weather")
+	results := jack.SearchMessageHistory("sunny weather", 0)
+	assert.Equal(t, 1, len(results), "Only Jack's message mentions sunny weather")
+	assert.Equal(t, "Indeed, sunny WEATHER.", string(results[0].GetMessage()), "Only Jack's message mentions sunny weather")
+
+	// The history is encrypted at rest, appended to, and loaded back from the file.
+	raw, err := os.ReadFile(historyPath)
+	assert.Nil(t, err, "The history should be saved")
+	assert.False(t, bytes.Contains(raw, []byte("sunny")), "The history should be encrypted at rest")
+	err = jack.SendIndividualMessage(ctx, protocol.NewSignalAddress(ivy.GetUserID(), 1), []byte("See you tomorrow."), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Jack should be able to send message to Ivy")
+	_, success = timeOutReadFromMessageChannel(ivy.GetMessageChan())
+	assert.True(t, success, "Ivy should receive a message from Jack")
+	appended, err := os.ReadFile(historyPath)
+	assert.Nil(t, err, "The history should be saved")
+	assert.Greater(t, len(appended), len(raw), "The message should be added to the history")
+	assert.Equal(t, raw, appended[:len(raw)], "The message should be appended without rewriting the history")
+	err = stores.NewMessageHistory().SetFile(historyPath, []byte("wrong passphrase"))
+	assert.ErrorIs(t, err, stores.ErrWrongPassphrase, "The history should not be loaded with a wrong passphrase")
+	loadedHistory := stores.NewMessageHistory()
+	err = loadedHistory.SetFile(historyPath, []byte("jack's passphrase"))
+	assert.Nil(t, err, "The history should be loaded from the file")
+	assert.Equal(t, jack.history.Entries(), loadedHistory.Entries(), "The loaded history should be the same")
+
+	// The history can be carried over in a backup.
+	recoveryCode, err := stores.GenerateRecoveryCode()
+	assert.Nil(t, err, "Should be able to generate a recovery code")
+	backup, err := jack.ExportBackup(recoveryCode, true)
+	assert.Nil(t, err, "Jack should be able to export a backup with his history")
+	restoredJack, err := openBackup(backup, recoveryCode)
+	assert.Nil(t, err, "Jack should be able to open his backup")
+	assert.Equal(t, 6, len(restoredJack.GetMessageHistory(stores.HistoryQuery{})), "The backup should hold the history")
+
+	// Messages older than the retention are deleted.
+	err = jack.history.AppendAll([]*pb.HistoryEntry{{
+		MessageID: "old",
+		GroupID:   groupId,
+		SenderID:  ivy.GetUserID(),
+		Timestamp: time.Now().Add(-time.Hour).UnixMilli(),
+		Message:   []byte("An old weather report."),
+	}})
+	assert.Nil(t, err, "Should be able to add an old message")
+	assert.Equal(t, 3, len(jack.SearchMessageHistory("weather", 0)), "The old message should be kept without retention")
+	err = jack.SetMessageHistoryRetention(time.Minute)
+	assert.Nil(t, err, "Jack should be able to set a retention")
+	assert.Equal(t, 2, len(jack.SearchMessageHistory("weather", 0)), "The old message should be deleted")
+	assert.Equal(t, 6, len(jack.GetMessageHistory(stores.HistoryQuery{})), "The recent messages should be kept")
+	loadedHistory = stores.NewMessageHistory()
+	err = loadedHistory.SetFile(historyPath, []byte("jack's passphrase"))
+	assert.Nil(t, err, "The history should be loaded from the file")
+	assert.Equal(t, jack.history.Entries(), loadedHistory.Entries(), "The deleted message should be gone from the file")
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	mia := createClientSideUserWithRandomUserID("mia")
+	noah := createClientSideUserWithRandomUserID("noah")
+
+	groupId, err := mia.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Mia should be able to create an MLS group")
+	mia.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, noah.GetUserID())
+	_, success := timeOutReadFromMessageChannel(noah.GetMessageChan())
+	assert.True(t, success, "Noah should receive a group invitation from Mia")
+	_, success = timeOutReadFromMessageChannel(mia.GetMessageChan())
+	assert.True(t, success, "Mia should receive a group addition event")
+
+	// A message whose sending fails is kept in the outbox with its state.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = mia.SendMlsGroupMessage(cancelledCtx, groupId, []byte("Queued message"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.ErrorIs(t, err, ErrMessageQueued, "Mia's message should be queued")
+	assert.Equal(t, codes.Canceled, status.Code(err), "The queued message should keep the RPC error")
+	entry, queued := mia.outbox[groupId]
+	assert.True(t, queued, "Mia's outbox should hold the message")
+	assert.NotEmpty(t, entry.stored.GetMessage().GetMessageID(), "The queued message should have a MessageID")
+	stored, err := mia.export()
+	assert.Nil(t, err, "Mia should be able to export her state")
+	assert.Equal(t, 1, len(stored.GetOutbox()), "The outbox should be persisted")
+
+	// No other message is issued on top of it until it is sent.
+	err = mia.SendMlsGroupMessage(cancelledCtx, groupId, []byte("Blocked message"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.ErrorIs(t, err, ErrOutboxPending, "Mia's next message should wait for the outbox")
+	assert.False(t, errors.Is(err, ErrMessageQueued), "Mia's next message should not be queued")
+
+	err = mia.FlushOutbox(ctx)
+	assert.Nil(t, err, "Mia should be able to flush her outbox")
+	assert.Equal(t, 0, len(mia.outbox), "Mia's outbox should be empty")
+	msg, success := timeOutReadFromMessageChannel(noah.GetMessageChan())
+	assert.True(t, success, "Noah should receive the queued message")
+	assert.Equal(t, "Queued message", string(msg.Message), "Noah should receive the queued message")
+	assert.Equal(t, 1, len(mia.GetMessageHistory(stores.HistoryQuery{GroupID: groupId})), "The flushed message should be in Mia's history")
+
+	// The server does not fan out a message sent again.
+	err = mia.Client.ResendGroupMessage(ctx, groupId, pb.GroupType_MLS, entry.stored.GetMessage())
+	assert.Nil(t, err, "The server should accept a message sent again")
+	err = mia.SendMlsGroupMessage(ctx, groupId, []byte("Next message"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Mia should be able to send a message after the outbox is flushed")
+	msg, success = timeOutReadFromMessageChannel(noah.GetMessageChan())
+	assert.True(t, success, "Noah should receive the next message")
+	assert.Equal(t, "Next message", string(msg.Message), "Noah should not receive the queued message twice")
+	select {
+	case err := <-noah.GetErrorChan():
+		assert.Fail(t, "Noah should not handle the queued message twice", err)
+	default:
+	}
+
+	assert.Nil(t, mia.Deactivate(ctx), "Mia should be able to deactivate")
+	assert.Nil(t, noah.Deactivate(ctx), "Noah should be able to deactivate")
+}
+
+func TestReplayRejection(t *testing.T) {
+	ctx := context.Background()
+	olivia := createClientSideUserWithRandomUserID("olivia")
+	paul := createClientSideUserWithRandomUserID("paul")
+
+	err := olivia.SendIndividualMessage(ctx, protocol.NewSignalAddress(paul.GetUserID(), 1), []byte("Hello Paul!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Olivia should be able to send message to Paul")
+	msg, success := timeOutReadFromMessageChannel(paul.GetMessageChan())
+	assert.True(t, success, "Paul should receive a message from Olivia")
+	assert.NotEmpty(t, msg.MessageID, "The message should have a MessageID")
+
+	// The message delivered again is dropped before it reaches the ratchet.
+	replayed := &pb.MessageWrapper{SenderID: olivia.GetUserID(), RecipientID: paul.GetUserID(), MessageID: msg.MessageID}
+	_, err = paul.ParseMessageWrapper(ctx, replayed)
+	assert.ErrorIs(t, err, ErrReplayedMessage, "Paul should reject the message delivered again")
+
+	// The received messages are remembered across restarts.
+	paul.mutex.Lock()
+	stored, err := paul.export()
+	paul.mutex.Unlock()
+	assert.Nil(t, err, "Paul should be able to export his state")
+	restoredPaul, err := restoreClientSideUser(stored)
+	assert.Nil(t, err, "Paul should be able to restore his state")
+	_, err = restoredPaul.ParseMessageWrapper(ctx, replayed)
+	assert.ErrorIs(t, err, ErrReplayedMessage, "The restored Paul should reject the message delivered again")
+
+	// A message without a MessageID, or relabelled with another one to get past the replay window, is rejected.
+	_, err = paul.ParseMessageWrapper(ctx, &pb.MessageWrapper{SenderID: olivia.GetUserID(), RecipientID: paul.GetUserID()})
+	assert.ErrorIs(t, err, ErrInvalidMessageID, "Paul should reject a message without a MessageID")
+	relabelled, err := olivia.SendIndividualMessageBenchmark(ctx, protocol.NewSignalAddress(paul.GetUserID(), 1), []byte("Relabelled"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Olivia should be able to encrypt a message to Paul")
+	relabelled.MessageID = "relabelled"
+	_, err = paul.ParseMessageWrapper(ctx, relabelled)
+	assert.ErrorIs(t, err, ErrInvalidMessageID, "Paul should reject a message relabelled with another MessageID")
+
+	// The next message still goes through.
+	err = olivia.SendIndividualMessage(ctx, protocol.NewSignalAddress(paul.GetUserID(), 1), []byte("Hello again Paul!"), pb.MessageType_TEXT_MESSAGE)
+	assert.Nil(t, err, "Olivia should be able to send message to Paul")
+	next, success := timeOutReadFromMessageChannel(paul.GetMessageChan())
+	assert.True(t, success, "Paul should receive the next message from Olivia")
+	assert.Equal(t, "Hello again Paul!", string(next.Message), "Paul should receive the next message from Olivia")
+	assert.NotEqual(t, msg.MessageID, next.MessageID, "The messages should have distinct MessageIDs")
+
+	assert.Nil(t, olivia.Deactivate(ctx), "Olivia should be able to deactivate")
+	assert.Nil(t, paul.Deactivate(ctx), "Paul should be able to deactivate")
+}
+
+func TestReceipts(t *testing.T) {
+	ctx := context.Background()
+	quinn := createClientSideUserWithRandomUserID("quinn")
+	rose := createClientSideUserWithRandomUserID("rose")
+	sam := createClientSideUserWithRandomUserID("sam")
+
+	groupId, err := quinn.CreateGroup(ctx, pb.GroupType_MLS)
+	assert.Nil(t, err, "Quinn should be able to create an MLS group")
+	quinn.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, rose.GetUserID())
+	_, success := timeOutReadFromMessageChannel(rose.GetMessageChan())
+	assert.True(t, success, "Rose should receive a group invitation from Quinn")
+	_, success = timeOutReadFromMessageChannel(quinn.GetMessageChan())
+	assert.True(t, success, "Quinn should receive a group addition event")
+	quinn.RequestInviteUserToGroup(ctx, groupId, pb.GroupType_MLS, sam.GetUserID())
+	_, success = timeOutReadFromMessageChannel(sam.GetMessageChan())
+	assert.True(t, success, "Sam should receive a group invitation from Quinn")
+	for _, c := range []<-chan OutputMessage{quinn.GetMessageChan(), rose.GetMessageChan()} {
+		_, success = timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Quinn and Rose should receive a group addition event")
+	}
+
+	// Only Rose acknowledges the messages she receives.
+	rose.SetDeliveryReceipts(true)
+	err = quinn.SendMlsGroupMessage(ctx, groupId, []byte("Did you get this?"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Quinn should be able to send a message to the group")
+	var msg OutputMessage
+	for _, c := range []<-chan OutputMessage{rose.GetMessageChan(), sam.GetMessageChan()} {
+		msg, success = timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Rose and Sam should receive the message from Quinn")
+		assert.Equal(t, "Did you get this?", string(msg.Message), "Rose and Sam should receive the message from Quinn")
+	}
+	history := quinn.GetMessageHistory(stores.HistoryQuery{GroupID: groupId})
+	assert.Equal(t, 1, len(history), "The message should be in Quinn's history")
+	assert.Equal(t, msg.MessageID, history[0].GetMessageID(), "Quinn's history should keep the MessageID the members received")
+
+	statuses, tracked := quinn.GetMessageReceipts(msg.MessageID)
+	assert.True(t, tracked, "Quinn's message should be tracked")
+	assert.Equal(t, 2, len(statuses), "Quinn's message should be tracked for Rose and Sam")
+	assert.Eventually(t, func() bool {
+		statuses, _ := quinn.GetMessageReceipts(msg.MessageID)
+		return statuses[rose.GetUserID()] == ReceiptDelivered
+	}, 5*time.Second, 50*time.Millisecond, "Rose's delivery receipt should reach Quinn")
+	statuses, _ = quinn.GetMessageReceipts(msg.MessageID)
+	assert.Equal(t, ReceiptSent, statuses[sam.GetUserID()], "Sam should not acknowledge the message")
+
+	// A read receipt is sent on request, and only for the messages received from the group.
+	err = sam.SendReadReceipts(ctx, groupId, msg.MessageID, "unknown")
+	assert.Nil(t, err, "Sam should be able to send a read receipt")
+	assert.Eventually(t, func() bool {
+		statuses, _ := quinn.GetMessageReceipts(msg.MessageID)
+		return statuses[sam.GetUserID()] == ReceiptRead
+	}, 5*time.Second, 50*time.Millisecond, "Sam's read receipt should reach Quinn")
+	assert.Nil(t, sam.SendReadReceipts(ctx, groupId, "unknown"), "A read receipt for no received message should not be sent")
+
+	// The receipts are not output, and the receipts for messages of others change nothing.
+	err = rose.SendMlsGroupMessage(ctx, groupId, []byte("Got it"), pb.MessageType_TEXT_MESSAGE, nil, false)
+	assert.Nil(t, err, "Rose should be able to send a message to the group")
+	for _, c := range []<-chan OutputMessage{quinn.GetMessageChan(), sam.GetMessageChan()} {
+		msg, success := timeOutReadFromMessageChannel(c)
+		assert.True(t, success, "Quinn and Sam should receive the message from Rose")
+		assert.Equal(t, "Got it", string(msg.Message), "Quinn and Sam should not receive the receipts")
+	}
+	_, tracked = quinn.GetMessageReceipts("unknown")
+	assert.False(t, tracked, "A message Quinn did not send should not be tracked")
+
+	assert.Nil(t, quinn.Deactivate(ctx), "Quinn should be able to deactivate")
+	assert.Nil(t, rose.Deactivate(ctx), "Rose should be able to deactivate")
+	assert.Nil(t, sam.Deactivate(ctx), "Sam should be able to deactivate")
+}
+
 func dialer() func(context.Context, string) (net.Conn, error) {
 	listener = bufconn.Listen(bufSize)
 	s := grpc.NewServer()
@@ -646,9 +1434,10 @@ func dialer() func(context.Context, string) (net.Conn, error) {
 
 // createClientSideUserWithRandomUserID create a client side user with a random user ID.
 func createClientSideUserWithRandomUserID(prefix string) *ClientSideUser {
+	ctx := context.Background()
 	userID := prefix + randomString(8)
 	//user, _ := NewClientSideUser(userID, "localhost:50051", true)
-	user := NewClientSideUserBufconn(userID, dialer(), true)
+	user := NewClientSideUserBufconn(ctx, userID, dialer(), true)
 	return user
 }
 
@@ -674,6 +1463,57 @@ func timeOutReadFromMessageChannel(ch <-chan OutputMessage) (OutputMessage, bool
 	}
 }
 
+// timeOutReadFromErrorChannel read from the given channel and return false if it times out.
+func timeOutReadFromErrorChannel(ch <-chan error) (error, bool) {
+	select {
+	case err := <-ch:
+		return err, true
+	case <-time.After(5 * time.Second):
+		return nil, false
+	}
+}
+
+// goroutines returns the stacks of the running goroutines by their ID.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		stacks[strings.Fields(stack)[1]] = stack
+	}
+	return stacks
+}
+
+// assertNoLeakedGoroutines fails the test if goroutines that were not running before are still running once they had
+// time to exit.
+func assertNoLeakedGoroutines(t *testing.T, before map[string]string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var leaked []string
+		for id, stack := range goroutines() {
+			if _, exists := before[id]; !exists {
+				leaked = append(leaked, stack)
+			}
+		}
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("%v goroutines leaked:\n\n%v", len(leaked), strings.Join(leaked, "\n\n"))
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
 func treekemNodeEqual(n1, n2 *treekem.Node) bool {
 	return string(n1.Public) == string(n2.Public) && string(n1.SignPublic) == string(n2.SignPublic)
 }
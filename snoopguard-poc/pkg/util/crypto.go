@@ -9,6 +9,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	syntax "github.com/cisco/go-tls-syntax"
 	"github.com/s3131212/go-mls"
 	"io"
@@ -25,40 +26,66 @@ type CipherText struct {
 }
 
 /*
-Encrypt encrypts the plaintext with the given key using AES-256 GCM
+MessageSuite is the set of primitives used to seal a message under a shared key and sign it. The cipher suites of the
+CMRT implement it.
 */
-func Encrypt(plaintext []byte, key []byte, signPrivKey []byte) (CipherText, error) {
-	// Create new AES cipher
+type MessageSuite interface {
+	NewAEAD(key []byte) (cipher.AEAD, error)
+	Sign(message []byte, signPrivKey []byte) ([]byte, error)
+	Verify(message []byte, signature []byte, signPubKey []byte) bool
+}
+
+/*
+p256MessageSuite is AES-GCM with P-256 ECDSA signatures over SHA-256.
+*/
+type p256MessageSuite struct{}
+
+func (p256MessageSuite) NewAEAD(key []byte) (cipher.AEAD, error) {
 	aesCipher, err := aes.NewCipher(key)
 	if err != nil {
-		return CipherText{}, err
+		return nil, err
 	}
+	return cipher.NewGCM(aesCipher)
+}
+
+func (p256MessageSuite) Sign(message []byte, signPrivKey []byte) ([]byte, error) {
+	return Sign(message, signPrivKey)
+}
+
+func (p256MessageSuite) Verify(message []byte, signature []byte, signPubKey []byte) bool {
+	return Verify(message, signature, signPubKey)
+}
+
+/*
+Encrypt encrypts the plaintext with the given key using AES-256 GCM
+*/
+func Encrypt(plaintext []byte, key []byte, signPrivKey []byte) (CipherText, error) {
+	return EncryptWithSuite(p256MessageSuite{}, plaintext, key, signPrivKey)
+}
 
-	// Create new GCM cipher
-	aesGCM, err := cipher.NewGCM(aesCipher)
+/*
+EncryptWithSuite encrypts the plaintext with the given key using the AEAD of the suite, and signs it with the signature
+scheme of the suite if signPrivKey is given.
+*/
+func EncryptWithSuite(suite MessageSuite, plaintext []byte, key []byte, signPrivKey []byte) (CipherText, error) {
+	aead, err := suite.NewAEAD(key)
 	if err != nil {
 		return CipherText{}, err
 	}
 
 	// Create new IV
-	iv := make([]byte, aesGCM.NonceSize())
+	iv := make([]byte, aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
 		return CipherText{}, err
 	}
 
 	// Encrypt the plaintext
-	ciphertext := aesGCM.Seal(nil, iv, plaintext, nil)
+	ciphertext := aead.Seal(nil, iv, plaintext, nil)
 
 	// Signature
 	var sig []byte
 	if signPrivKey != nil {
-		ecdhKey, err := ecdh.P256().NewPrivateKey(signPrivKey)
-		if err != nil {
-			return CipherText{}, err
-		}
-		ecdsaKey := ecdhToECDSAPrivateKey(ecdhKey)
-		hash := sha256.Sum256(ciphertext)
-		sig, err = ecdsa.SignASN1(rand.Reader, ecdsaKey, hash[:])
+		sig, err = suite.Sign(ciphertext, signPrivKey)
 		if err != nil {
 			return CipherText{}, err
 		}
@@ -75,42 +102,36 @@ func Encrypt(plaintext []byte, key []byte, signPrivKey []byte) (CipherText, erro
 Decrypt decrypts the ciphertext with the given key using AES-256 GCM
 */
 func Decrypt(ciphertext CipherText, key []byte, signPubKey []byte) ([]byte, error) {
+	return DecryptWithSuite(p256MessageSuite{}, ciphertext, key, signPubKey)
+}
+
+// ErrBadSignature is returned when a message is not signed by the expected key.
+var ErrBadSignature = errors.New("bad signature")
+
+/*
+DecryptWithSuite decrypts the ciphertext with the given key using the AEAD of the suite, after verifying its signature
+with the signature scheme of the suite if signPubKey is given.
+*/
+func DecryptWithSuite(suite MessageSuite, ciphertext CipherText, key []byte, signPubKey []byte) ([]byte, error) {
 	if len(ciphertext.Signature) == 0 && signPubKey != nil {
-		return nil, errors.New("signature is missing")
+		return nil, fmt.Errorf("%w: signature is missing", ErrBadSignature)
 	}
 
 	if len(ciphertext.Signature) != 0 && signPubKey == nil {
-		return nil, errors.New("public key is missing")
+		return nil, fmt.Errorf("%w: public key is missing", ErrBadSignature)
 	}
 
-	if signPubKey != nil {
-		ecdhKey, err := ecdh.P256().NewPublicKey(signPubKey)
-		if err != nil {
-			return nil, err
-		}
-		ecdsaPubKey := ecdhToECDSAPublicKey(ecdhKey)
-		hash := sha256.Sum256(ciphertext.CipherText)
-		valid := ecdsa.VerifyASN1(ecdsaPubKey, hash[:], ciphertext.Signature)
-
-		if !valid {
-			return nil, errors.New("signature is not valid")
-		}
+	if signPubKey != nil && !suite.Verify(ciphertext.CipherText, ciphertext.Signature, signPubKey) {
+		return nil, fmt.Errorf("%w: signature is not valid", ErrBadSignature)
 	}
 
-	// Create new AES cipher
-	aesCipher, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create new GCM cipher
-	aesGCM, err := cipher.NewGCM(aesCipher)
+	aead, err := suite.NewAEAD(key)
 	if err != nil {
 		return nil, err
 	}
 
 	// Decrypt the ciphertext
-	plaintext, err := aesGCM.Open(nil, ciphertext.IV, ciphertext.CipherText, nil)
+	plaintext, err := aead.Open(nil, ciphertext.IV, ciphertext.CipherText, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +159,19 @@ func Sign(message []byte, signPrivKey []byte) ([]byte, error) {
 	return sig, nil
 }
 
+/*
+Verify verifies the signature of the message with the given public key
+*/
+func Verify(message []byte, signature []byte, signPubKey []byte) bool {
+	ecdhKey, err := ecdh.P256().NewPublicKey(signPubKey)
+	if err != nil {
+		return false
+	}
+	ecdsaPubKey := ecdhToECDSAPublicKey(ecdhKey)
+	hash := sha256.Sum256(message)
+	return ecdsa.VerifyASN1(ecdsaPubKey, hash[:], signature)
+}
+
 func (ct CipherText) Serialize() []byte {
 	sig := make([]byte, 73)
 	sigLen := len(ct.Signature)
@@ -258,3 +292,80 @@ func DeserializeMLSCiphertext(data []byte) (mls.MLSCiphertext, error) {
 
 	return ct, nil
 }
+
+// mlsStatePublic is the public part of an MLS state. The state itself cannot be marshalled directly, as it carries
+// bookkeeping maps the TLS syntax does not support.
+type mlsStatePublic struct {
+	CipherSuite             mls.CipherSuite
+	GroupID                 []byte `tls:"head=1"`
+	Epoch                   mls.Epoch
+	Tree                    mls.TreeKEMPublicKey
+	ConfirmedTranscriptHash []byte `tls:"head=1"`
+	InterimTranscriptHash   []byte `tls:"head=1"`
+	Extensions              mls.ExtensionList
+}
+
+// MLS State Serialization. The public state and the secrets are returned separately, so that the caller can decide
+// how to protect the latter.
+func SerializeMLSState(state *mls.State) ([]byte, []byte, error) {
+	public, err := syntax.Marshal(mlsStatePublic{
+		CipherSuite:             state.CipherSuite,
+		GroupID:                 state.GroupID,
+		Epoch:                   state.Epoch,
+		Tree:                    state.Tree,
+		ConfirmedTranscriptHash: state.ConfirmedTranscriptHash,
+		InterimTranscriptHash:   state.InterimTranscriptHash,
+		Extensions:              state.Extensions,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secrets, err := syntax.Marshal(state.GetSecrets())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return public, secrets, nil
+}
+
+// MLS State Deserialization
+func DeserializeMLSState(public []byte, secrets []byte) (*mls.State, error) {
+	var pub mlsStatePublic
+	if _, err := syntax.Unmarshal(public, &pub); err != nil {
+		return nil, err
+	}
+	var sec mls.StateSecrets
+	if _, err := syntax.Unmarshal(secrets, &sec); err != nil {
+		return nil, err
+	}
+
+	state := &mls.State{
+		CipherSuite:             pub.CipherSuite,
+		GroupID:                 pub.GroupID,
+		Epoch:                   pub.Epoch,
+		Tree:                    pub.Tree,
+		ConfirmedTranscriptHash: pub.ConfirmedTranscriptHash,
+		InterimTranscriptHash:   pub.InterimTranscriptHash,
+		Extensions:              pub.Extensions,
+		NewCredentials:          map[mls.LeafIndex]bool{},
+	}
+	state.SetSecrets(sec)
+	state.Tree.Suite = state.CipherSuite
+
+	// The key schedule is serialized with its base keys and ratchets, but go-mls only builds the key sources on top of
+	// them, which protect and unprotect the messages, when it enters an epoch. They are rebuilt here.
+	handshakeKeys := newOf(state.Keys.HandshakeKeys)
+	handshakeKeys.Base, handshakeKeys.Ratchets = state.Keys.HandshakeBaseKeys, state.Keys.HandshakeRatchets
+	state.Keys.HandshakeKeys = handshakeKeys
+	applicationKeys := newOf(state.Keys.ApplicationKeys)
+	applicationKeys.Base, applicationKeys.Ratchets = state.Keys.ApplicationBaseKeys, state.Keys.ApplicationRatchets
+	state.Keys.ApplicationKeys = applicationKeys
+
+	return state, nil
+}
+
+// newOf returns a new zero value of the type the given pointer points to, for types go-mls does not export.
+func newOf[T any](_ *T) *T {
+	return new(T)
+}
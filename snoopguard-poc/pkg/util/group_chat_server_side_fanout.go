@@ -55,3 +55,20 @@ func (gcssf *GroupChatServerSideFanout) GetReceivingGroupSession(participantID s
 func (gcssf *GroupChatServerSideFanout) RemoveReceivingGroupSession(participantID string) {
 	delete(gcssf.participantIdToSession, participantID)
 }
+
+// GetReceivingParticipantIDs returns the participants whose sender keys were received.
+func (gcssf *GroupChatServerSideFanout) GetReceivingParticipantIDs() []string {
+	participantIDs := make([]string, 0, len(gcssf.participantIdToSession))
+	for participantID := range gcssf.participantIdToSession {
+		participantIDs = append(participantIDs, participantID)
+	}
+	return participantIDs
+}
+
+// RestoreReceivingGroupSession recreates the receiving session of a participant whose sender key is already in the
+// sender key store of the user, e.g. after the user was restored.
+func (gcssf *GroupChatServerSideFanout) RestoreReceivingGroupSession(participantID string) *GroupSessionWrapper {
+	senderKeyName := protocol.NewSenderKeyName(gcssf.groupId, protocol.NewSignalAddress(participantID, 1))
+	gcssf.participantIdToSession[participantID] = NewGroupSessionWrapper(gcssf.selfUser, senderKeyName, gcssf.selfUser.Serializer)
+	return gcssf.participantIdToSession[participantID]
+}
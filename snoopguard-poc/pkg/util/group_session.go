@@ -2,6 +2,7 @@ package util
 
 import (
 	"go.mau.fi/libsignal/groups"
+	"go.mau.fi/libsignal/groups/state/record"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/libsignal/protocol"
 	"go.mau.fi/libsignal/serialize"
@@ -55,6 +56,13 @@ func (gsw *GroupSessionWrapper) DistributeSenderKey() *protocol.SenderKeyDistrib
 	return distributionMessage
 }
 
+// RotateSenderKey replaces the own sender key with a fresh one and returns its distribution message. Receivers that do
+// not get the new sender key cannot decrypt the messages encrypted afterwards.
+func (gsw *GroupSessionWrapper) RotateSenderKey() *protocol.SenderKeyDistributionMessage {
+	gsw.selfUser.senderKeyStore.StoreSenderKey(gsw.senderKeyName, record.NewSenderKey(gsw.serializer.SenderKeyRecord, gsw.serializer.SenderKeyState))
+	return gsw.DistributeSenderKey()
+}
+
 // EncryptGroupMessage is a helper function to send encrypted messages with the given cipher.
 func (gsw *GroupSessionWrapper) EncryptGroupMessage(message []byte) protocol.GroupCiphertextMessage {
 	logger.Debug("Encrypting message: ", string(message))
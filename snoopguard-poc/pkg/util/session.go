@@ -80,21 +80,34 @@ func (sw *SessionWrapper) DecryptMsg(message protocol.CiphertextMessage) ([]byte
 	}
 }
 
-func (sw *SessionWrapper) ParseRawMessage(rawMessage []byte, hasPreKey bool) protocol.CiphertextMessage {
+// RemoteIdentityKey returns the identity key of the remote party, as authenticated when the session was established,
+// or nil if no session has been established yet.
+func (sw *SessionWrapper) RemoteIdentityKey() *identity.Key {
+	if !sw.selfUser.sessionStore.ContainsSession(sw.address) {
+		return nil
+	}
+	state := sw.selfUser.sessionStore.LoadSession(sw.address).SessionState()
+	if state == nil {
+		return nil
+	}
+	return state.RemoteIdentityKey()
+}
+
+func (sw *SessionWrapper) ParseRawMessage(rawMessage []byte, hasPreKey bool) (protocol.CiphertextMessage, error) {
 	if hasPreKey {
 		encryptedMessage, err := protocol.NewPreKeySignalMessageFromBytes(rawMessage, sw.serializer.PreKeySignalMessage, sw.serializer.SignalMessage)
 		if err != nil {
 			logger.Error("Unable to restore message (with prekey) as JSON: ", err)
-			panic("")
+			return nil, err
 		}
-		return encryptedMessage
+		return encryptedMessage, nil
 	} else {
 		encryptedMessage, err := protocol.NewSignalMessageFromBytes(rawMessage, sw.serializer.SignalMessage)
 		if err != nil {
 			logger.Error("Unable to restore message (without prekey) as JSON: ", err)
-			panic("")
+			return nil, err
 		}
-		return encryptedMessage
+		return encryptedMessage, nil
 	}
 
 }
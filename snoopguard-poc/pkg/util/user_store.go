@@ -0,0 +1,162 @@
+package util
+
+import (
+	pb "chatbot-poc-go/pkg/protos/services"
+	"chatbot-poc-go/pkg/stores"
+	"fmt"
+	syntax "github.com/cisco/go-tls-syntax"
+	"go.mau.fi/libsignal/ecc"
+	"go.mau.fi/libsignal/keys/identity"
+	"go.mau.fi/libsignal/protocol"
+	"go.mau.fi/libsignal/serialize"
+	"go.mau.fi/libsignal/state/record"
+
+	groupRecord "go.mau.fi/libsignal/groups/state/record"
+)
+
+// StoredUserVersion is the version of the format Export writes.
+const StoredUserVersion = 1
+
+// Export returns the identity, prekeys, sessions, sender keys and MLS keys of the user, so that it can be restored with
+// RestoreUser.
+func (u *User) Export() (*pb.StoredUser, error) {
+	identityPrivate := u.identityKeyPair.PrivateKey().Serialize()
+	stored := &pb.StoredUser{
+		Version:            StoredUserVersion,
+		UserID:             u.UserID,
+		DeviceID:           u.deviceID,
+		IdentityKeyPublic:  u.identityKeyPair.PublicKey().Serialize(),
+		IdentityKeyPrivate: identityPrivate[:],
+		RegistrationID:     u.registrationID,
+		MlsInitialSecret:   u.mlsInitialSecret,
+		MlsKeyPackages:     make(map[uint32][]byte),
+	}
+
+	for _, preKey := range u.preKeyStore.LoadPreKeys() {
+		stored.PreKeys = append(stored.PreKeys, preKey.Serialize())
+	}
+	for _, signedPreKey := range u.signedPreKeyStore.LoadSignedPreKeys() {
+		stored.SignedPreKeys = append(stored.SignedPreKeys, signedPreKey.Serialize())
+	}
+	for address, session := range u.sessionStore.LoadSessions() {
+		stored.Sessions = append(stored.Sessions, &pb.StoredSignalRecord{
+			Name:     address.Name(),
+			DeviceID: address.DeviceID(),
+			Record:   session.Serialize(),
+		})
+	}
+	for address, identityKey := range u.identityStore.LoadTrustedIdentities() {
+		stored.TrustedIdentities = append(stored.TrustedIdentities, &pb.StoredSignalRecord{
+			Name:     address.Name(),
+			DeviceID: address.DeviceID(),
+			Record:   identityKey.Serialize(),
+		})
+	}
+	for senderKeyName, senderKey := range u.senderKeyStore.LoadSenderKeys() {
+		stored.SenderKeys = append(stored.SenderKeys, &pb.StoredSenderKey{
+			GroupID:  senderKeyName.GroupID(),
+			Name:     senderKeyName.Sender().Name(),
+			DeviceID: senderKeyName.Sender().DeviceID(),
+			Record:   senderKey.Serialize(),
+		})
+	}
+
+	var err error
+	if stored.MlsIdentityPrivateKey, err = syntax.Marshal(u.mlsIdentityPriv); err != nil {
+		return nil, fmt.Errorf("failed to serialize MLS identity key: %w", err)
+	}
+	if stored.MlsCredential, err = syntax.Marshal(u.mlsCredential); err != nil {
+		return nil, fmt.Errorf("failed to serialize MLS credential: %w", err)
+	}
+	for id, keyPackage := range u.mlsKeyPackageStore.LoadKeyPackages() {
+		if stored.MlsKeyPackages[id], err = SerializeMLSKeyPackage(keyPackage); err != nil {
+			return nil, fmt.Errorf("failed to serialize MLS key package %v: %w", id, err)
+		}
+	}
+
+	return stored, nil
+}
+
+// RestoreUser recreates a user exported with Export.
+func RestoreUser(stored *pb.StoredUser, serializer *serialize.Serializer) (*User, error) {
+	if stored.GetVersion() != StoredUserVersion {
+		return nil, fmt.Errorf("unsupported stored user version %v", stored.GetVersion())
+	}
+
+	identityPublic, err := ecc.DecodePoint(stored.GetIdentityKeyPublic(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode identity key: %w", err)
+	}
+	if len(stored.GetIdentityKeyPrivate()) != 32 {
+		return nil, fmt.Errorf("invalid identity private key")
+	}
+
+	user := &User{
+		UserID:           stored.GetUserID(),
+		deviceID:         stored.GetDeviceID(),
+		address:          protocol.NewSignalAddress(stored.GetUserID(), stored.GetDeviceID()),
+		identityKeyPair:  identity.NewKeyPair(identity.NewKey(identityPublic), ecc.NewDjbECPrivateKey([32]byte(stored.GetIdentityKeyPrivate()))),
+		registrationID:   stored.GetRegistrationID(),
+		Serializer:       serializer,
+		mlsInitialSecret: stored.GetMlsInitialSecret(),
+	}
+	user.sessionStore = stores.NewInMemorySessionStore(serializer)
+	user.preKeyStore = stores.NewInMemoryPreKeyStore()
+	user.signedPreKeyStore = stores.NewInMemorySignedPreKeyStore()
+	user.identityStore = stores.NewInMemoryIdentityKeyStore(user.identityKeyPair, user.registrationID)
+	user.senderKeyStore = stores.NewInMemorySenderKeyStore()
+	user.mlsKeyPackageStore = stores.NewInMemoryKeyPackageStore()
+
+	for _, serialized := range stored.GetPreKeys() {
+		preKey, err := record.NewPreKeyFromBytes(serialized, serializer.PreKeyRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore prekey: %w", err)
+		}
+		user.preKeyStore.StorePreKey(preKey.ID().Value, preKey)
+	}
+	for _, serialized := range stored.GetSignedPreKeys() {
+		signedPreKey, err := record.NewSignedPreKeyFromBytes(serialized, serializer.SignedPreKeyRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore signed prekey: %w", err)
+		}
+		user.signedPreKeyStore.StoreSignedPreKey(signedPreKey.ID(), signedPreKey)
+	}
+	for _, storedSession := range stored.GetSessions() {
+		session, err := record.NewSessionFromBytes(storedSession.GetRecord(), serializer.Session, serializer.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore session with %v: %w", storedSession.GetName(), err)
+		}
+		user.sessionStore.StoreSession(protocol.NewSignalAddress(storedSession.GetName(), storedSession.GetDeviceID()), session)
+	}
+	for _, storedIdentity := range stored.GetTrustedIdentities() {
+		identityKey, err := ecc.DecodePoint(storedIdentity.GetRecord(), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore identity of %v: %w", storedIdentity.GetName(), err)
+		}
+		user.identityStore.SaveIdentity(protocol.NewSignalAddress(storedIdentity.GetName(), storedIdentity.GetDeviceID()), identity.NewKey(identityKey))
+	}
+	for _, storedSenderKey := range stored.GetSenderKeys() {
+		senderKey, err := groupRecord.NewSenderKeyFromBytes(storedSenderKey.GetRecord(), serializer.SenderKeyRecord, serializer.SenderKeyState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore sender key of %v in %v: %w", storedSenderKey.GetName(), storedSenderKey.GetGroupID(), err)
+		}
+		senderKeyName := protocol.NewSenderKeyName(storedSenderKey.GetGroupID(), protocol.NewSignalAddress(storedSenderKey.GetName(), storedSenderKey.GetDeviceID()))
+		user.senderKeyStore.StoreSenderKey(senderKeyName, senderKey)
+	}
+
+	if _, err := syntax.Unmarshal(stored.GetMlsIdentityPrivateKey(), &user.mlsIdentityPriv); err != nil {
+		return nil, fmt.Errorf("failed to restore MLS identity key: %w", err)
+	}
+	if _, err := syntax.Unmarshal(stored.GetMlsCredential(), &user.mlsCredential); err != nil {
+		return nil, fmt.Errorf("failed to restore MLS credential: %w", err)
+	}
+	for id, serialized := range stored.GetMlsKeyPackages() {
+		keyPackage, err := DeserializeMLSKeyPackage(serialized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore MLS key package %v: %w", id, err)
+		}
+		user.mlsKeyPackageStore.StoreKeyPackage(id, keyPackage)
+	}
+
+	return user, nil
+}
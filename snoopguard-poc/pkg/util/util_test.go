@@ -343,6 +343,144 @@ func TestMLSSerialization(t *testing.T) {
 	}
 }
 
+func TestMLSStateSerialization(t *testing.T) {
+	serializer := serialize.NewProtoBufSerializer()
+	alice := NewUser("Alice", 1, serializer)
+	alice.GenerateMLSKeyPackage(1)
+	bob := NewUser("Bob", 1, serializer)
+	bob.GenerateMLSKeyPackage(1)
+
+	aliceState, err := alice.GenerateMLSStateFromEmpty(RandomBytes(32), alice.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+	add, err := aliceState.Add(bob.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+	_, err = aliceState.Handle(add)
+	require.Nil(t, err)
+	_, welcome, aliceState, err := aliceState.Commit(RandomBytes(32))
+	require.Nil(t, err)
+	bobState, err := bob.GenerateMLSStateFromWelcome(welcome, bob.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+
+	public, secrets, err := SerializeMLSState(bobState)
+	require.Nil(t, err)
+	restoredBobState, err := DeserializeMLSState(public, secrets)
+	require.Nil(t, err)
+	require.True(t, bobState.Equals(*restoredBobState), "the restored state should be the same")
+
+	/* The restored state decrypts the messages of the group */
+	testMessage := []byte("Hello, world!")
+	ct, err := aliceState.Protect(testMessage)
+	require.Nil(t, err)
+	pt, err := restoredBobState.Unprotect(ct)
+	require.Nil(t, err)
+	assert.Equal(t, testMessage, pt)
+
+	/* The restored state encrypts new messages in the epoch it was restored in */
+	reply := []byte("Hello, Alice!")
+	ct, err = restoredBobState.Protect(reply)
+	require.Nil(t, err)
+	pt, err = aliceState.Unprotect(ct)
+	require.Nil(t, err)
+	assert.Equal(t, reply, pt)
+
+	/* The ratchets are restored where they were, so the next messages are decrypted and encrypted after them */
+	public, secrets, err = SerializeMLSState(restoredBobState)
+	require.Nil(t, err)
+	restoredBobState, err = DeserializeMLSState(public, secrets)
+	require.Nil(t, err)
+	ct, err = aliceState.Protect(testMessage)
+	require.Nil(t, err)
+	pt, err = restoredBobState.Unprotect(ct)
+	require.Nil(t, err)
+	assert.Equal(t, testMessage, pt)
+	ct, err = restoredBobState.Protect(reply)
+	require.Nil(t, err)
+	pt, err = aliceState.Unprotect(ct)
+	require.Nil(t, err)
+	assert.Equal(t, reply, pt)
+
+	/* The restored state commits, and the group follows */
+	commit, _, restoredBobState, err := restoredBobState.Commit(RandomBytes(32))
+	require.Nil(t, err)
+	aliceState, err = aliceState.Handle(commit)
+	require.Nil(t, err)
+	require.True(t, aliceState.Equals(*restoredBobState))
+
+	ct, err = restoredBobState.Protect(testMessage)
+	require.Nil(t, err)
+	pt, err = aliceState.Unprotect(ct)
+	require.Nil(t, err)
+	assert.Equal(t, testMessage, pt)
+}
+
+func TestUserExportRestore(t *testing.T) {
+	serializer := serialize.NewProtoBufSerializer()
+	alice := NewUser("Alice", 1, serializer)
+	bob := NewUser("Bob", 1, serializer)
+
+	bobPreKeyID := bob.GeneratePreKey(0)
+	bobUnusedPreKeyID := bob.GeneratePreKey(1)
+	bobSignedPreKeyID := bob.GenerateSignedPreKey()
+	bob.GenerateMLSKeyPackage(1)
+
+	aliceSession := alice.CreateSessionWrapper(protocol.NewSignalAddress("Bob", 1), bob.GetPreKeyBundle(bobPreKeyID, bobSignedPreKeyID))
+	bobSession := bob.CreateSessionWrapper(protocol.NewSignalAddress("Alice", 1), nil)
+	bobReceiveAliceMsg1, err := bobSession.DecryptMsg(aliceSession.EncryptMsg([]byte("Alice Message 1")))
+	require.Nil(t, err)
+	assert.Equal(t, "Alice Message 1", string(bobReceiveAliceMsg1))
+
+	aliceGroupSession := NewGroupChatServerSideFanout(alice, protocol.NewSenderKeyName("test group", protocol.NewSignalAddress("Alice", 1)))
+	bobGroupSession := NewGroupChatServerSideFanout(bob, protocol.NewSenderKeyName("test group", protocol.NewSignalAddress("Bob", 1)))
+	aliceGroupSession.CreateSendingGroupSession()
+	aliceSenderKey, _ := protocol.NewSenderKeyDistributionMessageFromBytes(aliceGroupSession.GetSendingGroupSession().DistributeSenderKey().Serialize(), bob.Serializer.SenderKeyDistributionMessage)
+	bobGroupSession.CreateReceivingGroupSession("Alice", aliceSenderKey)
+
+	/* Restore Bob from his export */
+	stored, err := bob.Export()
+	require.Nil(t, err)
+	restoredBob, err := RestoreUser(stored, serializer)
+	require.Nil(t, err)
+
+	assert.Equal(t, bob.GetIdentityKey().PublicKey().Fingerprint(), restoredBob.GetIdentityKey().PublicKey().Fingerprint())
+	assert.Equal(t, bob.GetIdentityKey().PrivateKey().Serialize(), restoredBob.GetIdentityKey().PrivateKey().Serialize())
+	assert.Equal(t, bob.GetRegistrationID(), restoredBob.GetRegistrationID())
+	assert.Nil(t, restoredBob.GetPreKey(bobPreKeyID), "the consumed prekey should not be restored")
+	assert.Equal(t, bob.GetPreKey(bobUnusedPreKeyID).Serialize(), restoredBob.GetPreKey(bobUnusedPreKeyID).Serialize())
+	assert.Equal(t, bob.GetSignedPreKey(bobSignedPreKeyID).Serialize(), restoredBob.GetSignedPreKey(bobSignedPreKeyID).Serialize())
+	assert.True(t, bob.GetMLSKeyPackage(1).Equals(restoredBob.GetMLSKeyPackage(1)))
+	assert.Equal(t, bob.GetMLSIdentityKey(), restoredBob.GetMLSIdentityKey())
+	assert.True(t, bob.GetMLSCredential().Equals(restoredBob.GetMLSCredential()))
+
+	/* The restored session continues the ratchet in both directions */
+	restoredBobSession := restoredBob.CreateSessionWrapper(protocol.NewSignalAddress("Alice", 1), nil)
+	bobReceiveAliceMsg2, err := restoredBobSession.DecryptMsg(aliceSession.EncryptMsg([]byte("Alice Message 2")))
+	require.Nil(t, err)
+	assert.Equal(t, "Alice Message 2", string(bobReceiveAliceMsg2))
+	aliceReceiveBobMsg1, err := aliceSession.DecryptMsg(restoredBobSession.EncryptMsg([]byte("Bob Message 1")))
+	require.Nil(t, err)
+	assert.Equal(t, "Bob Message 1", string(aliceReceiveBobMsg1))
+
+	/* The restored sender key decrypts the group messages */
+	restoredBobGroupSession := NewGroupChatServerSideFanout(restoredBob, protocol.NewSenderKeyName("test group", protocol.NewSignalAddress("Bob", 1)))
+	restoredBobGroupSession.RestoreReceivingGroupSession("Alice")
+	ciphertextFromAlice, _ := protocol.NewSenderKeyMessageFromBytes(aliceGroupSession.GetSendingGroupSession().EncryptGroupMessage([]byte("Alice Group Message")).SignedSerialize(), restoredBob.Serializer.SenderKeyMessage)
+	assert.Equal(t, "Alice Group Message", string(restoredBobGroupSession.GetReceivingGroupSession("Alice").DecryptGroupMessage(ciphertextFromAlice)))
+
+	/* The restored MLS keys join groups with the key packages issued before */
+	alice.GenerateMLSKeyPackage(1)
+	aliceState, err := alice.GenerateMLSStateFromEmpty(RandomBytes(32), alice.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+	add, err := aliceState.Add(bob.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+	_, err = aliceState.Handle(add)
+	require.Nil(t, err)
+	_, welcome, aliceState, err := aliceState.Commit(RandomBytes(32))
+	require.Nil(t, err)
+	bobState, err := restoredBob.GenerateMLSStateFromWelcome(welcome, restoredBob.GetMLSKeyPackage(1))
+	require.Nil(t, err)
+	require.True(t, aliceState.Equals(*bobState))
+}
+
 type Keypair struct {
 	Private *ecdh.PrivateKey
 	Public  *ecdh.PublicKey